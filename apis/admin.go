@@ -18,15 +18,19 @@ func bindAdminApi(app core.App, rg *echo.Group) {
 	api := adminApi{app: app}
 
 	subGroup := rg.Group("/admins", ActivityLogger(app))
-	subGroup.POST("/auth-with-password", api.authWithPassword)
-	subGroup.POST("/request-password-reset", api.requestPasswordReset)
-	subGroup.POST("/confirm-password-reset", api.confirmPasswordReset)
+	subGroup.POST("/auth-with-password", api.authWithPassword, CollectionRateLimit(app, "auth"))
+	subGroup.POST("/auth-with-mfa", api.authWithMfa, CollectionRateLimit(app, "auth"))
+	subGroup.POST("/mfa/setup", api.mfaSetup, RequireAdminAuth())
+	subGroup.POST("/mfa/confirm", api.mfaConfirm, RequireAdminAuth())
+	subGroup.POST("/mfa/disable", api.mfaDisable, RequireAdminAuth())
+	subGroup.POST("/request-password-reset", api.requestPasswordReset, CollectionRateLimit(app, "requestPasswordReset"))
+	subGroup.POST("/confirm-password-reset", api.confirmPasswordReset, CollectionRateLimit(app, "confirmPasswordReset"))
 	subGroup.POST("/auth-refresh", api.authRefresh, RequireAdminAuth())
-	subGroup.GET("", api.list, RequireAdminAuth())
-	subGroup.POST("", api.create, RequireAdminAuthOnlyIfAny(app))
-	subGroup.GET("/:id", api.view, RequireAdminAuth())
-	subGroup.PATCH("/:id", api.update, RequireAdminAuth())
-	subGroup.DELETE("/:id", api.delete, RequireAdminAuth())
+	subGroup.GET("", api.list, RequireAdminAuth(models.AdminRoleSuperuser))
+	subGroup.POST("", api.create, RequireAdminAuthOnlyIfAny(app, models.AdminRoleSuperuser))
+	subGroup.GET("/:id", api.view, RequireAdminAuth(models.AdminRoleSuperuser))
+	subGroup.PATCH("/:id", api.update, RequireAdminAuth(models.AdminRoleSuperuser))
+	subGroup.DELETE("/:id", api.delete, RequireAdminAuth(models.AdminRoleSuperuser))
 }
 
 type adminApi struct {
@@ -52,6 +56,25 @@ func (api *adminApi) authResponse(c echo.Context, admin *models.Admin) error {
 	})
 }
 
+// authOrMfaResponse writes a short-lived mfa token response if the admin
+// has enabled MFA, otherwise fallbacks to the regular auth response.
+func (api *adminApi) authOrMfaResponse(c echo.Context, admin *models.Admin) error {
+	mfa, _ := api.app.Dao().FindMfaByAdmin(admin)
+	if mfa == nil || !mfa.Verified {
+		return api.authResponse(c, admin)
+	}
+
+	token, tokenErr := tokens.NewAdminMfaToken(api.app, admin)
+	if tokenErr != nil {
+		return NewBadRequestError("Failed to create mfa token.", tokenErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"mfaRequired": true,
+		"mfaToken":    token,
+	})
+}
+
 func (api *adminApi) authRefresh(c echo.Context) error {
 	admin, _ := c.Get(ContextAdminKey).(*models.Admin)
 	if admin == nil {
@@ -95,7 +118,7 @@ func (api *adminApi) authWithPassword(c echo.Context) error {
 					return NewBadRequestError("Failed to authenticate.", err)
 				}
 
-				return api.authResponse(e.HttpContext, e.Admin)
+				return api.authOrMfaResponse(e.HttpContext, e.Admin)
 			})
 		}
 	})
@@ -109,6 +132,101 @@ func (api *adminApi) authWithPassword(c echo.Context) error {
 	return submitErr
 }
 
+func (api *adminApi) authWithMfa(c echo.Context) error {
+	form := forms.NewAdminMfaLogin(api.app)
+	if err := c.Bind(form); err != nil {
+		return NewBadRequestError("An error occurred while loading the submitted data.", err)
+	}
+
+	event := new(core.AdminAuthWithMfaEvent)
+	event.HttpContext = c
+	event.Code = form.Code
+
+	_, submitErr := form.Submit(func(next forms.InterceptorNextFunc[*forms.AdminMfaLoginData]) forms.InterceptorNextFunc[*forms.AdminMfaLoginData] {
+		return func(data *forms.AdminMfaLoginData) error {
+			event.Admin = data.Admin
+			event.Mfa = data.Mfa
+
+			return api.app.OnAdminBeforeAuthWithMfaRequest().Trigger(event, func(e *core.AdminAuthWithMfaEvent) error {
+				data.Admin = e.Admin
+				data.Mfa = e.Mfa
+
+				if err := next(data); err != nil {
+					return NewBadRequestError("Failed to authenticate.", err)
+				}
+
+				return api.authResponse(e.HttpContext, e.Admin)
+			})
+		}
+	})
+
+	if submitErr == nil {
+		if err := api.app.OnAdminAfterAuthWithMfaRequest().Trigger(event); err != nil && api.app.IsDebug() {
+			log.Println(err)
+		}
+	}
+
+	return submitErr
+}
+
+func (api *adminApi) mfaSetup(c echo.Context) error {
+	admin, _ := c.Get(ContextAdminKey).(*models.Admin)
+	if admin == nil {
+		return NewNotFoundError("Missing auth admin context.", nil)
+	}
+
+	form := forms.NewAdminMfaSetup(api.app, admin)
+
+	result, err := form.Submit()
+	if err != nil {
+		return NewBadRequestError("Failed to setup MFA.", err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"secret": result.Secret,
+		"url":    result.AuthUrl,
+	})
+}
+
+func (api *adminApi) mfaConfirm(c echo.Context) error {
+	admin, _ := c.Get(ContextAdminKey).(*models.Admin)
+	if admin == nil {
+		return NewNotFoundError("Missing auth admin context.", nil)
+	}
+
+	form := forms.NewAdminMfaConfirm(api.app, admin)
+	if err := c.Bind(form); err != nil {
+		return NewBadRequestError("An error occurred while loading the submitted data.", err)
+	}
+
+	recoveryCodes, err := form.Submit()
+	if err != nil {
+		return NewBadRequestError("Failed to confirm MFA.", err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"recoveryCodes": recoveryCodes,
+	})
+}
+
+func (api *adminApi) mfaDisable(c echo.Context) error {
+	admin, _ := c.Get(ContextAdminKey).(*models.Admin)
+	if admin == nil {
+		return NewNotFoundError("Missing auth admin context.", nil)
+	}
+
+	form := forms.NewAdminMfaDisable(api.app, admin)
+	if err := c.Bind(form); err != nil {
+		return NewBadRequestError("An error occurred while loading the submitted data.", err)
+	}
+
+	if err := form.Submit(); err != nil {
+		return NewBadRequestError("Failed to disable MFA.", err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
 func (api *adminApi) requestPasswordReset(c echo.Context) error {
 	form := forms.NewAdminPasswordResetRequest(api.app)
 	if err := c.Bind(form); err != nil {
@@ -64,6 +64,15 @@ func NewUnauthorizedError(message string, data any) *ApiError {
 	return NewApiError(http.StatusUnauthorized, message, data)
 }
 
+// NewTooManyRequestsError creates and returns 429 `ApiError`.
+func NewTooManyRequestsError(message string, data any) *ApiError {
+	if message == "" {
+		message = "Too Many Requests."
+	}
+
+	return NewApiError(http.StatusTooManyRequests, message, data)
+}
+
 // NewApiError creates and returns new normalized `ApiError` instance.
 func NewApiError(status int, message string, data any) *ApiError {
 	message = inflector.Sentenize(message)
@@ -148,3 +148,24 @@ func TestNewUnauthorizedError(t *testing.T) {
 		}
 	}
 }
+
+func TestNewTooManyRequestsError(t *testing.T) {
+	scenarios := []struct {
+		message  string
+		data     any
+		expected string
+	}{
+		{"", nil, `{"code":429,"message":"Too Many Requests.","data":{}}`},
+		{"demo", "rawData_test", `{"code":429,"message":"Demo.","data":{}}`},
+		{"demo", validation.Errors{"err1": errors.New("test error")}, `{"code":429,"message":"Demo.","data":{"err1":{"code":"validation_invalid_value","message":"Test error."}}}`},
+	}
+
+	for i, scenario := range scenarios {
+		e := apis.NewTooManyRequestsError(scenario.message, scenario.data)
+		result, _ := json.Marshal(e)
+
+		if string(result) != scenario.expected {
+			t.Errorf("(%d) Expected %v, got %v", i, scenario.expected, string(result))
+		}
+	}
+}
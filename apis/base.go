@@ -43,6 +43,7 @@ func InitApi(app core.App) (*echo.Echo, error) {
 	e.Use(middleware.Recover())
 	e.Use(middleware.Secure())
 	e.Use(LoadAuthContext(app))
+	e.Use(RateLimit(app))
 
 	// custom error handler
 	e.HTTPErrorHandler = func(c echo.Context, err error) {
@@ -99,12 +100,15 @@ func InitApi(app core.App) (*echo.Echo, error) {
 	// default routes
 	api := e.Group("/api")
 	bindSettingsApi(app, api)
+	bindBackupApi(app, api)
 	bindAdminApi(app, api)
 	bindCollectionApi(app, api)
 	bindRecordCrudApi(app, api)
 	bindRecordAuthApi(app, api)
+	bindRecordUploadApi(app, api)
 	bindFileApi(app, api)
 	bindRealtimeApi(app, api)
+	bindWebhookApi(app, api)
 	bindLogsApi(app, api)
 	bindHealthApi(app, api)
 
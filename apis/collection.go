@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/forms"
 	"github.com/pocketbase/pocketbase/models"
@@ -15,13 +16,13 @@ import (
 func bindCollectionApi(app core.App, rg *echo.Group) {
 	api := collectionApi{app: app}
 
-	subGroup := rg.Group("/collections", ActivityLogger(app), RequireAdminAuth())
+	subGroup := rg.Group("/collections", ActivityLogger(app), RequireAdminAuth(models.AdminRoleReadonly, models.AdminRoleCollections))
 	subGroup.GET("", api.list)
-	subGroup.POST("", api.create)
+	subGroup.POST("", api.create, RequireAdminAuth(models.AdminRoleCollections), requireUnscopedAdmin())
 	subGroup.GET("/:collection", api.view)
-	subGroup.PATCH("/:collection", api.update)
-	subGroup.DELETE("/:collection", api.delete)
-	subGroup.PUT("/import", api.bulkImport)
+	subGroup.PATCH("/:collection", api.update, RequireAdminAuth(models.AdminRoleCollections))
+	subGroup.DELETE("/:collection", api.delete, RequireAdminAuth(models.AdminRoleCollections))
+	subGroup.PUT("/import", api.bulkImport, RequireAdminAuth(models.AdminRoleCollections), requireUnscopedAdmin())
 }
 
 type collectionApi struct {
@@ -33,10 +34,19 @@ func (api *collectionApi) list(c echo.Context) error {
 		"id", "created", "updated", "name", "system", "type",
 	)
 
+	query := api.app.Dao().CollectionQuery()
+
+	// limit the result to the admin scoped collections
+	if admin, _ := c.Get(ContextAdminKey).(*models.Admin); admin != nil && admin.IsScoped() {
+		scopes := make([]any, len(admin.Collections))
+		copy(scopes, admin.Collections)
+		query.AndWhere(dbx.Or(dbx.In("id", scopes...), dbx.In("name", scopes...)))
+	}
+
 	collections := []*models.Collection{}
 
 	result, err := search.NewProvider(fieldResolver).
-		Query(api.app.Dao().CollectionQuery()).
+		Query(query).
 		ParseAndExec(c.QueryParams().Encode(), &collections)
 
 	if err != nil {
@@ -59,6 +69,10 @@ func (api *collectionApi) view(c echo.Context) error {
 		return NewNotFoundError("", err)
 	}
 
+	if admin, _ := c.Get(ContextAdminKey).(*models.Admin); admin != nil && !admin.CanAccessCollection(collection) {
+		return NewForbiddenError("The authorized admin is not allowed to access the requested collection.", nil)
+	}
+
 	event := new(core.CollectionViewEvent)
 	event.HttpContext = c
 	event.Collection = collection
@@ -112,6 +126,10 @@ func (api *collectionApi) update(c echo.Context) error {
 		return NewNotFoundError("", err)
 	}
 
+	if admin, _ := c.Get(ContextAdminKey).(*models.Admin); admin != nil && !admin.CanAccessCollection(collection) {
+		return NewForbiddenError("The authorized admin is not allowed to access the requested collection.", nil)
+	}
+
 	form := forms.NewCollectionUpsert(api.app, collection)
 
 	// load request
@@ -153,6 +171,10 @@ func (api *collectionApi) delete(c echo.Context) error {
 		return NewNotFoundError("", err)
 	}
 
+	if admin, _ := c.Get(ContextAdminKey).(*models.Admin); admin != nil && !admin.CanAccessCollection(collection) {
+		return NewForbiddenError("The authorized admin is not allowed to access the requested collection.", nil)
+	}
+
 	event := new(core.CollectionDeleteEvent)
 	event.HttpContext = c
 	event.Collection = collection
@@ -209,3 +231,18 @@ func (api *collectionApi) bulkImport(c echo.Context) error {
 
 	return submitErr
 }
+
+// requireUnscopedAdmin middleware prevents collection scoped admins
+// from performing actions that could affect collections outside of their scopes.
+func requireUnscopedAdmin() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			admin, _ := c.Get(ContextAdminKey).(*models.Admin)
+			if admin != nil && admin.IsScoped() {
+				return NewForbiddenError("The authorized admin is restricted to specific collections and is not allowed to perform this action.", nil)
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -2,12 +2,21 @@ package apis
 
 import (
 	"fmt"
+	"log"
+	"net/http"
 
 	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/models"
 	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/resolvers"
+	"github.com/pocketbase/pocketbase/tokens"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
 	"github.com/pocketbase/pocketbase/tools/list"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/spf13/cast"
 )
 
 var imageContentTypes = []string{"image/png", "image/jpg", "image/jpeg", "image/gif"}
@@ -18,14 +27,48 @@ func bindFileApi(app core.App, rg *echo.Group) {
 	api := fileApi{app: app}
 
 	subGroup := rg.Group("/files", ActivityLogger(app))
+	subGroup.POST("/token", api.fileToken, RequireAdminOrRecordAuth())
 	subGroup.HEAD("/:collection/:recordId/:filename", api.download, LoadCollectionContext(api.app))
 	subGroup.GET("/:collection/:recordId/:filename", api.download, LoadCollectionContext(api.app))
+
+	bindFileThumbsWorker(app)
 }
 
 type fileApi struct {
 	app core.App
 }
 
+func (api *fileApi) fileToken(c echo.Context) error {
+	event := new(core.FileTokenEvent)
+	event.HttpContext = c
+
+	if admin, _ := c.Get(ContextAdminKey).(*models.Admin); admin != nil {
+		event.Model = admin
+		event.Token, _ = tokens.NewAdminFileToken(api.app, admin)
+	} else if record, _ := c.Get(ContextAuthRecordKey).(*models.Record); record != nil {
+		event.Model = record
+		event.Token, _ = tokens.NewRecordFileToken(api.app, record)
+	}
+
+	handlerErr := api.app.OnFileBeforeTokenRequest().Trigger(event, func(e *core.FileTokenEvent) error {
+		if e.Model == nil || e.Token == "" {
+			return NewBadRequestError("Failed to generate file token.", nil)
+		}
+
+		return e.HttpContext.JSON(http.StatusOK, map[string]string{
+			"token": e.Token,
+		})
+	})
+
+	if handlerErr == nil {
+		if err := api.app.OnFileAfterTokenRequest().Trigger(event); err != nil && api.app.IsDebug() {
+			log.Println(err)
+		}
+	}
+
+	return handlerErr
+}
+
 func (api *fileApi) download(c echo.Context) error {
 	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
 	if collection == nil {
@@ -51,49 +94,77 @@ func (api *fileApi) download(c echo.Context) error {
 
 	options, _ := fileField.Options.(*schema.FileOptions)
 
-	baseFilesPath := record.BaseFilesPath()
+	if options.Protected {
+		if err := api.checkProtectedAccess(c, collection, record); err != nil {
+			return err
+		}
+	}
+
+	fileRecord := record
 
 	// fetch the original view file field related record
 	if collection.IsView() {
-		fileRecord, err := api.app.Dao().FindRecordByViewFile(collection.Id, fileField.Name, filename)
+		fileRecord, err = api.app.Dao().FindRecordByViewFile(collection.Id, fileField.Name, filename)
 		if err != nil {
 			return NewNotFoundError("", fmt.Errorf("Failed to fetch view file field record: %w", err))
 		}
-		baseFilesPath = fileRecord.BaseFilesPath()
 	}
 
+	baseFilesPath := fileRecord.BaseFilesPath()
+
 	fs, err := api.app.NewFilesystem()
 	if err != nil {
 		return NewBadRequestError("Filesystem initialization failure.", err)
 	}
 	defer fs.Close()
 
-	originalPath := baseFilesPath + "/" + filename
+	originalPath := core.RecordFileKey(api.app.Dao(), fileRecord, filename)
 	servedPath := originalPath
 	servedName := filename
 
 	// check for valid thumb size param
 	thumbSize := c.QueryParam("thumb")
-	if thumbSize != "" && (list.ExistInSlice(thumbSize, defaultThumbSizes) || list.ExistInSlice(thumbSize, options.Thumbs)) {
+	if thumbSize != "" && !list.ExistInSlice(thumbSize, defaultThumbSizes) && !list.ExistInSlice(thumbSize, options.Thumbs) {
+		thumbSize = ""
+	}
+
+	// check for preferred thumb output format
+	thumbFormat := negotiateThumbFormat(c, options)
+	if len(options.ThumbFormats) > 0 {
+		c.Response().Header().Add("Vary", "Accept")
+	}
+
+	if thumbSize != "" || thumbFormat != nil {
 		// extract the original file meta attributes and check it existence
 		oAttrs, oAttrsErr := fs.Attributes(originalPath)
 		if oAttrsErr != nil {
 			return NewNotFoundError("", err)
 		}
 
+		// the original is already in the requested format
+		if thumbFormat != nil && thumbFormat.ContentType == oAttrs.ContentType {
+			thumbFormat = nil
+		}
+
 		// check if it is an image
-		if list.ExistInSlice(oAttrs.ContentType, imageContentTypes) {
-			// add thumb size as file suffix
-			servedName = thumbSize + "_" + filename
-			servedPath = baseFilesPath + "/thumbs_" + filename + "/" + servedName
+		if (thumbSize != "" || thumbFormat != nil) && list.ExistInSlice(oAttrs.ContentType, imageContentTypes) {
+			// add thumb size as file prefix and the format extension as suffix
+			servedPath, servedName = thumbPath(baseFilesPath, filename, thumbSize, thumbFormat)
 
 			// check if the thumb exists:
-			// - if doesn't exist - create a new thumb with the specified thumb size
+			// - if doesn't exist - create a new thumb with the specified thumb size and format
 			// - if exists - compare last modified dates to determine whether the thumb should be recreated
 			tAttrs, tAttrsErr := fs.Attributes(servedPath)
 			if tAttrsErr != nil || oAttrs.ModTime.After(tAttrs.ModTime) {
-				if err := fs.CreateThumb(originalPath, servedPath, thumbSize); err != nil {
-					servedPath = originalPath // fallback to the original
+				thumbOptions := filesystem.ThumbOptions{Size: thumbSize, Quality: options.ThumbQuality}
+				if thumbFormat != nil {
+					thumbOptions.Format = thumbFormat.Name
+				}
+
+				if err := fs.CreateThumbWithOptions(originalPath, servedPath, thumbOptions); err != nil {
+					// fallback to the original
+					servedPath = originalPath
+					servedName = filename
 				}
 			}
 		}
@@ -122,3 +193,83 @@ func (api *fileApi) download(c echo.Context) error {
 		return nil
 	})
 }
+
+// checkProtectedAccess checks whether the requester associated with
+// the "token" query parameter is allowed to access the protected record files.
+func (api *fileApi) checkProtectedAccess(c echo.Context, collection *models.Collection, record *models.Record) error {
+	forbiddenErr := NewForbiddenError("Insufficient permissions to access the file resource.", nil)
+
+	admin, authRecord := findAuthByFileToken(api.app, c.QueryParam("token"))
+	if admin == nil && authRecord == nil {
+		return forbiddenErr
+	}
+
+	if admin != nil {
+		if !admin.CanViewRecords(collection) {
+			return forbiddenErr
+		}
+		return nil
+	}
+
+	if collection.ViewRule == nil {
+		return forbiddenErr // only admins can access
+	}
+
+	if *collection.ViewRule == "" {
+		return nil // public
+	}
+
+	// shallow copy the request data to avoid modifying the cached one
+	requestData := *RequestData(c)
+	requestData.Admin = nil
+	requestData.AuthRecord = authRecord
+
+	ruleFunc := func(q *dbx.SelectQuery) error {
+		resolver := resolvers.NewRecordFieldResolver(api.app.Dao(), collection, &requestData, true)
+		expr, err := search.FilterData(*collection.ViewRule).BuildExpr(resolver)
+		if err != nil {
+			return err
+		}
+		resolver.UpdateQuery(q)
+		q.AndWhere(expr)
+		return nil
+	}
+
+	if _, err := api.app.Dao().FindRecordById(collection.Id, record.Id, ruleFunc); err != nil {
+		return forbiddenErr
+	}
+
+	return nil
+}
+
+// findAuthByFileToken returns the admin or auth record (if any)
+// associated with the provided valid file token.
+func findAuthByFileToken(app core.App, token string) (*models.Admin, *models.Record) {
+	if token == "" {
+		return nil, nil
+	}
+
+	claims, _ := security.ParseUnverifiedJWT(token)
+	tokenType := cast.ToString(claims["type"])
+
+	switch tokenType {
+	case tokens.TypeAdmin:
+		admin, err := app.Dao().FindAdminByToken(
+			token,
+			app.Settings().AdminFileToken.Secret,
+		)
+		if err == nil && admin != nil {
+			return admin, nil
+		}
+	case tokens.TypeAuthRecord:
+		record, err := app.Dao().FindAuthRecordByToken(
+			token,
+			app.Settings().RecordFileToken.Secret,
+		)
+		if err == nil && record != nil {
+			return nil, record
+		}
+	}
+
+	return nil, nil
+}
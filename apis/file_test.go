@@ -1,14 +1,27 @@
 package apis_test
 
 import (
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
 	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tokens"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
 )
 
 func TestFileDownload(t *testing.T) {
@@ -190,3 +203,398 @@ func TestFileDownload(t *testing.T) {
 		scenario.Test(t)
 	}
 }
+
+func TestFileToken(t *testing.T) {
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "unauthorized",
+			Method:          http.MethodPost,
+			Url:             "/api/files/token",
+			ExpectedStatus:  401,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "auth record",
+			Method: http.MethodPost,
+			Url:    "/api/files/token",
+			RequestHeaders: map[string]string{
+				"Authorization": testRecordToken,
+			},
+			ExpectedStatus:  200,
+			ExpectedContent: []string{`"token":"`},
+			ExpectedEvents: map[string]int{
+				"OnFileBeforeTokenRequest": 1,
+				"OnFileAfterTokenRequest":  1,
+			},
+		},
+		{
+			Name:   "admin",
+			Method: http.MethodPost,
+			Url:    "/api/files/token",
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			ExpectedStatus:  200,
+			ExpectedContent: []string{`"token":"`},
+			ExpectedEvents: map[string]int{
+				"OnFileBeforeTokenRequest": 1,
+				"OnFileAfterTokenRequest":  1,
+			},
+		},
+		{
+			Name:   "hook token change",
+			Method: http.MethodPost,
+			Url:    "/api/files/token",
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				app.OnFileBeforeTokenRequest().Add(func(e *core.FileTokenEvent) error {
+					e.Token = "test"
+					return nil
+				})
+			},
+			ExpectedStatus:  200,
+			ExpectedContent: []string{`"token":"test"`},
+			ExpectedEvents: map[string]int{
+				"OnFileBeforeTokenRequest": 1,
+				"OnFileAfterTokenRequest":  1,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestFileDownloadProtected(t *testing.T) {
+	_, currentFile, _, _ := runtime.Caller(0)
+	dataDirRelPath := "../tests/data/"
+
+	testImgPath := filepath.Join(path.Dir(currentFile), dataDirRelPath, "storage/_pb_users_auth_/4q1xlclmfloku33/300_1SEi6Q6U72.png")
+	testThumbCropCenterPath := filepath.Join(path.Dir(currentFile), dataDirRelPath, "storage/_pb_users_auth_/4q1xlclmfloku33/thumbs_300_1SEi6Q6U72.png/70x50_300_1SEi6Q6U72.png")
+
+	testImg, imgErr := os.ReadFile(testImgPath)
+	if imgErr != nil {
+		t.Fatal(imgErr)
+	}
+
+	testThumbCropCenter, thumbErr := os.ReadFile(testThumbCropCenterPath)
+	if thumbErr != nil {
+		t.Fatal(thumbErr)
+	}
+
+	// initializes a test app with protected users avatar field
+	// and fixed file token secrets
+	newTestApp := func() (*tests.TestApp, error) {
+		app, err := tests.NewTestApp()
+		if err != nil {
+			return nil, err
+		}
+
+		app.Settings().AdminFileToken.Secret = "test_admin_file_secret"
+		app.Settings().RecordFileToken.Secret = "test_record_file_secret"
+
+		collection, err := app.Dao().FindCollectionByNameOrId("users")
+		if err != nil {
+			return nil, err
+		}
+		collection.Schema.GetFieldByName("avatar").Options.(*schema.FileOptions).Protected = true
+		if err := app.Dao().SaveCollection(collection); err != nil {
+			return nil, err
+		}
+
+		app.ResetEventCalls()
+
+		return app, nil
+	}
+
+	// generate the test file tokens
+	app, err := newTestApp()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer app.Cleanup()
+
+	admin, err := app.Dao().FindAdminByEmail("test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	adminFileToken, err := tokens.NewAdminFileToken(app, admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	owner, err := app.Dao().FindRecordById("users", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ownerFileToken, err := tokens.NewRecordFileToken(app, owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := app.Dao().FindRecordById("users", "oap640cot4yru2s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherFileToken, err := tokens.NewRecordFileToken(app, other)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "missing token",
+			Method:          http.MethodGet,
+			Url:             "/api/files/_pb_users_auth_/4q1xlclmfloku33/300_1SEi6Q6U72.png",
+			TestAppFactory:  newTestApp,
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:            "regular auth token",
+			Method:          http.MethodGet,
+			Url:             "/api/files/_pb_users_auth_/4q1xlclmfloku33/300_1SEi6Q6U72.png?token=" + testRecordToken,
+			TestAppFactory:  newTestApp,
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:            "file token of a record that doesn't satisfy the view rule",
+			Method:          http.MethodGet,
+			Url:             "/api/files/_pb_users_auth_/4q1xlclmfloku33/300_1SEi6Q6U72.png?token=" + otherFileToken,
+			TestAppFactory:  newTestApp,
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:            "file token of a record that doesn't satisfy the view rule (thumb)",
+			Method:          http.MethodGet,
+			Url:             "/api/files/_pb_users_auth_/4q1xlclmfloku33/300_1SEi6Q6U72.png?thumb=70x50&token=" + otherFileToken,
+			TestAppFactory:  newTestApp,
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:            "file token of a record that satisfies the view rule",
+			Method:          http.MethodGet,
+			Url:             "/api/files/_pb_users_auth_/4q1xlclmfloku33/300_1SEi6Q6U72.png?token=" + ownerFileToken,
+			TestAppFactory:  newTestApp,
+			ExpectedStatus:  200,
+			ExpectedContent: []string{string(testImg)},
+			ExpectedEvents: map[string]int{
+				"OnFileDownloadRequest": 1,
+			},
+		},
+		{
+			Name:            "file token of a record that satisfies the view rule (thumb)",
+			Method:          http.MethodGet,
+			Url:             "/api/files/_pb_users_auth_/4q1xlclmfloku33/300_1SEi6Q6U72.png?thumb=70x50&token=" + ownerFileToken,
+			TestAppFactory:  newTestApp,
+			ExpectedStatus:  200,
+			ExpectedContent: []string{string(testThumbCropCenter)},
+			ExpectedEvents: map[string]int{
+				"OnFileDownloadRequest": 1,
+			},
+		},
+		{
+			Name:            "admin file token",
+			Method:          http.MethodGet,
+			Url:             "/api/files/_pb_users_auth_/4q1xlclmfloku33/300_1SEi6Q6U72.png?token=" + adminFileToken,
+			TestAppFactory:  newTestApp,
+			ExpectedStatus:  200,
+			ExpectedContent: []string{string(testImg)},
+			ExpectedEvents: map[string]int{
+				"OnFileDownloadRequest": 1,
+			},
+		},
+		{
+			Name:           "admin file token without collection records access",
+			Method:         http.MethodGet,
+			Url:            "/api/files/_pb_users_auth_/4q1xlclmfloku33/300_1SEi6Q6U72.png?token=" + adminFileToken,
+			TestAppFactory: newTestApp,
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				setTestAdminRole(t, app, "sywbhecnh46rhm0", models.AdminRoleLogs)
+				app.ResetEventCalls()
+			},
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:            "non-protected field of the same record",
+			Method:          http.MethodGet,
+			Url:             "/api/files/_pb_users_auth_/oap640cot4yru2s/test_kfd2wYLxkz.txt",
+			TestAppFactory:  newTestApp,
+			ExpectedStatus:  200,
+			ExpectedContent: []string{"test"},
+			ExpectedEvents: map[string]int{
+				"OnFileDownloadRequest": 1,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestFileDownloadThumbFormats(t *testing.T) {
+	app, err := tests.NewTestApp()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	options := collection.Schema.GetFieldByName("avatar").Options.(*schema.FileOptions)
+	options.ThumbFormats = []string{"gif", "jpeg"}
+	options.ThumbQuality = 50
+	if err := app.Dao().SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := apis.InitApi(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name                string
+		url                 string
+		accept              string
+		expectedContentType string
+	}{
+		{
+			"no format preference",
+			"/api/files/users/4q1xlclmfloku33/300_1SEi6Q6U72.png",
+			"",
+			"image/png",
+		},
+		{
+			"wildcard accept",
+			"/api/files/users/4q1xlclmfloku33/300_1SEi6Q6U72.png",
+			"image/*,*/*;q=0.8",
+			"image/png",
+		},
+		{
+			"accept with unsupported and zero quality formats",
+			"/api/files/users/4q1xlclmfloku33/300_1SEi6Q6U72.png",
+			"image/avif,image/gif;q=0,image/*",
+			"image/png",
+		},
+		{
+			"accept with the field formats (field order precedence)",
+			"/api/files/users/4q1xlclmfloku33/300_1SEi6Q6U72.png",
+			"image/jpeg,image/gif;q=0.5",
+			"image/gif",
+		},
+		{
+			"accept with a single field format",
+			"/api/files/users/4q1xlclmfloku33/300_1SEi6Q6U72.png?thumb=70x50",
+			"image/jpeg",
+			"image/jpeg",
+		},
+		{
+			"explicit field format",
+			"/api/files/users/4q1xlclmfloku33/300_1SEi6Q6U72.png",
+			"image/gif",
+			"image/gif",
+		},
+		{
+			"explicit field format (overwrites the accept header)",
+			"/api/files/users/4q1xlclmfloku33/300_1SEi6Q6U72.png?format=jpeg",
+			"image/gif",
+			"image/jpeg",
+		},
+		{
+			"explicit non field format",
+			"/api/files/users/4q1xlclmfloku33/300_1SEi6Q6U72.png?format=png&thumb=70x50",
+			"image/jpeg",
+			"image/png",
+		},
+		{
+			"non image file",
+			"/api/files/users/oap640cot4yru2s/test_kfd2wYLxkz.txt",
+			"image/jpeg",
+			"text/plain; charset=utf-8",
+		},
+	}
+
+	for _, s := range scenarios {
+		req := httptest.NewRequest(http.MethodGet, s.url, nil)
+		if s.accept != "" {
+			req.Header.Set("Accept", s.accept)
+		}
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("[%s] Expected status 200, got %d", s.name, rec.Code)
+			continue
+		}
+
+		if v := rec.Header().Get("Content-Type"); v != s.expectedContentType {
+			t.Errorf("[%s] Expected Content-Type %q, got %q", s.name, s.expectedContentType, v)
+		}
+
+		if s.expectedContentType != "text/plain; charset=utf-8" && rec.Header().Get("Vary") != "Accept" {
+			t.Errorf("[%s] Expected Vary Accept header, got %q", s.name, rec.Header().Get("Vary"))
+		}
+
+		if _, format, err := image.DecodeConfig(rec.Body); err == nil && "image/"+format != s.expectedContentType {
+			t.Errorf("[%s] Expected %q image, got %q", s.name, s.expectedContentType, format)
+		}
+	}
+}
+
+func TestFileDownloadDedup(t *testing.T) {
+	app, err := tests.NewTestApp()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer app.Cleanup()
+
+	app.Settings().Storage.Dedup = true
+
+	record, err := app.Dao().FindRecordById("demo1", "al1h9ijdeojtsjy")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := forms.NewRecordUpsert(app, record)
+	file, err := filesystem.NewFileFromBytes([]byte("dedup content"), "dedup.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	form.AddFiles("file_one", file)
+	if err := form.Submit(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.Dao().FindFileRef(record.Collection().Id, record.Id, file.Name); err != nil {
+		t.Fatalf("Expected the file to be stored as deduplicated blob, got %v", err)
+	}
+
+	e, err := apis.InitApi(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/files/demo1/al1h9ijdeojtsjy/"+file.Name, nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK || rec.Body.String() != "dedup content" {
+		t.Fatalf("Expected the deduplicated file content, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	if v := rec.Header().Get("Content-Disposition"); !strings.Contains(v, file.Name) {
+		t.Fatalf("Expected the record filename in the Content-Disposition header, got %q", v)
+	}
+}
@@ -0,0 +1,238 @@
+package apis
+
+import (
+	"log"
+	"mime"
+	"runtime"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+	"github.com/pocketbase/pocketbase/tools/list"
+	"github.com/pocketbase/pocketbase/tools/routine"
+)
+
+// thumbFormatQueryParam is the file download query parameter
+// used to request explicitly a specific thumb output format.
+const thumbFormatQueryParam = "format"
+
+// thumbPath returns the storage path of a record file thumb.
+//
+// thumbSize and format are optional - a thumb without size preserves
+// the original image dimensions and a thumb without format
+// preserves the original image format.
+func thumbPath(baseFilesPath string, filename string, thumbSize string, format *filesystem.ImageFormat) (path string, name string) {
+	name = filename
+
+	if thumbSize != "" {
+		name = thumbSize + "_" + name
+	}
+
+	if format != nil {
+		name += format.Extension
+	}
+
+	return baseFilesPath + "/thumbs_" + filename + "/" + name, name
+}
+
+// negotiateThumbFormat returns the thumb output format of the file field
+// that should be served based on the "format" query parameter or
+// the request Accept header.
+//
+// Returns nil if the original file format should be served.
+func negotiateThumbFormat(c echo.Context, options *schema.FileOptions) *filesystem.ImageFormat {
+	if len(options.ThumbFormats) == 0 {
+		return nil
+	}
+
+	// explicit format
+	if name := c.QueryParam(thumbFormatQueryParam); name != "" {
+		if !list.ExistInSlice(name, options.ThumbFormats) {
+			return nil
+		}
+
+		if format, ok := filesystem.FindImageFormat(name); ok {
+			return &format
+		}
+
+		return nil
+	}
+
+	accepted := acceptedContentTypes(c.Request().Header.Get("Accept"))
+
+	// the field formats order takes precedence since
+	// the client usually lists its supported types in arbitrary order
+	for _, name := range options.ThumbFormats {
+		format, ok := filesystem.FindImageFormat(name)
+		if ok && list.ExistInSlice(format.ContentType, accepted) {
+			return &format
+		}
+	}
+
+	return nil
+}
+
+// acceptedContentTypes extracts the explicitly listed content types
+// from the provided Accept header value.
+//
+// Wildcards and types with zero quality are ignored.
+func acceptedContentTypes(accept string) []string {
+	result := []string{}
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || strings.Contains(mediaType, "*") {
+			continue
+		}
+
+		if q, ok := params["q"]; ok && strings.Trim(q, "0.") == "" {
+			continue // q=0, q=0.0, etc.
+		}
+
+		result = append(result, mediaType)
+	}
+
+	return result
+}
+
+// bindFileThumbsWorker registers the record hooks that eagerly generate
+// in the background the configured thumbs and image placeholders
+// of the newly uploaded record files.
+func bindFileThumbsWorker(app core.App) {
+	// limits the number of the concurrently processed files
+	sem := make(chan struct{}, runtime.NumCPU())
+
+	process := func(record *models.Record, uploadedFiles map[string][]*filesystem.File) {
+		if len(uploadedFiles) == 0 {
+			return
+		}
+
+		// copy the file names since the event data shouldn't
+		// be accessed after the request completion
+		files := make(map[string][]string, len(uploadedFiles))
+		for field, list := range uploadedFiles {
+			for _, f := range list {
+				files[field] = append(files[field], f.Name)
+			}
+		}
+
+		routine.FireAndForget(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			for field, names := range files {
+				fileField := record.Collection().Schema.GetFieldByName(field)
+				if fileField == nil {
+					continue
+				}
+
+				options, _ := fileField.Options.(*schema.FileOptions)
+				if options == nil {
+					continue
+				}
+
+				for _, name := range names {
+					err := generateFileThumbs(app, record, fileField.Name, name, options)
+					if err != nil && app.IsDebug() {
+						log.Println("Failed to generate the file thumbs:", name, err)
+					}
+				}
+			}
+		})
+	}
+
+	app.OnRecordAfterCreateRequest().Add(func(e *core.RecordCreateEvent) error {
+		process(e.Record, e.UploadedFiles)
+		return nil
+	})
+
+	app.OnRecordAfterUpdateRequest().Add(func(e *core.RecordUpdateEvent) error {
+		process(e.Record, e.UploadedFiles)
+		return nil
+	})
+}
+
+// generateFileThumbs creates the configured thumbs, thumb format variants
+// and image placeholder (if enabled) of a single image record file.
+//
+// Non image files are ignored.
+func generateFileThumbs(
+	app core.App,
+	record *models.Record,
+	field string,
+	filename string,
+	options *schema.FileOptions,
+) error {
+	if len(options.Thumbs) == 0 && len(options.ThumbFormats) == 0 && !options.Placeholders {
+		return nil // nothing to generate
+	}
+
+	fs, err := app.NewFilesystem()
+	if err != nil {
+		return err
+	}
+	defer fs.Close()
+
+	baseFilesPath := record.BaseFilesPath()
+	originalPath := core.RecordFileKey(app.Dao(), record, filename)
+
+	attrs, err := fs.Attributes(originalPath)
+	if err != nil {
+		return err
+	}
+
+	if !list.ExistInSlice(attrs.ContentType, imageContentTypes) {
+		return nil
+	}
+
+	formats := []*filesystem.ImageFormat{nil} // nil == original format
+	for _, name := range options.ThumbFormats {
+		if format, ok := filesystem.FindImageFormat(name); ok && format.ContentType != attrs.ContentType {
+			formats = append(formats, &format)
+		}
+	}
+
+	sizes := append([]string{""}, options.Thumbs...) // "" == original dimensions
+
+	for _, size := range sizes {
+		for _, format := range formats {
+			if size == "" && format == nil {
+				continue // the original file
+			}
+
+			thumbOptions := filesystem.ThumbOptions{Size: size, Quality: options.ThumbQuality}
+			if format != nil {
+				thumbOptions.Format = format.Name
+			}
+
+			path, _ := thumbPath(baseFilesPath, filename, size, format)
+
+			if err := fs.CreateThumbWithOptions(originalPath, path, thumbOptions); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !options.Placeholders {
+		return nil
+	}
+
+	placeholder, err := fs.CreateImagePlaceholder(originalPath)
+	if err != nil {
+		return err
+	}
+
+	return app.Dao().SaveImagePlaceholder(&models.ImagePlaceholder{
+		CollectionId: record.Collection().Id,
+		RecordId:     record.Id,
+		Field:        field,
+		Filename:     filename,
+		BlurHash:     placeholder.BlurHash,
+		Color:        placeholder.Color,
+		Width:        placeholder.Width,
+		Height:       placeholder.Height,
+	})
+}
@@ -0,0 +1,140 @@
+package apis_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestFileThumbsWorker(t *testing.T) {
+	app, err := tests.NewTestApp()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	options := collection.Schema.GetFieldByName("avatar").Options.(*schema.FileOptions)
+	options.Thumbs = []string{"10x10", "20x0"}
+	options.ThumbFormats = []string{"jpeg"}
+	options.Placeholders = true
+	if err := app.Dao().SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := apis.InitApi(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// generate a test red image
+	img := image.NewRGBA(image.Rect(0, 0, 40, 30))
+	for x := 0; x < 40; x++ {
+		for y := 0; y < 30; y++ {
+			img.Set(x, y, color.RGBA{255, 0, 0, 255})
+		}
+	}
+
+	body := new(bytes.Buffer)
+	mp := multipart.NewWriter(body)
+	w, err := mp.CreateFormFile("avatar", "test.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(w, img); err != nil {
+		t.Fatal(err)
+	}
+	mp.Close()
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/collections/users/records/4q1xlclmfloku33", body)
+	req.Header.Set("Content-Type", mp.FormDataContentType())
+	req.Header.Set("Authorization", testRecordToken)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", rec.Code, rec.Body.String())
+	}
+
+	record, err := app.Dao().FindRecordById("users", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+	filename := record.GetString("avatar")
+	if !strings.HasPrefix(filename, "test_") {
+		t.Fatalf("Expected the uploaded avatar, got %q", filename)
+	}
+
+	baseDir := record.BaseFilesPath() + "/thumbs_" + filename + "/"
+	expectedThumbs := []string{
+		baseDir + "10x10_" + filename,
+		baseDir + "10x10_" + filename + ".jpg",
+		baseDir + "20x0_" + filename,
+		baseDir + "20x0_" + filename + ".jpg",
+		baseDir + filename + ".jpg",
+	}
+
+	fs, err := app.NewFilesystem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	// wait for the background worker
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		placeholders, _ := app.Dao().FindImagePlaceholdersByRecords(collection.Id, record.Id)
+
+		missing := len(placeholders) == 0
+		for _, path := range expectedThumbs {
+			if exists, _ := fs.Exists(path); !exists {
+				missing = true
+				break
+			}
+		}
+
+		if !missing {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("The thumbs or placeholder were not generated in time (placeholders: %v)", placeholders)
+		}
+
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	// the placeholder should be exposed with the record
+	req = httptest.NewRequest(http.MethodGet, "/api/collections/users/records/4q1xlclmfloku33", nil)
+	req.Header.Set("Authorization", testRecordToken)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	expected := `"@placeholders":{"avatar":{"` + filename + `":{"blurHash":"LDTI:j]9fQ]9|co1fQo1fQfQfQfQ","color":"#ff0000","height":30,"width":40}}}`
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), expected) {
+		t.Fatalf("Expected %s in the record response, got %d %s", expected, rec.Code, rec.Body.String())
+	}
+
+	// the placeholders should be deleted together with the record
+	if err := app.Dao().DeleteRecord(record); err != nil {
+		t.Fatal(err)
+	}
+	placeholders, _ := app.Dao().FindImagePlaceholdersByRecords(collection.Id, record.Id)
+	if len(placeholders) != 0 {
+		t.Fatalf("Expected the record placeholders to be deleted, got %v", placeholders)
+	}
+}
@@ -14,7 +14,7 @@ import (
 func bindLogsApi(app core.App, rg *echo.Group) {
 	api := logsApi{app: app}
 
-	subGroup := rg.Group("/logs", RequireAdminAuth())
+	subGroup := rg.Group("/logs", RequireAdminAuth(models.AdminRoleReadonly, models.AdminRoleLogs))
 	subGroup.GET("/requests", api.requestsList)
 	subGroup.GET("/requests/stats", api.requestsStats)
 	subGroup.GET("/requests/:id", api.requestView)
@@ -22,6 +22,14 @@ func bindLogsApi(app core.App, rg *echo.Group) {
 	subGroup.GET("/errors", api.errorsList)
 
 	subGroup.GET("/logs", api.logsList)
+
+	// the webhook deliveries and audit logs contain the full records data
+	// and are accessible only by superusers (regardless of the collection rules)
+	subGroup.GET("/webhooks", api.webhookDeliveriesList, RequireAdminAuth(models.AdminRoleSuperuser))
+	subGroup.GET("/webhooks/:id", api.webhookDeliveryView, RequireAdminAuth(models.AdminRoleSuperuser))
+
+	subGroup.GET("/audit", api.auditLogsList, RequireAdminAuth(models.AdminRoleSuperuser))
+	subGroup.GET("/audit/:id", api.auditLogView, RequireAdminAuth(models.AdminRoleSuperuser))
 }
 
 type logsApi struct {
@@ -39,6 +47,18 @@ var errorFilterFields = []string{
 	"file", "line", "error", "fatal", "meta",
 }
 
+var webhookDeliveryFilterFields = []string{
+	"rowid", "id", "created", "updated",
+	"webhookId", "url", "action", "collectionId", "recordId",
+	"status", "attempts", "nextAttemptAt", "responseStatus", "error",
+}
+
+var auditLogFilterFields = []string{
+	"rowid", "id", "created", "updated",
+	"action", "collectionId", "collectionName", "recordId",
+	"actorType", "actorId", "actorCollectionId", "changes",
+}
+
 var logFilterFields = []string{
 	"rowid", "id", "created", "updated",
 	"level", "message", "meta",
@@ -121,3 +141,59 @@ func (api *logsApi) logsList(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, result)
 }
+
+func (api *logsApi) webhookDeliveriesList(c echo.Context) error {
+	fieldResolver := search.NewSimpleFieldResolver(webhookDeliveryFilterFields...)
+
+	result, err := search.NewProvider(fieldResolver).
+		Query(api.app.LogsDao().WebhookDeliveryQuery()).
+		ParseAndExec(c.QueryParams().Encode(), &[]*models.WebhookDelivery{})
+
+	if err != nil {
+		return NewBadRequestError("", err)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+func (api *logsApi) webhookDeliveryView(c echo.Context) error {
+	id := c.PathParam("id")
+	if id == "" {
+		return NewNotFoundError("", nil)
+	}
+
+	delivery, err := api.app.LogsDao().FindWebhookDeliveryById(id)
+	if err != nil || delivery == nil {
+		return NewNotFoundError("", err)
+	}
+
+	return c.JSON(http.StatusOK, delivery)
+}
+
+func (api *logsApi) auditLogsList(c echo.Context) error {
+	fieldResolver := search.NewSimpleFieldResolver(auditLogFilterFields...)
+
+	result, err := search.NewProvider(fieldResolver).
+		Query(api.app.LogsDao().AuditLogQuery()).
+		ParseAndExec(c.QueryParams().Encode(), &[]*models.AuditLog{})
+
+	if err != nil {
+		return NewBadRequestError("", err)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+func (api *logsApi) auditLogView(c echo.Context) error {
+	id := c.PathParam("id")
+	if id == "" {
+		return NewNotFoundError("", nil)
+	}
+
+	auditLog, err := api.app.LogsDao().FindAuditLogById(id)
+	if err != nil || auditLog == nil {
+		return NewNotFoundError("", err)
+	}
+
+	return c.JSON(http.StatusOK, auditLog)
+}
@@ -0,0 +1,59 @@
+package apis
+
+import (
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/models"
+)
+
+// auditActorFromContext extracts the request admin or auth record actor.
+func auditActorFromContext(c echo.Context) *core.AuditActor {
+	if admin, _ := c.Get(ContextAdminKey).(*models.Admin); admin != nil {
+		return &core.AuditActor{Type: models.AuditActorAdmin, Id: admin.Id}
+	}
+
+	if record, _ := c.Get(ContextAuthRecordKey).(*models.Record); record != nil {
+		return &core.AuditActor{
+			Type:         models.AuditActorRecord,
+			Id:           record.Id,
+			CollectionId: record.Collection().Id,
+		}
+	}
+
+	return &core.AuditActor{Type: models.AuditActorGuest}
+}
+
+// bindAuditActors registers the request admin or auth record as actor
+// of the records changed via the records crud api (incl. batch requests),
+// so that their audit logs (see [core.App.SetAuditActor]) could be
+// attributed to the request initiator.
+func bindAuditActors(app core.App) {
+	app.OnRecordBeforeCreateRequest().Add(func(e *core.RecordCreateEvent) error {
+		registerAuditActor(app, e.HttpContext, e.Record)
+		return nil
+	})
+
+	app.OnRecordBeforeUpdateRequest().Add(func(e *core.RecordUpdateEvent) error {
+		registerAuditActor(app, e.HttpContext, e.Record)
+		return nil
+	})
+
+	app.OnRecordBeforeDeleteRequest().Add(func(e *core.RecordDeleteEvent) error {
+		registerAuditActor(app, e.HttpContext, e.Record)
+		return nil
+	})
+}
+
+func registerAuditActor(app core.App, c echo.Context, record *models.Record) {
+	if c == nil || record == nil {
+		return
+	}
+
+	app.SetAuditActor(record, auditActorFromContext(c))
+
+	// the record is already saved/deleted (or the request has failed)
+	// by the time the response is written
+	c.Response().Before(func() {
+		app.SetAuditActor(record, nil)
+	})
+}
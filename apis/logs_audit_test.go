@@ -0,0 +1,320 @@
+package apis_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestAuditLogsList(t *testing.T) {
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "unauthorized",
+			Method:          http.MethodGet,
+			Url:             "/api/logs/audit",
+			ExpectedStatus:  401,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "authorized as auth record",
+			Method: http.MethodGet,
+			Url:    "/api/logs/audit",
+			RequestHeaders: map[string]string{
+				"Authorization": testRecordToken,
+			},
+			ExpectedStatus:  401,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "authorized as scoped readonly admin",
+			Method: http.MethodGet,
+			Url:    "/api/logs/audit",
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				setTestAdminRole(t, app, "sywbhecnh46rhm0", models.AdminRoleReadonly, "demo2")
+				app.ResetEventCalls()
+			},
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "authorized as admin",
+			Method: http.MethodGet,
+			Url:    "/api/logs/audit",
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				if err := tests.MockAuditLogsData(app); err != nil {
+					t.Fatal(err)
+				}
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"page":1`,
+				`"perPage":30`,
+				`"totalItems":2`,
+				`"items":[{`,
+				`"id":"p3sbvx0qm1fhz5c"`,
+				`"id":"w9z0c6e5bkr2yjd"`,
+				`"changes":{"title":{"new":"test1_update","old":"test1"}}`,
+			},
+		},
+		{
+			Name:   "authorized as admin + filter",
+			Method: http.MethodGet,
+			Url:    "/api/logs/audit?filter=actorType='auth_record'%26%26collectionName='demo2'",
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				if err := tests.MockAuditLogsData(app); err != nil {
+					t.Fatal(err)
+				}
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"totalItems":1`,
+				`"id":"w9z0c6e5bkr2yjd"`,
+			},
+		},
+		{
+			Name:   "authorized as admin with the logs role",
+			Method: http.MethodGet,
+			Url:    "/api/logs/audit",
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				setTestAdminRole(t, app, "sywbhecnh46rhm0", "logs")
+				if err := tests.MockAuditLogsData(app); err != nil {
+					t.Fatal(err)
+				}
+				app.ResetEventCalls()
+			},
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestAuditLogView(t *testing.T) {
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "unauthorized",
+			Method:          http.MethodGet,
+			Url:             "/api/logs/audit/p3sbvx0qm1fhz5c",
+			ExpectedStatus:  401,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "authorized as auth record",
+			Method: http.MethodGet,
+			Url:    "/api/logs/audit/p3sbvx0qm1fhz5c",
+			RequestHeaders: map[string]string{
+				"Authorization": testRecordToken,
+			},
+			ExpectedStatus:  401,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "authorized as logs admin",
+			Method: http.MethodGet,
+			Url:    "/api/logs/audit/p3sbvx0qm1fhz5c",
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				setTestAdminRole(t, app, "sywbhecnh46rhm0", models.AdminRoleLogs)
+				app.ResetEventCalls()
+			},
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "authorized as scoped readonly admin",
+			Method: http.MethodGet,
+			Url:    "/api/logs/audit/p3sbvx0qm1fhz5c",
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				setTestAdminRole(t, app, "sywbhecnh46rhm0", models.AdminRoleReadonly, "demo2")
+				app.ResetEventCalls()
+			},
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "authorized as admin + missing audit log",
+			Method: http.MethodGet,
+			Url:    "/api/logs/audit/missing",
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				if err := tests.MockAuditLogsData(app); err != nil {
+					t.Fatal(err)
+				}
+			},
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "authorized as admin + existing audit log",
+			Method: http.MethodGet,
+			Url:    "/api/logs/audit/w9z0c6e5bkr2yjd",
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				if err := tests.MockAuditLogsData(app); err != nil {
+					t.Fatal(err)
+				}
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"id":"w9z0c6e5bkr2yjd"`,
+				`"action":"delete"`,
+				`"recordId":"achvryl401bhse3"`,
+				`"actorType":"auth_record"`,
+				`"actorId":"4q1xlclmfloku33"`,
+				`"actorCollectionId":"_pb_users_auth_"`,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestAuditLogsRecording(t *testing.T) {
+	findAuditLogs := func(t *testing.T, app *tests.TestApp, recordId string) []*models.AuditLog {
+		logs := []*models.AuditLog{}
+
+		err := app.LogsDao().AuditLogQuery().
+			AndWhere(dbx.HashExp{"recordId": recordId}).
+			All(&logs)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return logs
+	}
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:   "update in a collection without enabled audit",
+			Method: http.MethodPatch,
+			Url:    "/api/collections/demo2/records/0yxhwia2amd8gec",
+			Body:   strings.NewReader(`{"title":"new"}`),
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				app.Settings().Logs.MaxDays = 1
+				app.Settings().Logs.AuditCollections = []string{"demo1"}
+			},
+			ExpectedStatus:  200,
+			ExpectedContent: []string{`"title":"new"`},
+			ExpectedEvents: map[string]int{
+				"OnRecordBeforeUpdateRequest": 1,
+				"OnRecordAfterUpdateRequest":  1,
+				"OnModelBeforeUpdate":         1,
+				"OnModelAfterUpdate":          1,
+			},
+			AfterTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				if logs := findAuditLogs(t, app, "0yxhwia2amd8gec"); len(logs) != 0 {
+					t.Fatalf("Expected no audit logs, got %d", len(logs))
+				}
+			},
+		},
+		{
+			Name:   "update by admin in a collection with enabled audit",
+			Method: http.MethodPatch,
+			Url:    "/api/collections/demo2/records/0yxhwia2amd8gec",
+			Body:   strings.NewReader(`{"title":"new"}`),
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				app.Settings().Logs.MaxDays = 1
+				app.Settings().Logs.AuditCollections = []string{"demo2"}
+			},
+			ExpectedStatus:  200,
+			ExpectedContent: []string{`"title":"new"`},
+			ExpectedEvents: map[string]int{
+				"OnRecordBeforeUpdateRequest": 1,
+				"OnRecordAfterUpdateRequest":  1,
+				"OnModelBeforeUpdate":         1,
+				"OnModelAfterUpdate":          1,
+			},
+			AfterTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				logs := findAuditLogs(t, app, "0yxhwia2amd8gec")
+				if len(logs) != 1 {
+					t.Fatalf("Expected 1 audit log, got %d", len(logs))
+				}
+
+				log := logs[0]
+				if log.Action != models.AuditActionUpdate {
+					t.Fatalf("Expected action %q, got %q", models.AuditActionUpdate, log.Action)
+				}
+				if log.ActorType != models.AuditActorAdmin || log.ActorId != "sywbhecnh46rhm0" {
+					t.Fatalf("Expected admin sywbhecnh46rhm0 actor, got %q %q", log.ActorType, log.ActorId)
+				}
+				if len(log.Changes) != 1 || log.Changes["title"] == nil {
+					t.Fatalf("Expected only title change, got %v", log.Changes)
+				}
+			},
+		},
+		{
+			Name:   "delete by guest in a collection with enabled audit",
+			Method: http.MethodDelete,
+			Url:    "/api/collections/demo2/records/0yxhwia2amd8gec",
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				app.Settings().Logs.MaxDays = 1
+				app.Settings().Logs.AuditCollections = []string{"sz5l5z67tg7gku0"}
+			},
+			ExpectedStatus: 204,
+			ExpectedEvents: map[string]int{
+				"OnRecordBeforeDeleteRequest": 1,
+				"OnRecordAfterDeleteRequest":  1,
+				"OnModelBeforeDelete":         1,
+				"OnModelAfterDelete":          1,
+				// the deleted record is referenced by an users record
+				"OnModelBeforeUpdate": 1,
+				"OnModelAfterUpdate":  1,
+			},
+			AfterTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				logs := findAuditLogs(t, app, "0yxhwia2amd8gec")
+				if len(logs) != 1 {
+					t.Fatalf("Expected 1 audit log, got %d", len(logs))
+				}
+
+				log := logs[0]
+				if log.Action != models.AuditActionDelete {
+					t.Fatalf("Expected action %q, got %q", models.AuditActionDelete, log.Action)
+				}
+				if log.ActorType != models.AuditActorGuest || log.ActorId != "" {
+					t.Fatalf("Expected guest actor, got %q %q", log.ActorType, log.ActorId)
+				}
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
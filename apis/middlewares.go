@@ -2,7 +2,11 @@ package apis
 
 import (
 	"fmt"
+	"math"
+	"net"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/labstack/echo/v5"
 	"github.com/pocketbase/pocketbase/core"
@@ -10,6 +14,7 @@ import (
 	"github.com/pocketbase/pocketbase/models"
 	"github.com/pocketbase/pocketbase/tokens"
 	"github.com/pocketbase/pocketbase/tools/list"
+	"github.com/pocketbase/pocketbase/tools/ratelimit"
 	"github.com/pocketbase/pocketbase/tools/security"
 	"github.com/spf13/cast"
 )
@@ -105,7 +110,10 @@ func RequireSameContextRecordAuth() echo.MiddlewareFunc {
 
 // RequireAdminAuth middleware requires a request to have
 // a valid admin Authorization header.
-func RequireAdminAuth() echo.MiddlewareFunc {
+//
+// You can further restrict the access by providing a list of allowed
+// admin roles (superusers are always allowed).
+func RequireAdminAuth(optRoles ...string) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			admin, _ := c.Get(ContextAdminKey).(*models.Admin)
@@ -113,6 +121,10 @@ func RequireAdminAuth() echo.MiddlewareFunc {
 				return NewUnauthorizedError("The request requires valid admin authorization token to be set.", nil)
 			}
 
+			if len(optRoles) > 0 && !admin.HasRole(optRoles...) {
+				return NewForbiddenError("The authorized admin is not allowed to perform this action.", nil)
+			}
+
 			return next(c)
 		}
 	}
@@ -121,7 +133,10 @@ func RequireAdminAuth() echo.MiddlewareFunc {
 // RequireAdminAuthOnlyIfAny middleware requires a request to have
 // a valid admin Authorization header ONLY if the application has
 // at least 1 existing Admin model.
-func RequireAdminAuthOnlyIfAny(app core.App) echo.MiddlewareFunc {
+//
+// Similar to [apis.RequireAdminAuth()], you can further restrict
+// the authorized admin access by providing a list of allowed admin roles.
+func RequireAdminAuthOnlyIfAny(app core.App, optRoles ...string) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			totalAdmins, err := app.Dao().TotalAdmins()
@@ -131,6 +146,10 @@ func RequireAdminAuthOnlyIfAny(app core.App) echo.MiddlewareFunc {
 
 			admin, _ := c.Get(ContextAdminKey).(*models.Admin)
 
+			if admin != nil && len(optRoles) > 0 && !admin.HasRole(optRoles...) {
+				return NewForbiddenError("The authorized admin is not allowed to perform this action.", nil)
+			}
+
 			if admin != nil || totalAdmins == 0 {
 				return next(c)
 			}
@@ -217,26 +236,12 @@ func LoadAuthContext(app core.App) echo.MiddlewareFunc {
 			// compatibility with the defaults of some HTTP clients
 			token = strings.TrimPrefix(token, "Bearer ")
 
-			claims, _ := security.ParseUnverifiedJWT(token)
-			tokenType := cast.ToString(claims["type"])
-
-			switch tokenType {
-			case tokens.TypeAdmin:
-				admin, err := app.Dao().FindAdminByToken(
-					token,
-					app.Settings().AdminAuthToken.Secret,
-				)
-				if err == nil && admin != nil {
-					c.Set(ContextAdminKey, admin)
-				}
-			case tokens.TypeAuthRecord:
-				record, err := app.Dao().FindAuthRecordByToken(
-					token,
-					app.Settings().RecordAuthToken.Secret,
-				)
-				if err == nil && record != nil {
-					c.Set(ContextAuthRecordKey, record)
-				}
+			admin, record := findAuthByToken(app, token)
+			if admin != nil {
+				c.Set(ContextAdminKey, admin)
+			}
+			if record != nil {
+				c.Set(ContextAuthRecordKey, record)
 			}
 
 			return next(c)
@@ -244,6 +249,34 @@ func LoadAuthContext(app core.App) echo.MiddlewareFunc {
 	}
 }
 
+// findAuthByToken returns the admin or auth record (if any)
+// associated with the provided valid auth token.
+func findAuthByToken(app core.App, token string) (*models.Admin, *models.Record) {
+	claims, _ := security.ParseUnverifiedJWT(token)
+	tokenType := cast.ToString(claims["type"])
+
+	switch tokenType {
+	case tokens.TypeAdmin:
+		admin, err := app.Dao().FindAdminByToken(
+			token,
+			app.Settings().AdminAuthToken.Secret,
+		)
+		if err == nil && admin != nil {
+			return admin, nil
+		}
+	case tokens.TypeAuthRecord:
+		record, err := app.Dao().FindAuthRecordByToken(
+			token,
+			app.Settings().RecordAuthToken.Secret,
+		)
+		if err == nil && record != nil {
+			return nil, record
+		}
+	}
+
+	return nil, nil
+}
+
 // LoadCollectionContext middleware finds the collection with related
 // path identifier and loads it into the request context.
 //
@@ -290,3 +323,139 @@ func ActivityLogger(app core.App) echo.MiddlewareFunc {
 		}
 	}
 }
+
+// RateLimit middleware limits the number of requests per client
+// based on the app Settings().RateLimits path rules (eg. "/api/realtime").
+//
+// A path rule matches if its label is equal to the request path or
+// if the label ends with "/" and it is a prefix of the request path
+// (the most specific path rule wins).
+//
+// The client is identified by its admin/auth record id or by its real ip.
+// Requests from admins are not rate limited.
+//
+// This middleware is expected to be already registered by default for all routes.
+func RateLimit(app core.App) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if err := checkRateLimit(app, c, rateLimitPathLabels(c.Request().URL.Path)...); err != nil {
+				return err
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// CollectionRateLimit middleware limits the number of requests per client
+// based on the app Settings().RateLimits collection action rules
+// (eg. "users:auth" or "*:create").
+//
+// The collection specific rule has precedence over the "*" one.
+// If there is no collection context (eg. for the admin auth routes)
+// only the "*" rule is checked.
+//
+// This middleware is expected to be registered after [apis.LoadCollectionContext()].
+func CollectionRateLimit(app core.App, action string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+
+			if err := checkCollectionRateLimit(app, c, collection, action); err != nil {
+				return err
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// checkCollectionRateLimit checks the current request against the
+// rate limit rule of the specified collection action (if any).
+func checkCollectionRateLimit(app core.App, c echo.Context, collection *models.Collection, action string) error {
+	labels := make([]string, 0, 2)
+
+	if collection != nil {
+		labels = append(labels, collection.Name+":"+action)
+	}
+	labels = append(labels, "*:"+action)
+
+	return checkRateLimit(app, c, labels...)
+}
+
+// rateLimitPathLabels returns the possible path rule labels
+// for the provided request path ordered by their specificity.
+//
+// For example "/api/realtime" will result in ["/api/realtime", "/api/", "/"].
+func rateLimitPathLabels(path string) []string {
+	labels := []string{path}
+
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i] == '/' && path[:i+1] != path {
+			labels = append(labels, path[:i+1])
+		}
+	}
+
+	return labels
+}
+
+const cacheKeyRateLimiter = "@rateLimiter"
+
+var rateLimiterMux sync.Mutex
+
+// appRateLimiter returns the shared app rate limiter (creating a new one if missing).
+func appRateLimiter(app core.App) *ratelimit.Limiter {
+	rateLimiterMux.Lock()
+	defer rateLimiterMux.Unlock()
+
+	if limiter, ok := app.Cache().Get(cacheKeyRateLimiter).(*ratelimit.Limiter); ok {
+		return limiter
+	}
+
+	limiter := ratelimit.New()
+	app.Cache().Set(cacheKeyRateLimiter, limiter)
+
+	return limiter
+}
+
+// checkRateLimit checks the current request against the first
+// rate limit rule matching one of the provided labels.
+//
+// Returns a 429 ApiError (and sets the Retry-After header) if the limit is reached.
+func checkRateLimit(app core.App, c echo.Context, labels ...string) error {
+	config := app.Settings().RateLimits
+	if !config.Enabled {
+		return nil
+	}
+
+	// admins are not rate limited
+	if admin, _ := c.Get(ContextAdminKey).(*models.Admin); admin != nil {
+		return nil
+	}
+
+	rule, ok := config.FindRule(labels...)
+	if !ok {
+		return nil
+	}
+
+	var clientId string
+	if record, _ := c.Get(ContextAuthRecordKey).(*models.Record); record != nil {
+		clientId = "auth:" + record.Id
+	} else {
+		ip, _, _ := net.SplitHostPort(c.Request().RemoteAddr)
+		clientId = "ip:" + logs.RealUserIp(c.Request(), ip)
+	}
+
+	allowed, retryAfter := appRateLimiter(app).Allow(
+		rule.Label+"@"+clientId,
+		rule.MaxRequests,
+		rule.DurationTime(),
+	)
+
+	if !allowed {
+		c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		return NewTooManyRequestsError("", nil)
+	}
+
+	return nil
+}
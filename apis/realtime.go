@@ -7,6 +7,10 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/labstack/echo/v5"
@@ -15,24 +19,69 @@ import (
 	"github.com/pocketbase/pocketbase/forms"
 	"github.com/pocketbase/pocketbase/models"
 	"github.com/pocketbase/pocketbase/resolvers"
+	"github.com/pocketbase/pocketbase/tools/list"
 	"github.com/pocketbase/pocketbase/tools/routine"
 	"github.com/pocketbase/pocketbase/tools/search"
 	"github.com/pocketbase/pocketbase/tools/subscriptions"
+	"github.com/pocketbase/pocketbase/tools/tokenizer"
 )
 
 // bindRealtimeApi registers the realtime api endpoints.
 func bindRealtimeApi(app core.App, rg *echo.Group) {
-	api := realtimeApi{app: app}
+	api := &realtimeApi{app: app, presence: &realtimePresence{}}
 
 	subGroup := rg.Group("/realtime", ActivityLogger(app))
 	subGroup.GET("", api.connect)
 	subGroup.POST("", api.setSubscriptions)
+	subGroup.GET("/ws", api.connectWebSocket)
+	subGroup.POST("/channels/:channel", api.publishChannelMessage, RequireAdminOrRecordAuth())
+	subGroup.GET("/channels/:channel/members", api.listChannelMembers, RequireAdminOrRecordAuth())
 
 	api.bindEvents()
 }
 
 type realtimeApi struct {
-	app core.App
+	app      core.App
+	presence *realtimePresence
+}
+
+const (
+	// realtimeHistorySize is the max number of the latest
+	// messages per topic that are kept for each SSE client.
+	realtimeHistorySize = 100
+
+	// realtimeResumeWindow is the duration after disconnect during which
+	// the SSE client messages are still buffered for a reconnect replay.
+	realtimeResumeWindow = 2 * time.Minute
+)
+
+// RealtimeHistoryKey is the client store key of the SSE client
+// messages history (see [subscriptions.History]).
+const RealtimeHistoryKey = "pbRealtimeHistory"
+
+// realtimeResumeKey is the client store key of the realtimeResumeState.
+const realtimeResumeKey = "pbRealtimeResume"
+
+// realtimeResumeState holds the parsed Last-Event-ID of a reconnected SSE client.
+type realtimeResumeState struct {
+	lastEventId string
+	clientId    string
+	messageId   uint64
+}
+
+// parseRealtimeEventId parses an SSE event id in the format "clientId:messageId".
+//
+// For backward compatibility the event id could be also just the "clientId".
+func parseRealtimeEventId(eventId string) *realtimeResumeState {
+	clientId, rawMessageId, _ := strings.Cut(eventId, ":")
+
+	messageId, _ := strconv.ParseUint(rawMessageId, 10, 64)
+
+	return &realtimeResumeState{
+		lastEventId: eventId,
+		clientId:    clientId,
+		messageId:   messageId,
+	}
 }
 
 func (api *realtimeApi) connect(c echo.Context) error {
@@ -42,6 +91,11 @@ func (api *realtimeApi) connect(c echo.Context) error {
 
 	// register new subscription client
 	client := subscriptions.NewDefaultClient()
+	client.Set(RealtimeHistoryKey, subscriptions.NewHistory(realtimeHistorySize))
+	if lastEventId := c.Request().Header.Get("Last-Event-ID"); lastEventId != "" {
+		// replayed on subscriptions submit (see api.resumeClient)
+		client.Set(realtimeResumeKey, parseRealtimeEventId(lastEventId))
+	}
 	api.app.SubscriptionsBroker().Register(client)
 	defer func() {
 		disconnectEvent := &core.RealtimeDisconnectEvent{
@@ -53,7 +107,14 @@ func (api *realtimeApi) connect(c echo.Context) error {
 			log.Println(err)
 		}
 
-		api.app.SubscriptionsBroker().Unregister(client.Id())
+		// stop sending new messages but keep the client registered
+		// for a while so that its history is still populated
+		// in case of a reconnect (see api.resumeClient)
+		client.Discard()
+		api.syncPresence()
+		time.AfterFunc(realtimeResumeWindow, func() {
+			api.app.SubscriptionsBroker().Unregister(client.Id())
+		})
 	}()
 
 	c.Response().Header().Set("Content-Type", "text/event-stream; charset=UTF-8")
@@ -81,13 +142,14 @@ func (api *realtimeApi) connect(c echo.Context) error {
 		HttpContext: c,
 		Client:      client,
 		Message: &subscriptions.Message{
+			Id:   subscriptions.NextMessageId(),
 			Name: "PB_CONNECT",
 			Data: `{"clientId":"` + client.Id() + `"}`,
 		},
 	}
 	connectMsgErr := api.app.OnRealtimeBeforeMessageSend().Trigger(connectMsgEvent, func(e *core.RealtimeMessageEvent) error {
 		w := e.HttpContext.Response()
-		fmt.Fprint(w, "id:"+client.Id()+"\n")
+		fmt.Fprint(w, "id:"+realtimeEventId(e.Client, e.Message)+"\n")
 		fmt.Fprint(w, "event:"+e.Message.Name+"\n")
 		fmt.Fprint(w, "data:"+e.Message.Data+"\n\n")
 		w.Flush()
@@ -128,7 +190,7 @@ func (api *realtimeApi) connect(c echo.Context) error {
 			}
 			msgErr := api.app.OnRealtimeBeforeMessageSend().Trigger(msgEvent, func(e *core.RealtimeMessageEvent) error {
 				w := e.HttpContext.Response()
-				fmt.Fprint(w, "id:"+e.Client.Id()+"\n")
+				fmt.Fprint(w, "id:"+realtimeEventId(e.Client, e.Message)+"\n")
 				fmt.Fprint(w, "event:"+e.Message.Name+"\n")
 				fmt.Fprint(w, "data:"+e.Message.Data+"\n\n")
 				w.Flush()
@@ -201,6 +263,11 @@ func (api *realtimeApi) setSubscriptions(c echo.Context) error {
 		// subscribe to the new subscriptions
 		e.Client.Subscribe(e.Subscriptions...)
 
+		// replay the missed messages of a reconnected client (if any)
+		api.resumeClient(e.Client)
+
+		api.syncPresence()
+
 		return e.HttpContext.NoContent(http.StatusNoContent)
 	})
 
@@ -211,6 +278,89 @@ func (api *realtimeApi) setSubscriptions(c echo.Context) error {
 	return handlerErr
 }
 
+// realtimeEventId returns the SSE event id of the client message
+// in the format "clientId:messageId" (or just "clientId" if the message has no id).
+func realtimeEventId(client subscriptions.Client, msg *subscriptions.Message) string {
+	if msg.Id == 0 {
+		return client.Id()
+	}
+
+	return client.Id() + ":" + strconv.FormatUint(msg.Id, 10)
+}
+
+// resumeClient replays the missed messages of a reconnected SSE client
+// (aka. a client connected with Last-Event-ID header) for its current subscriptions.
+//
+// The messages are replayed from the previous client history only if both
+// clients have the same auth state, otherwise or if some of the messages
+// are no longer available, a "PB_RESYNC" message is sent instead.
+//
+// Note: The resume is performed only once per client.
+func (api *realtimeApi) resumeClient(client subscriptions.Client) {
+	state, _ := client.Get(realtimeResumeKey).(*realtimeResumeState)
+	if state == nil {
+		return // not a reconnect
+	}
+	client.Set(realtimeResumeKey, nil)
+
+	var messages []subscriptions.Message
+	var complete bool
+
+	prevClient, _ := api.app.SubscriptionsBroker().ClientById(state.clientId)
+	if prevClient != nil && prevClient.Id() != client.Id() &&
+		extractAuthIdFromGetter(prevClient) == extractAuthIdFromGetter(client) {
+		if history, _ := prevClient.Get(RealtimeHistoryKey).(*subscriptions.History); history != nil {
+			topics := make([]string, 0, len(client.Subscriptions()))
+			for sub := range client.Subscriptions() {
+				topics = append(topics, sub)
+			}
+			messages, complete = history.Since(state.messageId, topics...)
+		}
+
+		// the previous connection is no longer needed
+		if prevClient.IsDiscarded() {
+			api.app.SubscriptionsBroker().Unregister(prevClient.Id())
+		}
+	}
+
+	if !complete {
+		messages = []subscriptions.Message{{
+			Id:   subscriptions.NextMessageId(),
+			Name: "PB_RESYNC",
+			Data: `{"lastEventId":` + strconv.Quote(state.lastEventId) + `}`,
+		}}
+	}
+
+	api.sendClientMessages(client, messages...)
+}
+
+// sendClientMessages stores the messages in the client history (if any)
+// and sends them in the background, preserving their order, to the client channel.
+func (api *realtimeApi) sendClientMessages(client subscriptions.Client, messages ...subscriptions.Message) {
+	if len(messages) == 0 {
+		return
+	}
+
+	if history, _ := client.Get(RealtimeHistoryKey).(*subscriptions.History); history != nil {
+		for _, msg := range messages {
+			history.Push(msg)
+		}
+	}
+
+	if client.IsDiscarded() {
+		return // disconnected client
+	}
+
+	routine.FireAndForget(func() {
+		for _, msg := range messages {
+			if client.IsDiscarded() {
+				return
+			}
+			client.Channel() <- msg
+		}
+	})
+}
+
 // updateClientsAuthModel updates the existing clients auth model with the new one (matched by ID).
 func (api *realtimeApi) updateClientsAuthModel(contextKey string, newModel models.Model) error {
 	for _, client := range api.app.SubscriptionsBroker().Clients() {
@@ -232,6 +382,8 @@ func (api *realtimeApi) unregisterClientsByAuthModel(contextKey string, model mo
 		}
 	}
 
+	api.syncPresence()
+
 	return nil
 }
 
@@ -262,11 +414,17 @@ func (api *realtimeApi) bindEvents() {
 		return nil
 	})
 
+	// broadcast the record and custom channel events from the other app instances
+	api.app.SubscriptionsBroker().OnEvent(api.handleBrokerEvent)
+
 	api.app.OnModelAfterCreate().PreAdd(func(e *core.ModelEvent) error {
 		if record, ok := e.Model.(*models.Record); ok {
 			if err := api.broadcastRecord("create", record); err != nil && api.app.IsDebug() {
 				log.Println(err)
 			}
+			if err := api.publishRecordEvent("create", record); err != nil && api.app.IsDebug() {
+				log.Println(err)
+			}
 		}
 		return nil
 	})
@@ -276,6 +434,9 @@ func (api *realtimeApi) bindEvents() {
 			if err := api.broadcastRecord("update", record); err != nil && api.app.IsDebug() {
 				log.Println(err)
 			}
+			if err := api.publishRecordEvent("update", record); err != nil && api.app.IsDebug() {
+				log.Println(err)
+			}
 		}
 		return nil
 	})
@@ -285,6 +446,9 @@ func (api *realtimeApi) bindEvents() {
 			if err := api.broadcastRecord("delete", record); err != nil && api.app.IsDebug() {
 				log.Println(err)
 			}
+			if err := api.publishRecordEvent("delete", record); err != nil && api.app.IsDebug() {
+				log.Println(err)
+			}
 		}
 		return nil
 	})
@@ -293,8 +457,8 @@ func (api *realtimeApi) bindEvents() {
 func (api *realtimeApi) canAccessRecord(client subscriptions.Client, record *models.Record, accessRule *string) bool {
 	admin, _ := client.Get(ContextAdminKey).(*models.Admin)
 	if admin != nil {
-		// admins can access everything
-		return true
+		// admins can access everything within their role and scopes
+		return admin.CanViewRecords(record.Collection())
 	}
 
 	if accessRule == nil {
@@ -307,13 +471,7 @@ func (api *realtimeApi) canAccessRecord(client subscriptions.Client, record *mod
 			return nil // empty public rule
 		}
 
-		// emulate request data
-		requestData := &models.RequestData{
-			Method: "GET",
-		}
-		requestData.AuthRecord, _ = client.Get(ContextAuthRecordKey).(*models.Record)
-
-		resolver := resolvers.NewRecordFieldResolver(api.app.Dao(), record.Collection(), requestData, true)
+		resolver := resolvers.NewRecordFieldResolver(api.app.Dao(), record.Collection(), realtimeRequestData(client), true)
 		expr, err := search.FilterData(*accessRule).BuildExpr(resolver)
 		if err != nil {
 			return err
@@ -324,17 +482,99 @@ func (api *realtimeApi) canAccessRecord(client subscriptions.Client, record *mod
 		return nil
 	}
 
-	foundRecord, err := api.app.Dao().FindRecordById(record.Collection().Id, record.Id, ruleFunc)
-	if err == nil && foundRecord != nil {
-		return true
+	return api.recordSatisfies(record, ruleFunc)
+}
+
+// recordSatisfies checks whether the record satisfies the ruleFunc query conditions.
+//
+// Records received from the other app instances are not marked as persisted
+// (see api.handleBrokerEvent) and they are checked against a virtual row
+// with the event snapshot values because the related db row may not
+// exist or may be already changed (eg. on delete).
+func (api *realtimeApi) recordSatisfies(record *models.Record, ruleFunc func(q *dbx.SelectQuery) error) bool {
+	if !record.IsNew() {
+		foundRecord, err := api.app.Dao().FindRecordById(record.Collection().Id, record.Id, ruleFunc)
+
+		return err == nil && foundRecord != nil
 	}
 
-	return false
+	values := record.ColumnValueMap()
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	columns := make([]string, len(names))
+	params := make(dbx.Params, len(names))
+	for i, name := range names {
+		placeholder := fmt.Sprintf("v%d", i)
+		columns[i] = fmt.Sprintf("{:%s} AS [[%s]]", placeholder, name)
+		params[placeholder] = values[name]
+	}
+
+	query := api.app.Dao().DB().
+		Select("(1)").
+		From(fmt.Sprintf("(SELECT %s) {{%s}}", strings.Join(columns, ", "), record.Collection().Name)).
+		Bind(params).
+		Limit(1)
+
+	if err := ruleFunc(query); err != nil {
+		return false
+	}
+
+	var exists bool
+	if err := query.Row(&exists); err != nil {
+		return false
+	}
+
+	return exists
 }
 
 type recordData struct {
-	Action string         `json:"action"`
-	Record *models.Record `json:"record"`
+	Action string `json:"action"`
+	Record any    `json:"record"`
+}
+
+// realtimeSubscriptionOptions defines the optional subscription
+// topic query parameters, eg. "demo/*?filter=status='open'&expand=rel".
+type realtimeSubscriptionOptions struct {
+	Filter string
+	Expand []string
+	Fields []string
+}
+
+// parseSubscription splits the subscription into its base topic and options.
+//
+// The options are expected to be a regular url query string
+// (it is recommended to url encode the param values).
+func parseSubscription(subscription string) (string, *realtimeSubscriptionOptions, error) {
+	options := &realtimeSubscriptionOptions{}
+
+	topic, rawQuery, hasQuery := strings.Cut(subscription, "?")
+	if !hasQuery {
+		return topic, options, nil
+	}
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return topic, options, err
+	}
+
+	options.Filter = query.Get(search.FilterQueryParam)
+	options.Expand = splitListParam(query.Get(expandQueryParam))
+	options.Fields = splitListParam(query.Get(fieldsQueryParam))
+
+	return topic, options, nil
+}
+
+// splitListParam splits the comma separated values of a query parameter
+// (the commas enclosed in parenthesis or quotes, eg. in expand modifiers, are ignored).
+func splitListParam(value string) []string {
+	parts, _ := tokenizer.NewFromString(value).ScanAll()
+
+	return list.NonzeroUniques(parts)
 }
 
 func (api *realtimeApi) broadcastRecord(action string, record *models.Record) error {
@@ -362,56 +602,62 @@ func (api *realtimeApi) broadcastRecord(action string, record *models.Record) er
 		collection.Id:   collection.ListRule,
 	}
 
-	data := &recordData{
-		Action: action,
-		Record: cleanRecord,
-	}
-
-	dataBytes, err := json.Marshal(data)
-	if err != nil {
-		if api.app.IsDebug() {
-			log.Println(err)
-		}
-		return err
-	}
-
-	encodedData := string(dataBytes)
+	// the encoded data of the subscriptions without options
+	// (it is the same for all clients and it is computed only once)
+	var defaultEncodedData string
 
 	for _, client := range clients {
 		client := client
 
-		for subscription, rule := range subscriptionRuleMap {
-			if !client.HasSubscription(subscription) {
+		for subscription := range client.Subscriptions() {
+			topic, options, err := parseSubscription(subscription)
+			if err != nil {
+				continue // malformed subscription options
+			}
+
+			rule, ok := subscriptionRuleMap[topic]
+			if !ok {
 				continue
 			}
 
-			if !api.canAccessRecord(client, data.Record, rule) {
+			if !api.canAccessRecord(client, cleanRecord, rule) {
 				continue
 			}
 
-			msg := subscriptions.Message{
-				Name: subscription,
-				Data: encodedData,
+			if options.Filter != "" && !api.matchesFilter(client, cleanRecord, options.Filter) {
+				continue
 			}
 
 			// ignore the auth record email visibility checks for
 			// auth owner, admin or manager
+			ignoreEmailVisibility := false
 			if collection.IsAuth() {
 				authId := extractAuthIdFromGetter(client)
-				if authId == data.Record.Id ||
-					api.canAccessRecord(client, data.Record, collection.AuthOptions().ManageRule) {
-					data.Record.IgnoreEmailVisibility(true) // ignore
-					if newData, err := json.Marshal(data); err == nil {
-						msg.Data = string(newData)
+				ignoreEmailVisibility = authId == cleanRecord.Id ||
+					api.canAccessRecord(client, cleanRecord, collection.AuthOptions().ManageRule)
+			}
+
+			isDefault := !ignoreEmailVisibility && len(options.Expand) == 0 && len(options.Fields) == 0
+
+			encodedData := defaultEncodedData
+			if !isDefault || encodedData == "" {
+				encodedData, err = api.encodeRecordData(client, action, cleanRecord, options, ignoreEmailVisibility)
+				if err != nil {
+					if api.app.IsDebug() {
+						log.Println(err)
 					}
-					data.Record.IgnoreEmailVisibility(false) // restore
+					continue
 				}
-			}
 
-			routine.FireAndForget(func() {
-				if !client.IsDiscarded() {
-					client.Channel() <- msg
+				if isDefault {
+					defaultEncodedData = encodedData
 				}
+			}
+
+			api.sendClientMessages(client, subscriptions.Message{
+				Id:   subscriptions.NextMessageId(),
+				Name: subscription,
+				Data: encodedData,
 			})
 		}
 	}
@@ -419,6 +665,89 @@ func (api *realtimeApi) broadcastRecord(action string, record *models.Record) er
 	return nil
 }
 
+// matchesFilter checks whether the record satisfies the subscription filter
+// (evaluated with the same restrictions as the records list filter).
+func (api *realtimeApi) matchesFilter(client subscriptions.Client, record *models.Record, filter string) bool {
+	requestData := realtimeRequestData(client)
+
+	if requestData.Admin == nil && (strings.Contains(filter, "@collection.") || strings.Contains(filter, "@request.")) {
+		return false // only admins can filter by @collection and @request fields
+	}
+
+	ruleFunc := func(q *dbx.SelectQuery) error {
+		resolver := resolvers.NewRecordFieldResolver(
+			api.app.Dao(),
+			record.Collection(),
+			requestData,
+			// hidden fields are searchable only by admins
+			requestData.Admin != nil,
+		)
+		expr, err := search.FilterData(filter).BuildExpr(resolver)
+		if err != nil {
+			return err
+		}
+		resolver.UpdateQuery(q)
+		q.AndWhere(expr)
+
+		return nil
+	}
+
+	return api.recordSatisfies(record, ruleFunc)
+}
+
+// encodeRecordData returns the serialized realtime record message data
+// expanded and projected according to the subscription options.
+func (api *realtimeApi) encodeRecordData(
+	client subscriptions.Client,
+	action string,
+	record *models.Record,
+	options *realtimeSubscriptionOptions,
+	ignoreEmailVisibility bool,
+) (string, error) {
+	// work with a copy since the record is shared between all clients
+	recordCopy := record.CleanCopy()
+	recordCopy.IgnoreEmailVisibility(ignoreEmailVisibility)
+
+	if len(options.Expand) > 0 {
+		requestData := realtimeRequestData(client)
+
+		errs := api.app.Dao().ExpandRecordsWithFilter(
+			[]*models.Record{recordCopy},
+			options.Expand,
+			expandFetch(api.app.Dao(), requestData),
+			expandFilter(api.app.Dao(), requestData),
+		)
+		if len(errs) > 0 && api.app.IsDebug() {
+			log.Println("Failed to expand realtime record:", errs)
+		}
+	}
+
+	recordCopy.SetExportFields(options.Fields...)
+
+	data := &recordData{
+		Action: action,
+		Record: recordCopy,
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+
+	return string(encoded), nil
+}
+
+// realtimeRequestData emulates the request data of the realtime client.
+func realtimeRequestData(client subscriptions.Client) *models.RequestData {
+	requestData := &models.RequestData{
+		Method: "GET",
+	}
+	requestData.Admin, _ = client.Get(ContextAdminKey).(*models.Admin)
+	requestData.AuthRecord, _ = client.Get(ContextAuthRecordKey).(*models.Record)
+
+	return requestData
+}
+
 type getter interface {
 	Get(string) any
 }
@@ -0,0 +1,132 @@
+package apis
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/tools/subscriptions"
+)
+
+const (
+	// RealtimeRecordEvent is the name of the broker event shared
+	// with the other app instances on record create, update and delete.
+	RealtimeRecordEvent = "@record"
+
+	// RealtimeChannelEvent is the name of the broker event shared
+	// with the other app instances on custom channel message.
+	RealtimeChannelEvent = "@channel"
+)
+
+// realtimeRecordEventData defines the RealtimeRecordEvent data.
+type realtimeRecordEventData struct {
+	Action       string         `json:"action"`
+	CollectionId string         `json:"collectionId"`
+	Record       map[string]any `json:"record"`
+}
+
+// realtimeChannelEventData defines the RealtimeChannelEvent data.
+type realtimeChannelEventData struct {
+	Channel string          `json:"channel"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// publishRecordEvent shares the record change with the other app instances
+// (it is a no-op if the subscriptions broker doesn't have an adapter).
+func (api *realtimeApi) publishRecordEvent(action string, record *models.Record) error {
+	collection := record.Collection()
+	if collection == nil {
+		return nil
+	}
+
+	values := record.ColumnValueMap()
+
+	// the auth secrets are never needed for the realtime access checks
+	delete(values, schema.FieldNamePasswordHash)
+	delete(values, schema.FieldNameTokenKey)
+
+	encoded, err := json.Marshal(&realtimeRecordEventData{
+		Action:       action,
+		CollectionId: collection.Id,
+		Record:       values,
+	})
+	if err != nil {
+		return err
+	}
+
+	return api.app.SubscriptionsBroker().Publish(subscriptions.Event{
+		Name: RealtimeRecordEvent,
+		Data: string(encoded),
+	})
+}
+
+// publishChannelEvent shares the encoded channel data with the other app instances
+// (it is a no-op if the subscriptions broker doesn't have an adapter).
+func (api *realtimeApi) publishChannelEvent(channel string, encodedData []byte) error {
+	encoded, err := json.Marshal(&realtimeChannelEventData{
+		Channel: channel,
+		Data:    encodedData,
+	})
+	if err != nil {
+		return err
+	}
+
+	return api.app.SubscriptionsBroker().Publish(subscriptions.Event{
+		Name: RealtimeChannelEvent,
+		Data: string(encoded),
+	})
+}
+
+// handleBrokerEvent broadcasts the record and custom channel
+// events received from the other app instances to the local clients.
+func (api *realtimeApi) handleBrokerEvent(event subscriptions.Event) {
+	var err error
+
+	switch event.Name {
+	case RealtimeRecordEvent:
+		err = api.handleRecordEvent(event)
+	case RealtimeChannelEvent:
+		err = api.handleChannelEvent(event)
+	}
+
+	if err != nil && api.app.IsDebug() {
+		log.Println("Failed to handle realtime broker event:", event.Name, err)
+	}
+}
+
+func (api *realtimeApi) handleRecordEvent(event subscriptions.Event) error {
+	data := &realtimeRecordEventData{}
+	if err := json.Unmarshal([]byte(event.Data), data); err != nil {
+		return err
+	}
+
+	collection, err := api.app.Dao().FindCollectionByNameOrId(data.CollectionId)
+	if err != nil {
+		return err
+	}
+
+	// note: the record is intentionally left marked as "new"
+	// so that the access checks are performed against its
+	// snapshot values (see api.recordSatisfies)
+	record := models.NewRecord(collection)
+	record.Load(data.Record)
+
+	return api.broadcastRecord(data.Action, record)
+}
+
+func (api *realtimeApi) handleChannelEvent(event subscriptions.Event) error {
+	data := &realtimeChannelEventData{}
+	if err := json.Unmarshal([]byte(event.Data), data); err != nil {
+		return err
+	}
+
+	config, ok := api.app.Settings().Realtime.FindChannel(data.Channel)
+	if !ok {
+		return nil // the channel is not available for the current app instance
+	}
+
+	api.deliverChannel(config, data.Data)
+
+	return nil
+}
@@ -0,0 +1,239 @@
+package apis_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/models/settings"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/subscriptions"
+	"github.com/pocketbase/pocketbase/tools/types"
+	_ "modernc.org/sqlite"
+)
+
+type testRealtimeAdapter struct {
+	mux       sync.Mutex
+	published []subscriptions.Event
+	handler   subscriptions.EventHandler
+}
+
+func (a *testRealtimeAdapter) Publish(event subscriptions.Event) error {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	a.published = append(a.published, event)
+
+	return nil
+}
+
+func (a *testRealtimeAdapter) Listen(handler subscriptions.EventHandler) error {
+	a.handler = handler
+	return nil
+}
+
+func (a *testRealtimeAdapter) Close() error {
+	return nil
+}
+
+func (a *testRealtimeAdapter) Published() []subscriptions.Event {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	return append([]subscriptions.Event{}, a.published...)
+}
+
+func TestRealtimeBrokerPublish(t *testing.T) {
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	testApp.Settings().Realtime.Channels = []settings.RealtimeChannel{{Name: "room"}}
+
+	adapter := &testRealtimeAdapter{}
+	if err := testApp.SubscriptionsBroker().SetAdapter(adapter); err != nil {
+		t.Fatal(err)
+	}
+	defer testApp.SubscriptionsBroker().SetAdapter(nil)
+
+	if _, err := apis.InitApi(testApp); err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := testApp.Dao().FindRecordById("users", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user.Set("name", "broker_update")
+	if err := testApp.Dao().SaveRecord(user); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := apis.PublishRealtimeChannelMessage(testApp, "room", "hello"); err != nil {
+		t.Fatal(err)
+	}
+
+	published := adapter.Published()
+	if len(published) != 2 {
+		t.Fatalf("Expected 2 published events, got %v", published)
+	}
+
+	recordEvent := published[0]
+	if recordEvent.Name != apis.RealtimeRecordEvent {
+		t.Fatalf("Expected %q event, got %q", apis.RealtimeRecordEvent, recordEvent.Name)
+	}
+	for _, expected := range []string{`"action":"update"`, `"collectionId":"_pb_users_auth_"`, `"name":"broker_update"`} {
+		if !strings.Contains(recordEvent.Data, expected) {
+			t.Errorf("Expected %s in %s", expected, recordEvent.Data)
+		}
+	}
+	for _, notExpected := range []string{`"passwordHash"`, `"tokenKey"`} {
+		if strings.Contains(recordEvent.Data, notExpected) {
+			t.Errorf("Didn't expect %s in %s", notExpected, recordEvent.Data)
+		}
+	}
+
+	channelEvent := published[1]
+	if channelEvent.Name != apis.RealtimeChannelEvent ||
+		channelEvent.Data != `{"channel":"room","data":{"action":"message","data":"hello"}}` {
+		t.Fatalf("Unexpected channel event %v", channelEvent)
+	}
+}
+
+func TestRealtimeBrokerRemoteEvents(t *testing.T) {
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	testApp.Settings().Realtime.Channels = []settings.RealtimeChannel{
+		{Name: "room", SubscribeRule: types.Pointer("")},
+	}
+
+	adapter := &testRealtimeAdapter{}
+	if err := testApp.SubscriptionsBroker().SetAdapter(adapter); err != nil {
+		t.Fatal(err)
+	}
+	defer testApp.SubscriptionsBroker().SetAdapter(nil)
+
+	if _, err := apis.InitApi(testApp); err != nil {
+		t.Fatal(err)
+	}
+
+	matchingClient := subscriptions.NewDefaultClient()
+	matchingClient.Subscribe("demo2/*?filter=title='remote'", "@channel/room")
+	testApp.SubscriptionsBroker().Register(matchingClient)
+
+	otherClient := subscriptions.NewDefaultClient()
+	otherClient.Subscribe("demo2/*?filter=title='other'")
+	testApp.SubscriptionsBroker().Register(otherClient)
+
+	// record that doesn't exist in the current app instance db
+	adapter.handler(subscriptions.Event{
+		Name: apis.RealtimeRecordEvent,
+		Data: `{"action":"delete","collectionId":"sz5l5z67tg7gku0","record":{"id":"remote000000001","title":"remote","active":true}}`,
+	})
+
+	msg := receiveTestClientMessage(t, matchingClient)
+	if !strings.Contains(msg.Data, `"action":"delete"`) || !strings.Contains(msg.Data, `"id":"remote000000001"`) {
+		t.Fatalf("Unexpected record message %v", msg)
+	}
+	expectNoTestClientMessage(t, otherClient)
+
+	// custom channel message
+	adapter.handler(subscriptions.Event{
+		Name: apis.RealtimeChannelEvent,
+		Data: `{"channel":"room","data":{"action":"message","data":{"a":1}}}`,
+	})
+
+	msg = receiveTestClientMessage(t, matchingClient)
+	if msg.Name != "@channel/room" || msg.Data != `{"action":"message","data":{"a":1}}` {
+		t.Fatalf("Unexpected channel message %v", msg)
+	}
+
+	// unknown channel and event
+	adapter.handler(subscriptions.Event{
+		Name: apis.RealtimeChannelEvent,
+		Data: `{"channel":"missing","data":{}}`,
+	})
+	adapter.handler(subscriptions.Event{Name: "unknown", Data: "test"})
+	expectNoTestClientMessage(t, matchingClient)
+
+	// the remote events shouldn't be published again
+	if published := adapter.Published(); len(published) != 0 {
+		t.Fatalf("Expected no published events, got %v", published)
+	}
+}
+
+func TestRealtimeBrokerOutboxAdapter(t *testing.T) {
+	outboxPath := filepath.Join(t.TempDir(), "outbox.db")
+
+	// initializes a new app instance (aka. node) connected to the shared outbox
+	newNode := func() *tests.TestApp {
+		app, _ := tests.NewTestApp()
+		t.Cleanup(app.Cleanup)
+
+		app.Settings().Realtime.Channels = []settings.RealtimeChannel{
+			{Name: "room", SubscribeRule: types.Pointer("")},
+		}
+
+		db, err := dbx.Open("sqlite", outboxPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { db.Close() })
+
+		adapter, err := subscriptions.NewOutboxAdapter(db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		adapter.PollInterval = 10 * time.Millisecond
+
+		if err := app.SubscriptionsBroker().SetAdapter(adapter); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { app.SubscriptionsBroker().SetAdapter(nil) })
+
+		if _, err := apis.InitApi(app); err != nil {
+			t.Fatal(err)
+		}
+
+		return app
+	}
+
+	nodeA := newNode()
+	nodeB := newNode()
+
+	client := subscriptions.NewDefaultClient()
+	client.Subscribe("demo2/*", "@channel/room")
+	nodeB.SubscriptionsBroker().Register(client)
+
+	record, err := nodeA.Dao().FindRecordById("demo2", "llvuca81nly1qls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	record.Set("title", "outbox_update")
+	if err := nodeA.Dao().SaveRecord(record); err != nil {
+		t.Fatal(err)
+	}
+
+	msg := receiveTestClientMessage(t, client)
+	data := map[string]any{}
+	if err := json.Unmarshal([]byte(msg.Data), &data); err != nil {
+		t.Fatal(err)
+	}
+	if data["action"] != "update" || data["record"].(map[string]any)["title"] != "outbox_update" {
+		t.Fatalf("Unexpected record message %v", msg)
+	}
+
+	if err := apis.PublishRealtimeChannelMessage(nodeA, "room", "hello"); err != nil {
+		t.Fatal(err)
+	}
+
+	msg = receiveTestClientMessage(t, client)
+	if msg.Name != "@channel/room" || msg.Data != `{"action":"message","data":"hello"}` {
+		t.Fatalf("Unexpected channel message %v", msg)
+	}
+}
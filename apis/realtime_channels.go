@@ -0,0 +1,288 @@
+package apis
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/settings"
+	"github.com/pocketbase/pocketbase/resolvers"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/pocketbase/pocketbase/tools/subscriptions"
+)
+
+// realtimeChannelRuleTable is the name of the virtual table
+// used as a base when resolving the realtime channel rules.
+const realtimeChannelRuleTable = "_pbRealtimeChannel"
+
+// channelData defines the custom realtime channel message data.
+type channelData struct {
+	// Action is one of "message", "join" or "leave".
+	Action string `json:"action"`
+
+	// Data is the published message payload (set only for "message").
+	Data any `json:"data,omitempty"`
+
+	// Member is the joined/left auth record (set only for "join" and "leave").
+	Member *models.Record `json:"member,omitempty"`
+}
+
+// PublishRealtimeChannelMessage sends data to all clients subscribed to the
+// named custom realtime channel (see [settings.RealtimeConfig]) that
+// satisfy the channel subscribe rule.
+//
+// The channel publish rule is not checked because it is intended only
+// for the HTTP publish requests.
+func PublishRealtimeChannelMessage(app core.App, channel string, data any) error {
+	config, ok := app.Settings().Realtime.FindChannel(channel)
+	if !ok {
+		return fmt.Errorf("Missing realtime channel %q.", channel)
+	}
+
+	api := &realtimeApi{app: app}
+
+	return api.broadcastChannel(config, &channelData{Action: "message", Data: data})
+}
+
+// RealtimeChannelMembers returns the auth records of the connected
+// clients subscribed to the named custom realtime channel
+// (each auth record is listed only once even if it has multiple connections).
+//
+// Note that only the clients connected to the current app instance are listed.
+//
+// Returns an error if the channel doesn't exist or doesn't have presence enabled.
+func RealtimeChannelMembers(app core.App, channel string) ([]*models.Record, error) {
+	config, ok := app.Settings().Realtime.FindChannel(channel)
+	if !ok || !config.Presence {
+		return nil, fmt.Errorf("Missing realtime presence channel %q.", channel)
+	}
+
+	api := &realtimeApi{app: app}
+
+	members := api.channelMembers(config)
+
+	result := make([]*models.Record, 0, len(members))
+	for _, member := range members {
+		result = append(result, member)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Id < result[j].Id
+	})
+
+	return result, nil
+}
+
+func (api *realtimeApi) publishChannelMessage(c echo.Context) error {
+	config, ok := api.app.Settings().Realtime.FindChannel(c.PathParam("channel"))
+	if !ok {
+		return NewNotFoundError("", nil)
+	}
+
+	requestData := RequestData(c)
+
+	if requestData.Admin == nil && config.PublishRule == nil {
+		// only admins can publish if the rule is nil
+		return NewForbiddenError("Only admins can perform this action.", nil)
+	}
+
+	if !api.canAccessChannel(requestData, config.PublishRule) {
+		return NewForbiddenError("You are not allowed to publish to this channel.", nil)
+	}
+
+	if err := api.broadcastChannel(config, &channelData{Action: "message", Data: requestData.Data}); err != nil {
+		return NewBadRequestError("Failed to publish the channel message.", err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (api *realtimeApi) listChannelMembers(c echo.Context) error {
+	config, ok := api.app.Settings().Realtime.FindChannel(c.PathParam("channel"))
+	if !ok || !config.Presence {
+		return NewNotFoundError("", nil)
+	}
+
+	if !api.canAccessChannel(RequestData(c), config.SubscribeRule) {
+		return NewForbiddenError("You are not allowed to access this channel.", nil)
+	}
+
+	members, err := RealtimeChannelMembers(api.app, config.Name)
+	if err != nil {
+		return NewNotFoundError("", err)
+	}
+
+	return c.JSON(http.StatusOK, members)
+}
+
+// canAccessChannel checks whether the request data satisfies the channel access rule.
+//
+// Since channels are not associated with a collection, only the
+// "@request.*" and "@collection.*" fields could be used in the rule.
+func (api *realtimeApi) canAccessChannel(requestData *models.RequestData, accessRule *string) bool {
+	if requestData.Admin != nil {
+		return true // admins can access every channel
+	}
+
+	if accessRule == nil {
+		return false // only admins can access the channel
+	}
+
+	if *accessRule == "" {
+		return true // empty public rule
+	}
+
+	virtualCollection := &models.Collection{Name: realtimeChannelRuleTable}
+
+	resolver := resolvers.NewRecordFieldResolver(api.app.Dao(), virtualCollection, requestData, true)
+	expr, err := search.FilterData(*accessRule).BuildExpr(resolver)
+	if err != nil {
+		if api.app.IsDebug() {
+			log.Println("Failed to resolve realtime channel rule:", err)
+		}
+		return false
+	}
+
+	query := api.app.Dao().DB().
+		Select("(1)").
+		From(fmt.Sprintf("(SELECT 1) {{%s}}", realtimeChannelRuleTable)).
+		AndWhere(expr).
+		Limit(1)
+	resolver.UpdateQuery(query)
+
+	var exists bool
+	if err := query.Row(&exists); err != nil {
+		return false
+	}
+
+	return exists
+}
+
+// broadcastChannel sends the channel data to all channel subscribers
+// that satisfy the channel subscribe rule (including the ones
+// connected to the other app instances).
+func (api *realtimeApi) broadcastChannel(config settings.RealtimeChannel, data *channelData) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	api.deliverChannel(config, encoded)
+
+	return api.publishChannelEvent(config.Name, encoded)
+}
+
+// deliverChannel sends the encoded channel data to the current
+// app instance channel subscribers that satisfy the channel subscribe rule.
+func (api *realtimeApi) deliverChannel(config settings.RealtimeChannel, encodedData []byte) {
+	topic := subscriptions.ChannelTopic(config.Name)
+
+	for _, client := range api.app.SubscriptionsBroker().ChannelSubscribers(config.Name) {
+		if !api.canAccessChannel(realtimeRequestData(client), config.SubscribeRule) {
+			continue
+		}
+
+		api.sendClientMessages(client, subscriptions.Message{
+			Id:   subscriptions.NextMessageId(),
+			Name: topic,
+			Data: string(encodedData),
+		})
+	}
+}
+
+// channelMembers returns the auth records (indexed by their id) of the
+// connected channel subscribers that satisfy the channel subscribe rule.
+func (api *realtimeApi) channelMembers(config settings.RealtimeChannel) map[string]*models.Record {
+	members := map[string]*models.Record{}
+
+	for _, client := range api.app.SubscriptionsBroker().ChannelSubscribers(config.Name) {
+		if client.IsDiscarded() {
+			continue // disconnected client
+		}
+
+		record, _ := client.Get(ContextAuthRecordKey).(*models.Record)
+		if record == nil {
+			continue // only auth records are tracked
+		}
+
+		if _, ok := members[record.Id]; ok {
+			continue // another connection of the same auth record
+		}
+
+		if !api.canAccessChannel(realtimeRequestData(client), config.SubscribeRule) {
+			continue
+		}
+
+		members[record.Id] = record.CleanCopy()
+	}
+
+	return members
+}
+
+// realtimePresence keeps track of the last known presence channels members.
+type realtimePresence struct {
+	mux     sync.Mutex
+	members map[string]map[string]*models.Record // channel -> member id -> member
+}
+
+// syncPresence compares the current presence channels members
+// with the last known ones and broadcasts the related
+// "join" and "leave" messages for the changed members.
+//
+// It should be called every time when the clients subscriptions
+// or auth state change (subscribe, disconnect, etc.).
+func (api *realtimeApi) syncPresence() {
+	if api.presence == nil {
+		return
+	}
+
+	api.presence.mux.Lock()
+	defer api.presence.mux.Unlock()
+
+	newMembers := map[string]map[string]*models.Record{}
+
+	for _, config := range api.app.Settings().Realtime.Channels {
+		if !config.Presence {
+			continue
+		}
+
+		current := api.channelMembers(config)
+		previous := api.presence.members[config.Name]
+		newMembers[config.Name] = current
+
+		api.broadcastPresenceChanges(config, "leave", previous, current)
+		api.broadcastPresenceChanges(config, "join", current, previous)
+	}
+
+	api.presence.members = newMembers
+}
+
+// broadcastPresenceChanges broadcasts a presence action message
+// for each member from source that is missing in target.
+func (api *realtimeApi) broadcastPresenceChanges(
+	config settings.RealtimeChannel,
+	action string,
+	source map[string]*models.Record,
+	target map[string]*models.Record,
+) {
+	ids := make([]string, 0, len(source))
+	for id := range source {
+		if _, ok := target[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		err := api.broadcastChannel(config, &channelData{Action: action, Member: source[id]})
+		if err != nil && api.app.IsDebug() {
+			log.Println("Failed to broadcast realtime presence change:", err)
+		}
+	}
+}
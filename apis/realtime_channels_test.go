@@ -0,0 +1,451 @@
+package apis_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/settings"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/subscriptions"
+	"github.com/pocketbase/pocketbase/tools/types"
+	"golang.org/x/net/websocket"
+)
+
+func TestRealtimeChannelPublish(t *testing.T) {
+	guestClient := subscriptions.NewDefaultClient()
+	authClient := subscriptions.NewDefaultClient()
+
+	setup := func(t *testing.T, app *tests.TestApp, channels ...settings.RealtimeChannel) {
+		app.Settings().Realtime.Channels = channels
+
+		user, err := app.Dao().FindRecordById("users", "4q1xlclmfloku33")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		guestClient.Unsubscribe()
+		guestClient.Subscribe("@channel/room")
+		app.SubscriptionsBroker().Register(guestClient)
+
+		authClient.Unsubscribe()
+		authClient.Subscribe("@channel/room")
+		authClient.Set(apis.ContextAuthRecordKey, user)
+		app.SubscriptionsBroker().Register(authClient)
+	}
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "unauthorized",
+			Method:          http.MethodPost,
+			Url:             "/api/realtime/channels/room",
+			Body:            strings.NewReader(`{"text":"hi"}`),
+			ExpectedStatus:  401,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "missing channel",
+			Method: http.MethodPost,
+			Url:    "/api/realtime/channels/missing",
+			Body:   strings.NewReader(`{"text":"hi"}`),
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				setup(t, app, settings.RealtimeChannel{Name: "room"})
+			},
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "auth record + nil publish rule",
+			Method: http.MethodPost,
+			Url:    "/api/realtime/channels/room",
+			Body:   strings.NewReader(`{"text":"hi"}`),
+			RequestHeaders: map[string]string{
+				"Authorization": testRecordToken,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				setup(t, app, settings.RealtimeChannel{Name: "room", SubscribeRule: types.Pointer("")})
+			},
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "auth record + unsatisfied publish rule",
+			Method: http.MethodPost,
+			Url:    "/api/realtime/channels/room",
+			Body:   strings.NewReader(`{"text":""}`),
+			RequestHeaders: map[string]string{
+				"Authorization": testRecordToken,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				setup(t, app, settings.RealtimeChannel{
+					Name:          "room",
+					SubscribeRule: types.Pointer(""),
+					PublishRule:   types.Pointer("@request.auth.id != '' && @request.data.text != ''"),
+				})
+			},
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "auth record + invalid publish rule",
+			Method: http.MethodPost,
+			Url:    "/api/realtime/channels/room",
+			Body:   strings.NewReader(`{"text":"hi"}`),
+			RequestHeaders: map[string]string{
+				"Authorization": testRecordToken,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				setup(t, app, settings.RealtimeChannel{
+					Name:          "room",
+					SubscribeRule: types.Pointer(""),
+					PublishRule:   types.Pointer("missing = 1"),
+				})
+			},
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "auth record + satisfied publish rule",
+			Method: http.MethodPost,
+			Url:    "/api/realtime/channels/room",
+			Body:   strings.NewReader(`{"text":"hi"}`),
+			RequestHeaders: map[string]string{
+				"Authorization": testRecordToken,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				setup(t, app, settings.RealtimeChannel{
+					Name:          "room",
+					SubscribeRule: types.Pointer("@request.auth.collectionName = 'users'"),
+					PublishRule:   types.Pointer("@request.auth.id != '' && @request.data.text != ''"),
+				})
+			},
+			ExpectedStatus: 204,
+			AfterTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				msg := receiveTestClientMessage(t, authClient)
+				if msg.Name != "@channel/room" || msg.Data != `{"action":"message","data":{"text":"hi"}}` {
+					t.Fatalf("Unexpected message %v", msg)
+				}
+
+				// the guest client doesn't satisfy the subscribe rule
+				expectNoTestClientMessage(t, guestClient)
+			},
+		},
+		{
+			Name:   "admin + nil publish rule",
+			Method: http.MethodPost,
+			Url:    "/api/realtime/channels/room",
+			Body:   strings.NewReader(`{"text":"hi"}`),
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				setup(t, app, settings.RealtimeChannel{Name: "room", SubscribeRule: types.Pointer("")})
+			},
+			ExpectedStatus: 204,
+			AfterTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				for _, client := range []subscriptions.Client{guestClient, authClient} {
+					msg := receiveTestClientMessage(t, client)
+					if msg.Data != `{"action":"message","data":{"text":"hi"}}` {
+						t.Fatalf("Unexpected message %v", msg)
+					}
+				}
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestPublishRealtimeChannelMessage(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().Realtime.Channels = []settings.RealtimeChannel{
+		{Name: "room", SubscribeRule: types.Pointer("")},
+	}
+
+	client := subscriptions.NewDefaultClient()
+	client.Subscribe("@channel/room")
+	app.SubscriptionsBroker().Register(client)
+
+	if err := apis.PublishRealtimeChannelMessage(app, "missing", "test"); err == nil {
+		t.Fatal("Expected error for missing channel, got nil")
+	}
+
+	if err := apis.PublishRealtimeChannelMessage(app, "room", map[string]any{"a": 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	msg := receiveTestClientMessage(t, client)
+	if msg.Name != "@channel/room" || msg.Data != `{"action":"message","data":{"a":1}}` {
+		t.Fatalf("Unexpected message %v", msg)
+	}
+}
+
+func TestRealtimeChannelMembers(t *testing.T) {
+	setup := func(t *testing.T, app *tests.TestApp, channel settings.RealtimeChannel) {
+		app.Settings().Realtime.Channels = []settings.RealtimeChannel{channel}
+
+		for _, id := range []string{"4q1xlclmfloku33", "oap640cot4yru2s"} {
+			user, err := app.Dao().FindRecordById("users", id)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// multiple connections of the same auth record
+			for i := 0; i < 2; i++ {
+				client := subscriptions.NewDefaultClient()
+				client.Subscribe("@channel/room")
+				client.Set(apis.ContextAuthRecordKey, user)
+				app.SubscriptionsBroker().Register(client)
+			}
+		}
+
+		// guest
+		guest := subscriptions.NewDefaultClient()
+		guest.Subscribe("@channel/room")
+		app.SubscriptionsBroker().Register(guest)
+
+		// not subscribed
+		other := subscriptions.NewDefaultClient()
+		other.Subscribe("@channel/other")
+		other.Set(apis.ContextAuthRecordKey, models.NewRecord(&models.Collection{}))
+		app.SubscriptionsBroker().Register(other)
+
+		// disconnected
+		user, err := app.Dao().FindRecordById("users", "bgs820n361vj1qd")
+		if err != nil {
+			t.Fatal(err)
+		}
+		disconnected := subscriptions.NewDefaultClient()
+		disconnected.Subscribe("@channel/room")
+		disconnected.Set(apis.ContextAuthRecordKey, user)
+		disconnected.Discard()
+		app.SubscriptionsBroker().Register(disconnected)
+	}
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "unauthorized",
+			Method:          http.MethodGet,
+			Url:             "/api/realtime/channels/room/members",
+			ExpectedStatus:  401,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "channel without presence",
+			Method: http.MethodGet,
+			Url:    "/api/realtime/channels/room/members",
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				setup(t, app, settings.RealtimeChannel{Name: "room"})
+			},
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "auth record + unsatisfied subscribe rule",
+			Method: http.MethodGet,
+			Url:    "/api/realtime/channels/room/members",
+			RequestHeaders: map[string]string{
+				"Authorization": testRecordToken,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				setup(t, app, settings.RealtimeChannel{
+					Name:          "room",
+					SubscribeRule: types.Pointer("@request.auth.verified = true"),
+					Presence:      true,
+				})
+			},
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "auth record + satisfied subscribe rule",
+			Method: http.MethodGet,
+			Url:    "/api/realtime/channels/room/members",
+			RequestHeaders: map[string]string{
+				"Authorization": testRecordToken,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				setup(t, app, settings.RealtimeChannel{
+					Name:          "room",
+					SubscribeRule: types.Pointer("@request.auth.id != ''"),
+					Presence:      true,
+				})
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`[{`,
+				`"id":"4q1xlclmfloku33"`,
+				`"id":"oap640cot4yru2s"`,
+			},
+			NotExpectedContent: []string{
+				`"id":"bgs820n361vj1qd"`,
+				`"email":"test@example.com"`,
+			},
+		},
+		{
+			Name:   "admin + nil subscribe rule",
+			Method: http.MethodGet,
+			Url:    "/api/realtime/channels/room/members",
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				setup(t, app, settings.RealtimeChannel{Name: "room", Presence: true})
+			},
+			ExpectedStatus: 200,
+			// the members must also satisfy the subscribe rule
+			ExpectedContent: []string{`[]`},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestRealtimeChannelPresence(t *testing.T) {
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	testApp.Settings().Realtime.Channels = []settings.RealtimeChannel{
+		{Name: "room", SubscribeRule: types.Pointer(""), Presence: true},
+	}
+
+	e, err := apis.InitApi(testApp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	dial := func(token string) *websocket.Conn {
+		config, err := websocket.NewConfig(
+			"ws"+strings.TrimPrefix(server.URL, "http")+"/api/realtime/ws",
+			server.URL,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != "" {
+			config.Header.Set("Authorization", token)
+		}
+
+		ws, err := websocket.DialConfig(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// PB_CONNECT
+		receiveTestWSReply(t, ws, "message", "")
+
+		return ws
+	}
+
+	send := func(ws *websocket.Conn, cmd string) {
+		if err := websocket.Message.Send(ws, cmd); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	guestWS := dial("")
+	defer guestWS.Close()
+	send(guestWS, `{"id":"g1","type":"subscribe","subscriptions":["@channel/room"]}`)
+	receiveTestWSReply(t, guestWS, "ack", "g1")
+
+	userWS := dial(testRecordToken)
+	defer userWS.Close()
+	send(userWS, `{"id":"u1","type":"subscribe","subscriptions":["@channel/room"]}`)
+	receiveTestWSReply(t, userWS, "ack", "u1")
+
+	// join
+	for _, ws := range []*websocket.Conn{guestWS, userWS} {
+		msg := receiveTestWSReply(t, ws, "message", "")
+		if msg.Name != "@channel/room" ||
+			!strings.Contains(string(msg.Data), `"action":"join"`) ||
+			!strings.Contains(string(msg.Data), `"id":"4q1xlclmfloku33"`) {
+			t.Fatalf("Expected join message, got %s %s", msg.Name, msg.Data)
+		}
+	}
+
+	// members list
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/realtime/channels/room/members", nil)
+	req.Header.Set("Authorization", testAdminToken)
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+	members := []map[string]any{}
+	if err := json.Unmarshal(body, &members); err != nil {
+		t.Fatal(err)
+	}
+	if len(members) != 1 || members[0]["id"] != "4q1xlclmfloku33" {
+		t.Fatalf("Expected only 4q1xlclmfloku33 member, got %s", body)
+	}
+
+	// leave
+	send(userWS, `{"id":"u2","type":"unsubscribe"}`)
+	receiveTestWSReply(t, userWS, "ack", "u2")
+	msg := receiveTestWSReply(t, guestWS, "message", "")
+	if !strings.Contains(string(msg.Data), `"action":"leave"`) ||
+		!strings.Contains(string(msg.Data), `"id":"4q1xlclmfloku33"`) {
+		t.Fatalf("Expected leave message, got %s", msg.Data)
+	}
+
+	guestWS.Close()
+	userWS.Close()
+	waitForNoRealtimeClients(t, testApp)
+}
+
+func receiveTestWSReply(t *testing.T, ws *websocket.Conn, expectedType string, expectedId string) *testWSReply {
+	ws.SetReadDeadline(time.Now().Add(3 * time.Second))
+
+	reply := &testWSReply{}
+	if err := websocket.JSON.Receive(ws, reply); err != nil {
+		t.Fatal(err)
+	}
+
+	if reply.Type != expectedType || reply.Id != expectedId {
+		t.Fatalf("Expected %q reply with id %q, got %v", expectedType, expectedId, reply)
+	}
+
+	return reply
+}
+
+func receiveTestClientMessage(t *testing.T, client subscriptions.Client) subscriptions.Message {
+	select {
+	case msg := <-client.Channel():
+		return msg
+	case <-time.After(time.Second):
+		t.Fatalf("Expected client %q to receive a message", client.Id())
+	}
+
+	return subscriptions.Message{}
+}
+
+func expectNoTestClientMessage(t *testing.T, client subscriptions.Client) {
+	select {
+	case msg := <-client.Channel():
+		t.Fatalf("Expected client %q to not receive a message, got %v", client.Id(), msg)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -1,10 +1,14 @@
 package apis_test
 
 import (
+	"bufio"
 	"errors"
 	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v5"
 	"github.com/pocketbase/pocketbase/apis"
@@ -13,6 +17,7 @@ import (
 	"github.com/pocketbase/pocketbase/tests"
 	"github.com/pocketbase/pocketbase/tools/hook"
 	"github.com/pocketbase/pocketbase/tools/subscriptions"
+	"github.com/pocketbase/pocketbase/tools/types"
 )
 
 func TestRealtimeConnect(t *testing.T) {
@@ -33,8 +38,11 @@ func TestRealtimeConnect(t *testing.T) {
 				"OnRealtimeDisconnectRequest": 1,
 			},
 			AfterTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
-				if len(app.SubscriptionsBroker().Clients()) != 0 {
-					t.Errorf("Expected the subscribers to be removed after connection close, found %d", len(app.SubscriptionsBroker().Clients()))
+				// the clients are kept for a while to allow resuming the connection
+				for _, client := range app.SubscriptionsBroker().Clients() {
+					if !client.IsDiscarded() {
+						t.Errorf("Expected the subscriber %q to be discarded after connection close", client.Id())
+					}
 				}
 			},
 		},
@@ -57,8 +65,11 @@ func TestRealtimeConnect(t *testing.T) {
 				})
 			},
 			AfterTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
-				if len(app.SubscriptionsBroker().Clients()) != 0 {
-					t.Errorf("Expected the subscribers to be removed after connection close, found %d", len(app.SubscriptionsBroker().Clients()))
+				// the clients are kept for a while to allow resuming the connection
+				for _, client := range app.SubscriptionsBroker().Clients() {
+					if !client.IsDiscarded() {
+						t.Errorf("Expected the subscriber %q to be discarded after connection close", client.Id())
+					}
 				}
 			},
 		},
@@ -79,8 +90,11 @@ func TestRealtimeConnect(t *testing.T) {
 				})
 			},
 			AfterTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
-				if len(app.SubscriptionsBroker().Clients()) != 0 {
-					t.Errorf("Expected the subscribers to be removed after connection close, found %d", len(app.SubscriptionsBroker().Clients()))
+				// the clients are kept for a while to allow resuming the connection
+				for _, client := range app.SubscriptionsBroker().Clients() {
+					if !client.IsDiscarded() {
+						t.Errorf("Expected the subscriber %q to be discarded after connection close", client.Id())
+					}
 				}
 			},
 		},
@@ -341,3 +355,327 @@ func TestRealtimeAdminUpdateEvent(t *testing.T) {
 		t.Fatalf("Expected authRecord with email %q, got %q", admin2.Email, clientAdmin.Email)
 	}
 }
+
+func TestRealtimeRecordSubscriptionOptions(t *testing.T) {
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	apis.InitApi(testApp)
+
+	admin, err := testApp.Dao().FindAdminByEmail("test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// make the collection public
+	collection, err := testApp.Dao().FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	collection.ListRule = types.Pointer("")
+	collection.ViewRule = types.Pointer("")
+	if err := testApp.Dao().SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	guestClient := subscriptions.NewDefaultClient()
+	guestClient.Subscribe(
+		"demo1/*?filter=text='missing'",
+		"demo1/*?filter=text='test2'&expand=rel_one&fields=id,expand",
+		"demo1/al1h9ijdeojtsjy",
+		"demo1/*?filter=@request.auth.id=''",
+		"demo1/*?filter=invalid(",
+		"demo1/*?filter=%zz",
+		"demo2/*?filter=title!=''",
+	)
+	testApp.SubscriptionsBroker().Register(guestClient)
+
+	adminClient := subscriptions.NewDefaultClient()
+	adminClient.Set(apis.ContextAdminKey, admin)
+	adminClient.Subscribe(
+		"demo1?filter=text='test2'",
+		"demo1/*?filter=@request.auth.id=''",
+	)
+	testApp.SubscriptionsBroker().Register(adminClient)
+
+	record, err := testApp.Dao().FindRecordById("demo1", "al1h9ijdeojtsjy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testApp.Dao().SaveRecord(record); err != nil {
+		t.Fatal(err)
+	}
+
+	collectMessages := func(client subscriptions.Client) map[string]string {
+		result := map[string]string{}
+		for {
+			select {
+			case msg := <-client.Channel():
+				result[msg.Name] = msg.Data
+			case <-time.After(100 * time.Millisecond):
+				return result
+			}
+		}
+	}
+
+	guestMessages := collectMessages(guestClient)
+	if len(guestMessages) != 2 {
+		t.Fatalf("Expected 2 guest messages, got %v", guestMessages)
+	}
+
+	projected := guestMessages["demo1/*?filter=text='test2'&expand=rel_one&fields=id,expand"]
+	if !strings.Contains(projected, `"expand":{"rel_one":{`) ||
+		!strings.Contains(projected, `"id":"84nmscqy84lsi1t"`) ||
+		!strings.Contains(projected, `"id":"al1h9ijdeojtsjy"`) ||
+		strings.Contains(projected, `"text":"test2"`) {
+		t.Fatalf("Unexpected filtered subscription message data %s", projected)
+	}
+
+	plain := guestMessages["demo1/al1h9ijdeojtsjy"]
+	if !strings.Contains(plain, `"text":"test2"`) || strings.Contains(plain, `"expand"`) {
+		t.Fatalf("Unexpected plain subscription message data %s", plain)
+	}
+
+	adminMessages := collectMessages(adminClient)
+	if len(adminMessages) != 2 {
+		t.Fatalf("Expected 2 admin messages, got %v", adminMessages)
+	}
+}
+
+type testSSEEvent struct {
+	Id   string
+	Name string
+	Data string
+}
+
+// connectTestSSE opens a new realtime SSE connection and
+// returns the new client id and the received events channel.
+func connectTestSSE(t *testing.T, serverUrl string, lastEventId string) (string, chan testSSEEvent, func()) {
+	req, err := http.NewRequest(http.MethodGet, serverUrl+"/api/realtime", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lastEventId != "" {
+		req.Header.Set("Last-Event-ID", lastEventId)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := make(chan testSSEEvent, 10)
+
+	go func() {
+		defer close(events)
+
+		event := testSSEEvent{}
+		scanner := bufio.NewScanner(res.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case strings.HasPrefix(line, "id:"):
+				event.Id = strings.TrimPrefix(line, "id:")
+			case strings.HasPrefix(line, "event:"):
+				event.Name = strings.TrimPrefix(line, "event:")
+			case strings.HasPrefix(line, "data:"):
+				event.Data = strings.TrimPrefix(line, "data:")
+			case line == "":
+				events <- event
+				event = testSSEEvent{}
+			}
+		}
+	}()
+
+	connectEvent := receiveTestSSE(t, events)
+	if connectEvent.Name != "PB_CONNECT" {
+		t.Fatalf("Expected PB_CONNECT event, got %v", connectEvent)
+	}
+
+	clientId, _, _ := strings.Cut(connectEvent.Id, ":")
+
+	return clientId, events, func() { res.Body.Close() }
+}
+
+func receiveTestSSE(t *testing.T, events chan testSSEEvent) testSSEEvent {
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(3 * time.Second):
+		t.Fatal("SSE event receive timeout")
+	}
+
+	return testSSEEvent{}
+}
+
+func TestRealtimeResume(t *testing.T) {
+	scenarios := []struct {
+		name string
+		// returns the Last-Event-ID header
+		prepare func(app *tests.TestApp) string
+		// the subscribe request Authorization header
+		token            string
+		expectedMessages []string
+	}{
+		{
+			name: "unknown previous client",
+			prepare: func(app *tests.TestApp) string {
+				return "missing:1"
+			},
+			expectedMessages: []string{"PB_RESYNC"},
+		},
+		{
+			name: "previous client with missed messages",
+			prepare: func(app *tests.TestApp) string {
+				history := subscriptions.NewHistory(10)
+
+				lastId := subscriptions.NextMessageId()
+				history.Push(subscriptions.Message{Id: lastId, Name: "demo1/*", Data: "a"})
+				history.Push(subscriptions.Message{Id: subscriptions.NextMessageId(), Name: "demo1/*", Data: "b"})
+				history.Push(subscriptions.Message{Id: subscriptions.NextMessageId(), Name: "demo2/*", Data: "c"})
+				history.Push(subscriptions.Message{Id: subscriptions.NextMessageId(), Name: "demo1/*", Data: "d"})
+
+				client := subscriptions.NewDefaultClient()
+				client.Set(apis.RealtimeHistoryKey, history)
+				client.Discard()
+				app.SubscriptionsBroker().Register(client)
+
+				return client.Id() + ":" + strconv.FormatUint(lastId, 10)
+			},
+			expectedMessages: []string{"demo1/*:b", "demo1/*:d"},
+		},
+		{
+			name: "previous client with discarded missed messages",
+			prepare: func(app *tests.TestApp) string {
+				history := subscriptions.NewHistory(1)
+
+				lastId := subscriptions.NextMessageId()
+				history.Push(subscriptions.Message{Id: subscriptions.NextMessageId(), Name: "demo1/*", Data: "a"})
+				history.Push(subscriptions.Message{Id: subscriptions.NextMessageId(), Name: "demo1/*", Data: "b"})
+
+				client := subscriptions.NewDefaultClient()
+				client.Set(apis.RealtimeHistoryKey, history)
+				client.Discard()
+				app.SubscriptionsBroker().Register(client)
+
+				return client.Id() + ":" + strconv.FormatUint(lastId, 10)
+			},
+			expectedMessages: []string{"PB_RESYNC"},
+		},
+		{
+			name: "previous client with different auth",
+			prepare: func(app *tests.TestApp) string {
+				history := subscriptions.NewHistory(10)
+
+				lastId := subscriptions.NextMessageId()
+				history.Push(subscriptions.Message{Id: subscriptions.NextMessageId(), Name: "demo1/*", Data: "a"})
+
+				authRecord, _ := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+
+				client := subscriptions.NewDefaultClient()
+				client.Set(apis.RealtimeHistoryKey, history)
+				client.Set(apis.ContextAuthRecordKey, authRecord)
+				client.Discard()
+				app.SubscriptionsBroker().Register(client)
+
+				return client.Id() + ":" + strconv.FormatUint(lastId, 10)
+			},
+			expectedMessages: []string{"PB_RESYNC"},
+		},
+		{
+			name: "previous client with the same auth",
+			prepare: func(app *tests.TestApp) string {
+				history := subscriptions.NewHistory(10)
+
+				lastId := subscriptions.NextMessageId()
+				history.Push(subscriptions.Message{Id: subscriptions.NextMessageId(), Name: "demo1/*", Data: "a"})
+
+				authRecord, _ := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+
+				client := subscriptions.NewDefaultClient()
+				client.Set(apis.RealtimeHistoryKey, history)
+				client.Set(apis.ContextAuthRecordKey, authRecord)
+				client.Discard()
+				app.SubscriptionsBroker().Register(client)
+
+				return client.Id() + ":" + strconv.FormatUint(lastId, 10)
+			},
+			token:            testRecordToken,
+			expectedMessages: []string{"demo1/*:a"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			testApp, _ := tests.NewTestApp()
+			defer testApp.Cleanup()
+
+			e, err := apis.InitApi(testApp)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			server := httptest.NewServer(e)
+			defer server.Close()
+
+			lastEventId := s.prepare(testApp)
+			prevClientId, _, _ := strings.Cut(lastEventId, ":")
+
+			clientId, events, closeConnection := connectTestSSE(t, server.URL, lastEventId)
+			defer closeConnection()
+
+			req, _ := http.NewRequest(
+				http.MethodPost,
+				server.URL+"/api/realtime",
+				strings.NewReader(`{"clientId":"`+clientId+`","subscriptions":["demo1/*"]}`),
+			)
+			req.Header.Set("Content-Type", "application/json")
+			if s.token != "" {
+				req.Header.Set("Authorization", s.token)
+			}
+			res, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res.Body.Close()
+			if res.StatusCode != 204 {
+				t.Fatalf("Expected 204 subscribe response, got %d", res.StatusCode)
+			}
+
+			var lastMessageId uint64
+			for _, expected := range s.expectedMessages {
+				event := receiveTestSSE(t, events)
+
+				if event.Name == "PB_RESYNC" {
+					if expected != "PB_RESYNC" {
+						t.Fatalf("Expected message %q, got PB_RESYNC", expected)
+					}
+					if !strings.Contains(event.Data, lastEventId) {
+						t.Fatalf("Expected the PB_RESYNC data to contain %q, got %q", lastEventId, event.Data)
+					}
+				} else if event.Name+":"+event.Data != expected {
+					t.Fatalf("Expected message %q, got %v", expected, event)
+				}
+
+				// ensure that the event ids are increasing
+				idClientId, rawId, _ := strings.Cut(event.Id, ":")
+				id, _ := strconv.ParseUint(rawId, 10, 64)
+				if idClientId != clientId || id <= lastMessageId {
+					t.Fatalf("Invalid event id %q (previous message id %d)", event.Id, lastMessageId)
+				}
+				lastMessageId = id
+			}
+
+			select {
+			case event := <-events:
+				t.Fatalf("Unexpected extra event %v", event)
+			case <-time.After(100 * time.Millisecond):
+			}
+
+			// the resumed previous client is no longer needed
+			if prev, _ := testApp.SubscriptionsBroker().ClientById(prevClientId); prev != nil && s.expectedMessages[0] != "PB_RESYNC" {
+				t.Fatalf("Expected the previous client %q to be unregistered", prevClientId)
+			}
+		})
+	}
+}
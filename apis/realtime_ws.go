@@ -0,0 +1,392 @@
+package apis
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/tools/list"
+	"github.com/pocketbase/pocketbase/tools/subscriptions"
+	"golang.org/x/net/websocket"
+)
+
+// list with the supported WebSocket realtime frame types
+const (
+	// client -> server
+	realtimeWSTypeSubscribe   = "subscribe"
+	realtimeWSTypeUnsubscribe = "unsubscribe"
+	realtimeWSTypeAuthorize   = "authorize"
+
+	// server -> client
+	realtimeWSTypeMessage = "message"
+	realtimeWSTypeAck     = "ack"
+	realtimeWSTypeError   = "error"
+
+	// both directions
+	realtimeWSTypePing = "ping"
+	realtimeWSTypePong = "pong"
+)
+
+const (
+	// realtimeWSPingInterval is the interval at which the server
+	// sends a "ping" frame to the connected WebSocket client.
+	realtimeWSPingInterval = 30 * time.Second
+
+	// realtimeWSPongWait is the max duration to wait for any client frame
+	// (usually the "pong" reply) before closing the connection.
+	realtimeWSPongWait = 2 * realtimeWSPingInterval
+
+	// realtimeWSWriteWait is the max duration of a single frame write.
+	realtimeWSWriteWait = 10 * time.Second
+
+	// realtimeWSMaxPayloadBytes is the max size of a single client frame.
+	realtimeWSMaxPayloadBytes = 64 << 10
+)
+
+// realtimeWSCommand defines a single client -> server WebSocket frame.
+type realtimeWSCommand struct {
+	// Id is an optional client defined reference that is
+	// returned with the command "ack" or "error" reply.
+	Id            string   `json:"id"`
+	Type          string   `json:"type"`
+	Subscriptions []string `json:"subscriptions"`
+	Token         string   `json:"token"`
+}
+
+// realtimeWSReply defines a single server -> client WebSocket frame.
+type realtimeWSReply struct {
+	Type  string          `json:"type"`
+	Id    string          `json:"id,omitempty"`
+	Name  string          `json:"name,omitempty"`
+	Data  json.RawMessage `json:"data,omitempty"`
+	Error string          `json:"error,omitempty"`
+}
+
+// connectWebSocket establishes a new realtime WebSocket connection.
+//
+// Unlike the SSE connection, the WebSocket client manages its
+// subscriptions and auth state in-band with "subscribe", "unsubscribe"
+// and "authorize" frames (see realtimeWSCommand).
+func (api *realtimeApi) connectWebSocket(c echo.Context) error {
+	if !strings.EqualFold(c.Request().Header.Get("Upgrade"), "websocket") {
+		return NewBadRequestError("Missing or invalid WebSocket upgrade request.", nil)
+	}
+
+	// register new subscription client
+	client := subscriptions.NewDefaultClient()
+	client.Set(ContextAdminKey, c.Get(ContextAdminKey))
+	client.Set(ContextAuthRecordKey, c.Get(ContextAuthRecordKey))
+	api.app.SubscriptionsBroker().Register(client)
+	defer func() {
+		api.app.SubscriptionsBroker().Unregister(client.Id())
+		api.syncPresence()
+	}()
+
+	connectEvent := &core.RealtimeConnectEvent{
+		HttpContext: c,
+		Client:      client,
+	}
+
+	if err := api.app.OnRealtimeConnectRequest().Trigger(connectEvent); err != nil {
+		return err
+	}
+
+	server := websocket.Server{
+		// the realtime auth relies only on the in-band/header auth token
+		// (not cookies) so there is no need to restrict the origin
+		Handshake: func(config *websocket.Config, r *http.Request) error {
+			return nil
+		},
+		Handler: func(ws *websocket.Conn) {
+			api.serveWebSocket(c, client, ws)
+		},
+	}
+
+	server.ServeHTTP(c.Response(), c.Request())
+
+	disconnectEvent := &core.RealtimeDisconnectEvent{
+		HttpContext: c,
+		Client:      client,
+	}
+
+	if err := api.app.OnRealtimeDisconnectRequest().Trigger(disconnectEvent); err != nil && api.app.IsDebug() {
+		log.Println(err)
+	}
+
+	return nil
+}
+
+func (api *realtimeApi) serveWebSocket(c echo.Context, client subscriptions.Client, ws *websocket.Conn) {
+	defer ws.Close()
+
+	ws.MaxPayloadBytes = realtimeWSMaxPayloadBytes
+
+	if api.app.IsDebug() {
+		log.Printf("Realtime WebSocket connection established: %s\n", client.Id())
+	}
+
+	// signalize established connection (aka. fire "connect" message)
+	connectMsg := subscriptions.Message{
+		Name: "PB_CONNECT",
+		Data: `{"clientId":"` + client.Id() + `"}`,
+	}
+	if err := api.sendWebSocketMessage(c, client, ws, &connectMsg); err != nil {
+		if api.app.IsDebug() {
+			log.Println("Realtime WebSocket connection closed (failed to deliver PB_CONNECT):", client.Id(), err)
+		}
+		return
+	}
+
+	commands := make(chan *realtimeWSCommand)
+	readDone := make(chan struct{})
+	stop := make(chan struct{})
+	defer close(stop)
+
+	// read the client frames in a separate goroutine so that
+	// all writes happen sequentially in the main loop
+	go func() {
+		defer close(readDone)
+
+		for {
+			ws.SetReadDeadline(time.Now().Add(realtimeWSPongWait))
+
+			var raw []byte
+			if err := websocket.Message.Receive(ws, &raw); err != nil {
+				return
+			}
+
+			cmd := &realtimeWSCommand{}
+			if err := json.Unmarshal(raw, cmd); err != nil {
+				cmd.Type = "" // handled as unsupported command
+			}
+
+			select {
+			case commands <- cmd:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	pingTicker := time.NewTicker(realtimeWSPingInterval)
+	defer pingTicker.Stop()
+
+	for {
+		select {
+		case <-pingTicker.C:
+			if client.IsDiscarded() {
+				// eg. the client auth record was deleted
+				if api.app.IsDebug() {
+					log.Println("Realtime WebSocket connection closed (discarded client):", client.Id())
+				}
+				return
+			}
+
+			if err := api.writeWebSocket(ws, &realtimeWSReply{Type: realtimeWSTypePing}); err != nil {
+				if api.app.IsDebug() {
+					log.Println("Realtime WebSocket connection closed (failed ping):", client.Id(), err)
+				}
+				return
+			}
+		case cmd := <-commands:
+			if err := api.handleWebSocketCommand(c, client, ws, cmd); err != nil {
+				if api.app.IsDebug() {
+					log.Println("Realtime WebSocket connection closed (failed to reply):", client.Id(), err)
+				}
+				return
+			}
+		case msg, ok := <-client.Channel():
+			if !ok {
+				// channel is closed
+				if api.app.IsDebug() {
+					log.Println("Realtime WebSocket connection closed (closed channel):", client.Id())
+				}
+				return
+			}
+
+			if err := api.sendWebSocketMessage(c, client, ws, &msg); err != nil {
+				if api.app.IsDebug() {
+					log.Println("Realtime WebSocket connection closed (failed to deliver message):", client.Id(), err)
+				}
+				return
+			}
+		case <-readDone:
+			// connection is closed or the client hasn't replied in time
+			if api.app.IsDebug() {
+				log.Println("Realtime WebSocket connection closed (closed by client or timeout):", client.Id())
+			}
+			return
+		}
+	}
+}
+
+// handleWebSocketCommand processes a single client command frame.
+//
+// The returned error is only for failed writes
+// (command errors are sent back to the client).
+func (api *realtimeApi) handleWebSocketCommand(
+	c echo.Context,
+	client subscriptions.Client,
+	ws *websocket.Conn,
+	cmd *realtimeWSCommand,
+) error {
+	var cmdErr error
+
+	switch cmd.Type {
+	case realtimeWSTypePing:
+		return api.writeWebSocket(ws, &realtimeWSReply{Type: realtimeWSTypePong, Id: cmd.Id})
+	case realtimeWSTypePong:
+		return nil // the read deadline is already extended
+	case realtimeWSTypeAuthorize:
+		cmdErr = api.authorizeWebSocketClient(c, client, cmd.Token)
+	case realtimeWSTypeSubscribe, realtimeWSTypeUnsubscribe:
+		cmdErr = api.changeWebSocketSubscriptions(c, client, cmd)
+	default:
+		cmdErr = errors.New("Unsupported or invalid command.")
+	}
+
+	if cmdErr != nil {
+		return api.writeWebSocket(ws, &realtimeWSReply{Type: realtimeWSTypeError, Id: cmd.Id, Error: cmdErr.Error()})
+	}
+
+	return api.writeWebSocket(ws, &realtimeWSReply{Type: realtimeWSTypeAck, Id: cmd.Id})
+}
+
+// authorizeWebSocketClient loads the admin or auth record associated
+// with the provided token into the client and the request context.
+//
+// Similar to the SSE subscriptions, once authorized the client
+// could only be reauthorized with the same admin or auth record
+// (eg. with a refreshed token).
+func (api *realtimeApi) authorizeWebSocketClient(c echo.Context, client subscriptions.Client, token string) error {
+	admin, record := findAuthByToken(api.app, strings.TrimPrefix(token, "Bearer "))
+	if admin == nil && record == nil {
+		return errors.New("Missing or invalid auth token.")
+	}
+
+	var newAuthId string
+	if admin != nil {
+		newAuthId = admin.Id
+	} else {
+		newAuthId = record.Id
+	}
+
+	oldAuthId := extractAuthIdFromGetter(client)
+	if oldAuthId != "" && oldAuthId != newAuthId {
+		return errors.New("The current and the previous authorization don't match.")
+	}
+
+	// update the auth state
+	if admin != nil {
+		c.Set(ContextAdminKey, admin)
+		client.Set(ContextAdminKey, admin)
+	} else {
+		c.Set(ContextAuthRecordKey, record)
+		client.Set(ContextAuthRecordKey, record)
+	}
+
+	api.syncPresence()
+
+	return nil
+}
+
+// changeWebSocketSubscriptions adds or removes the command subscriptions
+// from the client ones.
+//
+// The subscribe hooks are triggered with the full list of the
+// resulting client subscriptions (the same as the SSE subscriptions request).
+func (api *realtimeApi) changeWebSocketSubscriptions(c echo.Context, client subscriptions.Client, cmd *realtimeWSCommand) error {
+	newSubscriptions := []string{}
+
+	if cmd.Type == realtimeWSTypeSubscribe || len(cmd.Subscriptions) > 0 {
+		for sub := range client.Subscriptions() {
+			if cmd.Type == realtimeWSTypeUnsubscribe && list.ExistInSlice(sub, cmd.Subscriptions) {
+				continue
+			}
+			newSubscriptions = append(newSubscriptions, sub)
+		}
+	}
+
+	if cmd.Type == realtimeWSTypeSubscribe {
+		newSubscriptions = list.NonzeroUniques(append(newSubscriptions, cmd.Subscriptions...))
+	}
+
+	form := forms.NewRealtimeSubscribe()
+	form.ClientId = client.Id()
+	form.Subscriptions = newSubscriptions
+	if err := form.Validate(); err != nil {
+		return err
+	}
+
+	event := &core.RealtimeSubscribeEvent{
+		HttpContext:   c,
+		Client:        client,
+		Subscriptions: form.Subscriptions,
+	}
+
+	handlerErr := api.app.OnRealtimeBeforeSubscribeRequest().Trigger(event, func(e *core.RealtimeSubscribeEvent) error {
+		// replace any previous existing subscriptions
+		e.Client.Unsubscribe()
+		e.Client.Subscribe(e.Subscriptions...)
+
+		api.syncPresence()
+
+		return nil
+	})
+
+	if handlerErr == nil {
+		api.app.OnRealtimeAfterSubscribeRequest().Trigger(event)
+	}
+
+	return handlerErr
+}
+
+// sendWebSocketMessage sends a single realtime message to the client
+// wrapped with the OnRealtimeBeforeMessageSend and OnRealtimeAfterMessageSend hooks.
+func (api *realtimeApi) sendWebSocketMessage(
+	c echo.Context,
+	client subscriptions.Client,
+	ws *websocket.Conn,
+	msg *subscriptions.Message,
+) error {
+	msgEvent := &core.RealtimeMessageEvent{
+		HttpContext: c,
+		Client:      client,
+		Message:     msg,
+	}
+
+	msgErr := api.app.OnRealtimeBeforeMessageSend().Trigger(msgEvent, func(e *core.RealtimeMessageEvent) error {
+		data := json.RawMessage(e.Message.Data)
+		if !json.Valid(data) {
+			// send the non-json message data as plain string
+			data, _ = json.Marshal(e.Message.Data)
+		}
+
+		return api.writeWebSocket(ws, &realtimeWSReply{
+			Type: realtimeWSTypeMessage,
+			Name: e.Message.Name,
+			Data: data,
+		})
+	})
+	if msgErr != nil {
+		return msgErr
+	}
+
+	if err := api.app.OnRealtimeAfterMessageSend().Trigger(msgEvent); err != nil && api.app.IsDebug() {
+		log.Println("OnRealtimeAfterMessageSend error:", err)
+	}
+
+	return nil
+}
+
+func (api *realtimeApi) writeWebSocket(ws *websocket.Conn, reply *realtimeWSReply) error {
+	ws.SetWriteDeadline(time.Now().Add(realtimeWSWriteWait))
+
+	return websocket.JSON.Send(ws, reply)
+}
@@ -0,0 +1,245 @@
+package apis_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/subscriptions"
+	"golang.org/x/net/websocket"
+)
+
+type testWSReply struct {
+	Type  string          `json:"type"`
+	Id    string          `json:"id"`
+	Name  string          `json:"name"`
+	Data  json.RawMessage `json:"data"`
+	Error string          `json:"error"`
+}
+
+func TestRealtimeWebSocketMissingUpgrade(t *testing.T) {
+	scenario := tests.ApiScenario{
+		Method:          http.MethodGet,
+		Url:             "/api/realtime/ws",
+		ExpectedStatus:  400,
+		ExpectedContent: []string{`"data":{}`},
+	}
+
+	scenario.Test(t)
+}
+
+func TestRealtimeWebSocket(t *testing.T) {
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	e, err := apis.InitApi(testApp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var connectCalls, subscribeCalls int32
+	testApp.OnRealtimeConnectRequest().Add(func(e *core.RealtimeConnectEvent) error {
+		atomic.AddInt32(&connectCalls, 1)
+		return nil
+	})
+	testApp.OnRealtimeAfterSubscribeRequest().Add(func(e *core.RealtimeSubscribeEvent) error {
+		atomic.AddInt32(&subscribeCalls, 1)
+		return nil
+	})
+
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	config, err := websocket.NewConfig(
+		"ws"+strings.TrimPrefix(server.URL, "http")+"/api/realtime/ws",
+		server.URL,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ws, err := websocket.DialConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	send := func(cmd string) {
+		if err := websocket.Message.Send(ws, cmd); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	receive := func(expectedType string, expectedId string) *testWSReply {
+		ws.SetReadDeadline(time.Now().Add(3 * time.Second))
+
+		reply := &testWSReply{}
+		if err := websocket.JSON.Receive(ws, reply); err != nil {
+			t.Fatal(err)
+		}
+
+		if reply.Type != expectedType || reply.Id != expectedId {
+			t.Fatalf("Expected %q reply with id %q, got %v", expectedType, expectedId, reply)
+		}
+
+		return reply
+	}
+
+	// connect message
+	connectMsg := receive("message", "")
+	if connectMsg.Name != "PB_CONNECT" {
+		t.Fatalf("Expected PB_CONNECT message, got %q", connectMsg.Name)
+	}
+	connectData := map[string]string{}
+	if err := json.Unmarshal(connectMsg.Data, &connectData); err != nil {
+		t.Fatal(err)
+	}
+
+	var client subscriptions.Client
+	client, err = testApp.SubscriptionsBroker().ClientById(connectData["clientId"])
+	if err != nil {
+		t.Fatalf("Expected the client %q to be registered: %v", connectData["clientId"], err)
+	}
+	if total := atomic.LoadInt32(&connectCalls); total != 1 {
+		t.Fatalf("Expected OnRealtimeConnectRequest to be called once, got %d", total)
+	}
+
+	// keepalive
+	send(`{"id":"p1","type":"ping"}`)
+	receive("pong", "p1")
+
+	// invalid commands
+	send(`invalid`)
+	receive("error", "")
+	send(`{"id":"u1","type":"unknown"}`)
+	receive("error", "u1")
+
+	// subscribe
+	send(`{"id":"s1","type":"subscribe","subscriptions":["demo2/*","demo2/0yxhwia2amd8gec"]}`)
+	receive("ack", "s1")
+	send(`{"id":"s2","type":"subscribe","subscriptions":["demo1/*"]}`)
+	receive("ack", "s2")
+	if total := len(client.Subscriptions()); total != 3 {
+		t.Fatalf("Expected 3 subscriptions, got %d", total)
+	}
+
+	// record change message
+	record, err := testApp.Dao().FindRecordById("demo2", "0yxhwia2amd8gec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	record.Set("title", "ws_update")
+	if err := testApp.Dao().SaveRecord(record); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		msg := receive("message", "")
+		if msg.Name != "demo2/*" && msg.Name != "demo2/0yxhwia2amd8gec" {
+			t.Fatalf("Unexpected message %q", msg.Name)
+		}
+		if !strings.Contains(string(msg.Data), `"action":"update"`) ||
+			!strings.Contains(string(msg.Data), `"title":"ws_update"`) {
+			t.Fatalf("Unexpected message data %s", msg.Data)
+		}
+	}
+
+	// unsubscribe
+	send(`{"id":"us1","type":"unsubscribe","subscriptions":["demo2/*"]}`)
+	receive("ack", "us1")
+	if client.HasSubscription("demo2/*") || !client.HasSubscription("demo1/*") {
+		t.Fatalf("Expected only demo2/* to be unsubscribed, got %v", client.Subscriptions())
+	}
+	send(`{"id":"us2","type":"unsubscribe"}`)
+	receive("ack", "us2")
+	if total := len(client.Subscriptions()); total != 0 {
+		t.Fatalf("Expected no subscriptions, got %d", total)
+	}
+	if total := atomic.LoadInt32(&subscribeCalls); total != 4 {
+		t.Fatalf("Expected OnRealtimeAfterSubscribeRequest to be called 4 times, got %d", total)
+	}
+
+	// authorize
+	send(`{"id":"a1","type":"authorize","token":"invalid"}`)
+	receive("error", "a1")
+	send(`{"id":"a2","type":"authorize","token":"` + testAdminToken + `"}`)
+	receive("ack", "a2")
+	if admin, _ := client.Get(apis.ContextAdminKey).(*models.Admin); admin == nil || admin.Id != "sywbhecnh46rhm0" {
+		t.Fatalf("Expected the client to be authorized as admin sywbhecnh46rhm0, got %v", admin)
+	}
+	send(`{"id":"a3","type":"authorize","token":"` + testRecordToken + `"}`)
+	receive("error", "a3")
+
+	// disconnect
+	ws.Close()
+	waitForNoRealtimeClients(t, testApp)
+}
+
+func TestRealtimeWebSocketWithAuthHeader(t *testing.T) {
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	e, err := apis.InitApi(testApp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	config, err := websocket.NewConfig(
+		"ws"+strings.TrimPrefix(server.URL, "http")+"/api/realtime/ws",
+		server.URL,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Header.Set("Authorization", testRecordToken)
+
+	ws, err := websocket.DialConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	ws.SetReadDeadline(time.Now().Add(3 * time.Second))
+	reply := &testWSReply{}
+	if err := websocket.JSON.Receive(ws, reply); err != nil {
+		t.Fatal(err)
+	}
+
+	connectData := map[string]string{}
+	if err := json.Unmarshal(reply.Data, &connectData); err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := testApp.SubscriptionsBroker().ClientById(connectData["clientId"])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if record, _ := client.Get(apis.ContextAuthRecordKey).(*models.Record); record == nil || record.Id != "4q1xlclmfloku33" {
+		t.Fatalf("Expected the client to be authorized as auth record 4q1xlclmfloku33, got %v", record)
+	}
+
+	ws.Close()
+	waitForNoRealtimeClients(t, testApp)
+}
+
+func waitForNoRealtimeClients(t *testing.T, app *tests.TestApp) {
+	for i := 0; i < 50; i++ {
+		if len(app.SubscriptionsBroker().Clients()) == 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("Expected all realtime clients to be unregistered, found %d", len(app.SubscriptionsBroker().Clients()))
+}
@@ -0,0 +1,139 @@
+package apis
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/resolvers"
+	"github.com/pocketbase/pocketbase/tools/list"
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+// aggregateForbiddenFields lists the auth record fields that
+// could be grouped or aggregated only by admins.
+var aggregateForbiddenFields = []string{
+	schema.FieldNameEmail,
+	schema.FieldNameTokenKey,
+	schema.FieldNamePasswordHash,
+	schema.FieldNameLastResetSentAt,
+	schema.FieldNameLastVerificationSentAt,
+}
+
+func (api *recordApi) aggregate(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("", "Missing collection context.")
+	}
+
+	// forbid users and guests to query special filter/sort fields
+	if err := api.checkForForbiddenQueryFields(c); err != nil {
+		return err
+	}
+
+	requestData := RequestData(c)
+
+	if err := checkAdminRecordsAccess(requestData, collection, false); err != nil {
+		return err
+	}
+
+	if requestData.Admin == nil && collection.ListRule == nil {
+		// only admins can access if the rule is nil
+		return NewForbiddenError("Only admins can perform this action.", nil)
+	}
+
+	fieldsResolver := resolvers.NewRecordFieldResolver(
+		api.app.Dao(),
+		collection,
+		requestData,
+		// hidden fields are searchable only by admins
+		requestData.Admin != nil,
+	)
+
+	aggregateProvider := search.NewAggregateProvider(fieldsResolver).
+		Query(api.app.Dao().RecordQuery(collection))
+
+	if requestData.Admin == nil && collection.ListRule != nil {
+		aggregateProvider.AddFilter(search.FilterData(*collection.ListRule))
+	}
+
+	if err := aggregateProvider.Parse(c.QueryParams().Encode()); err != nil {
+		return NewBadRequestError("Invalid aggregate parameters.", err)
+	}
+
+	if err := checkAggregateFields(c, collection, requestData.Admin != nil); err != nil {
+		return NewBadRequestError("Invalid aggregate parameters.", err)
+	}
+
+	result, err := aggregateProvider.Exec()
+	if err != nil {
+		return NewBadRequestError("Invalid aggregate parameters.", err)
+	}
+
+	event := new(core.RecordsAggregateEvent)
+	event.HttpContext = c
+	event.Collection = collection
+	event.Result = result
+
+	return api.app.OnRecordsAggregateRequest().Trigger(event, func(e *core.RecordsAggregateEvent) error {
+		return e.HttpContext.JSON(http.StatusOK, e.Result)
+	})
+}
+
+// checkAggregateFields checks whether the requested group by and
+// aggregate fields could be used with the provided collection.
+//
+// Date buckets are allowed only for the date fields of the collection and
+// the auth records sensitive fields (eg. email) are allowed only for admins.
+func checkAggregateFields(c echo.Context, collection *models.Collection, isAdmin bool) error {
+	names := []string{}
+
+	for _, field := range search.ParseGroupByFromString(c.QueryParam(search.GroupByQueryParam)) {
+		names = append(names, field.Name)
+
+		if field.Bucket != "" && !isDateField(collection, field.Name) {
+			return fmt.Errorf("%q is not a date field and cannot be grouped by %q", field.Name, field.Bucket)
+		}
+	}
+
+	for _, fn := range search.AggregateFuncs() {
+		names = append(names, strings.Split(c.QueryParam(fn), ",")...)
+	}
+
+	if isAdmin {
+		return nil
+	}
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+
+		if strings.HasPrefix(name, "@") {
+			return fmt.Errorf("%q cannot be grouped or aggregated", name)
+		}
+
+		// (the relation fields are checked regardless of their collection type)
+		parts := strings.Split(name, ".")
+		if (len(parts) > 1 || collection.IsAuth()) &&
+			list.ExistInSlice(parts[len(parts)-1], aggregateForbiddenFields) {
+			return fmt.Errorf("%q can be grouped or aggregated only by admins", name)
+		}
+	}
+
+	return nil
+}
+
+// isDateField checks whether the field with the specified name
+// is a system or schema date field of the provided collection.
+func isDateField(collection *models.Collection, name string) bool {
+	if name == schema.FieldNameCreated || name == schema.FieldNameUpdated {
+		return true
+	}
+
+	field := collection.Schema.GetFieldByName(name)
+
+	return field != nil && field.Type == schema.FieldTypeDate
+}
@@ -32,17 +32,24 @@ func bindRecordAuthApi(app core.App, rg *echo.Group) {
 	)
 
 	subGroup.GET("/auth-methods", api.authMethods)
-	subGroup.POST("/auth-refresh", api.authRefresh, RequireSameContextRecordAuth())
-	subGroup.POST("/auth-with-oauth2", api.authWithOAuth2)
-	subGroup.POST("/auth-with-password", api.authWithPassword)
-	subGroup.POST("/request-password-reset", api.requestPasswordReset)
-	subGroup.POST("/confirm-password-reset", api.confirmPasswordReset)
-	subGroup.POST("/request-verification", api.requestVerification)
-	subGroup.POST("/confirm-verification", api.confirmVerification)
-	subGroup.POST("/request-email-change", api.requestEmailChange, RequireSameContextRecordAuth())
-	subGroup.POST("/confirm-email-change", api.confirmEmailChange)
+	subGroup.POST("/auth-refresh", api.authRefresh, RequireSameContextRecordAuth(), CollectionRateLimit(app, "authRefresh"))
+	subGroup.POST("/auth-with-oauth2", api.authWithOAuth2, CollectionRateLimit(app, "auth"))
+	subGroup.POST("/auth-with-password", api.authWithPassword, CollectionRateLimit(app, "auth"))
+	subGroup.POST("/auth-with-mfa", api.authWithMfa, CollectionRateLimit(app, "auth"))
+	subGroup.POST("/request-otp", api.requestOTP, CollectionRateLimit(app, "requestOTP"))
+	subGroup.POST("/auth-with-otp", api.authWithOTP, CollectionRateLimit(app, "auth"))
+	subGroup.POST("/mfa/setup", api.mfaSetup, RequireSameContextRecordAuth())
+	subGroup.POST("/mfa/confirm", api.mfaConfirm, RequireSameContextRecordAuth(), CollectionRateLimit(app, "auth"))
+	subGroup.POST("/mfa/disable", api.mfaDisable, RequireSameContextRecordAuth(), CollectionRateLimit(app, "auth"))
+	subGroup.POST("/request-password-reset", api.requestPasswordReset, CollectionRateLimit(app, "requestPasswordReset"))
+	subGroup.POST("/confirm-password-reset", api.confirmPasswordReset, CollectionRateLimit(app, "confirmPasswordReset"))
+	subGroup.POST("/request-verification", api.requestVerification, CollectionRateLimit(app, "requestVerification"))
+	subGroup.POST("/confirm-verification", api.confirmVerification, CollectionRateLimit(app, "confirmVerification"))
+	subGroup.POST("/request-email-change", api.requestEmailChange, RequireSameContextRecordAuth(), CollectionRateLimit(app, "requestEmailChange"))
+	subGroup.POST("/confirm-email-change", api.confirmEmailChange, CollectionRateLimit(app, "confirmEmailChange"))
 	subGroup.GET("/records/:id/external-auths", api.listExternalAuths, RequireAdminOrOwnerAuth("id"))
 	subGroup.DELETE("/records/:id/external-auths/:provider", api.unlinkExternalAuth, RequireAdminOrOwnerAuth("id"))
+	subGroup.DELETE("/records/:id/mfa", api.resetMfa, RequireAdminAuth())
 }
 
 type recordAuthApi struct {
@@ -93,10 +100,12 @@ func (api *recordAuthApi) authMethods(c echo.Context) error {
 	result := struct {
 		UsernamePassword bool           `json:"usernamePassword"`
 		EmailPassword    bool           `json:"emailPassword"`
+		OTP              bool           `json:"otp"`
 		AuthProviders    []providerInfo `json:"authProviders"`
 	}{
 		UsernamePassword: authOptions.AllowUsernameAuth,
 		EmailPassword:    authOptions.AllowEmailAuth,
+		OTP:              authOptions.AllowOTPAuth,
 		AuthProviders:    []providerInfo{},
 	}
 
@@ -224,7 +233,7 @@ func (api *recordAuthApi) authWithOAuth2(c echo.Context) error {
 				e.Record = data.Record
 				e.OAuth2User = data.OAuth2User
 
-				return RecordAuthResponse(api.app, e.HttpContext, e.Record, e.OAuth2User)
+				return recordAuthOrMfaResponse(api.app, e.HttpContext, e.Record, e.OAuth2User)
 			})
 		}
 	})
@@ -264,7 +273,7 @@ func (api *recordAuthApi) authWithPassword(c echo.Context) error {
 					return NewBadRequestError("Failed to authenticate.", err)
 				}
 
-				return RecordAuthResponse(api.app, e.HttpContext, e.Record, nil)
+				return recordAuthOrMfaResponse(api.app, e.HttpContext, e.Record, nil)
 			})
 		}
 	})
@@ -615,3 +624,246 @@ func (api *recordAuthApi) unlinkExternalAuth(c echo.Context) error {
 
 	return handlerErr
 }
+
+func (api *recordAuthApi) authWithMfa(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("Missing collection context.", nil)
+	}
+
+	if !collection.AuthOptions().AllowMfa {
+		return NewBadRequestError("The collection is not configured to allow MFA.", nil)
+	}
+
+	form := forms.NewRecordMfaLogin(api.app, collection)
+	if readErr := c.Bind(form); readErr != nil {
+		return NewBadRequestError("An error occurred while loading the submitted data.", readErr)
+	}
+
+	event := new(core.RecordAuthWithMfaEvent)
+	event.HttpContext = c
+	event.Collection = collection
+	event.Code = form.Code
+
+	_, submitErr := form.Submit(func(next forms.InterceptorNextFunc[*forms.RecordMfaLoginData]) forms.InterceptorNextFunc[*forms.RecordMfaLoginData] {
+		return func(data *forms.RecordMfaLoginData) error {
+			event.Record = data.Record
+			event.Mfa = data.Mfa
+
+			return api.app.OnRecordBeforeAuthWithMfaRequest().Trigger(event, func(e *core.RecordAuthWithMfaEvent) error {
+				data.Record = e.Record
+				data.Mfa = e.Mfa
+
+				if err := next(data); err != nil {
+					return NewBadRequestError("Failed to authenticate.", err)
+				}
+
+				return RecordAuthResponse(api.app, e.HttpContext, e.Record, nil)
+			})
+		}
+	})
+
+	if submitErr == nil {
+		if err := api.app.OnRecordAfterAuthWithMfaRequest().Trigger(event); err != nil && api.app.IsDebug() {
+			log.Println(err)
+		}
+	}
+
+	return submitErr
+}
+
+func (api *recordAuthApi) requestOTP(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("Missing collection context.", nil)
+	}
+
+	if !collection.AuthOptions().AllowOTPAuth {
+		return NewBadRequestError("The collection is not configured to allow OTP authentication.", nil)
+	}
+
+	form := forms.NewRecordOTPRequest(api.app, collection)
+	if err := c.Bind(form); err != nil {
+		return NewBadRequestError("An error occurred while loading the submitted data.", err)
+	}
+
+	if err := form.Validate(); err != nil {
+		return NewBadRequestError("An error occurred while validating the form.", err)
+	}
+
+	event := new(core.RecordRequestOTPEvent)
+	event.HttpContext = c
+	event.Collection = collection
+
+	_, submitErr := form.Submit(func(next forms.InterceptorNextFunc[*forms.RecordOTPRequestData]) forms.InterceptorNextFunc[*forms.RecordOTPRequestData] {
+		return func(data *forms.RecordOTPRequestData) error {
+			event.Record = data.Record
+			event.OTP = data.OTP
+
+			return api.app.OnRecordBeforeRequestOTPRequest().Trigger(event, func(e *core.RecordRequestOTPEvent) error {
+				data.Record = e.Record
+				data.OTP = e.OTP
+
+				// run in background because we don't need to show the result to the client
+				routine.FireAndForget(func() {
+					if err := next(data); err != nil && api.app.IsDebug() {
+						log.Println(err)
+					}
+				})
+
+				return e.HttpContext.JSON(http.StatusOK, map[string]string{"otpId": e.OTP.Id})
+			})
+		}
+	})
+
+	if submitErr == nil {
+		if err := api.app.OnRecordAfterRequestOTPRequest().Trigger(event); err != nil && api.app.IsDebug() {
+			log.Println(err)
+		}
+	} else if api.app.IsDebug() {
+		log.Println(submitErr)
+	}
+
+	// don't return the response error to prevent emails enumeration
+	// (returns a random otp id that can't be used for authentication)
+	if !c.Response().Committed {
+		c.JSON(http.StatusOK, map[string]string{
+			"otpId": security.RandomStringWithAlphabet(models.DefaultIdLength, models.DefaultIdAlphabet),
+		})
+	}
+
+	return nil
+}
+
+func (api *recordAuthApi) authWithOTP(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("Missing collection context.", nil)
+	}
+
+	if !collection.AuthOptions().AllowOTPAuth {
+		return NewBadRequestError("The collection is not configured to allow OTP authentication.", nil)
+	}
+
+	form := forms.NewRecordOTPLogin(api.app, collection)
+	if readErr := c.Bind(form); readErr != nil {
+		return NewBadRequestError("An error occurred while loading the submitted data.", readErr)
+	}
+
+	event := new(core.RecordAuthWithOTPEvent)
+	event.HttpContext = c
+	event.Collection = collection
+
+	_, submitErr := form.Submit(func(next forms.InterceptorNextFunc[*forms.RecordOTPLoginData]) forms.InterceptorNextFunc[*forms.RecordOTPLoginData] {
+		return func(data *forms.RecordOTPLoginData) error {
+			event.Record = data.Record
+			event.OTP = data.OTP
+
+			return api.app.OnRecordBeforeAuthWithOTPRequest().Trigger(event, func(e *core.RecordAuthWithOTPEvent) error {
+				data.Record = e.Record
+				data.OTP = e.OTP
+
+				if err := next(data); err != nil {
+					return NewBadRequestError("Failed to authenticate.", err)
+				}
+
+				return recordAuthOrMfaResponse(api.app, e.HttpContext, e.Record, nil)
+			})
+		}
+	})
+
+	if submitErr == nil {
+		if err := api.app.OnRecordAfterAuthWithOTPRequest().Trigger(event); err != nil && api.app.IsDebug() {
+			log.Println(err)
+		}
+	}
+
+	return submitErr
+}
+
+func (api *recordAuthApi) mfaSetup(c echo.Context) error {
+	record, _ := c.Get(ContextAuthRecordKey).(*models.Record)
+	if record == nil {
+		return NewNotFoundError("Missing auth record context.", nil)
+	}
+
+	form := forms.NewRecordMfaSetup(api.app, record)
+
+	result, err := form.Submit()
+	if err != nil {
+		return NewBadRequestError("Failed to setup MFA.", err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"secret": result.Secret,
+		"url":    result.AuthUrl,
+	})
+}
+
+func (api *recordAuthApi) mfaConfirm(c echo.Context) error {
+	record, _ := c.Get(ContextAuthRecordKey).(*models.Record)
+	if record == nil {
+		return NewNotFoundError("Missing auth record context.", nil)
+	}
+
+	form := forms.NewRecordMfaConfirm(api.app, record)
+	if readErr := c.Bind(form); readErr != nil {
+		return NewBadRequestError("An error occurred while loading the submitted data.", readErr)
+	}
+
+	recoveryCodes, err := form.Submit()
+	if err != nil {
+		return NewBadRequestError("Failed to confirm MFA.", err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"recoveryCodes": recoveryCodes,
+	})
+}
+
+func (api *recordAuthApi) mfaDisable(c echo.Context) error {
+	record, _ := c.Get(ContextAuthRecordKey).(*models.Record)
+	if record == nil {
+		return NewNotFoundError("Missing auth record context.", nil)
+	}
+
+	form := forms.NewRecordMfaDisable(api.app, record)
+	if readErr := c.Bind(form); readErr != nil {
+		return NewBadRequestError("An error occurred while loading the submitted data.", readErr)
+	}
+
+	if err := form.Submit(); err != nil {
+		return NewBadRequestError("Failed to disable MFA.", err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// resetMfa deletes the mfa of the specified auth record without
+// code verification (eg. in case the user has lost its device and recovery codes).
+func (api *recordAuthApi) resetMfa(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("Missing collection context.", nil)
+	}
+
+	if err := checkAdminRecordsAccess(RequestData(c), collection, true); err != nil {
+		return err
+	}
+
+	record, err := api.app.Dao().FindRecordById(collection.Id, c.PathParam("id"))
+	if err != nil || record == nil {
+		return NewNotFoundError("", err)
+	}
+
+	mfa, err := api.app.Dao().FindMfaByRecord(record)
+	if err != nil {
+		return NewNotFoundError("Missing auth record MFA.", err)
+	}
+
+	if err := api.app.Dao().DeleteMfa(mfa); err != nil {
+		return NewBadRequestError("Failed to reset the auth record MFA.", err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -1,6 +1,7 @@
 package apis_test
 
 import (
+	"io"
 	"net/http"
 	"strings"
 	"testing"
@@ -8,7 +9,11 @@ import (
 
 	"github.com/labstack/echo/v5"
 	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
 	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tokens"
+	"github.com/pocketbase/pocketbase/tools/security"
 	"github.com/pocketbase/pocketbase/tools/types"
 )
 
@@ -36,6 +41,7 @@ func TestRecordAuthMethodsList(t *testing.T) {
 			ExpectedContent: []string{
 				`"usernamePassword":true`,
 				`"emailPassword":true`,
+				`"otp":false`,
 				`"authProviders":[{`,
 				`"name":"gitlab"`,
 				`"state":`,
@@ -54,6 +60,7 @@ func TestRecordAuthMethodsList(t *testing.T) {
 			ExpectedContent: []string{
 				`"usernamePassword":false`,
 				`"emailPassword":true`,
+				`"otp":false`,
 				`"authProviders":[]`,
 			},
 		},
@@ -1144,3 +1151,738 @@ func TestRecordAuthUnlinkExternalsAuth(t *testing.T) {
 		scenario.Test(t)
 	}
 }
+
+func TestRecordAuthWithPasswordAndMfa(t *testing.T) {
+	setTestMfaEncryptionEnv(t)
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:   "enabled mfa but not allowed by the collection",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/auth-with-password",
+			Body: strings.NewReader(`{
+				"identity":"test@example.com",
+				"password":"1234567890"
+			}`),
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				record, _ := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+				enableTestRecordMfa(t, app, record)
+
+				options := record.Collection().AuthOptions()
+				options.AllowMfa = false
+				record.Collection().SetOptions(options)
+				if err := app.Dao().SaveCollection(record.Collection()); err != nil {
+					t.Fatal(err)
+				}
+
+				app.ResetEventCalls()
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"record":{`,
+				`"token":"`,
+			},
+			NotExpectedContent: []string{
+				`"mfaToken"`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnRecordBeforeAuthWithPasswordRequest": 1,
+				"OnRecordAfterAuthWithPasswordRequest":  1,
+				"OnRecordAuthRequest":                   1,
+			},
+		},
+		{
+			Name:   "enabled mfa",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/auth-with-password",
+			Body: strings.NewReader(`{
+				"identity":"test@example.com",
+				"password":"1234567890"
+			}`),
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				record, _ := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+				enableTestRecordMfa(t, app, record)
+				app.ResetEventCalls()
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"mfaRequired":true`,
+				`"mfaToken":"`,
+			},
+			NotExpectedContent: []string{
+				`"record":`,
+				`"token":`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnRecordBeforeAuthWithPasswordRequest": 1,
+				"OnRecordAfterAuthWithPasswordRequest":  1,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestRecordAuthWithMfa(t *testing.T) {
+	setTestMfaEncryptionEnv(t)
+
+	// shared between the BeforeTestFunc and the lazy request bodies
+	var secret, mfaToken string
+	var recoveryCodes []string
+
+	beforeTestFunc := func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+		record, _ := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+		secret, recoveryCodes = enableTestRecordMfa(t, app, record)
+		mfaToken, _ = tokens.NewRecordMfaToken(app, record)
+		app.ResetEventCalls()
+	}
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "collection without allowed mfa",
+			Method:          http.MethodPost,
+			Url:             "/api/collections/clients/auth-with-mfa",
+			Body:            strings.NewReader(`{"mfaToken":"test","code":"123456"}`),
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:           "empty data",
+			Method:         http.MethodPost,
+			Url:            "/api/collections/users/auth-with-mfa",
+			Body:           strings.NewReader(`{}`),
+			BeforeTestFunc: beforeTestFunc,
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"data":{`,
+				`"mfaToken":{"code":"validation_required"`,
+				`"code":{"code":"validation_required"`,
+			},
+		},
+		{
+			Name:   "regular auth token",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/auth-with-mfa",
+			Body: lazyBody(func() string {
+				code, _ := security.TOTPCode(secret, time.Now())
+				return `{"mfaToken":"` + testRecordToken + `","code":"` + code + `"}`
+			}),
+			BeforeTestFunc: beforeTestFunc,
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"data":{`,
+				`"mfaToken":{"code":"validation_invalid_token"`,
+			},
+		},
+		{
+			Name:            "invalid code",
+			Method:          http.MethodPost,
+			Url:             "/api/collections/users/auth-with-mfa",
+			Body:            lazyBody(func() string { return `{"mfaToken":"` + mfaToken + `","code":"000000"}` }),
+			BeforeTestFunc:  beforeTestFunc,
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents: map[string]int{
+				"OnRecordBeforeAuthWithMfaRequest": 1,
+			},
+		},
+		{
+			Name:   "valid totp code",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/auth-with-mfa",
+			Body: lazyBody(func() string {
+				code, _ := security.TOTPCode(secret, time.Now().Add(security.TOTPPeriod))
+				return `{"mfaToken":"` + mfaToken + `","code":"` + code + `"}`
+			}),
+			BeforeTestFunc: beforeTestFunc,
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"record":{`,
+				`"token":"`,
+				`"id":"4q1xlclmfloku33"`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnRecordBeforeAuthWithMfaRequest": 1,
+				"OnRecordAfterAuthWithMfaRequest":  1,
+				"OnRecordAuthRequest":              1,
+			},
+		},
+		{
+			Name:   "valid recovery code",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/auth-with-mfa",
+			Body: lazyBody(func() string {
+				return `{"mfaToken":"` + mfaToken + `","code":"` + recoveryCodes[0] + `"}`
+			}),
+			BeforeTestFunc: beforeTestFunc,
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"record":{`,
+				`"token":"`,
+				`"id":"4q1xlclmfloku33"`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnRecordBeforeAuthWithMfaRequest": 1,
+				"OnRecordAfterAuthWithMfaRequest":  1,
+				"OnRecordAuthRequest":              1,
+			},
+			AfterTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				record, _ := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+				mfa, _ := app.Dao().FindMfaByRecord(record)
+				if mfa == nil || len(mfa.RecoveryCodes) != forms.MfaRecoveryCodesCount-1 {
+					t.Fatalf("Expected the used recovery code to be removed, got %v", mfa)
+				}
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestRecordAuthMfaSetupAndConfirm(t *testing.T) {
+	setTestMfaEncryptionEnv(t)
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "setup as guest",
+			Method:          http.MethodPost,
+			Url:             "/api/collections/users/mfa/setup",
+			ExpectedStatus:  401,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "setup with collection without allowed mfa",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/mfa/setup",
+			RequestHeaders: map[string]string{
+				"Authorization": testRecordToken,
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "setup with allowed mfa",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/mfa/setup",
+			RequestHeaders: map[string]string{
+				"Authorization": testRecordToken,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				record, _ := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+				allowTestCollectionMfa(t, app, record.Collection())
+				app.ResetEventCalls()
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"secret":"`,
+				`"url":"otpauth://totp/`,
+			},
+			ExpectedEvents: map[string]int{
+				// the mfa model + the lazily generated encryption key param
+				"OnModelBeforeCreate": 2,
+				"OnModelAfterCreate":  2,
+			},
+		},
+		{
+			Name:   "confirm without setup",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/mfa/confirm",
+			Body:   strings.NewReader(`{"code":"123456"}`),
+			RequestHeaders: map[string]string{
+				"Authorization": testRecordToken,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				record, _ := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+				allowTestCollectionMfa(t, app, record.Collection())
+				app.ResetEventCalls()
+			},
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"data":{`,
+				`"code":{"code":"validation_missing_mfa_setup"`,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+
+	// confirm after setup
+	var secret string
+	confirmScenario := tests.ApiScenario{
+		Name:   "confirm with valid code",
+		Method: http.MethodPost,
+		Url:    "/api/collections/users/mfa/confirm",
+		Body: lazyBody(func() string {
+			code, _ := security.TOTPCode(secret, time.Now())
+			return `{"code":"` + code + `"}`
+		}),
+		RequestHeaders: map[string]string{
+			"Authorization": testRecordToken,
+		},
+		BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+			record, _ := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+			allowTestCollectionMfa(t, app, record.Collection())
+
+			result, err := forms.NewRecordMfaSetup(app, record).Submit()
+			if err != nil {
+				t.Fatal(err)
+			}
+			secret = result.Secret
+
+			app.ResetEventCalls()
+		},
+		ExpectedStatus: 200,
+		ExpectedContent: []string{
+			`"recoveryCodes":["`,
+		},
+		ExpectedEvents: map[string]int{
+			"OnModelBeforeUpdate": 1,
+			"OnModelAfterUpdate":  1,
+		},
+	}
+	confirmScenario.Test(t)
+}
+
+func TestRecordAuthMfaDisable(t *testing.T) {
+	setTestMfaEncryptionEnv(t)
+
+	var secret string
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "guest",
+			Method:          http.MethodPost,
+			Url:             "/api/collections/users/mfa/disable",
+			Body:            strings.NewReader(`{"code":"123456"}`),
+			ExpectedStatus:  401,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "invalid code",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/mfa/disable",
+			Body:   strings.NewReader(`{"code":"invalid"}`),
+			RequestHeaders: map[string]string{
+				"Authorization": testRecordToken,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				record, _ := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+				enableTestRecordMfa(t, app, record)
+				app.ResetEventCalls()
+			},
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"data":{`,
+				`"code":{"code":"validation_invalid_mfa_code"`,
+			},
+		},
+		{
+			Name:   "valid code",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/mfa/disable",
+			Body: lazyBody(func() string {
+				code, _ := security.TOTPCode(secret, time.Now().Add(security.TOTPPeriod))
+				return `{"code":"` + code + `"}`
+			}),
+			RequestHeaders: map[string]string{
+				"Authorization": testRecordToken,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				record, _ := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+				secret, _ = enableTestRecordMfa(t, app, record)
+				app.ResetEventCalls()
+			},
+			ExpectedStatus: 204,
+			ExpectedEvents: map[string]int{
+				"OnModelBeforeDelete": 1,
+				"OnModelAfterDelete":  1,
+			},
+			AfterTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				record, _ := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+				if _, err := app.Dao().FindMfaByRecord(record); err == nil {
+					t.Fatal("Expected the record mfa to be deleted")
+				}
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestRecordAuthResetMfa(t *testing.T) {
+	setTestMfaEncryptionEnv(t)
+
+	beforeTestFunc := func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+		record, _ := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+		enableTestRecordMfa(t, app, record)
+		app.ResetEventCalls()
+	}
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "guest",
+			Method:          http.MethodDelete,
+			Url:             "/api/collections/users/records/4q1xlclmfloku33/mfa",
+			BeforeTestFunc:  beforeTestFunc,
+			ExpectedStatus:  401,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "owner",
+			Method: http.MethodDelete,
+			Url:    "/api/collections/users/records/4q1xlclmfloku33/mfa",
+			RequestHeaders: map[string]string{
+				"Authorization": testRecordToken,
+			},
+			BeforeTestFunc:  beforeTestFunc,
+			ExpectedStatus:  401,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "admin with record without mfa",
+			Method: http.MethodDelete,
+			Url:    "/api/collections/users/records/oap640cot4yru2s/mfa",
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			BeforeTestFunc:  beforeTestFunc,
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "admin with record with mfa",
+			Method: http.MethodDelete,
+			Url:    "/api/collections/users/records/4q1xlclmfloku33/mfa",
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			BeforeTestFunc: beforeTestFunc,
+			ExpectedStatus: 204,
+			ExpectedEvents: map[string]int{
+				"OnModelBeforeDelete": 1,
+				"OnModelAfterDelete":  1,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestRecordAuthRequestOTP(t *testing.T) {
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "collection without allowed otp",
+			Method:          http.MethodPost,
+			Url:             "/api/collections/users/request-otp",
+			Body:            strings.NewReader(`{"email":"test@example.com"}`),
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "invalid data",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/request-otp",
+			Body:   strings.NewReader(`{"email":"invalid"}`),
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				allowTestCollectionOTP(t, app, "users")
+				app.ResetEventCalls()
+			},
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"data":{`,
+				`"email":{"code":"validation_is_email"`,
+			},
+		},
+		{
+			Name:   "missing auth record",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/request-otp",
+			Body:   strings.NewReader(`{"email":"missing@example.com"}`),
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				allowTestCollectionOTP(t, app, "users")
+				app.ResetEventCalls()
+			},
+			Delay:           100 * time.Millisecond,
+			ExpectedStatus:  200,
+			ExpectedContent: []string{`"otpId":"`},
+		},
+		{
+			Name:   "existing auth record",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/request-otp",
+			Body:   strings.NewReader(`{"email":"test@example.com"}`),
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				allowTestCollectionOTP(t, app, "users")
+				app.ResetEventCalls()
+			},
+			Delay:           100 * time.Millisecond,
+			ExpectedStatus:  200,
+			ExpectedContent: []string{`"otpId":"`},
+			ExpectedEvents: map[string]int{
+				"OnRecordBeforeRequestOTPRequest": 1,
+				"OnRecordAfterRequestOTPRequest":  1,
+				"OnModelBeforeCreate":             1,
+				"OnModelAfterCreate":              1,
+				"OnMailerBeforeRecordOTPSend":     1,
+				"OnMailerAfterRecordOTPSend":      1,
+			},
+			AfterTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				if app.TestMailer.TotalSend != 1 {
+					t.Fatalf("Expected 1 sent email, got %d", app.TestMailer.TotalSend)
+				}
+
+				if !strings.Contains(app.TestMailer.LastMessage.HTML, "/auth-with-otp/") {
+					t.Fatalf("Expected the email to contain the magic link, got\n%s", app.TestMailer.LastMessage.HTML)
+				}
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestRecordAuthWithOTP(t *testing.T) {
+	setTestMfaEncryptionEnv(t)
+
+	// shared between the BeforeTestFunc and the lazy request bodies
+	var otp *models.OTP
+	var otpToken string
+
+	beforeTestFunc := func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+		allowTestCollectionOTP(t, app, "users")
+
+		record, _ := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+		otp = &models.OTP{
+			CollectionId: record.Collection().Id,
+			RecordId:     record.Id,
+			SentTo:       record.Email(),
+		}
+		otp.SetPassword("123456")
+		if err := app.Dao().SaveOTP(otp); err != nil {
+			t.Fatal(err)
+		}
+
+		app.ResetEventCalls()
+	}
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "collection without allowed otp",
+			Method:          http.MethodPost,
+			Url:             "/api/collections/users/auth-with-otp",
+			Body:            strings.NewReader(`{"otpId":"test","password":"123456"}`),
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:           "empty data",
+			Method:         http.MethodPost,
+			Url:            "/api/collections/users/auth-with-otp",
+			Body:           strings.NewReader(`{}`),
+			BeforeTestFunc: beforeTestFunc,
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"data":{`,
+				`"otpId":{"code":"validation_required"`,
+				`"password":{"code":"validation_required"`,
+			},
+		},
+		{
+			Name:   "invalid token",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/auth-with-otp",
+			Body: lazyBody(func() string {
+				return `{"token":"` + testRecordToken + `"}`
+			}),
+			BeforeTestFunc: beforeTestFunc,
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"data":{`,
+				`"token":{"code":"validation_invalid_token"`,
+			},
+		},
+		{
+			Name:           "invalid password",
+			Method:         http.MethodPost,
+			Url:            "/api/collections/users/auth-with-otp",
+			Body:           lazyBody(func() string { return `{"otpId":"` + otp.Id + `","password":"000000"}` }),
+			BeforeTestFunc: beforeTestFunc,
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"data":{`,
+				`"password":{"code":"validation_invalid_otp_password"`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnRecordBeforeAuthWithOTPRequest": 1,
+			},
+		},
+		{
+			Name:           "valid password",
+			Method:         http.MethodPost,
+			Url:            "/api/collections/users/auth-with-otp",
+			Body:           lazyBody(func() string { return `{"otpId":"` + otp.Id + `","password":"123456"}` }),
+			BeforeTestFunc: beforeTestFunc,
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"record":{`,
+				`"token":"`,
+				`"id":"4q1xlclmfloku33"`,
+				`"verified":true`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnRecordBeforeAuthWithOTPRequest": 1,
+				"OnRecordAfterAuthWithOTPRequest":  1,
+				"OnRecordAuthRequest":              1,
+				"OnModelBeforeUpdate":              1,
+				"OnModelAfterUpdate":               1,
+			},
+			AfterTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				if _, err := app.Dao().FindOTPById(otp.Id); err == nil {
+					t.Fatal("Expected the used otp to be deleted")
+				}
+			},
+		},
+		{
+			Name:   "valid magic link token",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/auth-with-otp",
+			Body: lazyBody(func() string {
+				return `{"token":"` + otpToken + `"}`
+			}),
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				beforeTestFunc(t, app, e)
+
+				record, _ := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+				otpToken, _ = tokens.NewRecordOTPToken(app, record, otp.Id)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"record":{`,
+				`"token":"`,
+				`"id":"4q1xlclmfloku33"`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnRecordBeforeAuthWithOTPRequest": 1,
+				"OnRecordAfterAuthWithOTPRequest":  1,
+				"OnRecordAuthRequest":              1,
+				"OnModelBeforeUpdate":              1,
+				"OnModelAfterUpdate":               1,
+			},
+		},
+		{
+			Name:   "valid password with enabled mfa",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/auth-with-otp",
+			Body:   lazyBody(func() string { return `{"otpId":"` + otp.Id + `","password":"123456"}` }),
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				record, _ := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+				enableTestRecordMfa(t, app, record)
+
+				beforeTestFunc(t, app, e)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"mfaRequired":true`,
+				`"mfaToken":"`,
+			},
+			NotExpectedContent: []string{
+				`"record":`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnRecordBeforeAuthWithOTPRequest": 1,
+				"OnRecordAfterAuthWithOTPRequest":  1,
+				"OnModelBeforeUpdate":              1,
+				"OnModelAfterUpdate":               1,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+// allowTestCollectionOTP enables the AllowOTPAuth option of the specified auth collection.
+func allowTestCollectionOTP(t *testing.T, app *tests.TestApp, collectionNameOrId string) {
+	collection, err := app.Dao().FindCollectionByNameOrId(collectionNameOrId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options := collection.AuthOptions()
+	options.AllowOTPAuth = true
+	collection.SetOptions(options)
+
+	if err := app.Dao().SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// lazyBody returns a request body reader that is resolved on first read
+// (useful for request data generated in the scenario BeforeTestFunc).
+func lazyBody(fn func() string) io.Reader {
+	return &lazyReader{fn: fn}
+}
+
+type lazyReader struct {
+	fn     func() string
+	reader io.Reader
+}
+
+func (r *lazyReader) Read(p []byte) (int, error) {
+	if r.reader == nil {
+		r.reader = strings.NewReader(r.fn())
+	}
+
+	return r.reader.Read(p)
+}
+
+// allowTestCollectionMfa enables the AllowMfa option of the provided auth collection.
+func allowTestCollectionMfa(t *testing.T, app *tests.TestApp, collection *models.Collection) {
+	options := collection.AuthOptions()
+	options.AllowMfa = true
+	collection.SetOptions(options)
+
+	if err := app.Dao().SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// setTestMfaEncryptionEnv sets the test app encryption env key required by MFA.
+func setTestMfaEncryptionEnv(t *testing.T) {
+	// the tests.NewTestApp() encryption env name
+	t.Setenv("pb_test_env", "abcdabcdabcdabcdabcdabcdabcdabcd")
+}
+
+// enableTestRecordMfa enables the mfa of the provided auth record
+// (and its collection) and returns its plain secret and recovery codes.
+func enableTestRecordMfa(t *testing.T, app *tests.TestApp, record *models.Record) (string, []string) {
+	allowTestCollectionMfa(t, app, record.Collection())
+
+	result, err := forms.NewRecordMfaSetup(app, record).Submit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	confirm := forms.NewRecordMfaConfirm(app, record)
+	confirm.Code, _ = security.TOTPCode(result.Secret, time.Now())
+
+	recoveryCodes, err := confirm.Submit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return result.Secret, recoveryCodes
+}
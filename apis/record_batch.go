@@ -0,0 +1,375 @@
+package apis
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+)
+
+// MaxBatchOperations is the max allowed number of operations in a single batch request.
+const MaxBatchOperations = 100
+
+const (
+	BatchActionCreate = "create"
+	BatchActionUpdate = "update"
+	BatchActionUpsert = "upsert"
+	BatchActionDelete = "delete"
+)
+
+type batchOperation struct {
+	Action     string         `json:"action"`
+	Collection string         `json:"collection"`
+	Id         string         `json:"id"`
+	Data       map[string]any `json:"data"`
+}
+
+// Validate makes batchOperation validatable by implementing [validation.Validatable] interface.
+func (op batchOperation) Validate() error {
+	return validation.ValidateStruct(&op,
+		validation.Field(
+			&op.Action,
+			validation.Required,
+			validation.In(BatchActionCreate, BatchActionUpdate, BatchActionUpsert, BatchActionDelete),
+		),
+		validation.Field(&op.Collection, validation.Required),
+		validation.Field(
+			&op.Id,
+			validation.When(op.Action == BatchActionUpdate || op.Action == BatchActionDelete, validation.Required),
+		),
+	)
+}
+
+type batchRequest struct {
+	Operations []batchOperation `json:"operations"`
+}
+
+// Validate makes batchRequest validatable by implementing [validation.Validatable] interface.
+func (r batchRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Operations, validation.Required, validation.Length(1, MaxBatchOperations)),
+	)
+}
+
+type batchOperationResult struct {
+	Status int `json:"status"`
+	Body   any `json:"body"`
+}
+
+// batch executes a list of record create/update/upsert/delete operations
+// within a single transaction.
+//
+// The collection API rules and the OnRecordBefore*Request hooks are applied
+// for each operation. The OnRecordAfter*Request hooks are triggered only after
+// the transaction was successfully committed.
+//
+// If any of the operations fail, the entire batch is rolled back.
+//
+// Note that only JSON data is supported (aka. no file uploads).
+func (api *recordApi) batch(c echo.Context) error {
+	form := &batchRequest{}
+
+	if err := c.Bind(form); err != nil {
+		return NewBadRequestError("An error occurred while loading the submitted data.", err)
+	}
+
+	if err := form.Validate(); err != nil {
+		return NewBadRequestError("Invalid batch request data.", err)
+	}
+
+	results := make([]*batchOperationResult, len(form.Operations))
+	afterCalls := make([]func() error, 0, len(form.Operations))
+
+	txErr := api.app.Dao().RunInTransaction(func(txDao *daos.Dao) error {
+		for i, op := range form.Operations {
+			result, afterCall, err := api.batchOperation(c, txDao, op)
+			if err != nil {
+				return batchOperationError(i, err)
+			}
+
+			results[i] = result
+			afterCalls = append(afterCalls, afterCall)
+		}
+
+		return nil
+	})
+
+	if txErr != nil {
+		return txErr
+	}
+
+	for _, afterCall := range afterCalls {
+		if err := afterCall(); err != nil && api.app.IsDebug() {
+			log.Println(err)
+		}
+	}
+
+	return c.JSON(http.StatusOK, results)
+}
+
+// batchOperationError normalizes a single batch operation error.
+func batchOperationError(index int, err error) *ApiError {
+	message := fmt.Sprintf("Batch operation %d failed.", index)
+
+	status := http.StatusBadRequest
+	var data any = err
+
+	if apiErr, ok := err.(*ApiError); ok {
+		status = apiErr.Code
+		message = fmt.Sprintf("Batch operation %d failed: %s", index, apiErr.Message)
+		data = apiErr.RawData()
+	}
+
+	// wrap the validation errors of the failed operation under its index
+	if v, ok := data.(validation.Errors); ok {
+		data = validation.Errors{"operations": validation.Errors{fmt.Sprint(index): v}}
+	}
+
+	return NewApiError(status, message, data)
+}
+
+func (api *recordApi) batchOperation(
+	c echo.Context,
+	txDao *daos.Dao,
+	op batchOperation,
+) (*batchOperationResult, func() error, error) {
+	collection, err := txDao.FindCollectionByNameOrId(op.Collection)
+	if err != nil || collection == nil {
+		return nil, nil, NewNotFoundError("", err)
+	}
+
+	if collection.IsView() {
+		return nil, nil, NewBadRequestError("Unsupported collection type.", nil)
+	}
+
+	// operation specific request data copy
+	requestData := *RequestData(c)
+	requestData.Data = op.Data
+	if requestData.Data == nil {
+		requestData.Data = map[string]any{}
+	}
+
+	action := op.Action
+	if action == BatchActionUpsert {
+		action = BatchActionCreate
+		if op.Id != "" {
+			if _, err := txDao.FindRecordById(collection.Id, op.Id); err == nil {
+				action = BatchActionUpdate
+			}
+		}
+	}
+
+	// apply the same per collection rate limit as the single record endpoints
+	if err := checkCollectionRateLimit(api.app, c, collection, action); err != nil {
+		return nil, nil, err
+	}
+
+	switch action {
+	case BatchActionCreate:
+		return api.batchCreate(c, txDao, collection, &requestData, op)
+	case BatchActionUpdate:
+		return api.batchUpdate(c, txDao, collection, &requestData, op)
+	case BatchActionDelete:
+		return api.batchDelete(c, txDao, collection, &requestData, op)
+	}
+
+	return nil, nil, NewBadRequestError("Unsupported batch action.", nil)
+}
+
+func (api *recordApi) batchCreate(
+	c echo.Context,
+	txDao *daos.Dao,
+	collection *models.Collection,
+	requestData *models.RequestData,
+	op batchOperation,
+) (*batchOperationResult, func() error, error) {
+	if err := checkAdminRecordsAccess(requestData, collection, true); err != nil {
+		return nil, nil, err
+	}
+
+	if requestData.Admin == nil && collection.CreateRule == nil {
+		// only admins can access if the rule is nil
+		return nil, nil, NewForbiddenError("Only admins can perform this action.", nil)
+	}
+
+	data := op.Data
+	if op.Id != "" {
+		data = make(map[string]any, len(op.Data)+1)
+		for k, v := range op.Data {
+			data[k] = v
+		}
+		data[schema.FieldNameId] = op.Id
+	}
+
+	hasFullManageAccess := requestData.Admin != nil
+
+	// temporary save the record and check it against the create rule
+	if requestData.Admin == nil && collection.CreateRule != nil {
+		testRecord := models.NewRecord(collection)
+
+		// replace modifiers fields so that the resolved value is always
+		// available when accessing requestData.Data using just the field name
+		if requestData.HasModifierDataKeys() {
+			requestData.Data = testRecord.ReplaceModifers(requestData.Data)
+		}
+
+		testForm := forms.NewRecordUpsert(api.app, testRecord)
+		testForm.SetDao(txDao)
+		testForm.SetFullManageAccess(true)
+		if err := testForm.LoadData(data); err != nil {
+			return nil, nil, NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+		}
+
+		testErr := testForm.DrySubmit(func(dryDao *daos.Dao) error {
+			foundRecord, err := dryDao.FindRecordById(
+				collection.Id,
+				testRecord.Id,
+				recordRuleFunc(dryDao, collection, requestData, collection.CreateRule),
+			)
+			if err != nil {
+				return fmt.Errorf("DrySubmit create rule failure: %w", err)
+			}
+			hasFullManageAccess = hasAuthManageAccess(dryDao, foundRecord, requestData)
+			return nil
+		})
+
+		if testErr != nil {
+			return nil, nil, NewBadRequestError("Failed to create record.", testErr)
+		}
+	}
+
+	record := models.NewRecord(collection)
+	form := forms.NewRecordUpsert(api.app, record)
+	form.SetDao(txDao)
+	form.SetFullManageAccess(hasFullManageAccess)
+
+	if err := form.LoadData(data); err != nil {
+		return nil, nil, NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	result := &batchOperationResult{Status: http.StatusOK}
+
+	event, submitErr := api.submitCreate(c, txDao, record, form, func(e *core.RecordCreateEvent) error {
+		result.Body = e.Record
+		return nil
+	})
+	if submitErr != nil {
+		return nil, nil, submitErr
+	}
+
+	return result, func() error {
+		return api.app.OnRecordAfterCreateRequest().Trigger(event)
+	}, nil
+}
+
+func (api *recordApi) batchUpdate(
+	c echo.Context,
+	txDao *daos.Dao,
+	collection *models.Collection,
+	requestData *models.RequestData,
+	op batchOperation,
+) (*batchOperationResult, func() error, error) {
+	if err := checkAdminRecordsAccess(requestData, collection, true); err != nil {
+		return nil, nil, err
+	}
+
+	if requestData.Admin == nil && collection.UpdateRule == nil {
+		// only admins can access if the rule is nil
+		return nil, nil, NewForbiddenError("Only admins can perform this action.", nil)
+	}
+
+	// eager fetch the record so that the modifier field values are replaced
+	// and available when accessing requestData.Data using just the field name
+	if requestData.HasModifierDataKeys() {
+		record, err := txDao.FindRecordById(collection.Id, op.Id)
+		if err != nil || record == nil {
+			return nil, nil, NewNotFoundError("", err)
+		}
+		requestData.Data = record.ReplaceModifers(requestData.Data)
+	}
+
+	record, fetchErr := txDao.FindRecordById(
+		collection.Id,
+		op.Id,
+		recordRuleFunc(txDao, collection, requestData, collection.UpdateRule),
+	)
+	if fetchErr != nil || record == nil {
+		return nil, nil, NewNotFoundError("", fetchErr)
+	}
+
+	form := forms.NewRecordUpsert(api.app, record)
+	form.SetDao(txDao)
+	form.SetFullManageAccess(requestData.Admin != nil || hasAuthManageAccess(txDao, record, requestData))
+
+	if err := form.LoadData(op.Data); err != nil {
+		return nil, nil, NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	result := &batchOperationResult{Status: http.StatusOK}
+
+	event, submitErr := api.submitUpdate(c, txDao, record, form, func(e *core.RecordUpdateEvent) error {
+		result.Body = e.Record
+		return nil
+	})
+	if submitErr != nil {
+		return nil, nil, submitErr
+	}
+
+	return result, func() error {
+		return api.app.OnRecordAfterUpdateRequest().Trigger(event)
+	}, nil
+}
+
+func (api *recordApi) batchDelete(
+	c echo.Context,
+	txDao *daos.Dao,
+	collection *models.Collection,
+	requestData *models.RequestData,
+	op batchOperation,
+) (*batchOperationResult, func() error, error) {
+	if err := checkAdminRecordsAccess(requestData, collection, true); err != nil {
+		return nil, nil, err
+	}
+
+	if requestData.Admin == nil && collection.DeleteRule == nil {
+		// only admins can access if the rule is nil
+		return nil, nil, NewForbiddenError("Only admins can perform this action.", nil)
+	}
+
+	record, fetchErr := txDao.FindRecordById(
+		collection.Id,
+		op.Id,
+		recordRuleFunc(txDao, collection, requestData, collection.DeleteRule),
+	)
+	if fetchErr != nil || record == nil {
+		return nil, nil, NewNotFoundError("", fetchErr)
+	}
+
+	event := new(core.RecordDeleteEvent)
+	event.HttpContext = c
+	event.Collection = collection
+	event.Record = record
+
+	handlerErr := api.app.OnRecordBeforeDeleteRequest().Trigger(event, func(e *core.RecordDeleteEvent) error {
+		if err := txDao.DeleteRecord(e.Record); err != nil {
+			return NewBadRequestError("Failed to delete record. Make sure that the record is not part of a required relation reference.", err)
+		}
+
+		return nil
+	})
+
+	if handlerErr != nil {
+		return nil, nil, handlerErr
+	}
+
+	return &batchOperationResult{Status: http.StatusNoContent}, func() error {
+		return api.app.OnRecordAfterDeleteRequest().Trigger(event)
+	}, nil
+}
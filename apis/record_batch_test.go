@@ -0,0 +1,229 @@
+package apis_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/models/settings"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestRecordBatch(t *testing.T) {
+	scenarios := []tests.ApiScenario{
+		{
+			Name:           "empty operations",
+			Method:         http.MethodPost,
+			Url:            "/api/batch",
+			Body:           strings.NewReader(`{"operations":[]}`),
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"data":{`,
+				`"operations":{"code":"validation_required"`,
+			},
+		},
+		{
+			Name:   "invalid operation data",
+			Method: http.MethodPost,
+			Url:    "/api/batch",
+			Body: strings.NewReader(`{"operations":[
+				{"action":"create","collection":"demo2","data":{"title":"batch1"}},
+				{"action":"invalid","collection":""},
+				{"action":"update","collection":"demo2"}
+			]}`),
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"data":{`,
+				`"operations":{`,
+				`"1":{"action":{"code":"validation_in_invalid"`,
+				`"collection":{"code":"validation_required"`,
+				`"2":{"id":{"code":"validation_required"`,
+			},
+			NotExpectedContent: []string{
+				`"0":`,
+			},
+		},
+		{
+			Name:   "guest trying to create in admin only collection",
+			Method: http.MethodPost,
+			Url:    "/api/batch",
+			Body: strings.NewReader(`{"operations":[
+				{"action":"create","collection":"demo2","data":{"title":"batch1"}},
+				{"action":"create","collection":"demo1","data":{"text":"batch2"}}
+			]}`),
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`, `Batch operation 1 failed`},
+			ExpectedEvents: map[string]int{
+				"OnRecordBeforeCreateRequest": 1,
+				"OnModelBeforeCreate":         1,
+			},
+			AfterTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				// the successful operation should be rolled back
+				if _, err := app.Dao().FindFirstRecordByData("demo2", "title", "batch1"); err == nil {
+					t.Fatal("Expected the batch1 record to be rolled back")
+				}
+			},
+		},
+		{
+			Name:   "validation error in the middle of the batch",
+			Method: http.MethodPost,
+			Url:    "/api/batch",
+			Body: strings.NewReader(`{"operations":[
+				{"action":"create","collection":"demo2","data":{"title":"batch1"}},
+				{"action":"create","collection":"demo2","data":{"title":"batch1"}},
+				{"action":"create","collection":"demo2","data":{"title":"batch3"}}
+			]}`),
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"data":{`,
+				`"operations":{"1":{"title":{"code":"validation_not_unique"`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnRecordBeforeCreateRequest": 1,
+				"OnModelBeforeCreate":         1,
+			},
+			AfterTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				if _, err := app.Dao().FindFirstRecordByData("demo2", "title", "batch1"); err == nil {
+					t.Fatal("Expected the batch1 record to be rolled back")
+				}
+			},
+		},
+		{
+			Name:   "missing record",
+			Method: http.MethodPost,
+			Url:    "/api/batch",
+			Body: strings.NewReader(`{"operations":[
+				{"action":"delete","collection":"demo2","id":"missing"}
+			]}`),
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`, `Batch operation 0 failed`},
+		},
+		{
+			Name:   "guest with successful create, update, upsert and delete",
+			Method: http.MethodPost,
+			Url:    "/api/batch",
+			Body: strings.NewReader(`{"operations":[
+				{"action":"create","collection":"demo2","id":"batch0000000001","data":{"title":"batch1"}},
+				{"action":"update","collection":"demo2","id":"llvuca81nly1qls","data":{"title":"batch2"}},
+				{"action":"upsert","collection":"demo2","id":"batch0000000003","data":{"title":"batch3"}},
+				{"action":"upsert","collection":"demo2","id":"batch0000000003","data":{"title":"batch4"}},
+				{"action":"delete","collection":"demo2","id":"batch0000000001"}
+			]}`),
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`{"status":200,"body":{`,
+				`"id":"batch0000000001"`,
+				`"title":"batch1"`,
+				`"id":"llvuca81nly1qls"`,
+				`"title":"batch2"`,
+				`"title":"batch3"`,
+				`"title":"batch4"`,
+				`{"status":204,"body":null}`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnRecordBeforeCreateRequest": 2,
+				"OnRecordAfterCreateRequest":  2,
+				"OnRecordBeforeUpdateRequest": 2,
+				"OnRecordAfterUpdateRequest":  2,
+				"OnRecordBeforeDeleteRequest": 1,
+				"OnRecordAfterDeleteRequest":  1,
+				"OnModelBeforeCreate":         2,
+				"OnModelAfterCreate":          2,
+				"OnModelBeforeUpdate":         2,
+				"OnModelAfterUpdate":          2,
+				"OnModelBeforeDelete":         1,
+				"OnModelAfterDelete":          1,
+			},
+			AfterTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				if _, err := app.Dao().FindRecordById("demo2", "batch0000000001"); err == nil {
+					t.Fatal("Expected batch0000000001 to be deleted")
+				}
+
+				updated, err := app.Dao().FindRecordById("demo2", "llvuca81nly1qls")
+				if err != nil || updated.GetString("title") != "batch2" {
+					t.Fatalf("Expected llvuca81nly1qls to be updated, got %v (%v)", updated, err)
+				}
+
+				upserted, err := app.Dao().FindRecordById("demo2", "batch0000000003")
+				if err != nil || upserted.GetString("title") != "batch4" {
+					t.Fatalf("Expected batch0000000003 to be upserted, got %v (%v)", upserted, err)
+				}
+			},
+		},
+		{
+			Name:   "auth record with create rule referencing the batch operation data",
+			Method: http.MethodPost,
+			Url:    "/api/batch",
+			Body: strings.NewReader(`{"operations":[
+				{"action":"create","collection":"demo5","id":"batch0000000001","data":{"total":3}},
+				{"action":"create","collection":"demo5","id":"batch0000000002","data":{"total":2}}
+			]}`),
+			RequestHeaders: map[string]string{
+				"Authorization": testRecordToken,
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`, `Batch operation 1 failed`},
+			ExpectedEvents: map[string]int{
+				"OnRecordBeforeCreateRequest": 1,
+				"OnModelBeforeCreate":         1,
+			},
+			AfterTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				if _, err := app.Dao().FindRecordById("demo5", "batch0000000001"); err == nil {
+					t.Fatal("Expected the batch0000000001 record to be rolled back")
+				}
+			},
+		},
+		{
+			Name:   "guest exceeding the collection create rate limit",
+			Method: http.MethodPost,
+			Url:    "/api/batch",
+			Body: strings.NewReader(`{"operations":[
+				{"action":"create","collection":"demo2","id":"batch0000000001","data":{"title":"batch1"}},
+				{"action":"upsert","collection":"demo2","id":"batch0000000002","data":{"title":"batch2"}}
+			]}`),
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				app.Settings().RateLimits.Enabled = true
+				app.Settings().RateLimits.Rules = []settings.RateLimitRule{
+					{Label: "demo2:create", MaxRequests: 1, Duration: 60},
+				}
+			},
+			ExpectedStatus:  429,
+			ExpectedContent: []string{`"data":{}`, `Batch operation 1 failed`},
+			ExpectedEvents: map[string]int{
+				"OnRecordBeforeCreateRequest": 1,
+				"OnModelBeforeCreate":         1,
+			},
+			AfterTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				if _, err := app.Dao().FindRecordById("demo2", "batch0000000001"); err == nil {
+					t.Fatal("Expected the batch0000000001 record to be rolled back")
+				}
+			},
+		},
+		{
+			Name:   "admin with admin only collection",
+			Method: http.MethodPost,
+			Url:    "/api/batch",
+			Body: strings.NewReader(`{"operations":[
+				{"action":"create","collection":"demo1","data":{"text":"batch1"}}
+			]}`),
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"text":"batch1"`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnRecordBeforeCreateRequest": 1,
+				"OnRecordAfterCreateRequest":  1,
+				"OnModelBeforeCreate":         1,
+				"OnModelAfterCreate":          1,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
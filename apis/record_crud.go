@@ -16,7 +16,10 @@ import (
 	"github.com/pocketbase/pocketbase/tools/search"
 )
 
-const expandQueryParam = "expand"
+const (
+	expandQueryParam = "expand"
+	fieldsQueryParam = "fields"
+)
 
 // bindRecordCrudApi registers the record crud api endpoints and
 // the corresponding handlers.
@@ -28,11 +31,16 @@ func bindRecordCrudApi(app core.App, rg *echo.Group) {
 		ActivityLogger(app),
 	)
 
-	subGroup.GET("/records", api.list, LoadCollectionContext(app))
-	subGroup.GET("/records/:id", api.view, LoadCollectionContext(app))
-	subGroup.POST("/records", api.create, LoadCollectionContext(app, models.CollectionTypeBase, models.CollectionTypeAuth))
-	subGroup.PATCH("/records/:id", api.update, LoadCollectionContext(app, models.CollectionTypeBase, models.CollectionTypeAuth))
-	subGroup.DELETE("/records/:id", api.delete, LoadCollectionContext(app, models.CollectionTypeBase, models.CollectionTypeAuth))
+	subGroup.GET("/records", api.list, LoadCollectionContext(app), CollectionRateLimit(app, "list"))
+	subGroup.GET("/aggregate", api.aggregate, LoadCollectionContext(app), CollectionRateLimit(app, "list"))
+	subGroup.GET("/records/:id", api.view, LoadCollectionContext(app), CollectionRateLimit(app, "view"))
+	subGroup.POST("/records", api.create, LoadCollectionContext(app, models.CollectionTypeBase, models.CollectionTypeAuth), CollectionRateLimit(app, "create"))
+	subGroup.PATCH("/records/:id", api.update, LoadCollectionContext(app, models.CollectionTypeBase, models.CollectionTypeAuth), CollectionRateLimit(app, "update"))
+	subGroup.DELETE("/records/:id", api.delete, LoadCollectionContext(app, models.CollectionTypeBase, models.CollectionTypeAuth), CollectionRateLimit(app, "delete"))
+
+	rg.POST("/batch", api.batch, ActivityLogger(app))
+
+	bindAuditActors(app)
 }
 
 type recordApi struct {
@@ -52,6 +60,10 @@ func (api *recordApi) list(c echo.Context) error {
 
 	requestData := RequestData(c)
 
+	if err := checkAdminRecordsAccess(requestData, collection, false); err != nil {
+		return err
+	}
+
 	if requestData.Admin == nil && collection.ListRule == nil {
 		// only admins can access if the rule is nil
 		return NewForbiddenError("Only admins can perform this action.", nil)
@@ -65,8 +77,21 @@ func (api *recordApi) list(c echo.Context) error {
 		requestData.Admin != nil,
 	)
 
+	query := api.app.Dao().RecordQuery(collection)
+
+	// load only the columns required for the requested fields
+	columns := projectedRecordColumns(
+		collection,
+		splitListParam(c.QueryParam(fieldsQueryParam)),
+		splitListParam(c.QueryParam(expandQueryParam)),
+	)
+	if len(columns) > 0 {
+		query.Select(columns...)
+	}
+
 	searchProvider := search.NewProvider(fieldsResolver).
-		Query(api.app.Dao().RecordQuery(collection))
+		Query(query).
+		CursorSecret(api.app.Settings().RecordAuthToken.Secret)
 
 	if requestData.Admin == nil && collection.ListRule != nil {
 		searchProvider.AddFilter(search.FilterData(*collection.ListRule))
@@ -107,23 +132,16 @@ func (api *recordApi) view(c echo.Context) error {
 
 	requestData := RequestData(c)
 
+	if err := checkAdminRecordsAccess(requestData, collection, false); err != nil {
+		return err
+	}
+
 	if requestData.Admin == nil && collection.ViewRule == nil {
 		// only admins can access if the rule is nil
 		return NewForbiddenError("Only admins can perform this action.", nil)
 	}
 
-	ruleFunc := func(q *dbx.SelectQuery) error {
-		if requestData.Admin == nil && collection.ViewRule != nil && *collection.ViewRule != "" {
-			resolver := resolvers.NewRecordFieldResolver(api.app.Dao(), collection, requestData, true)
-			expr, err := search.FilterData(*collection.ViewRule).BuildExpr(resolver)
-			if err != nil {
-				return err
-			}
-			resolver.UpdateQuery(q)
-			q.AndWhere(expr)
-		}
-		return nil
-	}
+	ruleFunc := recordRuleFunc(api.app.Dao(), collection, requestData, collection.ViewRule)
 
 	record, fetchErr := api.app.Dao().FindRecordById(collection.Id, recordId, ruleFunc)
 	if fetchErr != nil || record == nil {
@@ -152,6 +170,10 @@ func (api *recordApi) create(c echo.Context) error {
 
 	requestData := RequestData(c)
 
+	if err := checkAdminRecordsAccess(requestData, collection, true); err != nil {
+		return err
+	}
+
 	if requestData.Admin == nil && collection.CreateRule == nil {
 		// only admins can access if the rule is nil
 		return NewForbiddenError("Only admins can perform this action.", nil)
@@ -175,23 +197,12 @@ func (api *recordApi) create(c echo.Context) error {
 			return NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
 		}
 
-		createRuleFunc := func(q *dbx.SelectQuery) error {
-			if *collection.CreateRule == "" {
-				return nil // no create rule to resolve
-			}
-
-			resolver := resolvers.NewRecordFieldResolver(api.app.Dao(), collection, requestData, true)
-			expr, err := search.FilterData(*collection.CreateRule).BuildExpr(resolver)
-			if err != nil {
-				return err
-			}
-			resolver.UpdateQuery(q)
-			q.AndWhere(expr)
-			return nil
-		}
-
 		testErr := testForm.DrySubmit(func(txDao *daos.Dao) error {
-			foundRecord, err := txDao.FindRecordById(collection.Id, testRecord.Id, createRuleFunc)
+			foundRecord, err := txDao.FindRecordById(
+				collection.Id,
+				testRecord.Id,
+				recordRuleFunc(txDao, collection, requestData, collection.CreateRule),
+			)
 			if err != nil {
 				return fmt.Errorf("DrySubmit create rule failure: %w", err)
 			}
@@ -213,29 +224,8 @@ func (api *recordApi) create(c echo.Context) error {
 		return NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
 	}
 
-	event := new(core.RecordCreateEvent)
-	event.HttpContext = c
-	event.Collection = collection
-	event.Record = record
-	event.UploadedFiles = form.FilesToUpload()
-
-	// create the record
-	submitErr := form.Submit(func(next forms.InterceptorNextFunc[*models.Record]) forms.InterceptorNextFunc[*models.Record] {
-		return func(m *models.Record) error {
-			event.Record = m
-
-			return api.app.OnRecordBeforeCreateRequest().Trigger(event, func(e *core.RecordCreateEvent) error {
-				if err := next(e.Record); err != nil {
-					return NewBadRequestError("Failed to create record.", err)
-				}
-
-				if err := EnrichRecord(e.HttpContext, api.app.Dao(), e.Record); err != nil && api.app.IsDebug() {
-					log.Println(err)
-				}
-
-				return e.HttpContext.JSON(http.StatusOK, e.Record)
-			})
-		}
+	event, submitErr := api.submitCreate(c, api.app.Dao(), record, form, func(e *core.RecordCreateEvent) error {
+		return e.HttpContext.JSON(http.StatusOK, e.Record)
 	})
 
 	if submitErr == nil {
@@ -260,6 +250,10 @@ func (api *recordApi) update(c echo.Context) error {
 
 	requestData := RequestData(c)
 
+	if err := checkAdminRecordsAccess(requestData, collection, true); err != nil {
+		return err
+	}
+
 	if requestData.Admin == nil && collection.UpdateRule == nil {
 		// only admins can access if the rule is nil
 		return NewForbiddenError("Only admins can perform this action.", nil)
@@ -275,18 +269,7 @@ func (api *recordApi) update(c echo.Context) error {
 		requestData.Data = record.ReplaceModifers(requestData.Data)
 	}
 
-	ruleFunc := func(q *dbx.SelectQuery) error {
-		if requestData.Admin == nil && collection.UpdateRule != nil && *collection.UpdateRule != "" {
-			resolver := resolvers.NewRecordFieldResolver(api.app.Dao(), collection, requestData, true)
-			expr, err := search.FilterData(*collection.UpdateRule).BuildExpr(resolver)
-			if err != nil {
-				return err
-			}
-			resolver.UpdateQuery(q)
-			q.AndWhere(expr)
-		}
-		return nil
-	}
+	ruleFunc := recordRuleFunc(api.app.Dao(), collection, requestData, collection.UpdateRule)
 
 	// fetch record
 	record, fetchErr := api.app.Dao().FindRecordById(collection.Id, recordId, ruleFunc)
@@ -302,29 +285,8 @@ func (api *recordApi) update(c echo.Context) error {
 		return NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
 	}
 
-	event := new(core.RecordUpdateEvent)
-	event.HttpContext = c
-	event.Collection = collection
-	event.Record = record
-	event.UploadedFiles = form.FilesToUpload()
-
-	// update the record
-	submitErr := form.Submit(func(next forms.InterceptorNextFunc[*models.Record]) forms.InterceptorNextFunc[*models.Record] {
-		return func(m *models.Record) error {
-			event.Record = m
-
-			return api.app.OnRecordBeforeUpdateRequest().Trigger(event, func(e *core.RecordUpdateEvent) error {
-				if err := next(e.Record); err != nil {
-					return NewBadRequestError("Failed to update record.", err)
-				}
-
-				if err := EnrichRecord(e.HttpContext, api.app.Dao(), e.Record); err != nil && api.app.IsDebug() {
-					log.Println(err)
-				}
-
-				return e.HttpContext.JSON(http.StatusOK, e.Record)
-			})
-		}
+	event, submitErr := api.submitUpdate(c, api.app.Dao(), record, form, func(e *core.RecordUpdateEvent) error {
+		return e.HttpContext.JSON(http.StatusOK, e.Record)
 	})
 
 	if submitErr == nil {
@@ -349,23 +311,16 @@ func (api *recordApi) delete(c echo.Context) error {
 
 	requestData := RequestData(c)
 
+	if err := checkAdminRecordsAccess(requestData, collection, true); err != nil {
+		return err
+	}
+
 	if requestData.Admin == nil && collection.DeleteRule == nil {
 		// only admins can access if the rule is nil
 		return NewForbiddenError("Only admins can perform this action.", nil)
 	}
 
-	ruleFunc := func(q *dbx.SelectQuery) error {
-		if requestData.Admin == nil && collection.DeleteRule != nil && *collection.DeleteRule != "" {
-			resolver := resolvers.NewRecordFieldResolver(api.app.Dao(), collection, requestData, true)
-			expr, err := search.FilterData(*collection.DeleteRule).BuildExpr(resolver)
-			if err != nil {
-				return err
-			}
-			resolver.UpdateQuery(q)
-			q.AndWhere(expr)
-		}
-		return nil
-	}
+	ruleFunc := recordRuleFunc(api.app.Dao(), collection, requestData, collection.DeleteRule)
 
 	record, fetchErr := api.app.Dao().FindRecordById(collection.Id, recordId, ruleFunc)
 	if fetchErr != nil || record == nil {
@@ -412,3 +367,103 @@ func (api *recordApi) checkForForbiddenQueryFields(c echo.Context) error {
 
 	return nil
 }
+
+// submitCreate submits the provided record create form wrapped in the
+// OnRecordBeforeCreateRequest hook and returns the triggered event.
+//
+// The created record is enriched using the provided dao and then passed
+// to finalize as the last step of the hook chain.
+func (api *recordApi) submitCreate(
+	c echo.Context,
+	dao *daos.Dao,
+	record *models.Record,
+	form *forms.RecordUpsert,
+	finalize func(e *core.RecordCreateEvent) error,
+) (*core.RecordCreateEvent, error) {
+	event := new(core.RecordCreateEvent)
+	event.HttpContext = c
+	event.Collection = record.Collection()
+	event.Record = record
+	event.UploadedFiles = form.FilesToUpload()
+
+	err := form.Submit(func(next forms.InterceptorNextFunc[*models.Record]) forms.InterceptorNextFunc[*models.Record] {
+		return func(m *models.Record) error {
+			event.Record = m
+
+			return api.app.OnRecordBeforeCreateRequest().Trigger(event, func(e *core.RecordCreateEvent) error {
+				if err := next(e.Record); err != nil {
+					return NewBadRequestError("Failed to create record.", err)
+				}
+
+				if err := EnrichRecord(e.HttpContext, dao, e.Record); err != nil && api.app.IsDebug() {
+					log.Println(err)
+				}
+
+				return finalize(e)
+			})
+		}
+	})
+
+	return event, err
+}
+
+// submitUpdate submits the provided record update form wrapped in the
+// OnRecordBeforeUpdateRequest hook and returns the triggered event.
+//
+// The updated record is enriched using the provided dao and then passed
+// to finalize as the last step of the hook chain.
+func (api *recordApi) submitUpdate(
+	c echo.Context,
+	dao *daos.Dao,
+	record *models.Record,
+	form *forms.RecordUpsert,
+	finalize func(e *core.RecordUpdateEvent) error,
+) (*core.RecordUpdateEvent, error) {
+	event := new(core.RecordUpdateEvent)
+	event.HttpContext = c
+	event.Collection = record.Collection()
+	event.Record = record
+	event.UploadedFiles = form.FilesToUpload()
+
+	err := form.Submit(func(next forms.InterceptorNextFunc[*models.Record]) forms.InterceptorNextFunc[*models.Record] {
+		return func(m *models.Record) error {
+			event.Record = m
+
+			return api.app.OnRecordBeforeUpdateRequest().Trigger(event, func(e *core.RecordUpdateEvent) error {
+				if err := next(e.Record); err != nil {
+					return NewBadRequestError("Failed to update record.", err)
+				}
+
+				if err := EnrichRecord(e.HttpContext, dao, e.Record); err != nil && api.app.IsDebug() {
+					log.Println(err)
+				}
+
+				return finalize(e)
+			})
+		}
+	})
+
+	return event, err
+}
+
+// recordRuleFunc returns a query modifier that applies the provided
+// collection rule (if any) for non-admin requests.
+func recordRuleFunc(
+	dao *daos.Dao,
+	collection *models.Collection,
+	requestData *models.RequestData,
+	rule *string,
+) func(q *dbx.SelectQuery) error {
+	return func(q *dbx.SelectQuery) error {
+		if requestData.Admin == nil && rule != nil && *rule != "" {
+			resolver := resolvers.NewRecordFieldResolver(dao, collection, requestData, true)
+			expr, err := search.FilterData(*rule).BuildExpr(resolver)
+			if err != nil {
+				return err
+			}
+			resolver.UpdateQuery(q)
+			q.AndWhere(expr)
+		}
+		return nil
+	}
+}
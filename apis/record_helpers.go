@@ -11,8 +11,10 @@ import (
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/daos"
 	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
 	"github.com/pocketbase/pocketbase/resolvers"
 	"github.com/pocketbase/pocketbase/tokens"
+	"github.com/pocketbase/pocketbase/tools/list"
 	"github.com/pocketbase/pocketbase/tools/rest"
 	"github.com/pocketbase/pocketbase/tools/search"
 )
@@ -63,22 +65,25 @@ func RecordAuthResponse(app core.App, c echo.Context, authRecord *models.Record,
 		e.Record.IgnoreEmailVisibility(true)
 
 		// expand record relations
-		expands := strings.Split(c.QueryParam(expandQueryParam), ",")
+		expands := splitListParam(c.QueryParam(expandQueryParam))
 		if len(expands) > 0 {
 			// create a copy of the cached request data and adjust it to the current auth record
 			requestData := *RequestData(e.HttpContext)
 			requestData.Admin = nil
 			requestData.AuthRecord = e.Record
-			failed := app.Dao().ExpandRecord(
-				e.Record,
+			failed := app.Dao().ExpandRecordsWithFilter(
+				[]*models.Record{e.Record},
 				expands,
 				expandFetch(app.Dao(), &requestData),
+				expandFilter(app.Dao(), &requestData),
 			)
 			if len(failed) > 0 && app.IsDebug() {
 				log.Println("Failed to expand relations: ", failed)
 			}
 		}
 
+		e.Record.SetExportFields(splitListParam(c.QueryParam(fieldsQueryParam))...)
+
 		result := map[string]any{
 			"token":  e.Token,
 			"record": e.Record,
@@ -92,8 +97,39 @@ func RecordAuthResponse(app core.App, c echo.Context, authRecord *models.Record,
 	})
 }
 
+// RecordMfaResponse generates and writes a properly formatted
+// "mfa pending" response for the provided auth record.
+//
+// The returned short-lived mfa token could be exchanged for a regular
+// auth token via the "auth-with-mfa" endpoint.
+func RecordMfaResponse(app core.App, c echo.Context, authRecord *models.Record) error {
+	token, tokenErr := tokens.NewRecordMfaToken(app, authRecord)
+	if tokenErr != nil {
+		return NewBadRequestError("Failed to create mfa token.", tokenErr)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"mfaRequired": true,
+		"mfaToken":    token,
+	})
+}
+
+// recordAuthOrMfaResponse writes a [RecordMfaResponse] if the auth record
+// has enabled MFA, otherwise fallbacks to the regular [RecordAuthResponse].
+func recordAuthOrMfaResponse(app core.App, c echo.Context, authRecord *models.Record, meta any) error {
+	if authRecord.Collection().AuthOptions().AllowMfa {
+		if mfa, _ := app.Dao().FindMfaByRecord(authRecord); mfa != nil && mfa.Verified {
+			return RecordMfaResponse(app, c, authRecord)
+		}
+	}
+
+	return RecordAuthResponse(app, c, authRecord, meta)
+}
+
 // EnrichRecord parses the request context and enrich the provided record:
+//   - loads the file fields image placeholders (if enabled)
 //   - expands relations (if defaultExpands and/or ?expand query param is set)
+//   - limits the serialized record fields (if ?fields query param is set)
 //   - ensures that the emails of the auth record and its expanded auth relations
 //     are visibe only for the current logged admin, record owner or record with manage access
 func EnrichRecord(c echo.Context, dao *daos.Dao, record *models.Record, defaultExpands ...string) error {
@@ -101,7 +137,9 @@ func EnrichRecord(c echo.Context, dao *daos.Dao, record *models.Record, defaultE
 }
 
 // EnrichRecords parses the request context and enriches the provided records:
+//   - loads the file fields image placeholders (if enabled)
 //   - expands relations (if defaultExpands and/or ?expand query param is set)
+//   - limits the serialized records fields (if ?fields query param is set)
 //   - ensures that the emails of the auth records and their expanded auth relations
 //     are visibe only for the current logged admin, record owner or record with manage access
 func EnrichRecords(c echo.Context, dao *daos.Dao, records []*models.Record, defaultExpands ...string) error {
@@ -111,13 +149,23 @@ func EnrichRecords(c echo.Context, dao *daos.Dao, records []*models.Record, defa
 		return fmt.Errorf("Failed to resolve email visibility: %w", err)
 	}
 
+	if err := loadImagePlaceholders(dao, records); err != nil {
+		return fmt.Errorf("Failed to load the image placeholders: %w", err)
+	}
+
+	if fields := splitListParam(c.QueryParam(fieldsQueryParam)); len(fields) > 0 {
+		for _, record := range records {
+			record.SetExportFields(fields...)
+		}
+	}
+
 	expands := defaultExpands
-	expands = append(expands, strings.Split(c.QueryParam(expandQueryParam), ",")...)
+	expands = append(expands, splitListParam(c.QueryParam(expandQueryParam))...)
 	if len(expands) == 0 {
 		return nil // nothing to expand
 	}
 
-	errs := dao.ExpandRecords(records, expands, expandFetch(dao, requestData))
+	errs := dao.ExpandRecordsWithFilter(records, expands, expandFetch(dao, requestData), expandFilter(dao, requestData))
 	if len(errs) > 0 {
 		return fmt.Errorf("Failed to expand: %v", errs)
 	}
@@ -125,32 +173,168 @@ func EnrichRecords(c echo.Context, dao *daos.Dao, records []*models.Record, defa
 	return nil
 }
 
-// expandFetch is the records fetch function that is used to expand related records.
-func expandFetch(
-	dao *daos.Dao,
-	requestData *models.RequestData,
-) daos.ExpandFetchFunc {
-	return func(relCollection *models.Collection, relIds []string) ([]*models.Record, error) {
-		records, err := dao.FindRecordsByIds(relCollection.Id, relIds, func(q *dbx.SelectQuery) error {
-			if requestData.Admin != nil {
-				return nil // admins can access everything
+// projectedRecordColumns returns the collection columns required to load
+// the records serialized with the provided fields projection and expands.
+//
+// Returns nil if all columns should be loaded (eg. empty or "*" projection).
+func projectedRecordColumns(collection *models.Collection, fields []string, expands []string) []string {
+	if len(fields) == 0 || collection.IsView() {
+		return nil // the view columns could vary
+	}
+
+	columns := []string{schema.FieldNameId}
+
+	if collection.IsAuth() {
+		// required also for the email visibility checks
+		columns = append(
+			columns,
+			schema.FieldNameUsername,
+			schema.FieldNameVerified,
+			schema.FieldNameEmailVisibility,
+			schema.FieldNameEmail,
+		)
+	}
+
+	for _, field := range fields {
+		name, _, _ := strings.Cut(field, ".")
+
+		switch name {
+		case "*":
+			return nil
+		case schema.FieldNameCreated, schema.FieldNameUpdated:
+			columns = append(columns, name)
+		case schema.FieldNamePlaceholders:
+			for _, f := range collection.Schema.Fields() {
+				if options, ok := f.Options.(*schema.FileOptions); ok && options.Placeholders {
+					columns = append(columns, f.Name)
+				}
+			}
+		default:
+			if collection.Schema.GetFieldByName(name) != nil {
+				columns = append(columns, name)
 			}
+		}
+	}
+
+	// the expanded relation fields
+	for _, expand := range expands {
+		name, _, _ := strings.Cut(expand, ".")
+		if collection.Schema.GetFieldByName(name) != nil {
+			columns = append(columns, name)
+		}
+	}
 
-			if relCollection.ViewRule == nil {
-				return fmt.Errorf("Only admins can view collection %q records", relCollection.Name)
+	columns = list.NonzeroUniques(columns)
+
+	for i, column := range columns {
+		columns[i] = collection.Name + "." + column
+	}
+
+	return columns
+}
+
+// loadImagePlaceholders loads and sets the image placeholders
+// of the file fields with enabled placeholders option.
+//
+// Only the placeholders of the files currently stored in the
+// records are exposed. View collections records are ignored.
+func loadImagePlaceholders(dao *daos.Dao, records []*models.Record) error {
+	// group the records by their collection
+	grouped := map[string][]*models.Record{}
+	collections := map[string]*models.Collection{}
+	for _, record := range records {
+		collection := record.Collection()
+		if collection == nil || collection.IsView() {
+			continue
+		}
+
+		hasPlaceholders := false
+		for _, field := range collection.Schema.Fields() {
+			if options, ok := field.Options.(*schema.FileOptions); ok && options.Placeholders {
+				hasPlaceholders = true
+				break
 			}
+		}
+		if !hasPlaceholders {
+			continue
+		}
+
+		grouped[collection.Id] = append(grouped[collection.Id], record)
+		collections[collection.Id] = collection
+	}
 
-			if *relCollection.ViewRule != "" {
-				resolver := resolvers.NewRecordFieldResolver(dao, relCollection, requestData, true)
-				expr, err := search.FilterData(*(relCollection.ViewRule)).BuildExpr(resolver)
-				if err != nil {
-					return err
+	for collectionId, group := range grouped {
+		ids := make([]string, len(group))
+		for i, record := range group {
+			ids[i] = record.Id
+		}
+
+		placeholders, err := dao.FindImagePlaceholdersByRecords(collectionId, ids...)
+		if err != nil {
+			return err
+		}
+
+		for _, record := range group {
+			data := map[string]any{}
+
+			for _, p := range placeholders {
+				if p.RecordId != record.Id {
+					continue
+				}
+
+				field := collections[collectionId].Schema.GetFieldByName(p.Field)
+				if field == nil {
+					continue
+				}
+
+				options, _ := field.Options.(*schema.FileOptions)
+				if options == nil || !options.Placeholders ||
+					!list.ExistInSlice(p.Filename, record.GetStringSlice(p.Field)) {
+					continue
+				}
+
+				fieldData, _ := data[p.Field].(map[string]any)
+				if fieldData == nil {
+					fieldData = map[string]any{}
+					data[p.Field] = fieldData
 				}
-				resolver.UpdateQuery(q)
-				q.AndWhere(expr)
+				fieldData[p.Filename] = p.PublicExport()
 			}
 
-			return nil
+			record.SetPlaceholders(data)
+		}
+	}
+
+	return nil
+}
+
+// checkAdminRecordsAccess checks whether the request admin (if any)
+// is allowed to view, or to manage when manage is true, the records
+// of the provided collection based on its role and collection scopes.
+func checkAdminRecordsAccess(requestData *models.RequestData, collection *models.Collection, manage bool) error {
+	if requestData.Admin == nil {
+		return nil // not an admin request
+	}
+
+	if manage && !requestData.Admin.CanManageRecords(collection) {
+		return NewForbiddenError("The authorized admin is not allowed to manage the collection records.", nil)
+	}
+
+	if !manage && !requestData.Admin.CanViewRecords(collection) {
+		return NewForbiddenError("The authorized admin is not allowed to view the collection records.", nil)
+	}
+
+	return nil
+}
+
+// expandFetch is the records fetch function that is used to expand related records.
+func expandFetch(
+	dao *daos.Dao,
+	requestData *models.RequestData,
+) daos.ExpandFetchFunc {
+	return func(relCollection *models.Collection, relIds []string) ([]*models.Record, error) {
+		records, err := dao.FindRecordsByIds(relCollection.Id, relIds, func(q *dbx.SelectQuery) error {
+			return applyExpandViewRule(dao, requestData, relCollection, q)
 		})
 
 		if err == nil && len(records) > 0 {
@@ -161,6 +345,90 @@ func expandFetch(
 	}
 }
 
+// expandFilter is the query filter function that is used to apply the related
+// collection view rule and the expand path `:filter()` modifier to the
+// indirectly expanded relation records (so that their limit and count
+// take into account only the records the requester has access to).
+func expandFilter(
+	dao *daos.Dao,
+	requestData *models.RequestData,
+) daos.ExpandFilterFunc {
+	return func(relCollection *models.Collection, filter string, q *dbx.SelectQuery) error {
+		if err := applyExpandViewRule(dao, requestData, relCollection, q); err != nil {
+			return err
+		}
+
+		if filter == "" {
+			return nil
+		}
+
+		isAdmin := requestData.Admin != nil
+
+		var resolver search.FieldResolver = resolvers.NewRecordFieldResolver(dao, relCollection, requestData, isAdmin)
+		if !isAdmin {
+			resolver = &expandFilterResolver{resolver}
+		}
+
+		expr, err := search.FilterData(filter).BuildExpr(resolver)
+		if err != nil {
+			return err
+		}
+		resolver.UpdateQuery(q)
+		q.AndWhere(expr)
+
+		return nil
+	}
+}
+
+// expandFilterResolver is a field resolver wrapper that forbids
+// the @collection.* and @request.* fields in the non-admin
+// expand `:filter()` modifiers.
+type expandFilterResolver struct {
+	search.FieldResolver
+}
+
+// Resolve implements the [search.FieldResolver] interface.
+func (r *expandFilterResolver) Resolve(field string) (*search.ResolverResult, error) {
+	if strings.HasPrefix(field, "@collection.") || strings.HasPrefix(field, "@request.") {
+		return nil, fmt.Errorf("Only admins can filter by @collection and @request fields, got %q", field)
+	}
+
+	return r.FieldResolver.Resolve(field)
+}
+
+// applyExpandViewRule applies the relCollection view rule constraints
+// to the provided expanded relation records query.
+func applyExpandViewRule(
+	dao *daos.Dao,
+	requestData *models.RequestData,
+	relCollection *models.Collection,
+	q *dbx.SelectQuery,
+) error {
+	if requestData.Admin != nil {
+		if !requestData.Admin.CanViewRecords(relCollection) {
+			return fmt.Errorf("The authorized admin is not allowed to view collection %q records", relCollection.Name)
+		}
+
+		return nil // admins can access everything within their role and scopes
+	}
+
+	if relCollection.ViewRule == nil {
+		return fmt.Errorf("Only admins can view collection %q records", relCollection.Name)
+	}
+
+	if *relCollection.ViewRule != "" {
+		resolver := resolvers.NewRecordFieldResolver(dao, relCollection, requestData, true)
+		expr, err := search.FilterData(*(relCollection.ViewRule)).BuildExpr(resolver)
+		if err != nil {
+			return err
+		}
+		resolver.UpdateQuery(q)
+		q.AndWhere(expr)
+	}
+
+	return nil
+}
+
 // autoIgnoreAuthRecordsEmailVisibility ignores the email visibility check for
 // the provided record if the current auth model is admin, owner or a "manager".
 //
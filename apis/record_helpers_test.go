@@ -171,6 +171,37 @@ func TestRecordAuthResponse(t *testing.T) {
 	}
 }
 
+func TestRecordAuthResponseFields(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	authRecord, err := app.Dao().FindRecordById("users", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/?expand=rel&fields=id,email,expand.rel.id", nil)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := apis.RecordAuthResponse(app, c, authRecord, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	response := rec.Body.String()
+
+	expected := `"record":{"email":"test@example.com","expand":{"rel":{"id":"llvuca81nly1qls"}},"id":"4q1xlclmfloku33"}`
+	if !strings.Contains(response, expected) {
+		t.Fatalf("Expected %s in response \n%v", expected, response)
+	}
+
+	if !strings.Contains(response, `"token":"`) {
+		t.Fatalf("Expected the token to be returned, got \n%v", response)
+	}
+}
+
 func TestEnrichRecords(t *testing.T) {
 	e := echo.New()
 	req := httptest.NewRequest(http.MethodGet, "/?expand=rel_many", nil)
@@ -211,3 +242,28 @@ func TestEnrichRecords(t *testing.T) {
 		}
 	}
 }
+
+func TestEnrichRecordsFields(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/?fields=id,%20text", nil)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	records, err := app.Dao().FindRecordsByIds("demo1", []string{"al1h9ijdeojtsjy", "84nmscqy84lsi1t"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	apis.EnrichRecords(c, app.Dao(), records)
+
+	for _, record := range records {
+		fields := record.ExportFields()
+		if len(fields) != 2 || fields[0] != "id" || fields[1] != "text" {
+			t.Fatalf("Expected export fields [id text], got %v", fields)
+		}
+	}
+}
@@ -0,0 +1,362 @@
+package apis
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/resolvers"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+const (
+	// headerUploadOffset is the tus-like header with the number of
+	// the already received upload bytes.
+	headerUploadOffset = "Upload-Offset"
+
+	// headerUploadLength is the tus-like header with the total upload size.
+	headerUploadLength = "Upload-Length"
+)
+
+// bindRecordUploadApi registers the resumable record file upload api endpoints.
+func bindRecordUploadApi(app core.App, rg *echo.Group) {
+	api := &recordUploadApi{app: app, active: map[string]struct{}{}}
+
+	subGroup := rg.Group(
+		"/collections/:collection/records/:id/uploads",
+		ActivityLogger(app),
+		LoadCollectionContext(app, models.CollectionTypeBase, models.CollectionTypeAuth),
+	)
+
+	subGroup.POST("", api.create, CollectionRateLimit(app, "create"))
+	subGroup.HEAD("/:upload", api.view)
+	subGroup.GET("/:upload", api.view)
+	subGroup.PATCH("/:upload", api.uploadChunk)
+	subGroup.POST("/:upload/complete", api.complete)
+	subGroup.DELETE("/:upload", api.delete)
+}
+
+type recordUploadApi struct {
+	app core.App
+
+	mux    sync.Mutex
+	active map[string]struct{}
+}
+
+func (api *recordUploadApi) create(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("", "Missing collection context.")
+	}
+
+	requestData := RequestData(c)
+
+	record, err := api.findUpdatableRecord(requestData, collection, c.PathParam("id"))
+	if err != nil {
+		return err
+	}
+
+	form := forms.NewRecordUploadCreate(api.app, record)
+	form.SetOwner(requestData.Admin, requestData.AuthRecord)
+
+	// load request
+	if err := c.Bind(form); err != nil {
+		return NewBadRequestError("An error occurred while loading the submitted data.", err)
+	}
+
+	upload, err := form.Submit()
+	if err != nil {
+		return NewBadRequestError("Failed to create the upload session.", err)
+	}
+
+	setUploadHeaders(c, upload)
+
+	return c.JSON(http.StatusOK, upload)
+}
+
+func (api *recordUploadApi) view(c echo.Context) error {
+	upload, err := api.findUpload(c)
+	if err != nil {
+		return err
+	}
+
+	setUploadHeaders(c, upload)
+
+	return c.JSON(http.StatusOK, upload)
+}
+
+func (api *recordUploadApi) uploadChunk(c echo.Context) error {
+	upload, err := api.findUpload(c)
+	if err != nil {
+		return err
+	}
+
+	if !api.lock(upload.Id) {
+		return NewApiError(http.StatusConflict, "The upload is currently processed by another request.", nil)
+	}
+	defer api.unlock(upload.Id)
+
+	// refetch to ensure that the latest offset is used
+	upload, err = api.app.Dao().FindUploadById(upload.Id)
+	if err != nil {
+		return NewNotFoundError("", err)
+	}
+
+	offset, err := strconv.ParseInt(c.Request().Header.Get(headerUploadOffset), 10, 64)
+	if err != nil {
+		return NewBadRequestError("Missing or invalid "+headerUploadOffset+" header.", nil)
+	}
+
+	if offset != upload.Offset {
+		setUploadHeaders(c, upload)
+		return NewApiError(http.StatusConflict, "The "+headerUploadOffset+" header doesn't match the current upload offset.", nil)
+	}
+
+	f, err := os.OpenFile(uploadFilePath(api.app, upload), os.O_WRONLY, 0)
+	if err != nil {
+		return NewBadRequestError("Failed to open the upload file.", err)
+	}
+	defer f.Close()
+
+	// discard any leftovers from previously interrupted writes
+	if err := f.Truncate(upload.Offset); err != nil {
+		return NewBadRequestError("Failed to prepare the upload file.", err)
+	}
+	if _, err := f.Seek(upload.Offset, io.SeekStart); err != nil {
+		return NewBadRequestError("Failed to prepare the upload file.", err)
+	}
+
+	remaining := upload.Size - upload.Offset
+
+	// read one extra byte to detect chunks exceeding the upload size
+	written, copyErr := io.Copy(f, io.LimitReader(c.Request().Body, remaining+1))
+	if written > remaining {
+		f.Truncate(upload.Offset)
+		return NewBadRequestError("The chunk exceeds the upload size.", nil)
+	}
+
+	// persist the received bytes even on interrupted requests
+	// so that the client could resume from the last offset
+	if written > 0 {
+		upload.Offset += written
+		if err := api.app.Dao().SaveUpload(upload); err != nil {
+			return NewBadRequestError("Failed to update the upload offset.", err)
+		}
+	}
+
+	if copyErr != nil {
+		return NewBadRequestError("Failed to read the upload chunk.", copyErr)
+	}
+
+	setUploadHeaders(c, upload)
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (api *recordUploadApi) complete(c echo.Context) error {
+	upload, err := api.findUpload(c)
+	if err != nil {
+		return err
+	}
+
+	if !api.lock(upload.Id) {
+		return NewApiError(http.StatusConflict, "The upload is currently processed by another request.", nil)
+	}
+	defer api.unlock(upload.Id)
+
+	// refetch to ensure that the latest offset is used
+	upload, err = api.app.Dao().FindUploadById(upload.Id)
+	if err != nil {
+		return NewNotFoundError("", err)
+	}
+
+	if !upload.IsComplete() {
+		return NewBadRequestError("The upload is not complete yet.", nil)
+	}
+
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+
+	requestData := RequestData(c)
+
+	record, err := api.findUpdatableRecord(requestData, collection, upload.RecordId)
+	if err != nil {
+		return err
+	}
+
+	file, err := filesystem.NewFileFromPath(uploadFilePath(api.app, upload))
+	if err != nil {
+		return NewBadRequestError("Failed to load the upload file.", err)
+	}
+
+	form := forms.NewRecordUpsert(api.app, record)
+	form.SetFullManageAccess(requestData.Admin != nil || hasAuthManageAccess(api.app.Dao(), record, requestData))
+
+	if err := form.AddFiles(upload.Field, file); err != nil {
+		return NewBadRequestError("Failed to load the upload file.", err)
+	}
+
+	event := new(core.RecordUpdateEvent)
+	event.HttpContext = c
+	event.Collection = collection
+	event.Record = record
+	event.UploadedFiles = form.FilesToUpload()
+
+	// update the record
+	submitErr := form.Submit(func(next forms.InterceptorNextFunc[*models.Record]) forms.InterceptorNextFunc[*models.Record] {
+		return func(m *models.Record) error {
+			event.Record = m
+
+			return api.app.OnRecordBeforeUpdateRequest().Trigger(event, func(e *core.RecordUpdateEvent) error {
+				if err := next(e.Record); err != nil {
+					return NewBadRequestError("Failed to update record.", err)
+				}
+
+				if err := EnrichRecord(e.HttpContext, api.app.Dao(), e.Record); err != nil && api.app.IsDebug() {
+					log.Println(err)
+				}
+
+				return e.HttpContext.JSON(http.StatusOK, e.Record)
+			})
+		}
+	})
+
+	if submitErr == nil {
+		if err := core.DeleteUpload(api.app, upload); err != nil && api.app.IsDebug() {
+			log.Println(err)
+		}
+
+		if err := api.app.OnRecordAfterUpdateRequest().Trigger(event); err != nil && api.app.IsDebug() {
+			log.Println(err)
+		}
+	}
+
+	return submitErr
+}
+
+func (api *recordUploadApi) delete(c echo.Context) error {
+	upload, err := api.findUpload(c)
+	if err != nil {
+		return err
+	}
+
+	if !api.lock(upload.Id) {
+		return NewApiError(http.StatusConflict, "The upload is currently processed by another request.", nil)
+	}
+	defer api.unlock(upload.Id)
+
+	if err := core.DeleteUpload(api.app, upload); err != nil {
+		return NewBadRequestError("Failed to delete the upload session.", err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// findUpload returns the request upload session
+// if it is owned by the current requester.
+//
+// Note that the request body is not read because
+// for chunk requests it contains the raw file bytes.
+func (api *recordUploadApi) findUpload(c echo.Context) (*models.Upload, error) {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return nil, NewNotFoundError("", "Missing collection context.")
+	}
+
+	upload, err := api.app.Dao().FindUploadById(c.PathParam("upload"))
+	if err != nil {
+		return nil, NewNotFoundError("", err)
+	}
+
+	admin, _ := c.Get(ContextAdminKey).(*models.Admin)
+	authRecord, _ := c.Get(ContextAuthRecordKey).(*models.Record)
+
+	if upload.CollectionId != collection.Id ||
+		upload.RecordId != c.PathParam("id") ||
+		!upload.IsOwner(admin, authRecord) {
+		return nil, NewNotFoundError("", nil)
+	}
+
+	return upload, nil
+}
+
+// findUpdatableRecord returns the record with the specified id
+// if the requester is allowed to update it.
+func (api *recordUploadApi) findUpdatableRecord(
+	requestData *models.RequestData,
+	collection *models.Collection,
+	recordId string,
+) (*models.Record, error) {
+	if err := checkAdminRecordsAccess(requestData, collection, true); err != nil {
+		return nil, err
+	}
+
+	if requestData.Admin == nil && collection.UpdateRule == nil {
+		// only admins can access if the rule is nil
+		return nil, NewForbiddenError("Only admins can perform this action.", nil)
+	}
+
+	ruleFunc := func(q *dbx.SelectQuery) error {
+		if requestData.Admin == nil && collection.UpdateRule != nil && *collection.UpdateRule != "" {
+			resolver := resolvers.NewRecordFieldResolver(api.app.Dao(), collection, requestData, true)
+			expr, err := search.FilterData(*collection.UpdateRule).BuildExpr(resolver)
+			if err != nil {
+				return err
+			}
+			resolver.UpdateQuery(q)
+			q.AndWhere(expr)
+		}
+		return nil
+	}
+
+	record, err := api.app.Dao().FindRecordById(collection.Id, recordId, ruleFunc)
+	if err != nil || record == nil {
+		return nil, NewNotFoundError("", err)
+	}
+
+	return record, nil
+}
+
+// lock marks the upload session as being processed.
+//
+// Returns false if the upload session is already locked by another request.
+func (api *recordUploadApi) lock(uploadId string) bool {
+	api.mux.Lock()
+	defer api.mux.Unlock()
+
+	if _, ok := api.active[uploadId]; ok {
+		return false
+	}
+
+	api.active[uploadId] = struct{}{}
+
+	return true
+}
+
+// unlock releases the upload session processing lock.
+func (api *recordUploadApi) unlock(uploadId string) {
+	api.mux.Lock()
+	defer api.mux.Unlock()
+
+	delete(api.active, uploadId)
+}
+
+func uploadFilePath(app core.App, upload *models.Upload) string {
+	return filepath.Join(core.LocalUploadDir(app, upload.Id), upload.Filename)
+}
+
+func setUploadHeaders(c echo.Context, upload *models.Upload) {
+	header := c.Response().Header()
+	header.Set(headerUploadOffset, strconv.FormatInt(upload.Offset, 10))
+	header.Set(headerUploadLength, strconv.FormatInt(upload.Size, 10))
+	header.Set("Cache-Control", "no-store")
+}
@@ -0,0 +1,358 @@
+package apis_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/settings"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tokens"
+)
+
+func TestRecordUploadCreate(t *testing.T) {
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "guest",
+			Method:          http.MethodPost,
+			Url:             "/api/collections/users/records/4q1xlclmfloku33/uploads",
+			Body:            strings.NewReader(`{"field":"file","filename":"test.txt","size":10}`),
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "auth record that doesn't satisfy the update rule",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/records/oap640cot4yru2s/uploads",
+			Body:   strings.NewReader(`{"field":"file","filename":"test.txt","size":10}`),
+			RequestHeaders: map[string]string{
+				"Authorization": testRecordToken,
+			},
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "view collection",
+			Method: http.MethodPost,
+			Url:    "/api/collections/view1/records/84nmscqy84lsi1t/uploads",
+			Body:   strings.NewReader(`{"field":"file_one","filename":"test.txt","size":10}`),
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "invalid data",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/records/4q1xlclmfloku33/uploads",
+			Body:   strings.NewReader(`{"field":"name","filename":"../test.txt","size":0}`),
+			RequestHeaders: map[string]string{
+				"Authorization": testRecordToken,
+			},
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"field":{"code":"validation_invalid_file_field"`,
+				`"filename":{"code":"validation_invalid_filename"`,
+				`"size":{"code":"validation_required"`,
+			},
+		},
+		{
+			Name:   "auth record that satisfies the update rule",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/records/4q1xlclmfloku33/uploads",
+			Body:   strings.NewReader(`{"field":"file","filename":"test.txt","size":10}`),
+			RequestHeaders: map[string]string{
+				"Authorization": testRecordToken,
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"id":"`,
+				`"collectionId":"_pb_users_auth_"`,
+				`"recordId":"4q1xlclmfloku33"`,
+				`"field":"file"`,
+				`"filename":"test.txt"`,
+				`"size":10`,
+				`"offset":0`,
+			},
+			NotExpectedContent: []string{`"ownerId"`},
+			ExpectedEvents: map[string]int{
+				"OnModelBeforeCreate": 1,
+				"OnModelAfterCreate":  1,
+			},
+		},
+		{
+			Name:   "admin",
+			Method: http.MethodPost,
+			Url:    "/api/collections/demo1/records/al1h9ijdeojtsjy/uploads",
+			Body:   strings.NewReader(`{"field":"file_many","filename":"test.txt","size":10}`),
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"collectionId":"wsmn24bux7wo113"`,
+				`"recordId":"al1h9ijdeojtsjy"`,
+				`"field":"file_many"`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnModelBeforeCreate": 1,
+				"OnModelAfterCreate":  1,
+			},
+		},
+		{
+			Name:   "exceeded owner upload sessions limit",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/records/4q1xlclmfloku33/uploads",
+			Body:   strings.NewReader(`{"field":"file","filename":"test.txt","size":10}`),
+			RequestHeaders: map[string]string{
+				"Authorization": testRecordToken,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				upload := &models.Upload{
+					CollectionId:      "_pb_users_auth_",
+					RecordId:          "4q1xlclmfloku33",
+					Field:             "file",
+					Filename:          "test.txt",
+					Size:              core.UploadMaxBytesPerOwner,
+					OwnerCollectionId: "_pb_users_auth_",
+					OwnerId:           "4q1xlclmfloku33",
+				}
+				if err := app.Dao().SaveUpload(upload); err != nil {
+					t.Fatal(err)
+				}
+				app.ResetEventCalls()
+			},
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"size":{"code":"validation_upload_size_limit"`,
+			},
+		},
+		{
+			Name:   "rate limited",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/records/4q1xlclmfloku33/uploads",
+			Body:   strings.NewReader(`{"field":"file","filename":"test.txt","size":10}`),
+			RequestHeaders: map[string]string{
+				"Authorization": testRecordToken,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				app.Settings().RateLimits.Enabled = true
+				app.Settings().RateLimits.Rules = []settings.RateLimitRule{
+					{Label: "users:create", MaxRequests: 1, Duration: 60},
+				}
+
+				req := httptest.NewRequest(http.MethodPost, "/api/collections/users/records/4q1xlclmfloku33/uploads", strings.NewReader(`{}`))
+				req.Header.Set("Authorization", testRecordToken)
+				e.ServeHTTP(httptest.NewRecorder(), req)
+				app.ResetEventCalls()
+			},
+			ExpectedStatus:  429,
+			ExpectedContent: []string{`"data":{}`},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestRecordUploadFlow(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	e, err := apis.InitApi(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := app.Dao().FindRecordById("users", "oap640cot4yru2s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherToken, err := tokens.NewRecordAuthToken(app, other)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := serveUploadRequest(e, http.MethodPost, "", testRecordToken, nil, `{"field":"file","filename":"test.txt","size":11}`)
+	if res.Code != 200 {
+		t.Fatalf("Expected create status 200, got %d (%s)", res.Code, res.Body.String())
+	}
+	upload := &models.Upload{}
+	if err := json.Unmarshal(res.Body.Bytes(), upload); err != nil {
+		t.Fatal(err)
+	}
+	uploadUrl := "/" + upload.Id
+
+	// non-owner access
+	res = serveUploadRequest(e, http.MethodGet, uploadUrl, otherToken, nil, "")
+	if res.Code != 404 {
+		t.Fatalf("Expected non-owner status 404, got %d", res.Code)
+	}
+	res = serveUploadRequest(e, http.MethodPatch, uploadUrl, "", map[string]string{"Upload-Offset": "0"}, "hello ")
+	if res.Code != 404 {
+		t.Fatalf("Expected guest chunk status 404, got %d", res.Code)
+	}
+
+	// missing and mismatched offset
+	res = serveUploadRequest(e, http.MethodPatch, uploadUrl, testRecordToken, nil, "hello ")
+	if res.Code != 400 {
+		t.Fatalf("Expected missing offset status 400, got %d", res.Code)
+	}
+	res = serveUploadRequest(e, http.MethodPatch, uploadUrl, testRecordToken, map[string]string{"Upload-Offset": "5"}, "hello ")
+	if res.Code != 409 || res.Header().Get("Upload-Offset") != "0" {
+		t.Fatalf("Expected mismatched offset status 409 and offset 0, got %d (%q)", res.Code, res.Header().Get("Upload-Offset"))
+	}
+
+	// first chunk
+	res = serveUploadRequest(e, http.MethodPatch, uploadUrl, testRecordToken, map[string]string{"Upload-Offset": "0"}, "hello ")
+	if res.Code != 204 || res.Header().Get("Upload-Offset") != "6" {
+		t.Fatalf("Expected first chunk status 204 and offset 6, got %d (%q)", res.Code, res.Header().Get("Upload-Offset"))
+	}
+
+	// progress
+	res = serveUploadRequest(e, http.MethodHead, uploadUrl, testRecordToken, nil, "")
+	if res.Code != 200 || res.Header().Get("Upload-Offset") != "6" || res.Header().Get("Upload-Length") != "11" {
+		t.Fatalf("Expected progress status 200, offset 6 and length 11, got %d (%v)", res.Code, res.Header())
+	}
+
+	// incomplete upload
+	res = serveUploadRequest(e, http.MethodPost, uploadUrl+"/complete", testRecordToken, nil, "")
+	if res.Code != 400 {
+		t.Fatalf("Expected incomplete upload status 400, got %d", res.Code)
+	}
+
+	// chunk bigger than the remaining size
+	res = serveUploadRequest(e, http.MethodPatch, uploadUrl, testRecordToken, map[string]string{"Upload-Offset": "6"}, "world!!")
+	if res.Code != 400 {
+		t.Fatalf("Expected oversized chunk status 400, got %d", res.Code)
+	}
+
+	// last chunk
+	res = serveUploadRequest(e, http.MethodPatch, uploadUrl, testRecordToken, map[string]string{"Upload-Offset": "6"}, "world")
+	if res.Code != 204 || res.Header().Get("Upload-Offset") != "11" {
+		t.Fatalf("Expected last chunk status 204 and offset 11, got %d (%q)", res.Code, res.Header().Get("Upload-Offset"))
+	}
+
+	app.ResetEventCalls()
+
+	// finalize
+	res = serveUploadRequest(e, http.MethodPost, uploadUrl+"/complete", testRecordToken, nil, "")
+	if res.Code != 200 {
+		t.Fatalf("Expected complete status 200, got %d (%s)", res.Code, res.Body.String())
+	}
+	for _, event := range []string{"OnRecordBeforeUpdateRequest", "OnRecordAfterUpdateRequest"} {
+		if app.EventCalls[event] != 1 {
+			t.Errorf("Expected %s to be called once, got %d", event, app.EventCalls[event])
+		}
+	}
+
+	record, err := app.Dao().FindRecordById("users", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := record.GetStringSlice("file")
+	if len(files) == 0 || !strings.HasPrefix(files[len(files)-1], "test_") {
+		t.Fatalf("Expected the uploaded file to be appended, got %v", files)
+	}
+
+	fs, err := app.NewFilesystem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	r, err := fs.GetFile(record.BaseFilesPath() + "/" + files[len(files)-1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	content, _ := io.ReadAll(r)
+	if string(content) != "hello world" {
+		t.Fatalf("Expected the stored file content to be %q, got %q", "hello world", content)
+	}
+
+	if _, err := app.Dao().FindUploadById(upload.Id); err == nil {
+		t.Fatal("Expected the completed upload to be deleted")
+	}
+	if _, err := os.Stat(core.LocalUploadDir(app, upload.Id)); !os.IsNotExist(err) {
+		t.Fatalf("Expected the completed upload dir to be deleted, got %v", err)
+	}
+}
+
+func TestRecordUploadCompleteValidation(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	e, err := apis.InitApi(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the avatar field accepts only images
+	res := serveUploadRequest(e, http.MethodPost, "", testRecordToken, nil, `{"field":"avatar","filename":"test.png","size":4}`)
+	if res.Code != 200 {
+		t.Fatalf("Expected create status 200, got %d (%s)", res.Code, res.Body.String())
+	}
+	upload := &models.Upload{}
+	if err := json.Unmarshal(res.Body.Bytes(), upload); err != nil {
+		t.Fatal(err)
+	}
+
+	res = serveUploadRequest(e, http.MethodPatch, "/"+upload.Id, testRecordToken, map[string]string{"Upload-Offset": "0"}, "test")
+	if res.Code != 204 {
+		t.Fatalf("Expected chunk status 204, got %d", res.Code)
+	}
+
+	res = serveUploadRequest(e, http.MethodPost, "/"+upload.Id+"/complete", testRecordToken, nil, "")
+	if res.Code != 400 || !strings.Contains(res.Body.String(), `"avatar":{"code":"validation_invalid_mime_type"`) {
+		t.Fatalf("Expected complete status 400 with avatar mime type error, got %d (%s)", res.Code, res.Body.String())
+	}
+
+	// the upload session should remain so that it could be manually deleted
+	if _, err := app.Dao().FindUploadById(upload.Id); err != nil {
+		t.Fatalf("Expected the upload to remain, got %v", err)
+	}
+
+	res = serveUploadRequest(e, http.MethodDelete, "/"+upload.Id, testRecordToken, nil, "")
+	if res.Code != 204 {
+		t.Fatalf("Expected delete status 204, got %d", res.Code)
+	}
+	if _, err := app.Dao().FindUploadById(upload.Id); err == nil {
+		t.Fatal("Expected the upload to be deleted")
+	}
+	if _, err := os.Stat(core.LocalUploadDir(app, upload.Id)); !os.IsNotExist(err) {
+		t.Fatalf("Expected the upload dir to be deleted, got %v", err)
+	}
+}
+
+// serveUploadRequest sends a test request to the
+// users "4q1xlclmfloku33" record uploads api.
+func serveUploadRequest(e *echo.Echo, method string, path string, token string, headers map[string]string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/api/collections/users/records/4q1xlclmfloku33/uploads"+path, strings.NewReader(body))
+	if method == http.MethodPost {
+		req.Header.Set("Content-Type", "application/json")
+	} else {
+		req.Header.Set("Content-Type", "application/offset+octet-stream")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	return rec
+}
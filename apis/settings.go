@@ -9,6 +9,7 @@ import (
 	"github.com/labstack/echo/v5"
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
 	"github.com/pocketbase/pocketbase/models/settings"
 	"github.com/pocketbase/pocketbase/tools/security"
 )
@@ -17,7 +18,7 @@ import (
 func bindSettingsApi(app core.App, rg *echo.Group) {
 	api := settingsApi{app: app}
 
-	subGroup := rg.Group("/settings", ActivityLogger(app), RequireAdminAuth())
+	subGroup := rg.Group("/settings", ActivityLogger(app), RequireAdminAuth(models.AdminRoleSettings))
 	subGroup.GET("", api.list)
 	subGroup.PATCH("", api.set)
 	subGroup.POST("/test/s3", api.testS3)
@@ -0,0 +1,190 @@
+package apis
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/routine"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/pocketbase/pocketbase/webhooks"
+)
+
+// webhooksRetryBatchSize is the max number of due webhook
+// deliveries that are resent with a single retry cron run.
+const webhooksRetryBatchSize = 100
+
+// bindWebhookApi registers the webhook api endpoints and
+// the record events webhook deliveries.
+func bindWebhookApi(app core.App, rg *echo.Group) {
+	api := webhookApi{app: app}
+
+	subGroup := rg.Group("/webhooks", ActivityLogger(app), RequireAdminAuth(models.AdminRoleSuperuser))
+	subGroup.GET("", api.list)
+	subGroup.POST("", api.create)
+	subGroup.GET("/:id", api.view)
+	subGroup.PATCH("/:id", api.update)
+	subGroup.DELETE("/:id", api.delete)
+
+	api.bindEvents()
+}
+
+type webhookApi struct {
+	app core.App
+}
+
+func (api *webhookApi) list(c echo.Context) error {
+	fieldResolver := search.NewSimpleFieldResolver(
+		"id", "created", "updated", "name", "url", "collections", "actions", "disabled",
+	)
+
+	result, err := search.NewProvider(fieldResolver).
+		Query(api.app.Dao().WebhookQuery()).
+		ParseAndExec(c.QueryParams().Encode(), &[]*models.Webhook{})
+
+	if err != nil {
+		return NewBadRequestError("", err)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+func (api *webhookApi) view(c echo.Context) error {
+	id := c.PathParam("id")
+	if id == "" {
+		return NewNotFoundError("", nil)
+	}
+
+	webhook, err := api.app.Dao().FindWebhookById(id)
+	if err != nil || webhook == nil {
+		return NewNotFoundError("", err)
+	}
+
+	return c.JSON(http.StatusOK, webhook)
+}
+
+func (api *webhookApi) create(c echo.Context) error {
+	webhook := &models.Webhook{}
+
+	form := forms.NewWebhookUpsert(api.app, webhook)
+
+	// load request
+	if err := c.Bind(form); err != nil {
+		return NewBadRequestError("An error occurred while loading the submitted data.", err)
+	}
+
+	if err := form.Submit(); err != nil {
+		return NewBadRequestError("Failed to create webhook.", err)
+	}
+
+	return c.JSON(http.StatusOK, webhook)
+}
+
+func (api *webhookApi) update(c echo.Context) error {
+	id := c.PathParam("id")
+	if id == "" {
+		return NewNotFoundError("", nil)
+	}
+
+	webhook, err := api.app.Dao().FindWebhookById(id)
+	if err != nil || webhook == nil {
+		return NewNotFoundError("", err)
+	}
+
+	form := forms.NewWebhookUpsert(api.app, webhook)
+
+	// load request
+	if err := c.Bind(form); err != nil {
+		return NewBadRequestError("An error occurred while loading the submitted data.", err)
+	}
+
+	if err := form.Submit(); err != nil {
+		return NewBadRequestError("Failed to update webhook.", err)
+	}
+
+	return c.JSON(http.StatusOK, webhook)
+}
+
+func (api *webhookApi) delete(c echo.Context) error {
+	id := c.PathParam("id")
+	if id == "" {
+		return NewNotFoundError("", nil)
+	}
+
+	webhook, err := api.app.Dao().FindWebhookById(id)
+	if err != nil || webhook == nil {
+		return NewNotFoundError("", err)
+	}
+
+	if err := api.app.Dao().DeleteWebhook(webhook); err != nil {
+		return NewBadRequestError("Failed to delete webhook.", err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (api *webhookApi) bindEvents() {
+	api.app.OnModelAfterCreate().Add(func(e *core.ModelEvent) error {
+		if record, ok := e.Model.(*models.Record); ok && record != nil {
+			api.deliver(models.WebhookActionCreate, record)
+		}
+		return nil
+	})
+
+	api.app.OnModelAfterUpdate().Add(func(e *core.ModelEvent) error {
+		if record, ok := e.Model.(*models.Record); ok && record != nil {
+			api.deliver(models.WebhookActionUpdate, record)
+		}
+		return nil
+	})
+
+	api.app.OnModelAfterDelete().Add(func(e *core.ModelEvent) error {
+		if record, ok := e.Model.(*models.Record); ok && record != nil {
+			api.deliver(models.WebhookActionDelete, record)
+		}
+		return nil
+	})
+
+	api.app.OnRecordAuthRequest().Add(func(e *core.RecordAuthEvent) error {
+		if e.Record != nil {
+			api.deliver(models.WebhookActionAuth, e.Record)
+		}
+		return nil
+	})
+
+	// periodically resend the due pending deliveries
+	// (failed attempts are rescheduled with exponential backoff)
+	api.app.Cron().MustAdd("__pbWebhooksRetry__", "* * * * *", func() {
+		if !api.app.IsBootstrapped() {
+			return
+		}
+
+		if err := webhooks.ProcessDue(api.app, webhooksRetryBatchSize); err != nil && api.app.IsDebug() {
+			log.Println(err)
+		}
+	})
+}
+
+// deliver queues the record action for all matching webhooks
+// and tries to send the created deliveries in the background.
+//
+// The webhook errors are non critical and are only logged in debug mode.
+func (api *webhookApi) deliver(action string, record *models.Record) {
+	deliveries, err := webhooks.Enqueue(api.app, action, record)
+	if err != nil && api.app.IsDebug() {
+		log.Println(err)
+	}
+
+	for _, delivery := range deliveries {
+		delivery := delivery
+
+		routine.FireAndForget(func() {
+			if err := webhooks.Send(api.app, delivery); err != nil && api.app.IsDebug() {
+				log.Println(err)
+			}
+		})
+	}
+}
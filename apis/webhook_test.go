@@ -0,0 +1,520 @@
+package apis_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/webhooks"
+)
+
+func TestWebhooksList(t *testing.T) {
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "unauthorized",
+			Method:          http.MethodGet,
+			Url:             "/api/webhooks",
+			ExpectedStatus:  401,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "authorized as auth record",
+			Method: http.MethodGet,
+			Url:    "/api/webhooks",
+			RequestHeaders: map[string]string{
+				"Authorization": testRecordToken,
+			},
+			ExpectedStatus:  401,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "authorized as non superuser admin",
+			Method: http.MethodGet,
+			Url:    "/api/webhooks",
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				setTestAdminRole(t, app, "sywbhecnh46rhm0", models.AdminRoleSettings)
+				app.ResetEventCalls()
+			},
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "authorized as admin",
+			Method: http.MethodGet,
+			Url:    "/api/webhooks",
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"page":1`,
+				`"perPage":30`,
+				`"totalItems":2`,
+				`"id":"ftxuvq1ojf4b2pa"`,
+				`"id":"kbbx2t0dpzpuddx"`,
+			},
+			NotExpectedContent: []string{
+				`"secret"`,
+				`test_webhook_secret`,
+			},
+		},
+		{
+			Name:   "authorized as admin + filter",
+			Method: http.MethodGet,
+			Url:    "/api/webhooks?filter=actions~'auth'",
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"totalItems":1`,
+				`"id":"kbbx2t0dpzpuddx"`,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestWebhookView(t *testing.T) {
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "unauthorized",
+			Method:          http.MethodGet,
+			Url:             "/api/webhooks/ftxuvq1ojf4b2pa",
+			ExpectedStatus:  401,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "authorized as auth record",
+			Method: http.MethodGet,
+			Url:    "/api/webhooks/ftxuvq1ojf4b2pa",
+			RequestHeaders: map[string]string{
+				"Authorization": testRecordToken,
+			},
+			ExpectedStatus:  401,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "authorized as admin + missing webhook",
+			Method: http.MethodGet,
+			Url:    "/api/webhooks/missing",
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "authorized as admin + existing webhook",
+			Method: http.MethodGet,
+			Url:    "/api/webhooks/ftxuvq1ojf4b2pa",
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"id":"ftxuvq1ojf4b2pa"`,
+				`"name":"demo2 changes"`,
+				`"url":"https://example.com/hooks/demo2"`,
+				`"collections":["demo2"]`,
+				`"actions":["create","update","delete"]`,
+				`"disabled":true`,
+			},
+			NotExpectedContent: []string{
+				`"secret"`,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestWebhookCreate(t *testing.T) {
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "unauthorized",
+			Method:          http.MethodPost,
+			Url:             "/api/webhooks",
+			Body:            strings.NewReader(`{"name":"test","url":"https://example.com","actions":["create"],"secret":"1234567890"}`),
+			ExpectedStatus:  401,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "authorized as non superuser admin",
+			Method: http.MethodPost,
+			Url:    "/api/webhooks",
+			Body:   strings.NewReader(`{"name":"test","url":"https://example.com","actions":["create"],"secret":"1234567890"}`),
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				setTestAdminRole(t, app, "sywbhecnh46rhm0", models.AdminRoleCollections)
+				app.ResetEventCalls()
+			},
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "authorized as admin + invalid data",
+			Method: http.MethodPost,
+			Url:    "/api/webhooks",
+			Body:   strings.NewReader(`{"url":"invalid","collections":["missing"],"actions":["invalid"],"secret":"123"}`),
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"data":{`,
+				`"name":{"code":"validation_required"`,
+				`"url":{"code":"validation_is_url"`,
+				`"collections":{"0":{"code":"validation_missing_collection"`,
+				`"actions":{"0":{"code":"validation_in_invalid"`,
+				`"secret":{"code":"validation_length_out_of_range"`,
+			},
+		},
+		{
+			Name:   "authorized as admin + valid data",
+			Method: http.MethodPost,
+			Url:    "/api/webhooks",
+			Body:   strings.NewReader(`{"name":"test","url":"https://example.com/new","collections":["demo1"],"actions":["create","auth"],"secret":"1234567890"}`),
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"id":`,
+				`"name":"test"`,
+				`"url":"https://example.com/new"`,
+				`"collections":["demo1"]`,
+				`"actions":["create","auth"]`,
+				`"disabled":false`,
+			},
+			NotExpectedContent: []string{
+				`"secret"`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnModelBeforeCreate": 1,
+				"OnModelAfterCreate":  1,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestWebhookUpdate(t *testing.T) {
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "unauthorized",
+			Method:          http.MethodPatch,
+			Url:             "/api/webhooks/ftxuvq1ojf4b2pa",
+			Body:            strings.NewReader(`{"name":"test_update"}`),
+			ExpectedStatus:  401,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "authorized as auth record",
+			Method: http.MethodPatch,
+			Url:    "/api/webhooks/ftxuvq1ojf4b2pa",
+			Body:   strings.NewReader(`{"name":"test_update"}`),
+			RequestHeaders: map[string]string{
+				"Authorization": testRecordToken,
+			},
+			ExpectedStatus:  401,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "authorized as admin + missing webhook",
+			Method: http.MethodPatch,
+			Url:    "/api/webhooks/missing",
+			Body:   strings.NewReader(`{"name":"test_update"}`),
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "authorized as admin + invalid data",
+			Method: http.MethodPatch,
+			Url:    "/api/webhooks/ftxuvq1ojf4b2pa",
+			Body:   strings.NewReader(`{"url":"","actions":[]}`),
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"url":{"code":"validation_required"`,
+				`"actions":{"code":"validation_required"`,
+			},
+		},
+		{
+			Name:   "authorized as admin + valid data",
+			Method: http.MethodPatch,
+			Url:    "/api/webhooks/ftxuvq1ojf4b2pa",
+			Body:   strings.NewReader(`{"name":"test_update","actions":["delete"],"disabled":false}`),
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"id":"ftxuvq1ojf4b2pa"`,
+				`"name":"test_update"`,
+				`"collections":["demo2"]`,
+				`"actions":["delete"]`,
+				`"disabled":false`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnModelBeforeUpdate": 1,
+				"OnModelAfterUpdate":  1,
+			},
+			AfterTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				webhook, err := app.Dao().FindWebhookById("ftxuvq1ojf4b2pa")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if webhook.Secret != "test_webhook_secret" {
+					t.Fatalf("Expected the secret to remain unchanged, got %q", webhook.Secret)
+				}
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestWebhookDelete(t *testing.T) {
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "unauthorized",
+			Method:          http.MethodDelete,
+			Url:             "/api/webhooks/ftxuvq1ojf4b2pa",
+			ExpectedStatus:  401,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "authorized as auth record",
+			Method: http.MethodDelete,
+			Url:    "/api/webhooks/ftxuvq1ojf4b2pa",
+			RequestHeaders: map[string]string{
+				"Authorization": testRecordToken,
+			},
+			ExpectedStatus:  401,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "authorized as admin + missing webhook",
+			Method: http.MethodDelete,
+			Url:    "/api/webhooks/missing",
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "authorized as admin + existing webhook",
+			Method: http.MethodDelete,
+			Url:    "/api/webhooks/ftxuvq1ojf4b2pa",
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			ExpectedStatus: 204,
+			ExpectedEvents: map[string]int{
+				"OnModelBeforeDelete": 1,
+				"OnModelAfterDelete":  1,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestWebhookRecordEventsDelivery(t *testing.T) {
+	var mux sync.Mutex
+	received := []*http.Request{}
+	bodies := []string{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		mux.Lock()
+		received = append(received, r)
+		bodies = append(bodies, string(body))
+		mux.Unlock()
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	enableWebhook := func(t *testing.T, app *tests.TestApp, id string) {
+		webhook, err := app.Dao().FindWebhookById(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		webhook.Url = server.URL
+		webhook.Disabled = false
+		if err := app.Dao().SaveWebhook(webhook); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reset := func() {
+		mux.Lock()
+		received = received[:0]
+		bodies = bodies[:0]
+		mux.Unlock()
+	}
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:   "record update",
+			Method: http.MethodPatch,
+			Url:    "/api/collections/demo2/records/llvuca81nly1qls",
+			Body:   strings.NewReader(`{"title":"webhook_test"}`),
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			Delay: 100 * time.Millisecond,
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				reset()
+				enableWebhook(t, app, "ftxuvq1ojf4b2pa")
+				app.ResetEventCalls()
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"title":"webhook_test"`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnRecordBeforeUpdateRequest": 1,
+				"OnRecordAfterUpdateRequest":  1,
+				"OnModelBeforeUpdate":         1,
+				"OnModelAfterUpdate":          1,
+			},
+			AfterTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				mux.Lock()
+				defer mux.Unlock()
+
+				if len(received) != 1 {
+					t.Fatalf("Expected 1 webhook request, got %d", len(received))
+				}
+
+				r := received[0]
+				timestamp, _ := strconv.ParseInt(r.Header.Get(webhooks.HeaderTimestamp), 10, 64)
+				expectedSignature := webhooks.Sign("test_webhook_secret", timestamp, []byte(bodies[0]))
+				if r.Header.Get(webhooks.HeaderSignature) != expectedSignature {
+					t.Fatalf("Expected signature %q, got %q", expectedSignature, r.Header.Get(webhooks.HeaderSignature))
+				}
+
+				if !strings.HasPrefix(bodies[0], `{"action":"update","record":{`) ||
+					!strings.Contains(bodies[0], `"id":"llvuca81nly1qls"`) ||
+					!strings.Contains(bodies[0], `"title":"webhook_test"`) {
+					t.Fatalf("Unexpected webhook payload %s", bodies[0])
+				}
+
+				delivery, err := app.LogsDao().FindWebhookDeliveryById(r.Header.Get(webhooks.HeaderId))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if delivery.Status != models.WebhookDeliveryStatusSuccess || delivery.Attempts != 1 {
+					t.Fatalf("Expected successful delivery log, got %v", delivery)
+				}
+			},
+		},
+		{
+			Name:   "non subscribed collection",
+			Method: http.MethodPatch,
+			Url:    "/api/collections/demo1/records/84nmscqy84lsi1t",
+			Body:   strings.NewReader(`{"text":"webhook_test"}`),
+			RequestHeaders: map[string]string{
+				"Authorization": testAdminToken,
+			},
+			Delay: 100 * time.Millisecond,
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				reset()
+				enableWebhook(t, app, "ftxuvq1ojf4b2pa")
+				app.ResetEventCalls()
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"text":"webhook_test"`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnRecordBeforeUpdateRequest": 1,
+				"OnRecordAfterUpdateRequest":  1,
+				"OnModelBeforeUpdate":         1,
+				"OnModelAfterUpdate":          1,
+			},
+			AfterTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				mux.Lock()
+				defer mux.Unlock()
+
+				if len(received) != 0 {
+					t.Fatalf("Expected no webhook requests, got %d", len(received))
+				}
+			},
+		},
+		{
+			Name:   "auth record authentication",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/auth-with-password",
+			Body:   strings.NewReader(`{"identity":"test@example.com","password":"1234567890"}`),
+			Delay:  100 * time.Millisecond,
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				reset()
+				enableWebhook(t, app, "kbbx2t0dpzpuddx")
+				app.ResetEventCalls()
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"token":`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnRecordBeforeAuthWithPasswordRequest": 1,
+				"OnRecordAfterAuthWithPasswordRequest":  1,
+				"OnRecordAuthRequest":                   1,
+			},
+			AfterTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				mux.Lock()
+				defer mux.Unlock()
+
+				if len(received) != 1 {
+					t.Fatalf("Expected 1 webhook request, got %d", len(received))
+				}
+
+				// the email should be included regardless of the emailVisibility state
+				if !strings.HasPrefix(bodies[0], `{"action":"auth","record":{`) ||
+					!strings.Contains(bodies[0], `"email":"test@example.com"`) {
+					t.Fatalf("Unexpected webhook payload %s", bodies[0])
+				}
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
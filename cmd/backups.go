@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/fatih/color"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/spf13/cobra"
+)
+
+// NewBackupsCommand creates and returns new command for managing
+// the app backups (create, restore, list).
+func NewBackupsCommand(app core.App) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "backups",
+		Short: "Manages the pb_data backups",
+	}
+
+	command.AddCommand(backupsCreateCommand(app))
+	command.AddCommand(backupsRestoreCommand(app))
+	command.AddCommand(backupsListCommand(app))
+
+	return command
+}
+
+func backupsCreateCommand(app core.App) *cobra.Command {
+	return &cobra.Command{
+		Use:     "create [name]",
+		Example: "backups create my_backup.zip",
+		Short:   "Creates a new pb_data backup (the name is autogenerated if not set)",
+		Args:    cobra.MaximumNArgs(1),
+		Run: func(command *cobra.Command, args []string) {
+			form := forms.NewBackupCreate(app)
+			if len(args) > 0 {
+				form.Name = args[0]
+			}
+
+			if err := form.Submit(); err != nil {
+				color.Red("Failed to create backup: %v", err)
+				return
+			}
+
+			color.Green("Successfully created backup %q!", form.Name)
+		},
+	}
+}
+
+func backupsRestoreCommand(app core.App) *cobra.Command {
+	return &cobra.Command{
+		Use:     "restore",
+		Example: "backups restore my_backup.zip",
+		Short:   "Replaces the current pb_data with the one from the specified backup",
+		Args:    cobra.ExactArgs(1),
+		Run: func(command *cobra.Command, args []string) {
+			if err := app.RestoreBackup(context.Background(), args[0]); err != nil {
+				color.Red("Failed to restore backup: %v", err)
+				return
+			}
+
+			color.Green("Successfully restored backup %q!", args[0])
+		},
+	}
+}
+
+func backupsListCommand(app core.App) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "Lists all available pb_data backups",
+		Run: func(command *cobra.Command, args []string) {
+			fsys, err := app.NewBackupsFilesystem()
+			if err != nil {
+				color.Red("Failed to load backups filesystem: %v", err)
+				return
+			}
+			defer fsys.Close()
+
+			backups, err := fsys.List("")
+			if err != nil {
+				color.Red("Failed to list backups: %v", err)
+				return
+			}
+
+			sort.SliceStable(backups, func(i, j int) bool {
+				return backups[i].ModTime.After(backups[j].ModTime)
+			})
+
+			for _, b := range backups {
+				fmt.Printf("%s\t%d bytes\t%s\n", b.Key, b.Size, b.ModTime.UTC().Format("2006-01-02 15:04:05"))
+			}
+		},
+	}
+}
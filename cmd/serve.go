@@ -47,6 +47,9 @@ func NewServeCommand(app core.App, showStartBanner bool) *cobra.Command {
 				color.Yellow("=====================================")
 			}
 
+			// start the scheduled jobs ticker
+			app.Cron().Start()
+
 			router, err := apis.InitApi(app)
 			if err != nil {
 				panic(err)
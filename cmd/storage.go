@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/spf13/cobra"
+)
+
+// NewStorageCommand creates and returns new command for maintaining
+// the content-addressed (aka. deduplicated) record files storage.
+func NewStorageCommand(app core.App) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "storage",
+		Short: "Maintains the deduplicated record files storage",
+	}
+
+	command.AddCommand(storageVerifyCommand(app))
+	command.AddCommand(storageGCCommand(app))
+
+	return command
+}
+
+func storageVerifyCommand(app core.App) *cobra.Command {
+	return &cobra.Command{
+		Use:           "verify",
+		Short:         "Checks for missing, corrupted and orphaned file blobs",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(command *cobra.Command, args []string) error {
+			report, err := core.VerifyFileBlobs(app)
+			if err != nil {
+				return fmt.Errorf("Failed to verify the file blobs: %w", err)
+			}
+
+			for _, hash := range report.Missing {
+				fmt.Printf("missing\t%s\n", hash)
+			}
+
+			for _, hash := range report.Corrupted {
+				fmt.Printf("corrupted\t%s\n", hash)
+			}
+
+			for _, hash := range report.Orphaned {
+				fmt.Printf("orphaned\t%s\n", hash)
+			}
+
+			if !report.IsValid() {
+				return fmt.Errorf(
+					"Found %d missing and %d corrupted file blobs (total %d).",
+					len(report.Missing),
+					len(report.Corrupted),
+					report.Total,
+				)
+			}
+
+			color.Green(
+				"Successfully verified %d file blobs (%d orphaned).",
+				report.Total,
+				len(report.Orphaned),
+			)
+
+			return nil
+		},
+	}
+}
+
+func storageGCCommand(app core.App) *cobra.Command {
+	return &cobra.Command{
+		Use:           "gc",
+		Short:         "Deletes the file blobs that are no longer referenced by any record",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(command *cobra.Command, args []string) error {
+			deleted, err := core.DeleteOrphanedFileBlobs(app)
+
+			for _, hash := range deleted {
+				fmt.Printf("deleted\t%s\n", hash)
+			}
+
+			if err != nil {
+				return fmt.Errorf("Failed to delete the orphaned file blobs: %w", err)
+			}
+
+			color.Green("Successfully deleted %d orphaned file blobs.", len(deleted))
+
+			return nil
+		},
+	}
+}
@@ -4,9 +4,13 @@
 package core
 
 import (
+	"context"
+
 	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
 	"github.com/pocketbase/pocketbase/models/settings"
+	"github.com/pocketbase/pocketbase/tools/cron"
 	"github.com/pocketbase/pocketbase/tools/filesystem"
 	"github.com/pocketbase/pocketbase/tools/hook"
 	"github.com/pocketbase/pocketbase/tools/mailer"
@@ -64,7 +68,12 @@ type App interface {
 	Cache() *store.Store[any]
 
 	// SubscriptionsBroker returns the app realtime subscriptions broker instance.
-	SubscriptionsBroker() *subscriptions.Broker
+	SubscriptionsBroker() subscriptions.Broker
+
+	// Cron returns the app cron instance.
+	//
+	// The cron ticker is started with the serve command and stopped on app termination.
+	Cron() *cron.Cron
 
 	// NewMailClient creates and returns a configured app mail client.
 	NewMailClient() mailer.Mailer
@@ -75,9 +84,32 @@ type App interface {
 	// after you are done working with it.
 	NewFilesystem() (*filesystem.System, error)
 
+	// NewBackupsFilesystem creates and returns a configured filesystem.System instance
+	// for managing the app backups.
+	//
+	// NB! Make sure to call `Close()` on the returned result
+	// after you are done working with it.
+	NewBackupsFilesystem() (*filesystem.System, error)
+
 	// RefreshSettings reinitializes and reloads the stored application settings.
 	RefreshSettings() error
 
+	// SetAuditActor registers the actor of the next audited change of
+	// the provided record (eg. the request admin or auth record).
+	//
+	// Set a nil actor to unregister the record actor.
+	SetAuditActor(record *models.Record, actor *AuditActor)
+
+	// CreateBackup creates a new backup of the current app pb_data directory
+	// (data.db, logs.db and the local storage) with the specified name.
+	//
+	// The backup is stored in the backups filesystem (see NewBackupsFilesystem()).
+	CreateBackup(ctx context.Context, name string) error
+
+	// RestoreBackup restores the backup with the specified name and
+	// reinitializes the app resources with the restored data.
+	RestoreBackup(ctx context.Context, name string) error
+
 	// IsBootstrapped checks if the application was initialized
 	// (aka. whether Bootstrap() was called).
 	IsBootstrapped() bool
@@ -242,32 +274,55 @@ type App interface {
 	// triggered and called only if their event data origin matches the tags.
 	OnMailerAfterRecordChangeEmailSend(tags ...string) *hook.TaggedHook[*MailerRecordEvent]
 
+	// OnMailerBeforeRecordOTPSend hook is triggered right before
+	// sending a one-time password email to an auth record.
+	//
+	// The plain one-time password is available in the event Meta "otp" key
+	// (could be used for example to deliver it via a different channel
+	// if [hook.StopPropagation] is returned in one of its listeners).
+	//
+	// If the optional "tags" list (Collection ids or names) is specified,
+	// then all event handlers registered via the created hook will be
+	// triggered and called only if their event data origin matches the tags.
+	OnMailerBeforeRecordOTPSend(tags ...string) *hook.TaggedHook[*MailerRecordEvent]
+
+	// OnMailerAfterRecordOTPSend hook is triggered after a
+	// one-time password email was successfully sent to an auth record.
+	//
+	// If the optional "tags" list (Collection ids or names) is specified,
+	// then all event handlers registered via the created hook will be
+	// triggered and called only if their event data origin matches the tags.
+	OnMailerAfterRecordOTPSend(tags ...string) *hook.TaggedHook[*MailerRecordEvent]
+
 	// ---------------------------------------------------------------
 	// Realtime API event hooks
 	// ---------------------------------------------------------------
 
 	// OnRealtimeConnectRequest hook is triggered right before establishing
-	// the SSE client connection.
+	// the SSE or WebSocket client connection.
 	OnRealtimeConnectRequest() *hook.Hook[*RealtimeConnectEvent]
 
 	// OnRealtimeDisconnectRequest hook is triggered on disconnected/interrupted
-	// SSE client connection.
+	// SSE or WebSocket client connection.
 	OnRealtimeDisconnectRequest() *hook.Hook[*RealtimeDisconnectEvent]
 
 	// OnRealtimeBeforeMessage hook is triggered right before sending
-	// an SSE message to a client.
+	// an SSE or WebSocket message to a client.
 	//
 	// Returning [hook.StopPropagation] will prevent sending the message.
 	// Returning any other non-nil error will close the realtime connection.
 	OnRealtimeBeforeMessageSend() *hook.Hook[*RealtimeMessageEvent]
 
 	// OnRealtimeBeforeMessage hook is triggered right after sending
-	// an SSE message to a client.
+	// an SSE or WebSocket message to a client.
 	OnRealtimeAfterMessageSend() *hook.Hook[*RealtimeMessageEvent]
 
 	// OnRealtimeBeforeSubscribeRequest hook is triggered before changing
 	// the client subscriptions, allowing you to further validate and
 	// modify the submitted change.
+	//
+	// For WebSocket clients the event subscriptions are the full list
+	// of the client subscriptions after the in-band subscribe/unsubscribe.
 	OnRealtimeBeforeSubscribeRequest() *hook.Hook[*RealtimeSubscribeEvent]
 
 	// OnRealtimeAfterSubscribeRequest hook is triggered after the client
@@ -301,6 +356,26 @@ type App interface {
 	// File API event hooks
 	// ---------------------------------------------------------------
 
+	// OnFileBeforeTokenRequest hook is triggered before each file
+	// token API request.
+	//
+	// Could be used to modify or reject the generated protected file
+	// access token before returning it to the client.
+	OnFileBeforeTokenRequest() *hook.Hook[*FileTokenEvent]
+
+	// OnFileAfterTokenRequest hook is triggered after each
+	// successful file token API request.
+	OnFileAfterTokenRequest() *hook.Hook[*FileTokenEvent]
+
+	// OnFileBeforeUpload hook is triggered before each new record file
+	// upload (aka. before persisting the record and the file in the storage).
+	//
+	// The event File reader could be used to inspect the file content
+	// (eg. to scan it with an antivirus daemon).
+	// Returning an error rejects the record create/update operation.
+	// To quarantine the file, copy it to a different location before rejecting it.
+	OnFileBeforeUpload(tags ...string) *hook.TaggedHook[*FileUploadEvent]
+
 	// OnFileDownloadRequest hook is triggered before each API File download request.
 	//
 	// Could be used to validate or modify the file response before
@@ -372,6 +447,17 @@ type App interface {
 	// successful Admin auth with password API request.
 	OnAdminAfterAuthWithPasswordRequest() *hook.Hook[*AdminAuthWithPasswordEvent]
 
+	// OnAdminBeforeAuthWithMfaRequest hook is triggered before each Admin
+	// auth with mfa API request (after the mfa token validation and before the code verification).
+	//
+	// Could be used to additionally validate the request data or implement
+	// completely different second factor verification (returning [hook.StopPropagation]).
+	OnAdminBeforeAuthWithMfaRequest() *hook.Hook[*AdminAuthWithMfaEvent]
+
+	// OnAdminAfterAuthWithMfaRequest hook is triggered after each
+	// successful Admin auth with mfa API request.
+	OnAdminAfterAuthWithMfaRequest() *hook.Hook[*AdminAuthWithMfaEvent]
+
 	// OnAdminBeforeAuthRefreshRequest hook is triggered before each Admin
 	// auth refresh API request (right before generating a new auth token).
 	//
@@ -461,6 +547,63 @@ type App interface {
 	// triggered and called only if their event data origin matches the tags.
 	OnRecordAfterAuthWithOAuth2Request(tags ...string) *hook.TaggedHook[*RecordAuthWithOAuth2Event]
 
+	// OnRecordBeforeAuthWithMfaRequest hook is triggered before each Record
+	// auth with mfa API request (after the mfa token validation and before the code verification).
+	//
+	// Could be used to additionally validate the request data or implement
+	// completely different second factor verification (returning [hook.StopPropagation]).
+	//
+	// If the optional "tags" list (Collection ids or names) is specified,
+	// then all event handlers registered via the created hook will be
+	// triggered and called only if their event data origin matches the tags.
+	OnRecordBeforeAuthWithMfaRequest(tags ...string) *hook.TaggedHook[*RecordAuthWithMfaEvent]
+
+	// OnRecordAfterAuthWithMfaRequest hook is triggered after each
+	// successful Record auth with mfa API request.
+	//
+	// If the optional "tags" list (Collection ids or names) is specified,
+	// then all event handlers registered via the created hook will be
+	// triggered and called only if their event data origin matches the tags.
+	OnRecordAfterAuthWithMfaRequest(tags ...string) *hook.TaggedHook[*RecordAuthWithMfaEvent]
+
+	// OnRecordBeforeRequestOTPRequest hook is triggered before each Record
+	// request OTP API request (after request data load and before the OTP creation).
+	//
+	// Could be used to additionally validate the request data or implement
+	// completely different one-time password delivery behavior (returning [hook.StopPropagation]).
+	//
+	// If the optional "tags" list (Collection ids or names) is specified,
+	// then all event handlers registered via the created hook will be
+	// triggered and called only if their event data origin matches the tags.
+	OnRecordBeforeRequestOTPRequest(tags ...string) *hook.TaggedHook[*RecordRequestOTPEvent]
+
+	// OnRecordAfterRequestOTPRequest hook is triggered after each
+	// successful request OTP API request.
+	//
+	// If the optional "tags" list (Collection ids or names) is specified,
+	// then all event handlers registered via the created hook will be
+	// triggered and called only if their event data origin matches the tags.
+	OnRecordAfterRequestOTPRequest(tags ...string) *hook.TaggedHook[*RecordRequestOTPEvent]
+
+	// OnRecordBeforeAuthWithOTPRequest hook is triggered before each Record
+	// auth with OTP API request (after the OTP lookup and before the password or magic link token verification).
+	//
+	// Could be used to additionally validate the request data or implement
+	// completely different OTP verification (returning [hook.StopPropagation]).
+	//
+	// If the optional "tags" list (Collection ids or names) is specified,
+	// then all event handlers registered via the created hook will be
+	// triggered and called only if their event data origin matches the tags.
+	OnRecordBeforeAuthWithOTPRequest(tags ...string) *hook.TaggedHook[*RecordAuthWithOTPEvent]
+
+	// OnRecordAfterAuthWithOTPRequest hook is triggered after each
+	// successful Record auth with OTP API request.
+	//
+	// If the optional "tags" list (Collection ids or names) is specified,
+	// then all event handlers registered via the created hook will be
+	// triggered and called only if their event data origin matches the tags.
+	OnRecordAfterAuthWithOTPRequest(tags ...string) *hook.TaggedHook[*RecordAuthWithOTPEvent]
+
 	// OnRecordBeforeAuthRefreshRequest hook is triggered before each Record
 	// auth refresh API request (right before generating a new auth token).
 	//
@@ -635,6 +778,15 @@ type App interface {
 	// triggered and called only if their event data origin matches the tags.
 	OnRecordsListRequest(tags ...string) *hook.TaggedHook[*RecordsListEvent]
 
+	// OnRecordsAggregateRequest hook is triggered on each API Records aggregate request.
+	//
+	// Could be used to validate or modify the response before returning it to the client.
+	//
+	// If the optional "tags" list (Collection ids or names) is specified,
+	// then all event handlers registered via the created hook will be
+	// triggered and called only if their event data origin matches the tags.
+	OnRecordsAggregateRequest(tags ...string) *hook.TaggedHook[*RecordsAggregateEvent]
+
 	// OnRecordViewRequest hook is triggered on each API Record view request.
 	//
 	// Could be used to validate or modify the response before returning it to the client.
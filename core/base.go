@@ -14,6 +14,7 @@ import (
 	"github.com/pocketbase/pocketbase/logs"
 	"github.com/pocketbase/pocketbase/models"
 	"github.com/pocketbase/pocketbase/models/settings"
+	"github.com/pocketbase/pocketbase/tools/cron"
 	"github.com/pocketbase/pocketbase/tools/filesystem"
 	"github.com/pocketbase/pocketbase/tools/hook"
 	"github.com/pocketbase/pocketbase/tools/mailer"
@@ -47,7 +48,9 @@ type BaseApp struct {
 	settings            *settings.Settings
 	dao                 *daos.Dao
 	logsDao             *daos.Dao
-	subscriptionsBroker *subscriptions.Broker
+	subscriptionsBroker subscriptions.Broker
+	cron                *cron.Cron
+	auditRecorder       *auditRecorder
 
 	// app event hooks
 	onBeforeBootstrap *hook.Hook[*BootstrapEvent]
@@ -73,6 +76,8 @@ type BaseApp struct {
 	onMailerAfterRecordVerificationSend   *hook.Hook[*MailerRecordEvent]
 	onMailerBeforeRecordChangeEmailSend   *hook.Hook[*MailerRecordEvent]
 	onMailerAfterRecordChangeEmailSend    *hook.Hook[*MailerRecordEvent]
+	onMailerBeforeRecordOTPSend           *hook.Hook[*MailerRecordEvent]
+	onMailerAfterRecordOTPSend            *hook.Hook[*MailerRecordEvent]
 
 	// realtime api event hooks
 	onRealtimeConnectRequest         *hook.Hook[*RealtimeConnectEvent]
@@ -88,7 +93,10 @@ type BaseApp struct {
 	onSettingsAfterUpdateRequest  *hook.Hook[*SettingsUpdateEvent]
 
 	// file api event hooks
-	onFileDownloadRequest *hook.Hook[*FileDownloadEvent]
+	onFileBeforeUpload       *hook.Hook[*FileUploadEvent]
+	onFileDownloadRequest    *hook.Hook[*FileDownloadEvent]
+	onFileBeforeTokenRequest *hook.Hook[*FileTokenEvent]
+	onFileAfterTokenRequest  *hook.Hook[*FileTokenEvent]
 
 	// admin api event hooks
 	onAdminsListRequest                      *hook.Hook[*AdminsListEvent]
@@ -102,6 +110,8 @@ type BaseApp struct {
 	onAdminAuthRequest                       *hook.Hook[*AdminAuthEvent]
 	onAdminBeforeAuthWithPasswordRequest     *hook.Hook[*AdminAuthWithPasswordEvent]
 	onAdminAfterAuthWithPasswordRequest      *hook.Hook[*AdminAuthWithPasswordEvent]
+	onAdminBeforeAuthWithMfaRequest          *hook.Hook[*AdminAuthWithMfaEvent]
+	onAdminAfterAuthWithMfaRequest           *hook.Hook[*AdminAuthWithMfaEvent]
 	onAdminBeforeAuthRefreshRequest          *hook.Hook[*AdminAuthRefreshEvent]
 	onAdminAfterAuthRefreshRequest           *hook.Hook[*AdminAuthRefreshEvent]
 	onAdminBeforeRequestPasswordResetRequest *hook.Hook[*AdminRequestPasswordResetEvent]
@@ -115,6 +125,12 @@ type BaseApp struct {
 	onRecordAfterAuthWithPasswordRequest      *hook.Hook[*RecordAuthWithPasswordEvent]
 	onRecordBeforeAuthWithOAuth2Request       *hook.Hook[*RecordAuthWithOAuth2Event]
 	onRecordAfterAuthWithOAuth2Request        *hook.Hook[*RecordAuthWithOAuth2Event]
+	onRecordBeforeAuthWithMfaRequest          *hook.Hook[*RecordAuthWithMfaEvent]
+	onRecordAfterAuthWithMfaRequest           *hook.Hook[*RecordAuthWithMfaEvent]
+	onRecordBeforeRequestOTPRequest           *hook.Hook[*RecordRequestOTPEvent]
+	onRecordAfterRequestOTPRequest            *hook.Hook[*RecordRequestOTPEvent]
+	onRecordBeforeAuthWithOTPRequest          *hook.Hook[*RecordAuthWithOTPEvent]
+	onRecordAfterAuthWithOTPRequest           *hook.Hook[*RecordAuthWithOTPEvent]
 	onRecordBeforeAuthRefreshRequest          *hook.Hook[*RecordAuthRefreshEvent]
 	onRecordAfterAuthRefreshRequest           *hook.Hook[*RecordAuthRefreshEvent]
 	onRecordBeforeRequestPasswordResetRequest *hook.Hook[*RecordRequestPasswordResetEvent]
@@ -135,6 +151,7 @@ type BaseApp struct {
 
 	// record crud API event hooks
 	onRecordsListRequest        *hook.Hook[*RecordsListEvent]
+	onRecordsAggregateRequest   *hook.Hook[*RecordsAggregateEvent]
 	onRecordViewRequest         *hook.Hook[*RecordViewEvent]
 	onRecordBeforeCreateRequest *hook.Hook[*RecordCreateEvent]
 	onRecordAfterCreateRequest  *hook.Hook[*RecordCreateEvent]
@@ -165,6 +182,10 @@ type BaseAppConfig struct {
 	DataMaxIdleConns int // default 20
 	LogsMaxOpenConns int // default to 100
 	LogsMaxIdleConns int // default to 5
+
+	// optional custom realtime subscriptions broker
+	// (default to [subscriptions.NewDefaultBroker()])
+	SubscriptionsBroker subscriptions.Broker
 }
 
 // NewBaseApp creates and returns a new BaseApp instance
@@ -182,7 +203,8 @@ func NewBaseApp(config *BaseAppConfig) *BaseApp {
 		logsMaxIdleConns:    config.LogsMaxIdleConns,
 		cache:               store.New[any](nil),
 		settings:            settings.New(),
-		subscriptionsBroker: subscriptions.NewBroker(),
+		subscriptionsBroker: config.SubscriptionsBroker,
+		cron:                cron.New(),
 
 		// app event hooks
 		onBeforeBootstrap: &hook.Hook[*BootstrapEvent]{},
@@ -208,6 +230,8 @@ func NewBaseApp(config *BaseAppConfig) *BaseApp {
 		onMailerAfterRecordVerificationSend:   &hook.Hook[*MailerRecordEvent]{},
 		onMailerBeforeRecordChangeEmailSend:   &hook.Hook[*MailerRecordEvent]{},
 		onMailerAfterRecordChangeEmailSend:    &hook.Hook[*MailerRecordEvent]{},
+		onMailerBeforeRecordOTPSend:           &hook.Hook[*MailerRecordEvent]{},
+		onMailerAfterRecordOTPSend:            &hook.Hook[*MailerRecordEvent]{},
 
 		// realtime API event hooks
 		onRealtimeConnectRequest:         &hook.Hook[*RealtimeConnectEvent]{},
@@ -223,7 +247,10 @@ func NewBaseApp(config *BaseAppConfig) *BaseApp {
 		onSettingsAfterUpdateRequest:  &hook.Hook[*SettingsUpdateEvent]{},
 
 		// file API event hooks
-		onFileDownloadRequest: &hook.Hook[*FileDownloadEvent]{},
+		onFileBeforeUpload:       &hook.Hook[*FileUploadEvent]{},
+		onFileDownloadRequest:    &hook.Hook[*FileDownloadEvent]{},
+		onFileBeforeTokenRequest: &hook.Hook[*FileTokenEvent]{},
+		onFileAfterTokenRequest:  &hook.Hook[*FileTokenEvent]{},
 
 		// admin API event hooks
 		onAdminsListRequest:                      &hook.Hook[*AdminsListEvent]{},
@@ -237,6 +264,8 @@ func NewBaseApp(config *BaseAppConfig) *BaseApp {
 		onAdminAuthRequest:                       &hook.Hook[*AdminAuthEvent]{},
 		onAdminBeforeAuthWithPasswordRequest:     &hook.Hook[*AdminAuthWithPasswordEvent]{},
 		onAdminAfterAuthWithPasswordRequest:      &hook.Hook[*AdminAuthWithPasswordEvent]{},
+		onAdminBeforeAuthWithMfaRequest:          &hook.Hook[*AdminAuthWithMfaEvent]{},
+		onAdminAfterAuthWithMfaRequest:           &hook.Hook[*AdminAuthWithMfaEvent]{},
 		onAdminBeforeAuthRefreshRequest:          &hook.Hook[*AdminAuthRefreshEvent]{},
 		onAdminAfterAuthRefreshRequest:           &hook.Hook[*AdminAuthRefreshEvent]{},
 		onAdminBeforeRequestPasswordResetRequest: &hook.Hook[*AdminRequestPasswordResetEvent]{},
@@ -250,6 +279,12 @@ func NewBaseApp(config *BaseAppConfig) *BaseApp {
 		onRecordAfterAuthWithPasswordRequest:      &hook.Hook[*RecordAuthWithPasswordEvent]{},
 		onRecordBeforeAuthWithOAuth2Request:       &hook.Hook[*RecordAuthWithOAuth2Event]{},
 		onRecordAfterAuthWithOAuth2Request:        &hook.Hook[*RecordAuthWithOAuth2Event]{},
+		onRecordBeforeAuthWithMfaRequest:          &hook.Hook[*RecordAuthWithMfaEvent]{},
+		onRecordAfterAuthWithMfaRequest:           &hook.Hook[*RecordAuthWithMfaEvent]{},
+		onRecordBeforeRequestOTPRequest:           &hook.Hook[*RecordRequestOTPEvent]{},
+		onRecordAfterRequestOTPRequest:            &hook.Hook[*RecordRequestOTPEvent]{},
+		onRecordBeforeAuthWithOTPRequest:          &hook.Hook[*RecordAuthWithOTPEvent]{},
+		onRecordAfterAuthWithOTPRequest:           &hook.Hook[*RecordAuthWithOTPEvent]{},
 		onRecordBeforeAuthRefreshRequest:          &hook.Hook[*RecordAuthRefreshEvent]{},
 		onRecordAfterAuthRefreshRequest:           &hook.Hook[*RecordAuthRefreshEvent]{},
 		onRecordBeforeRequestPasswordResetRequest: &hook.Hook[*RecordRequestPasswordResetEvent]{},
@@ -270,6 +305,7 @@ func NewBaseApp(config *BaseAppConfig) *BaseApp {
 
 		// record crud API event hooks
 		onRecordsListRequest:        &hook.Hook[*RecordsListEvent]{},
+		onRecordsAggregateRequest:   &hook.Hook[*RecordsAggregateEvent]{},
 		onRecordViewRequest:         &hook.Hook[*RecordViewEvent]{},
 		onRecordBeforeCreateRequest: &hook.Hook[*RecordCreateEvent]{},
 		onRecordAfterCreateRequest:  &hook.Hook[*RecordCreateEvent]{},
@@ -291,6 +327,10 @@ func NewBaseApp(config *BaseAppConfig) *BaseApp {
 		onCollectionsAfterImportRequest:  &hook.Hook[*CollectionsImportEvent]{},
 	}
 
+	if app.subscriptionsBroker == nil {
+		app.subscriptionsBroker = subscriptions.NewDefaultBroker()
+	}
+
 	app.registerDefaultHooks()
 
 	return app
@@ -446,10 +486,15 @@ func (app *BaseApp) Cache() *store.Store[any] {
 }
 
 // SubscriptionsBroker returns the app realtime subscriptions broker instance.
-func (app *BaseApp) SubscriptionsBroker() *subscriptions.Broker {
+func (app *BaseApp) SubscriptionsBroker() subscriptions.Broker {
 	return app.subscriptionsBroker
 }
 
+// Cron returns the app cron instance.
+func (app *BaseApp) Cron() *cron.Cron {
+	return app.cron
+}
+
 // NewMailClient creates and returns a new SMTP or Sendmail client
 // based on the current app settings.
 func (app *BaseApp) NewMailClient() mailer.Mailer {
@@ -488,6 +533,14 @@ func (app *BaseApp) NewFilesystem() (*filesystem.System, error) {
 	return filesystem.NewLocal(filepath.Join(app.DataDir(), "storage"))
 }
 
+// SetAuditActor registers the actor of the next audited change of
+// the provided record (eg. the request admin or auth record).
+//
+// Set a nil actor to unregister the record actor.
+func (app *BaseApp) SetAuditActor(record *models.Record, actor *AuditActor) {
+	app.auditRecorder.setActor(record, actor)
+}
+
 // RefreshSettings reinitializes and reloads the stored application settings.
 func (app *BaseApp) RefreshSettings() error {
 	if app.settings == nil {
@@ -602,6 +655,14 @@ func (app *BaseApp) OnMailerAfterRecordChangeEmailSend(tags ...string) *hook.Tag
 	return hook.NewTaggedHook(app.onMailerAfterRecordChangeEmailSend, tags...)
 }
 
+func (app *BaseApp) OnMailerBeforeRecordOTPSend(tags ...string) *hook.TaggedHook[*MailerRecordEvent] {
+	return hook.NewTaggedHook(app.onMailerBeforeRecordOTPSend, tags...)
+}
+
+func (app *BaseApp) OnMailerAfterRecordOTPSend(tags ...string) *hook.TaggedHook[*MailerRecordEvent] {
+	return hook.NewTaggedHook(app.onMailerAfterRecordOTPSend, tags...)
+}
+
 // -------------------------------------------------------------------
 // Realtime API event hooks
 // -------------------------------------------------------------------
@@ -650,10 +711,22 @@ func (app *BaseApp) OnSettingsAfterUpdateRequest() *hook.Hook[*SettingsUpdateEve
 // File API event hooks
 // -------------------------------------------------------------------
 
+func (app *BaseApp) OnFileBeforeUpload(tags ...string) *hook.TaggedHook[*FileUploadEvent] {
+	return hook.NewTaggedHook(app.onFileBeforeUpload, tags...)
+}
+
 func (app *BaseApp) OnFileDownloadRequest(tags ...string) *hook.TaggedHook[*FileDownloadEvent] {
 	return hook.NewTaggedHook(app.onFileDownloadRequest, tags...)
 }
 
+func (app *BaseApp) OnFileBeforeTokenRequest() *hook.Hook[*FileTokenEvent] {
+	return app.onFileBeforeTokenRequest
+}
+
+func (app *BaseApp) OnFileAfterTokenRequest() *hook.Hook[*FileTokenEvent] {
+	return app.onFileAfterTokenRequest
+}
+
 // -------------------------------------------------------------------
 // Admin API event hooks
 // -------------------------------------------------------------------
@@ -702,6 +775,14 @@ func (app *BaseApp) OnAdminAfterAuthWithPasswordRequest() *hook.Hook[*AdminAuthW
 	return app.onAdminAfterAuthWithPasswordRequest
 }
 
+func (app *BaseApp) OnAdminBeforeAuthWithMfaRequest() *hook.Hook[*AdminAuthWithMfaEvent] {
+	return app.onAdminBeforeAuthWithMfaRequest
+}
+
+func (app *BaseApp) OnAdminAfterAuthWithMfaRequest() *hook.Hook[*AdminAuthWithMfaEvent] {
+	return app.onAdminAfterAuthWithMfaRequest
+}
+
 func (app *BaseApp) OnAdminBeforeAuthRefreshRequest() *hook.Hook[*AdminAuthRefreshEvent] {
 	return app.onAdminBeforeAuthRefreshRequest
 }
@@ -750,6 +831,30 @@ func (app *BaseApp) OnRecordAfterAuthWithOAuth2Request(tags ...string) *hook.Tag
 	return hook.NewTaggedHook(app.onRecordAfterAuthWithOAuth2Request, tags...)
 }
 
+func (app *BaseApp) OnRecordBeforeAuthWithMfaRequest(tags ...string) *hook.TaggedHook[*RecordAuthWithMfaEvent] {
+	return hook.NewTaggedHook(app.onRecordBeforeAuthWithMfaRequest, tags...)
+}
+
+func (app *BaseApp) OnRecordAfterAuthWithMfaRequest(tags ...string) *hook.TaggedHook[*RecordAuthWithMfaEvent] {
+	return hook.NewTaggedHook(app.onRecordAfterAuthWithMfaRequest, tags...)
+}
+
+func (app *BaseApp) OnRecordBeforeRequestOTPRequest(tags ...string) *hook.TaggedHook[*RecordRequestOTPEvent] {
+	return hook.NewTaggedHook(app.onRecordBeforeRequestOTPRequest, tags...)
+}
+
+func (app *BaseApp) OnRecordAfterRequestOTPRequest(tags ...string) *hook.TaggedHook[*RecordRequestOTPEvent] {
+	return hook.NewTaggedHook(app.onRecordAfterRequestOTPRequest, tags...)
+}
+
+func (app *BaseApp) OnRecordBeforeAuthWithOTPRequest(tags ...string) *hook.TaggedHook[*RecordAuthWithOTPEvent] {
+	return hook.NewTaggedHook(app.onRecordBeforeAuthWithOTPRequest, tags...)
+}
+
+func (app *BaseApp) OnRecordAfterAuthWithOTPRequest(tags ...string) *hook.TaggedHook[*RecordAuthWithOTPEvent] {
+	return hook.NewTaggedHook(app.onRecordAfterAuthWithOTPRequest, tags...)
+}
+
 func (app *BaseApp) OnRecordBeforeAuthRefreshRequest(tags ...string) *hook.TaggedHook[*RecordAuthRefreshEvent] {
 	return hook.NewTaggedHook(app.onRecordBeforeAuthRefreshRequest, tags...)
 }
@@ -826,6 +931,10 @@ func (app *BaseApp) OnRecordsListRequest(tags ...string) *hook.TaggedHook[*Recor
 	return hook.NewTaggedHook(app.onRecordsListRequest, tags...)
 }
 
+func (app *BaseApp) OnRecordsAggregateRequest(tags ...string) *hook.TaggedHook[*RecordsAggregateEvent] {
+	return hook.NewTaggedHook(app.onRecordsAggregateRequest, tags...)
+}
+
 func (app *BaseApp) OnRecordViewRequest(tags ...string) *hook.TaggedHook[*RecordViewEvent] {
 	return hook.NewTaggedHook(app.onRecordViewRequest, tags...)
 }
@@ -1022,6 +1131,10 @@ func (app *BaseApp) createDaoWithHooks(concurrentDB, nonconcurrentDB dbx.Builder
 }
 
 func (app *BaseApp) registerDefaultHooks() {
+	// audit the record changes of the configured collections
+	app.auditRecorder = &auditRecorder{app: app}
+	app.auditRecorder.bindEvents()
+
 	deletePrefix := func(prefix string) error {
 		fs, err := app.NewFilesystem()
 		if err != nil {
@@ -1056,6 +1169,82 @@ func (app *BaseApp) registerDefaultHooks() {
 			})
 		}
 
+		// release the deduplicated files of the deleted Collection or Record model
+		if hashes, err := deleteFileRefs(e.Dao, e.Model); err != nil && app.IsDebug() {
+			log.Println(err)
+		} else if len(hashes) > 0 {
+			routine.FireAndForget(func() {
+				fs, err := app.NewFilesystem()
+				if err != nil {
+					return
+				}
+				defer fs.Close()
+
+				if err := releaseFileBlobs(app, app.Dao(), fs, hashes...); err != nil && app.IsDebug() {
+					log.Println(err)
+				}
+			})
+		}
+
+		// cleanup the generated image placeholders of the deleted record
+		if m, ok := e.Model.(*models.Record); ok && m.Collection() != nil {
+			if err := e.Dao.DeleteImagePlaceholdersByRecord(m); err != nil && app.IsDebug() {
+				log.Println(err)
+			}
+		}
+
 		return nil
 	})
+
+	// periodically delete the request, webhook delivery and audit logs older than the configured Logs.MaxDays
+	app.Cron().MustAdd("__pbLogsCleanup__", "0 */6 * * *", func() {
+		if !app.IsBootstrapped() {
+			return
+		}
+
+		maxDays := app.Settings().Logs.MaxDays
+
+		if err := app.LogsDao().DeleteOldRequests(time.Now().AddDate(0, 0, -1*maxDays)); err != nil && app.IsDebug() {
+			log.Println(err)
+		}
+
+		if err := app.LogsDao().DeleteOldWebhookDeliveries(time.Now().AddDate(0, 0, -1*maxDays)); err != nil && app.IsDebug() {
+			log.Println(err)
+		}
+
+		if err := app.LogsDao().DeleteOldAuditLogs(time.Now().AddDate(0, 0, -1*maxDays)); err != nil && app.IsDebug() {
+			log.Println(err)
+		}
+
+		if maxDays == 0 {
+			// no logs are allowed -> reclaim the preserved disk space after the delete operation
+			if err := app.LogsDao().Vacuum(); err != nil && app.IsDebug() {
+				log.Println(err)
+			}
+		}
+	})
+
+	// periodically delete the expired auth records one-time passwords
+	app.Cron().MustAdd("__pbOTPsCleanup__", "0 * * * *", func() {
+		if !app.IsBootstrapped() {
+			return
+		}
+
+		maxElapsed := time.Duration(app.Settings().RecordOTPToken.Duration) * time.Second
+
+		if err := app.Dao().DeleteExpiredOTPs(maxElapsed); err != nil && app.IsDebug() {
+			log.Println(err)
+		}
+	})
+
+	// periodically delete the inactive resumable upload sessions
+	app.Cron().MustAdd("__pbUploadsCleanup__", "0 * * * *", func() {
+		if !app.IsBootstrapped() {
+			return
+		}
+
+		if err := DeleteStaleUploads(app); err != nil && app.IsDebug() {
+			log.Println(err)
+		}
+	})
 }
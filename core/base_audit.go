@@ -0,0 +1,106 @@
+package core
+
+import (
+	"log"
+	"sync"
+
+	"github.com/pocketbase/pocketbase/models"
+)
+
+// AuditActor defines the initiator of an audited record change.
+type AuditActor struct {
+	// Type is one of the models.AuditActor* constants.
+	Type string
+
+	// Id is the id of the admin or auth record actor.
+	Id string
+
+	// CollectionId is the collection id of the auth record actor.
+	CollectionId string
+}
+
+// auditRecorder stores the audit logs of the record changes
+// for the collections listed in the app Settings().Logs.AuditCollections.
+//
+// It is bound with the app default hooks, so the record changes are
+// audited regardless of how they were made (api requests, console
+// commands, migrations, custom hooks, etc.).
+//
+// The changes with registered actor (see [BaseApp.SetAuditActor]) are
+// attributed to it and all other changes are stored with the "system" actor.
+type auditRecorder struct {
+	app App
+
+	// actors holds the registered actors of the records that are being changed
+	actors sync.Map // map[*models.Record]*AuditActor
+}
+
+func (r *auditRecorder) bindEvents() {
+	r.app.OnModelAfterCreate().Add(func(e *ModelEvent) error {
+		if record, ok := e.Model.(*models.Record); ok && record != nil {
+			r.save(models.AuditActionCreate, record)
+		}
+		return nil
+	})
+
+	r.app.OnModelAfterUpdate().Add(func(e *ModelEvent) error {
+		if record, ok := e.Model.(*models.Record); ok && record != nil {
+			r.save(models.AuditActionUpdate, record)
+		}
+		return nil
+	})
+
+	r.app.OnModelAfterDelete().Add(func(e *ModelEvent) error {
+		if record, ok := e.Model.(*models.Record); ok && record != nil {
+			r.save(models.AuditActionDelete, record)
+		}
+		return nil
+	})
+}
+
+func (r *auditRecorder) isEnabled(record *models.Record) bool {
+	collection := record.Collection()
+
+	return collection != nil && r.app.Settings().Logs.IsAuditEnabled(collection.Id, collection.Name)
+}
+
+func (r *auditRecorder) setActor(record *models.Record, actor *AuditActor) {
+	if record == nil {
+		return
+	}
+
+	if actor == nil {
+		r.actors.Delete(record)
+		return
+	}
+
+	if r.isEnabled(record) {
+		r.actors.Store(record, actor)
+	}
+}
+
+// save stores a new audit log for the provided record change.
+//
+// The audit log errors are non critical and are only logged in debug mode.
+func (r *auditRecorder) save(action string, record *models.Record) {
+	if !r.isEnabled(record) {
+		return
+	}
+
+	auditLog := models.NewRecordAuditLog(action, record)
+
+	if actor, ok := r.actors.Load(record); ok {
+		a := actor.(*AuditActor)
+		auditLog.ActorType = a.Type
+		auditLog.ActorId = a.Id
+		auditLog.ActorCollectionId = a.CollectionId
+	}
+
+	if action == models.AuditActionUpdate && len(auditLog.Changes) == 0 {
+		return // nothing has changed
+	}
+
+	if err := r.app.LogsDao().SaveAuditLog(auditLog); err != nil && r.app.IsDebug() {
+		log.Println(err)
+	}
+}
@@ -0,0 +1,86 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestAuditRecorder(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().Logs.MaxDays = 1
+	app.Settings().Logs.AuditCollections = []string{"demo2"}
+
+	findLogs := func(recordId string) []*models.AuditLog {
+		logs := []*models.AuditLog{}
+
+		err := app.LogsDao().AuditLogQuery().
+			AndWhere(dbx.HashExp{"recordId": recordId}).
+			OrderBy("created ASC", "rowid ASC").
+			All(&logs)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return logs
+	}
+
+	// change outside of an api request
+	// ---
+	record, err := app.Dao().FindRecordById("demo2", "0yxhwia2amd8gec")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record.Set("title", "system_change")
+	if err := app.Dao().SaveRecord(record); err != nil {
+		t.Fatal(err)
+	}
+
+	logs := findLogs(record.Id)
+	if len(logs) != 1 {
+		t.Fatalf("Expected 1 audit log, got %d", len(logs))
+	}
+	if logs[0].Action != models.AuditActionUpdate || logs[0].ActorType != models.AuditActorSystem {
+		t.Fatalf("Expected system update audit log, got %q (%q)", logs[0].Action, logs[0].ActorType)
+	}
+
+	// change with registered actor
+	// ---
+	app.SetAuditActor(record, &core.AuditActor{Type: models.AuditActorAdmin, Id: "sywbhecnh46rhm0"})
+
+	record.Set("title", "admin_change")
+	if err := app.Dao().SaveRecord(record); err != nil {
+		t.Fatal(err)
+	}
+
+	app.SetAuditActor(record, nil)
+
+	logs = findLogs(record.Id)
+	if len(logs) != 2 {
+		t.Fatalf("Expected 2 audit logs, got %d", len(logs))
+	}
+	if logs[1].ActorType != models.AuditActorAdmin || logs[1].ActorId != "sywbhecnh46rhm0" {
+		t.Fatalf("Expected admin actor, got %q (%q)", logs[1].ActorType, logs[1].ActorId)
+	}
+
+	// not audited collection
+	// ---
+	other, err := app.Dao().FindRecordById("demo3", "7nwo8tuiatetxdm")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other.Set("title", "not_audited")
+	if err := app.Dao().SaveRecord(other); err != nil {
+		t.Fatal(err)
+	}
+	if logs = findLogs(other.Id); len(logs) != 0 {
+		t.Fatalf("Expected no audit logs for demo3, got %d", len(logs))
+	}
+}
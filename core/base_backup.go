@@ -0,0 +1,323 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/archive"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+// CacheKeyActiveBackup is the app cache key under which the name of the
+// currently running backup/restore operation is stored (if any).
+const CacheKeyActiveBackup string = "@activeBackup"
+
+const (
+	// LocalBackupsDirName is the name of the pb_data subdirectory
+	// where the backups are stored when S3 backups storage is not enabled.
+	LocalBackupsDirName string = "backups"
+
+	// LocalTempDirName is the name of the pb_data subdirectory
+	// used for storing temporary backup/restore files.
+	LocalTempDirName string = ".pb_temp"
+)
+
+// backupEntries lists the pb_data items that are included in a backup
+// and replaced on restore.
+var backupEntries = []string{
+	"data.db",
+	"data.db-shm",
+	"data.db-wal",
+	"logs.db",
+	"logs.db-shm",
+	"logs.db-wal",
+	"storage",
+}
+
+// NewBackupsFilesystem creates a new local or S3 filesystem instance
+// for managing the app backups based on the current app settings.
+//
+// NB! Make sure to call `Close()` on the returned result
+// after you are done working with it.
+func (app *BaseApp) NewBackupsFilesystem() (*filesystem.System, error) {
+	if app.settings != nil && app.settings.Backups.S3.Enabled {
+		return filesystem.NewS3(
+			app.settings.Backups.S3.Bucket,
+			app.settings.Backups.S3.Region,
+			app.settings.Backups.S3.Endpoint,
+			app.settings.Backups.S3.AccessKey,
+			app.settings.Backups.S3.Secret,
+			app.settings.Backups.S3.ForcePathStyle,
+		)
+	}
+
+	// fallback to local filesystem
+	return filesystem.NewLocal(filepath.Join(app.DataDir(), LocalBackupsDirName))
+}
+
+// CreateBackup creates a new backup of the current app pb_data directory.
+//
+// The backup is a zip archive containing a consistent snapshot of the
+// data.db and logs.db databases (created with VACUUM INTO) and the local
+// storage directory. The archive is saved in the backups filesystem
+// (see [BaseApp.NewBackupsFilesystem]) under the provided name.
+//
+// If name is empty, an auto generated backup name is used.
+func (app *BaseApp) CreateBackup(ctx context.Context, name string) error {
+	if name == "" {
+		name = GenerateBackupName("pb_backup_")
+	}
+
+	if err := app.lockBackup(name); err != nil {
+		return err
+	}
+	defer app.Cache().Remove(CacheKeyActiveBackup)
+
+	tempDir := filepath.Join(app.DataDir(), LocalTempDirName)
+	if err := os.MkdirAll(tempDir, os.ModePerm); err != nil {
+		return err
+	}
+
+	stagingDir := filepath.Join(tempDir, "pb_backup_"+security.PseudorandomString(10))
+	if err := os.MkdirAll(stagingDir, os.ModePerm); err != nil {
+		return err
+	}
+	defer os.RemoveAll(stagingDir)
+
+	// consistent db snapshots
+	if err := app.Dao().VacuumInto(filepath.Join(stagingDir, "data.db")); err != nil {
+		return err
+	}
+	if err := app.LogsDao().VacuumInto(filepath.Join(stagingDir, "logs.db")); err != nil {
+		return err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// local storage snapshot
+	storageDir := filepath.Join(app.DataDir(), "storage")
+	if _, err := os.Stat(storageDir); err == nil {
+		if err := linkDir(storageDir, filepath.Join(stagingDir, "storage")); err != nil {
+			return err
+		}
+	}
+
+	zipPath := stagingDir + ".zip"
+	if err := archive.Create(stagingDir, zipPath); err != nil {
+		return err
+	}
+	defer os.Remove(zipPath)
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	fsys, err := app.NewBackupsFilesystem()
+	if err != nil {
+		return err
+	}
+	defer fsys.Close()
+
+	file, err := filesystem.NewFileFromPath(zipPath)
+	if err != nil {
+		return err
+	}
+	file.OriginalName = name
+	file.Name = name
+
+	return fsys.UploadFile(file, name)
+}
+
+// RestoreBackup restores the backup with the specified name and
+// reinitializes the application with the restored data.
+//
+// The current pb_data databases and storage are replaced with the backup
+// ones while the app is reset via [BaseApp.ResetBootstrapState] and
+// then bootstrapped again, so no manual restart is required.
+//
+// If the restored app fails to bootstrap, the previous pb_data state is reverted.
+func (app *BaseApp) RestoreBackup(ctx context.Context, name string) error {
+	if err := app.lockBackup(name); err != nil {
+		return err
+	}
+	defer app.Cache().Remove(CacheKeyActiveBackup)
+
+	tempDir := filepath.Join(app.DataDir(), LocalTempDirName)
+	if err := os.MkdirAll(tempDir, os.ModePerm); err != nil {
+		return err
+	}
+
+	suffix := security.PseudorandomString(10)
+	zipPath := filepath.Join(tempDir, "pb_restore_"+suffix+".zip")
+	extractedDir := filepath.Join(tempDir, "pb_restore_"+suffix)
+	oldDir := filepath.Join(tempDir, "pb_old_"+suffix)
+	defer os.Remove(zipPath)
+	defer os.RemoveAll(extractedDir)
+
+	if err := app.downloadBackup(name, zipPath); err != nil {
+		return err
+	}
+
+	if err := archive.Extract(zipPath, extractedDir); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(filepath.Join(extractedDir, "data.db")); err != nil {
+		return errors.New("invalid backup archive - missing data.db file")
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := app.ResetBootstrapState(); err != nil {
+		return err
+	}
+
+	// move the current data aside (allowing revert in case of failure)
+	if err := moveEntries(app.DataDir(), oldDir, backupEntries); err != nil {
+		moveEntries(oldDir, app.DataDir(), backupEntries)
+		app.Bootstrap()
+		return err
+	}
+
+	if err := moveEntries(extractedDir, app.DataDir(), backupEntries); err != nil {
+		app.revertRestore(oldDir)
+		return err
+	}
+
+	if err := app.Bootstrap(); err != nil {
+		app.revertRestore(oldDir)
+		return err
+	}
+
+	return os.RemoveAll(oldDir)
+}
+
+// GenerateBackupName generates a new backup name
+// in the format "{prefix}{YYYYMMDDHHMMSS}.zip".
+func GenerateBackupName(prefix string) string {
+	return prefix + time.Now().UTC().Format("20060102150405") + ".zip"
+}
+
+// lockBackup marks the start of a new backup/restore operation
+// returning an error if there is already another one in progress.
+func (app *BaseApp) lockBackup(name string) error {
+	if !app.Cache().SetIfNotExists(CacheKeyActiveBackup, name) {
+		return errors.New("try again later - another backup/restore operation has already been started")
+	}
+
+	return nil
+}
+
+// downloadBackup copies the named backup from the backups filesystem to destPath.
+func (app *BaseApp) downloadBackup(name string, destPath string) error {
+	fsys, err := app.NewBackupsFilesystem()
+	if err != nil {
+		return err
+	}
+	defer fsys.Close()
+
+	br, err := fsys.GetFile(name)
+	if err != nil {
+		return err
+	}
+	defer br.Close()
+
+	f, err := os.Create(destPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, br)
+
+	return err
+}
+
+// revertRestore restores the pb_data entries previously moved to oldDir.
+func (app *BaseApp) revertRestore(oldDir string) {
+	app.ResetBootstrapState()
+
+	for _, name := range backupEntries {
+		os.RemoveAll(filepath.Join(app.DataDir(), name))
+	}
+
+	moveEntries(oldDir, app.DataDir(), backupEntries)
+
+	app.Bootstrap()
+}
+
+// moveEntries moves the existing srcDir entries with the specified names to destDir.
+func moveEntries(srcDir string, destDir string, names []string) error {
+	if err := os.MkdirAll(destDir, os.ModePerm); err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		src := filepath.Join(srcDir, name)
+
+		if _, err := os.Stat(src); err != nil {
+			continue // missing
+		}
+
+		if err := os.Rename(src, filepath.Join(destDir, name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// linkDir recreates the srcDir tree in destDir by hard linking
+// its files (with fallback to a regular copy if linking fails).
+func linkDir(srcDir string, destDir string) error {
+	return filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+
+		dest := filepath.Join(destDir, rel)
+
+		if d.IsDir() {
+			return os.MkdirAll(dest, os.ModePerm)
+		}
+
+		if err := os.Link(path, dest); err == nil {
+			return nil
+		}
+
+		return copyFile(path, dest)
+	})
+}
+
+func copyFile(src string, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+
+	return err
+}
@@ -0,0 +1,102 @@
+package core_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestGenerateBackupName(t *testing.T) {
+	name := core.GenerateBackupName("test_")
+
+	if !strings.HasPrefix(name, "test_") || !strings.HasSuffix(name, ".zip") {
+		t.Fatalf("Expected name in the format test_{date}.zip, got %q", name)
+	}
+
+	if l := len(name); l != len("test_")+14+len(".zip") {
+		t.Fatalf("Expected name with length %d, got %d", len("test_")+14+len(".zip"), l)
+	}
+}
+
+func TestBaseAppCreateBackup(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	// simulate an already running backup
+	app.Cache().Set(core.CacheKeyActiveBackup, "")
+	if err := app.CreateBackup(context.Background(), "test.zip"); err == nil {
+		t.Fatal("Expected error for an already active backup")
+	}
+	app.Cache().Remove(core.CacheKeyActiveBackup)
+
+	if err := app.CreateBackup(context.Background(), "test.zip"); err != nil {
+		t.Fatalf("Failed to create the backup: %v", err)
+	}
+
+	if app.Cache().Has(core.CacheKeyActiveBackup) {
+		t.Fatal("Expected the active backup cache key to be removed")
+	}
+
+	backupPath := filepath.Join(app.DataDir(), core.LocalBackupsDirName, "test.zip")
+	if info, err := os.Stat(backupPath); err != nil || info.Size() == 0 {
+		t.Fatalf("Expected non-empty backup file at %q, got %v", backupPath, err)
+	}
+
+	// the temp staging files should be cleaned up
+	entries, _ := os.ReadDir(filepath.Join(app.DataDir(), core.LocalTempDirName))
+	if len(entries) != 0 {
+		t.Fatalf("Expected the temp dir to be empty, got %d entries", len(entries))
+	}
+}
+
+func TestBaseAppRestoreBackup(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	if err := app.RestoreBackup(context.Background(), "missing.zip"); err == nil {
+		t.Fatal("Expected error for missing backup")
+	}
+
+	if err := app.CreateBackup(context.Background(), "test.zip"); err != nil {
+		t.Fatal(err)
+	}
+
+	// modify the current data after the backup
+	admin, err := app.Dao().FindAdminByEmail("test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := app.Dao().DeleteAdmin(admin); err != nil {
+		t.Fatal(err)
+	}
+	testFile := filepath.Join(app.DataDir(), "storage", "after_backup.txt")
+	if err := os.WriteFile(testFile, []byte("test"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.RestoreBackup(context.Background(), "test.zip"); err != nil {
+		t.Fatalf("Failed to restore the backup: %v", err)
+	}
+
+	if !app.IsBootstrapped() {
+		t.Fatal("Expected the app to be bootstrapped after restore")
+	}
+
+	if _, err := app.Dao().FindAdminByEmail("test@example.com"); err != nil {
+		t.Fatalf("Expected the deleted admin to be restored, got %v", err)
+	}
+
+	if _, err := os.Stat(testFile); err == nil {
+		t.Fatal("Expected the storage to be restored to its backup state")
+	}
+
+	// the backups should be preserved
+	if _, err := os.Stat(filepath.Join(app.DataDir(), core.LocalBackupsDirName, "test.zip")); err != nil {
+		t.Fatalf("Expected the backup file to be preserved, got %v", err)
+	}
+}
@@ -0,0 +1,398 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"path"
+	"sort"
+	"sync"
+
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+	"github.com/pocketbase/pocketbase/tools/list"
+)
+
+// FileBlobsPrefix is the storage prefix of the content-addressed
+// (aka. deduplicated) record files.
+const FileBlobsPrefix = "_blobs"
+
+// fileRefsMux guards the blobs references count checks and uploads
+// to prevent deleting a blob that is being referenced concurrently.
+var fileRefsMux sync.Mutex
+
+// FileBlobKey returns the storage key of the blob with the specified content hash.
+func FileBlobKey(hash string) string {
+	if len(hash) < 2 {
+		return FileBlobsPrefix + "/" + hash
+	}
+
+	return FileBlobsPrefix + "/" + hash[:2] + "/" + hash
+}
+
+// RecordFileKey returns the storage key of the specified record file content.
+//
+// For deduplicated files this is the key of the referenced blob,
+// otherwise it is the regular record file path.
+func RecordFileKey(dao *daos.Dao, record *models.Record, filename string) string {
+	if ref, err := dao.FindFileRef(record.Collection().Id, record.Id, filename); err == nil {
+		return FileBlobKey(ref.Hash)
+	}
+
+	return record.BaseFilesPath() + "/" + filename
+}
+
+// UploadRecordFile uploads a single new record file.
+//
+// If the [settings.StorageConfig.Dedup] option is enabled, the file
+// content is stored as a blob shared between all files with the same
+// content and only a new file reference is created for the record.
+//
+// The file reference is created with the provided dao and, if the dao
+// is part of a transaction, the blob is uploaded after the transaction commit
+// (aka. when the new reference is visible to the concurrent blob releases).
+func UploadRecordFile(app App, dao *daos.Dao, fs *filesystem.System, record *models.Record, file *filesystem.File) error {
+	if !app.Settings().Storage.Dedup {
+		return fs.UploadFile(file, record.BaseFilesPath()+"/"+file.Name)
+	}
+
+	hash, err := fileContentHash(file.Reader)
+	if err != nil {
+		return err
+	}
+
+	ref := &models.FileRef{
+		CollectionId: record.Collection().Id,
+		RecordId:     record.Id,
+		Filename:     file.Name,
+		Hash:         hash,
+		Size:         file.Size,
+	}
+
+	if err := dao.SaveFileRef(ref); err != nil {
+		return err
+	}
+
+	return dao.AfterCommit(func(dao *daos.Dao) error {
+		err := uploadFileBlob(app, file, hash)
+		if err == nil {
+			return nil
+		}
+
+		// the blob upload failed -> release the new reference
+		if releaseErr := releaseFileRef(app, dao, ref); releaseErr != nil && app.IsDebug() {
+			log.Println(releaseErr)
+		}
+
+		if app.IsDebug() {
+			log.Println(err)
+		}
+
+		return err
+	})
+}
+
+// DeleteRecordFile deletes a single record file.
+//
+// For deduplicated files only the record file reference is deleted
+// (using the provided dao) and the blob is deleted only if there are no
+// other references to it after the dao transaction commit (if any).
+func DeleteRecordFile(app App, dao *daos.Dao, fs *filesystem.System, record *models.Record, filename string) error {
+	ref, err := dao.FindFileRef(record.Collection().Id, record.Id, filename)
+	if err != nil {
+		return fs.Delete(record.BaseFilesPath() + "/" + filename)
+	}
+
+	return releaseFileRef(app, dao, ref)
+}
+
+// uploadFileBlob uploads the content of the provided file
+// as blob with the specified hash (if it is not stored already).
+func uploadFileBlob(app App, file *filesystem.File, hash string) error {
+	fs, err := app.NewFilesystem()
+	if err != nil {
+		return err
+	}
+	defer fs.Close()
+
+	// prevent deleting the blob by a concurrent release
+	// that has counted the references before the upload
+	fileRefsMux.Lock()
+	defer fileRefsMux.Unlock()
+
+	key := FileBlobKey(hash)
+
+	if exists, _ := fs.Exists(key); exists {
+		return nil // already stored
+	}
+
+	return fs.UploadFile(file, key)
+}
+
+// releaseFileRef deletes the provided file reference using the specified dao
+// and, after the dao transaction commit (if any), deletes its blob if
+// there are no other references to it.
+func releaseFileRef(app App, dao *daos.Dao, ref *models.FileRef) error {
+	if err := dao.DeleteFileRef(ref); err != nil {
+		return err
+	}
+
+	return dao.AfterCommit(func(dao *daos.Dao) error {
+		fs, err := app.NewFilesystem()
+		if err != nil {
+			return err
+		}
+		defer fs.Close()
+
+		err = releaseFileBlobs(app, dao, fs, ref.Hash)
+		if err != nil && app.IsDebug() {
+			log.Println(err)
+		}
+
+		return err
+	})
+}
+
+// releaseFileBlobs deletes the blobs of the specified
+// hashes that are no longer referenced.
+func releaseFileBlobs(app App, dao *daos.Dao, fs *filesystem.System, hashes ...string) error {
+	fileRefsMux.Lock()
+	defer fileRefsMux.Unlock()
+
+	for _, hash := range list.ToUniqueStringSlice(hashes) {
+		if _, err := deleteUnreferencedFileBlob(dao, fs, hash); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteUnreferencedFileBlob deletes the blob with the specified hash
+// if it is stored and there are no references to it.
+//
+// The references check and the blob delete are performed while holding
+// the db write lock, so that a concurrent reference from another process
+// (eg. "serve" and the "storage gc" command) is either counted or committed
+// after the delete (and then its blob is uploaded again).
+//
+// The caller must hold the fileRefsMux lock.
+func deleteUnreferencedFileBlob(dao *daos.Dao, fs *filesystem.System, hash string) (bool, error) {
+	var deleted bool
+
+	err := dao.RunInTransaction(func(txDao *daos.Dao) error {
+		total, err := txDao.LockFileRefsByHash(hash)
+		if err != nil {
+			return err
+		}
+
+		if total > 0 {
+			return nil // still in use
+		}
+
+		key := FileBlobKey(hash)
+		if exists, _ := fs.Exists(key); !exists {
+			return nil
+		}
+
+		if err := fs.Delete(key); err != nil {
+			return err
+		}
+
+		deleted = true
+
+		return nil
+	})
+
+	return deleted, err
+}
+
+// deleteFileRefs deletes the file references of the provided deleted
+// record or collection model and returns their blob hashes.
+func deleteFileRefs(dao *daos.Dao, m models.Model) ([]string, error) {
+	var refs []*models.FileRef
+	var err error
+
+	switch v := m.(type) {
+	case *models.Record:
+		if v.Collection() == nil {
+			return nil, nil
+		}
+		refs, err = dao.FindFileRefsByRecord(v)
+	case *models.Collection:
+		refs, err = dao.FindFileRefsByCollection(v)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make([]string, 0, len(refs))
+
+	for _, ref := range refs {
+		if err := dao.DeleteFileRef(ref); err != nil {
+			return hashes, err
+		}
+		hashes = append(hashes, ref.Hash)
+	}
+
+	return hashes, nil
+}
+
+// FileBlobsReport defines the content-addressed storage verification result.
+type FileBlobsReport struct {
+	// Total is the number of the stored blobs.
+	Total int `json:"total"`
+
+	// Missing lists the hashes of the referenced but not stored blobs.
+	Missing []string `json:"missing"`
+
+	// Corrupted lists the hashes of the blobs whose content doesn't match their hash.
+	Corrupted []string `json:"corrupted"`
+
+	// Orphaned lists the hashes of the stored blobs without any reference.
+	Orphaned []string `json:"orphaned"`
+}
+
+// IsValid reports whether there are no missing or corrupted blobs
+// (the orphaned blobs are harmless and could be deleted with [DeleteOrphanedFileBlobs]).
+func (r *FileBlobsReport) IsValid() bool {
+	return len(r.Missing) == 0 && len(r.Corrupted) == 0
+}
+
+// VerifyFileBlobs checks the integrity of the stored blobs
+// and their references.
+func VerifyFileBlobs(app App) (*FileBlobsReport, error) {
+	fs, err := app.NewFilesystem()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.Close()
+
+	referenced, err := app.Dao().FindFileRefHashes()
+	if err != nil {
+		return nil, err
+	}
+
+	objects, err := fs.List(FileBlobsPrefix + "/")
+	if err != nil {
+		return nil, err
+	}
+
+	report := &FileBlobsReport{
+		Missing:   []string{},
+		Corrupted: []string{},
+		Orphaned:  []string{},
+	}
+
+	stored := make(map[string]struct{}, len(objects))
+
+	for _, obj := range objects {
+		if obj.IsDir {
+			continue
+		}
+
+		report.Total++
+
+		hash := path.Base(obj.Key)
+		stored[hash] = struct{}{}
+
+		if !list.ExistInSlice(hash, referenced) {
+			report.Orphaned = append(report.Orphaned, hash)
+		}
+
+		contentHash, err := storedContentHash(fs, obj.Key)
+		if err != nil || contentHash != hash {
+			report.Corrupted = append(report.Corrupted, hash)
+		}
+	}
+
+	for _, hash := range referenced {
+		if _, ok := stored[hash]; !ok {
+			report.Missing = append(report.Missing, hash)
+		}
+	}
+
+	sort.Strings(report.Orphaned)
+	sort.Strings(report.Corrupted)
+
+	return report, nil
+}
+
+// DeleteOrphanedFileBlobs deletes all stored blobs without
+// any reference and returns their hashes.
+//
+// It is safe to be called while the app is serving from another process
+// (see [deleteUnreferencedFileBlob]).
+func DeleteOrphanedFileBlobs(app App) ([]string, error) {
+	fs, err := app.NewFilesystem()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.Close()
+
+	objects, err := fs.List(FileBlobsPrefix + "/")
+	if err != nil {
+		return nil, err
+	}
+
+	fileRefsMux.Lock()
+	defer fileRefsMux.Unlock()
+
+	deleted := []string{}
+
+	for _, obj := range objects {
+		if obj.IsDir {
+			continue
+		}
+
+		hash := path.Base(obj.Key)
+
+		ok, err := deleteUnreferencedFileBlob(app.Dao(), fs, hash)
+		if err != nil {
+			return deleted, err
+		}
+
+		if ok {
+			deleted = append(deleted, hash)
+		}
+	}
+
+	sort.Strings(deleted)
+
+	return deleted, nil
+}
+
+// fileContentHash returns the hex encoded SHA-256 hash of the file content.
+func fileContentHash(fr filesystem.FileReader) (string, error) {
+	r, err := fr.Open()
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	return readerHash(r)
+}
+
+// storedContentHash returns the hex encoded SHA-256 hash of the stored file content.
+func storedContentHash(fs *filesystem.System, key string) (string, error) {
+	r, err := fs.GetFile(key)
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	return readerHash(r)
+}
+
+func readerHash(r io.Reader) (string, error) {
+	h := sha256.New()
+
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,332 @@
+package core_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+)
+
+func testContentHash(content string) string {
+	h := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(h[:])
+}
+
+func TestFileBlobKey(t *testing.T) {
+	scenarios := []struct {
+		hash     string
+		expected string
+	}{
+		{"", "_blobs/"},
+		{"a", "_blobs/a"},
+		{"abcdef", "_blobs/ab/abcdef"},
+	}
+
+	for _, s := range scenarios {
+		if key := core.FileBlobKey(s.hash); key != s.expected {
+			t.Errorf("(%q) Expected %q, got %q", s.hash, s.expected, key)
+		}
+	}
+}
+
+func TestUploadRecordFileWithoutDedup(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	fs, err := app.NewFilesystem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	record, err := app.Dao().FindRecordById("users", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := filesystem.NewFileFromBytes([]byte("test"), "test.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := core.UploadRecordFile(app, app.Dao(), fs, record, file); err != nil {
+		t.Fatal(err)
+	}
+
+	key := core.RecordFileKey(app.Dao(), record, file.Name)
+	if key != record.BaseFilesPath()+"/"+file.Name {
+		t.Fatalf("Expected the regular record file key, got %q", key)
+	}
+
+	if exists, _ := fs.Exists(key); !exists {
+		t.Fatalf("Expected %q to be uploaded", key)
+	}
+
+	if err := core.DeleteRecordFile(app, app.Dao(), fs, record, file.Name); err != nil {
+		t.Fatal(err)
+	}
+
+	if exists, _ := fs.Exists(key); exists {
+		t.Fatalf("Expected %q to be deleted", key)
+	}
+}
+
+func TestUploadAndDeleteRecordFileWithDedup(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().Storage.Dedup = true
+
+	fs, err := app.NewFilesystem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	record1, err := app.Dao().FindRecordById("users", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record2, err := app.Dao().FindRecordById("users", "oap640cot4yru2s")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file1, _ := filesystem.NewFileFromBytes([]byte("test"), "test1.txt")
+	file2, _ := filesystem.NewFileFromBytes([]byte("test"), "test2.txt")
+
+	if err := core.UploadRecordFile(app, app.Dao(), fs, record1, file1); err != nil {
+		t.Fatal(err)
+	}
+	if err := core.UploadRecordFile(app, app.Dao(), fs, record2, file2); err != nil {
+		t.Fatal(err)
+	}
+
+	blobKey := core.FileBlobKey(testContentHash("test"))
+
+	for _, key := range []string{
+		core.RecordFileKey(app.Dao(), record1, file1.Name),
+		core.RecordFileKey(app.Dao(), record2, file2.Name),
+	} {
+		if key != blobKey {
+			t.Fatalf("Expected record file key %q, got %q", blobKey, key)
+		}
+	}
+
+	if exists, _ := fs.Exists(record1.BaseFilesPath() + "/" + file1.Name); exists {
+		t.Fatal("Expected the regular record file to not be stored")
+	}
+
+	if total, _ := app.Dao().TotalFileRefsByHash(testContentHash("test")); total != 2 {
+		t.Fatalf("Expected 2 blob refs, got %d", total)
+	}
+
+	// delete the first reference
+	if err := core.DeleteRecordFile(app, app.Dao(), fs, record1, file1.Name); err != nil {
+		t.Fatal(err)
+	}
+	if exists, _ := fs.Exists(blobKey); !exists {
+		t.Fatal("Expected the blob to remain while it is still referenced")
+	}
+
+	// delete the last reference
+	if err := core.DeleteRecordFile(app, app.Dao(), fs, record2, file2.Name); err != nil {
+		t.Fatal(err)
+	}
+	if exists, _ := fs.Exists(blobKey); exists {
+		t.Fatal("Expected the blob to be deleted with its last reference")
+	}
+}
+
+func TestUploadAndDeleteRecordFileWithDedupInTransaction(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().Storage.Dedup = true
+
+	fs, err := app.NewFilesystem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	record, err := app.Dao().FindRecordById("users", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blobKey := core.FileBlobKey(testContentHash("test_tx"))
+
+	// rolled back upload
+	app.Dao().RunInTransaction(func(txDao *daos.Dao) error {
+		file, _ := filesystem.NewFileFromBytes([]byte("test_tx"), "test_rollback.txt")
+		if err := core.UploadRecordFile(app, txDao, fs, record, file); err != nil {
+			t.Fatal(err)
+		}
+		return errors.New("rollback")
+	})
+	if total, _ := app.Dao().TotalFileRefsByHash(testContentHash("test_tx")); total != 0 {
+		t.Fatalf("Expected no blob refs after rollback, got %d", total)
+	}
+	if exists, _ := fs.Exists(blobKey); exists {
+		t.Fatal("Expected the blob to not be uploaded after rollback")
+	}
+
+	// committed upload
+	file, _ := filesystem.NewFileFromBytes([]byte("test_tx"), "test_commit.txt")
+	app.Dao().RunInTransaction(func(txDao *daos.Dao) error {
+		if err := core.UploadRecordFile(app, txDao, fs, record, file); err != nil {
+			t.Fatal(err)
+		}
+		if exists, _ := fs.Exists(blobKey); exists {
+			t.Fatal("Expected the blob to be uploaded after the commit")
+		}
+		return nil
+	})
+	if total, _ := app.Dao().TotalFileRefsByHash(testContentHash("test_tx")); total != 1 {
+		t.Fatalf("Expected 1 blob ref after commit, got %d", total)
+	}
+	if exists, _ := fs.Exists(blobKey); !exists {
+		t.Fatal("Expected the blob to be uploaded")
+	}
+
+	// committed delete
+	app.Dao().RunInTransaction(func(txDao *daos.Dao) error {
+		if err := core.DeleteRecordFile(app, txDao, fs, record, file.Name); err != nil {
+			t.Fatal(err)
+		}
+		if exists, _ := fs.Exists(blobKey); !exists {
+			t.Fatal("Expected the blob to be deleted after the commit")
+		}
+		return nil
+	})
+	if exists, _ := fs.Exists(blobKey); exists {
+		t.Fatal("Expected the blob to be deleted")
+	}
+}
+
+func TestDeleteRecordWithDedupFiles(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().Storage.Dedup = true
+
+	fs, err := app.NewFilesystem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	record, err := app.Dao().FindRecordById("users", "oap640cot4yru2s")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file, _ := filesystem.NewFileFromBytes([]byte("test_delete"), "test.txt")
+	if err := core.UploadRecordFile(app, app.Dao(), fs, record, file); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Dao().DeleteRecord(record); err != nil {
+		t.Fatal(err)
+	}
+
+	if refs, _ := app.Dao().FindFileRefsByRecord(record); len(refs) != 0 {
+		t.Fatalf("Expected the record file refs to be deleted, got %v", refs)
+	}
+
+	// the blob is deleted in the background
+	blobKey := core.FileBlobKey(testContentHash("test_delete"))
+	for i := 0; ; i++ {
+		if exists, _ := fs.Exists(blobKey); !exists {
+			break
+		}
+
+		if i > 100 {
+			t.Fatal("Expected the blob to be deleted")
+		}
+
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestVerifyAndDeleteOrphanedFileBlobs(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	fs, err := app.NewFilesystem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	validHash := testContentHash("valid")
+	corruptedHash := testContentHash("corrupted")
+	orphanedHash := testContentHash("orphaned")
+	missingHash := testContentHash("missing")
+
+	if err := fs.Upload([]byte("valid"), core.FileBlobKey(validHash)); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Upload([]byte("changed"), core.FileBlobKey(corruptedHash)); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Upload([]byte("orphaned"), core.FileBlobKey(orphanedHash)); err != nil {
+		t.Fatal(err)
+	}
+
+	refs := []*models.FileRef{
+		{CollectionId: "c", RecordId: "r", Filename: "valid.txt", Hash: validHash},
+		{CollectionId: "c", RecordId: "r", Filename: "corrupted.txt", Hash: corruptedHash},
+		{CollectionId: "c", RecordId: "r", Filename: "missing.txt", Hash: missingHash},
+	}
+	for _, ref := range refs {
+		if err := app.Dao().SaveFileRef(ref); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := core.VerifyFileBlobs(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Total != 3 {
+		t.Errorf("Expected 3 blobs, got %d", report.Total)
+	}
+	if report.IsValid() {
+		t.Error("Expected the report to be invalid")
+	}
+	if len(report.Missing) != 1 || report.Missing[0] != missingHash {
+		t.Errorf("Expected missing %q, got %v", missingHash, report.Missing)
+	}
+	if len(report.Corrupted) != 1 || report.Corrupted[0] != corruptedHash {
+		t.Errorf("Expected corrupted %q, got %v", corruptedHash, report.Corrupted)
+	}
+	if len(report.Orphaned) != 1 || report.Orphaned[0] != orphanedHash {
+		t.Errorf("Expected orphaned %q, got %v", orphanedHash, report.Orphaned)
+	}
+
+	deleted, err := core.DeleteOrphanedFileBlobs(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 1 || deleted[0] != orphanedHash {
+		t.Fatalf("Expected deleted %q, got %v", orphanedHash, deleted)
+	}
+
+	if exists, _ := fs.Exists(core.FileBlobKey(orphanedHash)); exists {
+		t.Fatal("Expected the orphaned blob to be deleted")
+	}
+	if exists, _ := fs.Exists(core.FileBlobKey(validHash)); !exists {
+		t.Fatal("Expected the referenced blob to remain")
+	}
+}
@@ -40,6 +40,15 @@ func TestNewBaseApp(t *testing.T) {
 	if app.subscriptionsBroker == nil {
 		t.Fatal("expected subscriptionsBroker to be set, got nil")
 	}
+
+	if app.cron == nil {
+		t.Fatal("expected cron to be set, got nil")
+	}
+
+	// the default logs, otps and uploads cleanup jobs
+	if total := app.cron.Total(); total != 3 {
+		t.Fatalf("expected 3 registered cron jobs, got %d", total)
+	}
 }
 
 func TestBaseAppBootstrap(t *testing.T) {
@@ -179,6 +188,10 @@ func TestBaseAppGetters(t *testing.T) {
 		t.Fatalf("Expected app.SubscriptionsBroker %v, got %v", app.SubscriptionsBroker(), app.subscriptionsBroker)
 	}
 
+	if app.cron != app.Cron() {
+		t.Fatalf("Expected app.Cron %v, got %v", app.Cron(), app.cron)
+	}
+
 	if app.onBeforeServe != app.OnBeforeServe() || app.OnBeforeServe() == nil {
 		t.Fatalf("Getter app.OnBeforeServe does not match or nil (%v vs %v)", app.OnBeforeServe(), app.onBeforeServe)
 	}
@@ -0,0 +1,62 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pocketbase/pocketbase/models"
+)
+
+// LocalUploadsDirName is the name of the [LocalTempDirName] subdirectory
+// where the resumable upload sessions files are stored.
+const LocalUploadsDirName string = "uploads"
+
+// UploadMaxIdle specifies how long an unfinished resumable upload
+// session could stay inactive before being automatically deleted.
+var UploadMaxIdle = 24 * time.Hour
+
+// UploadMaxSessionsPerOwner specifies the max number of unfinished
+// resumable upload sessions that a single owner could have.
+//
+// All guest sessions are counted as having the same owner.
+var UploadMaxSessionsPerOwner = 10
+
+// UploadMaxBytesPerOwner specifies the max total size (in bytes) of the
+// unfinished resumable upload sessions that a single owner could have.
+//
+// All guest sessions are counted as having the same owner.
+var UploadMaxBytesPerOwner int64 = 5 << 30
+
+// LocalUploadDir returns the local directory
+// where the resumable upload session files are stored.
+func LocalUploadDir(app App, uploadId string) string {
+	return filepath.Join(app.DataDir(), LocalTempDirName, LocalUploadsDirName, uploadId)
+}
+
+// DeleteUpload deletes the provided resumable upload session
+// together with its locally stored files.
+func DeleteUpload(app App, upload *models.Upload) error {
+	if err := app.Dao().DeleteUpload(upload); err != nil {
+		return err
+	}
+
+	return os.RemoveAll(LocalUploadDir(app, upload.Id))
+}
+
+// DeleteStaleUploads deletes all resumable upload sessions (and their files)
+// that were not updated for more than [UploadMaxIdle].
+func DeleteStaleUploads(app App) error {
+	uploads, err := app.Dao().FindStaleUploads(UploadMaxIdle)
+	if err != nil {
+		return err
+	}
+
+	for _, upload := range uploads {
+		if err := DeleteUpload(app, upload); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package core_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestLocalUploadDir(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	expected := filepath.Join(app.DataDir(), core.LocalTempDirName, core.LocalUploadsDirName, "test")
+
+	if dir := core.LocalUploadDir(app, "test"); dir != expected {
+		t.Fatalf("Expected %q, got %q", expected, dir)
+	}
+}
+
+func TestDeleteStaleUploads(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	uploads := []*models.Upload{
+		{CollectionId: "test", RecordId: "test1", Field: "test", Filename: "test.txt", Size: 10},
+		{CollectionId: "test", RecordId: "test2", Field: "test", Filename: "test.txt", Size: 10},
+	}
+	for _, upload := range uploads {
+		if err := app.Dao().SaveUpload(upload); err != nil {
+			t.Fatal(err)
+		}
+
+		dir := core.LocalUploadDir(app, upload.Id)
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, upload.Filename), []byte("test"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// make the first upload inactive
+	_, err := app.Dao().DB().Update(
+		"_uploads",
+		dbx.Params{"updated": types.NowDateTime().Time().Add(-core.UploadMaxIdle - time.Minute).Format(types.DefaultDateLayout)},
+		dbx.HashExp{"id": uploads[0].Id},
+	).Execute()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := core.DeleteStaleUploads(app); err != nil {
+		t.Fatal(err)
+	}
+
+	// stale
+	if _, err := app.Dao().FindUploadById(uploads[0].Id); err == nil {
+		t.Fatal("Expected the stale upload to be deleted")
+	}
+	if _, err := os.Stat(core.LocalUploadDir(app, uploads[0].Id)); !os.IsNotExist(err) {
+		t.Fatalf("Expected the stale upload dir to be deleted, got %v", err)
+	}
+
+	// active
+	if _, err := app.Dao().FindUploadById(uploads[1].Id); err != nil {
+		t.Fatalf("Expected the active upload to remain, got %v", err)
+	}
+	if _, err := os.Stat(core.LocalUploadDir(app, uploads[1].Id)); err != nil {
+		t.Fatalf("Expected the active upload dir to remain, got %v", err)
+	}
+}
@@ -2,6 +2,8 @@ package core
 
 import (
 	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/spf13/cast"
 )
 
 func initPragmas(db *dbx.DB) error {
@@ -18,3 +20,30 @@ func initPragmas(db *dbx.DB) error {
 
 	return err
 }
+
+// geoDistanceFunc is the implementation of the custom sqlite
+// [search.GeoDistanceFuncName] function.
+//
+// Returns nil if any of the coordinates is not a valid number.
+func geoDistanceFunc(lat1, lon1, lat2, lon2 any) any {
+	coords := make([]float64, 4)
+
+	for i, v := range []any{lat1, lon1, lat2, lon2} {
+		if b, ok := v.([]byte); ok {
+			v = string(b)
+		}
+
+		if v == nil || v == "" {
+			return nil
+		}
+
+		c, err := cast.ToFloat64E(v)
+		if err != nil {
+			return nil
+		}
+
+		coords[i] = c
+	}
+
+	return search.GeoDistance(coords[0], coords[1], coords[2], coords[3])
+}
@@ -3,16 +3,35 @@
 package core
 
 import (
+	"database/sql"
+
+	"github.com/mattn/go-sqlite3"
 	"github.com/pocketbase/dbx"
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/pocketbase/pocketbase/tools/search"
 )
 
+// cgoDriverName is the name of the sqlite3 driver with the registered app custom functions.
+const cgoDriverName = "pb_sqlite3"
+
+func init() {
+	sql.Register(cgoDriverName, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			return conn.RegisterFunc(search.GeoDistanceFuncName, func(lat1, lon1, lat2, lon2 any) any {
+				return geoDistanceFunc(lat1, lon1, lat2, lon2)
+			}, true)
+		},
+	})
+}
+
 func connectDB(dbPath string) (*dbx.DB, error) {
-	db, err := dbx.Open("sqlite3", dbPath)
+	sqlDB, err := sql.Open(cgoDriverName, dbPath)
 	if err != nil {
 		return nil, err
 	}
 
+	// use the default sqlite3 builder
+	db := dbx.NewFromDB(sqlDB, "sqlite3")
+
 	if err := initPragmas(db); err != nil {
 		db.Close()
 		return nil, err
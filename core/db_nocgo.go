@@ -3,10 +3,23 @@
 package core
 
 import (
+	"database/sql/driver"
+
 	"github.com/pocketbase/dbx"
-	_ "modernc.org/sqlite"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"modernc.org/sqlite"
 )
 
+func init() {
+	sqlite.MustRegisterDeterministicScalarFunction(
+		search.GeoDistanceFuncName,
+		4,
+		func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+			return geoDistanceFunc(args[0], args[1], args[2], args[3]), nil
+		},
+	)
+}
+
 func connectDB(dbPath string) (*dbx.DB, error) {
 	db, err := dbx.Open("sqlite", dbPath)
 	if err != nil {
@@ -0,0 +1,54 @@
+package core
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGeoDistanceFunc(t *testing.T) {
+	scenarios := []struct {
+		args     []any
+		expected any
+	}{
+		{[]any{nil, 0, 0, 0}, nil},
+		{[]any{0, []byte(nil), 0, 0}, nil},
+		{[]any{0, 0, "", 0}, nil},
+		{[]any{0, 0, 0, "invalid"}, nil},
+		{[]any{0, 0, 0, 0}, 0.0},
+		{[]any{int64(0), "0", []byte("0"), 180.0}, 20015.1},
+	}
+
+	for i, s := range scenarios {
+		result := geoDistanceFunc(s.args[0], s.args[1], s.args[2], s.args[3])
+
+		if s.expected == nil {
+			if result != nil {
+				t.Errorf("(%d) Expected nil, got %v", i, result)
+			}
+			continue
+		}
+
+		v, _ := result.(float64)
+		if math.Abs(v-s.expected.(float64)) > 0.1 {
+			t.Errorf("(%d) Expected %v, got %v", i, s.expected, result)
+		}
+	}
+}
+
+func TestConnectDBGeoDistance(t *testing.T) {
+	db, err := connectDB(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var distance float64
+	err = db.NewQuery("SELECT geo_distance(42.6977, 23.3219, 48.8566, '2.3522')").Row(&distance)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if math.Abs(distance-1757.2) > 0.1 {
+		t.Fatalf("Expected distance %v, got %v", 1757.2, distance)
+	}
+}
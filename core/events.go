@@ -151,6 +151,13 @@ type RecordsListEvent struct {
 	Result      *search.Result
 }
 
+type RecordsAggregateEvent struct {
+	BaseCollectionEvent
+
+	HttpContext echo.Context
+	Result      *search.Result
+}
+
 type RecordViewEvent struct {
 	BaseCollectionEvent
 
@@ -211,6 +218,15 @@ type RecordAuthWithOAuth2Event struct {
 	OAuth2User  *auth.AuthUser
 }
 
+type RecordAuthWithMfaEvent struct {
+	BaseCollectionEvent
+
+	HttpContext echo.Context
+	Record      *models.Record
+	Mfa         *models.Mfa
+	Code        string
+}
+
 type RecordAuthRefreshEvent struct {
 	BaseCollectionEvent
 
@@ -218,6 +234,22 @@ type RecordAuthRefreshEvent struct {
 	Record      *models.Record
 }
 
+type RecordRequestOTPEvent struct {
+	BaseCollectionEvent
+
+	HttpContext echo.Context
+	Record      *models.Record
+	OTP         *models.OTP
+}
+
+type RecordAuthWithOTPEvent struct {
+	BaseCollectionEvent
+
+	HttpContext echo.Context
+	Record      *models.Record
+	OTP         *models.OTP
+}
+
 type RecordRequestPasswordResetEvent struct {
 	BaseCollectionEvent
 
@@ -319,6 +351,13 @@ type AdminAuthWithPasswordEvent struct {
 	Password    string
 }
 
+type AdminAuthWithMfaEvent struct {
+	HttpContext echo.Context
+	Admin       *models.Admin
+	Mfa         *models.Mfa
+	Code        string
+}
+
 type AdminAuthRefreshEvent struct {
 	HttpContext echo.Context
 	Admin       *models.Admin
@@ -377,6 +416,20 @@ type CollectionsImportEvent struct {
 // File API events data
 // -------------------------------------------------------------------
 
+type FileTokenEvent struct {
+	HttpContext echo.Context
+	Model       models.Model // *models.Admin or *models.Record
+	Token       string
+}
+
+type FileUploadEvent struct {
+	BaseCollectionEvent
+
+	Record    *models.Record
+	FileField *schema.SchemaField
+	File      *filesystem.File
+}
+
 type FileDownloadEvent struct {
 	BaseCollectionEvent
 
@@ -119,7 +119,17 @@ func (dao *Dao) DeleteAdmin(admin *models.Admin) error {
 		return errors.New("You cannot delete the only existing admin.")
 	}
 
-	return dao.Delete(admin)
+	return dao.RunInTransaction(func(txDao *Dao) error {
+		// manually trigger delete on the linked mfa (if any)
+		// to ensure that the `OnModel*` hooks are triggered
+		if mfa, _ := txDao.FindMfaByAdmin(admin); mfa != nil {
+			if err := txDao.DeleteMfa(mfa); err != nil {
+				return err
+			}
+		}
+
+		return txDao.Delete(admin)
+	})
 }
 
 // SaveAdmin upserts the provided Admin model.
@@ -256,3 +256,26 @@ func TestSaveAdmin(t *testing.T) {
 		t.Fatalf("Expected admin email to be %s, got %s", updatedEmail, existingAdmin.Email)
 	}
 }
+
+func TestDeleteAdminWithMfa(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	admin, err := app.Dao().FindAdminById("sbmbsdb40jyxf7h")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mfa := &models.Mfa{RecordId: admin.Id, Secret: "test"}
+	if err := app.Dao().SaveMfa(mfa); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Dao().DeleteAdmin(admin); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.Dao().FindMfaByAdmin(admin); err == nil {
+		t.Fatal("Expected the admin mfa to be deleted")
+	}
+}
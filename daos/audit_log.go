@@ -0,0 +1,48 @@
+package daos
+
+import (
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// AuditLogQuery returns a new AuditLog select query.
+func (dao *Dao) AuditLogQuery() *dbx.SelectQuery {
+	return dao.ModelQuery(&models.AuditLog{})
+}
+
+// FindAuditLogById finds a single AuditLog by its id.
+func (dao *Dao) FindAuditLogById(id string) (*models.AuditLog, error) {
+	model := &models.AuditLog{}
+
+	err := dao.AuditLogQuery().
+		AndWhere(dbx.HashExp{"id": id}).
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// DeleteOldAuditLogs delete all audit logs that are created before createdBefore.
+func (dao *Dao) DeleteOldAuditLogs(createdBefore time.Time) error {
+	m := models.AuditLog{}
+	tableName := m.TableName()
+
+	formattedDate := createdBefore.UTC().Format(types.DefaultDateLayout)
+	expr := dbx.NewExp("[[created]] <= {:date}", dbx.Params{"date": formattedDate})
+
+	_, err := dao.DB().Delete(tableName, expr).Execute()
+
+	return err
+}
+
+// SaveAuditLog upserts the provided AuditLog model.
+func (dao *Dao) SaveAuditLog(log *models.AuditLog) error {
+	return dao.Save(log)
+}
@@ -0,0 +1,117 @@
+package daos_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestAuditLogQuery(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	expected := "SELECT {{_auditLogs}}.* FROM `_auditLogs`"
+
+	sql := app.LogsDao().AuditLogQuery().Build().SQL()
+	if sql != expected {
+		t.Errorf("Expected sql %s, got %s", expected, sql)
+	}
+}
+
+func TestFindAuditLogById(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	tests.MockAuditLogsData(app)
+
+	scenarios := []struct {
+		id          string
+		expectError bool
+	}{
+		{"", true},
+		{"missing", true},
+		{"p3sbvx0qm1fhz5c", false},
+		{"w9z0c6e5bkr2yjd", false},
+	}
+
+	for _, s := range scenarios {
+		log, err := app.LogsDao().FindAuditLogById(s.id)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.id, s.expectError, hasErr, err)
+			continue
+		}
+
+		if log != nil && log.Id != s.id {
+			t.Errorf("[%s] Expected audit log with id %s, got %s", s.id, s.id, log.Id)
+		}
+	}
+}
+
+func TestDeleteOldAuditLogs(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	tests.MockAuditLogsData(app)
+
+	scenarios := []struct {
+		date          string
+		expectedTotal int
+	}{
+		{"2022-01-01 10:00:00.000Z", 2},
+		{"2022-05-01 11:00:00.000Z", 1},
+		{"2022-05-03 11:00:00.000Z", 0},
+	}
+
+	for i, s := range scenarios {
+		date, err := time.Parse(types.DefaultDateLayout, s.date)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := app.LogsDao().DeleteOldAuditLogs(date); err != nil {
+			t.Errorf("(%d) Delete error %v", i, err)
+		}
+
+		var total int
+		if err := app.LogsDao().AuditLogQuery().Select("count(*)").Row(&total); err != nil {
+			t.Errorf("(%d) Count error %v", i, err)
+		}
+
+		if total != s.expectedTotal {
+			t.Errorf("(%d) Expected %d remaining audit logs, got %d", i, s.expectedTotal, total)
+		}
+	}
+}
+
+func TestSaveAuditLog(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	log := &models.AuditLog{
+		Action:    models.AuditActionCreate,
+		RecordId:  "test",
+		ActorType: models.AuditActorSystem,
+		Changes:   types.JsonMap{"title": map[string]any{"old": nil, "new": "test"}},
+	}
+	if err := app.LogsDao().SaveAuditLog(log); err != nil {
+		t.Fatal(err)
+	}
+
+	existing, err := app.LogsDao().FindAuditLogById(log.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if existing.Action != log.Action || existing.RecordId != log.RecordId {
+		t.Fatalf("Expected the audit log to be persisted, got %v", existing)
+	}
+
+	if existing.Changes["title"] == nil {
+		t.Fatalf("Expected the changes to be persisted, got %v", existing.Changes)
+	}
+}
@@ -43,6 +43,10 @@ type Dao struct {
 	// This field has no effect if an explicit query context is already specified.
 	ModelQueryTimeout time.Duration
 
+	// afterCommitFuncs holds the functions that will be executed after
+	// a successful commit of the current transaction (nil if not in a transaction).
+	afterCommitFuncs *[]func(dao *Dao) error
+
 	BeforeCreateFunc func(eventDao *Dao, m models.Model) error
 	AfterCreateFunc  func(eventDao *Dao, m models.Model)
 	BeforeUpdateFunc func(eventDao *Dao, m models.Model) error
@@ -116,13 +120,16 @@ func (dao *Dao) RunInTransaction(fn func(txDao *Dao) error) error {
 		txDao.AfterCreateFunc = dao.AfterCreateFunc
 		txDao.AfterUpdateFunc = dao.AfterUpdateFunc
 		txDao.AfterDeleteFunc = dao.AfterDeleteFunc
+		txDao.afterCommitFuncs = dao.afterCommitFuncs
 
 		return fn(txDao)
 	case *dbx.DB:
 		afterCalls := []afterCallGroup{}
+		afterCommitFuncs := []func(dao *Dao) error{}
 
 		txError := txOrDB.Transactional(func(tx *dbx.Tx) error {
 			txDao := New(tx)
+			txDao.afterCommitFuncs = &afterCommitFuncs
 
 			if dao.BeforeCreateFunc != nil {
 				txDao.BeforeCreateFunc = func(eventDao *Dao, m models.Model) error {
@@ -172,6 +179,10 @@ func (dao *Dao) RunInTransaction(fn func(txDao *Dao) error) error {
 					dao.AfterDeleteFunc(dao, call.Model)
 				}
 			}
+
+			for _, fn := range afterCommitFuncs {
+				fn(dao)
+			}
 		}
 
 		return txError
@@ -180,6 +191,25 @@ func (dao *Dao) RunInTransaction(fn func(txDao *Dao) error) error {
 	return errors.New("failed to start transaction (unknown dao.NonconcurrentDB() instance)")
 }
 
+// AfterCommit registers fn to be executed after the successful commit
+// of the dao transaction (fn is discarded if the transaction fails).
+//
+// The deferred fn is called with the non-transaction dao (the one that
+// started the transaction) and its error is ignored so fn is expected
+// to handle it on its own.
+//
+// If the dao is not part of a transaction started with [Dao.RunInTransaction],
+// fn is executed immediately with the current dao and its error is returned.
+func (dao *Dao) AfterCommit(fn func(dao *Dao) error) error {
+	if dao.afterCommitFuncs == nil {
+		return fn(dao)
+	}
+
+	*dao.afterCommitFuncs = append(*dao.afterCommitFuncs, fn)
+
+	return nil
+}
+
 // Delete deletes the provided model.
 func (dao *Dao) Delete(m models.Model) error {
 	if !m.HasId() {
@@ -170,6 +170,68 @@ func TestDaoRunInTransaction(t *testing.T) {
 	}
 }
 
+func TestDaoAfterCommit(t *testing.T) {
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	// outside of a transaction
+	calls := 0
+	err := testApp.Dao().AfterCommit(func(dao *daos.Dao) error {
+		calls++
+		if dao != testApp.Dao() {
+			t.Fatal("Expected the current dao")
+		}
+		return errors.New("test error")
+	})
+	if err == nil || calls != 1 {
+		t.Fatalf("Expected immediate call with returned error, got %d calls (%v)", calls, err)
+	}
+
+	// failed transaction
+	calls = 0
+	testApp.Dao().RunInTransaction(func(txDao *daos.Dao) error {
+		txDao.AfterCommit(func(dao *daos.Dao) error {
+			calls++
+			return nil
+		})
+		return errors.New("test error")
+	})
+	if calls != 0 {
+		t.Fatalf("Expected no calls for failed transaction, got %d", calls)
+	}
+
+	// successful nested transaction
+	calls = 0
+	testApp.Dao().RunInTransaction(func(txDao *daos.Dao) error {
+		txDao.AfterCommit(func(dao *daos.Dao) error {
+			calls++
+			return nil
+		})
+
+		err := txDao.RunInTransaction(func(tx2Dao *daos.Dao) error {
+			return tx2Dao.AfterCommit(func(dao *daos.Dao) error {
+				calls++
+
+				if dao != testApp.Dao() {
+					t.Fatal("Expected the non-transaction dao")
+				}
+
+				_, err := dao.FindAdminByEmail("test@example.com")
+				return err
+			})
+		})
+
+		if calls != 0 {
+			t.Fatalf("Expected no calls before the commit, got %d", calls)
+		}
+
+		return err
+	})
+	if calls != 2 {
+		t.Fatalf("Expected 2 calls after the commit, got %d", calls)
+	}
+}
+
 func TestDaoSaveCreate(t *testing.T) {
 	testApp, _ := tests.NewTestApp()
 	defer testApp.Cleanup()
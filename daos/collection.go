@@ -146,6 +146,10 @@ func (dao *Dao) DeleteCollection(collection *models.Collection) error {
 			if err := txDao.DeleteTable(collection.Name); err != nil {
 				return err
 			}
+
+			if err := txDao.deleteRecordTableSearchIndex(collection); err != nil {
+				return err
+			}
 		}
 
 		return txDao.Delete(collection)
@@ -297,6 +301,10 @@ func (dao *Dao) ImportCollections(
 					if err := txDao.DeleteTable(existing.Name); err != nil {
 						return err
 					}
+
+					if err := txDao.deleteRecordTableSearchIndex(existing); err != nil {
+						return err
+					}
 				}
 			}
 		}
@@ -0,0 +1,121 @@
+package daos
+
+import (
+	"errors"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+)
+
+// FileRefQuery returns a new FileRef select query.
+func (dao *Dao) FileRefQuery() *dbx.SelectQuery {
+	return dao.ModelQuery(&models.FileRef{})
+}
+
+// FindFileRef returns the blob reference of a single record file.
+func (dao *Dao) FindFileRef(collectionId string, recordId string, filename string) (*models.FileRef, error) {
+	model := &models.FileRef{}
+
+	err := dao.FileRefQuery().
+		AndWhere(dbx.HashExp{
+			"collectionId": collectionId,
+			"recordId":     recordId,
+			"filename":     filename,
+		}).
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// FindFileRefsByRecord returns all blob references of the provided record files.
+func (dao *Dao) FindFileRefsByRecord(record *models.Record) ([]*models.FileRef, error) {
+	models := []*models.FileRef{}
+
+	err := dao.FileRefQuery().
+		AndWhere(dbx.HashExp{
+			"collectionId": record.Collection().Id,
+			"recordId":     record.Id,
+		}).
+		All(&models)
+
+	return models, err
+}
+
+// FindFileRefsByCollection returns all blob references of the provided collection records files.
+func (dao *Dao) FindFileRefsByCollection(collection *models.Collection) ([]*models.FileRef, error) {
+	models := []*models.FileRef{}
+
+	err := dao.FileRefQuery().
+		AndWhere(dbx.HashExp{"collectionId": collection.Id}).
+		All(&models)
+
+	return models, err
+}
+
+// FindFileRefHashes returns all unique referenced blob hashes.
+func (dao *Dao) FindFileRefHashes() ([]string, error) {
+	hashes := []string{}
+
+	err := dao.FileRefQuery().
+		Select("hash").
+		Distinct(true).
+		OrderBy("hash ASC").
+		Column(&hashes)
+
+	return hashes, err
+}
+
+// TotalFileRefsByHash returns the number of the references to the blob with the specified hash.
+func (dao *Dao) TotalFileRefsByHash(hash string) (int, error) {
+	var total int
+
+	err := dao.FileRefQuery().
+		Select("count(*)").
+		AndWhere(dbx.HashExp{"hash": hash}).
+		Row(&total)
+
+	return total, err
+}
+
+// LockFileRefsByHash acquires the db write lock and returns the
+// number of the references to the blob with the specified hash.
+//
+// When called within a transaction the lock is held until the transaction
+// completes, preventing the creation of new references to the blob
+// from other connections and processes (eg. while the blob is being deleted).
+func (dao *Dao) LockFileRefsByHash(hash string) (int, error) {
+	// the update is a no-op but it acquires the write lock
+	// even if there are no matching rows
+	result, err := dao.NonconcurrentDB().
+		NewQuery("UPDATE {{_fileRefs}} SET [[hash]] = [[hash]] WHERE [[hash]] = {:hash}").
+		Bind(dbx.Params{"hash": hash}).
+		Execute()
+	if err != nil {
+		return 0, err
+	}
+
+	total, err := result.RowsAffected()
+
+	return int(total), err
+}
+
+// SaveFileRef upserts the provided FileRef model.
+func (dao *Dao) SaveFileRef(model *models.FileRef) error {
+	if model.CollectionId == "" || model.RecordId == "" || model.Filename == "" || model.Hash == "" {
+		return errors.New("Missing required FileRef fields.")
+	}
+
+	return dao.Save(model)
+}
+
+// DeleteFileRef deletes the provided FileRef model.
+//
+// Note that the referenced blob is not deleted.
+func (dao *Dao) DeleteFileRef(model *models.FileRef) error {
+	return dao.Delete(model)
+}
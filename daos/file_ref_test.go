@@ -0,0 +1,219 @@
+package daos_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func createTestFileRefs(t *testing.T, app *tests.TestApp) []*models.FileRef {
+	refs := []*models.FileRef{
+		{CollectionId: "_pb_users_auth_", RecordId: "4q1xlclmfloku33", Filename: "a.txt", Hash: "hash1"},
+		{CollectionId: "_pb_users_auth_", RecordId: "4q1xlclmfloku33", Filename: "b.txt", Hash: "hash2"},
+		{CollectionId: "_pb_users_auth_", RecordId: "oap640cot4yru2s", Filename: "c.txt", Hash: "hash1"},
+		{CollectionId: "wsmn24bux7wo113", RecordId: "84nmscqy84lsi1t", Filename: "d.txt", Hash: "hash3"},
+	}
+
+	for _, ref := range refs {
+		if err := app.Dao().SaveFileRef(ref); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return refs
+}
+
+func TestFileRefQuery(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	expected := "SELECT {{_fileRefs}}.* FROM `_fileRefs`"
+
+	sql := app.Dao().FileRefQuery().Build().SQL()
+	if sql != expected {
+		t.Errorf("Expected sql %s, got %s", expected, sql)
+	}
+}
+
+func TestSaveFileRef(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		name        string
+		model       *models.FileRef
+		expectError bool
+	}{
+		{"empty", &models.FileRef{}, true},
+		{"missing hash", &models.FileRef{CollectionId: "c", RecordId: "r", Filename: "f"}, true},
+		{"valid", &models.FileRef{CollectionId: "c", RecordId: "r", Filename: "f", Hash: "h"}, false},
+		{"duplicated record file", &models.FileRef{CollectionId: "c", RecordId: "r", Filename: "f", Hash: "h2"}, true},
+	}
+
+	for _, s := range scenarios {
+		err := app.Dao().SaveFileRef(s.model)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+		}
+	}
+}
+
+func TestFindFileRef(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	createTestFileRefs(t, app)
+
+	scenarios := []struct {
+		collectionId string
+		recordId     string
+		filename     string
+		expectHash   string
+	}{
+		{"", "", "", ""},
+		{"_pb_users_auth_", "4q1xlclmfloku33", "missing.txt", ""},
+		{"_pb_users_auth_", "oap640cot4yru2s", "a.txt", ""},
+		{"_pb_users_auth_", "4q1xlclmfloku33", "a.txt", "hash1"},
+		{"wsmn24bux7wo113", "84nmscqy84lsi1t", "d.txt", "hash3"},
+	}
+
+	for i, s := range scenarios {
+		ref, err := app.Dao().FindFileRef(s.collectionId, s.recordId, s.filename)
+
+		if s.expectHash == "" {
+			if err == nil {
+				t.Errorf("(%d) Expected error, got %v", i, ref)
+			}
+			continue
+		}
+
+		if err != nil || ref.Hash != s.expectHash {
+			t.Errorf("(%d) Expected ref with hash %q, got %v (%v)", i, s.expectHash, ref, err)
+		}
+	}
+}
+
+func TestFindFileRefsByRecordAndCollection(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	createTestFileRefs(t, app)
+
+	record, err := app.Dao().FindRecordById("users", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recordRefs, err := app.Dao().FindFileRefsByRecord(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recordRefs) != 2 {
+		t.Fatalf("Expected 2 record refs, got %d", len(recordRefs))
+	}
+
+	collectionRefs, err := app.Dao().FindFileRefsByCollection(record.Collection())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(collectionRefs) != 3 {
+		t.Fatalf("Expected 3 collection refs, got %d", len(collectionRefs))
+	}
+}
+
+func TestFindFileRefHashes(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	createTestFileRefs(t, app)
+
+	hashes, err := app.Dao().FindFileRefHashes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"hash1", "hash2", "hash3"}
+	if len(hashes) != len(expected) {
+		t.Fatalf("Expected hashes %v, got %v", expected, hashes)
+	}
+	for i, hash := range expected {
+		if hashes[i] != hash {
+			t.Fatalf("Expected hashes %v, got %v", expected, hashes)
+		}
+	}
+}
+
+func TestTotalFileRefsByHashAndDeleteFileRef(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	refs := createTestFileRefs(t, app)
+
+	scenarios := []struct {
+		hash     string
+		expected int
+	}{
+		{"missing", 0},
+		{"hash1", 2},
+		{"hash2", 1},
+	}
+
+	for _, s := range scenarios {
+		total, err := app.Dao().TotalFileRefsByHash(s.hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != s.expected {
+			t.Errorf("(%s) Expected %d refs, got %d", s.hash, s.expected, total)
+		}
+	}
+
+	if err := app.Dao().DeleteFileRef(refs[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	if total, _ := app.Dao().TotalFileRefsByHash("hash1"); total != 1 {
+		t.Fatalf("Expected 1 hash1 ref after the delete, got %d", total)
+	}
+}
+
+func TestLockFileRefsByHash(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	createTestFileRefs(t, app)
+
+	scenarios := []struct {
+		hash     string
+		expected int
+	}{
+		{"missing", 0},
+		{"hash1", 2},
+		{"hash3", 1},
+	}
+
+	for _, s := range scenarios {
+		err := app.Dao().RunInTransaction(func(txDao *daos.Dao) error {
+			total, err := txDao.LockFileRefsByHash(s.hash)
+			if err != nil {
+				return err
+			}
+			if total != s.expected {
+				t.Errorf("(%s) Expected %d refs, got %d", s.hash, s.expected, total)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("(%s) %v", s.hash, err)
+		}
+	}
+
+	// the refs should remain unchanged
+	if total, _ := app.Dao().TotalFileRefsByHash("hash1"); total != 2 {
+		t.Fatalf("Expected 2 hash1 refs, got %d", total)
+	}
+}
@@ -0,0 +1,84 @@
+package daos
+
+import (
+	"errors"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/list"
+)
+
+// ImagePlaceholderQuery returns a new ImagePlaceholder select query.
+func (dao *Dao) ImagePlaceholderQuery() *dbx.SelectQuery {
+	return dao.ModelQuery(&models.ImagePlaceholder{})
+}
+
+// FindImagePlaceholder returns the placeholder of a single record file.
+func (dao *Dao) FindImagePlaceholder(collectionId string, recordId string, filename string) (*models.ImagePlaceholder, error) {
+	model := &models.ImagePlaceholder{}
+
+	err := dao.ImagePlaceholderQuery().
+		AndWhere(dbx.HashExp{
+			"collectionId": collectionId,
+			"recordId":     recordId,
+			"filename":     filename,
+		}).
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// FindImagePlaceholdersByRecords returns all placeholders
+// of the specified collection records.
+func (dao *Dao) FindImagePlaceholdersByRecords(collectionId string, recordIds ...string) ([]*models.ImagePlaceholder, error) {
+	models := []*models.ImagePlaceholder{}
+
+	if len(recordIds) == 0 {
+		return models, nil
+	}
+
+	err := dao.ImagePlaceholderQuery().
+		AndWhere(dbx.HashExp{"collectionId": collectionId}).
+		AndWhere(dbx.In("recordId", list.ToInterfaceSlice(recordIds)...)).
+		All(&models)
+
+	return models, err
+}
+
+// SaveImagePlaceholder upserts the provided ImagePlaceholder model.
+//
+// If a placeholder for the same record file already exists, it is replaced.
+func (dao *Dao) SaveImagePlaceholder(model *models.ImagePlaceholder) error {
+	if model.CollectionId == "" || model.RecordId == "" || model.Field == "" || model.Filename == "" {
+		return errors.New("Missing required ImagePlaceholder fields.")
+	}
+
+	if model.IsNew() {
+		existing, _ := dao.FindImagePlaceholder(model.CollectionId, model.RecordId, model.Filename)
+		if existing != nil && existing.Id != model.Id {
+			model.Id = existing.Id
+			model.Created = existing.Created
+			model.MarkAsNotNew()
+		}
+	}
+
+	return dao.Save(model)
+}
+
+// DeleteImagePlaceholdersByRecord deletes all placeholders of the provided record.
+//
+// Note that the ImagePlaceholder models are deleted with a single query
+// and no model hooks are triggered.
+func (dao *Dao) DeleteImagePlaceholdersByRecord(record *models.Record) error {
+	_, err := dao.DB().Delete((&models.ImagePlaceholder{}).TableName(), dbx.HashExp{
+		"collectionId": record.Collection().Id,
+		"recordId":     record.Id,
+	}).Execute()
+
+	return err
+}
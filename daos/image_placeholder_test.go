@@ -0,0 +1,131 @@
+package daos_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestImagePlaceholderQuery(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	expected := "SELECT {{_imagePlaceholders}}.* FROM `_imagePlaceholders`"
+
+	sql := app.Dao().ImagePlaceholderQuery().Build().SQL()
+	if sql != expected {
+		t.Errorf("Expected sql %s, got %s", expected, sql)
+	}
+}
+
+func TestSaveImagePlaceholder(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		name        string
+		model       *models.ImagePlaceholder
+		expectError bool
+	}{
+		{"empty", &models.ImagePlaceholder{}, true},
+		{"missing filename", &models.ImagePlaceholder{CollectionId: "c", RecordId: "r", Field: "f"}, true},
+		{"valid", &models.ImagePlaceholder{CollectionId: "c", RecordId: "r", Field: "f", Filename: "a.png", Color: "#000000"}, false},
+	}
+
+	for _, s := range scenarios {
+		err := app.Dao().SaveImagePlaceholder(s.model)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+		}
+	}
+
+	// upsert of an existing record file
+	replacement := &models.ImagePlaceholder{CollectionId: "c", RecordId: "r", Field: "f", Filename: "a.png", Color: "#ffffff"}
+	if err := app.Dao().SaveImagePlaceholder(replacement); err != nil {
+		t.Fatal(err)
+	}
+
+	placeholders, err := app.Dao().FindImagePlaceholdersByRecords("c", "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(placeholders) != 1 {
+		t.Fatalf("Expected 1 placeholder, got %d", len(placeholders))
+	}
+	if placeholders[0].Color != "#ffffff" {
+		t.Fatalf("Expected the placeholder to be replaced, got %v", placeholders[0])
+	}
+}
+
+func TestFindImagePlaceholdersByRecords(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	items := []*models.ImagePlaceholder{
+		{CollectionId: "c1", RecordId: "r1", Field: "f", Filename: "a.png"},
+		{CollectionId: "c1", RecordId: "r1", Field: "f", Filename: "b.png"},
+		{CollectionId: "c1", RecordId: "r2", Field: "f", Filename: "c.png"},
+		{CollectionId: "c2", RecordId: "r1", Field: "f", Filename: "d.png"},
+	}
+	for _, item := range items {
+		if err := app.Dao().SaveImagePlaceholder(item); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scenarios := []struct {
+		collectionId string
+		recordIds    []string
+		expected     int
+	}{
+		{"c1", nil, 0},
+		{"missing", []string{"r1"}, 0},
+		{"c1", []string{"r1"}, 2},
+		{"c1", []string{"r1", "r2"}, 3},
+		{"c2", []string{"r1", "r2"}, 1},
+	}
+
+	for i, s := range scenarios {
+		result, err := app.Dao().FindImagePlaceholdersByRecords(s.collectionId, s.recordIds...)
+		if err != nil {
+			t.Errorf("(%d) Unexpected error %v", i, err)
+			continue
+		}
+
+		if len(result) != s.expected {
+			t.Errorf("(%d) Expected %d placeholders, got %d", i, s.expected, len(result))
+		}
+	}
+}
+
+func TestDeleteImagePlaceholdersByRecord(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, err := app.Dao().FindRecordById("demo1", "al1h9ijdeojtsjy")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items := []*models.ImagePlaceholder{
+		{CollectionId: record.Collection().Id, RecordId: record.Id, Field: "file_one", Filename: "a.png"},
+		{CollectionId: record.Collection().Id, RecordId: "other", Field: "file_one", Filename: "b.png"},
+	}
+	for _, item := range items {
+		if err := app.Dao().SaveImagePlaceholder(item); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := app.Dao().DeleteImagePlaceholdersByRecord(record); err != nil {
+		t.Fatal(err)
+	}
+
+	result, _ := app.Dao().FindImagePlaceholdersByRecords(record.Collection().Id, record.Id, "other")
+	if len(result) != 1 || result[0].RecordId != "other" {
+		t.Fatalf("Expected only the other record placeholder to remain, got %v", result)
+	}
+}
@@ -0,0 +1,177 @@
+package daos
+
+import (
+	"errors"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// MfaQuery returns a new Mfa select query.
+func (dao *Dao) MfaQuery() *dbx.SelectQuery {
+	return dao.ModelQuery(&models.Mfa{})
+}
+
+// FindMfaByRecord returns the Mfa model linked to the provided auth record.
+func (dao *Dao) FindMfaByRecord(authRecord *models.Record) (*models.Mfa, error) {
+	return dao.findMfa(authRecord.Collection().Id, authRecord.Id)
+}
+
+// FindMfaByAdmin returns the Mfa model linked to the provided admin.
+func (dao *Dao) FindMfaByAdmin(admin *models.Admin) (*models.Mfa, error) {
+	return dao.findMfa("", admin.Id)
+}
+
+func (dao *Dao) findMfa(collectionId string, recordId string) (*models.Mfa, error) {
+	model := &models.Mfa{}
+
+	err := dao.MfaQuery().
+		AndWhere(dbx.HashExp{
+			"collectionId": collectionId,
+			"recordId":     recordId,
+		}).
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// SaveMfa upserts the provided Mfa model.
+func (dao *Dao) SaveMfa(model *models.Mfa) error {
+	if model.RecordId == "" || model.Secret == "" {
+		return errors.New("Missing required Mfa fields.")
+	}
+
+	return dao.Save(model)
+}
+
+// DeleteMfa deletes the provided Mfa model.
+func (dao *Dao) DeleteMfa(model *models.Mfa) error {
+	return dao.Delete(model)
+}
+
+// IncrementMfaAttempts atomically increments the code verification attempts
+// counter of the provided Mfa model and returns its new value.
+//
+// The counter is not incremented and [sql.ErrNoRows] is returned
+// if the Mfa is locked or it has already reached maxAttempts.
+//
+// Note that the Mfa model is updated with a single query
+// and no model hooks are triggered.
+func (dao *Dao) IncrementMfaAttempts(model *models.Mfa, maxAttempts int) (int, error) {
+	var attempts int
+
+	err := dao.DB().NewQuery(`
+		UPDATE {{_mfas}}
+		SET [[attempts]] = [[attempts]] + 1
+		WHERE [[id]] = {:id} AND [[attempts]] < {:maxAttempts} AND [[lockedUntil]] < {:now}
+		RETURNING [[attempts]]
+	`).Bind(dbx.Params{
+		"id":          model.Id,
+		"maxAttempts": maxAttempts,
+		"now":         types.NowDateTime().String(),
+	}).Row(&attempts)
+
+	if err != nil {
+		return 0, err
+	}
+
+	model.Attempts = attempts
+
+	return attempts, nil
+}
+
+// LockMfa resets the attempts counter of the provided Mfa model
+// and rejects all code verifications until the specified date.
+//
+// Note that the Mfa model is updated with a single query
+// and no model hooks are triggered.
+func (dao *Dao) LockMfa(model *models.Mfa, until time.Time) error {
+	lockedUntil, err := types.ParseDateTime(until)
+	if err != nil {
+		return err
+	}
+
+	_, err = dao.DB().Update(
+		model.TableName(),
+		dbx.Params{"attempts": 0, "lockedUntil": lockedUntil.String()},
+		dbx.HashExp{"id": model.Id},
+	).Execute()
+	if err != nil {
+		return err
+	}
+
+	model.Attempts = 0
+	model.LockedUntil = lockedUntil
+
+	return nil
+}
+
+// UseMfaStep atomically marks the provided TOTP time step as used
+// (resetting the attempts counter) and reports whether the step
+// wasn't already used, aka. it is newer than the last accepted one.
+//
+// Note that the Mfa model is updated with a single query
+// and no model hooks are triggered.
+func (dao *Dao) UseMfaStep(model *models.Mfa, step int64) (bool, error) {
+	result, err := dao.DB().NewQuery(`
+		UPDATE {{_mfas}}
+		SET [[lastStep]] = {:step}, [[attempts]] = 0
+		WHERE [[id]] = {:id} AND [[lastStep]] < {:step}
+	`).Bind(dbx.Params{
+		"id":   model.Id,
+		"step": step,
+	}).Execute()
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil || affected == 0 {
+		return false, err
+	}
+
+	model.LastStep = step
+	model.Attempts = 0
+
+	return true, nil
+}
+
+// UseMfaRecoveryCode atomically removes the provided recovery code from
+// the stored unused recovery codes of the Mfa model and resets its attempts.
+//
+// Returns false if the code is not one of the unused recovery codes
+// (eg. it was already used by a concurrent request).
+func (dao *Dao) UseMfaRecoveryCode(model *models.Mfa, code string) (bool, error) {
+	result, err := dao.DB().NewQuery(`
+		UPDATE {{_mfas}}
+		SET [[recoveryCodes]] = (
+			SELECT json_group_array([[value]]) FROM json_each({{_mfas}}.[[recoveryCodes]]) WHERE [[value]] != {:hash}
+		), [[attempts]] = 0
+		WHERE [[id]] = {:id} AND EXISTS (
+			SELECT 1 FROM json_each({{_mfas}}.[[recoveryCodes]]) WHERE [[value]] = {:hash}
+		)
+	`).Bind(dbx.Params{
+		"id":   model.Id,
+		"hash": models.HashMfaRecoveryCode(code),
+	}).Execute()
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil || affected == 0 {
+		return false, err
+	}
+
+	model.UseRecoveryCode(code)
+	model.Attempts = 0
+
+	return true, nil
+}
@@ -0,0 +1,296 @@
+package daos_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestMfaQuery(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	expected := "SELECT {{_mfas}}.* FROM `_mfas`"
+
+	sql := app.Dao().MfaQuery().Build().SQL()
+	if sql != expected {
+		t.Errorf("Expected sql %s, got %s", expected, sql)
+	}
+}
+
+func TestFindMfaByRecord(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, err := app.Dao().FindRecordById("users", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.Dao().FindMfaByRecord(record); err == nil {
+		t.Fatal("Expected error for missing record mfa")
+	}
+
+	// admin with the same id shouldn't be matched
+	adminMfa := &models.Mfa{RecordId: record.Id, Secret: "test"}
+	if err := app.Dao().SaveMfa(adminMfa); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.Dao().FindMfaByRecord(record); err == nil {
+		t.Fatal("Expected error for missing record mfa (found admin mfa)")
+	}
+
+	recordMfa := &models.Mfa{CollectionId: record.Collection().Id, RecordId: record.Id, Secret: "test"}
+	if err := app.Dao().SaveMfa(recordMfa); err != nil {
+		t.Fatal(err)
+	}
+
+	mfa, err := app.Dao().FindMfaByRecord(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if mfa.Id != recordMfa.Id {
+		t.Fatalf("Expected mfa %q, got %q", recordMfa.Id, mfa.Id)
+	}
+}
+
+func TestFindMfaByAdmin(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	admin, err := app.Dao().FindAdminById("sywbhecnh46rhm0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.Dao().FindMfaByAdmin(admin); err == nil {
+		t.Fatal("Expected error for missing admin mfa")
+	}
+
+	adminMfa := &models.Mfa{RecordId: admin.Id, Secret: "test"}
+	if err := app.Dao().SaveMfa(adminMfa); err != nil {
+		t.Fatal(err)
+	}
+
+	mfa, err := app.Dao().FindMfaByAdmin(admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if mfa.Id != adminMfa.Id {
+		t.Fatalf("Expected mfa %q, got %q", adminMfa.Id, mfa.Id)
+	}
+}
+
+func TestSaveMfa(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	// save with empty data
+	if err := app.Dao().SaveMfa(&models.Mfa{}); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	mfa := &models.Mfa{
+		CollectionId: "_pb_users_auth_",
+		RecordId:     "4q1xlclmfloku33",
+		Secret:       "test",
+		Verified:     true,
+	}
+	mfa.SetRecoveryCodes([]string{"a", "b"})
+
+	if err := app.Dao().SaveMfa(mfa); err != nil {
+		t.Fatal(err)
+	}
+
+	// check if it was really saved
+	saved, err := app.Dao().FindFirstRecordByData("users", "id", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+	found, err := app.Dao().FindMfaByRecord(saved)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !found.Verified || found.Secret != "test" || len(found.RecoveryCodes) != 2 {
+		t.Fatalf("Expected the saved mfa to match the original one, got %v", found)
+	}
+}
+
+func TestDeleteMfa(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	mfa := &models.Mfa{RecordId: "sywbhecnh46rhm0", Secret: "test"}
+	if err := app.Dao().SaveMfa(mfa); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Dao().DeleteMfa(mfa); err != nil {
+		t.Fatal(err)
+	}
+
+	total := 0
+	app.Dao().MfaQuery().Select("count(*)").Row(&total)
+	if total != 0 {
+		t.Fatalf("Expected no mfa models, got %d", total)
+	}
+}
+
+func TestIncrementMfaAttempts(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	mfa := &models.Mfa{RecordId: "sywbhecnh46rhm0", Secret: "test"}
+	if err := app.Dao().SaveMfa(mfa); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 1; i <= 2; i++ {
+		attempts, err := app.Dao().IncrementMfaAttempts(mfa, 2)
+		if err != nil {
+			t.Fatalf("(%d) Expected nil error, got %v", i, err)
+		}
+		if attempts != i || mfa.Attempts != i {
+			t.Fatalf("(%d) Expected %d attempts, got %d (model %d)", i, i, attempts, mfa.Attempts)
+		}
+	}
+
+	// max attempts reached
+	if _, err := app.Dao().IncrementMfaAttempts(mfa, 2); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Expected sql.ErrNoRows, got %v", err)
+	}
+
+	// locked
+	if err := app.Dao().LockMfa(mfa, time.Now().Add(1*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.Dao().IncrementMfaAttempts(mfa, 2); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Expected sql.ErrNoRows for locked mfa, got %v", err)
+	}
+
+	// expired lock
+	if err := app.Dao().LockMfa(mfa, time.Now().Add(-1*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if attempts, err := app.Dao().IncrementMfaAttempts(mfa, 2); err != nil || attempts != 1 {
+		t.Fatalf("Expected 1 attempt after the lock expiration, got %d (%v)", attempts, err)
+	}
+}
+
+func TestLockMfa(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	mfa := &models.Mfa{RecordId: "sywbhecnh46rhm0", Secret: "test", Attempts: 3}
+	if err := app.Dao().SaveMfa(mfa); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Dao().LockMfa(mfa, time.Now().Add(1*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	refreshed, err := app.Dao().FindMfaByAdmin(&models.Admin{BaseModel: models.BaseModel{Id: "sywbhecnh46rhm0"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !refreshed.IsLocked() || refreshed.Attempts != 0 {
+		t.Fatalf("Expected locked mfa with reset attempts, got %v (%d attempts)", refreshed.LockedUntil, refreshed.Attempts)
+	}
+}
+
+func TestUseMfaStep(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	mfa := &models.Mfa{RecordId: "sywbhecnh46rhm0", Secret: "test", Attempts: 3}
+	if err := app.Dao().SaveMfa(mfa); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		step     int64
+		expected bool
+	}{
+		{10, true},
+		{10, false}, // replay
+		{9, false},  // older step
+		{11, true},
+	}
+
+	for i, s := range scenarios {
+		result, err := app.Dao().UseMfaStep(mfa, s.step)
+		if err != nil {
+			t.Fatalf("(%d) Expected nil error, got %v", i, err)
+		}
+		if result != s.expected {
+			t.Fatalf("(%d) Expected %v, got %v", i, s.expected, result)
+		}
+	}
+
+	refreshed, err := app.Dao().FindMfaByAdmin(&models.Admin{BaseModel: models.BaseModel{Id: "sywbhecnh46rhm0"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if refreshed.LastStep != 11 || refreshed.Attempts != 0 {
+		t.Fatalf("Expected lastStep 11 with reset attempts, got %d (%d attempts)", refreshed.LastStep, refreshed.Attempts)
+	}
+}
+
+func TestUseMfaRecoveryCode(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	mfa := &models.Mfa{RecordId: "sywbhecnh46rhm0", Secret: "test", Attempts: 3}
+	mfa.SetRecoveryCodes([]string{"code1", "code2", "code3"})
+	if err := app.Dao().SaveMfa(mfa); err != nil {
+		t.Fatal(err)
+	}
+
+	// load a second copy to simulate a concurrent request
+	concurrent, err := app.Dao().FindMfaByAdmin(&models.Admin{BaseModel: models.BaseModel{Id: "sywbhecnh46rhm0"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		mfa      *models.Mfa
+		code     string
+		expected bool
+	}{
+		{mfa, "missing", false},
+		{mfa, "code2", true},
+		{mfa, "code2", false},        // replay
+		{concurrent, "code2", false}, // stale model
+		{concurrent, "code1", true},
+	}
+
+	for i, s := range scenarios {
+		result, err := app.Dao().UseMfaRecoveryCode(s.mfa, s.code)
+		if err != nil {
+			t.Fatalf("(%d) Expected nil error, got %v", i, err)
+		}
+		if result != s.expected {
+			t.Fatalf("(%d) Expected %v, got %v", i, s.expected, result)
+		}
+	}
+
+	refreshed, err := app.Dao().FindMfaByAdmin(&models.Admin{BaseModel: models.BaseModel{Id: "sywbhecnh46rhm0"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedCodes := []any{models.HashMfaRecoveryCode("code3")}
+	if len(refreshed.RecoveryCodes) != 1 || refreshed.RecoveryCodes[0] != expectedCodes[0] || refreshed.Attempts != 0 {
+		t.Fatalf("Expected only code3 with reset attempts, got %v (%d attempts)", refreshed.RecoveryCodes, refreshed.Attempts)
+	}
+}
@@ -0,0 +1,103 @@
+package daos
+
+import (
+	"errors"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// OTPQuery returns a new OTP select query.
+func (dao *Dao) OTPQuery() *dbx.SelectQuery {
+	return dao.ModelQuery(&models.OTP{})
+}
+
+// FindOTPById returns a single OTP model by its id.
+func (dao *Dao) FindOTPById(id string) (*models.OTP, error) {
+	model := &models.OTP{}
+
+	err := dao.OTPQuery().
+		AndWhere(dbx.HashExp{"id": id}).
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// SaveOTP upserts the provided OTP model.
+func (dao *Dao) SaveOTP(model *models.OTP) error {
+	if model.CollectionId == "" || model.RecordId == "" || model.Password == "" {
+		return errors.New("Missing required OTP fields.")
+	}
+
+	return dao.Save(model)
+}
+
+// IncrementOTPAttempts atomically increments the password verification
+// attempts counter of the provided OTP model and returns its new value.
+//
+// The counter is not incremented and [sql.ErrNoRows] is returned
+// if the OTP was deleted or it has already reached maxAttempts.
+//
+// Note that the OTP model is updated with a single query
+// and no model hooks are triggered.
+func (dao *Dao) IncrementOTPAttempts(model *models.OTP, maxAttempts int) (int, error) {
+	var attempts int
+
+	err := dao.DB().NewQuery(`
+		UPDATE {{_otps}}
+		SET [[attempts]] = [[attempts]] + 1
+		WHERE [[id]] = {:id} AND [[attempts]] < {:maxAttempts}
+		RETURNING [[attempts]]
+	`).Bind(dbx.Params{
+		"id":          model.Id,
+		"maxAttempts": maxAttempts,
+	}).Row(&attempts)
+
+	if err != nil {
+		return 0, err
+	}
+
+	model.Attempts = attempts
+
+	return attempts, nil
+}
+
+// DeleteOTP deletes the provided OTP model.
+func (dao *Dao) DeleteOTP(model *models.OTP) error {
+	return dao.Delete(model)
+}
+
+// DeleteAllOTPsByRecord deletes all OTP models linked to the provided auth record.
+//
+// Note that the OTP models are deleted with a single query
+// and no model hooks are triggered.
+func (dao *Dao) DeleteAllOTPsByRecord(authRecord *models.Record) error {
+	_, err := dao.DB().Delete((&models.OTP{}).TableName(), dbx.HashExp{
+		"collectionId": authRecord.Collection().Id,
+		"recordId":     authRecord.Id,
+	}).Execute()
+
+	return err
+}
+
+// DeleteExpiredOTPs deletes all OTP models created before the specified max duration.
+//
+// Note that the OTP models are deleted with a single query
+// and no model hooks are triggered.
+func (dao *Dao) DeleteExpiredOTPs(maxElapsed time.Duration) error {
+	createdBefore := time.Now().Add(-maxElapsed).UTC().Format(types.DefaultDateLayout)
+
+	_, err := dao.DB().Delete(
+		(&models.OTP{}).TableName(),
+		dbx.NewExp("[[created]] < {:date}", dbx.Params{"date": createdBefore}),
+	).Execute()
+
+	return err
+}
@@ -0,0 +1,229 @@
+package daos_test
+
+import (
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestOTPQuery(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	expected := "SELECT {{_otps}}.* FROM `_otps`"
+
+	sql := app.Dao().OTPQuery().Build().SQL()
+	if sql != expected {
+		t.Errorf("Expected sql %s, got %s", expected, sql)
+	}
+}
+
+func TestFindOTPById(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	otp := &models.OTP{CollectionId: "test_collection", RecordId: "test_record"}
+	otp.SetPassword("123456")
+	if err := app.Dao().SaveOTP(otp); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		id          string
+		expectError bool
+	}{
+		{"", true},
+		{"missing", true},
+		{otp.Id, false},
+	}
+
+	for _, s := range scenarios {
+		model, err := app.Dao().FindOTPById(s.id)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%q) Expected hasErr %v, got %v (%v)", s.id, s.expectError, hasErr, err)
+			continue
+		}
+
+		if !hasErr && model.Id != s.id {
+			t.Errorf("(%q) Expected model with id %q, got %q", s.id, s.id, model.Id)
+		}
+	}
+}
+
+func TestSaveOTP(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		name        string
+		otp         *models.OTP
+		expectError bool
+	}{
+		{"empty model", &models.OTP{}, true},
+		{"missing collection", &models.OTP{RecordId: "test", Password: "test"}, true},
+		{"missing record", &models.OTP{CollectionId: "test", Password: "test"}, true},
+		{"missing password", &models.OTP{CollectionId: "test", RecordId: "test"}, true},
+		{"valid model", &models.OTP{CollectionId: "test", RecordId: "test", Password: "test"}, false},
+	}
+
+	for _, s := range scenarios {
+		err := app.Dao().SaveOTP(s.otp)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+		}
+	}
+}
+
+func TestIncrementOTPAttempts(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	otp := &models.OTP{CollectionId: "test", RecordId: "test", Password: "test"}
+	if err := app.Dao().SaveOTP(otp); err != nil {
+		t.Fatal(err)
+	}
+
+	maxAttempts := 3
+
+	// parallel increments with the same (stale) model
+	var wg sync.WaitGroup
+	var mux sync.Mutex
+	var succeeded, rejected int
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := app.Dao().IncrementOTPAttempts(&models.OTP{BaseModel: otp.BaseModel}, maxAttempts)
+
+			mux.Lock()
+			defer mux.Unlock()
+
+			switch {
+			case err == nil:
+				succeeded++
+			case errors.Is(err, sql.ErrNoRows):
+				rejected++
+			default:
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if succeeded != maxAttempts || rejected != 10-maxAttempts {
+		t.Fatalf("Expected %d succeeded and %d rejected increments, got %d and %d", maxAttempts, 10-maxAttempts, succeeded, rejected)
+	}
+
+	updated, err := app.Dao().FindOTPById(otp.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if updated.Attempts != maxAttempts {
+		t.Fatalf("Expected %d attempts, got %d", maxAttempts, updated.Attempts)
+	}
+}
+
+func TestDeleteOTP(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	otp := &models.OTP{CollectionId: "test", RecordId: "test", Password: "test"}
+	if err := app.Dao().SaveOTP(otp); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Dao().DeleteOTP(otp); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.Dao().FindOTPById(otp.Id); err == nil {
+		t.Fatal("Expected the otp to be deleted")
+	}
+}
+
+func TestDeleteAllOTPsByRecord(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, err := app.Dao().FindRecordById("users", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otps := []*models.OTP{
+		{CollectionId: record.Collection().Id, RecordId: record.Id, Password: "test"},
+		{CollectionId: record.Collection().Id, RecordId: record.Id, Password: "test"},
+		{CollectionId: record.Collection().Id, RecordId: "other", Password: "test"},
+		{CollectionId: "other", RecordId: record.Id, Password: "test"},
+	}
+	for _, otp := range otps {
+		if err := app.Dao().SaveOTP(otp); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := app.Dao().DeleteAllOTPsByRecord(record); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, otp := range otps {
+		_, err := app.Dao().FindOTPById(otp.Id)
+
+		exists := err == nil
+		if expected := i >= 2; exists != expected {
+			t.Errorf("(%d) Expected exists %v, got %v", i, expected, exists)
+		}
+	}
+}
+
+func TestDeleteExpiredOTPs(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	otps := []*models.OTP{
+		{CollectionId: "test", RecordId: "test1", Password: "test"},
+		{CollectionId: "test", RecordId: "test2", Password: "test"},
+	}
+	for _, otp := range otps {
+		if err := app.Dao().SaveOTP(otp); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// make the first otp older
+	_, err := app.Dao().DB().Update(
+		"_otps",
+		dbx.Params{"created": types.NowDateTime().Time().Add(-10 * time.Minute).Format(types.DefaultDateLayout)},
+		dbx.HashExp{"id": otps[0].Id},
+	).Execute()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Dao().DeleteExpiredOTPs(5 * time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.Dao().FindOTPById(otps[0].Id); err == nil {
+		t.Fatal("Expected the expired otp to be deleted")
+	}
+
+	if _, err := app.Dao().FindOTPById(otps[1].Id); err != nil {
+		t.Fatalf("Expected the non-expired otp to remain, got %v", err)
+	}
+}
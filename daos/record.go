@@ -431,8 +431,8 @@ func (dao *Dao) DeleteRecord(record *models.Record) error {
 	}
 
 	return dao.RunInTransaction(func(txDao *Dao) error {
-		// manually trigger delete on any linked external auth to ensure
-		// that the `OnModel*` hooks are triggered
+		// manually trigger delete on any linked external auth and mfa
+		// to ensure that the `OnModel*` hooks are triggered
 		if record.Collection().IsAuth() {
 			// note: the select is outside of the transaction to minimize
 			// SQLITE_BUSY errors when mixing read&write in a single transaction
@@ -445,6 +445,18 @@ func (dao *Dao) DeleteRecord(record *models.Record) error {
 					return err
 				}
 			}
+
+			// delete the linked mfa settings (if any)
+			if mfa, _ := dao.FindMfaByRecord(record); mfa != nil {
+				if err := txDao.DeleteMfa(mfa); err != nil {
+					return err
+				}
+			}
+
+			// delete the pending otp requests (if any)
+			if err := txDao.DeleteAllOTPsByRecord(record); err != nil {
+				return err
+			}
 		}
 
 		// delete the record before the relation references to ensure that there
@@ -4,23 +4,38 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/models"
 	"github.com/pocketbase/pocketbase/models/schema"
 	"github.com/pocketbase/pocketbase/tools/inflector"
 	"github.com/pocketbase/pocketbase/tools/list"
+	"github.com/pocketbase/pocketbase/tools/search"
 	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/pocketbase/pocketbase/tools/tokenizer"
 	"github.com/pocketbase/pocketbase/tools/types"
 )
 
 // MaxExpandDepth specifies the max allowed nested expand depth path.
 const MaxExpandDepth = 6
 
+// MaxIndirectExpandLimit specifies the default and max allowed number
+// of indirectly expanded relation records per single parent record.
+const MaxIndirectExpandLimit = 1000
+
 // ExpandFetchFunc defines the function that is used to fetch the expanded relation records.
 type ExpandFetchFunc func(relCollection *models.Collection, relIds []string) ([]*models.Record, error)
 
+// ExpandFilterFunc defines the function that is used to filter the
+// indirectly expanded relation records query (eg. to apply the
+// related collection access rule and the `:filter()` expand modifier).
+//
+// The filter argument is the raw `:filter()` modifier value (if any).
+type ExpandFilterFunc func(relCollection *models.Collection, filter string, q *dbx.SelectQuery) error
+
 // ExpandRecord expands the relations of a single Record model.
 //
 // Returns a map with the failed expand parameters and their errors.
@@ -32,12 +47,32 @@ func (dao *Dao) ExpandRecord(record *models.Record, expands []string, fetchFunc
 //
 // Returns a map with the failed expand parameters and their errors.
 func (dao *Dao) ExpandRecords(records []*models.Record, expands []string, fetchFunc ExpandFetchFunc) map[string]error {
+	return dao.ExpandRecordsWithFilter(records, expands, fetchFunc, nil)
+}
+
+// ExpandRecordsWithFilter expands the relations of the provided Record models list
+// and applies the optional filterFunc to the indirectly expanded relations query.
+//
+// Indirect expand path segments support the following modifiers:
+//
+//	comments(post):sort(-created,title) // sort by the comments fields
+//	comments(post):limit(5)             // max 5 comments per post
+//	comments(post):filter(status='ok')  // requires non-nil filterFunc
+//	comments(post):count                // stores the total in expand["comments(post):count"]
+//
+// Returns a map with the failed expand parameters and their errors.
+func (dao *Dao) ExpandRecordsWithFilter(
+	records []*models.Record,
+	expands []string,
+	fetchFunc ExpandFetchFunc,
+	filterFunc ExpandFilterFunc,
+) map[string]error {
 	normalized := normalizeExpands(expands)
 
 	failed := map[string]error{}
 
 	for _, expand := range normalized {
-		if err := dao.expandRecords(records, expand, fetchFunc, 1); err != nil {
+		if err := dao.expandRecords(records, expand, fetchFunc, filterFunc, 1); err != nil {
 			failed[expand] = err
 		}
 	}
@@ -47,12 +82,115 @@ func (dao *Dao) ExpandRecords(records []*models.Record, expands []string, fetchF
 
 var indirectExpandRegex = regexp.MustCompile(`^(\w+)\((\w+)\)$`)
 
+var expandModifierRegex = regexp.MustCompile(`(?s)^(\w+)(?:\((.*)\))?$`)
+
+// expand path segment modifiers
+const (
+	expandSortModifier   = "sort"
+	expandLimitModifier  = "limit"
+	expandFilterModifier = "filter"
+	expandCountModifier  = "count"
+)
+
+// expandSegment defines a single parsed expand path segment
+// (eg. "comments(post):sort(-created):limit(5)").
+type expandSegment struct {
+	name         string
+	sort         []search.SortField
+	filter       string
+	limit        int
+	count        bool
+	hasModifiers bool
+}
+
+// parseExpandSegment parses the provided raw expand path segment and its modifiers.
+func parseExpandSegment(raw string) (*expandSegment, error) {
+	tk := tokenizer.NewFromString(raw)
+	tk.Separators(':')
+
+	parts, err := tk.ScanAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(parts) == 0 || parts[0] == "" {
+		return nil, fmt.Errorf("Invalid expand path segment %q.", raw)
+	}
+
+	result := &expandSegment{
+		name:         parts[0],
+		limit:        MaxIndirectExpandLimit,
+		hasModifiers: len(parts) > 1,
+	}
+
+	for _, part := range parts[1:] {
+		matches := expandModifierRegex.FindStringSubmatch(part)
+		if len(matches) != 3 {
+			return nil, fmt.Errorf("Invalid expand modifier %q.", part)
+		}
+
+		args := strings.TrimSpace(matches[2])
+
+		switch matches[1] {
+		case expandSortModifier:
+			result.sort = search.ParseSortFromString(args)
+			if len(result.sort) == 0 {
+				return nil, fmt.Errorf("Missing %q modifier fields.", expandSortModifier)
+			}
+		case expandLimitModifier:
+			limit, err := strconv.Atoi(args)
+			if err != nil || limit < 0 || limit > MaxIndirectExpandLimit {
+				return nil, fmt.Errorf("The %q modifier must be a number between 0 and %d.", expandLimitModifier, MaxIndirectExpandLimit)
+			}
+			result.limit = limit
+		case expandFilterModifier:
+			if args == "" {
+				return nil, fmt.Errorf("Missing %q modifier expression.", expandFilterModifier)
+			}
+			result.filter = args
+		case expandCountModifier:
+			if matches[2] != "" {
+				return nil, fmt.Errorf("The %q modifier doesn't accept arguments.", expandCountModifier)
+			}
+			result.count = true
+		default:
+			return nil, fmt.Errorf("Unknown expand modifier %q.", matches[1])
+		}
+	}
+
+	return result, nil
+}
+
+// splitExpandPath splits the provided expand path into its first
+// segment and the remaining path (while respecting the modifiers boundaries).
+func splitExpandPath(path string) (string, string, error) {
+	tk := tokenizer.NewFromString(path)
+	tk.Separators('.')
+
+	parts, err := tk.ScanAll()
+	if err != nil {
+		return "", "", err
+	}
+
+	if len(parts) == 0 {
+		return "", "", nil
+	}
+
+	return parts[0], strings.Join(parts[1:], "."), nil
+}
+
 // notes:
 // - fetchFunc must be non-nil func
 // - all records are expected to be from the same collection
 // - if MaxExpandDepth is reached, the function returns nil ignoring the remaining expand path
-// - indirect expands are supported only with single relation fields
-func (dao *Dao) expandRecords(records []*models.Record, expandPath string, fetchFunc ExpandFetchFunc, recursionLevel int) error {
+// - the expand path modifiers are supported only with indirect expands
+func (dao *Dao) expandRecords(
+	records []*models.Record,
+	expandPath string,
+	fetchFunc ExpandFetchFunc,
+	filterFunc ExpandFilterFunc,
+	recursionLevel int,
+) error {
 	if fetchFunc == nil {
 		return errors.New("Relation records fetchFunc is not set.")
 	}
@@ -67,8 +205,17 @@ func (dao *Dao) expandRecords(records []*models.Record, expandPath string, fetch
 	var relFieldOptions *schema.RelationOptions
 	var relCollection *models.Collection
 
-	parts := strings.SplitN(expandPath, ".", 2)
-	matches := indirectExpandRegex.FindStringSubmatch(parts[0])
+	rawSegment, nestedPath, err := splitExpandPath(expandPath)
+	if err != nil {
+		return err
+	}
+
+	segment, err := parseExpandSegment(rawSegment)
+	if err != nil {
+		return err
+	}
+
+	matches := indirectExpandRegex.FindStringSubmatch(segment.name)
 
 	if len(matches) == 3 {
 		indirectRel, _ := dao.FindCollectionByNameOrId(matches[1])
@@ -84,33 +231,45 @@ func (dao *Dao) expandRecords(records []*models.Record, expandPath string, fetch
 		indirectRelField.InitOptions()
 		indirectRelFieldOptions, _ := indirectRelField.Options.(*schema.RelationOptions)
 		if indirectRelFieldOptions == nil || indirectRelFieldOptions.CollectionId != mainCollection.Id {
-			return fmt.Errorf("Invalid indirect relation field path %q.", parts[0])
-		}
-		if indirectRelFieldOptions.MaxSelect != nil && *indirectRelFieldOptions.MaxSelect != 1 {
-			// for now don't allow multi-relation indirect fields expand
-			// due to eventual poor query performance with large data sets.
-			return fmt.Errorf("Multi-relation fields cannot be indirectly expanded in %q.", parts[0])
+			return fmt.Errorf("Invalid indirect relation field path %q.", segment.name)
 		}
 
+		isMultiple := indirectRelFieldOptions.MaxSelect == nil || *indirectRelFieldOptions.MaxSelect != 1
+
 		recordIds := make([]any, len(records))
 		for i, record := range records {
 			recordIds[i] = record.Id
 		}
 
-		// @todo after the index optimizations consider allowing
-		// indirect expand for multi-relation fields
-		indirectRecords, err := dao.FindRecordsByExpr(
-			indirectRel.Id,
-			dbx.In(inflector.Columnify(matches[2]), recordIds...),
+		mappedIndirectRecordIds, err := dao.findIndirectExpandIds(
+			recordIds,
+			indirectRel,
+			indirectRelField,
+			isMultiple,
+			segment,
+			filterFunc,
 		)
 		if err != nil {
 			return err
 		}
-		mappedIndirectRecordIds := make(map[string][]string, len(indirectRecords))
-		for _, indirectRecord := range indirectRecords {
-			recId := indirectRecord.GetString(matches[2])
-			if recId != "" {
-				mappedIndirectRecordIds[recId] = append(mappedIndirectRecordIds[recId], indirectRecord.Id)
+
+		if segment.count {
+			totals, err := dao.countIndirectExpandRecords(
+				recordIds,
+				indirectRel,
+				indirectRelField,
+				isMultiple,
+				segment,
+				filterFunc,
+			)
+			if err != nil {
+				return err
+			}
+
+			for _, record := range records {
+				expandData := record.Expand()
+				expandData[segment.name+":"+expandCountModifier] = totals[record.Id]
+				record.SetExpand(expandData)
 			}
 		}
 
@@ -118,7 +277,7 @@ func (dao *Dao) expandRecords(records []*models.Record, expandPath string, fetch
 		for _, record := range records {
 			relIds, ok := mappedIndirectRecordIds[record.Id]
 			if ok && len(relIds) > 0 {
-				record.Set(parts[0], relIds)
+				record.Set(segment.name, relIds)
 			}
 		}
 
@@ -126,27 +285,31 @@ func (dao *Dao) expandRecords(records []*models.Record, expandPath string, fetch
 			MaxSelect:    nil,
 			CollectionId: indirectRel.Id,
 		}
-		if indirectRelField.Unique {
+		if !isMultiple && indirectRelField.Unique {
 			relFieldOptions.MaxSelect = types.Pointer(1)
 		}
 		// indirect relation
 		relField = &schema.SchemaField{
 			Id:      "indirect_" + security.PseudorandomString(5),
 			Type:    schema.FieldTypeRelation,
-			Name:    parts[0],
+			Name:    segment.name,
 			Options: relFieldOptions,
 		}
 		relCollection = indirectRel
 	} else {
+		if segment.hasModifiers {
+			return fmt.Errorf("Expand modifiers are supported only with indirect relations (%q).", rawSegment)
+		}
+
 		// direct relation
-		relField = mainCollection.Schema.GetFieldByName(parts[0])
+		relField = mainCollection.Schema.GetFieldByName(segment.name)
 		if relField == nil || relField.Type != schema.FieldTypeRelation {
-			return fmt.Errorf("Couldn't find relation field %q in collection %q.", parts[0], mainCollection.Name)
+			return fmt.Errorf("Couldn't find relation field %q in collection %q.", segment.name, mainCollection.Name)
 		}
 		relField.InitOptions()
 		relFieldOptions, _ = relField.Options.(*schema.RelationOptions)
 		if relFieldOptions == nil {
-			return fmt.Errorf("Couldn't initialize the options of relation field %q.", parts[0])
+			return fmt.Errorf("Couldn't initialize the options of relation field %q.", segment.name)
 		}
 
 		relCollection, _ = dao.FindCollectionByNameOrId(relFieldOptions.CollectionId)
@@ -170,8 +333,8 @@ func (dao *Dao) expandRecords(records []*models.Record, expandPath string, fetch
 	}
 
 	// expand nested fields
-	if len(parts) > 1 {
-		err := dao.expandRecords(rels, parts[1], fetchFunc, recursionLevel+1)
+	if nestedPath != "" {
+		err := dao.expandRecords(rels, nestedPath, fetchFunc, filterFunc, recursionLevel+1)
 		if err != nil {
 			return err
 		}
@@ -235,14 +398,194 @@ func (dao *Dao) expandRecords(records []*models.Record, expandPath string, fetch
 	return nil
 }
 
+// indirectExpandQuery returns a new query that selects the relCollection
+// records pointing to the provided parent ids through relField,
+// together with the SQL expression of the matching parent id.
+//
+// Multiple relation fields are expanded with a json_each join, so
+// that each child record is matched once for every referenced parent.
+func (dao *Dao) indirectExpandQuery(
+	parentIds []any,
+	relCollection *models.Collection,
+	relField *schema.SchemaField,
+	isMultiple bool,
+	segment *expandSegment,
+	filterFunc ExpandFilterFunc,
+) (*dbx.SelectQuery, string, error) {
+	tableName := inflector.Columnify(relCollection.Name)
+	fieldColumn := tableName + "." + inflector.Columnify(relField.Name)
+
+	query := dao.RecordQuery(relCollection)
+
+	parentColumn := fieldColumn
+	if isMultiple {
+		query.InnerJoin(
+			fmt.Sprintf(
+				// note: the case is used to normalize value access for single and multiple relations.
+				"json_each(CASE WHEN json_valid([[%s]]) THEN [[%s]] ELSE json_array([[%s]]) END) {{__expand_je}}",
+				fieldColumn, fieldColumn, fieldColumn,
+			),
+			nil,
+		)
+		parentColumn = "__expand_je.value"
+	}
+
+	query.AndWhere(dbx.In(parentColumn, parentIds...))
+
+	if segment.filter != "" && filterFunc == nil {
+		return nil, "", fmt.Errorf("The %q expand modifier is not supported in the current context.", expandFilterModifier)
+	}
+
+	if filterFunc != nil {
+		// apply the filter in a subquery to prevent the eventual
+		// filter joins from duplicating the matching children
+		filterQuery := dao.RecordQuery(relCollection).Select(tableName + ".id")
+		if err := filterFunc(relCollection, segment.filter, filterQuery); err != nil {
+			return nil, "", err
+		}
+
+		built := filterQuery.Build()
+		query.AndWhere(dbx.NewExp("[["+tableName+".id]] IN ("+built.SQL()+")", built.Params()))
+	}
+
+	return query, "[[" + parentColumn + "]]", nil
+}
+
+// findIndirectExpandIds returns the ids of the indirectly related
+// records grouped by their parent id (sorted and limited per parent).
+func (dao *Dao) findIndirectExpandIds(
+	parentIds []any,
+	relCollection *models.Collection,
+	relField *schema.SchemaField,
+	isMultiple bool,
+	segment *expandSegment,
+	filterFunc ExpandFilterFunc,
+) (map[string][]string, error) {
+	tableName := inflector.Columnify(relCollection.Name)
+
+	sortFields := sortableIndirectExpandFields(relCollection)
+
+	query, parentExpr, err := dao.indirectExpandQuery(parentIds, relCollection, relField, isMultiple, segment, filterFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	selects := make([]string, 0, len(segment.sort)+2)
+	selects = append(selects, tableName+".id", parentExpr+" AS __parent")
+
+	orderBy := make([]string, 0, len(segment.sort)+1)
+	for i, sortField := range segment.sort {
+		if !list.ExistInSlice(sortField.Name, sortFields) {
+			return nil, fmt.Errorf("Invalid %q modifier field %q.", expandSortModifier, sortField.Name)
+		}
+		alias := fmt.Sprintf("__sort%d", i)
+		selects = append(selects, fmt.Sprintf("[[%s.%s]] AS %s", tableName, inflector.Columnify(sortField.Name), alias))
+		orderBy = append(orderBy, fmt.Sprintf("[[%s]] %s", alias, sortField.Direction))
+	}
+	orderBy = append(orderBy, "[[id]] ASC")
+
+	// deduplicate the child-parent pairs before numbering the rows
+	// (eg. a child could reference the same parent multiple times)
+	query.Select(selects...).Distinct(true)
+
+	built := query.Build()
+
+	params := dbx.Params{"__limit": segment.limit}
+	for k, v := range built.Params() {
+		params[k] = v
+	}
+
+	rows := []struct {
+		Id     string `db:"id"`
+		Parent string `db:"__parent"`
+	}{}
+
+	err = dao.DB().NewQuery(
+		"SELECT [[id]], [[__parent]] FROM (" +
+			"SELECT [[id]], [[__parent]], " +
+			"ROW_NUMBER() OVER (PARTITION BY [[__parent]] ORDER BY " + strings.Join(orderBy, ", ") + ") AS [[__rowNum]] " +
+			"FROM (" + built.SQL() + ")" +
+			") " +
+			"WHERE [[__rowNum]] <= {:__limit} " +
+			"ORDER BY [[__parent]], [[__rowNum]]",
+	).Bind(params).All(&rows)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]string, len(parentIds))
+	for _, row := range rows {
+		result[row.Parent] = append(result[row.Parent], row.Id)
+	}
+
+	return result, nil
+}
+
+// countIndirectExpandRecords returns the total number of the
+// indirectly related records grouped by their parent id.
+func (dao *Dao) countIndirectExpandRecords(
+	parentIds []any,
+	relCollection *models.Collection,
+	relField *schema.SchemaField,
+	isMultiple bool,
+	segment *expandSegment,
+	filterFunc ExpandFilterFunc,
+) (map[string]int, error) {
+	tableName := inflector.Columnify(relCollection.Name)
+
+	query, parentExpr, err := dao.indirectExpandQuery(parentIds, relCollection, relField, isMultiple, segment, filterFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []struct {
+		Parent string `db:"__parent"`
+		Total  int    `db:"__total"`
+	}{}
+
+	err = query.
+		Select(
+			parentExpr+" AS __parent",
+			"COUNT(DISTINCT [["+tableName+".id]]) AS __total",
+		).
+		GroupBy(parentExpr).
+		All(&rows)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]int, len(parentIds))
+	for _, row := range rows {
+		result[row.Parent] = row.Total
+	}
+
+	return result, nil
+}
+
+// sortableIndirectExpandFields returns the names of the collection
+// fields that could be used with the "sort" expand modifier.
+func sortableIndirectExpandFields(collection *models.Collection) []string {
+	result := schema.BaseModelFieldNames()
+
+	if collection.IsAuth() {
+		result = append(result, schema.FieldNameUsername, schema.FieldNameVerified)
+	}
+
+	for _, field := range collection.Schema.Fields() {
+		result = append(result, field.Name)
+	}
+
+	return result
+}
+
 // normalizeExpands normalizes expand strings and merges self containing paths
 // (eg. ["a.b.c", "a.b", "   test  ", "  ", "test"] -> ["a.b.c", "test"]).
 func normalizeExpands(paths []string) []string {
 	// normalize paths
 	normalized := make([]string, 0, len(paths))
 	for _, p := range paths {
-		p = strings.ReplaceAll(p, " ", "") // replace spaces
-		p = strings.Trim(p, ".")           // trim incomplete paths
+		p = stripExpandSpaces(p) // remove spaces outside of the modifiers
+		p = strings.Trim(p, ".") // trim incomplete paths
 		if p != "" {
 			normalized = append(normalized, p)
 		}
@@ -269,3 +612,35 @@ func normalizeExpands(paths []string) []string {
 
 	return list.ToUniqueStringSlice(result)
 }
+
+// stripExpandSpaces removes the whitespace characters of the provided
+// expand path that are not enclosed in parenthesis or quotes
+// (eg. " a . b(c) : filter(d = 'e f') " -> "a.b(c):filter(d = 'e f')").
+func stripExpandSpaces(path string) string {
+	var result strings.Builder
+	var quote rune
+	var depth int
+
+	for _, ch := range path {
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"' || ch == '`':
+			quote = ch
+		case ch == '(':
+			depth++
+		case ch == ')':
+			if depth > 0 {
+				depth--
+			}
+		case depth == 0 && unicode.IsSpace(ch):
+			continue
+		}
+
+		result.WriteRune(ch)
+	}
+
+	return result.String()
+}
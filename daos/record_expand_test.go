@@ -6,6 +6,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/daos"
 	"github.com/pocketbase/pocketbase/models"
 	"github.com/pocketbase/pocketbase/models/schema"
@@ -362,3 +363,170 @@ func TestExpandRecord(t *testing.T) {
 		}
 	}
 }
+
+func TestExpandRecordsWithFilter(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	fetchFunc := func(c *models.Collection, ids []string) ([]*models.Record, error) {
+		return app.Dao().FindRecordsByIds(c.Id, ids, nil)
+	}
+
+	rawFilterFunc := func(c *models.Collection, filter string, q *dbx.SelectQuery) error {
+		if filter != "" {
+			q.AndWhere(dbx.NewExp(filter))
+		}
+		return nil
+	}
+
+	scenarios := []struct {
+		testName             string
+		expands              []string
+		filterFunc           daos.ExpandFilterFunc
+		expectExpandFailures int
+		expectResult         string
+	}{
+		{
+			"multi-relation indirect expand",
+			[]string{"demo1(rel_many)"},
+			nil,
+			0,
+			`{"4q1xlclmfloku33":["al1h9ijdeojtsjy"],"bgs820n361vj1qd":["al1h9ijdeojtsjy"],"oap640cot4yru2s":["84nmscqy84lsi1t","al1h9ijdeojtsjy"]}`,
+		},
+		{
+			"sort, limit and count modifiers",
+			[]string{"demo1(rel_many):sort(-text):limit(1):count"},
+			nil,
+			0,
+			`{"4q1xlclmfloku33":["al1h9ijdeojtsjy"],"4q1xlclmfloku33:count":1,"bgs820n361vj1qd":["al1h9ijdeojtsjy"],"bgs820n361vj1qd:count":1,"oap640cot4yru2s":["al1h9ijdeojtsjy"],"oap640cot4yru2s:count":2}`,
+		},
+		{
+			"count with zero limit",
+			[]string{"demo1(rel_many):limit(0):count"},
+			nil,
+			0,
+			`{"4q1xlclmfloku33:count":1,"bgs820n361vj1qd:count":1,"oap640cot4yru2s:count":2}`,
+		},
+		{
+			"filter modifier with spaces and filterFunc",
+			[]string{" demo1(rel_many) : filter(text = 'test') : count "},
+			rawFilterFunc,
+			0,
+			`{"4q1xlclmfloku33:count":0,"bgs820n361vj1qd:count":0,"oap640cot4yru2s":["84nmscqy84lsi1t"],"oap640cot4yru2s:count":1}`,
+		},
+		{
+			"filterFunc error",
+			[]string{"demo1(rel_many)"},
+			func(c *models.Collection, filter string, q *dbx.SelectQuery) error {
+				return errors.New("test error")
+			},
+			1,
+			`{}`,
+		},
+		{
+			"filter modifier without filterFunc",
+			[]string{"demo1(rel_many):filter(text='test')"},
+			nil,
+			1,
+			`{}`,
+		},
+		{
+			"invalid modifiers",
+			[]string{
+				"demo1(rel_many):limit(-1)",
+				"demo1(rel_many):limit(1001)",
+				"demo1(rel_many):sort(missing)",
+				"demo1(rel_many):sort()",
+				"demo1(rel_many):count(1)",
+				"demo1(rel_many):unknown",
+				"rel:limit(1)",
+			},
+			nil,
+			7,
+			`{}`,
+		},
+	}
+
+	for _, s := range scenarios {
+		records, err := app.Dao().FindRecordsByIds("users", []string{"4q1xlclmfloku33", "bgs820n361vj1qd", "oap640cot4yru2s"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		failed := app.Dao().ExpandRecordsWithFilter(records, s.expands, fetchFunc, s.filterFunc)
+
+		if len(failed) != s.expectExpandFailures {
+			t.Errorf("[%s] Expected %d failures, got %d: \n%v", s.testName, s.expectExpandFailures, len(failed), failed)
+		}
+
+		result := map[string]any{}
+		for _, record := range records {
+			for key, value := range record.Expand() {
+				switch v := value.(type) {
+				case []*models.Record:
+					ids := make([]string, len(v))
+					for i, r := range v {
+						ids[i] = r.Id
+					}
+					result[record.Id] = ids
+				case *models.Record:
+					result[record.Id] = []string{v.Id}
+				default:
+					result[record.Id+strings.TrimPrefix(key, "demo1(rel_many)")] = v
+				}
+			}
+		}
+
+		encoded, _ := json.Marshal(result)
+		if string(encoded) != s.expectResult {
+			t.Errorf("[%s] Expected \n%s, \ngot \n%s", s.testName, s.expectResult, encoded)
+		}
+	}
+}
+
+func TestExpandRecordsWithFilterDuplicatedRelations(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	// reference the same parent multiple times
+	_, err := app.Dao().DB().NewQuery(
+		`UPDATE demo1 SET rel_many = '["oap640cot4yru2s","oap640cot4yru2s","bgs820n361vj1qd"]' WHERE id = 'al1h9ijdeojtsjy'`,
+	).Execute()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fetchFunc := func(c *models.Collection, ids []string) ([]*models.Record, error) {
+		return app.Dao().FindRecordsByIds(c.Id, ids, nil)
+	}
+
+	record, err := app.Dao().FindRecordById("users", "oap640cot4yru2s")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	failed := app.Dao().ExpandRecordsWithFilter(
+		[]*models.Record{record},
+		[]string{"demo1(rel_many):sort(-text):limit(2):count"},
+		fetchFunc,
+		nil,
+	)
+	if len(failed) != 0 {
+		t.Fatalf("Expected no failures, got %v", failed)
+	}
+
+	rels, _ := record.Expand()["demo1(rel_many)"].([]*models.Record)
+	ids := make([]string, len(rels))
+	for i, r := range rels {
+		ids[i] = r.Id
+	}
+
+	expectedIds := []string{"al1h9ijdeojtsjy", "84nmscqy84lsi1t"}
+	if strings.Join(ids, ",") != strings.Join(expectedIds, ",") {
+		t.Fatalf("Expected ids %v, got %v", expectedIds, ids)
+	}
+
+	if total := record.Expand()["demo1(rel_many):count"]; total != 2 {
+		t.Fatalf("Expected count 2, got %v", total)
+	}
+}
@@ -74,6 +74,13 @@ func (dao *Dao) SyncRecordTableSchema(newCollection *models.Collection, oldColle
 			}
 		}
 
+		// create the full-text search index (if enabled)
+		if len(newCollection.SearchFields()) > 0 {
+			if err := dao.createRecordTableSearchIndex(newCollection); err != nil {
+				return err
+			}
+		}
+
 		return nil
 	}
 
@@ -85,6 +92,15 @@ func (dao *Dao) SyncRecordTableSchema(newCollection *models.Collection, oldColle
 		newSchema := newCollection.Schema
 		deletedFieldNames := []string{}
 		renamedFieldNames := map[string]string{}
+		hasSearchIndexChanges := isSearchIndexChanged(newCollection, oldCollection)
+
+		// drop the old full-text search index before the columns changes
+		// because its triggers could reference some of the changed columns
+		if hasSearchIndexChanges {
+			if err := txDao.deleteRecordTableSearchIndex(oldCollection); err != nil {
+				return err
+			}
+		}
 
 		// check for renamed table
 		if !strings.EqualFold(oldTableName, newTableName) {
@@ -149,6 +165,13 @@ func (dao *Dao) SyncRecordTableSchema(newCollection *models.Collection, oldColle
 			}
 		}
 
+		// recreate the full-text search index with the new fields
+		if hasSearchIndexChanges && len(newCollection.SearchFields()) > 0 {
+			if err := txDao.createRecordTableSearchIndex(newCollection); err != nil {
+				return err
+			}
+		}
+
 		return txDao.syncCollectionReferences(newCollection, renamedFieldNames, deletedFieldNames)
 	})
 }
@@ -196,3 +219,99 @@ func (dao *Dao) syncCollectionReferences(collection *models.Collection, renamedF
 
 	return nil
 }
+
+// isSearchIndexChanged checks whether the full-text search index
+// fields of the two provided collection states are different.
+func isSearchIndexChanged(newCollection *models.Collection, oldCollection *models.Collection) bool {
+	newFields := newCollection.SearchFields()
+	oldFields := oldCollection.SearchFields()
+
+	if len(newFields) != len(oldFields) {
+		return true
+	}
+
+	for i, name := range newFields {
+		if name != oldFields[i] {
+			return true
+		}
+
+		newField := newCollection.Schema.GetFieldByName(name)
+		oldField := oldCollection.Schema.GetFieldByName(name)
+		if newField == nil || oldField == nil || newField.Id != oldField.Id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// createRecordTableSearchIndex creates the collection FTS5 search table,
+// populates it with the existing records data and registers the triggers
+// that will keep it in sync with the collection records table.
+//
+// The search table is a regular (aka. not external content) FTS5 table
+// because the records rowid is not guaranteed to be preserved on VACUUM.
+func (dao *Dao) createRecordTableSearchIndex(collection *models.Collection) error {
+	searchFields := collection.SearchFields()
+	if len(searchFields) == 0 {
+		return fmt.Errorf("Collection %q doesn't have any search fields.", collection.Name)
+	}
+
+	tableName := collection.Name
+	ftsTableName := collection.SearchTableName()
+
+	columns := make([]string, 0, len(searchFields)+1)
+	newValues := make([]string, 0, len(searchFields)+1)
+	for _, name := range append([]string{schema.FieldNameId}, searchFields...) {
+		columns = append(columns, "[["+name+"]]")
+		newValues = append(newValues, "new.[["+name+"]]")
+	}
+	joinedColumns := strings.Join(columns, ", ")
+	joinedNewValues := strings.Join(newValues, ", ")
+
+	// the old records entries are deleted with a "MATCH" lookup
+	// against the indexed id column to avoid full search table scans
+	deleteOld := fmt.Sprintf(
+		`DELETE FROM {{%s}} WHERE {{%s}} MATCH ('id:"' || REPLACE(old.[[id]], '"', '""') || '"') AND [[id]] = old.[[id]];`,
+		ftsTableName, ftsTableName,
+	)
+	insertNew := fmt.Sprintf(
+		"INSERT INTO {{%s}} (%s) VALUES (%s);",
+		ftsTableName, joinedColumns, joinedNewValues,
+	)
+
+	_, err := dao.DB().NewQuery(fmt.Sprintf(
+		`
+		CREATE VIRTUAL TABLE {{%s}} USING fts5(%s);
+		INSERT INTO {{%s}} (%s) SELECT %s FROM {{%s}};
+		CREATE TRIGGER {{%s_insert}} AFTER INSERT ON {{%s}} BEGIN %s END;
+		CREATE TRIGGER {{%s_update}} AFTER UPDATE OF %s ON {{%s}} BEGIN %s %s END;
+		CREATE TRIGGER {{%s_delete}} AFTER DELETE ON {{%s}} BEGIN %s END;
+		`,
+		ftsTableName, joinedColumns,
+		ftsTableName, joinedColumns, joinedColumns, tableName,
+		ftsTableName, tableName, insertNew,
+		ftsTableName, joinedColumns, tableName, deleteOld, insertNew,
+		ftsTableName, tableName, deleteOld,
+	)).Execute()
+
+	return err
+}
+
+// deleteRecordTableSearchIndex drops the collection FTS5 search table
+// and its related records table sync triggers (if exist).
+func (dao *Dao) deleteRecordTableSearchIndex(collection *models.Collection) error {
+	ftsTableName := collection.SearchTableName()
+
+	_, err := dao.DB().NewQuery(fmt.Sprintf(
+		`
+		DROP TRIGGER IF EXISTS {{%s_insert}};
+		DROP TRIGGER IF EXISTS {{%s_update}};
+		DROP TRIGGER IF EXISTS {{%s_delete}};
+		DROP TABLE IF EXISTS {{%s}};
+		`,
+		ftsTableName, ftsTableName, ftsTableName, ftsTableName,
+	)).Execute()
+
+	return err
+}
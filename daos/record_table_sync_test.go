@@ -3,6 +3,7 @@ package daos_test
 import (
 	"testing"
 
+	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/models"
 	"github.com/pocketbase/pocketbase/models/schema"
 	"github.com/pocketbase/pocketbase/tests"
@@ -117,3 +118,118 @@ func TestSyncRecordTableSchema(t *testing.T) {
 		}
 	}
 }
+
+func TestSyncRecordTableSchemaSearchIndex(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	tests.SkipIfNoFTS5(t, app)
+
+	countMatches := func(collection *models.Collection, query string) int {
+		var total int
+
+		err := app.Dao().DB().Select("count(*)").
+			From(collection.SearchTableName()).
+			AndWhere(dbx.NewExp("[["+collection.SearchTableName()+"]] MATCH {:query}", dbx.Params{"query": query})).
+			Row(&total)
+		if err != nil {
+			t.Fatalf("Failed to count %q matches: %v", query, err)
+		}
+
+		return total
+	}
+
+	collection := &models.Collection{
+		Name: "fts_test",
+		Type: models.CollectionTypeBase,
+		Schema: schema.NewSchema(
+			&schema.SchemaField{Name: "title", Type: schema.FieldTypeText},
+			&schema.SchemaField{Name: "description", Type: schema.FieldTypeEditor},
+		),
+	}
+	collection.SetOptions(models.CollectionBaseOptions{SearchFields: []string{"title"}})
+
+	if err := app.Dao().SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	if !app.Dao().HasTable(collection.SearchTableName()) {
+		t.Fatalf("Expected search table %q to be created", collection.SearchTableName())
+	}
+
+	// insert trigger
+	record := models.NewRecord(collection)
+	record.Set("title", "lorem ipsum")
+	record.Set("description", "dolor")
+	if err := app.Dao().SaveRecord(record); err != nil {
+		t.Fatal(err)
+	}
+	if total := countMatches(collection, "lorem"); total != 1 {
+		t.Fatalf("Expected 1 lorem match after insert, got %d", total)
+	}
+	if total := countMatches(collection, "dolor"); total != 0 {
+		t.Fatalf("Expected the non search field to not be indexed, got %d matches", total)
+	}
+
+	// update trigger
+	record.Set("title", "sit amet")
+	if err := app.Dao().SaveRecord(record); err != nil {
+		t.Fatal(err)
+	}
+	if total := countMatches(collection, "lorem"); total != 0 {
+		t.Fatalf("Expected 0 lorem matches after update, got %d", total)
+	}
+	if total := countMatches(collection, "amet"); total != 1 {
+		t.Fatalf("Expected 1 amet match after update, got %d", total)
+	}
+
+	// search fields change (with rename)
+	collection.Schema.GetFieldByName("title").Name = "title_renamed"
+	collection.SetOptions(models.CollectionBaseOptions{SearchFields: []string{"title_renamed", "description"}})
+	if err := app.Dao().SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+	if total := countMatches(collection, "title_renamed:amet"); total != 1 {
+		t.Fatalf("Expected 1 title_renamed:amet match after the rebuild, got %d", total)
+	}
+	if total := countMatches(collection, "description:dolor"); total != 1 {
+		t.Fatalf("Expected 1 description:dolor match after the rebuild, got %d", total)
+	}
+
+	// delete trigger
+	if err := app.Dao().DeleteRecord(record); err != nil {
+		t.Fatal(err)
+	}
+	if total := countMatches(collection, "amet"); total != 0 {
+		t.Fatalf("Expected 0 amet matches after delete, got %d", total)
+	}
+
+	// disable the search index
+	collection.SetOptions(models.CollectionBaseOptions{})
+	if err := app.Dao().SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+	if app.Dao().HasTable(collection.SearchTableName()) {
+		t.Fatalf("Expected search table %q to be deleted", collection.SearchTableName())
+	}
+
+	// the records table should be still editable after the triggers removal
+	if err := app.Dao().SaveRecord(models.NewRecord(collection)); err != nil {
+		t.Fatal(err)
+	}
+
+	// collection delete
+	collection.SetOptions(models.CollectionBaseOptions{SearchFields: []string{"description"}})
+	if err := app.Dao().SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+	if !app.Dao().HasTable(collection.SearchTableName()) {
+		t.Fatalf("Expected search table %q to be recreated", collection.SearchTableName())
+	}
+	if err := app.Dao().DeleteCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+	if app.Dao().HasTable(collection.SearchTableName()) {
+		t.Fatalf("Expected search table %q to be deleted together with the collection", collection.SearchTableName())
+	}
+}
@@ -907,3 +907,50 @@ func createMockBatchProcessingData(dao *daos.Dao) error {
 
 	return nil
 }
+
+func TestDeleteRecordWithMfa(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, err := app.Dao().FindRecordById("users", "oap640cot4yru2s")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mfa := &models.Mfa{CollectionId: record.Collection().Id, RecordId: record.Id, Secret: "test"}
+	if err := app.Dao().SaveMfa(mfa); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Dao().DeleteRecord(record); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.Dao().FindMfaByRecord(record); err == nil {
+		t.Fatal("Expected the record mfa to be deleted")
+	}
+}
+
+func TestDeleteRecordWithOTPs(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, err := app.Dao().FindRecordById("users", "oap640cot4yru2s")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otp := &models.OTP{CollectionId: record.Collection().Id, RecordId: record.Id}
+	otp.SetPassword("123456")
+	if err := app.Dao().SaveOTP(otp); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Dao().DeleteRecord(record); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.Dao().FindOTPById(otp.Id); err == nil {
+		t.Fatal("Expected the record otp to be deleted")
+	}
+}
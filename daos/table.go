@@ -74,3 +74,15 @@ func (dao *Dao) Vacuum() error {
 
 	return err
 }
+
+// VacuumInto executes VACUUM INTO on the current dao.DB() instance
+// writing a consistent and compacted copy of the database at destPath.
+//
+// The destination file must not already exist.
+func (dao *Dao) VacuumInto(destPath string) error {
+	_, err := dao.DB().NewQuery("VACUUM INTO {:dest}").
+		Bind(dbx.Params{"dest": destPath}).
+		Execute()
+
+	return err
+}
@@ -0,0 +1,84 @@
+package daos
+
+import (
+	"errors"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// UploadQuery returns a new Upload select query.
+func (dao *Dao) UploadQuery() *dbx.SelectQuery {
+	return dao.ModelQuery(&models.Upload{})
+}
+
+// FindUploadById returns a single Upload model by its id.
+func (dao *Dao) FindUploadById(id string) (*models.Upload, error) {
+	model := &models.Upload{}
+
+	err := dao.UploadQuery().
+		AndWhere(dbx.HashExp{"id": id}).
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// FindStaleUploads returns all Upload models that were
+// not updated for more than the specified max duration.
+func (dao *Dao) FindStaleUploads(maxIdle time.Duration) ([]*models.Upload, error) {
+	updatedBefore := time.Now().Add(-maxIdle).UTC().Format(types.DefaultDateLayout)
+
+	models := []*models.Upload{}
+
+	err := dao.UploadQuery().
+		AndWhere(dbx.NewExp("[[updated]] < {:date}", dbx.Params{"date": updatedBefore})).
+		All(&models)
+
+	return models, err
+}
+
+// FindUploadsTotalsByOwner returns the number and the total size
+// of all Upload models of the specified owner.
+//
+// Empty ownerCollectionId and ownerId match the guest uploads.
+func (dao *Dao) FindUploadsTotalsByOwner(ownerCollectionId, ownerId string) (int, int64, error) {
+	var result struct {
+		Count int   `db:"count"`
+		Total int64 `db:"total"`
+	}
+
+	err := dao.UploadQuery().
+		Select("count(*) as count", "coalesce(sum([[size]]), 0) as total").
+		AndWhere(dbx.HashExp{
+			"ownerCollectionId": ownerCollectionId,
+			"ownerId":           ownerId,
+		}).
+		One(&result)
+
+	return result.Count, result.Total, err
+}
+
+// SaveUpload upserts the provided Upload model.
+func (dao *Dao) SaveUpload(model *models.Upload) error {
+	if model.CollectionId == "" || model.RecordId == "" || model.Field == "" || model.Filename == "" {
+		return errors.New("Missing required Upload fields.")
+	}
+
+	if model.Offset < 0 || model.Offset > model.Size {
+		return errors.New("The Upload offset must be between 0 and the upload size.")
+	}
+
+	return dao.Save(model)
+}
+
+// DeleteUpload deletes the provided Upload model.
+func (dao *Dao) DeleteUpload(model *models.Upload) error {
+	return dao.Delete(model)
+}
@@ -0,0 +1,200 @@
+package daos_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestUploadQuery(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	expected := "SELECT {{_uploads}}.* FROM `_uploads`"
+
+	sql := app.Dao().UploadQuery().Build().SQL()
+	if sql != expected {
+		t.Errorf("Expected sql %s, got %s", expected, sql)
+	}
+}
+
+func TestFindUploadById(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	upload := &models.Upload{CollectionId: "test", RecordId: "test", Field: "test", Filename: "test.txt", Size: 10}
+	if err := app.Dao().SaveUpload(upload); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		id          string
+		expectError bool
+	}{
+		{"", true},
+		{"missing", true},
+		{upload.Id, false},
+	}
+
+	for _, s := range scenarios {
+		model, err := app.Dao().FindUploadById(s.id)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%q) Expected hasErr %v, got %v (%v)", s.id, s.expectError, hasErr, err)
+			continue
+		}
+
+		if !hasErr && model.Id != s.id {
+			t.Errorf("(%q) Expected model with id %q, got %q", s.id, s.id, model.Id)
+		}
+	}
+}
+
+func TestSaveUpload(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		name        string
+		upload      *models.Upload
+		expectError bool
+	}{
+		{
+			"missing required fields",
+			&models.Upload{},
+			true,
+		},
+		{
+			"negative offset",
+			&models.Upload{CollectionId: "test", RecordId: "test", Field: "test", Filename: "test.txt", Size: 10, Offset: -1},
+			true,
+		},
+		{
+			"offset bigger than the size",
+			&models.Upload{CollectionId: "test", RecordId: "test", Field: "test", Filename: "test.txt", Size: 10, Offset: 11},
+			true,
+		},
+		{
+			"valid upload",
+			&models.Upload{CollectionId: "test", RecordId: "test", Field: "test", Filename: "test.txt", Size: 10, Offset: 10},
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		err := app.Dao().SaveUpload(s.upload)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		if _, err := app.Dao().FindUploadById(s.upload.Id); err != nil {
+			t.Errorf("[%s] Expected the upload to be persisted, got %v", s.name, err)
+		}
+	}
+}
+
+func TestDeleteUpload(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	upload := &models.Upload{CollectionId: "test", RecordId: "test", Field: "test", Filename: "test.txt", Size: 10}
+	if err := app.Dao().SaveUpload(upload); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Dao().DeleteUpload(upload); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.Dao().FindUploadById(upload.Id); err == nil {
+		t.Fatal("Expected the upload to be deleted")
+	}
+}
+
+func TestFindStaleUploads(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	uploads := []*models.Upload{
+		{CollectionId: "test", RecordId: "test1", Field: "test", Filename: "test.txt", Size: 10},
+		{CollectionId: "test", RecordId: "test2", Field: "test", Filename: "test.txt", Size: 10},
+	}
+	for _, upload := range uploads {
+		if err := app.Dao().SaveUpload(upload); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// make the first upload inactive
+	_, err := app.Dao().DB().Update(
+		"_uploads",
+		dbx.Params{"updated": types.NowDateTime().Time().Add(-10 * time.Minute).Format(types.DefaultDateLayout)},
+		dbx.HashExp{"id": uploads[0].Id},
+	).Execute()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := app.Dao().FindStaleUploads(5 * time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result) != 1 || result[0].Id != uploads[0].Id {
+		t.Fatalf("Expected only upload %q to be stale, got %v", uploads[0].Id, result)
+	}
+}
+
+func TestFindUploadsTotalsByOwner(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	uploads := []*models.Upload{
+		{CollectionId: "test", RecordId: "test1", Field: "test", Filename: "test.txt", Size: 10, OwnerCollectionId: "users", OwnerId: "user1"},
+		{CollectionId: "test", RecordId: "test2", Field: "test", Filename: "test.txt", Size: 20, OwnerCollectionId: "users", OwnerId: "user1"},
+		{CollectionId: "test", RecordId: "test3", Field: "test", Filename: "test.txt", Size: 40, OwnerCollectionId: "users", OwnerId: "user2"},
+		{CollectionId: "test", RecordId: "test4", Field: "test", Filename: "test.txt", Size: 80},
+	}
+	for _, upload := range uploads {
+		if err := app.Dao().SaveUpload(upload); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scenarios := []struct {
+		ownerCollectionId string
+		ownerId           string
+		expectedCount     int
+		expectedTotal     int64
+	}{
+		{"users", "user1", 2, 30},
+		{"users", "user2", 1, 40},
+		{"", "", 1, 80},
+		{"users", "missing", 0, 0},
+		{"", "user1", 0, 0},
+	}
+
+	for i, s := range scenarios {
+		count, total, err := app.Dao().FindUploadsTotalsByOwner(s.ownerCollectionId, s.ownerId)
+		if err != nil {
+			t.Errorf("(%d) Unexpected error %v", i, err)
+			continue
+		}
+
+		if count != s.expectedCount || total != s.expectedTotal {
+			t.Errorf("(%d) Expected %d uploads with %d bytes, got %d with %d bytes", i, s.expectedCount, s.expectedTotal, count, total)
+		}
+	}
+}
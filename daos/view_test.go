@@ -111,7 +111,8 @@ func TestSaveView(t *testing.T) {
 			[]string{
 				"id", "created", "updated",
 				"passwordHash", "tokenKey", "email",
-				"lastResetSentAt", "avatar", "c",
+				"lastResetSentAt", "avatar", "role",
+				"collections", "c",
 			},
 		},
 		{
@@ -0,0 +1,71 @@
+package daos
+
+import (
+	"errors"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+)
+
+// WebhookQuery returns a new Webhook select query.
+func (dao *Dao) WebhookQuery() *dbx.SelectQuery {
+	return dao.ModelQuery(&models.Webhook{})
+}
+
+// FindWebhookById finds a single Webhook model by its id.
+func (dao *Dao) FindWebhookById(id string) (*models.Webhook, error) {
+	model := &models.Webhook{}
+
+	err := dao.WebhookQuery().
+		AndWhere(dbx.HashExp{"id": id}).
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// FindWebhooksByRecordAction returns all enabled webhooks subscribed
+// for the specified action of the provided collection records.
+func (dao *Dao) FindWebhooksByRecordAction(collection *models.Collection, action string) ([]*models.Webhook, error) {
+	webhooks := []*models.Webhook{}
+
+	err := dao.WebhookQuery().
+		AndWhere(dbx.HashExp{"disabled": false}).
+		OrderBy("created ASC").
+		All(&webhooks)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*models.Webhook, 0, len(webhooks))
+
+	for _, webhook := range webhooks {
+		if webhook.HasAction(action) && webhook.HasCollection(collection) {
+			result = append(result, webhook)
+		}
+	}
+
+	return result, nil
+}
+
+// SaveWebhook upserts the provided Webhook model.
+func (dao *Dao) SaveWebhook(webhook *models.Webhook) error {
+	if webhook.Url == "" || webhook.Secret == "" {
+		return errors.New("Missing required webhook url or secret.")
+	}
+
+	return dao.Save(webhook)
+}
+
+// DeleteWebhook deletes the provided Webhook model.
+//
+// Note that the already created webhook deliveries are not affected
+// and will remain in the delivery logs.
+func (dao *Dao) DeleteWebhook(webhook *models.Webhook) error {
+	return dao.Delete(webhook)
+}
@@ -0,0 +1,78 @@
+package daos
+
+import (
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// WebhookDeliveryQuery returns a new WebhookDelivery logs select query.
+func (dao *Dao) WebhookDeliveryQuery() *dbx.SelectQuery {
+	return dao.ModelQuery(&models.WebhookDelivery{})
+}
+
+// FindWebhookDeliveryById finds a single WebhookDelivery log by its id.
+func (dao *Dao) FindWebhookDeliveryById(id string) (*models.WebhookDelivery, error) {
+	model := &models.WebhookDelivery{}
+
+	err := dao.WebhookDeliveryQuery().
+		AndWhere(dbx.HashExp{"id": id}).
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// FindDueWebhookDeliveries returns the oldest pending webhook deliveries
+// whose next attempt is scheduled at or before the specified time.
+//
+// If limit is <= 0 all due deliveries are returned.
+func (dao *Dao) FindDueWebhookDeliveries(before time.Time, limit int) ([]*models.WebhookDelivery, error) {
+	deliveries := []*models.WebhookDelivery{}
+
+	query := dao.WebhookDeliveryQuery().
+		AndWhere(dbx.HashExp{"status": models.WebhookDeliveryStatusPending}).
+		AndWhere(dbx.NewExp("[[nextAttemptAt]] <= {:before}", dbx.Params{
+			"before": before.UTC().Format(types.DefaultDateLayout),
+		})).
+		OrderBy("nextAttemptAt ASC", "created ASC")
+
+	if limit > 0 {
+		query.Limit(int64(limit))
+	}
+
+	err := query.All(&deliveries)
+
+	return deliveries, err
+}
+
+// DeleteOldWebhookDeliveries delete all completed (successful or failed)
+// webhook deliveries that are created before createdBefore.
+//
+// Note that the pending deliveries are never deleted so that
+// the logs cleanup doesn't drop the retries queue.
+func (dao *Dao) DeleteOldWebhookDeliveries(createdBefore time.Time) error {
+	m := models.WebhookDelivery{}
+	tableName := m.TableName()
+
+	formattedDate := createdBefore.UTC().Format(types.DefaultDateLayout)
+	expr := dbx.And(
+		dbx.NewExp("[[created]] <= {:date}", dbx.Params{"date": formattedDate}),
+		dbx.Not(dbx.HashExp{"status": models.WebhookDeliveryStatusPending}),
+	)
+
+	_, err := dao.DB().Delete(tableName, expr).Execute()
+
+	return err
+}
+
+// SaveWebhookDelivery upserts the provided WebhookDelivery model.
+func (dao *Dao) SaveWebhookDelivery(delivery *models.WebhookDelivery) error {
+	return dao.Save(delivery)
+}
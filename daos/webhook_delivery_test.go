@@ -0,0 +1,187 @@
+package daos_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestWebhookDeliveryQuery(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	expected := "SELECT {{_webhookDeliveries}}.* FROM `_webhookDeliveries`"
+
+	sql := app.LogsDao().WebhookDeliveryQuery().Build().SQL()
+	if sql != expected {
+		t.Errorf("Expected sql %s, got %s", expected, sql)
+	}
+}
+
+func TestFindWebhookDeliveryById(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	tests.MockWebhookDeliveriesData(app)
+
+	scenarios := []struct {
+		id          string
+		expectError bool
+	}{
+		{"", true},
+		{"missing", true},
+		{"e8nq3ks90dw2zkq", false},
+		{"9uc3ov0jalnr4yc", false},
+	}
+
+	for _, s := range scenarios {
+		delivery, err := app.LogsDao().FindWebhookDeliveryById(s.id)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.id, s.expectError, hasErr, err)
+			continue
+		}
+
+		if delivery != nil && delivery.Id != s.id {
+			t.Errorf("[%s] Expected delivery with id %s, got %s", s.id, s.id, delivery.Id)
+		}
+	}
+}
+
+func TestFindDueWebhookDeliveries(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	tests.MockWebhookDeliveriesData(app)
+
+	// add another pending delivery
+	delivery := &models.WebhookDelivery{
+		WebhookId: "ftxuvq1ojf4b2pa",
+		Status:    models.WebhookDeliveryStatusPending,
+		Payload:   types.JsonRaw(`{}`),
+	}
+	delivery.NextAttemptAt, _ = types.ParseDateTime("2022-05-03 10:00:00.000Z")
+	if err := app.LogsDao().SaveWebhookDelivery(delivery); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		before   string
+		limit    int
+		expected []string
+	}{
+		{"2022-05-01 10:00:00.000Z", 0, []string{}},
+		{"2022-05-02 12:00:00.123Z", 0, []string{"9uc3ov0jalnr4yc"}},
+		{"2022-05-04 10:00:00.000Z", 0, []string{"9uc3ov0jalnr4yc", delivery.Id}},
+		{"2022-05-04 10:00:00.000Z", 1, []string{"9uc3ov0jalnr4yc"}},
+	}
+
+	for i, s := range scenarios {
+		before, err := time.Parse(types.DefaultDateLayout, s.before)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		deliveries, err := app.LogsDao().FindDueWebhookDeliveries(before, s.limit)
+		if err != nil {
+			t.Errorf("(%d) Unexpected error %v", i, err)
+			continue
+		}
+
+		if len(deliveries) != len(s.expected) {
+			t.Errorf("(%d) Expected %d deliveries, got %d", i, len(s.expected), len(deliveries))
+			continue
+		}
+
+		for j, id := range s.expected {
+			if deliveries[j].Id != id {
+				t.Errorf("(%d) Expected delivery %q at position %d, got %q", i, id, j, deliveries[j].Id)
+			}
+		}
+	}
+}
+
+func TestDeleteOldWebhookDeliveries(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	tests.MockWebhookDeliveriesData(app)
+
+	scenarios := []struct {
+		date          string
+		expectedTotal int
+	}{
+		{"2022-01-01 10:00:00.000Z", 2},
+		{"2022-05-01 11:00:00.000Z", 1},
+		// the pending delivery is never deleted
+		{"2022-05-03 11:00:00.000Z", 1},
+	}
+
+	for i, s := range scenarios {
+		date, err := time.Parse(types.DefaultDateLayout, s.date)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := app.LogsDao().DeleteOldWebhookDeliveries(date); err != nil {
+			t.Errorf("(%d) Delete error %v", i, err)
+		}
+
+		var total int
+		if err := app.LogsDao().WebhookDeliveryQuery().Select("count(*)").Row(&total); err != nil {
+			t.Errorf("(%d) Count error %v", i, err)
+		}
+
+		if total != s.expectedTotal {
+			t.Errorf("(%d) Expected %d remaining deliveries, got %d", i, s.expectedTotal, total)
+		}
+	}
+
+	if _, err := app.LogsDao().FindWebhookDeliveryById("9uc3ov0jalnr4yc"); err != nil {
+		t.Fatalf("Expected the pending delivery to remain, got %v", err)
+	}
+}
+
+func TestSaveWebhookDelivery(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	tests.MockWebhookDeliveriesData(app)
+
+	delivery := &models.WebhookDelivery{
+		WebhookId: "ftxuvq1ojf4b2pa",
+		Status:    models.WebhookDeliveryStatusPending,
+		Payload:   types.JsonRaw(`{"action":"create"}`),
+	}
+	if err := app.LogsDao().SaveWebhookDelivery(delivery); err != nil {
+		t.Fatal(err)
+	}
+
+	existing, err := app.LogsDao().FindWebhookDeliveryById(delivery.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	existing.Status = models.WebhookDeliveryStatusSuccess
+	existing.Attempts = 1
+	if err := app.LogsDao().SaveWebhookDelivery(existing); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := app.LogsDao().FindWebhookDeliveryById(delivery.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if updated.Status != models.WebhookDeliveryStatusSuccess || updated.Attempts != 1 {
+		t.Fatalf("Expected the delivery to be updated, got %v", updated)
+	}
+
+	if updated.Payload.String() != `{"action":"create"}` {
+		t.Fatalf("Unexpected payload %s", updated.Payload.String())
+	}
+}
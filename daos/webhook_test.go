@@ -0,0 +1,171 @@
+package daos_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestWebhookQuery(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	expected := "SELECT {{_webhooks}}.* FROM `_webhooks`"
+
+	sql := app.Dao().WebhookQuery().Build().SQL()
+	if sql != expected {
+		t.Errorf("Expected sql %s, got %s", expected, sql)
+	}
+}
+
+func TestFindWebhookById(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		id          string
+		expectError bool
+	}{
+		{"", true},
+		{"missing", true},
+		{"ftxuvq1ojf4b2pa", false},
+		{"kbbx2t0dpzpuddx", false},
+	}
+
+	for _, s := range scenarios {
+		webhook, err := app.Dao().FindWebhookById(s.id)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.id, s.expectError, hasErr, err)
+			continue
+		}
+
+		if webhook != nil && webhook.Id != s.id {
+			t.Errorf("[%s] Expected webhook with id %s, got %s", s.id, s.id, webhook.Id)
+		}
+	}
+}
+
+func TestFindWebhooksByRecordAction(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	demo1, err := app.Dao().FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	demo2, err := app.Dao().FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// enable the test webhooks
+	demo2Webhook, err := app.Dao().FindWebhookById("ftxuvq1ojf4b2pa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	demo2Webhook.Disabled = false
+	if err := app.Dao().SaveWebhook(demo2Webhook); err != nil {
+		t.Fatal(err)
+	}
+
+	authWebhook, err := app.Dao().FindWebhookById("kbbx2t0dpzpuddx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	authWebhook.Disabled = false
+	if err := app.Dao().SaveWebhook(authWebhook); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name       string
+		collection *models.Collection
+		action     string
+		expected   []string
+	}{
+		{"missing action", demo2, "missing", []string{}},
+		{"non matching collection", demo1, models.WebhookActionCreate, []string{}},
+		{"matching collection and action", demo2, models.WebhookActionCreate, []string{"ftxuvq1ojf4b2pa"}},
+		{"all collections webhook", demo1, models.WebhookActionAuth, []string{"kbbx2t0dpzpuddx"}},
+	}
+
+	for _, s := range scenarios {
+		webhooks, err := app.Dao().FindWebhooksByRecordAction(s.collection, s.action)
+		if err != nil {
+			t.Errorf("[%s] Unexpected error %v", s.name, err)
+			continue
+		}
+
+		if len(webhooks) != len(s.expected) {
+			t.Errorf("[%s] Expected %d webhooks, got %d", s.name, len(s.expected), len(webhooks))
+			continue
+		}
+
+		for i, id := range s.expected {
+			if webhooks[i].Id != id {
+				t.Errorf("[%s] Expected webhook %q at position %d, got %q", s.name, id, i, webhooks[i].Id)
+			}
+		}
+	}
+
+	// disabled webhooks should be ignored
+	authWebhook.Disabled = true
+	if err := app.Dao().SaveWebhook(authWebhook); err != nil {
+		t.Fatal(err)
+	}
+
+	webhooks, err := app.Dao().FindWebhooksByRecordAction(demo1, models.WebhookActionAuth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(webhooks) != 0 {
+		t.Fatalf("Expected no webhooks, got %d", len(webhooks))
+	}
+}
+
+func TestSaveWebhook(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		name        string
+		webhook     *models.Webhook
+		expectError bool
+	}{
+		{"empty model", &models.Webhook{}, true},
+		{"missing url", &models.Webhook{Secret: "test"}, true},
+		{"missing secret", &models.Webhook{Url: "https://example.com"}, true},
+		{"valid model", &models.Webhook{Url: "https://example.com", Secret: "test"}, false},
+	}
+
+	for _, s := range scenarios {
+		err := app.Dao().SaveWebhook(s.webhook)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+		}
+	}
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	webhook, err := app.Dao().FindWebhookById("ftxuvq1ojf4b2pa")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Dao().DeleteWebhook(webhook); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.Dao().FindWebhookById(webhook.Id); err == nil {
+		t.Fatal("Expected the webhook to be deleted")
+	}
+}
@@ -29,6 +29,14 @@ func main() {
 		"the directory with the user defined migrations",
 	)
 
+	var hooksDir string
+	app.RootCmd.PersistentFlags().StringVar(
+		&hooksDir,
+		"hooksDir",
+		"",
+		"the directory with the JS app hooks",
+	)
+
 	var automigrate bool
 	app.RootCmd.PersistentFlags().BoolVar(
 		&automigrate,
@@ -72,6 +80,11 @@ func main() {
 		Dir: migrationsDir,
 	})
 
+	// load js pb_hooks
+	jsvm.MustRegisterHooks(app, &jsvm.HooksOptions{
+		Dir: hooksDir,
+	})
+
 	// migrate command (with js templates)
 	migratecmd.MustRegister(app, app.RootCmd, &migratecmd.Options{
 		TemplateLang: migratecmd.TemplateLangJS,
@@ -0,0 +1,108 @@
+package forms
+
+import (
+	"errors"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+)
+
+// AdminMfaLoginData defines the AdminMfaLogin form submit data.
+type AdminMfaLoginData struct {
+	Admin *models.Admin
+	Mfa   *models.Mfa
+}
+
+// AdminMfaLogin is an admin second factor login form that
+// exchanges a short-lived mfa token and a TOTP (or recovery) code
+// for the authenticated admin.
+type AdminMfaLogin struct {
+	app core.App
+	dao *daos.Dao
+
+	MfaToken string `form:"mfaToken" json:"mfaToken"`
+	Code     string `form:"code" json:"code"`
+}
+
+// NewAdminMfaLogin creates a new [AdminMfaLogin] form initialized with
+// the provided [core.App] instance.
+//
+// If you want to submit the form as part of a transaction,
+// you can change the default Dao via [SetDao()].
+func NewAdminMfaLogin(app core.App) *AdminMfaLogin {
+	return &AdminMfaLogin{
+		app: app,
+		dao: app.Dao(),
+	}
+}
+
+// SetDao replaces the default form Dao instance with the provided one.
+func (form *AdminMfaLogin) SetDao(dao *daos.Dao) {
+	form.dao = dao
+}
+
+// Validate makes the form validatable by implementing [validation.Validatable] interface.
+func (form *AdminMfaLogin) Validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(&form.MfaToken, validation.Required, validation.By(form.checkMfaToken)),
+		validation.Field(&form.Code, validation.Required, validation.Length(1, 255)),
+	)
+}
+
+func (form *AdminMfaLogin) checkMfaToken(value any) error {
+	v, _ := value.(string)
+	if v == "" {
+		return nil // nothing to check
+	}
+
+	admin, err := form.dao.FindAdminByToken(v, form.app.Settings().AdminMfaToken.Secret)
+	if err != nil || admin == nil {
+		return validation.NewError("validation_invalid_token", "Invalid or expired token.")
+	}
+
+	return nil
+}
+
+// Submit validates and submits the admin form.
+// On success returns the authorized admin model.
+//
+// You can optionally provide a list of InterceptorFunc to
+// further modify the form behavior before persisting it.
+func (form *AdminMfaLogin) Submit(interceptors ...InterceptorFunc[*AdminMfaLoginData]) (*models.Admin, error) {
+	if err := form.Validate(); err != nil {
+		return nil, err
+	}
+
+	admin, err := form.dao.FindAdminByToken(form.MfaToken, form.app.Settings().AdminMfaToken.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	mfa, err := form.dao.FindMfaByAdmin(admin)
+	if err != nil || !mfa.Verified {
+		return nil, errors.New("MFA is not enabled for the admin.")
+	}
+
+	data := &AdminMfaLoginData{
+		Admin: admin,
+		Mfa:   mfa,
+	}
+
+	interceptorsErr := runInterceptors(data, func(d *AdminMfaLoginData) error {
+		data = d
+
+		if d.Admin == nil || d.Mfa == nil {
+			return errors.New("Missing admin or mfa data.")
+		}
+
+		return verifyMfaCode(form.app, form.dao, d.Mfa, form.Code)
+	}, interceptors...)
+
+	if interceptorsErr != nil {
+		return nil, interceptorsErr
+	}
+
+	return data.Admin, nil
+}
@@ -0,0 +1,133 @@
+package forms_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tokens"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+func TestAdminMfaLoginValidateAndSubmit(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	setTestMfaEncryptionEnv(t, app)
+
+	admin, err := app.Dao().FindAdminByEmail("test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherAdmin, err := app.Dao().FindAdminByEmail("test2@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secret, recoveryCodes := enableTestMfa(
+		t,
+		app,
+		forms.NewAdminMfaSetup(app, admin),
+		forms.NewAdminMfaConfirm(app, admin),
+	)
+
+	mfaToken, _ := tokens.NewAdminMfaToken(app, admin)
+	authToken, _ := tokens.NewAdminAuthToken(app, admin)
+	otherMfaToken, _ := tokens.NewAdminMfaToken(app, otherAdmin)
+	validCode, _ := security.TOTPCode(secret, time.Now().Add(security.TOTPPeriod))
+
+	scenarios := []struct {
+		name        string
+		token       string
+		code        string
+		expectError bool
+	}{
+		{"empty data", "", "", true},
+		{"auth token instead of mfa token", authToken, validCode, true},
+		{"admin without mfa", otherMfaToken, validCode, true},
+		{"invalid code", mfaToken, "123456", true},
+		{"valid totp code", mfaToken, validCode, false},
+		{"valid recovery code", mfaToken, recoveryCodes[0], false},
+		{"reused recovery code", mfaToken, recoveryCodes[0], true},
+	}
+
+	for _, s := range scenarios {
+		form := forms.NewAdminMfaLogin(app)
+		form.MfaToken = s.token
+		form.Code = s.code
+
+		result, err := form.Submit()
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if !hasErr && result.Id != admin.Id {
+			t.Errorf("[%s] Expected admin %q, got %v", s.name, admin.Id, result)
+		}
+	}
+}
+
+func TestAdminMfaLoginInterceptors(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	setTestMfaEncryptionEnv(t, app)
+
+	admin, err := app.Dao().FindAdminByEmail("test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enableTestMfa(
+		t,
+		app,
+		forms.NewAdminMfaSetup(app, admin),
+		forms.NewAdminMfaConfirm(app, admin),
+	)
+
+	form := forms.NewAdminMfaLogin(app)
+	form.MfaToken, _ = tokens.NewAdminMfaToken(app, admin)
+	form.Code = "123456"
+	var interceptorData *forms.AdminMfaLoginData
+	testErr := errors.New("test_error")
+
+	interceptor1Called := false
+	interceptor1 := func(next forms.InterceptorNextFunc[*forms.AdminMfaLoginData]) forms.InterceptorNextFunc[*forms.AdminMfaLoginData] {
+		return func(data *forms.AdminMfaLoginData) error {
+			interceptor1Called = true
+			return next(data)
+		}
+	}
+
+	interceptor2Called := false
+	interceptor2 := func(next forms.InterceptorNextFunc[*forms.AdminMfaLoginData]) forms.InterceptorNextFunc[*forms.AdminMfaLoginData] {
+		return func(data *forms.AdminMfaLoginData) error {
+			interceptorData = data
+			interceptor2Called = true
+			return testErr
+		}
+	}
+
+	_, submitErr := form.Submit(interceptor1, interceptor2)
+	if submitErr != testErr {
+		t.Fatalf("Expected submitError %v, got %v", testErr, submitErr)
+	}
+
+	if !interceptor1Called {
+		t.Fatalf("Expected interceptor1 to be called")
+	}
+
+	if !interceptor2Called {
+		t.Fatalf("Expected interceptor2 to be called")
+	}
+
+	if interceptorData == nil || interceptorData.Admin.Id != admin.Id || interceptorData.Mfa == nil {
+		t.Fatalf("Expected AdminMfaLoginData for admin %s, got %v", admin.Id, interceptorData)
+	}
+}
@@ -1,12 +1,15 @@
 package forms
 
 import (
+	"strconv"
+
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/daos"
 	"github.com/pocketbase/pocketbase/forms/validators"
 	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/list"
 )
 
 // AdminUpsert is a [models.Admin] upsert (create/update) form.
@@ -15,11 +18,13 @@ type AdminUpsert struct {
 	dao   *daos.Dao
 	admin *models.Admin
 
-	Id              string `form:"id" json:"id"`
-	Avatar          int    `form:"avatar" json:"avatar"`
-	Email           string `form:"email" json:"email"`
-	Password        string `form:"password" json:"password"`
-	PasswordConfirm string `form:"passwordConfirm" json:"passwordConfirm"`
+	Id              string   `form:"id" json:"id"`
+	Avatar          int      `form:"avatar" json:"avatar"`
+	Email           string   `form:"email" json:"email"`
+	Password        string   `form:"password" json:"password"`
+	PasswordConfirm string   `form:"passwordConfirm" json:"passwordConfirm"`
+	Role            string   `form:"role" json:"role"`
+	Collections     []string `form:"collections" json:"collections"`
 }
 
 // NewAdminUpsert creates a new [AdminUpsert] form with initializer
@@ -39,6 +44,12 @@ func NewAdminUpsert(app core.App, admin *models.Admin) *AdminUpsert {
 	form.Id = admin.Id
 	form.Avatar = admin.Avatar
 	form.Email = admin.Email
+	form.Role = admin.Role
+	form.Collections = list.ToUniqueStringSlice(admin.Collections)
+
+	if form.Role == "" {
+		form.Role = models.AdminRoleSuperuser
+	}
 
 	return form
 }
@@ -82,9 +93,33 @@ func (form *AdminUpsert) Validate() error {
 			validation.When(form.Password != "", validation.Required),
 			validation.By(validators.Compare(form.Password)),
 		),
+		validation.Field(
+			&form.Role,
+			validation.Required,
+			validation.In(list.ToInterfaceSlice(models.AdminRoles)...),
+		),
+		validation.Field(
+			&form.Collections,
+			validation.When(form.Role == models.AdminRoleSuperuser, validation.Empty),
+			validation.By(form.checkCollections),
+		),
 	)
 }
 
+func (form *AdminUpsert) checkCollections(value any) error {
+	v, _ := value.([]string)
+
+	for i, nameOrId := range v {
+		if _, err := form.dao.FindCollectionByNameOrId(nameOrId); err != nil {
+			return validation.Errors{
+				strconv.Itoa(i): validation.NewError("validation_missing_collection", "Missing or invalid collection."),
+			}
+		}
+	}
+
+	return nil
+}
+
 func (form *AdminUpsert) checkUniqueEmail(value any) error {
 	v, _ := value.(string)
 
@@ -112,6 +147,8 @@ func (form *AdminUpsert) Submit(interceptors ...InterceptorFunc[*models.Admin])
 
 	form.admin.Avatar = form.Avatar
 	form.admin.Email = form.Email
+	form.admin.Role = form.Role
+	form.admin.Collections = list.ToInterfaceSlice(list.ToUniqueStringSlice(form.Collections))
 
 	if form.Password != "" {
 		form.admin.SetPassword(form.Password)
@@ -9,6 +9,7 @@ import (
 	"github.com/pocketbase/pocketbase/forms"
 	"github.com/pocketbase/pocketbase/models"
 	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/list"
 )
 
 func TestNewAdminUpsert(t *testing.T) {
@@ -28,6 +29,9 @@ func TestNewAdminUpsert(t *testing.T) {
 	if form.Email != admin.Email {
 		t.Errorf("Expected Email %q, got %q", admin.Email, form.Email)
 	}
+	if form.Role != models.AdminRoleSuperuser {
+		t.Errorf("Expected Role %q, got %q", models.AdminRoleSuperuser, form.Role)
+	}
 }
 
 func TestAdminUpsertValidateAndSubmit(t *testing.T) {
@@ -115,6 +119,41 @@ func TestAdminUpsertValidateAndSubmit(t *testing.T) {
 			}`,
 			false,
 		},
+		{
+			// update failure - invalid role
+			"sywbhecnh46rhm0",
+			`{
+				"role": "invalid"
+			}`,
+			true,
+		},
+		{
+			// update failure - superuser with collections
+			"sywbhecnh46rhm0",
+			`{
+				"role":        "superuser",
+				"collections": ["demo1"]
+			}`,
+			true,
+		},
+		{
+			// update failure - missing collection
+			"sywbhecnh46rhm0",
+			`{
+				"role":        "readonly",
+				"collections": ["demo1", "missing"]
+			}`,
+			true,
+		},
+		{
+			// update success - role with collections
+			"sywbhecnh46rhm0",
+			`{
+				"role":        "collections",
+				"collections": ["demo1", "wsmn24bux7wo113", "demo1"]
+			}`,
+			false,
+		},
 	}
 
 	for i, s := range scenarios {
@@ -176,6 +215,14 @@ func TestAdminUpsertValidateAndSubmit(t *testing.T) {
 			t.Errorf("(%d) Expected avatar %d, got %d", i, form.Avatar, foundAdmin.Avatar)
 		}
 
+		if foundAdmin.Role != form.Role {
+			t.Errorf("(%d) Expected role %q, got %q", i, form.Role, foundAdmin.Role)
+		}
+
+		if len(foundAdmin.Collections) != len(list.ToUniqueStringSlice(form.Collections)) {
+			t.Errorf("(%d) Expected unique collections %v, got %v", i, form.Collections, foundAdmin.Collections)
+		}
+
 		if form.Password != "" && initialTokenKey == foundAdmin.TokenKey {
 			t.Errorf("(%d) Expected token key to be renewed when setting a new password", i)
 		}
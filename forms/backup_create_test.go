@@ -0,0 +1,147 @@
+package forms_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestBackupCreateValidateAndSubmit(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		backupName     string
+		expectedErrors []string
+	}{
+		{
+			"invalid length",
+			strings.Repeat("a", 97) + ".zip",
+			[]string{"name"},
+		},
+		{
+			"valid length + invalid format",
+			"test!@#$%^&*()_=.zip",
+			[]string{"name"},
+		},
+		{
+			"valid length + valid format + missing zip extension",
+			"test",
+			[]string{"name"},
+		},
+		{
+			"existing backup name",
+			"existing.zip",
+			[]string{"name"},
+		},
+		{
+			"valid name",
+			"test_backup-1.zip",
+			[]string{},
+		},
+		{
+			"auto generated name",
+			"",
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		func() {
+			app, _ := tests.NewTestApp()
+			defer app.Cleanup()
+
+			fsys, err := app.NewBackupsFilesystem()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer fsys.Close()
+
+			// create a dummy backup file to simulate existing backups
+			if err := fsys.Upload([]byte("test"), "existing.zip"); err != nil {
+				t.Fatal(err)
+			}
+
+			form := forms.NewBackupCreate(app)
+			form.Name = s.backupName
+
+			interceptorCalls := 0
+			result := form.Submit(func(next forms.InterceptorNextFunc[string]) forms.InterceptorNextFunc[string] {
+				return func(name string) error {
+					interceptorCalls++
+					return next(name)
+				}
+			})
+
+			// parse errors
+			errs, ok := result.(validation.Errors)
+			if !ok && result != nil {
+				t.Errorf("[%s] Failed to parse errors %v", s.name, result)
+				return
+			}
+
+			// check errors
+			if len(errs) > len(s.expectedErrors) {
+				t.Errorf("[%s] Expected error keys %v, got %v", s.name, s.expectedErrors, errs)
+			}
+			for _, k := range s.expectedErrors {
+				if _, ok := errs[k]; !ok {
+					t.Errorf("[%s] Missing expected error key %q in %v", s.name, k, errs)
+				}
+			}
+
+			expectedInterceptorCalls := 0
+			if len(s.expectedErrors) == 0 {
+				expectedInterceptorCalls = 1
+			}
+			if interceptorCalls != expectedInterceptorCalls {
+				t.Errorf("[%s] Expected interceptor to be called %d, got %d", s.name, expectedInterceptorCalls, interceptorCalls)
+			}
+
+			if len(s.expectedErrors) > 0 {
+				return
+			}
+
+			if exists, _ := fsys.Exists(form.Name); !exists {
+				t.Errorf("[%s] Expected backup %q to be created", s.name, form.Name)
+			}
+		}()
+	}
+}
+
+func TestBackupCreateInterceptors(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	form := forms.NewBackupCreate(app)
+	form.Name = "test.zip"
+
+	testErr := errors.New("test_error")
+
+	interceptorCalls := 0
+	submitErr := form.Submit(func(next forms.InterceptorNextFunc[string]) forms.InterceptorNextFunc[string] {
+		return func(name string) error {
+			interceptorCalls++
+			return testErr
+		}
+	})
+
+	if submitErr != testErr {
+		t.Fatalf("Expected submitError %v, got %v", testErr, submitErr)
+	}
+
+	if interceptorCalls != 1 {
+		t.Fatalf("Expected the interceptor to be called once, got %d", interceptorCalls)
+	}
+
+	// the backup shouldn't be created
+	fsys, _ := app.NewBackupsFilesystem()
+	defer fsys.Close()
+	if exists, _ := fsys.Exists(form.Name); exists {
+		raw, _ := json.Marshal(form)
+		t.Fatalf("Expected the backup to not be created %s", raw)
+	}
+}
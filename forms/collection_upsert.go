@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
@@ -20,6 +21,15 @@ import (
 
 var collectionNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_]*$`)
 
+// searchableFieldTypes is the list of the schema field types
+// that could be part of the collection full-text search index.
+var searchableFieldTypes = []string{
+	schema.FieldTypeText,
+	schema.FieldTypeEditor,
+	schema.FieldTypeEmail,
+	schema.FieldTypeUrl,
+}
+
 // CollectionUpsert is a [models.Collection] upsert (create/update) form.
 type CollectionUpsert struct {
 	app        core.App
@@ -390,6 +400,21 @@ func (form *CollectionUpsert) checkOptions(value any) error {
 	v, _ := value.(types.JsonMap)
 
 	switch form.Type {
+	case models.CollectionTypeBase:
+		options := models.CollectionBaseOptions{}
+		if err := decodeOptions(v, &options); err != nil {
+			return err
+		}
+
+		// check the generic validations
+		if err := options.Validate(); err != nil {
+			return err
+		}
+
+		// additional form specific validations
+		if err := form.checkSearchFields(options.SearchFields); err != nil {
+			return validation.Errors{"searchFields": err}
+		}
 	case models.CollectionTypeAuth:
 		options := models.CollectionAuthOptions{}
 		if err := decodeOptions(v, &options); err != nil {
@@ -405,6 +430,10 @@ func (form *CollectionUpsert) checkOptions(value any) error {
 		if err := form.checkRule(options.ManageRule); err != nil {
 			return validation.Errors{"manageRule": err}
 		}
+
+		if err := form.checkSearchFields(options.SearchFields); err != nil {
+			return validation.Errors{"searchFields": err}
+		}
 	case models.CollectionTypeView:
 		options := models.CollectionViewOptions{}
 		if err := decodeOptions(v, &options); err != nil {
@@ -430,6 +459,25 @@ func (form *CollectionUpsert) checkOptions(value any) error {
 	return nil
 }
 
+// checkSearchFields ensures that all full-text search fields
+// are existing schema fields with text based values.
+func (form *CollectionUpsert) checkSearchFields(searchFields []string) error {
+	for i, name := range searchFields {
+		field := form.Schema.GetFieldByName(name)
+
+		if field == nil || !list.ExistInSlice(field.Type, searchableFieldTypes) {
+			return validation.Errors{
+				strconv.Itoa(i): validation.NewError(
+					"validation_invalid_search_field",
+					fmt.Sprintf("%q is not a text, editor, email or url schema field.", name),
+				),
+			}
+		}
+	}
+
+	return nil
+}
+
 func decodeOptions(options types.JsonMap, result any) error {
 	raw, err := options.MarshalJSON()
 	if err != nil {
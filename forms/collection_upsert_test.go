@@ -135,6 +135,44 @@ func TestCollectionUpsertValidateAndSubmit(t *testing.T) {
 			}`,
 			[]string{"name"},
 		},
+		{
+			"create failure - missing search field",
+			"",
+			`{
+				"name": "test_new",
+				"schema": [
+					{"name":"test","type":"text"}
+				],
+				"options": {"searchFields": ["test", "missing"]}
+			}`,
+			[]string{"options"},
+		},
+		{
+			"create failure - non text search field",
+			"",
+			`{
+				"name": "test_new",
+				"schema": [
+					{"name":"test","type":"text"},
+					{"name":"active","type":"bool"}
+				],
+				"options": {"searchFields": ["active"]}
+			}`,
+			[]string{"options"},
+		},
+		{
+			"create failure - duplicated auth search fields",
+			"",
+			`{
+				"name": "test_new",
+				"type": "auth",
+				"schema": [
+					{"name":"test","type":"editor"}
+				],
+				"options": {"minPasswordLength": 8, "searchFields": ["test", "test"]}
+			}`,
+			[]string{"options"},
+		},
 		{
 			"create failure - existing internal table",
 			"",
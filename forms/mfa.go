@@ -0,0 +1,223 @@
+package forms
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+const (
+	// MfaRecoveryCodesCount is the number of the generated one-time
+	// recovery codes after a successful mfa enrolment.
+	MfaRecoveryCodesCount = 10
+
+	// MfaMaxAttempts is the max number of the allowed failed code
+	// verification attempts before the mfa is temporary locked
+	// (for the duration of the mfa token, invalidating all issued mfa tokens).
+	MfaMaxAttempts = 5
+
+	mfaEncryptionKeyParam   = "mfaEncryptionKey"
+	mfaRecoveryCodeChunk    = 5
+	mfaRecoveryCodeAlphabet = "abcdefghijkmnpqrstuvwxyz23456789"
+)
+
+var mfaEncryptionKeyMux sync.Mutex
+
+// mfaEncryptionKey returns the 32 chars key used to encrypt the stored TOTP secrets.
+//
+// The key is randomly generated on first use and persisted as app param
+// encrypted with the app encryption env key.
+//
+// MFA requires the app encryption env key to be set because otherwise
+// the key would be stored in plain text next to the secrets it encrypts.
+func mfaEncryptionKey(app core.App, dao *daos.Dao) (string, error) {
+	mfaEncryptionKeyMux.Lock()
+	defer mfaEncryptionKeyMux.Unlock()
+
+	envKey := os.Getenv(app.EncryptionEnv())
+	if envKey == "" {
+		return "", errors.New("MFA requires the app encryption env key to be set.")
+	}
+
+	param, err := dao.FindParamByKey(mfaEncryptionKeyParam)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", err
+	}
+
+	// generate a new key
+	if param == nil {
+		value := mfaEncryptionKeyValue{Key: security.RandomString(32)}
+
+		if err := dao.SaveParam(mfaEncryptionKeyParam, value, envKey); err != nil {
+			return "", err
+		}
+
+		return value.Key, nil
+	}
+
+	decrypted, err := security.Decrypt(string(param.Value), envKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to load the mfa encryption key - invalid encryption key: %w", err)
+	}
+
+	value := mfaEncryptionKeyValue{}
+
+	if err := json.Unmarshal(decrypted, &value); err != nil {
+		return "", err
+	}
+
+	return value.Key, nil
+}
+
+// mfaEncryptionKeyValue defines the stored mfa encryption key param value.
+type mfaEncryptionKeyValue struct {
+	Key string `json:"key"`
+}
+
+// mfaOwner defines the admin or auth record that owns an Mfa model.
+type mfaOwner struct {
+	admin  *models.Admin
+	record *models.Record
+}
+
+func (o mfaOwner) collectionId() string {
+	if o.record != nil {
+		return o.record.Collection().Id
+	}
+	return ""
+}
+
+func (o mfaOwner) recordId() string {
+	if o.record != nil {
+		return o.record.Id
+	}
+	return o.admin.Id
+}
+
+// account returns the owner account name displayed in the authenticator apps.
+func (o mfaOwner) account() string {
+	if o.record == nil {
+		return o.admin.Email
+	}
+
+	if email := o.record.Email(); email != "" {
+		return email
+	}
+
+	return o.record.Username()
+}
+
+// checkAllowed returns an error if the owner auth collection doesn't allow mfa.
+func (o mfaOwner) checkAllowed() error {
+	if o.record != nil && !o.record.Collection().AuthOptions().AllowMfa {
+		return errors.New("The collection is not configured to allow MFA.")
+	}
+
+	return nil
+}
+
+// findMfa returns the owner Mfa model (if any).
+func (o mfaOwner) findMfa(dao *daos.Dao) (*models.Mfa, error) {
+	if o.record != nil {
+		return dao.FindMfaByRecord(o.record)
+	}
+
+	return dao.FindMfaByAdmin(o.admin)
+}
+
+// verifyMfaCode checks whether the provided code is a valid TOTP code
+// or one of the unused mfa recovery codes.
+//
+// Each verification counts toward [MfaMaxAttempts] until a valid code
+// is accepted and already accepted TOTP codes are rejected.
+//
+// If a recovery code is used, it is removed from the mfa model
+// and the change is persisted.
+func verifyMfaCode(app core.App, dao *daos.Dao, mfa *models.Mfa, code string) error {
+	invalidCodeErr := errors.New("Invalid or expired MFA code.")
+
+	attempts, err := dao.IncrementMfaAttempts(mfa, MfaMaxAttempts)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("Too many failed MFA attempts. Please try again later.")
+		}
+		return err
+	}
+
+	key, err := mfaEncryptionKey(app, dao)
+	if err != nil {
+		return err
+	}
+
+	secret, err := mfa.DecryptSecret(key)
+	if err != nil {
+		return err
+	}
+
+	if step, ok := security.MatchTOTPCodeStep(secret, code, time.Now()); ok {
+		used, err := dao.UseMfaStep(mfa, step)
+		if err != nil {
+			return err
+		}
+		if used {
+			return nil
+		}
+	} else if mfa.Verified {
+		used, err := dao.UseMfaRecoveryCode(mfa, normalizeMfaRecoveryCode(code))
+		if err != nil {
+			return err
+		}
+		if used {
+			return nil
+		}
+	}
+
+	if attempts >= MfaMaxAttempts {
+		if err := dao.LockMfa(mfa, time.Now().Add(mfaLockDuration(app, mfa))); err != nil {
+			return err
+		}
+	}
+
+	return invalidCodeErr
+}
+
+// mfaLockDuration returns the duration of the mfa lock after reaching
+// [MfaMaxAttempts] (aka. the owner mfa token duration so that all
+// already issued mfa tokens expire during the lock).
+func mfaLockDuration(app core.App, mfa *models.Mfa) time.Duration {
+	if mfa.IsForAdmin() {
+		return time.Duration(app.Settings().AdminMfaToken.Duration) * time.Second
+	}
+
+	return time.Duration(app.Settings().RecordMfaToken.Duration) * time.Second
+}
+
+// newMfaRecoveryCodes generates a new list of random recovery codes
+// in the format "xxxxx-xxxxx".
+func newMfaRecoveryCodes() []string {
+	codes := make([]string, MfaRecoveryCodesCount)
+
+	for i := range codes {
+		codes[i] = security.RandomStringWithAlphabet(mfaRecoveryCodeChunk, mfaRecoveryCodeAlphabet) +
+			"-" +
+			security.RandomStringWithAlphabet(mfaRecoveryCodeChunk, mfaRecoveryCodeAlphabet)
+	}
+
+	return codes
+}
+
+// normalizeMfaRecoveryCode normalizes a user submitted recovery code
+// (eg. trims the surrounding whitespaces and lowercase it).
+func normalizeMfaRecoveryCode(code string) string {
+	return strings.ToLower(strings.TrimSpace(code))
+}
@@ -0,0 +1,105 @@
+package forms
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+)
+
+// MfaConfirm is a form that verifies the first TOTP code of
+// a pending mfa enrolment (see [MfaSetup]) and enables the mfa.
+type MfaConfirm struct {
+	app   core.App
+	dao   *daos.Dao
+	owner mfaOwner
+
+	Code string `form:"code" json:"code"`
+}
+
+// NewAdminMfaConfirm creates a new [MfaConfirm] form for the provided admin.
+//
+// If you want to submit the form as part of a transaction,
+// you can change the default Dao via [SetDao()].
+func NewAdminMfaConfirm(app core.App, admin *models.Admin) *MfaConfirm {
+	return &MfaConfirm{
+		app:   app,
+		dao:   app.Dao(),
+		owner: mfaOwner{admin: admin},
+	}
+}
+
+// NewRecordMfaConfirm creates a new [MfaConfirm] form for the provided auth record.
+//
+// If you want to submit the form as part of a transaction,
+// you can change the default Dao via [SetDao()].
+func NewRecordMfaConfirm(app core.App, record *models.Record) *MfaConfirm {
+	return &MfaConfirm{
+		app:   app,
+		dao:   app.Dao(),
+		owner: mfaOwner{record: record},
+	}
+}
+
+// SetDao replaces the default form Dao instance with the provided one.
+func (form *MfaConfirm) SetDao(dao *daos.Dao) {
+	form.dao = dao
+}
+
+// Validate makes the form validatable by implementing [validation.Validatable] interface.
+func (form *MfaConfirm) Validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(&form.Code, validation.Required, validation.By(form.checkCode)),
+	)
+}
+
+func (form *MfaConfirm) checkCode(value any) error {
+	v, _ := value.(string)
+
+	mfa, _ := form.owner.findMfa(form.dao)
+	if mfa == nil || mfa.Verified {
+		return validation.NewError("validation_missing_mfa_setup", "Missing or already confirmed MFA setup.")
+	}
+
+	if err := verifyMfaCode(form.app, form.dao, mfa, v); err != nil {
+		return validation.NewError("validation_invalid_mfa_code", "Invalid or expired MFA code.")
+	}
+
+	return nil
+}
+
+// Submit validates and submits the form.
+// On success marks the mfa as verified and returns
+// the list of the newly generated plain recovery codes.
+//
+// You can optionally provide a list of InterceptorFunc to
+// further modify the form behavior before persisting it.
+func (form *MfaConfirm) Submit(interceptors ...InterceptorFunc[*models.Mfa]) ([]string, error) {
+	if err := form.owner.checkAllowed(); err != nil {
+		return nil, err
+	}
+
+	if err := form.Validate(); err != nil {
+		return nil, err
+	}
+
+	mfa, err := form.owner.findMfa(form.dao)
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryCodes := newMfaRecoveryCodes()
+
+	mfa.Verified = true
+	mfa.SetRecoveryCodes(recoveryCodes)
+
+	interceptorsErr := runInterceptors(mfa, func(m *models.Mfa) error {
+		return form.dao.SaveMfa(m)
+	}, interceptors...)
+
+	if interceptorsErr != nil {
+		return nil, interceptorsErr
+	}
+
+	return recoveryCodes, nil
+}
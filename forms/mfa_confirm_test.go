@@ -0,0 +1,115 @@
+package forms_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+func TestMfaConfirmValidateAndSubmit(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	setTestMfaEncryptionEnv(t, app)
+
+	record, err := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	allowTestCollectionMfa(t, app, record)
+
+	// missing setup
+	form := forms.NewRecordMfaConfirm(app, record)
+	form.Code = "123456"
+	if _, err := form.Submit(); err == nil {
+		t.Fatal("Expected error for missing mfa setup")
+	}
+
+	result, err := forms.NewRecordMfaSetup(app, record).Submit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validCode, _ := security.TOTPCode(result.Secret, time.Now())
+	oldCode, _ := security.TOTPCode(result.Secret, time.Now().Add(-5*time.Minute))
+
+	scenarios := []struct {
+		code        string
+		expectError bool
+	}{
+		{"", true},
+		{"invalid", true},
+		{oldCode, true},
+		{validCode, false},
+		{validCode, true}, // already verified
+	}
+
+	for i, s := range scenarios {
+		form := forms.NewRecordMfaConfirm(app, record)
+		form.Code = s.code
+
+		recoveryCodes, err := form.Submit()
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		if len(recoveryCodes) != forms.MfaRecoveryCodesCount {
+			t.Errorf("(%d) Expected %d recovery codes, got %v", i, forms.MfaRecoveryCodesCount, recoveryCodes)
+		}
+
+		mfa, err := app.Dao().FindMfaByRecord(record)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !mfa.Verified {
+			t.Errorf("(%d) Expected the mfa to be verified", i)
+		}
+
+		if len(mfa.RecoveryCodes) != forms.MfaRecoveryCodesCount {
+			t.Errorf("(%d) Expected %d stored recovery codes, got %v", i, forms.MfaRecoveryCodesCount, mfa.RecoveryCodes)
+		}
+	}
+}
+
+func TestAdminMfaConfirmSubmit(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	setTestMfaEncryptionEnv(t, app)
+
+	admin, err := app.Dao().FindAdminByEmail("test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, recoveryCodes := enableTestMfa(
+		t,
+		app,
+		forms.NewAdminMfaSetup(app, admin),
+		forms.NewAdminMfaConfirm(app, admin),
+	)
+
+	if len(recoveryCodes) != forms.MfaRecoveryCodesCount {
+		t.Fatalf("Expected %d recovery codes, got %v", forms.MfaRecoveryCodesCount, recoveryCodes)
+	}
+
+	mfa, err := app.Dao().FindMfaByAdmin(admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !mfa.Verified {
+		t.Fatal("Expected the admin mfa to be verified")
+	}
+}
@@ -0,0 +1,89 @@
+package forms
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+)
+
+// MfaDisable is a form that disables (aka. deletes) the mfa of a
+// single admin or auth record after verifying a TOTP or recovery code.
+type MfaDisable struct {
+	app   core.App
+	dao   *daos.Dao
+	owner mfaOwner
+
+	Code string `form:"code" json:"code"`
+}
+
+// NewAdminMfaDisable creates a new [MfaDisable] form for the provided admin.
+//
+// If you want to submit the form as part of a transaction,
+// you can change the default Dao via [SetDao()].
+func NewAdminMfaDisable(app core.App, admin *models.Admin) *MfaDisable {
+	return &MfaDisable{
+		app:   app,
+		dao:   app.Dao(),
+		owner: mfaOwner{admin: admin},
+	}
+}
+
+// NewRecordMfaDisable creates a new [MfaDisable] form for the provided auth record.
+//
+// If you want to submit the form as part of a transaction,
+// you can change the default Dao via [SetDao()].
+func NewRecordMfaDisable(app core.App, record *models.Record) *MfaDisable {
+	return &MfaDisable{
+		app:   app,
+		dao:   app.Dao(),
+		owner: mfaOwner{record: record},
+	}
+}
+
+// SetDao replaces the default form Dao instance with the provided one.
+func (form *MfaDisable) SetDao(dao *daos.Dao) {
+	form.dao = dao
+}
+
+// Validate makes the form validatable by implementing [validation.Validatable] interface.
+func (form *MfaDisable) Validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(&form.Code, validation.Required, validation.By(form.checkCode)),
+	)
+}
+
+func (form *MfaDisable) checkCode(value any) error {
+	v, _ := value.(string)
+
+	mfa, _ := form.owner.findMfa(form.dao)
+	if mfa == nil || !mfa.Verified {
+		return validation.NewError("validation_mfa_not_enabled", "MFA is not enabled.")
+	}
+
+	if err := verifyMfaCode(form.app, form.dao, mfa, v); err != nil {
+		return validation.NewError("validation_invalid_mfa_code", "Invalid or expired MFA code.")
+	}
+
+	return nil
+}
+
+// Submit validates and submits the form.
+// On success deletes the owner mfa model.
+//
+// You can optionally provide a list of InterceptorFunc to
+// further modify the form behavior before persisting it.
+func (form *MfaDisable) Submit(interceptors ...InterceptorFunc[*models.Mfa]) error {
+	if err := form.Validate(); err != nil {
+		return err
+	}
+
+	mfa, err := form.owner.findMfa(form.dao)
+	if err != nil {
+		return err
+	}
+
+	return runInterceptors(mfa, func(m *models.Mfa) error {
+		return form.dao.DeleteMfa(m)
+	}, interceptors...)
+}
@@ -0,0 +1,86 @@
+package forms_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+func TestMfaDisableValidateAndSubmit(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	setTestMfaEncryptionEnv(t, app)
+
+	admin, err := app.Dao().FindAdminByEmail("test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// missing mfa
+	form := forms.NewAdminMfaDisable(app, admin)
+	form.Code = "123456"
+	if err := form.Submit(); err == nil {
+		t.Fatal("Expected error for missing mfa")
+	}
+
+	secret, _ := enableTestMfa(
+		t,
+		app,
+		forms.NewAdminMfaSetup(app, admin),
+		forms.NewAdminMfaConfirm(app, admin),
+	)
+
+	// invalid code
+	form = forms.NewAdminMfaDisable(app, admin)
+	form.Code = "invalid"
+	if err := form.Submit(); err == nil {
+		t.Fatal("Expected error for invalid code")
+	}
+	if _, err := app.Dao().FindMfaByAdmin(admin); err != nil {
+		t.Fatal("Expected the mfa to not be deleted")
+	}
+
+	// valid code
+	form = forms.NewAdminMfaDisable(app, admin)
+	form.Code, _ = security.TOTPCode(secret, time.Now().Add(security.TOTPPeriod))
+	if err := form.Submit(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.Dao().FindMfaByAdmin(admin); err == nil {
+		t.Fatal("Expected the mfa to be deleted")
+	}
+}
+
+func TestMfaDisableWithRecoveryCode(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	setTestMfaEncryptionEnv(t, app)
+
+	record, err := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	allowTestCollectionMfa(t, app, record)
+
+	_, recoveryCodes := enableTestMfa(
+		t,
+		app,
+		forms.NewRecordMfaSetup(app, record),
+		forms.NewRecordMfaConfirm(app, record),
+	)
+
+	form := forms.NewRecordMfaDisable(app, record)
+	form.Code = " " + recoveryCodes[3] + " "
+	if err := form.Submit(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.Dao().FindMfaByRecord(record); err == nil {
+		t.Fatal("Expected the mfa to be deleted")
+	}
+}
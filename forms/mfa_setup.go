@@ -0,0 +1,114 @@
+package forms
+
+import (
+	"errors"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+// MfaSetup is a form that starts a new TOTP mfa enrolment
+// for a single admin or auth record.
+//
+// The enrolment must be completed with [MfaConfirm].
+//
+// Note that MFA requires the app encryption env key to be set
+// (see the "--encryptionEnv" flag) because it is used to encrypt
+// the key of the stored TOTP secrets.
+type MfaSetup struct {
+	app   core.App
+	dao   *daos.Dao
+	owner mfaOwner
+}
+
+// MfaSetupResult defines the [MfaSetup] form submit result.
+type MfaSetupResult struct {
+	Mfa *models.Mfa
+
+	// Secret is the plain base32 encoded TOTP secret.
+	Secret string
+
+	// AuthUrl is the "otpauth://" uri of the TOTP secret
+	// (usually rendered as QR code for the authenticator apps).
+	AuthUrl string
+}
+
+// NewAdminMfaSetup creates a new [MfaSetup] form for the provided admin.
+//
+// If you want to submit the form as part of a transaction,
+// you can change the default Dao via [SetDao()].
+func NewAdminMfaSetup(app core.App, admin *models.Admin) *MfaSetup {
+	return &MfaSetup{
+		app:   app,
+		dao:   app.Dao(),
+		owner: mfaOwner{admin: admin},
+	}
+}
+
+// NewRecordMfaSetup creates a new [MfaSetup] form for the provided auth record.
+//
+// If you want to submit the form as part of a transaction,
+// you can change the default Dao via [SetDao()].
+func NewRecordMfaSetup(app core.App, record *models.Record) *MfaSetup {
+	return &MfaSetup{
+		app:   app,
+		dao:   app.Dao(),
+		owner: mfaOwner{record: record},
+	}
+}
+
+// SetDao replaces the default form Dao instance with the provided one.
+func (form *MfaSetup) SetDao(dao *daos.Dao) {
+	form.dao = dao
+}
+
+// Submit generates a new TOTP secret and stores it encrypted
+// as unverified Mfa model (replacing any previous unverified one).
+//
+// You can optionally provide a list of InterceptorFunc to
+// further modify the form behavior before persisting it.
+func (form *MfaSetup) Submit(interceptors ...InterceptorFunc[*models.Mfa]) (*MfaSetupResult, error) {
+	if err := form.owner.checkAllowed(); err != nil {
+		return nil, err
+	}
+
+	mfa, _ := form.owner.findMfa(form.dao)
+	if mfa == nil {
+		mfa = &models.Mfa{
+			CollectionId: form.owner.collectionId(),
+			RecordId:     form.owner.recordId(),
+		}
+	} else if mfa.Verified {
+		return nil, errors.New("MFA is already enabled.")
+	}
+
+	secret, err := security.NewTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := mfaEncryptionKey(form.app, form.dao)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := mfa.SetSecret(secret, key); err != nil {
+		return nil, err
+	}
+
+	interceptorsErr := runInterceptors(mfa, func(m *models.Mfa) error {
+		return form.dao.SaveMfa(m)
+	}, interceptors...)
+
+	if interceptorsErr != nil {
+		return nil, interceptorsErr
+	}
+
+	return &MfaSetupResult{
+		Mfa:     mfa,
+		Secret:  secret,
+		AuthUrl: security.TOTPAuthUrl(form.app.Settings().Meta.AppName, form.owner.account(), secret),
+	}, nil
+}
@@ -0,0 +1,251 @@
+package forms_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+func TestRecordMfaSetupSubmit(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	setTestMfaEncryptionEnv(t, app)
+
+	record, err := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// collection without mfa
+	if _, err := forms.NewRecordMfaSetup(app, record).Submit(); err == nil {
+		t.Fatal("Expected error for collection without enabled mfa")
+	}
+
+	allowTestCollectionMfa(t, app, record)
+
+	result1, err := forms.NewRecordMfaSetup(app, record).Submit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result1.Mfa.Verified {
+		t.Fatal("Expected the mfa to be unverified")
+	}
+
+	if result1.Mfa.Secret == result1.Secret {
+		t.Fatal("Expected the stored mfa secret to be encrypted")
+	}
+
+	if !strings.HasPrefix(result1.AuthUrl, "otpauth://totp/acme_test:test@example.com?") {
+		t.Fatalf("Unexpected auth url %q", result1.AuthUrl)
+	}
+
+	// repeating the setup should replace the unverified secret
+	result2, err := forms.NewRecordMfaSetup(app, record).Submit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result2.Mfa.Id != result1.Mfa.Id {
+		t.Fatalf("Expected the unverified mfa %q to be reused, got %q", result1.Mfa.Id, result2.Mfa.Id)
+	}
+
+	if result2.Secret == result1.Secret {
+		t.Fatal("Expected a new secret to be generated")
+	}
+
+	// already verified mfa
+	confirm := forms.NewRecordMfaConfirm(app, record)
+	confirm.Code, _ = security.TOTPCode(result2.Secret, time.Now())
+	if _, err := confirm.Submit(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := forms.NewRecordMfaSetup(app, record).Submit(); err == nil {
+		t.Fatal("Expected error for already enabled mfa")
+	}
+}
+
+func TestAdminMfaSetupSubmit(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	setTestMfaEncryptionEnv(t, app)
+
+	admin, err := app.Dao().FindAdminByEmail("test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := forms.NewAdminMfaSetup(app, admin).Submit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Mfa.CollectionId != "" || result.Mfa.RecordId != admin.Id {
+		t.Fatalf("Expected the mfa to be linked to the admin, got %v", result.Mfa)
+	}
+
+	stored, err := app.Dao().FindMfaByAdmin(admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stored.Verified {
+		t.Fatal("Expected the stored mfa to be unverified")
+	}
+}
+
+func TestMfaSetupInterceptors(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	setTestMfaEncryptionEnv(t, app)
+
+	admin, err := app.Dao().FindAdminByEmail("test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := forms.NewAdminMfaSetup(app, admin)
+	var interceptorMfa *models.Mfa
+	testErr := errors.New("test_error")
+
+	interceptor1Called := false
+	interceptor1 := func(next forms.InterceptorNextFunc[*models.Mfa]) forms.InterceptorNextFunc[*models.Mfa] {
+		return func(m *models.Mfa) error {
+			interceptor1Called = true
+			return next(m)
+		}
+	}
+
+	interceptor2Called := false
+	interceptor2 := func(next forms.InterceptorNextFunc[*models.Mfa]) forms.InterceptorNextFunc[*models.Mfa] {
+		return func(m *models.Mfa) error {
+			interceptorMfa = m
+			interceptor2Called = true
+			return testErr
+		}
+	}
+
+	_, submitErr := form.Submit(interceptor1, interceptor2)
+	if submitErr != testErr {
+		t.Fatalf("Expected submitError %v, got %v", testErr, submitErr)
+	}
+
+	if !interceptor1Called {
+		t.Fatalf("Expected interceptor1 to be called")
+	}
+
+	if !interceptor2Called {
+		t.Fatalf("Expected interceptor2 to be called")
+	}
+
+	if interceptorMfa == nil || interceptorMfa.RecordId != admin.Id {
+		t.Fatalf("Expected Mfa model for admin %s, got %v", admin.Id, interceptorMfa)
+	}
+
+	if _, err := app.Dao().FindMfaByAdmin(admin); err == nil {
+		t.Fatal("Expected the mfa to not be persisted")
+	}
+}
+
+// setTestMfaEncryptionEnv sets the app encryption env key required by the mfa forms.
+func setTestMfaEncryptionEnv(t *testing.T, app *tests.TestApp) {
+	t.Setenv(app.EncryptionEnv(), "abcdabcdabcdabcdabcdabcdabcdabcd")
+}
+
+// allowTestCollectionMfa enables the AllowMfa option of the record collection.
+func allowTestCollectionMfa(t *testing.T, app *tests.TestApp, record *models.Record) {
+	collection := record.Collection()
+
+	options := collection.AuthOptions()
+	options.AllowMfa = true
+	collection.SetOptions(options)
+
+	if err := app.Dao().SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// enableTestMfa completes a new mfa enrolment for the provided
+// admin or auth record and returns its plain secret and recovery codes.
+func enableTestMfa(t *testing.T, app *tests.TestApp, setup *forms.MfaSetup, confirm *forms.MfaConfirm) (string, []string) {
+	result, err := setup.Submit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	confirm.Code, err = security.TOTPCode(result.Secret, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recoveryCodes, err := confirm.Submit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return result.Secret, recoveryCodes
+}
+
+func TestMfaSetupWithoutEncryptionEnv(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	t.Setenv(app.EncryptionEnv(), "")
+
+	admin, err := app.Dao().FindAdminByEmail("test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := forms.NewAdminMfaSetup(app, admin).Submit(); err == nil {
+		t.Fatal("Expected the mfa setup to fail without encryption env key")
+	}
+
+	if _, err := app.Dao().FindParamByKey("mfaEncryptionKey"); err == nil {
+		t.Fatal("Expected the mfa encryption key to not be stored")
+	}
+}
+
+func TestMfaSetupWithEncryptionEnv(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	t.Setenv(app.EncryptionEnv(), "abcdabcdabcdabcdabcdabcdabcdabcd")
+
+	admin, err := app.Dao().FindAdminByEmail("test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secret, _ := enableTestMfa(
+		t,
+		app,
+		forms.NewAdminMfaSetup(app, admin),
+		forms.NewAdminMfaConfirm(app, admin),
+	)
+
+	param, err := app.Dao().FindParamByKey("mfaEncryptionKey")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(param.Value), `"key"`) {
+		t.Fatalf("Expected the stored mfa encryption key to be encrypted, got %s", param.Value)
+	}
+
+	// the stored key should be still usable
+	form := forms.NewAdminMfaDisable(app, admin)
+	form.Code, _ = security.TOTPCode(secret, time.Now().Add(security.TOTPPeriod))
+	if err := form.Submit(); err != nil {
+		t.Fatal(err)
+	}
+}
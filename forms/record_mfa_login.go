@@ -0,0 +1,122 @@
+package forms
+
+import (
+	"errors"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+)
+
+// RecordMfaLoginData defines the RecordMfaLogin form submit data.
+type RecordMfaLoginData struct {
+	Record *models.Record
+	Mfa    *models.Mfa
+}
+
+// RecordMfaLogin is an auth record second factor login form that
+// exchanges a short-lived mfa token and a TOTP (or recovery) code
+// for the authenticated record.
+type RecordMfaLogin struct {
+	app        core.App
+	dao        *daos.Dao
+	collection *models.Collection
+
+	MfaToken string `form:"mfaToken" json:"mfaToken"`
+	Code     string `form:"code" json:"code"`
+}
+
+// NewRecordMfaLogin creates a new [RecordMfaLogin] form initialized
+// with from the provided [core.App] and [models.Collection] instance.
+//
+// If you want to submit the form as part of a transaction,
+// you can change the default Dao via [SetDao()].
+func NewRecordMfaLogin(app core.App, collection *models.Collection) *RecordMfaLogin {
+	return &RecordMfaLogin{
+		app:        app,
+		dao:        app.Dao(),
+		collection: collection,
+	}
+}
+
+// SetDao replaces the default form Dao instance with the provided one.
+func (form *RecordMfaLogin) SetDao(dao *daos.Dao) {
+	form.dao = dao
+}
+
+// Validate makes the form validatable by implementing [validation.Validatable] interface.
+func (form *RecordMfaLogin) Validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(&form.MfaToken, validation.Required, validation.By(form.checkMfaToken)),
+		validation.Field(&form.Code, validation.Required, validation.Length(1, 255)),
+	)
+}
+
+func (form *RecordMfaLogin) checkMfaToken(value any) error {
+	v, _ := value.(string)
+	if v == "" {
+		return nil // nothing to check
+	}
+
+	if _, err := form.findRecord(v); err != nil {
+		return validation.NewError("validation_invalid_token", "Invalid or expired token.")
+	}
+
+	return nil
+}
+
+func (form *RecordMfaLogin) findRecord(token string) (*models.Record, error) {
+	record, err := form.dao.FindAuthRecordByToken(token, form.app.Settings().RecordMfaToken.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	if record.Collection().Id != form.collection.Id {
+		return nil, errors.New("The mfa token doesn't belong to the form collection.")
+	}
+
+	return record, nil
+}
+
+// Submit validates and submits the form.
+// On success returns the authorized record model.
+//
+// You can optionally provide a list of InterceptorFunc to
+// further modify the form behavior before persisting it.
+func (form *RecordMfaLogin) Submit(interceptors ...InterceptorFunc[*RecordMfaLoginData]) (*models.Record, error) {
+	if err := form.Validate(); err != nil {
+		return nil, err
+	}
+
+	record, err := form.findRecord(form.MfaToken)
+	if err != nil {
+		return nil, err
+	}
+
+	mfa, err := form.dao.FindMfaByRecord(record)
+	if err != nil || !mfa.Verified {
+		return nil, errors.New("MFA is not enabled for the auth record.")
+	}
+
+	data := &RecordMfaLoginData{
+		Record: record,
+		Mfa:    mfa,
+	}
+
+	interceptorsErr := runInterceptors(data, func(d *RecordMfaLoginData) error {
+		data = d
+
+		if d.Record == nil || d.Mfa == nil {
+			return errors.New("Missing auth record or mfa data.")
+		}
+
+		return verifyMfaCode(form.app, form.dao, d.Mfa, form.Code)
+	}, interceptors...)
+
+	if interceptorsErr != nil {
+		return nil, interceptorsErr
+	}
+
+	return data.Record, nil
+}
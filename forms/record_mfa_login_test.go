@@ -0,0 +1,191 @@
+package forms_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tokens"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+func TestRecordMfaLoginValidateAndSubmit(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	setTestMfaEncryptionEnv(t, app)
+
+	record, err := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	allowTestCollectionMfa(t, app, record)
+
+	otherRecord, err := app.Dao().FindAuthRecordByEmail("users", "test2@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clientsRecord, err := app.Dao().FindAuthRecordByEmail("clients", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secret, recoveryCodes := enableTestMfa(
+		t,
+		app,
+		forms.NewRecordMfaSetup(app, record),
+		forms.NewRecordMfaConfirm(app, record),
+	)
+
+	mfaToken, _ := tokens.NewRecordMfaToken(app, record)
+	authToken, _ := tokens.NewRecordAuthToken(app, record)
+	otherMfaToken, _ := tokens.NewRecordMfaToken(app, otherRecord)
+	clientsMfaToken, _ := tokens.NewRecordMfaToken(app, clientsRecord)
+	validCode, _ := security.TOTPCode(secret, time.Now().Add(security.TOTPPeriod))
+
+	scenarios := []struct {
+		name        string
+		token       string
+		code        string
+		expectError bool
+	}{
+		{"empty data", "", "", true},
+		{"auth token instead of mfa token", authToken, validCode, true},
+		{"mfa token from different collection", clientsMfaToken, validCode, true},
+		{"record without mfa", otherMfaToken, validCode, true},
+		{"invalid code", mfaToken, "123456", true},
+		{"valid totp code", mfaToken, validCode, false},
+		{"replayed totp code", mfaToken, validCode, true},
+		{"valid recovery code", mfaToken, recoveryCodes[0], false},
+		{"reused recovery code", mfaToken, recoveryCodes[0], true},
+	}
+
+	for _, s := range scenarios {
+		form := forms.NewRecordMfaLogin(app, record.Collection())
+		form.MfaToken = s.token
+		form.Code = s.code
+
+		result, err := form.Submit()
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if !hasErr && result.Id != record.Id {
+			t.Errorf("[%s] Expected record %q, got %v", s.name, record.Id, result)
+		}
+	}
+}
+
+func TestRecordMfaLoginMaxAttempts(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	setTestMfaEncryptionEnv(t, app)
+
+	record, err := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	allowTestCollectionMfa(t, app, record)
+
+	secret, _ := enableTestMfa(
+		t,
+		app,
+		forms.NewRecordMfaSetup(app, record),
+		forms.NewRecordMfaConfirm(app, record),
+	)
+
+	mfaToken, _ := tokens.NewRecordMfaToken(app, record)
+
+	for i := 0; i < forms.MfaMaxAttempts; i++ {
+		form := forms.NewRecordMfaLogin(app, record.Collection())
+		form.MfaToken = mfaToken
+		form.Code = "123456"
+		if _, err := form.Submit(); err == nil {
+			t.Fatalf("(%d) Expected invalid code error", i)
+		}
+	}
+
+	// valid code after reaching the max attempts
+	form := forms.NewRecordMfaLogin(app, record.Collection())
+	form.MfaToken = mfaToken
+	form.Code, _ = security.TOTPCode(secret, time.Now().Add(security.TOTPPeriod))
+	if _, err := form.Submit(); err == nil {
+		t.Fatal("Expected the mfa to be locked")
+	}
+
+	mfa, err := app.Dao().FindMfaByRecord(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !mfa.IsLocked() {
+		t.Fatalf("Expected the mfa to be locked, got lockedUntil %v", mfa.LockedUntil)
+	}
+}
+
+func TestRecordMfaLoginInterceptors(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	setTestMfaEncryptionEnv(t, app)
+
+	record, err := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	allowTestCollectionMfa(t, app, record)
+
+	enableTestMfa(
+		t,
+		app,
+		forms.NewRecordMfaSetup(app, record),
+		forms.NewRecordMfaConfirm(app, record),
+	)
+
+	form := forms.NewRecordMfaLogin(app, record.Collection())
+	form.MfaToken, _ = tokens.NewRecordMfaToken(app, record)
+	form.Code = "123456"
+	var interceptorData *forms.RecordMfaLoginData
+	testErr := errors.New("test_error")
+
+	interceptor1Called := false
+	interceptor1 := func(next forms.InterceptorNextFunc[*forms.RecordMfaLoginData]) forms.InterceptorNextFunc[*forms.RecordMfaLoginData] {
+		return func(data *forms.RecordMfaLoginData) error {
+			interceptor1Called = true
+			return next(data)
+		}
+	}
+
+	interceptor2Called := false
+	interceptor2 := func(next forms.InterceptorNextFunc[*forms.RecordMfaLoginData]) forms.InterceptorNextFunc[*forms.RecordMfaLoginData] {
+		return func(data *forms.RecordMfaLoginData) error {
+			interceptorData = data
+			interceptor2Called = true
+			return testErr
+		}
+	}
+
+	_, submitErr := form.Submit(interceptor1, interceptor2)
+	if submitErr != testErr {
+		t.Fatalf("Expected submitError %v, got %v", testErr, submitErr)
+	}
+
+	if !interceptor1Called {
+		t.Fatalf("Expected interceptor1 to be called")
+	}
+
+	if !interceptor2Called {
+		t.Fatalf("Expected interceptor2 to be called")
+	}
+
+	if interceptorData == nil || interceptorData.Record.Id != record.Id || interceptorData.Mfa == nil {
+		t.Fatalf("Expected RecordMfaLoginData for record %s, got %v", record.Id, interceptorData)
+	}
+}
@@ -0,0 +1,225 @@
+package forms
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+// OTPMaxAttempts is the max number of the allowed failed
+// password attempts before the OTP model is invalidated.
+const OTPMaxAttempts = 5
+
+// RecordOTPLoginData defines the RecordOTPLogin form submit data.
+type RecordOTPLoginData struct {
+	Record *models.Record
+	OTP    *models.OTP
+}
+
+// RecordOTPLogin is an auth record one-time password login form.
+//
+// The form could be submitted either with an OTP id and its emailed
+// password, or with the emailed magic link token.
+type RecordOTPLogin struct {
+	app        core.App
+	dao        *daos.Dao
+	collection *models.Collection
+
+	OtpId    string `form:"otpId" json:"otpId"`
+	Password string `form:"password" json:"password"`
+	Token    string `form:"token" json:"token"`
+}
+
+// NewRecordOTPLogin creates a new [RecordOTPLogin] form initialized
+// with from the provided [core.App] and [models.Collection] instance.
+//
+// If you want to submit the form as part of a transaction,
+// you can change the default Dao via [SetDao()].
+func NewRecordOTPLogin(app core.App, collection *models.Collection) *RecordOTPLogin {
+	return &RecordOTPLogin{
+		app:        app,
+		dao:        app.Dao(),
+		collection: collection,
+	}
+}
+
+// SetDao replaces the default form Dao instance with the provided one.
+func (form *RecordOTPLogin) SetDao(dao *daos.Dao) {
+	form.dao = dao
+}
+
+// Validate makes the form validatable by implementing [validation.Validatable] interface.
+func (form *RecordOTPLogin) Validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(&form.OtpId, validation.When(form.Token == "", validation.Required), validation.Length(1, 255)),
+		validation.Field(&form.Password, validation.When(form.Token == "", validation.Required), validation.Length(1, 255)),
+		validation.Field(&form.Token, validation.By(form.checkToken)),
+	)
+}
+
+func (form *RecordOTPLogin) checkToken(value any) error {
+	v, _ := value.(string)
+	if v == "" {
+		return nil // nothing to check
+	}
+
+	if _, _, err := form.parseToken(v); err != nil {
+		return validation.NewError("validation_invalid_token", "Invalid or expired token.")
+	}
+
+	return nil
+}
+
+// parseToken verifies the provided magic link token and
+// returns its associated auth record and OTP model.
+func (form *RecordOTPLogin) parseToken(token string) (*models.Record, *models.OTP, error) {
+	claims, _ := security.ParseUnverifiedJWT(token)
+	otpId, _ := claims["otpId"].(string)
+	if otpId == "" {
+		return nil, nil, errors.New("Missing token otpId claim.")
+	}
+
+	record, err := form.dao.FindAuthRecordByToken(token, form.app.Settings().RecordOTPToken.Secret)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	otp, err := form.findOTP(otpId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if otp.RecordId != record.Id {
+		return nil, nil, errors.New("The token otp doesn't belong to the token auth record.")
+	}
+
+	return record, otp, nil
+}
+
+// findOTP returns the non-expired form collection OTP model with the specified id.
+func (form *RecordOTPLogin) findOTP(id string) (*models.OTP, error) {
+	otp, err := form.dao.FindOTPById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if otp.CollectionId != form.collection.Id {
+		return nil, errors.New("The otp doesn't belong to the form collection.")
+	}
+
+	if otp.HasExpired(time.Duration(form.app.Settings().RecordOTPToken.Duration) * time.Second) {
+		return nil, errors.New("The otp has expired.")
+	}
+
+	return otp, nil
+}
+
+// Submit validates and submits the form.
+// On success returns the authorized record model.
+//
+// On successful login all pending OTP models of the auth record are deleted
+// and the auth record is marked as verified (if the OTP was sent to its current email).
+//
+// You can optionally provide a list of InterceptorFunc to
+// further modify the form behavior before persisting it.
+func (form *RecordOTPLogin) Submit(interceptors ...InterceptorFunc[*RecordOTPLoginData]) (*models.Record, error) {
+	if err := form.Validate(); err != nil {
+		return nil, err
+	}
+
+	data := &RecordOTPLoginData{}
+
+	if form.Token != "" {
+		record, otp, err := form.parseToken(form.Token)
+		if err != nil {
+			return nil, err
+		}
+		data.Record = record
+		data.OTP = otp
+	} else {
+		otp, err := form.findOTP(form.OtpId)
+		if err != nil {
+			return nil, err
+		}
+		data.OTP = otp
+
+		record, err := form.dao.FindRecordById(form.collection.Id, otp.RecordId)
+		if err != nil {
+			return nil, err
+		}
+		data.Record = record
+	}
+
+	interceptorsErr := runInterceptors(data, func(d *RecordOTPLoginData) error {
+		data = d
+
+		if d.Record == nil || d.OTP == nil {
+			return errors.New("Missing auth record or otp data.")
+		}
+
+		// the magic link token was already verified
+		if form.Token == "" {
+			if err := form.verifyPassword(d.OTP); err != nil {
+				return err
+			}
+		}
+
+		return form.dao.RunInTransaction(func(txDao *daos.Dao) error {
+			if err := txDao.DeleteAllOTPsByRecord(d.Record); err != nil {
+				return err
+			}
+
+			// the one-time password was delivered to the record email
+			if !d.Record.Verified() && d.Record.Email() == d.OTP.SentTo {
+				d.Record.SetVerified(true)
+
+				return txDao.SaveRecord(d.Record)
+			}
+
+			return nil
+		})
+	}, interceptors...)
+
+	if interceptorsErr != nil {
+		return nil, interceptorsErr
+	}
+
+	return data.Record, nil
+}
+
+// verifyPassword checks the submitted password against the provided OTP model.
+//
+// Each verification atomically increments the OTP attempts counter
+// (before the password check so that parallel requests can't exceed
+// [OTPMaxAttempts]) and the OTP model is deleted once the limit is reached.
+func (form *RecordOTPLogin) verifyPassword(otp *models.OTP) error {
+	invalidPasswordErr := validation.Errors{
+		"password": validation.NewError("validation_invalid_otp_password", "Invalid or expired one-time password."),
+	}
+
+	attempts, err := form.dao.IncrementOTPAttempts(otp, OTPMaxAttempts)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return invalidPasswordErr
+		}
+		return err
+	}
+
+	if otp.ValidatePassword(form.Password) {
+		return nil
+	}
+
+	if attempts >= OTPMaxAttempts {
+		if err := form.dao.DeleteOTP(otp); err != nil {
+			return err
+		}
+	}
+
+	return invalidPasswordErr
+}
@@ -0,0 +1,276 @@
+package forms_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tokens"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestRecordOTPLoginValidateAndSubmit(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, err := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherRecord, err := app.Dao().FindAuthRecordByEmail("users", "test2@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clientsRecord, err := app.Dao().FindAuthRecordByEmail("clients", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name        string
+		prepare     func() *forms.RecordOTPLogin
+		expectError bool
+	}{
+		{
+			"empty data",
+			func() *forms.RecordOTPLogin {
+				return forms.NewRecordOTPLogin(app, record.Collection())
+			},
+			true,
+		},
+		{
+			"missing otp",
+			func() *forms.RecordOTPLogin {
+				form := forms.NewRecordOTPLogin(app, record.Collection())
+				form.OtpId = "missing"
+				form.Password = "123456"
+				return form
+			},
+			true,
+		},
+		{
+			"otp from different collection",
+			func() *forms.RecordOTPLogin {
+				otp := createTestOTP(t, app, clientsRecord, "123456")
+				form := forms.NewRecordOTPLogin(app, record.Collection())
+				form.OtpId = otp.Id
+				form.Password = "123456"
+				return form
+			},
+			true,
+		},
+		{
+			"expired otp",
+			func() *forms.RecordOTPLogin {
+				otp := createTestOTP(t, app, record, "123456")
+				expireTestOTP(t, app, otp)
+				form := forms.NewRecordOTPLogin(app, record.Collection())
+				form.OtpId = otp.Id
+				form.Password = "123456"
+				return form
+			},
+			true,
+		},
+		{
+			"invalid password",
+			func() *forms.RecordOTPLogin {
+				otp := createTestOTP(t, app, record, "123456")
+				form := forms.NewRecordOTPLogin(app, record.Collection())
+				form.OtpId = otp.Id
+				form.Password = "654321"
+				return form
+			},
+			true,
+		},
+		{
+			"auth token instead of otp token",
+			func() *forms.RecordOTPLogin {
+				form := forms.NewRecordOTPLogin(app, record.Collection())
+				form.Token, _ = tokens.NewRecordAuthToken(app, record)
+				return form
+			},
+			true,
+		},
+		{
+			"otp token with otp of another record",
+			func() *forms.RecordOTPLogin {
+				otp := createTestOTP(t, app, otherRecord, "123456")
+				form := forms.NewRecordOTPLogin(app, record.Collection())
+				form.Token, _ = tokens.NewRecordOTPToken(app, record, otp.Id)
+				return form
+			},
+			true,
+		},
+		{
+			"valid password",
+			func() *forms.RecordOTPLogin {
+				otp := createTestOTP(t, app, record, "123456")
+				form := forms.NewRecordOTPLogin(app, record.Collection())
+				form.OtpId = otp.Id
+				form.Password = "123456"
+				return form
+			},
+			false,
+		},
+		{
+			"valid token",
+			func() *forms.RecordOTPLogin {
+				otp := createTestOTP(t, app, record, "123456")
+				form := forms.NewRecordOTPLogin(app, record.Collection())
+				form.Token, _ = tokens.NewRecordOTPToken(app, record, otp.Id)
+				return form
+			},
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		form := s.prepare()
+
+		interceptorCalls := 0
+		interceptor := func(next forms.InterceptorNextFunc[*forms.RecordOTPLoginData]) forms.InterceptorNextFunc[*forms.RecordOTPLoginData] {
+			return func(data *forms.RecordOTPLoginData) error {
+				interceptorCalls++
+				return next(data)
+			}
+		}
+
+		result, err := form.Submit(interceptor)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		if interceptorCalls != 1 {
+			t.Errorf("[%s] Expected interceptor to be called once, got %d", s.name, interceptorCalls)
+		}
+
+		if result.Id != record.Id {
+			t.Errorf("[%s] Expected record %q, got %v", s.name, record.Id, result)
+		}
+
+		if !result.Verified() {
+			t.Errorf("[%s] Expected the record to be marked as verified", s.name)
+		}
+
+		total := 0
+		app.Dao().OTPQuery().
+			Select("count(*)").
+			AndWhere(dbx.HashExp{"recordId": record.Id}).
+			Row(&total)
+		if total != 0 {
+			t.Errorf("[%s] Expected all record otps to be deleted, found %d", s.name, total)
+		}
+	}
+}
+
+func TestRecordOTPLoginMaxAttempts(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, err := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otp := createTestOTP(t, app, record, "123456")
+
+	for i := 1; i <= forms.OTPMaxAttempts; i++ {
+		form := forms.NewRecordOTPLogin(app, record.Collection())
+		form.OtpId = otp.Id
+		form.Password = "000000"
+
+		if _, err := form.Submit(); err == nil {
+			t.Fatalf("(%d) Expected invalid password error", i)
+		}
+
+		updated, err := app.Dao().FindOTPById(otp.Id)
+		if i == forms.OTPMaxAttempts {
+			if err == nil {
+				t.Fatalf("(%d) Expected the otp to be deleted after the max attempts", i)
+			}
+		} else if err != nil || updated.Attempts != i {
+			t.Fatalf("(%d) Expected %d failed attempts, got %v (%v)", i, i, updated, err)
+		}
+	}
+
+	// the correct password shouldn't work anymore
+	form := forms.NewRecordOTPLogin(app, record.Collection())
+	form.OtpId = otp.Id
+	form.Password = "123456"
+	if _, err := form.Submit(); err == nil {
+		t.Fatal("Expected error for the invalidated otp")
+	}
+}
+
+func TestRecordOTPLoginParallelAttempts(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, err := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otp := createTestOTP(t, app, record, "123456")
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 3*forms.OTPMaxAttempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			form := forms.NewRecordOTPLogin(app, record.Collection())
+			form.OtpId = otp.Id
+			form.Password = "000000"
+			form.Submit()
+		}()
+	}
+
+	wg.Wait()
+
+	if _, err := app.Dao().FindOTPById(otp.Id); err == nil {
+		t.Fatal("Expected the otp to be deleted after the max attempts")
+	}
+}
+
+func createTestOTP(t *testing.T, app *tests.TestApp, record *models.Record, password string) *models.OTP {
+	otp := &models.OTP{
+		CollectionId: record.Collection().Id,
+		RecordId:     record.Id,
+		SentTo:       record.Email(),
+	}
+	otp.SetPassword(password)
+
+	if err := app.Dao().SaveOTP(otp); err != nil {
+		t.Fatal(err)
+	}
+
+	return otp
+}
+
+func expireTestOTP(t *testing.T, app *tests.TestApp, otp *models.OTP) {
+	duration := time.Duration(app.Settings().RecordOTPToken.Duration+1) * time.Second
+
+	_, err := app.Dao().DB().Update(
+		otp.TableName(),
+		dbx.Params{"created": types.NowDateTime().Time().Add(-duration).Format(types.DefaultDateLayout)},
+		dbx.HashExp{"id": otp.Id},
+	).Execute()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,119 @@
+package forms
+
+import (
+	"errors"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/mails"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+// OTPPasswordLength is the length of the generated numeric one-time passwords.
+const OTPPasswordLength = 6
+
+// RecordOTPRequestData defines the RecordOTPRequest form submit data.
+type RecordOTPRequestData struct {
+	Record *models.Record
+	OTP    *models.OTP
+}
+
+// RecordOTPRequest is an auth record one-time password request form.
+type RecordOTPRequest struct {
+	app        core.App
+	dao        *daos.Dao
+	collection *models.Collection
+
+	Email string `form:"email" json:"email"`
+}
+
+// NewRecordOTPRequest creates a new [RecordOTPRequest]
+// form initialized with from the provided [core.App] instance.
+//
+// If you want to submit the form as part of a transaction,
+// you can change the default Dao via [SetDao()].
+func NewRecordOTPRequest(app core.App, collection *models.Collection) *RecordOTPRequest {
+	return &RecordOTPRequest{
+		app:        app,
+		dao:        app.Dao(),
+		collection: collection,
+	}
+}
+
+// SetDao replaces the default form Dao instance with the provided one.
+func (form *RecordOTPRequest) SetDao(dao *daos.Dao) {
+	form.dao = dao
+}
+
+// Validate makes the form validatable by implementing [validation.Validatable] interface.
+//
+// This method doesn't checks whether auth record with `form.Email` exists (this is done on Submit).
+func (form *RecordOTPRequest) Validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(
+			&form.Email,
+			validation.Required,
+			validation.Length(1, 255),
+			is.EmailFormat,
+		),
+	)
+}
+
+// Submit validates and submits the form.
+// On success, creates a new OTP model and sends its
+// one-time password to the `form.Email` auth record.
+//
+// Note that the returned OTP model id is generated before
+// the interceptors call, so it is available even if the
+// actual OTP persistence and email send are delayed.
+//
+// You can optionally provide a list of InterceptorFunc to further
+// modify the form behavior before persisting it.
+func (form *RecordOTPRequest) Submit(interceptors ...InterceptorFunc[*RecordOTPRequestData]) (*models.OTP, error) {
+	if err := form.Validate(); err != nil {
+		return nil, err
+	}
+
+	authRecord, err := form.dao.FindAuthRecordByEmail(form.collection.Id, form.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	password := security.RandomStringWithAlphabet(OTPPasswordLength, "0123456789")
+
+	otp := &models.OTP{
+		CollectionId: authRecord.Collection().Id,
+		RecordId:     authRecord.Id,
+		SentTo:       authRecord.Email(),
+	}
+	otp.RefreshId()
+	otp.SetPassword(password)
+
+	data := &RecordOTPRequestData{
+		Record: authRecord,
+		OTP:    otp,
+	}
+
+	interceptorsErr := runInterceptors(data, func(d *RecordOTPRequestData) error {
+		data = d
+
+		if d.Record == nil || d.OTP == nil {
+			return errors.New("Missing auth record or otp data.")
+		}
+
+		if err := form.dao.SaveOTP(d.OTP); err != nil {
+			return err
+		}
+
+		return mails.SendRecordOTP(form.app, d.Record, d.OTP, password)
+	}, interceptors...)
+
+	if interceptorsErr != nil {
+		return nil, interceptorsErr
+	}
+
+	return data.OTP, nil
+}
@@ -0,0 +1,134 @@
+package forms_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestRecordOTPRequestValidateAndSubmit(t *testing.T) {
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	authCollection, err := testApp.Dao().FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name        string
+		email       string
+		expectError bool
+	}{
+		{"empty email", "", true},
+		{"invalid email format", "invalid", true},
+		{"missing auth record", "missing@example.com", true},
+		{"existing auth record", "test@example.com", false},
+		{"existing auth record (new request)", "test@example.com", false},
+	}
+
+	for _, s := range scenarios {
+		testApp.TestMailer.TotalSend = 0 // reset
+
+		form := forms.NewRecordOTPRequest(testApp, authCollection)
+		form.Email = s.email
+
+		interceptorCalls := 0
+		interceptor := func(next forms.InterceptorNextFunc[*forms.RecordOTPRequestData]) forms.InterceptorNextFunc[*forms.RecordOTPRequestData] {
+			return func(data *forms.RecordOTPRequestData) error {
+				interceptorCalls++
+				return next(data)
+			}
+		}
+
+		otp, err := form.Submit(interceptor)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		expectInterceptorCalls := 1
+		if s.expectError {
+			expectInterceptorCalls = 0
+		}
+		if interceptorCalls != expectInterceptorCalls {
+			t.Errorf("[%s] Expected interceptor to be called %d, got %d", s.name, expectInterceptorCalls, interceptorCalls)
+		}
+
+		if hasErr {
+			continue
+		}
+
+		if testApp.TestMailer.TotalSend != 1 {
+			t.Errorf("[%s] Expected 1 sent email, got %d", s.name, testApp.TestMailer.TotalSend)
+		}
+
+		saved, err := testApp.Dao().FindOTPById(otp.Id)
+		if err != nil {
+			t.Errorf("[%s] Expected the otp to be persisted, got %v", s.name, err)
+			continue
+		}
+
+		if saved.SentTo != s.email || saved.CollectionId != authCollection.Id || saved.Password == "" {
+			t.Errorf("[%s] Unexpected otp model %v", s.name, saved)
+		}
+
+		// the plain password shouldn't be stored
+		if len(saved.Password) == forms.OTPPasswordLength {
+			t.Errorf("[%s] Expected the otp password to be hashed, got %q", s.name, saved.Password)
+		}
+
+	}
+}
+
+func TestRecordOTPRequestInterceptors(t *testing.T) {
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	authCollection, err := testApp.Dao().FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := forms.NewRecordOTPRequest(testApp, authCollection)
+	form.Email = "test@example.com"
+
+	testErr := errors.New("test_error")
+
+	interceptor1Called := false
+	interceptor1 := func(next forms.InterceptorNextFunc[*forms.RecordOTPRequestData]) forms.InterceptorNextFunc[*forms.RecordOTPRequestData] {
+		return func(data *forms.RecordOTPRequestData) error {
+			interceptor1Called = true
+			return next(data)
+		}
+	}
+
+	interceptor2Called := false
+	interceptor2 := func(next forms.InterceptorNextFunc[*forms.RecordOTPRequestData]) forms.InterceptorNextFunc[*forms.RecordOTPRequestData] {
+		return func(data *forms.RecordOTPRequestData) error {
+			interceptor2Called = true
+			return testErr
+		}
+	}
+
+	_, submitErr := form.Submit(interceptor1, interceptor2)
+	if submitErr != testErr {
+		t.Fatalf("Expected submitError %v, got %v", testErr, submitErr)
+	}
+
+	if !interceptor1Called {
+		t.Fatalf("Expected interceptor1 to be called")
+	}
+
+	if !interceptor2Called {
+		t.Fatalf("Expected interceptor2 to be called")
+	}
+
+	if testApp.TestMailer.TotalSend != 0 {
+		t.Fatalf("Expected no sent emails, got %d", testApp.TestMailer.TotalSend)
+	}
+}
@@ -0,0 +1,191 @@
+package forms
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+)
+
+// RecordUploadCreate is a resumable record file upload session create form.
+type RecordUploadCreate struct {
+	app        core.App
+	dao        *daos.Dao
+	record     *models.Record
+	admin      *models.Admin
+	authRecord *models.Record
+
+	Field    string `form:"field" json:"field"`
+	Filename string `form:"filename" json:"filename"`
+	Size     int64  `form:"size" json:"size"`
+}
+
+// NewRecordUploadCreate creates a new [RecordUploadCreate] form
+// initialized with from the provided [core.App] and [models.Record] instances.
+//
+// If you want to submit the form as part of a transaction,
+// you can change the default Dao via [SetDao()].
+func NewRecordUploadCreate(app core.App, record *models.Record) *RecordUploadCreate {
+	return &RecordUploadCreate{
+		app:    app,
+		dao:    app.Dao(),
+		record: record,
+	}
+}
+
+// SetDao replaces the default form Dao instance with the provided one.
+func (form *RecordUploadCreate) SetDao(dao *daos.Dao) {
+	form.dao = dao
+}
+
+// SetOwner sets the admin or auth record that will own
+// the created upload session (both nil means guest).
+func (form *RecordUploadCreate) SetOwner(admin *models.Admin, authRecord *models.Record) {
+	form.admin = admin
+	form.authRecord = authRecord
+}
+
+// Validate makes the form validatable by implementing [validation.Validatable] interface.
+func (form *RecordUploadCreate) Validate() error {
+	var maxSize int64
+
+	options := form.fileOptions()
+	if options != nil {
+		maxSize = int64(options.MaxSize)
+	}
+
+	return validation.ValidateStruct(form,
+		validation.Field(&form.Field, validation.Required, validation.By(form.checkField)),
+		validation.Field(&form.Filename, validation.Required, validation.Length(1, 255), validation.By(checkUploadFilename)),
+		validation.Field(
+			&form.Size,
+			validation.Required,
+			validation.Min(int64(1)),
+			validation.When(options != nil, validation.Max(maxSize)),
+		),
+	)
+}
+
+func (form *RecordUploadCreate) fileOptions() *schema.FileOptions {
+	field := form.record.Collection().Schema.GetFieldByName(form.Field)
+	if field == nil || field.Type != schema.FieldTypeFile {
+		return nil
+	}
+
+	options, _ := field.Options.(*schema.FileOptions)
+
+	return options
+}
+
+func (form *RecordUploadCreate) checkField(value any) error {
+	if form.fileOptions() == nil {
+		return validation.NewError("validation_invalid_file_field", "The field must be a valid collection file field.")
+	}
+
+	return nil
+}
+
+func checkUploadFilename(value any) error {
+	v, _ := value.(string)
+
+	if v == "." || v == ".." || strings.ContainsAny(v, `/\`) {
+		return validation.NewError("validation_invalid_filename", "Invalid filename.")
+	}
+
+	return nil
+}
+
+// checkUploadOwnerLimits checks whether the upload owner could
+// create one more upload session with the specified size.
+func checkUploadOwnerLimits(dao *daos.Dao, upload *models.Upload) error {
+	count, total, err := dao.FindUploadsTotalsByOwner(upload.OwnerCollectionId, upload.OwnerId)
+	if err != nil {
+		return err
+	}
+
+	if count >= core.UploadMaxSessionsPerOwner {
+		return validation.Errors{"field": validation.NewError(
+			"validation_upload_sessions_limit",
+			"The max number of unfinished uploads was reached.",
+		)}
+	}
+
+	if total+upload.Size > core.UploadMaxBytesPerOwner {
+		return validation.Errors{"size": validation.NewError(
+			"validation_upload_size_limit",
+			"The max total size of the unfinished uploads was reached.",
+		)}
+	}
+
+	return nil
+}
+
+// Submit validates and submits the form.
+// On success, creates a new empty Upload model and its local file.
+//
+// You can optionally provide a list of InterceptorFunc to further
+// modify the form behavior before persisting it.
+func (form *RecordUploadCreate) Submit(interceptors ...InterceptorFunc[*models.Upload]) (*models.Upload, error) {
+	if err := form.Validate(); err != nil {
+		return nil, err
+	}
+
+	upload := &models.Upload{
+		CollectionId: form.record.Collection().Id,
+		RecordId:     form.record.Id,
+		Field:        form.Field,
+		Filename:     form.Filename,
+		Size:         form.Size,
+	}
+	upload.RefreshId()
+	upload.SetOwner(form.admin, form.authRecord)
+
+	interceptorsErr := runInterceptors(upload, func(m *models.Upload) error {
+		upload = m
+
+		if upload.Offset != 0 {
+			return errors.New("A new upload must start from 0 offset.")
+		}
+
+		dir := core.LocalUploadDir(form.app, upload.Id)
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return err
+		}
+
+		f, err := os.Create(filepath.Join(dir, upload.Filename))
+		if err != nil {
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+
+		// the owner limits are checked in the same transaction
+		// to prevent exceeding them with concurrent requests
+		err = form.dao.RunInTransaction(func(txDao *daos.Dao) error {
+			if err := checkUploadOwnerLimits(txDao, upload); err != nil {
+				return err
+			}
+
+			return txDao.SaveUpload(upload)
+		})
+		if err != nil {
+			os.RemoveAll(dir)
+			return err
+		}
+
+		return nil
+	}, interceptors...)
+
+	if interceptorsErr != nil {
+		return nil, interceptorsErr
+	}
+
+	return upload, nil
+}
@@ -0,0 +1,194 @@
+package forms_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestRecordUploadCreateValidateAndSubmit(t *testing.T) {
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	record, err := testApp.Dao().FindRecordById("users", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name           string
+		data           string
+		expectedErrors []string
+	}{
+		{
+			"empty data",
+			`{}`,
+			[]string{"field", "filename", "size"},
+		},
+		{
+			"non-file field and invalid filename",
+			`{"field":"name","filename":"../test.txt","size":10}`,
+			[]string{"field", "filename"},
+		},
+		{
+			"missing field and dot filename",
+			`{"field":"missing","filename":"..","size":10}`,
+			[]string{"field", "filename"},
+		},
+		{
+			"size bigger than the field max size",
+			`{"field":"file","filename":"test.txt","size":5242881}`,
+			[]string{"size"},
+		},
+		{
+			"valid data",
+			`{"field":"file","filename":"test.txt","size":5242880}`,
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		form := forms.NewRecordUploadCreate(testApp, record)
+		form.SetOwner(nil, record)
+
+		// load data
+		loadErr := json.Unmarshal([]byte(s.data), form)
+		if loadErr != nil {
+			t.Errorf("[%s] Failed to load form data: %v", s.name, loadErr)
+			continue
+		}
+
+		interceptorCalls := 0
+		interceptor := func(next forms.InterceptorNextFunc[*models.Upload]) forms.InterceptorNextFunc[*models.Upload] {
+			return func(m *models.Upload) error {
+				interceptorCalls++
+				return next(m)
+			}
+		}
+
+		upload, err := form.Submit(interceptor)
+
+		// parse errors
+		errs, ok := err.(validation.Errors)
+		if !ok && err != nil {
+			t.Errorf("[%s] Failed to parse errors %v", s.name, err)
+			continue
+		}
+
+		// check errors
+		if len(errs) > len(s.expectedErrors) {
+			t.Errorf("[%s] Expected error keys %v, got %v", s.name, s.expectedErrors, errs)
+		}
+		for _, k := range s.expectedErrors {
+			if _, ok := errs[k]; !ok {
+				t.Errorf("[%s] Missing expected error key %q in %v", s.name, k, errs)
+			}
+		}
+
+		expectInterceptorCalls := 1
+		if len(s.expectedErrors) > 0 {
+			expectInterceptorCalls = 0
+		}
+		if interceptorCalls != expectInterceptorCalls {
+			t.Errorf("[%s] Expected interceptor to be called %d, got %d", s.name, expectInterceptorCalls, interceptorCalls)
+		}
+
+		if len(s.expectedErrors) > 0 {
+			continue
+		}
+
+		saved, err := testApp.Dao().FindUploadById(upload.Id)
+		if err != nil {
+			t.Errorf("[%s] Expected the upload to be persisted, got %v", s.name, err)
+			continue
+		}
+
+		if saved.CollectionId != record.Collection().Id ||
+			saved.RecordId != record.Id ||
+			saved.Field != form.Field ||
+			saved.Filename != form.Filename ||
+			saved.Size != form.Size ||
+			saved.Offset != 0 ||
+			!saved.IsOwner(nil, record) {
+			t.Errorf("[%s] Unexpected upload model %v", s.name, saved)
+		}
+
+		info, err := os.Stat(filepath.Join(core.LocalUploadDir(testApp, upload.Id), upload.Filename))
+		if err != nil {
+			t.Errorf("[%s] Expected the upload file to be created, got %v", s.name, err)
+		} else if info.Size() != 0 {
+			t.Errorf("[%s] Expected empty upload file, got %d bytes", s.name, info.Size())
+		}
+	}
+}
+
+func TestRecordUploadCreateOwnerLimits(t *testing.T) {
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	oldMaxSessions := core.UploadMaxSessionsPerOwner
+	oldMaxBytes := core.UploadMaxBytesPerOwner
+	defer func() {
+		core.UploadMaxSessionsPerOwner = oldMaxSessions
+		core.UploadMaxBytesPerOwner = oldMaxBytes
+	}()
+	core.UploadMaxSessionsPerOwner = 2
+	core.UploadMaxBytesPerOwner = 100
+
+	record, err := testApp.Dao().FindRecordById("users", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherRecord, err := testApp.Dao().FindRecordById("users", "oap640cot4yru2s")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name          string
+		owner         *models.Record
+		size          int64
+		expectedError string
+	}{
+		{"first upload", record, 60, ""},
+		{"exceeding the max total size", record, 41, "size"},
+		{"within the max total size", record, 40, ""},
+		{"exceeding the max sessions", record, 1, "field"},
+		{"another owner", otherRecord, 100, ""},
+		{"guest", nil, 100, ""},
+	}
+
+	for _, s := range scenarios {
+		form := forms.NewRecordUploadCreate(testApp, record)
+		form.SetOwner(nil, s.owner)
+		form.Field = "file"
+		form.Filename = "test.txt"
+		form.Size = s.size
+
+		_, err := form.Submit()
+
+		if s.expectedError == "" {
+			if err != nil {
+				t.Errorf("[%s] Expected nil error, got %v", s.name, err)
+			}
+			continue
+		}
+
+		errs, ok := err.(validation.Errors)
+		if !ok {
+			t.Errorf("[%s] Expected validation errors, got %v", s.name, err)
+			continue
+		}
+		if _, ok := errs[s.expectedError]; !ok || len(errs) != 1 {
+			t.Errorf("[%s] Expected only %q error, got %v", s.name, s.expectedError, errs)
+		}
+	}
+}
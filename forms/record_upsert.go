@@ -689,16 +689,15 @@ func (form *RecordUpsert) DrySubmit(callback func(txDao *daos.Dao) error) error
 		return err
 	}
 
-	var dryDao *daos.Dao
-	if form.dao.ConcurrentDB() == form.dao.NonconcurrentDB() {
-		// it is already in a transaction and therefore use the app concurrent db pool
-		// to prevent "transaction has already been committed or rolled back" error
-		dryDao = daos.New(form.app.Dao().ConcurrentDB())
-	} else {
-		// otherwise use the form noncurrent dao db pool
-		dryDao = daos.New(form.dao.NonconcurrentDB())
+	// it is already in a transaction and therefore use a savepoint
+	// so that the dry changes are visible only within the current transaction
+	// (and to prevent "transaction has already been committed or rolled back" error)
+	if tx, ok := form.dao.NonconcurrentDB().(*dbx.Tx); ok {
+		return form.drySubmitWithSavepoint(tx, isNew, callback)
 	}
 
+	dryDao := daos.New(form.dao.NonconcurrentDB())
+
 	return dryDao.RunInTransaction(func(txDao *daos.Dao) error {
 		tx, ok := txDao.DB().(*dbx.Tx)
 		if !ok {
@@ -723,6 +722,35 @@ func (form *RecordUpsert) DrySubmit(callback func(txDao *daos.Dao) error) error
 	})
 }
 
+// drySubmitWithSavepoint performs the dry submit within a savepoint of
+// the provided (already started) transaction and reverts it.
+func (form *RecordUpsert) drySubmitWithSavepoint(tx *dbx.Tx, isNew bool, callback func(txDao *daos.Dao) error) error {
+	if _, err := tx.NewQuery("SAVEPOINT dry_submit").Execute(); err != nil {
+		return err
+	}
+	defer func() {
+		tx.NewQuery("ROLLBACK TO SAVEPOINT dry_submit").Execute()
+		tx.NewQuery("RELEASE SAVEPOINT dry_submit").Execute()
+	}()
+
+	txDao := daos.New(tx)
+
+	if err := txDao.SaveRecord(form.record); err != nil {
+		return err
+	}
+
+	// restore record isNew state
+	if isNew {
+		form.record.MarkAsNew()
+	}
+
+	if callback != nil {
+		return callback(txDao)
+	}
+
+	return nil
+}
+
 // Submit validates the form and upserts the form Record model.
 //
 // You can optionally provide a list of InterceptorFunc to further
@@ -740,6 +768,11 @@ func (form *RecordUpsert) Submit(interceptors ...InterceptorFunc[*models.Record]
 			form.record.MarkAsNew()
 		}
 
+		// inspect the new files before persisting anything
+		if err := form.inspectFilesToUpload(); err != nil {
+			return err
+		}
+
 		// persist the record model
 		if saveErr := form.dao.SaveRecord(form.record); saveErr != nil {
 			return fmt.Errorf("failed to save the record: %w", saveErr)
@@ -762,6 +795,54 @@ func (form *RecordUpsert) Submit(interceptors ...InterceptorFunc[*models.Record]
 	}, interceptors...)
 }
 
+// inspectFilesToUpload triggers the [core.App.OnFileBeforeUpload] hook
+// for each new file and returns the rejected files as validation errors.
+func (form *RecordUpsert) inspectFilesToUpload() error {
+	if len(form.filesToUpload) == 0 {
+		return nil // no parsed file fields
+	}
+
+	errs := validation.Errors{}
+
+	for fieldKey, files := range form.filesToUpload {
+		field := form.record.Collection().Schema.GetFieldByName(fieldKey)
+
+		for _, file := range files {
+			event := new(core.FileUploadEvent)
+			event.Collection = form.record.Collection()
+			event.Record = form.record
+			event.FileField = field
+			event.File = file
+
+			err := form.app.OnFileBeforeUpload().Trigger(event)
+			if err == nil {
+				continue
+			}
+
+			if form.app.IsDebug() {
+				log.Println(err)
+			}
+
+			if errObj, ok := err.(validation.Error); ok {
+				errs[fieldKey] = errObj
+			} else {
+				errs[fieldKey] = validation.NewError(
+					"validation_file_rejected",
+					fmt.Sprintf("Failed to upload %q - the file was rejected.", file.OriginalName),
+				)
+			}
+
+			break // report only the first rejected file per field
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
 func (form *RecordUpsert) processFilesToUpload() error {
 	if len(form.filesToUpload) == 0 {
 		return nil // no parsed file fields
@@ -778,14 +859,13 @@ func (form *RecordUpsert) processFilesToUpload() error {
 	defer fs.Close()
 
 	var uploadErrors []error // list of upload errors
-	var uploaded []string    // list of uploaded file paths
+	var uploaded []string    // list of uploaded file names
 
 	for fieldKey := range form.filesToUpload {
 		for i, file := range form.filesToUpload[fieldKey] {
-			path := form.record.BaseFilesPath() + "/" + file.Name
-			if err := fs.UploadFile(file, path); err == nil {
+			if err := core.UploadRecordFile(form.app, form.dao, fs, form.record, file); err == nil {
 				// keep track of the already uploaded file
-				uploaded = append(uploaded, path)
+				uploaded = append(uploaded, file.Name)
 			} else {
 				// store the upload error
 				uploadErrors = append(uploadErrors, fmt.Errorf("file %d: %v", i, err))
@@ -829,9 +909,7 @@ func (form *RecordUpsert) deleteFilesByNamesList(filenames []string) ([]string,
 
 	for i := len(filenames) - 1; i >= 0; i-- {
 		filename := filenames[i]
-		path := form.record.BaseFilesPath() + "/" + filename
-
-		if err := fs.Delete(path); err == nil {
+		if err := core.DeleteRecordFile(form.app, form.dao, fs, form.record, filename); err == nil {
 			// remove the deleted file from the list
 			filenames = append(filenames[:i], filenames[i+1:]...)
 
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -11,6 +12,7 @@ import (
 	"strings"
 	"testing"
 
+	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/labstack/echo/v5"
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/daos"
@@ -1037,3 +1039,99 @@ func TestRecordUpsertAddAndRemoveFiles(t *testing.T) {
 		t.Fatalf("Expected file_many to be 5, got %v", fileMany)
 	}
 }
+
+func TestRecordUpsertFileBeforeUploadHook(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	// stub scanner that rejects the files with "infected" content
+	inspected := []string{}
+	app.OnFileBeforeUpload().Add(func(e *core.FileUploadEvent) error {
+		inspected = append(inspected, e.Collection.Name+"."+e.FileField.Name+":"+e.File.OriginalName)
+
+		r, err := e.File.Reader.Open()
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+
+		content, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+
+		if string(content) == "infected" {
+			return errors.New("infected")
+		}
+
+		return nil
+	})
+
+	record, err := app.Dao().FindRecordById("demo1", "84nmscqy84lsi1t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldFileMany := record.GetStringSlice("file_many")
+
+	clean, err := filesystem.NewFileFromBytes([]byte("clean"), "clean.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	infected, err := filesystem.NewFileFromBytes([]byte("infected"), "infected.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// rejected
+	form := forms.NewRecordUpsert(app, record)
+	form.AddFiles("file_many", clean, infected)
+
+	submitErr := form.Submit()
+
+	errs, ok := submitErr.(validation.Errors)
+	if !ok {
+		t.Fatalf("Expected validation.Errors, got %v", submitErr)
+	}
+	if errObj, ok := errs["file_many"].(validation.Error); !ok || errObj.Code() != "validation_file_rejected" {
+		t.Fatalf("Expected file_many validation_file_rejected error, got %v", errs)
+	}
+
+	expectedInspected := []string{"demo1.file_many:clean.txt", "demo1.file_many:infected.txt"}
+	if len(inspected) != len(expectedInspected) {
+		t.Fatalf("Expected inspected files %v, got %v", expectedInspected, inspected)
+	}
+	for _, v := range expectedInspected {
+		if !list.ExistInSlice(v, inspected) {
+			t.Fatalf("Missing inspected file %q in %v", v, inspected)
+		}
+	}
+
+	recordAfter, err := app.Dao().FindRecordById("demo1", "84nmscqy84lsi1t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := recordAfter.GetStringSlice("file_many"); len(v) != len(oldFileMany) {
+		t.Fatalf("Expected the record to remain unchanged, got file_many %v", v)
+	}
+	if hasRecordFile(app, recordAfter, clean.Name) || hasRecordFile(app, recordAfter, infected.Name) {
+		t.Fatal("Expected the new files to not be uploaded")
+	}
+
+	// accepted
+	inspected = nil
+	form = forms.NewRecordUpsert(app, recordAfter)
+	form.AddFiles("file_many", clean)
+
+	if err := form.Submit(); err != nil {
+		t.Fatalf("Expected the clean file to be accepted, got %v", err)
+	}
+
+	if len(inspected) != 1 {
+		t.Fatalf("Expected 1 inspected file, got %v", inspected)
+	}
+
+	if !hasRecordFile(app, recordAfter, clean.Name) {
+		t.Fatal("Expected the clean file to be uploaded")
+	}
+}
@@ -2,7 +2,6 @@ package forms
 
 import (
 	"os"
-	"time"
 
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/daos"
@@ -63,16 +62,6 @@ func (form *SettingsUpsert) Submit(interceptors ...InterceptorFunc[*settings.Set
 			return err
 		}
 
-		// explicitly trigger old logs deletion
-		form.app.LogsDao().DeleteOldRequests(
-			time.Now().AddDate(0, 0, -1*form.Settings.Logs.MaxDays),
-		)
-
-		if form.Settings.Logs.MaxDays == 0 {
-			// no logs are allowed -> reclaim preserved disk space after the previous delete operation
-			form.app.LogsDao().Vacuum()
-		}
-
 		// merge the application settings with the form ones
 		return form.app.Settings().Merge(form.Settings)
 	}, interceptors...)
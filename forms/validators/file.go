@@ -2,6 +2,8 @@ package validators
 
 import (
 	"fmt"
+	"mime"
+	"path/filepath"
 	"strings"
 
 	"github.com/gabriel-vasile/mimetype"
@@ -83,3 +85,57 @@ func UploadedFileMimeType(validTypes []string) validation.RuleFunc {
 		)
 	}
 }
+
+// UploadedFileExtension checks whether the validated `rest.UploadedFile`
+// name extension matches with its actual content type (aka. magic bytes).
+//
+// Files with unknown extension or with generic content
+// (plain text or unrecognized binary data) are always considered valid.
+//
+// Example:
+//
+//	validation.Field(&form.File, validation.By(validators.UploadedFileExtension()))
+func UploadedFileExtension() validation.RuleFunc {
+	return func(value any) error {
+		v, _ := value.(*filesystem.File)
+		if v == nil {
+			return nil // nothing to validate
+		}
+
+		ext := strings.ToLower(filepath.Ext(v.Name))
+
+		claimedType, _, _ := mime.ParseMediaType(mime.TypeByExtension(ext))
+		if claimedType == "" {
+			return nil // unknown extension
+		}
+
+		f, err := v.Reader.Open()
+		if err != nil {
+			return validation.NewError(
+				"validation_invalid_mime_type",
+				fmt.Sprintf("Failed to upload %q due to unsupported file type.", v.OriginalName),
+			)
+		}
+		defer f.Close()
+
+		filetype, err := mimetype.DetectReader(f)
+		if err != nil || filetype.Is("text/plain") || filetype.Is("application/octet-stream") {
+			return nil // inconclusive
+		}
+
+		for mt := filetype; mt != nil; mt = mt.Parent() {
+			if mt.Is(claimedType) || strings.EqualFold(mt.Extension(), ext) {
+				return nil // valid
+			}
+		}
+
+		return validation.NewError(
+			"validation_mime_type_mismatch",
+			fmt.Sprintf(
+				"%q extension doesn't match with its %s content.",
+				v.OriginalName,
+				filetype.String(),
+			),
+		)
+	}
+}
@@ -1,6 +1,9 @@
 package validators_test
 
 import (
+	"bytes"
+	"image"
+	"image/png"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -91,3 +94,45 @@ func TestUploadedFileMimeType(t *testing.T) {
 		}
 	}
 }
+
+func TestUploadedFileExtension(t *testing.T) {
+	pngBuf := new(bytes.Buffer)
+	if err := png.Encode(pngBuf, image.NewRGBA(image.Rect(0, 0, 1, 1))); err != nil {
+		t.Fatal(err)
+	}
+
+	htmlContent := []byte("<!DOCTYPE html><html><body><script>alert(1)</script></body></html>")
+
+	newFile := func(content []byte, name string) *filesystem.File {
+		f, err := filesystem.NewFileFromBytes(content, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+
+	scenarios := []struct {
+		name        string
+		file        *filesystem.File
+		expectError bool
+	}{
+		{"nil file", nil, false},
+		{"png content with png extension", newFile(pngBuf.Bytes(), "test.png"), false},
+		{"png content with uppercased png extension", newFile(pngBuf.Bytes(), "test.PNG"), false},
+		{"png content with unknown extension", newFile(pngBuf.Bytes(), "test.unknown"), false},
+		{"png content with html extension", newFile(pngBuf.Bytes(), "test.html"), true},
+		{"png content with gif extension", newFile(pngBuf.Bytes(), "test.gif"), true},
+		{"html content with png extension", newFile(htmlContent, "test.png"), true},
+		{"html content with html extension", newFile(htmlContent, "test.html"), false},
+		{"plain text content with png extension", newFile([]byte("test"), "test.png"), false},
+	}
+
+	for _, s := range scenarios {
+		err := validators.UploadedFileExtension()(s.file)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr to be %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+		}
+	}
+}
@@ -340,6 +340,11 @@ func (validator *RecordDataValidator) checkFileValue(field *schema.SchemaField,
 			return err
 		}
 
+		// check whether the file extension wasn't spoofed
+		if err := UploadedFileExtension()(file); err != nil {
+			return err
+		}
+
 		// check type
 		if len(options.MimeTypes) > 0 {
 			if err := UploadedFileMimeType(options.MimeTypes)(file); err != nil {
@@ -1065,6 +1065,14 @@ func TestRecordDataValidatorValidateFile(t *testing.T) {
 				MimeTypes: []string{"image/jpeg"},
 			},
 		},
+		&schema.SchemaField{
+			Name: "field4",
+			Type: schema.FieldTypeFile,
+			Options: &schema.FileOptions{
+				MaxSelect: 1,
+				MaxSize:   1000,
+			},
+		},
 	)
 	if err := app.Dao().SaveCollection(collection); err != nil {
 		t.Fatal(err)
@@ -1082,6 +1090,12 @@ func TestRecordDataValidatorValidateFile(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	// stub a file with spoofed extension
+	spoofedFile, err := filesystem.NewFileFromBytes([]byte("<!DOCTYPE html><html><body></body></html>"), "spoofed.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	scenarios := []testDataFieldScenario{
 		{
 			"check required constraint - nil",
@@ -1093,6 +1107,19 @@ func TestRecordDataValidatorValidateFile(t *testing.T) {
 			nil,
 			[]string{"field2"},
 		},
+		{
+			"check spoofed file extension",
+			map[string]any{
+				"field1": nil,
+				"field2": []string{"test1"},
+				"field3": nil,
+				"field4": spoofedFile.Name,
+			},
+			map[string][]*filesystem.File{
+				"field4": {spoofedFile},
+			},
+			[]string{"field4"},
+		},
 		{
 			"check MaxSelect constraint",
 			map[string]any{
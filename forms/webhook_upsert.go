@@ -0,0 +1,130 @@
+package forms
+
+import (
+	"strconv"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/forms/validators"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/list"
+)
+
+// WebhookUpsert is a [models.Webhook] upsert (create/update) form.
+type WebhookUpsert struct {
+	app     core.App
+	dao     *daos.Dao
+	webhook *models.Webhook
+
+	Id          string   `form:"id" json:"id"`
+	Name        string   `form:"name" json:"name"`
+	Url         string   `form:"url" json:"url"`
+	Collections []string `form:"collections" json:"collections"`
+	Actions     []string `form:"actions" json:"actions"`
+	Secret      string   `form:"secret" json:"secret"`
+	Disabled    bool     `form:"disabled" json:"disabled"`
+}
+
+// NewWebhookUpsert creates a new [WebhookUpsert] form with initializer
+// config created from the provided [core.App] and [models.Webhook] instances
+// (for create you could pass a pointer to an empty Webhook - `&models.Webhook{}`).
+//
+// If you want to submit the form as part of a transaction,
+// you can change the default Dao via [SetDao()].
+func NewWebhookUpsert(app core.App, webhook *models.Webhook) *WebhookUpsert {
+	form := &WebhookUpsert{
+		app:     app,
+		dao:     app.Dao(),
+		webhook: webhook,
+	}
+
+	// load defaults
+	form.Id = webhook.Id
+	form.Name = webhook.Name
+	form.Url = webhook.Url
+	form.Collections = list.ToUniqueStringSlice(webhook.Collections)
+	form.Actions = list.ToUniqueStringSlice(webhook.Actions)
+	form.Disabled = webhook.Disabled
+
+	return form
+}
+
+// SetDao replaces the default form Dao instance with the provided one.
+func (form *WebhookUpsert) SetDao(dao *daos.Dao) {
+	form.dao = dao
+}
+
+// Validate makes the form validatable by implementing [validation.Validatable] interface.
+func (form *WebhookUpsert) Validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(
+			&form.Id,
+			validation.When(
+				form.webhook.IsNew(),
+				validation.Length(models.DefaultIdLength, models.DefaultIdLength),
+				validation.Match(idRegex),
+				validation.By(validators.UniqueId(form.dao, form.webhook.TableName())),
+			).Else(validation.In(form.webhook.Id)),
+		),
+		validation.Field(&form.Name, validation.Required, validation.Length(1, 255)),
+		validation.Field(&form.Url, validation.Required, validation.Length(1, 2000), is.URL),
+		validation.Field(&form.Collections, validation.By(form.checkCollections)),
+		validation.Field(
+			&form.Actions,
+			validation.Required,
+			validation.Each(validation.In(list.ToInterfaceSlice(models.WebhookActions)...)),
+		),
+		validation.Field(
+			&form.Secret,
+			// on update an empty secret means "keep the current one"
+			validation.When(form.webhook.IsNew(), validation.Required),
+			validation.Length(10, 255),
+		),
+	)
+}
+
+func (form *WebhookUpsert) checkCollections(value any) error {
+	v, _ := value.([]string)
+
+	for i, nameOrId := range v {
+		if _, err := form.dao.FindCollectionByNameOrId(nameOrId); err != nil {
+			return validation.Errors{
+				strconv.Itoa(i): validation.NewError("validation_missing_collection", "Missing or invalid collection."),
+			}
+		}
+	}
+
+	return nil
+}
+
+// Submit validates the form and upserts the form webhook model.
+//
+// You can optionally provide a list of InterceptorFunc to further
+// modify the form behavior before persisting it.
+func (form *WebhookUpsert) Submit(interceptors ...InterceptorFunc[*models.Webhook]) error {
+	if err := form.Validate(); err != nil {
+		return err
+	}
+
+	// custom insertion id can be set only on create
+	if form.webhook.IsNew() && form.Id != "" {
+		form.webhook.MarkAsNew()
+		form.webhook.SetId(form.Id)
+	}
+
+	form.webhook.Name = form.Name
+	form.webhook.Url = form.Url
+	form.webhook.Collections = list.ToInterfaceSlice(list.ToUniqueStringSlice(form.Collections))
+	form.webhook.Actions = list.ToInterfaceSlice(list.ToUniqueStringSlice(form.Actions))
+	form.webhook.Disabled = form.Disabled
+
+	if form.Secret != "" {
+		form.webhook.Secret = form.Secret
+	}
+
+	return runInterceptors(form.webhook, func(webhook *models.Webhook) error {
+		return form.dao.SaveWebhook(webhook)
+	}, interceptors...)
+}
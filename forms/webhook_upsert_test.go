@@ -0,0 +1,248 @@
+package forms_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/list"
+)
+
+func TestNewWebhookUpsert(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	webhook, err := app.Dao().FindWebhookById("ftxuvq1ojf4b2pa")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := forms.NewWebhookUpsert(app, webhook)
+
+	// test defaults
+	if form.Id != webhook.Id {
+		t.Errorf("Expected Id %q, got %q", webhook.Id, form.Id)
+	}
+	if form.Name != webhook.Name {
+		t.Errorf("Expected Name %q, got %q", webhook.Name, form.Name)
+	}
+	if form.Url != webhook.Url {
+		t.Errorf("Expected Url %q, got %q", webhook.Url, form.Url)
+	}
+	if len(form.Collections) != 1 || form.Collections[0] != "demo2" {
+		t.Errorf("Expected Collections [demo2], got %v", form.Collections)
+	}
+	if len(form.Actions) != 3 {
+		t.Errorf("Expected 3 Actions, got %v", form.Actions)
+	}
+	if form.Secret != "" {
+		t.Errorf("Expected the Secret to not be loaded, got %q", form.Secret)
+	}
+	if form.Disabled != webhook.Disabled {
+		t.Errorf("Expected Disabled %v, got %v", webhook.Disabled, form.Disabled)
+	}
+}
+
+func TestWebhookUpsertValidateAndSubmit(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		name           string
+		id             string
+		jsonData       string
+		expectedErrors []string
+	}{
+		{
+			"create with empty data",
+			"",
+			`{}`,
+			[]string{"name", "url", "actions", "secret"},
+		},
+		{
+			"create with invalid data",
+			"",
+			`{
+				"name":        "test",
+				"url":         "invalid",
+				"collections": ["demo1", "missing"],
+				"actions":     ["create", "invalid"],
+				"secret":      "short"
+			}`,
+			[]string{"url", "collections", "actions", "secret"},
+		},
+		{
+			"create with valid data",
+			"",
+			`{
+				"name":        "test",
+				"url":         "https://example.com/test",
+				"collections": ["demo1", "wsmn24bux7wo113", "demo1"],
+				"actions":     ["create", "auth", "create"],
+				"secret":      "1234567890",
+				"disabled":    true
+			}`,
+			[]string{},
+		},
+		{
+			"update with empty data",
+			"ftxuvq1ojf4b2pa",
+			`{}`,
+			[]string{},
+		},
+		{
+			"update with invalid data",
+			"ftxuvq1ojf4b2pa",
+			`{
+				"name":    "",
+				"actions": []
+			}`,
+			[]string{"name", "actions"},
+		},
+		{
+			"update with valid data",
+			"ftxuvq1ojf4b2pa",
+			`{
+				"name":        "test_update",
+				"collections": [],
+				"actions":     ["delete"],
+				"secret":      "1234567890_new"
+			}`,
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		webhook := &models.Webhook{}
+		if s.id != "" {
+			webhook, _ = app.Dao().FindWebhookById(s.id)
+		}
+		initialSecret := webhook.Secret
+
+		form := forms.NewWebhookUpsert(app, webhook)
+
+		// load data
+		if err := json.Unmarshal([]byte(s.jsonData), form); err != nil {
+			t.Errorf("[%s] Failed to load form data: %v", s.name, err)
+			continue
+		}
+
+		interceptorCalls := 0
+
+		err := form.Submit(func(next forms.InterceptorNextFunc[*models.Webhook]) forms.InterceptorNextFunc[*models.Webhook] {
+			return func(m *models.Webhook) error {
+				interceptorCalls++
+				return next(m)
+			}
+		})
+
+		// parse errors
+		errs, ok := err.(validation.Errors)
+		if !ok && err != nil {
+			t.Errorf("[%s] Failed to parse errors %v", s.name, err)
+			continue
+		}
+
+		// check errors
+		if len(errs) != len(s.expectedErrors) {
+			t.Errorf("[%s] Expected error keys %v, got %v", s.name, s.expectedErrors, errs)
+			continue
+		}
+		for _, k := range s.expectedErrors {
+			if _, ok := errs[k]; !ok {
+				t.Errorf("[%s] Missing expected error key %q in %v", s.name, k, errs)
+			}
+		}
+
+		expectInterceptorCall := 1
+		if len(s.expectedErrors) > 0 {
+			expectInterceptorCall = 0
+		}
+		if interceptorCalls != expectInterceptorCall {
+			t.Errorf("[%s] Expected interceptor to be called %d, got %d", s.name, expectInterceptorCall, interceptorCalls)
+		}
+
+		if len(s.expectedErrors) > 0 {
+			continue // skip persistence check
+		}
+
+		found, err := app.Dao().FindWebhookById(webhook.Id)
+		if err != nil {
+			t.Errorf("[%s] Expected the webhook to be persisted, got %v", s.name, err)
+			continue
+		}
+
+		if found.Name != form.Name || found.Url != form.Url || found.Disabled != form.Disabled {
+			t.Errorf("[%s] Expected the form fields to be persisted, got %v", s.name, found)
+		}
+
+		if len(found.Collections) != len(list.ToUniqueStringSlice(form.Collections)) {
+			t.Errorf("[%s] Expected unique collections %v, got %v", s.name, form.Collections, found.Collections)
+		}
+
+		if len(found.Actions) != len(list.ToUniqueStringSlice(form.Actions)) {
+			t.Errorf("[%s] Expected unique actions %v, got %v", s.name, form.Actions, found.Actions)
+		}
+
+		expectedSecret := form.Secret
+		if expectedSecret == "" {
+			expectedSecret = initialSecret
+		}
+		if found.Secret != expectedSecret {
+			t.Errorf("[%s] Expected secret %q, got %q", s.name, expectedSecret, found.Secret)
+		}
+	}
+}
+
+func TestWebhookUpsertSubmitInterceptors(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	webhook := &models.Webhook{}
+	form := forms.NewWebhookUpsert(app, webhook)
+	form.Name = "test"
+	form.Url = "https://example.com"
+	form.Actions = []string{models.WebhookActionCreate}
+	form.Secret = "1234567890"
+
+	testErr := errors.New("test_error")
+	interceptorWebhookUrl := ""
+
+	interceptor1Called := false
+	interceptor1 := func(next forms.InterceptorNextFunc[*models.Webhook]) forms.InterceptorNextFunc[*models.Webhook] {
+		return func(m *models.Webhook) error {
+			interceptor1Called = true
+			return next(m)
+		}
+	}
+
+	interceptor2Called := false
+	interceptor2 := func(next forms.InterceptorNextFunc[*models.Webhook]) forms.InterceptorNextFunc[*models.Webhook] {
+		return func(m *models.Webhook) error {
+			interceptorWebhookUrl = webhook.Url // to check if the model was filled
+			interceptor2Called = true
+			return testErr
+		}
+	}
+
+	err := form.Submit(interceptor1, interceptor2)
+	if err != testErr {
+		t.Fatalf("Expected error %v, got %v", testErr, err)
+	}
+
+	if !interceptor1Called {
+		t.Fatalf("Expected interceptor1 to be called")
+	}
+
+	if !interceptor2Called {
+		t.Fatalf("Expected interceptor2 to be called")
+	}
+
+	if interceptorWebhookUrl != form.Url {
+		t.Fatalf("Expected the form model to be filled before calling the interceptors")
+	}
+}
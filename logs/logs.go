@@ -25,10 +25,10 @@ const (
 	ContextCollectionKey string = "collection"
 )
 
-// Returns the "real" user IP from common proxy headers (or fallbackIp if none is found).
+// RealUserIp returns the "real" user IP from common proxy headers (or fallbackIp if none is found).
 //
 // The returned IP value shouldn't be trusted if not behind a trusted reverse proxy!
-func realUserIp(r *http.Request, fallbackIp string) string {
+func RealUserIp(r *http.Request, fallbackIp string) string {
 	if ip := r.Header.Get("CF-Connecting-IP"); ip != "" {
 		return ip
 	}
@@ -336,7 +336,7 @@ func (e *Event) Request(c echo.Context) *Event {
 		Method:    strings.ToLower(httpRequest.Method),
 		Status:    status,
 		Auth:      requestAuth,
-		UserIp:    realUserIp(httpRequest, ip),
+		UserIp:    RealUserIp(httpRequest, ip),
 		RemoteIp:  ip,
 		Referer:   httpRequest.Referer(),
 		UserAgent: httpRequest.UserAgent(),
@@ -4,6 +4,7 @@ import (
 	"html/template"
 	"log"
 	"net/mail"
+	"strings"
 
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/mails/templates"
@@ -148,6 +149,58 @@ func SendRecordChangeEmail(app core.App, record *models.Record, newEmail string)
 	return sendErr
 }
 
+// SendRecordOTP sends a one-time password email (with a magic link)
+// to the specified auth record.
+func SendRecordOTP(app core.App, authRecord *models.Record, otp *models.OTP, password string) error {
+	token, tokenErr := tokens.NewRecordOTPToken(app, authRecord, otp.Id)
+	if tokenErr != nil {
+		return tokenErr
+	}
+
+	mailClient := app.NewMailClient()
+
+	subject, body, err := resolveEmailTemplate(app, token, app.Settings().Meta.OTPTemplate)
+	if err != nil {
+		return err
+	}
+
+	// the password is not html escaped because it contains only digits
+	body = strings.ReplaceAll(body, settings.EmailPlaceholderOTP, password)
+
+	message := &mailer.Message{
+		From: mail.Address{
+			Name:    app.Settings().Meta.SenderName,
+			Address: app.Settings().Meta.SenderAddress,
+		},
+		To:      []mail.Address{{Address: otp.SentTo}},
+		Subject: subject,
+		HTML:    body,
+	}
+
+	event := new(core.MailerRecordEvent)
+	event.MailClient = mailClient
+	event.Message = message
+	event.Collection = authRecord.Collection()
+	event.Record = authRecord
+	event.Meta = map[string]any{
+		"token": token,
+		"otpId": otp.Id,
+		"otp":   password,
+	}
+
+	sendErr := app.OnMailerBeforeRecordOTPSend().Trigger(event, func(e *core.MailerRecordEvent) error {
+		return e.MailClient.Send(e.Message)
+	})
+
+	if sendErr == nil {
+		if err := app.OnMailerAfterRecordOTPSend().Trigger(event); err != nil && app.IsDebug() {
+			log.Println(err)
+		}
+	}
+
+	return sendErr
+}
+
 func resolveEmailTemplate(
 	app core.App,
 	token string,
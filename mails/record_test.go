@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/pocketbase/pocketbase/mails"
+	"github.com/pocketbase/pocketbase/models"
 	"github.com/pocketbase/pocketbase/tests"
 )
 
@@ -85,3 +86,36 @@ func TestSendRecordChangeEmail(t *testing.T) {
 		}
 	}
 }
+
+func TestSendRecordOTP(t *testing.T) {
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	user, _ := testApp.Dao().FindFirstRecordByData("users", "email", "test@example.com")
+
+	otp := &models.OTP{SentTo: "otp@example.com"}
+	otp.Id = "test_otp_id"
+
+	err := mails.SendRecordOTP(testApp, user, otp, "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if testApp.TestMailer.TotalSend != 1 {
+		t.Fatalf("Expected one email to be sent, got %d", testApp.TestMailer.TotalSend)
+	}
+
+	if to := testApp.TestMailer.LastMessage.To[0].Address; to != otp.SentTo {
+		t.Fatalf("Expected the email to be sent to %q, got %q", otp.SentTo, to)
+	}
+
+	expectedParts := []string{
+		"<strong>123456</strong>",
+		"http://localhost:8090/_/#/auth/auth-with-otp/eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.",
+	}
+	for _, part := range expectedParts {
+		if !strings.Contains(testApp.TestMailer.LastMessage.HTML, part) {
+			t.Fatalf("Couldn't find %s \nin\n %s", part, testApp.TestMailer.LastMessage.HTML)
+		}
+	}
+}
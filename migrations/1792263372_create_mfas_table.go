@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+// This migration creates the "_mfas" system table that stores
+// the admins and auth records TOTP multi-factor authentication settings.
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		_, err := db.NewQuery(`
+			CREATE TABLE {{_mfas}} (
+				[[id]]            TEXT PRIMARY KEY NOT NULL,
+				[[collectionId]]  TEXT DEFAULT "" NOT NULL,
+				[[recordId]]      TEXT NOT NULL,
+				[[secret]]        TEXT NOT NULL,
+				[[recoveryCodes]] JSON DEFAULT "[]" NOT NULL,
+				[[verified]]      BOOLEAN DEFAULT FALSE NOT NULL,
+				[[attempts]]      INTEGER DEFAULT 0 NOT NULL,
+				[[lockedUntil]]   TEXT DEFAULT "" NOT NULL,
+				[[lastStep]]      INTEGER DEFAULT 0 NOT NULL,
+				[[created]]       TEXT DEFAULT "" NOT NULL,
+				[[updated]]       TEXT DEFAULT "" NOT NULL
+			);
+
+			CREATE UNIQUE INDEX _mfas_collection_record_idx on {{_mfas}} ([[collectionId]], [[recordId]]);
+		`).Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		_, err := db.DropTable("_mfas").Execute()
+
+		return err
+	})
+}
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+// This migration creates the "_otps" system table that stores
+// the auth records one-time password requests.
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		_, err := db.NewQuery(`
+			CREATE TABLE {{_otps}} (
+				[[id]]           TEXT PRIMARY KEY NOT NULL,
+				[[collectionId]] TEXT NOT NULL,
+				[[recordId]]     TEXT NOT NULL,
+				[[password]]     TEXT NOT NULL,
+				[[sentTo]]       TEXT DEFAULT "" NOT NULL,
+				[[attempts]]     INTEGER DEFAULT 0 NOT NULL,
+				[[created]]      TEXT DEFAULT "" NOT NULL,
+				[[updated]]      TEXT DEFAULT "" NOT NULL
+			);
+
+			CREATE INDEX _otps_collection_record_idx on {{_otps}} ([[collectionId]], [[recordId]]);
+		`).Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		_, err := db.DropTable("_otps").Execute()
+
+		return err
+	})
+}
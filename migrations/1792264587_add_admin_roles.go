@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+// This migration adds the admin role and collection scopes columns
+// (existing admins are marked as superusers to preserve their access).
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		if _, err := db.AddColumn("_admins", "role", `TEXT DEFAULT "superuser" NOT NULL`).Execute(); err != nil {
+			return err
+		}
+
+		if _, err := db.AddColumn("_admins", "collections", `JSON DEFAULT "[]" NOT NULL`).Execute(); err != nil {
+			return err
+		}
+
+		return nil
+	}, func(db dbx.Builder) error {
+		if _, err := db.DropColumn("_admins", "collections").Execute(); err != nil {
+			return err
+		}
+
+		if _, err := db.DropColumn("_admins", "role").Execute(); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+// This migration creates the "_webhooks" system table that stores
+// the outgoing record events webhook definitions.
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		_, err := db.NewQuery(`
+			CREATE TABLE {{_webhooks}} (
+				[[id]]          TEXT PRIMARY KEY NOT NULL,
+				[[name]]        TEXT DEFAULT "" NOT NULL,
+				[[url]]         TEXT DEFAULT "" NOT NULL,
+				[[collections]] JSON DEFAULT "[]" NOT NULL,
+				[[actions]]     JSON DEFAULT "[]" NOT NULL,
+				[[secret]]      TEXT DEFAULT "" NOT NULL,
+				[[disabled]]    BOOLEAN DEFAULT FALSE NOT NULL,
+				[[created]]     TEXT DEFAULT "" NOT NULL,
+				[[updated]]     TEXT DEFAULT "" NOT NULL
+			);
+		`).Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		_, err := db.DropTable("_webhooks").Execute()
+
+		return err
+	})
+}
@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+// This migration creates the "_uploads" system table that stores
+// the resumable record file upload sessions.
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		_, err := db.NewQuery(`
+			CREATE TABLE {{_uploads}} (
+				[[id]]                TEXT PRIMARY KEY NOT NULL,
+				[[collectionId]]      TEXT NOT NULL,
+				[[recordId]]          TEXT NOT NULL,
+				[[field]]             TEXT NOT NULL,
+				[[filename]]          TEXT NOT NULL,
+				[[size]]              INTEGER DEFAULT 0 NOT NULL,
+				[[offset]]            INTEGER DEFAULT 0 NOT NULL,
+				[[ownerId]]           TEXT DEFAULT "" NOT NULL,
+				[[ownerCollectionId]] TEXT DEFAULT "" NOT NULL,
+				[[created]]           TEXT DEFAULT "" NOT NULL,
+				[[updated]]           TEXT DEFAULT "" NOT NULL
+			);
+
+			CREATE INDEX _uploads_updated_idx on {{_uploads}} ([[updated]]);
+		`).Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		_, err := db.DropTable("_uploads").Execute()
+
+		return err
+	})
+}
@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+// This migration creates the "_imagePlaceholders" system table that stores
+// the generated record file field images placeholders.
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		_, err := db.NewQuery(`
+			CREATE TABLE {{_imagePlaceholders}} (
+				[[id]]           TEXT PRIMARY KEY NOT NULL,
+				[[collectionId]] TEXT NOT NULL,
+				[[recordId]]     TEXT NOT NULL,
+				[[field]]        TEXT NOT NULL,
+				[[filename]]     TEXT NOT NULL,
+				[[blurHash]]     TEXT DEFAULT "" NOT NULL,
+				[[color]]        TEXT DEFAULT "" NOT NULL,
+				[[width]]        INTEGER DEFAULT 0 NOT NULL,
+				[[height]]       INTEGER DEFAULT 0 NOT NULL,
+				[[created]]      TEXT DEFAULT "" NOT NULL,
+				[[updated]]      TEXT DEFAULT "" NOT NULL
+			);
+
+			CREATE UNIQUE INDEX _imagePlaceholders_file_idx on {{_imagePlaceholders}} ([[collectionId]], [[recordId]], [[filename]]);
+		`).Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		_, err := db.DropTable("_imagePlaceholders").Execute()
+
+		return err
+	})
+}
@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+// This migration creates the "_fileRefs" system table that stores
+// the record files references to the content-addressed storage blobs.
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		_, err := db.NewQuery(`
+			CREATE TABLE {{_fileRefs}} (
+				[[id]]           TEXT PRIMARY KEY NOT NULL,
+				[[collectionId]] TEXT NOT NULL,
+				[[recordId]]     TEXT NOT NULL,
+				[[filename]]     TEXT NOT NULL,
+				[[hash]]         TEXT NOT NULL,
+				[[size]]         INTEGER DEFAULT 0 NOT NULL,
+				[[created]]      TEXT DEFAULT "" NOT NULL,
+				[[updated]]      TEXT DEFAULT "" NOT NULL
+			);
+
+			CREATE UNIQUE INDEX _fileRefs_file_idx on {{_fileRefs}} ([[collectionId]], [[recordId]], [[filename]]);
+			CREATE INDEX _fileRefs_hash_idx on {{_fileRefs}} ([[hash]]);
+		`).Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		_, err := db.DropTable("_fileRefs").Execute()
+
+		return err
+	})
+}
@@ -0,0 +1,100 @@
+package migrations
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/settings"
+	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// This migration generates and stores the secrets of the settings token
+// configs that are missing in the stored app settings (eg. the ones added
+// after the settings were created), otherwise they would be regenerated
+// on each app start and would differ between the app instances.
+//
+// Note that encrypted settings cannot be decoded here and
+// their missing secrets are stored with the next settings save.
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		dao := daos.New(db)
+
+		param, err := dao.FindParamByKey(models.ParamAppSettings)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil // the default settings will be stored on app load
+			}
+			return err
+		}
+
+		stored := map[string]any{}
+		if err := json.Unmarshal(param.Value, &stored); err != nil {
+			return nil // encrypted
+		}
+
+		rawDefaults, err := json.Marshal(settings.New())
+		if err != nil {
+			return err
+		}
+
+		defaults := map[string]any{}
+		if err := json.Unmarshal(rawDefaults, &defaults); err != nil {
+			return err
+		}
+
+		tokenKeys := []string{
+			"adminAuthToken",
+			"adminPasswordResetToken",
+			"adminMfaToken",
+			"adminFileToken",
+			"recordAuthToken",
+			"recordPasswordResetToken",
+			"recordEmailChangeToken",
+			"recordVerificationToken",
+			"recordMfaToken",
+			"recordOTPToken",
+			"recordFileToken",
+		}
+
+		var hasChanges bool
+
+		for _, key := range tokenKeys {
+			defaultConfig, _ := defaults[key].(map[string]any)
+
+			config, _ := stored[key].(map[string]any)
+			if config == nil {
+				config = map[string]any{}
+			}
+
+			if secret, _ := config["secret"].(string); secret != "" {
+				continue
+			}
+
+			config["secret"] = security.RandomString(50)
+			if _, ok := config["duration"]; !ok {
+				config["duration"] = defaultConfig["duration"]
+			}
+
+			stored[key] = config
+			hasChanges = true
+		}
+
+		if !hasChanges {
+			return nil
+		}
+
+		encoded, err := json.Marshal(stored)
+		if err != nil {
+			return err
+		}
+
+		param.Value = types.JsonRaw(encoded)
+
+		return dao.Save(param)
+	}, nil)
+}
@@ -0,0 +1,38 @@
+package logs
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+func init() {
+	LogsMigrations.Register(func(db dbx.Builder) error {
+		_, err := db.NewQuery(`
+			CREATE TABLE {{_webhookDeliveries}} (
+				[[id]]             TEXT PRIMARY KEY NOT NULL,
+				[[webhookId]]      TEXT DEFAULT "" NOT NULL,
+				[[url]]            TEXT DEFAULT "" NOT NULL,
+				[[action]]         TEXT DEFAULT "" NOT NULL,
+				[[collectionId]]   TEXT DEFAULT "" NOT NULL,
+				[[recordId]]       TEXT DEFAULT "" NOT NULL,
+				[[payload]]        JSON DEFAULT "{}" NOT NULL,
+				[[status]]         TEXT DEFAULT "pending" NOT NULL,
+				[[attempts]]       INTEGER DEFAULT 0 NOT NULL,
+				[[nextAttemptAt]]  TEXT DEFAULT "" NOT NULL,
+				[[responseStatus]] INTEGER DEFAULT 0 NOT NULL,
+				[[error]]          TEXT DEFAULT "" NOT NULL,
+				[[created]]        TEXT DEFAULT "" NOT NULL,
+				[[updated]]        TEXT DEFAULT "" NOT NULL
+			);
+
+			CREATE INDEX _webhookDeliveries_webhookId_idx on {{_webhookDeliveries}} ([[webhookId]]);
+			CREATE INDEX _webhookDeliveries_status_next_idx on {{_webhookDeliveries}} ([[status]], [[nextAttemptAt]]);
+			CREATE INDEX _webhookDeliveries_created_hour_idx on {{_webhookDeliveries}} (strftime('%Y-%m-%d %H:00:00', [[created]]));
+		`).Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		_, err := db.DropTable("_webhookDeliveries").Execute()
+
+		return err
+	})
+}
@@ -0,0 +1,35 @@
+package logs
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+func init() {
+	LogsMigrations.Register(func(db dbx.Builder) error {
+		_, err := db.NewQuery(`
+			CREATE TABLE {{_auditLogs}} (
+				[[id]]                TEXT PRIMARY KEY NOT NULL,
+				[[action]]            TEXT DEFAULT "" NOT NULL,
+				[[collectionId]]      TEXT DEFAULT "" NOT NULL,
+				[[collectionName]]    TEXT DEFAULT "" NOT NULL,
+				[[recordId]]          TEXT DEFAULT "" NOT NULL,
+				[[actorType]]         TEXT DEFAULT "" NOT NULL,
+				[[actorId]]           TEXT DEFAULT "" NOT NULL,
+				[[actorCollectionId]] TEXT DEFAULT "" NOT NULL,
+				[[changes]]           JSON DEFAULT "{}" NOT NULL,
+				[[created]]           TEXT DEFAULT "" NOT NULL,
+				[[updated]]           TEXT DEFAULT "" NOT NULL
+			);
+
+			CREATE INDEX _auditLogs_collection_record_idx on {{_auditLogs}} ([[collectionId]], [[recordId]]);
+			CREATE INDEX _auditLogs_actor_idx on {{_auditLogs}} ([[actorType]], [[actorId]]);
+			CREATE INDEX _auditLogs_created_hour_idx on {{_auditLogs}} (strftime('%Y-%m-%d %H:00:00', [[created]]));
+		`).Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		_, err := db.DropTable("_auditLogs").Execute()
+
+		return err
+	})
+}
@@ -12,6 +12,34 @@ var (
 	_ Model = (*Admin)(nil)
 )
 
+// Admin roles.
+const (
+	// AdminRoleSuperuser grants unrestricted access to everything.
+	AdminRoleSuperuser = "superuser"
+
+	// AdminRoleReadonly grants read-only access to the collections,
+	// their records and the request logs.
+	AdminRoleReadonly = "readonly"
+
+	// AdminRoleLogs grants access only to the request logs.
+	AdminRoleLogs = "logs"
+
+	// AdminRoleCollections grants access to manage the collections and their records.
+	AdminRoleCollections = "collections"
+
+	// AdminRoleSettings grants access to manage the application settings.
+	AdminRoleSettings = "settings"
+)
+
+// AdminRoles is the list of all available admin roles.
+var AdminRoles = []string{
+	AdminRoleSuperuser,
+	AdminRoleReadonly,
+	AdminRoleLogs,
+	AdminRoleCollections,
+	AdminRoleSettings,
+}
+
 type Admin struct {
 	BaseModel
 
@@ -20,6 +48,14 @@ type Admin struct {
 	TokenKey        string         `db:"tokenKey" json:"-"`
 	PasswordHash    string         `db:"passwordHash" json:"-"`
 	LastResetSentAt types.DateTime `db:"lastResetSentAt" json:"-"`
+
+	// Role is the admin access role (empty is treated as AdminRoleSuperuser).
+	Role string `db:"role" json:"role"`
+
+	// Collections is an optional list with collection ids or names that
+	// restricts the collections and records access of non-superuser admins
+	// (empty means access to all collections allowed by the admin role).
+	Collections types.JsonArray `db:"collections" json:"collections"`
 }
 
 // TableName returns the Admin model SQL table name.
@@ -60,6 +96,64 @@ func (m *Admin) SetPassword(password string) error {
 	return m.RefreshTokenKey()
 }
 
+// IsSuperuser reports whether the admin has unrestricted access.
+//
+// Admins without explicit role (eg. created before the roles introduction)
+// are also considered superusers.
+func (m *Admin) IsSuperuser() bool {
+	return m.Role == "" || m.Role == AdminRoleSuperuser
+}
+
+// HasRole reports whether the admin has any of the specified roles.
+//
+// Superusers are considered to have all roles.
+func (m *Admin) HasRole(roles ...string) bool {
+	if m.IsSuperuser() {
+		return true
+	}
+
+	for _, role := range roles {
+		if m.Role == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsScoped reports whether the admin access is restricted to a limited set of collections.
+func (m *Admin) IsScoped() bool {
+	return !m.IsSuperuser() && len(m.Collections) > 0
+}
+
+// CanAccessCollection reports whether the admin collection
+// scopes allow access to the specified collection.
+func (m *Admin) CanAccessCollection(collection *Collection) bool {
+	if !m.IsScoped() {
+		return true
+	}
+
+	for _, v := range m.Collections {
+		if str, _ := v.(string); str != "" && (str == collection.Id || str == collection.Name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CanViewRecords reports whether the admin is allowed to list and
+// view the records of the specified collection (bypassing its API rules).
+func (m *Admin) CanViewRecords(collection *Collection) bool {
+	return m.HasRole(AdminRoleReadonly, AdminRoleCollections) && m.CanAccessCollection(collection)
+}
+
+// CanManageRecords reports whether the admin is allowed to create, update and
+// delete the records of the specified collection (bypassing its API rules).
+func (m *Admin) CanManageRecords(collection *Collection) bool {
+	return m.HasRole(AdminRoleCollections) && m.CanAccessCollection(collection)
+}
+
 // RefreshTokenKey generates and sets new random token key.
 func (m *Admin) RefreshTokenKey() error {
 	m.TokenKey = security.RandomString(50)
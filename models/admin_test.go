@@ -102,3 +102,134 @@ func TestAdminRefreshTokenKey(t *testing.T) {
 		t.Fatalf("Expected TokenKey to change, got %q", m.TokenKey)
 	}
 }
+
+func TestAdminIsSuperuser(t *testing.T) {
+	scenarios := []struct {
+		role     string
+		expected bool
+	}{
+		{"", true},
+		{models.AdminRoleSuperuser, true},
+		{models.AdminRoleReadonly, false},
+		{models.AdminRoleLogs, false},
+		{models.AdminRoleCollections, false},
+		{models.AdminRoleSettings, false},
+	}
+
+	for _, s := range scenarios {
+		m := models.Admin{Role: s.role}
+
+		if result := m.IsSuperuser(); result != s.expected {
+			t.Errorf("(%q) Expected %v, got %v", s.role, s.expected, result)
+		}
+	}
+}
+
+func TestAdminHasRole(t *testing.T) {
+	scenarios := []struct {
+		role     string
+		roles    []string
+		expected bool
+	}{
+		{"", nil, true},
+		{models.AdminRoleSuperuser, []string{models.AdminRoleLogs}, true},
+		{models.AdminRoleLogs, nil, false},
+		{models.AdminRoleLogs, []string{models.AdminRoleSuperuser}, false},
+		{models.AdminRoleLogs, []string{models.AdminRoleSettings}, false},
+		{models.AdminRoleLogs, []string{models.AdminRoleSettings, models.AdminRoleLogs}, true},
+	}
+
+	for i, s := range scenarios {
+		m := models.Admin{Role: s.role}
+
+		if result := m.HasRole(s.roles...); result != s.expected {
+			t.Errorf("(%d) Expected %v, got %v", i, s.expected, result)
+		}
+	}
+}
+
+func TestAdminCollectionsAccess(t *testing.T) {
+	c1 := &models.Collection{Name: "c1"}
+	c1.Id = "c1_id"
+
+	c2 := &models.Collection{Name: "c2"}
+	c2.Id = "c2_id"
+
+	scenarios := []struct {
+		name         string
+		admin        models.Admin
+		expectScoped bool
+		expectAccess []bool // c1, c2
+		expectView   []bool // c1, c2
+		expectManage []bool // c1, c2
+	}{
+		{
+			"superuser with scopes",
+			models.Admin{Role: models.AdminRoleSuperuser, Collections: types.JsonArray{"c1"}},
+			false,
+			[]bool{true, true},
+			[]bool{true, true},
+			[]bool{true, true},
+		},
+		{
+			"readonly without scopes",
+			models.Admin{Role: models.AdminRoleReadonly},
+			false,
+			[]bool{true, true},
+			[]bool{true, true},
+			[]bool{false, false},
+		},
+		{
+			"readonly with name scope",
+			models.Admin{Role: models.AdminRoleReadonly, Collections: types.JsonArray{"c1"}},
+			true,
+			[]bool{true, false},
+			[]bool{true, false},
+			[]bool{false, false},
+		},
+		{
+			"collections with id scope",
+			models.Admin{Role: models.AdminRoleCollections, Collections: types.JsonArray{"c2_id"}},
+			true,
+			[]bool{false, true},
+			[]bool{false, true},
+			[]bool{false, true},
+		},
+		{
+			"logs",
+			models.Admin{Role: models.AdminRoleLogs},
+			false,
+			[]bool{true, true},
+			[]bool{false, false},
+			[]bool{false, false},
+		},
+		{
+			"settings",
+			models.Admin{Role: models.AdminRoleSettings},
+			false,
+			[]bool{true, true},
+			[]bool{false, false},
+			[]bool{false, false},
+		},
+	}
+
+	for _, s := range scenarios {
+		if result := s.admin.IsScoped(); result != s.expectScoped {
+			t.Errorf("[%s] Expected IsScoped %v, got %v", s.name, s.expectScoped, result)
+		}
+
+		for i, c := range []*models.Collection{c1, c2} {
+			if result := s.admin.CanAccessCollection(c); result != s.expectAccess[i] {
+				t.Errorf("[%s] Expected CanAccessCollection(%s) %v, got %v", s.name, c.Name, s.expectAccess[i], result)
+			}
+
+			if result := s.admin.CanViewRecords(c); result != s.expectView[i] {
+				t.Errorf("[%s] Expected CanViewRecords(%s) %v, got %v", s.name, c.Name, s.expectView[i], result)
+			}
+
+			if result := s.admin.CanManageRecords(c); result != s.expectManage[i] {
+				t.Errorf("[%s] Expected CanManageRecords(%s) %v, got %v", s.name, c.Name, s.expectManage[i], result)
+			}
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+var _ Model = (*AuditLog)(nil)
+
+// list with the supported values for `AuditLog.Action`
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+// list with the supported values for `AuditLog.ActorType`
+const (
+	AuditActorSystem = "system"
+	AuditActorGuest  = "guest"
+	AuditActorAdmin  = "admin"
+	AuditActorRecord = "auth_record"
+)
+
+// AuditChange defines a single field change of an [AuditLog].
+type AuditChange struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// AuditLog defines a single record change audit log entry.
+type AuditLog struct {
+	BaseModel
+
+	Action         string `db:"action" json:"action"`
+	CollectionId   string `db:"collectionId" json:"collectionId"`
+	CollectionName string `db:"collectionName" json:"collectionName"`
+	RecordId       string `db:"recordId" json:"recordId"`
+
+	// ActorType is the type of the change initiator
+	// ("system" for changes outside of a known api request).
+	ActorType string `db:"actorType" json:"actorType"`
+
+	// ActorId is the id of the admin or auth record that made the change (if any).
+	ActorId string `db:"actorId" json:"actorId"`
+
+	// ActorCollectionId is the collection id of the auth record that made the change (if any).
+	ActorCollectionId string `db:"actorCollectionId" json:"actorCollectionId"`
+
+	// Changes is a field name => [AuditChange] map with the changed record fields.
+	Changes types.JsonMap `db:"changes" json:"changes"`
+}
+
+// TableName returns the AuditLog model SQL table name.
+func (m *AuditLog) TableName() string {
+	return "_auditLogs"
+}
+
+// NewRecordAuditLog creates a new system [AuditLog] for the specified
+// record action with field-level diff between the record original
+// state (see [Record.OriginalCopy()]) and its latest one.
+//
+// For "create" actions all old values are nil and
+// for "delete" actions all new values are nil.
+//
+// The auth record password hash and token key are never stored
+// and their changes are registered only with a masked value.
+func NewRecordAuditLog(action string, record *Record) *AuditLog {
+	collection := record.Collection()
+
+	log := &AuditLog{
+		Action:         action,
+		CollectionId:   collection.Id,
+		CollectionName: collection.Name,
+		RecordId:       record.Id,
+		ActorType:      AuditActorSystem,
+		Changes:        types.JsonMap{},
+	}
+
+	var oldRecord *Record
+	if action != AuditActionCreate {
+		oldRecord = record.OriginalCopy()
+	}
+
+	fields := []string{}
+	if collection.IsAuth() {
+		fields = append(fields, schema.AuthFieldNames()...)
+	}
+	for _, f := range collection.Schema.Fields() {
+		fields = append(fields, f.Name)
+	}
+
+	for _, name := range fields {
+		change := &AuditChange{}
+
+		if oldRecord != nil {
+			change.Old = oldRecord.Get(name)
+		}
+
+		if action != AuditActionDelete {
+			change.New = record.Get(name)
+		}
+
+		if action == AuditActionUpdate && isSameAuditValue(change.Old, change.New) {
+			continue
+		}
+
+		if name == schema.FieldNamePasswordHash || name == schema.FieldNameTokenKey {
+			change.Old = maskAuditValue(change.Old)
+			change.New = maskAuditValue(change.New)
+		}
+
+		log.Changes[name] = change
+	}
+
+	return log
+}
+
+// maskAuditValue replaces the non-empty secret values with a fixed mask.
+func maskAuditValue(value any) any {
+	if value == nil || value == "" {
+		return value
+	}
+
+	return "******"
+}
+
+func isSameAuditValue(a, b any) bool {
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+
+	return errA == nil && errB == nil && bytes.Equal(encodedA, encodedB)
+}
@@ -0,0 +1,148 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+)
+
+func TestAuditLogTableName(t *testing.T) {
+	m := models.AuditLog{}
+	if m.TableName() != "_auditLogs" {
+		t.Fatalf("Unexpected table name, got %q", m.TableName())
+	}
+}
+
+func TestNewRecordAuditLog(t *testing.T) {
+	baseCollection := &models.Collection{
+		Name: "test",
+		Type: models.CollectionTypeBase,
+		Schema: schema.NewSchema(
+			&schema.SchemaField{Name: "title", Type: schema.FieldTypeText},
+			&schema.SchemaField{Name: "tags", Type: schema.FieldTypeJson},
+		),
+	}
+	baseCollection.Id = "test_id"
+
+	authCollection := &models.Collection{
+		Name: "test_auth",
+		Type: models.CollectionTypeAuth,
+		Schema: schema.NewSchema(
+			&schema.SchemaField{Name: "name", Type: schema.FieldTypeText},
+		),
+	}
+	authCollection.Id = "test_auth_id"
+
+	newBaseRecord := func() *models.Record {
+		r := models.NewRecord(baseCollection)
+		r.Load(map[string]any{"id": "r1", "title": "old", "tags": []string{"a", "b"}})
+		return r
+	}
+
+	newAuthRecord := func() *models.Record {
+		r := models.NewRecord(authCollection)
+		r.Load(map[string]any{
+			"id":           "r2",
+			"name":         "old",
+			"email":        "old@example.com",
+			"verified":     false,
+			"passwordHash": "old_hash",
+			"tokenKey":     "old_key",
+		})
+		return r
+	}
+
+	scenarios := []struct {
+		name     string
+		action   string
+		record   func() *models.Record
+		expected string
+	}{
+		{
+			"create",
+			models.AuditActionCreate,
+			func() *models.Record {
+				r := models.NewRecord(baseCollection)
+				r.Id = "r1"
+				r.Set("title", "new")
+				return r
+			},
+			`{"tags":{"old":null,"new":null},"title":{"old":null,"new":"new"}}`,
+		},
+		{
+			"update without changes",
+			models.AuditActionUpdate,
+			newBaseRecord,
+			`{}`,
+		},
+		{
+			"update with changes",
+			models.AuditActionUpdate,
+			func() *models.Record {
+				r := newBaseRecord()
+				r.Set("title", "new")
+				r.Set("tags", []string{"a", "b"}) // same value
+				return r
+			},
+			`{"title":{"old":"old","new":"new"}}`,
+		},
+		{
+			"delete",
+			models.AuditActionDelete,
+			newBaseRecord,
+			`{"tags":{"old":["a","b"],"new":null},"title":{"old":"old","new":null}}`,
+		},
+		{
+			"auth record update with masked secrets",
+			models.AuditActionUpdate,
+			func() *models.Record {
+				r := newAuthRecord()
+				r.Set("name", "new")
+				r.Set("email", "new@example.com")
+				r.Set("passwordHash", "new_hash")
+				r.Set("tokenKey", "new_key")
+				return r
+			},
+			`{"email":{"old":"old@example.com","new":"new@example.com"},"name":{"old":"old","new":"new"},"passwordHash":{"old":"******","new":"******"},"tokenKey":{"old":"******","new":"******"}}`,
+		},
+		{
+			"auth record update without secrets change",
+			models.AuditActionUpdate,
+			func() *models.Record {
+				r := newAuthRecord()
+				r.Set("verified", true)
+				return r
+			},
+			`{"verified":{"old":false,"new":true}}`,
+		},
+	}
+
+	for _, s := range scenarios {
+		record := s.record()
+
+		log := models.NewRecordAuditLog(s.action, record)
+
+		if log.Action != s.action {
+			t.Errorf("[%s] Expected action %q, got %q", s.name, s.action, log.Action)
+		}
+
+		if log.CollectionId != record.Collection().Id || log.CollectionName != record.Collection().Name {
+			t.Errorf("[%s] Expected collection %q (%q), got %q (%q)", s.name, record.Collection().Id, record.Collection().Name, log.CollectionId, log.CollectionName)
+		}
+
+		if log.RecordId != record.Id {
+			t.Errorf("[%s] Expected record id %q, got %q", s.name, record.Id, log.RecordId)
+		}
+
+		if log.ActorType != models.AuditActorSystem || log.ActorId != "" {
+			t.Errorf("[%s] Expected system actor, got %q (%q)", s.name, log.ActorType, log.ActorId)
+		}
+
+		changes, _ := json.Marshal(log.Changes)
+		if string(changes) != s.expected {
+			t.Errorf("[%s] Expected changes \n%s, \ngot \n%s", s.name, s.expected, changes)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
 	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/tools/list"
 	"github.com/pocketbase/pocketbase/tools/types"
 )
 
@@ -63,6 +64,31 @@ func (m *Collection) IsView() bool {
 	return m.Type == CollectionTypeView
 }
 
+// SearchFields returns the names of the collection schema fields
+// that are part of the collection full-text search index (if any).
+//
+// View collections don't support full-text search and for them
+// this method always returns an empty slice.
+//
+// Note that the full-text search index requires the SQLite FTS5 extension
+// (for CGO builds mattn/go-sqlite3 must be compiled with the "sqlite_fts5" build tag).
+func (m *Collection) SearchFields() []string {
+	switch m.Type {
+	case CollectionTypeAuth:
+		return m.AuthOptions().SearchFields
+	case CollectionTypeView:
+		return []string{}
+	default:
+		return m.BaseOptions().SearchFields
+	}
+}
+
+// SearchTableName returns the name of the FTS5 virtual table
+// used for the collection full-text search index.
+func (m *Collection) SearchTableName() string {
+	return "_" + m.Id + "_fts"
+}
+
 // MarshalJSON implements the [json.Marshaler] interface.
 func (m Collection) MarshalJSON() ([]byte, error) {
 	type alias Collection // prevent recursion
@@ -161,11 +187,14 @@ func (m *Collection) SetOptions(typedOptions any) error {
 
 // CollectionBaseOptions defines the "base" Collection.Options fields.
 type CollectionBaseOptions struct {
+	SearchFields []string `form:"searchFields" json:"searchFields"`
 }
 
 // Validate implements [validation.Validatable] interface.
 func (o CollectionBaseOptions) Validate() error {
-	return nil
+	return validation.ValidateStruct(&o,
+		validation.Field(&o.SearchFields, validation.By(checkUniqueSearchFields)),
+	)
 }
 
 // -------------------------------------------------------------------
@@ -176,10 +205,13 @@ type CollectionAuthOptions struct {
 	AllowOAuth2Auth    bool     `form:"allowOAuth2Auth" json:"allowOAuth2Auth"`
 	AllowUsernameAuth  bool     `form:"allowUsernameAuth" json:"allowUsernameAuth"`
 	AllowEmailAuth     bool     `form:"allowEmailAuth" json:"allowEmailAuth"`
+	AllowMfa           bool     `form:"allowMfa" json:"allowMfa"`
+	AllowOTPAuth       bool     `form:"allowOTPAuth" json:"allowOTPAuth"`
 	RequireEmail       bool     `form:"requireEmail" json:"requireEmail"`
 	ExceptEmailDomains []string `form:"exceptEmailDomains" json:"exceptEmailDomains"`
 	OnlyEmailDomains   []string `form:"onlyEmailDomains" json:"onlyEmailDomains"`
 	MinPasswordLength  int      `form:"minPasswordLength" json:"minPasswordLength"`
+	SearchFields       []string `form:"searchFields" json:"searchFields"`
 }
 
 // Validate implements [validation.Validatable] interface.
@@ -200,9 +232,20 @@ func (o CollectionAuthOptions) Validate() error {
 			validation.Min(5),
 			validation.Max(72),
 		),
+		validation.Field(&o.SearchFields, validation.By(checkUniqueSearchFields)),
 	)
 }
 
+func checkUniqueSearchFields(value any) error {
+	v, _ := value.([]string)
+
+	if len(list.ToUniqueStringSlice(v)) != len(v) {
+		return validation.NewError("validation_duplicated_search_fields", "The search fields must be unique.")
+	}
+
+	return nil
+}
+
 // -------------------------------------------------------------------
 
 // CollectionViewOptions defines the "view" Collection.Options fields.
@@ -66,6 +66,65 @@ func TestCollectionIsAuth(t *testing.T) {
 	}
 }
 
+func TestCollectionSearchFields(t *testing.T) {
+	scenarios := []struct {
+		name       string
+		collection models.Collection
+		expected   []string
+	}{
+		{
+			"no type",
+			models.Collection{Options: types.JsonMap{"searchFields": []string{"a"}}},
+			[]string{"a"},
+		},
+		{
+			"base type",
+			models.Collection{Type: models.CollectionTypeBase, Options: types.JsonMap{"searchFields": []string{"a", "b"}}},
+			[]string{"a", "b"},
+		},
+		{
+			"auth type",
+			models.Collection{Type: models.CollectionTypeAuth, Options: types.JsonMap{"searchFields": []string{"b"}}},
+			[]string{"b"},
+		},
+		{
+			"view type",
+			models.Collection{Type: models.CollectionTypeView, Options: types.JsonMap{"searchFields": []string{"a"}}},
+			[]string{},
+		},
+		{
+			"missing search fields",
+			models.Collection{Type: models.CollectionTypeBase},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		result := s.collection.SearchFields()
+
+		if len(result) != len(s.expected) {
+			t.Errorf("[%s] Expected %v, got %v", s.name, s.expected, result)
+			continue
+		}
+
+		for i, name := range s.expected {
+			if result[i] != name {
+				t.Errorf("[%s] Expected %v, got %v", s.name, s.expected, result)
+				break
+			}
+		}
+	}
+}
+
+func TestCollectionSearchTableName(t *testing.T) {
+	m := models.Collection{}
+	m.Id = "test"
+
+	if m.SearchTableName() != "_test_fts" {
+		t.Fatalf("Unexpected search table name, got %q", m.SearchTableName())
+	}
+}
+
 func TestCollectionMarshalJSON(t *testing.T) {
 	scenarios := []struct {
 		name       string
@@ -75,22 +134,22 @@ func TestCollectionMarshalJSON(t *testing.T) {
 		{
 			"no type",
 			models.Collection{Name: "test"},
-			`{"id":"","created":"","updated":"","name":"test","type":"","system":false,"schema":[],"listRule":null,"viewRule":null,"createRule":null,"updateRule":null,"deleteRule":null,"options":{}}`,
+			`{"id":"","created":"","updated":"","name":"test","type":"","system":false,"schema":[],"listRule":null,"viewRule":null,"createRule":null,"updateRule":null,"deleteRule":null,"options":{"searchFields":null}}`,
 		},
 		{
 			"unknown type + non empty options",
 			models.Collection{Name: "test", Type: "unknown", ListRule: types.Pointer("test_list"), Options: types.JsonMap{"test": 123}},
-			`{"id":"","created":"","updated":"","name":"test","type":"unknown","system":false,"schema":[],"listRule":"test_list","viewRule":null,"createRule":null,"updateRule":null,"deleteRule":null,"options":{}}`,
+			`{"id":"","created":"","updated":"","name":"test","type":"unknown","system":false,"schema":[],"listRule":"test_list","viewRule":null,"createRule":null,"updateRule":null,"deleteRule":null,"options":{"searchFields":null}}`,
 		},
 		{
 			"base type + non empty options",
 			models.Collection{Name: "test", Type: models.CollectionTypeBase, ListRule: types.Pointer("test_list"), Options: types.JsonMap{"test": 123}},
-			`{"id":"","created":"","updated":"","name":"test","type":"base","system":false,"schema":[],"listRule":"test_list","viewRule":null,"createRule":null,"updateRule":null,"deleteRule":null,"options":{}}`,
+			`{"id":"","created":"","updated":"","name":"test","type":"base","system":false,"schema":[],"listRule":"test_list","viewRule":null,"createRule":null,"updateRule":null,"deleteRule":null,"options":{"searchFields":null}}`,
 		},
 		{
 			"auth type + non empty options",
 			models.Collection{BaseModel: models.BaseModel{Id: "test"}, Type: models.CollectionTypeAuth, Options: types.JsonMap{"test": 123, "allowOAuth2Auth": true, "minPasswordLength": 4}},
-			`{"id":"test","created":"","updated":"","name":"","type":"auth","system":false,"schema":[],"listRule":null,"viewRule":null,"createRule":null,"updateRule":null,"deleteRule":null,"options":{"allowEmailAuth":false,"allowOAuth2Auth":true,"allowUsernameAuth":false,"exceptEmailDomains":null,"manageRule":null,"minPasswordLength":4,"onlyEmailDomains":null,"requireEmail":false}}`,
+			`{"id":"test","created":"","updated":"","name":"","type":"auth","system":false,"schema":[],"listRule":null,"viewRule":null,"createRule":null,"updateRule":null,"deleteRule":null,"options":{"allowEmailAuth":false,"allowMfa":false,"allowOAuth2Auth":true,"allowOTPAuth":false,"allowUsernameAuth":false,"exceptEmailDomains":null,"manageRule":null,"minPasswordLength":4,"onlyEmailDomains":null,"requireEmail":false,"searchFields":null}}`,
 		},
 	}
 
@@ -115,22 +174,27 @@ func TestCollectionBaseOptions(t *testing.T) {
 		{
 			"no type",
 			models.Collection{Options: types.JsonMap{"test": 123}},
-			"{}",
+			`{"searchFields":null}`,
 		},
 		{
 			"unknown type",
 			models.Collection{Type: "anything", Options: types.JsonMap{"test": 123}},
-			"{}",
+			`{"searchFields":null}`,
 		},
 		{
 			"different type",
 			models.Collection{Type: models.CollectionTypeAuth, Options: types.JsonMap{"test": 123, "minPasswordLength": 4}},
-			"{}",
+			`{"searchFields":null}`,
 		},
 		{
 			"base type",
 			models.Collection{Type: models.CollectionTypeBase, Options: types.JsonMap{"test": 123}},
-			"{}",
+			`{"searchFields":null}`,
+		},
+		{
+			"base type with search fields",
+			models.Collection{Type: models.CollectionTypeBase, Options: types.JsonMap{"test": 123, "searchFields": []string{"a", "b"}}},
+			`{"searchFields":["a","b"]}`,
 		},
 	}
 
@@ -150,7 +214,7 @@ func TestCollectionBaseOptions(t *testing.T) {
 
 func TestCollectionAuthOptions(t *testing.T) {
 	options := types.JsonMap{"test": 123, "minPasswordLength": 4}
-	expectedSerialization := `{"manageRule":null,"allowOAuth2Auth":false,"allowUsernameAuth":false,"allowEmailAuth":false,"requireEmail":false,"exceptEmailDomains":null,"onlyEmailDomains":null,"minPasswordLength":4}`
+	expectedSerialization := `{"manageRule":null,"allowOAuth2Auth":false,"allowUsernameAuth":false,"allowEmailAuth":false,"allowMfa":false,"allowOTPAuth":false,"requireEmail":false,"exceptEmailDomains":null,"onlyEmailDomains":null,"minPasswordLength":4,"searchFields":null}`
 
 	scenarios := []struct {
 		name       string
@@ -247,17 +311,17 @@ func TestNormalizeOptions(t *testing.T) {
 		{
 			"unknown type",
 			models.Collection{Type: "unknown", Options: types.JsonMap{"test": 123, "minPasswordLength": 4}},
-			"{}",
+			`{"searchFields":null}`,
 		},
 		{
 			"base type",
 			models.Collection{Type: models.CollectionTypeBase, Options: types.JsonMap{"test": 123, "minPasswordLength": 4}},
-			"{}",
+			`{"searchFields":null}`,
 		},
 		{
 			"auth type",
 			models.Collection{Type: models.CollectionTypeAuth, Options: types.JsonMap{"test": 123, "minPasswordLength": 4}},
-			`{"allowEmailAuth":false,"allowOAuth2Auth":false,"allowUsernameAuth":false,"exceptEmailDomains":null,"manageRule":null,"minPasswordLength":4,"onlyEmailDomains":null,"requireEmail":false}`,
+			`{"allowEmailAuth":false,"allowMfa":false,"allowOAuth2Auth":false,"allowOTPAuth":false,"allowUsernameAuth":false,"exceptEmailDomains":null,"manageRule":null,"minPasswordLength":4,"onlyEmailDomains":null,"requireEmail":false,"searchFields":null}`,
 		},
 	}
 
@@ -307,25 +371,25 @@ func TestSetOptions(t *testing.T) {
 			"no type",
 			models.Collection{},
 			map[string]any{},
-			"{}",
+			`{"searchFields":null}`,
 		},
 		{
 			"unknown type + non empty options",
 			models.Collection{Type: "unknown", Options: types.JsonMap{"test": 123}},
 			map[string]any{"test": 456, "minPasswordLength": 4},
-			"{}",
+			`{"searchFields":null}`,
 		},
 		{
 			"base type",
 			models.Collection{Type: models.CollectionTypeBase, Options: types.JsonMap{"test": 123}},
 			map[string]any{"test": 456, "minPasswordLength": 4},
-			"{}",
+			`{"searchFields":null}`,
 		},
 		{
 			"auth type",
 			models.Collection{Type: models.CollectionTypeAuth, Options: types.JsonMap{"test": 123}},
 			map[string]any{"test": 456, "minPasswordLength": 4},
-			`{"allowEmailAuth":false,"allowOAuth2Auth":false,"allowUsernameAuth":false,"exceptEmailDomains":null,"manageRule":null,"minPasswordLength":4,"onlyEmailDomains":null,"requireEmail":false}`,
+			`{"allowEmailAuth":false,"allowMfa":false,"allowOAuth2Auth":false,"allowOTPAuth":false,"allowUsernameAuth":false,"exceptEmailDomains":null,"manageRule":null,"minPasswordLength":4,"onlyEmailDomains":null,"requireEmail":false,"searchFields":null}`,
 		},
 	}
 
@@ -347,9 +411,48 @@ func TestSetOptions(t *testing.T) {
 }
 
 func TestCollectionBaseOptionsValidate(t *testing.T) {
-	opt := models.CollectionBaseOptions{}
-	if err := opt.Validate(); err != nil {
-		t.Fatal(err)
+	scenarios := []struct {
+		name           string
+		options        models.CollectionBaseOptions
+		expectedErrors []string
+	}{
+		{
+			"empty",
+			models.CollectionBaseOptions{},
+			nil,
+		},
+		{
+			"duplicated search fields",
+			models.CollectionBaseOptions{SearchFields: []string{"a", "b", "a"}},
+			[]string{"searchFields"},
+		},
+		{
+			"unique search fields",
+			models.CollectionBaseOptions{SearchFields: []string{"a", "b"}},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		result := s.options.Validate()
+
+		// parse errors
+		errs, ok := result.(validation.Errors)
+		if !ok && result != nil {
+			t.Errorf("[%s] Failed to parse errors %v", s.name, result)
+			continue
+		}
+
+		if len(errs) != len(s.expectedErrors) {
+			t.Errorf("[%s] Expected error keys %v, got errors \n%v", s.name, s.expectedErrors, result)
+			continue
+		}
+
+		for key := range errs {
+			if !list.ExistInSlice(key, s.expectedErrors) {
+				t.Errorf("[%s] Unexpected error key %q in \n%v", s.name, key, errs)
+			}
+		}
 	}
 }
 
@@ -401,6 +504,13 @@ func TestCollectionAuthOptionsValidate(t *testing.T) {
 			},
 			[]string{},
 		},
+		{
+			"duplicated search fields",
+			models.CollectionAuthOptions{
+				SearchFields: []string{"a", "a"},
+			},
+			[]string{"searchFields"},
+		},
 		{
 			"all fields with valid data",
 			models.CollectionAuthOptions{
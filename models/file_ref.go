@@ -0,0 +1,23 @@
+package models
+
+var _ Model = (*FileRef)(nil)
+
+// FileRef defines a single record file reference to
+// a content-addressed storage blob (aka. deduplicated file).
+type FileRef struct {
+	BaseModel
+
+	CollectionId string `db:"collectionId" json:"collectionId"`
+	RecordId     string `db:"recordId" json:"recordId"`
+	Filename     string `db:"filename" json:"filename"`
+
+	// Hash is the hex encoded SHA-256 hash of the file content.
+	Hash string `db:"hash" json:"hash"`
+
+	Size int64 `db:"size" json:"size"`
+}
+
+// TableName returns the FileRef model SQL table name.
+func (m *FileRef) TableName() string {
+	return "_fileRefs"
+}
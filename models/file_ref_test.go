@@ -0,0 +1,14 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/models"
+)
+
+func TestFileRefTableName(t *testing.T) {
+	m := models.FileRef{}
+	if m.TableName() != "_fileRefs" {
+		t.Fatalf("Unexpected table name, got %q", m.TableName())
+	}
+}
@@ -0,0 +1,38 @@
+package models
+
+var _ Model = (*ImagePlaceholder)(nil)
+
+// ImagePlaceholder defines the low resolution placeholders
+// of a single record file field image.
+type ImagePlaceholder struct {
+	BaseModel
+
+	CollectionId string `db:"collectionId" json:"collectionId"`
+	RecordId     string `db:"recordId" json:"recordId"`
+	Field        string `db:"field" json:"field"`
+	Filename     string `db:"filename" json:"filename"`
+
+	// BlurHash is the BlurHash string of the image.
+	BlurHash string `db:"blurHash" json:"blurHash"`
+
+	// Color is the dominant image color as hex string (eg. "#ff0000").
+	Color string `db:"color" json:"color"`
+
+	Width  int `db:"width" json:"width"`
+	Height int `db:"height" json:"height"`
+}
+
+// TableName returns the ImagePlaceholder model SQL table name.
+func (m *ImagePlaceholder) TableName() string {
+	return "_imagePlaceholders"
+}
+
+// PublicExport returns the placeholder data that is exposed with the related record.
+func (m *ImagePlaceholder) PublicExport() map[string]any {
+	return map[string]any{
+		"blurHash": m.BlurHash,
+		"color":    m.Color,
+		"width":    m.Width,
+		"height":   m.Height,
+	}
+}
@@ -0,0 +1,38 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/models"
+)
+
+func TestImagePlaceholderTableName(t *testing.T) {
+	m := models.ImagePlaceholder{}
+	if m.TableName() != "_imagePlaceholders" {
+		t.Fatalf("Unexpected table name, got %q", m.TableName())
+	}
+}
+
+func TestImagePlaceholderPublicExport(t *testing.T) {
+	m := models.ImagePlaceholder{
+		CollectionId: "test_collection",
+		RecordId:     "test_record",
+		Field:        "test_field",
+		Filename:     "test.png",
+		BlurHash:     "00TSUA",
+		Color:        "#ffffff",
+		Width:        10,
+		Height:       20,
+	}
+
+	raw, err := json.Marshal(m.PublicExport())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"blurHash":"00TSUA","color":"#ffffff","height":20,"width":10}`
+	if string(raw) != expected {
+		t.Fatalf("Expected %s, got %s", expected, raw)
+	}
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+var _ Model = (*Mfa)(nil)
+
+// Mfa defines the TOTP multi-factor authentication
+// settings of a single admin or auth record.
+type Mfa struct {
+	BaseModel
+
+	// CollectionId is the auth record collection id (empty for admins).
+	CollectionId string `db:"collectionId" json:"collectionId"`
+
+	// RecordId is the auth record or admin id.
+	RecordId string `db:"recordId" json:"recordId"`
+
+	// Secret is the encrypted base32 TOTP secret.
+	Secret string `db:"secret" json:"-"`
+
+	// RecoveryCodes is a list with the sha256 hashes of the unused recovery codes.
+	RecoveryCodes types.JsonArray `db:"recoveryCodes" json:"-"`
+
+	// Verified indicates whether the TOTP secret enrolment was confirmed.
+	//
+	// Unverified Mfa models are ignored during authentication.
+	Verified bool `db:"verified" json:"verified"`
+
+	// Attempts is the number of the code verification attempts
+	// since the last accepted code (or the last lock).
+	Attempts int `db:"attempts" json:"-"`
+
+	// LockedUntil is the date until which all code verifications are
+	// rejected after reaching the max allowed failed attempts.
+	LockedUntil types.DateTime `db:"lockedUntil" json:"-"`
+
+	// LastStep is the TOTP time step of the last accepted code
+	// (used to prevent replaying an already accepted code).
+	LastStep int64 `db:"lastStep" json:"-"`
+}
+
+// TableName returns the Mfa model SQL table name.
+func (m *Mfa) TableName() string {
+	return "_mfas"
+}
+
+// IsForAdmin reports whether the model belongs to an admin.
+func (m *Mfa) IsForAdmin() bool {
+	return m.CollectionId == ""
+}
+
+// IsLocked reports whether the code verifications are temporary locked.
+func (m *Mfa) IsLocked() bool {
+	return m.LockedUntil.Time().After(time.Now())
+}
+
+// SetSecret encrypts the provided plain TOTP secret with the
+// specified key (must be valid 32 chars aes key) and stores it in m.Secret.
+func (m *Mfa) SetSecret(secret string, key string) error {
+	encrypted, err := security.Encrypt([]byte(secret), key)
+	if err != nil {
+		return err
+	}
+
+	m.Secret = encrypted
+
+	return nil
+}
+
+// DecryptSecret decrypts and returns the plain TOTP secret.
+func (m *Mfa) DecryptSecret(key string) (string, error) {
+	secret, err := security.Decrypt(m.Secret, key)
+	if err != nil {
+		return "", err
+	}
+
+	return string(secret), nil
+}
+
+// SetRecoveryCodes replaces the existing recovery codes with the provided ones.
+//
+// Only the codes hashes are stored.
+func (m *Mfa) SetRecoveryCodes(codes []string) {
+	m.RecoveryCodes = make(types.JsonArray, 0, len(codes))
+
+	for _, code := range codes {
+		m.RecoveryCodes = append(m.RecoveryCodes, HashMfaRecoveryCode(code))
+	}
+}
+
+// UseRecoveryCode checks whether the provided code is one of the
+// unused recovery codes and if so removes it from the list.
+//
+// Note that the model is not persisted.
+func (m *Mfa) UseRecoveryCode(code string) bool {
+	hash := HashMfaRecoveryCode(code)
+
+	for i, existing := range m.RecoveryCodes {
+		str, _ := existing.(string)
+
+		if subtle.ConstantTimeCompare([]byte(str), []byte(hash)) == 1 {
+			m.RecoveryCodes = append(m.RecoveryCodes[:i], m.RecoveryCodes[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// HashMfaRecoveryCode returns the stored (aka. sha256) hash of the provided recovery code.
+func HashMfaRecoveryCode(code string) string {
+	h := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(h[:])
+}
@@ -0,0 +1,117 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestMfaTableName(t *testing.T) {
+	m := models.Mfa{}
+	if m.TableName() != "_mfas" {
+		t.Fatalf("Unexpected table name, got %q", m.TableName())
+	}
+}
+
+func TestMfaIsForAdmin(t *testing.T) {
+	m := models.Mfa{}
+
+	if !m.IsForAdmin() {
+		t.Fatal("Expected the model without collection to be for admin")
+	}
+
+	m.CollectionId = "test"
+
+	if m.IsForAdmin() {
+		t.Fatal("Expected the model with collection to be for auth record")
+	}
+}
+
+func TestMfaIsLocked(t *testing.T) {
+	scenarios := []struct {
+		lockedUntil string
+		expected    bool
+	}{
+		{"", false},
+		{time.Now().Add(-1 * time.Minute).UTC().Format(types.DefaultDateLayout), false},
+		{time.Now().Add(1 * time.Minute).UTC().Format(types.DefaultDateLayout), true},
+	}
+
+	for i, s := range scenarios {
+		m := models.Mfa{}
+		m.LockedUntil, _ = types.ParseDateTime(s.lockedUntil)
+
+		if result := m.IsLocked(); result != s.expected {
+			t.Errorf("(%d) Expected %v, got %v", i, s.expected, result)
+		}
+	}
+}
+
+func TestMfaSecret(t *testing.T) {
+	m := models.Mfa{}
+
+	if err := m.SetSecret("test", "invalid_key"); err == nil {
+		t.Fatal("Expected error for invalid encryption key")
+	}
+
+	key := "abcdabcdabcdabcdabcdabcdabcdabcd"
+
+	if err := m.SetSecret("test", key); err != nil {
+		t.Fatal(err)
+	}
+
+	if m.Secret == "" || m.Secret == "test" {
+		t.Fatalf("Expected the secret to be encrypted, got %q", m.Secret)
+	}
+
+	if _, err := m.DecryptSecret("1234abcdabcdabcdabcdabcdabcdabcd"); err == nil {
+		t.Fatal("Expected error for wrong decryption key")
+	}
+
+	secret, err := m.DecryptSecret(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if secret != "test" {
+		t.Fatalf("Expected decrypted secret %q, got %q", "test", secret)
+	}
+}
+
+func TestMfaRecoveryCodes(t *testing.T) {
+	m := models.Mfa{}
+
+	m.SetRecoveryCodes([]string{"code1", "code2"})
+
+	if len(m.RecoveryCodes) != 2 {
+		t.Fatalf("Expected 2 recovery codes, got %v", m.RecoveryCodes)
+	}
+
+	for _, c := range m.RecoveryCodes {
+		if c == "code1" || c == "code2" {
+			t.Fatalf("Expected the recovery codes to be hashed, got %v", m.RecoveryCodes)
+		}
+	}
+
+	if m.UseRecoveryCode("missing") {
+		t.Fatal("Expected the missing code to not be used")
+	}
+
+	if !m.UseRecoveryCode("code2") {
+		t.Fatal("Expected code2 to be used")
+	}
+
+	if m.UseRecoveryCode("code2") {
+		t.Fatal("Expected code2 to not be reusable")
+	}
+
+	if len(m.RecoveryCodes) != 1 {
+		t.Fatalf("Expected 1 remaining recovery code, got %v", m.RecoveryCodes)
+	}
+
+	if !m.UseRecoveryCode("code1") {
+		t.Fatal("Expected code1 to be used")
+	}
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+var _ Model = (*OTP)(nil)
+
+// OTP defines a single auth record one-time password request.
+type OTP struct {
+	BaseModel
+
+	CollectionId string `db:"collectionId" json:"collectionId"`
+	RecordId     string `db:"recordId" json:"recordId"`
+
+	// Password is the sha256 hash of the emailed one-time password.
+	Password string `db:"password" json:"-"`
+
+	// SentTo is the email address to which the one-time password was sent.
+	SentTo string `db:"sentTo" json:"sentTo"`
+
+	// Attempts is the number of the failed password verification attempts.
+	Attempts int `db:"attempts" json:"attempts"`
+}
+
+// TableName returns the OTP model SQL table name.
+func (m *OTP) TableName() string {
+	return "_otps"
+}
+
+// SetPassword stores the hash of the provided plain one-time password.
+func (m *OTP) SetPassword(password string) {
+	m.Password = hashOTPPassword(password)
+}
+
+// ValidatePassword validates a plain one-time password against the model's hash.
+func (m *OTP) ValidatePassword(password string) bool {
+	if m.Password == "" || password == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(m.Password), []byte(hashOTPPassword(password))) == 1
+}
+
+// HasExpired checks whether the OTP model is older than the specified max duration.
+func (m *OTP) HasExpired(maxElapsed time.Duration) bool {
+	expiresAt := m.Created.Time().Add(maxElapsed)
+
+	return types.NowDateTime().Time().After(expiresAt)
+}
+
+func hashOTPPassword(password string) string {
+	h := sha256.Sum256([]byte(password))
+	return hex.EncodeToString(h[:])
+}
@@ -0,0 +1,68 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestOTPTableName(t *testing.T) {
+	m := models.OTP{}
+	if m.TableName() != "_otps" {
+		t.Fatalf("Unexpected table name, got %q", m.TableName())
+	}
+}
+
+func TestOTPPassword(t *testing.T) {
+	m := models.OTP{}
+
+	if m.ValidatePassword("") {
+		t.Fatal("Expected the empty password to be invalid")
+	}
+
+	m.SetPassword("123456")
+
+	if m.Password == "" || m.Password == "123456" {
+		t.Fatalf("Expected the password to be hashed, got %q", m.Password)
+	}
+
+	scenarios := []struct {
+		password string
+		expected bool
+	}{
+		{"", false},
+		{"12345", false},
+		{"654321", false},
+		{"123456", true},
+	}
+
+	for _, s := range scenarios {
+		result := m.ValidatePassword(s.password)
+		if result != s.expected {
+			t.Errorf("(%q) Expected %v, got %v", s.password, s.expected, result)
+		}
+	}
+}
+
+func TestOTPHasExpired(t *testing.T) {
+	m := models.OTP{}
+	m.Created, _ = types.ParseDateTime(time.Now().Add(-5 * time.Minute))
+
+	scenarios := []struct {
+		maxElapsed time.Duration
+		expected   bool
+	}{
+		{time.Minute, true},
+		{4 * time.Minute, true},
+		{6 * time.Minute, false},
+	}
+
+	for i, s := range scenarios {
+		result := m.HasExpired(s.maxElapsed)
+		if result != s.expected {
+			t.Errorf("(%d) Expected %v, got %v", i, s.expected, result)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/pocketbase/dbx"
@@ -34,6 +35,8 @@ type Record struct {
 	loaded                bool
 	originalData          map[string]any    // the original (aka. first loaded) model data
 	expand                *store.Store[any] // expanded relations
+	placeholders          map[string]any    // file field images placeholders
+	exportFields          []string          // the public export fields projection (all if empty)
 	data                  *store.Store[any] // any custom data in addition to the base model fields
 }
 
@@ -167,6 +170,18 @@ func (m *Record) SetExpand(expand map[string]any) {
 	m.expand.Reset(expand)
 }
 
+// Placeholders returns a shallow copy of the current Record model
+// file field => filename => image placeholder data.
+func (m *Record) Placeholders() map[string]any {
+	return shallowCopy(m.placeholders)
+}
+
+// SetPlaceholders shallow copies the provided file field => filename => image
+// placeholder data to the current Record model's placeholders.
+func (m *Record) SetPlaceholders(placeholders map[string]any) {
+	m.placeholders = shallowCopy(placeholders)
+}
+
 // MergeExpand merges recursively the provided expand data into
 // the current model's expand (if any).
 //
@@ -285,6 +300,21 @@ func (m *Record) WithUnkownData(state bool) {
 	m.exportUnknown = state
 }
 
+// ExportFields returns the public export fields projection of the record.
+func (m *Record) ExportFields() []string {
+	return append([]string(nil), m.exportFields...)
+}
+
+// SetExportFields limits the public export/serialization of the record
+// only to the specified fields (call it without arguments to reset it).
+//
+// The fields could be dot separated paths to limit also the exported
+// fields of the expanded relations, eg. "id,title,expand.author.name".
+// Use "*" to export all fields at the specific level, eg. "*,expand.author.name".
+func (m *Record) SetExportFields(fields ...string) {
+	m.exportFields = list.NonzeroUniques(fields)
+}
+
 // Set sets the provided key-value data pair for the current Record model.
 //
 // If the record collection has field with name matching the provided "key",
@@ -491,6 +521,15 @@ func (m *Record) PublicExport() map[string]any {
 		result[schema.FieldNameExpand] = m.expand.GetAll()
 	}
 
+	// add images placeholders (if set)
+	if len(m.placeholders) > 0 {
+		result[schema.FieldNamePlaceholders] = m.Placeholders()
+	}
+
+	if len(m.exportFields) > 0 {
+		return pickFields(result, parseFieldsTree(m.exportFields))
+	}
+
 	return result
 }
 
@@ -657,6 +696,97 @@ func shallowCopy(data map[string]any) map[string]any {
 	return result
 }
 
+// fieldsTree is a parsed fields projection where each key is a field
+// name and its value are the nested fields to pick (nil for all).
+type fieldsTree map[string]fieldsTree
+
+// parseFieldsTree parses the provided dot separated fields paths.
+//
+// A path selecting the entire value takes precedence over
+// the paths selecting its nested fields (eg. "a,a.b" => "a").
+func parseFieldsTree(fields []string) fieldsTree {
+	tree := fieldsTree{}
+
+	for _, field := range fields {
+		parts := strings.Split(strings.TrimSpace(field), ".")
+		current := tree
+
+		for i, part := range parts {
+			if part == "" {
+				break
+			}
+
+			sub, exists := current[part]
+
+			if i == len(parts)-1 {
+				current[part] = nil
+				break
+			}
+
+			if exists && sub == nil {
+				break // the entire value is already selected
+			}
+
+			if !exists {
+				sub = fieldsTree{}
+				current[part] = sub
+			}
+
+			current = sub
+		}
+	}
+
+	return tree
+}
+
+// pickFields returns a new map containing only the data fields
+// from the provided tree (including the nested expanded records fields).
+func pickFields(data map[string]any, tree fieldsTree) map[string]any {
+	result := make(map[string]any, len(tree))
+
+	if _, ok := tree["*"]; ok {
+		for k, v := range data {
+			result[k] = v
+		}
+	}
+
+	for key, sub := range tree {
+		if key == "*" {
+			continue
+		}
+
+		v, ok := data[key]
+		if !ok {
+			continue
+		}
+
+		if sub == nil {
+			result[key] = v
+		} else {
+			result[key] = pickNestedFields(v, sub)
+		}
+	}
+
+	return result
+}
+
+func pickNestedFields(value any, tree fieldsTree) any {
+	switch v := value.(type) {
+	case *Record:
+		return pickFields(v.PublicExport(), tree)
+	case []*Record:
+		result := make([]map[string]any, len(v))
+		for i, r := range v {
+			result[i] = pickFields(r.PublicExport(), tree)
+		}
+		return result
+	case map[string]any:
+		return pickFields(v, tree)
+	default:
+		return value // not an object
+	}
+}
+
 func (m *Record) extractUnknownData(data map[string]any) map[string]any {
 	knownFields := map[string]struct{}{}
 
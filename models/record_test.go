@@ -408,6 +408,132 @@ func TestRecordSetAndGetExpand(t *testing.T) {
 	}
 }
 
+func TestRecordSetAndGetPlaceholders(t *testing.T) {
+	collection := &models.Collection{}
+	m := models.NewRecord(collection)
+
+	if placeholders := m.Placeholders(); len(placeholders) != 0 {
+		t.Fatalf("Expected empty placeholders, got %v", placeholders)
+	}
+
+	data := map[string]any{"test": 123}
+
+	m.SetPlaceholders(data)
+
+	// change the original data to check if it was shallow copied
+	data["test"] = 456
+
+	placeholders := m.Placeholders()
+	if v, ok := placeholders["test"]; !ok || v != 123 {
+		t.Fatalf("Expected placeholders.test to be %v, got %v", 123, v)
+	}
+
+	raw, err := m.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(raw, []byte(`"@placeholders":{"test":123}`)) {
+		t.Fatalf("Expected the placeholders to be exported, got %s", raw)
+	}
+}
+
+func TestRecordSetAndGetExportFields(t *testing.T) {
+	relCollection := &models.Collection{
+		Name: "rel",
+		Schema: schema.NewSchema(
+			&schema.SchemaField{Name: "title", Type: schema.FieldTypeText},
+			&schema.SchemaField{Name: "content", Type: schema.FieldTypeText},
+		),
+	}
+	relCollection.Id = "rel_id"
+
+	collection := &models.Collection{
+		Name: "test",
+		Schema: schema.NewSchema(
+			&schema.SchemaField{Name: "title", Type: schema.FieldTypeText},
+			&schema.SchemaField{Name: "content", Type: schema.FieldTypeText},
+		),
+	}
+	collection.Id = "test_id"
+
+	newRel := func(id string) *models.Record {
+		r := models.NewRecord(relCollection)
+		r.Id = id
+		r.Set("title", "title_"+id)
+		r.Set("content", "content_"+id)
+		return r
+	}
+
+	nested := newRel("nested")
+
+	one := newRel("one")
+	one.SetExpand(map[string]any{"nested": nested})
+
+	m := models.NewRecord(collection)
+	m.Id = "record"
+	m.Set("title", "title_record")
+	m.Set("content", "content_record")
+	m.SetExpand(map[string]any{
+		"one":  one,
+		"many": []*models.Record{newRel("a"), newRel("b")},
+	})
+
+	scenarios := []struct {
+		name     string
+		fields   []string
+		expected string
+	}{
+		{
+			"no projection",
+			nil,
+			`{"collectionId":"test_id","collectionName":"test","content":"content_record","created":"","expand":{"many":[{"collectionId":"rel_id","collectionName":"rel","content":"content_a","created":"","id":"a","title":"title_a","updated":""},{"collectionId":"rel_id","collectionName":"rel","content":"content_b","created":"","id":"b","title":"title_b","updated":""}],"one":{"collectionId":"rel_id","collectionName":"rel","content":"content_one","created":"","expand":{"nested":{"collectionId":"rel_id","collectionName":"rel","content":"content_nested","created":"","id":"nested","title":"title_nested","updated":""}},"id":"one","title":"title_one","updated":""}},"id":"record","title":"title_record","updated":""}`,
+		},
+		{
+			"top level fields",
+			[]string{"id", "title", "missing", ""},
+			`{"id":"record","title":"title_record"}`,
+		},
+		{
+			"nested expand fields",
+			[]string{"id", "expand.one.title", "expand.one.expand.nested.id", "expand.many.id"},
+			`{"expand":{"many":[{"id":"a"},{"id":"b"}],"one":{"expand":{"nested":{"id":"nested"}},"title":"title_one"}},"id":"record"}`,
+		},
+		{
+			"entire value takes precedence over its nested fields",
+			[]string{"expand.one.id", "expand.one", "expand.many.id"},
+			`{"expand":{"many":[{"id":"a"},{"id":"b"}],"one":{"collectionId":"rel_id","collectionName":"rel","content":"content_one","created":"","expand":{"nested":{"collectionId":"rel_id","collectionName":"rel","content":"content_nested","created":"","id":"nested","title":"title_nested","updated":""}},"id":"one","title":"title_one","updated":""}}}`,
+		},
+		{
+			"wildcard",
+			[]string{"*", "expand.one.id", "expand.many.*"},
+			`{"collectionId":"test_id","collectionName":"test","content":"content_record","created":"","expand":{"many":[{"collectionId":"rel_id","collectionName":"rel","content":"content_a","created":"","id":"a","title":"title_a","updated":""},{"collectionId":"rel_id","collectionName":"rel","content":"content_b","created":"","id":"b","title":"title_b","updated":""}],"one":{"id":"one"}},"id":"record","title":"title_record","updated":""}`,
+		},
+	}
+
+	for _, s := range scenarios {
+		m.SetExportFields(s.fields...)
+
+		raw, err := m.MarshalJSON()
+		if err != nil {
+			t.Fatalf("[%s] %v", s.name, err)
+		}
+
+		if string(raw) != s.expected {
+			t.Errorf("[%s] Expected \n%s, \ngot \n%s", s.name, s.expected, raw)
+		}
+	}
+
+	m.SetExportFields("a", "b", "a")
+	if fields := m.ExportFields(); len(fields) != 2 || fields[0] != "a" || fields[1] != "b" {
+		t.Fatalf("Expected export fields [a b], got %v", fields)
+	}
+
+	m.SetExportFields()
+	if fields := m.ExportFields(); len(fields) != 0 {
+		t.Fatalf("Expected empty export fields, got %v", fields)
+	}
+}
+
 func TestRecordMergeExpand(t *testing.T) {
 	collection := &models.Collection{}
 	m := models.NewRecord(collection)
@@ -38,6 +38,7 @@ const (
 	FieldNameCollectionId           string = "collectionId"
 	FieldNameCollectionName         string = "collectionName"
 	FieldNameExpand                 string = "expand"
+	FieldNamePlaceholders           string = "@placeholders"
 	FieldNameUsername               string = "username"
 	FieldNameEmail                  string = "email"
 	FieldNameEmailVisibility        string = "emailVisibility"
@@ -580,6 +581,27 @@ type FileOptions struct {
 	MaxSize   int      `form:"maxSize" json:"maxSize"` // in bytes
 	MimeTypes []string `form:"mimeTypes" json:"mimeTypes"`
 	Thumbs    []string `form:"thumbs" json:"thumbs"`
+
+	// ThumbFormats is an ordered list of preferred image formats (eg. "jpeg", "png")
+	// to which the served images and thumbs could be converted based on the
+	// "format" query parameter or the request Accept header.
+	//
+	// Only formats registered with [filesystem.RegisterImageFormat] are allowed
+	// (by default only "jpeg", "png" and "gif" since there are no builtin
+	// encoders for formats like webp and avif).
+	ThumbFormats []string `form:"thumbFormats" json:"thumbFormats"`
+
+	// ThumbQuality is the quality of the lossy converted images
+	// (0 means [filesystem.DefaultImageQuality]).
+	ThumbQuality int `form:"thumbQuality" json:"thumbQuality"`
+
+	// Placeholders indicates whether to generate BlurHash and dominant
+	// color placeholders for the uploaded images.
+	Placeholders bool `form:"placeholders" json:"placeholders"`
+
+	// Protected indicates whether the file access requires a
+	// valid file token and satisfied collection ViewRule.
+	Protected bool `form:"protected" json:"protected"`
 }
 
 func (o FileOptions) Validate() error {
@@ -590,6 +612,11 @@ func (o FileOptions) Validate() error {
 			validation.NotIn("0x0", "0x0t", "0x0b", "0x0f"),
 			validation.Match(filesystem.ThumbSizeRegex),
 		)),
+		validation.Field(&o.ThumbFormats, validation.Each(
+			validation.In(list.ToInterfaceSlice(filesystem.ImageFormatNames())...).
+				Error("Unsupported image format."),
+		)),
+		validation.Field(&o.ThumbQuality, validation.Min(0), validation.Max(100)),
 	)
 }
 
@@ -376,6 +376,15 @@ func TestSchemaFieldValidate(t *testing.T) {
 			},
 			[]string{},
 		},
+		{
+			"valid name (placeholders)",
+			schema.SchemaField{
+				Type: schema.FieldTypeText,
+				Id:   "1234567890",
+				Name: "placeholders",
+			},
+			[]string{},
+		},
 		{
 			"unique check for type file",
 			schema.SchemaField{
@@ -504,7 +513,7 @@ func TestSchemaFieldInitOptions(t *testing.T) {
 		{
 			schema.SchemaField{Type: schema.FieldTypeFile},
 			false,
-			`{"system":false,"id":"","name":"","type":"file","required":false,"unique":false,"options":{"maxSelect":0,"maxSize":0,"mimeTypes":null,"thumbs":null}}`,
+			`{"system":false,"id":"","name":"","type":"file","required":false,"unique":false,"options":{"maxSelect":0,"maxSize":0,"mimeTypes":null,"thumbs":null,"thumbFormats":null,"thumbQuality":0,"placeholders":false,"protected":false}}`,
 		},
 		{
 			schema.SchemaField{Type: schema.FieldTypeRelation},
@@ -2048,6 +2057,45 @@ func TestFileOptionsValidate(t *testing.T) {
 			},
 			[]string{},
 		},
+		{
+			"invalid thumb formats and quality",
+			schema.FileOptions{
+				MaxSize:      1,
+				MaxSelect:    2,
+				ThumbFormats: []string{"jpeg", "missing"},
+				ThumbQuality: 101,
+			},
+			[]string{"thumbFormats", "thumbQuality"},
+		},
+		{
+			"formats without registered encoder",
+			schema.FileOptions{
+				MaxSize:      1,
+				MaxSelect:    2,
+				ThumbFormats: []string{"webp", "avif"},
+			},
+			[]string{"thumbFormats"},
+		},
+		{
+			"negative thumb quality",
+			schema.FileOptions{
+				MaxSize:      1,
+				MaxSelect:    2,
+				ThumbQuality: -1,
+			},
+			[]string{"thumbQuality"},
+		},
+		{
+			"valid thumb formats, quality and placeholders",
+			schema.FileOptions{
+				MaxSize:      1,
+				MaxSelect:    2,
+				ThumbFormats: []string{"jpeg", "png", "gif"},
+				ThumbQuality: 100,
+				Placeholders: true,
+			},
+			[]string{},
+		},
 	}
 
 	checkFieldOptionsScenarios(t, scenarios)
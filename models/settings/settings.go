@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 	"sync"
+	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
@@ -28,12 +30,25 @@ type Settings struct {
 	Smtp SmtpConfig `form:"smtp" json:"smtp"`
 	S3   S3Config   `form:"s3" json:"s3"`
 
+	Storage StorageConfig `form:"storage" json:"storage"`
+
+	Backups BackupsConfig `form:"backups" json:"backups"`
+
+	RateLimits RateLimitsConfig `form:"rateLimits" json:"rateLimits"`
+
+	Realtime RealtimeConfig `form:"realtime" json:"realtime"`
+
 	AdminAuthToken           TokenConfig `form:"adminAuthToken" json:"adminAuthToken"`
 	AdminPasswordResetToken  TokenConfig `form:"adminPasswordResetToken" json:"adminPasswordResetToken"`
+	AdminMfaToken            TokenConfig `form:"adminMfaToken" json:"adminMfaToken"`
+	AdminFileToken           TokenConfig `form:"adminFileToken" json:"adminFileToken"`
 	RecordAuthToken          TokenConfig `form:"recordAuthToken" json:"recordAuthToken"`
 	RecordPasswordResetToken TokenConfig `form:"recordPasswordResetToken" json:"recordPasswordResetToken"`
 	RecordEmailChangeToken   TokenConfig `form:"recordEmailChangeToken" json:"recordEmailChangeToken"`
 	RecordVerificationToken  TokenConfig `form:"recordVerificationToken" json:"recordVerificationToken"`
+	RecordMfaToken           TokenConfig `form:"recordMfaToken" json:"recordMfaToken"`
+	RecordOTPToken           TokenConfig `form:"recordOTPToken" json:"recordOTPToken"`
+	RecordFileToken          TokenConfig `form:"recordFileToken" json:"recordFileToken"`
 
 	// Deprecated: Will be removed in v0.9+
 	EmailAuth EmailAuthConfig `form:"emailAuth" json:"emailAuth"`
@@ -69,10 +84,26 @@ func New() *Settings {
 			VerificationTemplate:       defaultVerificationTemplate,
 			ResetPasswordTemplate:      defaultResetPasswordTemplate,
 			ConfirmEmailChangeTemplate: defaultConfirmEmailChangeTemplate,
+			OTPTemplate:                defaultOTPTemplate,
 		},
 		Logs: LogsConfig{
 			MaxDays: 5,
 		},
+		RateLimits: RateLimitsConfig{
+			Enabled: false,
+			Rules: []RateLimitRule{
+				{Label: "*:auth", MaxRequests: 5, Duration: 10},
+				{Label: "*:requestPasswordReset", MaxRequests: 2, Duration: 60},
+				{Label: "*:requestVerification", MaxRequests: 2, Duration: 60},
+				{Label: "*:requestEmailChange", MaxRequests: 2, Duration: 60},
+				{Label: "*:requestOTP", MaxRequests: 2, Duration: 60},
+				{Label: "*:create", MaxRequests: 20, Duration: 5},
+				{Label: "/api/batch", MaxRequests: 3, Duration: 1},
+			},
+		},
+		Realtime: RealtimeConfig{
+			Channels: []RealtimeChannel{},
+		},
 		Smtp: SmtpConfig{
 			Enabled:  false,
 			Host:     "smtp.example.com",
@@ -89,6 +120,14 @@ func New() *Settings {
 			Secret:   security.RandomString(50),
 			Duration: 1800, // 30 minutes,
 		},
+		AdminMfaToken: TokenConfig{
+			Secret:   security.RandomString(50),
+			Duration: 300, // 5 minutes,
+		},
+		AdminFileToken: TokenConfig{
+			Secret:   security.RandomString(50),
+			Duration: 120, // 2 minutes,
+		},
 		RecordAuthToken: TokenConfig{
 			Secret:   security.RandomString(50),
 			Duration: 1209600, // 14 days,
@@ -105,6 +144,18 @@ func New() *Settings {
 			Secret:   security.RandomString(50),
 			Duration: 1800, // 30 minutes,
 		},
+		RecordMfaToken: TokenConfig{
+			Secret:   security.RandomString(50),
+			Duration: 300, // 5 minutes,
+		},
+		RecordOTPToken: TokenConfig{
+			Secret:   security.RandomString(50),
+			Duration: 180, // 3 minutes,
+		},
+		RecordFileToken: TokenConfig{
+			Secret:   security.RandomString(50),
+			Duration: 120, // 2 minutes,
+		},
 		GoogleAuth: AuthProviderConfig{
 			Enabled: false,
 		},
@@ -169,12 +220,20 @@ func (s *Settings) Validate() error {
 		validation.Field(&s.Logs),
 		validation.Field(&s.AdminAuthToken),
 		validation.Field(&s.AdminPasswordResetToken),
+		validation.Field(&s.AdminMfaToken),
+		validation.Field(&s.AdminFileToken),
 		validation.Field(&s.RecordAuthToken),
 		validation.Field(&s.RecordPasswordResetToken),
 		validation.Field(&s.RecordEmailChangeToken),
 		validation.Field(&s.RecordVerificationToken),
+		validation.Field(&s.RecordMfaToken),
+		validation.Field(&s.RecordOTPToken),
+		validation.Field(&s.RecordFileToken),
 		validation.Field(&s.Smtp),
 		validation.Field(&s.S3),
+		validation.Field(&s.Backups),
+		validation.Field(&s.RateLimits),
+		validation.Field(&s.Realtime),
 		validation.Field(&s.GoogleAuth),
 		validation.Field(&s.FacebookAuth),
 		validation.Field(&s.GithubAuth),
@@ -228,12 +287,18 @@ func (s *Settings) RedactClone() (*Settings, error) {
 	sensitiveFields := []*string{
 		&clone.Smtp.Password,
 		&clone.S3.Secret,
+		&clone.Backups.S3.Secret,
 		&clone.AdminAuthToken.Secret,
 		&clone.AdminPasswordResetToken.Secret,
+		&clone.AdminMfaToken.Secret,
+		&clone.AdminFileToken.Secret,
 		&clone.RecordAuthToken.Secret,
 		&clone.RecordPasswordResetToken.Secret,
 		&clone.RecordEmailChangeToken.Secret,
 		&clone.RecordVerificationToken.Secret,
+		&clone.RecordMfaToken.Secret,
+		&clone.RecordOTPToken.Secret,
+		&clone.RecordFileToken.Secret,
 		&clone.GoogleAuth.ClientSecret,
 		&clone.FacebookAuth.ClientSecret,
 		&clone.GithubAuth.ClientSecret,
@@ -372,6 +437,32 @@ func (c S3Config) Validate() error {
 
 // -------------------------------------------------------------------
 
+type StorageConfig struct {
+	// Dedup enables the content-addressed storage of the new record files.
+	//
+	// When enabled, the uploaded files content is stored only once
+	// (identified by its SHA-256 hash) and shared between all records
+	// that reference it. The records still keep their own filenames.
+	Dedup bool `form:"dedup" json:"dedup"`
+}
+
+// -------------------------------------------------------------------
+
+type BackupsConfig struct {
+	// S3 is an optional S3 storage config specifying where to store the app backups
+	// (when disabled the backups are stored locally in the "pb_data/backups" dir).
+	S3 S3Config `form:"s3" json:"s3"`
+}
+
+// Validate makes BackupsConfig validatable by implementing [validation.Validatable] interface.
+func (c BackupsConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.S3),
+	)
+}
+
+// -------------------------------------------------------------------
+
 type MetaConfig struct {
 	AppName                    string        `form:"appName" json:"appName"`
 	AppUrl                     string        `form:"appUrl" json:"appUrl"`
@@ -381,6 +472,7 @@ type MetaConfig struct {
 	VerificationTemplate       EmailTemplate `form:"verificationTemplate" json:"verificationTemplate"`
 	ResetPasswordTemplate      EmailTemplate `form:"resetPasswordTemplate" json:"resetPasswordTemplate"`
 	ConfirmEmailChangeTemplate EmailTemplate `form:"confirmEmailChangeTemplate" json:"confirmEmailChangeTemplate"`
+	OTPTemplate                EmailTemplate `form:"otpTemplate" json:"otpTemplate"`
 }
 
 // Validate makes MetaConfig validatable by implementing [validation.Validatable] interface.
@@ -393,6 +485,7 @@ func (c MetaConfig) Validate() error {
 		validation.Field(&c.VerificationTemplate, validation.Required),
 		validation.Field(&c.ResetPasswordTemplate, validation.Required),
 		validation.Field(&c.ConfirmEmailChangeTemplate, validation.Required),
+		validation.Field(&c.OTPTemplate, validation.Required, validation.By(checkOTPTemplate)),
 	)
 }
 
@@ -419,6 +512,16 @@ func (t EmailTemplate) Validate() error {
 	)
 }
 
+func checkOTPTemplate(value any) error {
+	v, _ := value.(EmailTemplate)
+
+	if err := checkPlaceholderParams(EmailPlaceholderOTP)(v.Body); err != nil {
+		return validation.Errors{"body": err}
+	}
+
+	return nil
+}
+
 func checkPlaceholderParams(params ...string) validation.RuleFunc {
 	return func(value any) error {
 		v, _ := value.(string)
@@ -484,12 +587,195 @@ func (t EmailTemplate) Resolve(
 
 type LogsConfig struct {
 	MaxDays int `form:"maxDays" json:"maxDays"`
+
+	// AuditCollections is a list with the ids or names of the collections
+	// whose record changes are stored in the audit logs.
+	AuditCollections []string `form:"auditCollections" json:"auditCollections"`
 }
 
 // Validate makes LogsConfig validatable by implementing [validation.Validatable] interface.
 func (c LogsConfig) Validate() error {
 	return validation.ValidateStruct(&c,
 		validation.Field(&c.MaxDays, validation.Min(0)),
+		validation.Field(&c.AuditCollections, validation.Each(validation.Required, validation.Length(1, 255))),
+	)
+}
+
+// IsAuditEnabled checks whether the audit logs are enabled
+// for the collection with the specified id or name.
+//
+// Audit logs are never stored if the logs retention is disabled (aka. MaxDays is 0).
+func (c LogsConfig) IsAuditEnabled(collectionIdOrName ...string) bool {
+	if c.MaxDays == 0 {
+		return false
+	}
+
+	for _, v := range c.AuditCollections {
+		for _, idOrName := range collectionIdOrName {
+			if v != "" && v == idOrName {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// -------------------------------------------------------------------
+
+type RateLimitsConfig struct {
+	Enabled bool            `form:"enabled" json:"enabled"`
+	Rules   []RateLimitRule `form:"rules" json:"rules"`
+}
+
+// Validate makes RateLimitsConfig validatable by implementing [validation.Validatable] interface.
+func (c RateLimitsConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(
+			&c.Rules,
+			validation.When(c.Enabled, validation.Required),
+			validation.By(checkUniqueRuleLabels),
+		),
+	)
+}
+
+// FindRule returns the first rule matching one of the provided labels
+// (the labels are checked in the order they are specified).
+func (c RateLimitsConfig) FindRule(labels ...string) (RateLimitRule, bool) {
+	for _, label := range labels {
+		for _, rule := range c.Rules {
+			if rule.Label == label {
+				return rule, true
+			}
+		}
+	}
+
+	return RateLimitRule{}, false
+}
+
+func checkUniqueRuleLabels(value any) error {
+	rules, _ := value.([]RateLimitRule)
+
+	existing := make(map[string]struct{}, len(rules))
+
+	for i, rule := range rules {
+		if _, ok := existing[rule.Label]; ok {
+			return validation.Errors{
+				fmt.Sprint(i): validation.Errors{
+					"label": validation.NewError("validation_duplicated_rate_limit_label", "Rate limit rule with the same label already exists."),
+				},
+			}
+		}
+		existing[rule.Label] = struct{}{}
+	}
+
+	return nil
+}
+
+var rateLimitLabelRegex = regexp.MustCompile(`^(/[\w\-/.]*|(\*|\w+):\w+)$`)
+
+type RateLimitRule struct {
+	// Label is the identifier of the rule and could be in one of the following formats:
+	//   - "/api/realtime" - matches requests with the specified path (or path prefix if it ends with "/")
+	//   - "users:auth" - matches the "auth" action of the "users" collection
+	//   - "*:create" - matches the "create" action of any collection
+	Label string `form:"label" json:"label"`
+
+	// MaxRequests is the max allowed number of requests per Duration.
+	MaxRequests int `form:"maxRequests" json:"maxRequests"`
+
+	// Duration specifies the sliding window interval (in seconds).
+	Duration int64 `form:"duration" json:"duration"`
+}
+
+// Validate makes RateLimitRule validatable by implementing [validation.Validatable] interface.
+func (c RateLimitRule) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Label, validation.Required, validation.Match(rateLimitLabelRegex)),
+		validation.Field(&c.MaxRequests, validation.Required, validation.Min(1)),
+		validation.Field(&c.Duration, validation.Required, validation.Min(int64(1))),
+	)
+}
+
+// DurationTime returns the rule's Duration as [time.Duration].
+func (c RateLimitRule) DurationTime() time.Duration {
+	return time.Duration(c.Duration) * time.Second
+}
+
+// -------------------------------------------------------------------
+
+type RealtimeConfig struct {
+	Channels []RealtimeChannel `form:"channels" json:"channels"`
+}
+
+// Validate makes RealtimeConfig validatable by implementing [validation.Validatable] interface.
+func (c RealtimeConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Channels, validation.By(checkUniqueChannelNames)),
+	)
+}
+
+// FindChannel returns the custom realtime channel with the specified name.
+func (c RealtimeConfig) FindChannel(name string) (RealtimeChannel, bool) {
+	for _, channel := range c.Channels {
+		if channel.Name == name {
+			return channel, true
+		}
+	}
+
+	return RealtimeChannel{}, false
+}
+
+func checkUniqueChannelNames(value any) error {
+	channels, _ := value.([]RealtimeChannel)
+
+	existing := make(map[string]struct{}, len(channels))
+
+	for i, channel := range channels {
+		if _, ok := existing[channel.Name]; ok {
+			return validation.Errors{
+				fmt.Sprint(i): validation.Errors{
+					"name": validation.NewError("validation_duplicated_realtime_channel", "Realtime channel with the same name already exists."),
+				},
+			}
+		}
+		existing[channel.Name] = struct{}{}
+	}
+
+	return nil
+}
+
+var realtimeChannelNameRegex = regexp.MustCompile(`^[\w\-\.]+$`)
+
+type RealtimeChannel struct {
+	// Name is the unique identifier of the channel
+	// (clients subscribe to it with the "@channel/{name}" topic).
+	Name string `form:"name" json:"name"`
+
+	// SubscribeRule is the filter rule that the client must satisfy
+	// in order to receive the channel messages.
+	//
+	// Similar to the collection API rules, nil means "admins only"
+	// and empty string means "everyone".
+	SubscribeRule *string `form:"subscribeRule" json:"subscribeRule"`
+
+	// PublishRule is the filter rule that the request must satisfy
+	// in order to publish to the channel via the HTTP API
+	// (the published payload is available as "@request.data.*").
+	//
+	// Similar to the collection API rules, nil means "admins only"
+	// and empty string means "everyone".
+	PublishRule *string `form:"publishRule" json:"publishRule"`
+
+	// Presence enables the channel members tracking and the
+	// "join"/"leave" messages for the connected auth records.
+	Presence bool `form:"presence" json:"presence"`
+}
+
+// Validate makes RealtimeChannel validatable by implementing [validation.Validatable] interface.
+func (c RealtimeChannel) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Name, validation.Required, validation.Length(1, 100), validation.Match(realtimeChannelNameRegex)),
 	)
 }
 
@@ -6,6 +6,7 @@ const (
 	EmailPlaceholderAppUrl    string = "{APP_URL}"
 	EmailPlaceholderToken     string = "{TOKEN}"
 	EmailPlaceholderActionUrl string = "{ACTION_URL}"
+	EmailPlaceholderOTP       string = "{OTP}"
 )
 
 var defaultVerificationTemplate = EmailTemplate{
@@ -52,3 +53,21 @@ var defaultConfirmEmailChangeTemplate = EmailTemplate{
 </p>`,
 	ActionUrl: EmailPlaceholderAppUrl + "/_/#/auth/confirm-email-change/" + EmailPlaceholderToken,
 }
+
+// note: the default OTP action url (aka. the magic link) is expected
+// to be changed to point to the client application login page.
+var defaultOTPTemplate = EmailTemplate{
+	Subject: "OTP for " + EmailPlaceholderAppName,
+	Body: `<p>Hello,</p>
+<p>Your one-time password is: <strong>` + EmailPlaceholderOTP + `</strong></p>
+<p>Or click on the button below to sign in directly.</p>
+<p>
+  <a class="btn" href="` + EmailPlaceholderActionUrl + `" target="_blank" rel="noopener">Sign in</a>
+</p>
+<p><i>If you didn't ask for the one-time password, you can ignore this email.</i></p>
+<p>
+  Thanks,<br/>
+  ` + EmailPlaceholderAppName + ` team
+</p>`,
+	ActionUrl: EmailPlaceholderAppUrl + "/_/#/auth/auth-with-otp/" + EmailPlaceholderToken,
+}
@@ -6,11 +6,13 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/pocketbase/pocketbase/models/settings"
 	"github.com/pocketbase/pocketbase/tools/auth"
 	"github.com/pocketbase/pocketbase/tools/mailer"
+	"github.com/pocketbase/pocketbase/tools/types"
 )
 
 func TestSettingsValidate(t *testing.T) {
@@ -23,12 +25,22 @@ func TestSettingsValidate(t *testing.T) {
 	s.Smtp.Host = ""
 	s.S3.Enabled = true
 	s.S3.Endpoint = "invalid"
+	s.Backups.S3.Enabled = true
+	s.Backups.S3.Endpoint = "invalid"
+	s.RateLimits.Enabled = true
+	s.RateLimits.Rules = nil
+	s.Realtime.Channels = []settings.RealtimeChannel{{Name: "invalid name"}}
 	s.AdminAuthToken.Duration = -10
 	s.AdminPasswordResetToken.Duration = -10
+	s.AdminMfaToken.Duration = -10
+	s.AdminFileToken.Duration = -10
 	s.RecordAuthToken.Duration = -10
 	s.RecordPasswordResetToken.Duration = -10
 	s.RecordEmailChangeToken.Duration = -10
 	s.RecordVerificationToken.Duration = -10
+	s.RecordMfaToken.Duration = -10
+	s.RecordOTPToken.Duration = -10
+	s.RecordFileToken.Duration = -10
 	s.GoogleAuth.Enabled = true
 	s.GoogleAuth.ClientId = ""
 	s.FacebookAuth.Enabled = true
@@ -75,12 +87,20 @@ func TestSettingsValidate(t *testing.T) {
 		`"logs":{`,
 		`"smtp":{`,
 		`"s3":{`,
+		`"backups":{`,
+		`"rateLimits":{`,
+		`"realtime":{`,
 		`"adminAuthToken":{`,
 		`"adminPasswordResetToken":{`,
+		`"adminMfaToken":{`,
+		`"adminFileToken":{`,
 		`"recordAuthToken":{`,
 		`"recordPasswordResetToken":{`,
 		`"recordEmailChangeToken":{`,
 		`"recordVerificationToken":{`,
+		`"recordMfaToken":{`,
+		`"recordOTPToken":{`,
+		`"recordFileToken":{`,
 		`"googleAuth":{`,
 		`"facebookAuth":{`,
 		`"githubAuth":{`,
@@ -120,12 +140,21 @@ func TestSettingsMerge(t *testing.T) {
 	s2.Smtp.Enabled = true
 	s2.S3.Enabled = true
 	s2.S3.Endpoint = "test"
+	s2.Backups.S3.Enabled = true
+	s2.Backups.S3.Endpoint = "test_backups"
+	s2.RateLimits.Enabled = true
+	s2.RateLimits.Rules = []settings.RateLimitRule{{Label: "/api/test", MaxRequests: 1, Duration: 2}}
 	s2.AdminAuthToken.Duration = 1
 	s2.AdminPasswordResetToken.Duration = 2
 	s2.RecordAuthToken.Duration = 3
 	s2.RecordPasswordResetToken.Duration = 4
 	s2.RecordEmailChangeToken.Duration = 5
 	s2.RecordVerificationToken.Duration = 6
+	s2.AdminMfaToken.Duration = 7
+	s2.RecordMfaToken.Duration = 8
+	s2.RecordOTPToken.Duration = 9
+	s2.AdminFileToken.Duration = 10
+	s2.RecordFileToken.Duration = 11
 	s2.GoogleAuth.Enabled = true
 	s2.GoogleAuth.ClientId = "google_test"
 	s2.FacebookAuth.Enabled = true
@@ -220,12 +249,18 @@ func TestSettingsRedactClone(t *testing.T) {
 	// secrets
 	s1.Smtp.Password = testSecret
 	s1.S3.Secret = testSecret
+	s1.Backups.S3.Secret = testSecret
 	s1.AdminAuthToken.Secret = testSecret
 	s1.AdminPasswordResetToken.Secret = testSecret
+	s1.AdminMfaToken.Secret = testSecret
+	s1.AdminFileToken.Secret = testSecret
 	s1.RecordAuthToken.Secret = testSecret
 	s1.RecordPasswordResetToken.Secret = testSecret
 	s1.RecordEmailChangeToken.Secret = testSecret
 	s1.RecordVerificationToken.Secret = testSecret
+	s1.RecordMfaToken.Secret = testSecret
+	s1.RecordOTPToken.Secret = testSecret
+	s1.RecordFileToken.Secret = testSecret
 	s1.GoogleAuth.ClientSecret = testSecret
 	s1.FacebookAuth.ClientSecret = testSecret
 	s1.GithubAuth.ClientSecret = testSecret
@@ -531,6 +566,12 @@ func TestMetaConfigValidate(t *testing.T) {
 		Body:      "test" + settings.EmailPlaceholderActionUrl,
 	}
 
+	withOTPPlaceholdersTemplate := settings.EmailTemplate{
+		Subject:   "test",
+		ActionUrl: "http://example.com" + settings.EmailPlaceholderToken,
+		Body:      "test" + settings.EmailPlaceholderActionUrl + settings.EmailPlaceholderOTP,
+	}
+
 	scenarios := []struct {
 		config      settings.MetaConfig
 		expectError bool
@@ -550,6 +591,7 @@ func TestMetaConfigValidate(t *testing.T) {
 				VerificationTemplate:       invalidTemplate,
 				ResetPasswordTemplate:      invalidTemplate,
 				ConfirmEmailChangeTemplate: invalidTemplate,
+				OTPTemplate:                invalidTemplate,
 			},
 			true,
 		},
@@ -563,6 +605,21 @@ func TestMetaConfigValidate(t *testing.T) {
 				VerificationTemplate:       noPlaceholdersTemplate,
 				ResetPasswordTemplate:      noPlaceholdersTemplate,
 				ConfirmEmailChangeTemplate: noPlaceholdersTemplate,
+				OTPTemplate:                noPlaceholdersTemplate,
+			},
+			true,
+		},
+		// missing required otp template placeholder
+		{
+			settings.MetaConfig{
+				AppName:                    "test",
+				AppUrl:                     "https://example.com",
+				SenderName:                 "test",
+				SenderAddress:              "test@example.com",
+				VerificationTemplate:       withPlaceholdersTemplate,
+				ResetPasswordTemplate:      withPlaceholdersTemplate,
+				ConfirmEmailChangeTemplate: withPlaceholdersTemplate,
+				OTPTemplate:                withPlaceholdersTemplate,
 			},
 			true,
 		},
@@ -576,6 +633,7 @@ func TestMetaConfigValidate(t *testing.T) {
 				VerificationTemplate:       withPlaceholdersTemplate,
 				ResetPasswordTemplate:      withPlaceholdersTemplate,
 				ConfirmEmailChangeTemplate: withPlaceholdersTemplate,
+				OTPTemplate:                withOTPPlaceholdersTemplate,
 			},
 			false,
 		},
@@ -733,11 +791,248 @@ func TestLogsConfigValidate(t *testing.T) {
 			settings.LogsConfig{MaxDays: -10},
 			true,
 		},
+		{
+			settings.LogsConfig{MaxDays: 1, AuditCollections: []string{"demo1", ""}},
+			true,
+		},
 		// valid data
 		{
 			settings.LogsConfig{MaxDays: 1},
 			false,
 		},
+		{
+			settings.LogsConfig{MaxDays: 1, AuditCollections: []string{"demo1", "wsmn24bux7wo113"}},
+			false,
+		},
+	}
+
+	for i, scenario := range scenarios {
+		result := scenario.config.Validate()
+
+		if result != nil && !scenario.expectError {
+			t.Errorf("(%d) Didn't expect error, got %v", i, result)
+		}
+
+		if result == nil && scenario.expectError {
+			t.Errorf("(%d) Expected error, got nil", i)
+		}
+	}
+}
+
+func TestLogsConfigIsAuditEnabled(t *testing.T) {
+	scenarios := []struct {
+		name     string
+		config   settings.LogsConfig
+		idOrName []string
+		expected bool
+	}{
+		{"no audit collections", settings.LogsConfig{MaxDays: 1}, []string{"demo1"}, false},
+		{"no collection", settings.LogsConfig{MaxDays: 1, AuditCollections: []string{"demo1"}}, nil, false},
+		{"disabled logs retention", settings.LogsConfig{AuditCollections: []string{"demo1"}}, []string{"demo1"}, false},
+		{"non matching collection", settings.LogsConfig{MaxDays: 1, AuditCollections: []string{"demo1"}}, []string{"id2", "demo2"}, false},
+		{"matching collection", settings.LogsConfig{MaxDays: 1, AuditCollections: []string{"demo1"}}, []string{"id1", "demo1"}, true},
+	}
+
+	for _, s := range scenarios {
+		if v := s.config.IsAuditEnabled(s.idOrName...); v != s.expected {
+			t.Errorf("[%s] Expected %v, got %v", s.name, s.expected, v)
+		}
+	}
+}
+
+func TestBackupsConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		config      settings.BackupsConfig
+		expectError bool
+	}{
+		// zero values
+		{
+			settings.BackupsConfig{},
+			false,
+		},
+		// invalid s3 data
+		{
+			settings.BackupsConfig{
+				S3: settings.S3Config{Enabled: true},
+			},
+			true,
+		},
+		// valid s3 data
+		{
+			settings.BackupsConfig{
+				S3: settings.S3Config{
+					Enabled:   true,
+					Endpoint:  "https://example.com",
+					Bucket:    "test",
+					Region:    "test",
+					AccessKey: "test",
+					Secret:    "test",
+				},
+			},
+			false,
+		},
+	}
+
+	for i, scenario := range scenarios {
+		result := scenario.config.Validate()
+
+		if result != nil && !scenario.expectError {
+			t.Errorf("(%d) Didn't expect error, got %v", i, result)
+		}
+
+		if result == nil && scenario.expectError {
+			t.Errorf("(%d) Expected error, got nil", i)
+		}
+	}
+}
+
+func TestRateLimitsConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		config      settings.RateLimitsConfig
+		expectError bool
+	}{
+		// zero values (disabled)
+		{
+			settings.RateLimitsConfig{},
+			false,
+		},
+		// zero values (enabled)
+		{
+			settings.RateLimitsConfig{Enabled: true},
+			true,
+		},
+		// invalid rule data
+		{
+			settings.RateLimitsConfig{
+				Rules: []settings.RateLimitRule{{Label: "invalid", MaxRequests: 0, Duration: 0}},
+			},
+			true,
+		},
+		// duplicated labels
+		{
+			settings.RateLimitsConfig{
+				Enabled: true,
+				Rules: []settings.RateLimitRule{
+					{Label: "*:auth", MaxRequests: 1, Duration: 1},
+					{Label: "*:auth", MaxRequests: 2, Duration: 2},
+				},
+			},
+			true,
+		},
+		// valid data
+		{
+			settings.RateLimitsConfig{
+				Enabled: true,
+				Rules: []settings.RateLimitRule{
+					{Label: "*:auth", MaxRequests: 1, Duration: 1},
+					{Label: "users:auth", MaxRequests: 1, Duration: 1},
+					{Label: "/api/realtime", MaxRequests: 1, Duration: 1},
+					{Label: "/api/", MaxRequests: 1, Duration: 1},
+				},
+			},
+			false,
+		},
+	}
+
+	for i, scenario := range scenarios {
+		result := scenario.config.Validate()
+
+		if result != nil && !scenario.expectError {
+			t.Errorf("(%d) Didn't expect error, got %v", i, result)
+		}
+
+		if result == nil && scenario.expectError {
+			t.Errorf("(%d) Expected error, got nil", i)
+		}
+	}
+}
+
+func TestRateLimitsConfigFindRule(t *testing.T) {
+	config := settings.RateLimitsConfig{
+		Rules: []settings.RateLimitRule{
+			{Label: "*:auth", MaxRequests: 1, Duration: 1},
+			{Label: "users:auth", MaxRequests: 2, Duration: 2},
+			{Label: "/api/realtime", MaxRequests: 3, Duration: 3},
+		},
+	}
+
+	scenarios := []struct {
+		labels        []string
+		expectedFound bool
+		expectedMax   int
+	}{
+		{nil, false, 0},
+		{[]string{"missing"}, false, 0},
+		{[]string{"/api/realtime"}, true, 3},
+		{[]string{"users:auth", "*:auth"}, true, 2},
+		{[]string{"demo:auth", "*:auth"}, true, 1},
+	}
+
+	for i, s := range scenarios {
+		rule, found := config.FindRule(s.labels...)
+
+		if found != s.expectedFound {
+			t.Errorf("(%d) Expected found %v, got %v", i, s.expectedFound, found)
+			continue
+		}
+
+		if rule.MaxRequests != s.expectedMax {
+			t.Errorf("(%d) Expected rule with MaxRequests %d, got %d", i, s.expectedMax, rule.MaxRequests)
+		}
+	}
+}
+
+func TestRateLimitRuleDurationTime(t *testing.T) {
+	rule := settings.RateLimitRule{Duration: 3}
+
+	if d := rule.DurationTime(); d != 3*time.Second {
+		t.Fatalf("Expected %v, got %v", 3*time.Second, d)
+	}
+}
+
+func TestRealtimeConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		config      settings.RealtimeConfig
+		expectError bool
+	}{
+		// zero values
+		{
+			settings.RealtimeConfig{},
+			false,
+		},
+		// invalid channel data
+		{
+			settings.RealtimeConfig{
+				Channels: []settings.RealtimeChannel{{Name: ""}},
+			},
+			true,
+		},
+		{
+			settings.RealtimeConfig{
+				Channels: []settings.RealtimeChannel{{Name: "test/*"}},
+			},
+			true,
+		},
+		// duplicated names
+		{
+			settings.RealtimeConfig{
+				Channels: []settings.RealtimeChannel{
+					{Name: "test"},
+					{Name: "test", Presence: true},
+				},
+			},
+			true,
+		},
+		// valid data
+		{
+			settings.RealtimeConfig{
+				Channels: []settings.RealtimeChannel{
+					{Name: "test"},
+					{Name: "test-2.room_a", SubscribeRule: types.Pointer(""), PublishRule: types.Pointer("@request.auth.id != ''"), Presence: true},
+				},
+			},
+			false,
+		},
 	}
 
 	for i, scenario := range scenarios {
@@ -753,6 +1048,24 @@ func TestLogsConfigValidate(t *testing.T) {
 	}
 }
 
+func TestRealtimeConfigFindChannel(t *testing.T) {
+	config := settings.RealtimeConfig{
+		Channels: []settings.RealtimeChannel{
+			{Name: "a"},
+			{Name: "b", Presence: true},
+		},
+	}
+
+	if _, ok := config.FindChannel("missing"); ok {
+		t.Fatal("Expected channel missing to not be found")
+	}
+
+	channel, ok := config.FindChannel("b")
+	if !ok || channel.Name != "b" || !channel.Presence {
+		t.Fatalf("Expected channel b to be found, got %v (%v)", channel, ok)
+	}
+}
+
 func TestAuthProviderConfigValidate(t *testing.T) {
 	scenarios := []struct {
 		config      settings.AuthProviderConfig
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+var _ Model = (*Upload)(nil)
+
+// Upload defines a single resumable record file upload session.
+type Upload struct {
+	BaseModel
+
+	CollectionId string `db:"collectionId" json:"collectionId"`
+	RecordId     string `db:"recordId" json:"recordId"`
+	Field        string `db:"field" json:"field"`
+	Filename     string `db:"filename" json:"filename"`
+
+	// Size is the total size of the uploaded file (in bytes).
+	Size int64 `db:"size" json:"size"`
+
+	// Offset is the number of the already received bytes.
+	Offset int64 `db:"offset" json:"offset"`
+
+	// OwnerId is the id of the admin or auth record that
+	// has created the upload session (empty for guests).
+	OwnerId string `db:"ownerId" json:"-"`
+
+	// OwnerCollectionId is the collection id of the auth record that
+	// has created the upload session (empty for admins and guests).
+	OwnerCollectionId string `db:"ownerCollectionId" json:"-"`
+}
+
+// TableName returns the Upload model SQL table name.
+func (m *Upload) TableName() string {
+	return "_uploads"
+}
+
+// IsComplete checks whether all of the upload file bytes were received.
+func (m *Upload) IsComplete() bool {
+	return m.Size > 0 && m.Offset >= m.Size
+}
+
+// IsOwner checks whether the upload session was created by the provided admin
+// or auth record (both nil means guest).
+func (m *Upload) IsOwner(admin *Admin, authRecord *Record) bool {
+	switch {
+	case admin != nil:
+		return m.OwnerCollectionId == "" && m.OwnerId == admin.Id
+	case authRecord != nil:
+		return m.OwnerCollectionId == authRecord.Collection().Id && m.OwnerId == authRecord.Id
+	default:
+		return m.OwnerCollectionId == "" && m.OwnerId == ""
+	}
+}
+
+// SetOwner marks the provided admin or auth record
+// as owner of the upload session (both nil means guest).
+func (m *Upload) SetOwner(admin *Admin, authRecord *Record) {
+	switch {
+	case admin != nil:
+		m.OwnerId = admin.Id
+		m.OwnerCollectionId = ""
+	case authRecord != nil:
+		m.OwnerId = authRecord.Id
+		m.OwnerCollectionId = authRecord.Collection().Id
+	default:
+		m.OwnerId = ""
+		m.OwnerCollectionId = ""
+	}
+}
+
+// IsStale checks whether the upload session was
+// not updated for more than the specified max duration.
+func (m *Upload) IsStale(maxIdle time.Duration) bool {
+	staleAt := m.Updated.Time().Add(maxIdle)
+
+	return types.NowDateTime().Time().After(staleAt)
+}
@@ -0,0 +1,102 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestUploadTableName(t *testing.T) {
+	m := models.Upload{}
+	if m.TableName() != "_uploads" {
+		t.Fatalf("Unexpected table name, got %q", m.TableName())
+	}
+}
+
+func TestUploadIsComplete(t *testing.T) {
+	scenarios := []struct {
+		size     int64
+		offset   int64
+		expected bool
+	}{
+		{0, 0, false},
+		{10, 0, false},
+		{10, 9, false},
+		{10, 10, true},
+	}
+
+	for i, s := range scenarios {
+		m := models.Upload{Size: s.size, Offset: s.offset}
+
+		result := m.IsComplete()
+		if result != s.expected {
+			t.Errorf("(%d) Expected %v, got %v", i, s.expected, result)
+		}
+	}
+}
+
+func TestUploadOwner(t *testing.T) {
+	admin := &models.Admin{}
+	admin.Id = "test_admin"
+
+	collection := &models.Collection{}
+	collection.Id = "test_collection"
+	authRecord := models.NewRecord(collection)
+	authRecord.Id = "test_record"
+
+	otherRecord := models.NewRecord(collection)
+	otherRecord.Id = "test_admin"
+
+	scenarios := []struct {
+		name        string
+		ownerAdmin  *models.Admin
+		ownerRecord *models.Record
+		checkAdmin  *models.Admin
+		checkRecord *models.Record
+		expected    bool
+	}{
+		{"guest owner - guest", nil, nil, nil, nil, true},
+		{"guest owner - admin", nil, nil, admin, nil, false},
+		{"guest owner - auth record", nil, nil, nil, authRecord, false},
+		{"admin owner - guest", admin, nil, nil, nil, false},
+		{"admin owner - admin", admin, nil, admin, nil, true},
+		{"admin owner - auth record with the same id", admin, nil, nil, otherRecord, false},
+		{"auth record owner - guest", nil, authRecord, nil, nil, false},
+		{"auth record owner - admin", nil, authRecord, admin, nil, false},
+		{"auth record owner - other auth record", nil, authRecord, nil, otherRecord, false},
+		{"auth record owner - auth record", nil, authRecord, nil, authRecord, true},
+	}
+
+	for _, s := range scenarios {
+		m := models.Upload{}
+		m.SetOwner(s.ownerAdmin, s.ownerRecord)
+
+		result := m.IsOwner(s.checkAdmin, s.checkRecord)
+		if result != s.expected {
+			t.Errorf("[%s] Expected %v, got %v", s.name, s.expected, result)
+		}
+	}
+}
+
+func TestUploadIsStale(t *testing.T) {
+	m := models.Upload{}
+	m.Updated, _ = types.ParseDateTime(time.Now().Add(-5 * time.Minute))
+
+	scenarios := []struct {
+		maxIdle  time.Duration
+		expected bool
+	}{
+		{time.Minute, true},
+		{4 * time.Minute, true},
+		{6 * time.Minute, false},
+	}
+
+	for i, s := range scenarios {
+		result := m.IsStale(s.maxIdle)
+		if result != s.expected {
+			t.Errorf("(%d) Expected %v, got %v", i, s.expected, result)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+var _ Model = (*Webhook)(nil)
+
+// list with the supported webhook actions
+const (
+	WebhookActionCreate = "create"
+	WebhookActionUpdate = "update"
+	WebhookActionDelete = "delete"
+	WebhookActionAuth   = "auth"
+)
+
+// WebhookActions is a list with all supported webhook actions.
+var WebhookActions = []string{
+	WebhookActionCreate,
+	WebhookActionUpdate,
+	WebhookActionDelete,
+	WebhookActionAuth,
+}
+
+// Webhook defines a single outgoing record events webhook.
+type Webhook struct {
+	BaseModel
+
+	Name string `db:"name" json:"name"`
+	Url  string `db:"url" json:"url"`
+
+	// Collections is a list with the ids or names of the collections
+	// whose record events are delivered to the webhook
+	// (empty list means all collections).
+	Collections types.JsonArray `db:"collections" json:"collections"`
+
+	// Actions is a list with the record actions that are delivered to the webhook.
+	Actions types.JsonArray `db:"actions" json:"actions"`
+
+	// Secret is the key used to sign the webhook delivery payloads.
+	Secret string `db:"secret" json:"-"`
+
+	Disabled bool `db:"disabled" json:"disabled"`
+}
+
+// TableName returns the Webhook model SQL table name.
+func (m *Webhook) TableName() string {
+	return "_webhooks"
+}
+
+// HasAction checks whether the webhook is subscribed for the specified action.
+func (m *Webhook) HasAction(action string) bool {
+	for _, v := range m.Actions {
+		if str, _ := v.(string); str != "" && str == action {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasCollection checks whether the webhook is subscribed for
+// the record events of the specified collection.
+func (m *Webhook) HasCollection(collection *Collection) bool {
+	if collection == nil {
+		return false
+	}
+
+	if len(m.Collections) == 0 {
+		return true // all collections
+	}
+
+	for _, v := range m.Collections {
+		if str, _ := v.(string); str != "" && (str == collection.Id || str == collection.Name) {
+			return true
+		}
+	}
+
+	return false
+}
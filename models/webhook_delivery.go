@@ -0,0 +1,51 @@
+package models
+
+import (
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+var _ Model = (*WebhookDelivery)(nil)
+
+// list with the supported values for `WebhookDelivery.Status`
+const (
+	WebhookDeliveryStatusPending = "pending"
+	WebhookDeliveryStatusSuccess = "success"
+	WebhookDeliveryStatusFailed  = "failed"
+)
+
+// WebhookDelivery defines a single queued (or already sent) webhook payload.
+type WebhookDelivery struct {
+	BaseModel
+
+	WebhookId    string        `db:"webhookId" json:"webhookId"`
+	Url          string        `db:"url" json:"url"`
+	Action       string        `db:"action" json:"action"`
+	CollectionId string        `db:"collectionId" json:"collectionId"`
+	RecordId     string        `db:"recordId" json:"recordId"`
+	Payload      types.JsonRaw `db:"payload" json:"payload"`
+
+	// Status is the current delivery state (pending, success or failed).
+	Status string `db:"status" json:"status"`
+
+	// Attempts is the number of the already performed send attempts.
+	Attempts int `db:"attempts" json:"attempts"`
+
+	// NextAttemptAt is the earliest time when a pending delivery will be (re)sent.
+	NextAttemptAt types.DateTime `db:"nextAttemptAt" json:"nextAttemptAt"`
+
+	// ResponseStatus is the HTTP status code of the last send attempt (if any).
+	ResponseStatus int `db:"responseStatus" json:"responseStatus"`
+
+	// Error is the error message of the last failed send attempt (if any).
+	Error string `db:"error" json:"error"`
+}
+
+// TableName returns the WebhookDelivery model SQL table name.
+func (m *WebhookDelivery) TableName() string {
+	return "_webhookDeliveries"
+}
+
+// IsPending checks whether the delivery is still waiting to be sent.
+func (m *WebhookDelivery) IsPending() bool {
+	return m.Status == WebhookDeliveryStatusPending
+}
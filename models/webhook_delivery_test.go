@@ -0,0 +1,34 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/models"
+)
+
+func TestWebhookDeliveryTableName(t *testing.T) {
+	m := models.WebhookDelivery{}
+	if m.TableName() != "_webhookDeliveries" {
+		t.Fatalf("Unexpected table name, got %q", m.TableName())
+	}
+}
+
+func TestWebhookDeliveryIsPending(t *testing.T) {
+	scenarios := []struct {
+		status   string
+		expected bool
+	}{
+		{"", false},
+		{models.WebhookDeliveryStatusPending, true},
+		{models.WebhookDeliveryStatusSuccess, false},
+		{models.WebhookDeliveryStatusFailed, false},
+	}
+
+	for _, s := range scenarios {
+		m := models.WebhookDelivery{Status: s.status}
+
+		if v := m.IsPending(); v != s.expected {
+			t.Errorf("[%s] Expected %v, got %v", s.status, s.expected, v)
+		}
+	}
+}
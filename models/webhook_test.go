@@ -0,0 +1,71 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestWebhookTableName(t *testing.T) {
+	m := models.Webhook{}
+	if m.TableName() != "_webhooks" {
+		t.Fatalf("Unexpected table name, got %q", m.TableName())
+	}
+}
+
+func TestWebhookHasAction(t *testing.T) {
+	m := models.Webhook{
+		Actions: types.JsonArray{models.WebhookActionCreate, models.WebhookActionAuth},
+	}
+
+	scenarios := []struct {
+		action   string
+		expected bool
+	}{
+		{"", false},
+		{"missing", false},
+		{models.WebhookActionCreate, true},
+		{models.WebhookActionUpdate, false},
+		{models.WebhookActionDelete, false},
+		{models.WebhookActionAuth, true},
+	}
+
+	for _, s := range scenarios {
+		if v := m.HasAction(s.action); v != s.expected {
+			t.Errorf("[%s] Expected %v, got %v", s.action, s.expected, v)
+		}
+	}
+}
+
+func TestWebhookHasCollection(t *testing.T) {
+	c1 := &models.Collection{Name: "c1"}
+	c1.Id = "c1_id"
+
+	c2 := &models.Collection{Name: "c2"}
+	c2.Id = "c2_id"
+
+	c3 := &models.Collection{Name: "c3"}
+	c3.Id = "c3_id"
+
+	scenarios := []struct {
+		name        string
+		collections types.JsonArray
+		collection  *models.Collection
+		expected    bool
+	}{
+		{"nil collection", nil, nil, false},
+		{"no collections", nil, c1, true},
+		{"match by id", types.JsonArray{"c1_id", "c2"}, c1, true},
+		{"match by name", types.JsonArray{"c1_id", "c2"}, c2, true},
+		{"no match", types.JsonArray{"c1_id", "c2"}, c3, false},
+	}
+
+	for _, s := range scenarios {
+		m := models.Webhook{Collections: s.collections}
+
+		if v := m.HasCollection(s.collection); v != s.expected {
+			t.Errorf("[%s] Expected %v, got %v", s.name, s.expected, v)
+		}
+	}
+}
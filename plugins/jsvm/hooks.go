@@ -0,0 +1,110 @@
+package jsvm
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"sync"
+
+	"github.com/dop251/goja"
+	"github.com/dop251/goja_nodejs/console"
+	"github.com/dop251/goja_nodejs/process"
+	"github.com/dop251/goja_nodejs/require"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// HooksOptions defines optional struct to customize the default hooks loader behavior.
+type HooksOptions struct {
+	// Dir specifies the directory with the JS app hooks.
+	//
+	// If not set it fallbacks to a relative "pb_data/../pb_hooks" directory.
+	Dir string
+}
+
+// hooks is the app hooks loader plugin definition.
+// Usually it is instantiated via RegisterHooks or MustRegisterHooks.
+type hooks struct {
+	app     core.App
+	options *HooksOptions
+}
+
+// MustRegisterHooks registers the hooks loader plugin to
+// the provided app instance and panics if it fails.
+//
+// Internally it calls RegisterHooks(app, options).
+//
+// If options is nil, by default the js files from pb_data/../pb_hooks are loaded.
+// Set custom options.Dir if you want to change it to some other directory.
+func MustRegisterHooks(app core.App, options *HooksOptions) {
+	if err := RegisterHooks(app, options); err != nil {
+		panic(err)
+	}
+}
+
+// RegisterHooks registers the hooks loader plugin to the provided app instance.
+//
+// Each hooks file is executed in its own JS runtime with the following
+// app specific bindings (in addition to the NewBaseVM() ones):
+//
+//	cronAdd(jobId, cronExpr, handler)
+//	cronRemove(jobId)
+//
+// If options is nil, by default the js files from pb_data/../pb_hooks are loaded.
+// Set custom options.Dir if you want to change it to some other directory.
+func RegisterHooks(app core.App, options *HooksOptions) error {
+	p := &hooks{app: app}
+
+	if options != nil {
+		p.options = options
+	} else {
+		p.options = &HooksOptions{}
+	}
+
+	if p.options.Dir == "" {
+		p.options.Dir = filepath.Join(app.DataDir(), "../pb_hooks")
+	}
+
+	files, err := readDirFiles(p.options.Dir)
+	if err != nil {
+		return err
+	}
+
+	registry := new(require.Registry) // this can be shared by multiple runtimes
+
+	for file, content := range files {
+		vm := NewBaseVM()
+		registry.Enable(vm)
+		console.Enable(vm)
+		process.Enable(vm)
+		cronBinds(app, vm)
+
+		_, err := vm.RunString(string(content))
+		if err != nil {
+			return fmt.Errorf("failed to run hooks file %s: %w", file, err)
+		}
+	}
+
+	return nil
+}
+
+// cronBinds registers the app cron related bindings to the provided runtime.
+func cronBinds(app core.App, vm *goja.Runtime) {
+	// goja runtimes are not goroutine safe and the cron jobs are
+	// executed in their own goroutine, so the handler calls are serialized
+	var mux sync.Mutex
+
+	vm.Set("cronAdd", func(jobId string, cronExpr string, handler goja.Callable) error {
+		return app.Cron().Add(jobId, cronExpr, func() {
+			mux.Lock()
+			defer mux.Unlock()
+
+			if _, err := handler(goja.Undefined()); err != nil && app.IsDebug() {
+				log.Printf("[cron] %s: %v\n", jobId, err)
+			}
+		})
+	})
+
+	vm.Set("cronRemove", func(jobId string) {
+		app.Cron().Remove(jobId)
+	})
+}
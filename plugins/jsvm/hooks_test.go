@@ -0,0 +1,65 @@
+package jsvm_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/plugins/jsvm"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestRegisterHooksMissingDir(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	err := jsvm.RegisterHooks(app, &jsvm.HooksOptions{
+		Dir: filepath.Join(t.TempDir(), "missing"),
+	})
+	if err != nil {
+		t.Fatalf("Expected missing hooks dir to be ignored, got %v", err)
+	}
+}
+
+func TestRegisterHooksCronBinds(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dir := t.TempDir()
+
+	content := `
+		cronAdd("test1", "* * * * *", () => {});
+		cronAdd("test2", "@daily", () => {});
+		cronAdd("test3", "@hourly", () => {});
+		cronRemove("test2");
+	`
+	if err := os.WriteFile(filepath.Join(dir, "main.pb.js"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	totalBefore := app.Cron().Total()
+
+	if err := jsvm.RegisterHooks(app, &jsvm.HooksOptions{Dir: dir}); err != nil {
+		t.Fatal(err)
+	}
+
+	if total := app.Cron().Total(); total != totalBefore+2 {
+		t.Fatalf("Expected %d cron jobs, got %d", totalBefore+2, total)
+	}
+}
+
+func TestRegisterHooksCronInvalidExpr(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dir := t.TempDir()
+
+	content := `cronAdd("test", "invalid", () => {});`
+	if err := os.WriteFile(filepath.Join(dir, "main.pb.js"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := jsvm.RegisterHooks(app, &jsvm.HooksOptions{Dir: dir}); err == nil {
+		t.Fatal("Expected invalid cron expression error")
+	}
+}
@@ -8,6 +8,12 @@
 //	jsvm.MustRegisterMigrations(app, &jsvm.MigrationsOptions{
 //		Dir: "custom_js_migrations_dir_path", // default to "pb_data/../pb_migrations"
 //	})
+//
+// 2. JS app hooks loader (eg. for registering cron jobs):
+//
+//	jsvm.MustRegisterHooks(app, &jsvm.HooksOptions{
+//		Dir: "custom_js_hooks_dir_path", // default to "pb_data/../pb_hooks"
+//	})
 package jsvm
 
 import (
@@ -168,6 +174,10 @@ func apisBind(vm *goja.Runtime) {
 	obj.Set("requestData", apis.RequestData)
 	obj.Set("enrichRecord", apis.EnrichRecord)
 	obj.Set("enrichRecords", apis.EnrichRecords)
+
+	// realtime helpers
+	obj.Set("publishRealtimeChannelMessage", apis.PublishRealtimeChannelMessage)
+	obj.Set("realtimeChannelMembers", apis.RealtimeChannelMembers)
 }
 
 // FieldMapper provides custom mapping between Go and JavaScript property names.
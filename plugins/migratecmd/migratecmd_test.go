@@ -38,13 +38,16 @@ migrate((db) => {
     "deleteRule": null,
     "options": {
       "allowEmailAuth": false,
+      "allowMfa": false,
       "allowOAuth2Auth": false,
+      "allowOTPAuth": false,
       "allowUsernameAuth": false,
       "exceptEmailDomains": null,
       "manageRule": "created > 0",
       "minPasswordLength": 20,
       "onlyEmailDomains": null,
-      "requireEmail": false
+      "requireEmail": false,
+      "searchFields": null
     }
   });
 
@@ -88,13 +91,16 @@ func init() {
 			"deleteRule": null,
 			"options": {
 				"allowEmailAuth": false,
+				"allowMfa": false,
 				"allowOAuth2Auth": false,
+				"allowOTPAuth": false,
 				"allowUsernameAuth": false,
 				"exceptEmailDomains": null,
 				"manageRule": "created > 0",
 				"minPasswordLength": 20,
 				"onlyEmailDomains": null,
-				"requireEmail": false
+				"requireEmail": false,
+				"searchFields": null
 			}
 		}` + "`" + `
 
@@ -208,13 +214,16 @@ migrate((db) => {
     "deleteRule": null,
     "options": {
       "allowEmailAuth": false,
+      "allowMfa": false,
       "allowOAuth2Auth": false,
+      "allowOTPAuth": false,
       "allowUsernameAuth": false,
       "exceptEmailDomains": null,
       "manageRule": "created > 0",
       "minPasswordLength": 20,
       "onlyEmailDomains": null,
-      "requireEmail": false
+      "requireEmail": false,
+      "searchFields": null
     }
   });
 
@@ -262,13 +271,16 @@ func init() {
 			"deleteRule": null,
 			"options": {
 				"allowEmailAuth": false,
+				"allowMfa": false,
 				"allowOAuth2Auth": false,
+				"allowOTPAuth": false,
 				"allowUsernameAuth": false,
 				"exceptEmailDomains": null,
 				"manageRule": "created > 0",
 				"minPasswordLength": 20,
 				"onlyEmailDomains": null,
-				"requireEmail": false
+				"requireEmail": false,
+				"searchFields": null
 			}
 		}` + "`" + `
 
@@ -366,7 +378,9 @@ migrate((db) => {
   collection.type = "base"
   collection.listRule = null
   collection.deleteRule = "updated > 0 && @request.auth.id != ''"
-  collection.options = {}
+  collection.options = {
+    "searchFields": null
+  }
 
   // remove
   collection.schema.removeField("f3_id")
@@ -411,13 +425,16 @@ migrate((db) => {
   collection.deleteRule = null
   collection.options = {
     "allowEmailAuth": false,
+    "allowMfa": false,
     "allowOAuth2Auth": false,
+    "allowOTPAuth": false,
     "allowUsernameAuth": false,
     "exceptEmailDomains": null,
     "manageRule": "created > 0",
     "minPasswordLength": 20,
     "onlyEmailDomains": null,
-    "requireEmail": false
+    "requireEmail": false,
+    "searchFields": null
   }
 
   // add
@@ -485,7 +502,9 @@ func init() {
 		collection.DeleteRule = types.Pointer("updated > 0 && @request.auth.id != ''")
 
 		options := map[string]any{}
-		json.Unmarshal([]byte(` + "`" + `{}` + "`" + `), &options)
+		json.Unmarshal([]byte(` + "`" + `{
+			"searchFields": null
+		}` + "`" + `), &options)
 		collection.SetOptions(options)
 
 		// remove
@@ -544,13 +563,16 @@ func init() {
 		options := map[string]any{}
 		json.Unmarshal([]byte(` + "`" + `{
 			"allowEmailAuth": false,
+			"allowMfa": false,
 			"allowOAuth2Auth": false,
+			"allowOTPAuth": false,
 			"allowUsernameAuth": false,
 			"exceptEmailDomains": null,
 			"manageRule": "created > 0",
 			"minPasswordLength": 20,
 			"onlyEmailDomains": null,
-			"requireEmail": false
+			"requireEmail": false,
+			"searchFields": null
 		}` + "`" + `), &options)
 		collection.SetOptions(options)
 
@@ -12,6 +12,7 @@ import (
 	"github.com/pocketbase/pocketbase/cmd"
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tools/list"
+	"github.com/pocketbase/pocketbase/tools/subscriptions"
 	"github.com/spf13/cobra"
 )
 
@@ -56,6 +57,10 @@ type Config struct {
 	DataMaxIdleConns int // default to core.DefaultDataMaxIdleConns
 	LogsMaxOpenConns int // default to core.DefaultLogsMaxOpenConns
 	LogsMaxIdleConns int // default to core.DefaultLogsMaxIdleConns
+
+	// optional custom realtime subscriptions broker
+	// (default to subscriptions.NewDefaultBroker())
+	SubscriptionsBroker subscriptions.Broker
 }
 
 // New creates a new PocketBase instance with the default configuration.
@@ -124,6 +129,8 @@ func NewWithConfig(config *Config) *PocketBase {
 		DataMaxIdleConns: config.DataMaxIdleConns,
 		LogsMaxOpenConns: config.LogsMaxOpenConns,
 		LogsMaxIdleConns: config.LogsMaxIdleConns,
+
+		SubscriptionsBroker: config.SubscriptionsBroker,
 	})}
 
 	// hide the default help command (allow only `--help` flag)
@@ -137,6 +144,8 @@ func NewWithConfig(config *Config) *PocketBase {
 func (pb *PocketBase) Start() error {
 	// register system commands
 	pb.RootCmd.AddCommand(cmd.NewServeCommand(pb, !pb.hideStartBanner))
+	pb.RootCmd.AddCommand(cmd.NewBackupsCommand(pb))
+	pb.RootCmd.AddCommand(cmd.NewStorageCommand(pb))
 	pb.RootCmd.AddCommand(cmd.NewTempUpgradeCommand(pb))
 
 	return pb.Execute()
@@ -158,6 +167,9 @@ func (pb *PocketBase) Execute() error {
 
 	wg.Add(1)
 
+	// the root command error (if any)
+	cmdErrCh := make(chan error, 1)
+
 	// wait for interrupt signal to gracefully shutdown the application
 	go func() {
 		defer wg.Done()
@@ -169,19 +181,35 @@ func (pb *PocketBase) Execute() error {
 	// execute the root command
 	go func() {
 		defer wg.Done()
-		if err := pb.RootCmd.Execute(); err != nil {
-			log.Println(err)
-		}
+		cmdErrCh <- pb.RootCmd.Execute()
 	}()
 
 	wg.Wait()
 
 	// cleanup
-	return pb.onTerminate()
+	terminateErr := pb.onTerminate()
+
+	select {
+	case err := <-cmdErrCh:
+		if err != nil {
+			return err
+		}
+	default:
+		// interrupted
+	}
+
+	return terminateErr
 }
 
 // onTerminate tries to release the app resources on app termination.
 func (pb *PocketBase) onTerminate() error {
+	pb.Cron().Stop()
+
+	// stop sharing the realtime events with the other app instances (if any)
+	if err := pb.SubscriptionsBroker().SetAdapter(nil); err != nil && pb.IsDebug() {
+		log.Println(err)
+	}
+
 	return pb.ResetBootstrapState()
 }
 
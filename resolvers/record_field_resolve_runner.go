@@ -3,6 +3,7 @@ package resolvers
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -15,6 +16,10 @@ import (
 	"github.com/pocketbase/pocketbase/tools/security"
 )
 
+// backRelationRegex matches the back-relation field paths
+// in the format "collectionName_via_relationFieldName".
+var backRelationRegex = regexp.MustCompile(`^(\w+)_via_(\w+)$`)
+
 // parseAndRun starts a new one-off RecordFieldResolver.Resolve execution.
 func parseAndRun(fieldName string, resolver *RecordFieldResolver) (*search.ResolverResult, error) {
 	r := &runner{
@@ -40,6 +45,13 @@ type runner struct {
 	withMultiMatch             bool                // indicates whether to attach a multiMatchSubquery condition to the ResolverResult
 	multiMatchActiveTableAlias string              // the last used multi-match table alias
 	multiMatch                 *multiMatchSubquery // the multi-match subquery expression generated from the fieldName
+	joinedCollections          []*joinedCollection // the related collections joined to the main query while resolving the fieldName
+}
+
+// joinedCollection defines a related collection joined to the main query.
+type joinedCollection struct {
+	collection *models.Collection
+	tableAlias string
 }
 
 func (r *runner) run() (*search.ResolverResult, error) {
@@ -153,6 +165,7 @@ func (r *runner) processCollectionField() (*search.ResolverResult, error) {
 
 	// join the collection to the main query
 	r.resolver.registerJoin(inflector.Columnify(collection.Name), r.activeTableAlias, nil)
+	r.joinedCollections = append(r.joinedCollections, &joinedCollection{collection, r.activeTableAlias})
 
 	// join the collection to the multi-match subquery
 	r.multiMatchActiveTableAlias = "__mm" + r.activeTableAlias
@@ -449,6 +462,17 @@ func (r *runner) processActiveProps() (*search.ResolverResult, error) {
 		}
 
 		field := collection.Schema.GetFieldByName(prop)
+
+		// check if it is a back-relation path (eg. comments_via_post)
+		if field == nil {
+			if matches := backRelationRegex.FindStringSubmatch(prop); len(matches) == 3 {
+				if err := r.processBackRelation(collection, prop, matches[1], matches[2]); err != nil {
+					return nil, err
+				}
+				continue
+			}
+		}
+
 		if field == nil {
 			if r.nullifyMisingField {
 				return &search.ResolverResult{Identifier: "NULL"}, nil
@@ -525,6 +549,7 @@ func (r *runner) processActiveProps() (*search.ResolverResult, error) {
 		)
 		r.activeCollectionName = newCollectionName
 		r.activeTableAlias = newTableAlias
+		r.joinedCollections = append(r.joinedCollections, &joinedCollection{relCollection, newTableAlias})
 		// ---
 
 		// join the relation to the multi-match subquery
@@ -556,6 +581,77 @@ func (r *runner) processActiveProps() (*search.ResolverResult, error) {
 	return nil, fmt.Errorf("failed to resolve field %q", r.fieldName)
 }
 
+// processBackRelation joins the records of backCollectionName that
+// reference the active collection records through backFieldName.
+func (r *runner) processBackRelation(
+	collection *models.Collection,
+	prop string,
+	backCollectionName string,
+	backFieldName string,
+) error {
+	backCollection, err := r.resolver.loadCollection(backCollectionName)
+	if err != nil {
+		return fmt.Errorf("failed to find back-relation %q collection", prop)
+	}
+
+	backField := backCollection.Schema.GetFieldByName(backFieldName)
+	if backField == nil || backField.Type != schema.FieldTypeRelation {
+		return fmt.Errorf("invalid back-relation field %q", prop)
+	}
+
+	backField.InitOptions()
+	options, ok := backField.Options.(*schema.RelationOptions)
+	if !ok || options.CollectionId != collection.Id {
+		return fmt.Errorf("invalid back-relation field %q", prop)
+	}
+
+	cleanFieldName := inflector.Columnify(backField.Name)
+	cleanProp := inflector.Columnify(prop)
+
+	// join the back-relation to the main query
+	// ---
+	newTableAlias := r.activeTableAlias + "_" + cleanProp
+	r.resolver.registerJoin(
+		inflector.Columnify(backCollection.Name),
+		newTableAlias,
+		backRelationJoinExpr(newTableAlias+"."+cleanFieldName, r.activeTableAlias, options),
+	)
+	r.activeCollectionName = backCollection.Name
+	r.activeTableAlias = newTableAlias
+	r.joinedCollections = append(r.joinedCollections, &joinedCollection{backCollection, newTableAlias})
+	// ---
+
+	// join the back-relation to the multi-match subquery
+	// ---
+	// (a single record could be referenced by many back-relation records)
+	r.withMultiMatch = true
+
+	newTableAlias2 := r.multiMatchActiveTableAlias + "_" + cleanProp
+	r.multiMatch.joins = append(r.multiMatch.joins, &join{
+		tableName:  inflector.Columnify(backCollection.Name),
+		tableAlias: newTableAlias2,
+		on:         backRelationJoinExpr(newTableAlias2+"."+cleanFieldName, r.multiMatchActiveTableAlias, options),
+	})
+	r.multiMatchActiveTableAlias = newTableAlias2
+	// ---
+
+	return nil
+}
+
+// backRelationJoinExpr returns the join condition between the
+// back-relation field column and its referenced parent table.
+func backRelationJoinExpr(tableColumnPair string, parentTableAlias string, options *schema.RelationOptions) dbx.Expression {
+	if options.MaxSelect != nil && *options.MaxSelect == 1 {
+		return dbx.NewExp(fmt.Sprintf("[[%s]] = [[%s.id]]", tableColumnPair, parentTableAlias))
+	}
+
+	return dbx.NewExp(fmt.Sprintf(
+		"[[%s.id]] IN (SELECT [[__je.value]] FROM %s {{__je}})",
+		parentTableAlias,
+		jsonEach(tableColumnPair),
+	))
+}
+
 func jsonArrayLength(tableColumnPair string) string {
 	return fmt.Sprintf(
 		// note: the case is used to normalize value access for single and multiple relations.
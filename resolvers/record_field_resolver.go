@@ -10,6 +10,7 @@ import (
 	"github.com/pocketbase/pocketbase/daos"
 	"github.com/pocketbase/pocketbase/models"
 	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/tools/inflector"
 	"github.com/pocketbase/pocketbase/tools/search"
 	"github.com/pocketbase/pocketbase/tools/security"
 	"github.com/spf13/cast"
@@ -36,8 +37,18 @@ var plainRequestAuthFields = []string{
 	"@request.auth." + schema.FieldNameUpdated,
 }
 
-// ensure that `search.FieldResolver` interface is implemented
-var _ search.FieldResolver = (*RecordFieldResolver)(nil)
+// full-text search related identifiers
+const (
+	searchTableAlias string = "__fts"
+	rankSortField    string = "@rank"
+)
+
+// ensure that `search.FieldResolver` and `search.FullTextSearchResolver`
+// interfaces are implemented
+var (
+	_ search.FieldResolver          = (*RecordFieldResolver)(nil)
+	_ search.FullTextSearchResolver = (*RecordFieldResolver)(nil)
+)
 
 // RecordFieldResolver defines a custom search resolver struct for
 // managing Record model search fields.
@@ -60,6 +71,7 @@ type RecordFieldResolver struct {
 	allowedFields     []string
 	loadedCollections []*models.Collection
 	joins             []*join // we cannot use a map because the insertion order is not preserved
+	searchJoin        *join
 	requestData       *models.RequestData
 	staticRequestData map[string]any
 }
@@ -109,6 +121,13 @@ func NewRecordFieldResolver(
 // Conditionally updates the provided search query based on the
 // resolved fields (eg. dynamically joining relations).
 func (r *RecordFieldResolver) UpdateQuery(query *dbx.SelectQuery) error {
+	if r.searchJoin != nil {
+		query.InnerJoin(
+			(r.searchJoin.tableName + " " + r.searchJoin.tableAlias),
+			r.searchJoin.on,
+		)
+	}
+
 	if len(r.joins) > 0 {
 		query.Distinct(true)
 
@@ -138,10 +157,150 @@ func (r *RecordFieldResolver) UpdateQuery(query *dbx.SelectQuery) error {
 //	@request.data.someSelect:each
 //	@request.data.someField:isset
 //	@collection.product.name
+//	comments_via_post.title (back-relation)
+//	@rank (available only with full-text search)
 func (r *RecordFieldResolver) Resolve(fieldName string) (*search.ResolverResult, error) {
+	if fieldName == rankSortField {
+		if r.searchJoin == nil {
+			return nil, fmt.Errorf("%s is available only with full-text search", rankSortField)
+		}
+
+		return &search.ResolverResult{Identifier: "[[" + searchTableAlias + ".rank]]"}, nil
+	}
+
 	return parseAndRun(fieldName, r)
 }
 
+// ResolveColumn implements `search.ColumnResolver` interface.
+//
+// It resolves the provided field the same way as [RecordFieldResolver.Resolve]
+// but additionally checks the access to the related collections joined by
+// the field path (relations, back-relations and @collection.* fields):
+//   - admins must be allowed to view the records of each related collection
+//   - for everyone else each related collection must have a non-nil View API
+//     rule and the column value is nullified for the related records that
+//     don't satisfy it (or don't satisfy the hidden fields conditions)
+func (r *RecordFieldResolver) ResolveColumn(fieldName string) (*search.ResolverResult, error) {
+	if fieldName == rankSortField {
+		return r.Resolve(fieldName)
+	}
+
+	runner := &runner{
+		fieldName: fieldName,
+		resolver:  r,
+	}
+
+	result, err := runner.run()
+	if err != nil {
+		return nil, err
+	}
+
+	var admin *models.Admin
+	if r.requestData != nil {
+		admin = r.requestData.Admin
+	}
+
+	conditions := []dbx.Expression{}
+
+	for _, joined := range runner.joinedCollections {
+		if admin != nil {
+			if !admin.CanViewRecords(joined.collection) {
+				return nil, fmt.Errorf("field %q is not accessible", fieldName)
+			}
+			continue
+		}
+
+		if joined.collection.ViewRule == nil {
+			return nil, fmt.Errorf("field %q is not accessible", fieldName)
+		}
+
+		if *joined.collection.ViewRule == "" {
+			continue // public
+		}
+
+		tableName := inflector.Columnify(joined.collection.Name)
+
+		ruleQuery := r.dao.RecordQuery(joined.collection).Select(tableName + ".id")
+
+		ruleResolver := NewRecordFieldResolver(r.dao, joined.collection, r.requestData, true)
+		expr, err := search.FilterData(*joined.collection.ViewRule).BuildExpr(ruleResolver)
+		if err != nil {
+			return nil, err
+		}
+		ruleQuery.AndWhere(expr)
+		if err := ruleResolver.UpdateQuery(ruleQuery); err != nil {
+			return nil, err
+		}
+
+		built := ruleQuery.Build()
+		conditions = append(conditions, dbx.NewExp(
+			"[["+joined.tableAlias+".id]] IN ("+built.SQL()+")",
+			built.Params(),
+		))
+	}
+
+	if result.AfterBuild != nil {
+		conditions = append(conditions, result.AfterBuild(dbx.NewExp("1=1")))
+		result.AfterBuild = nil
+	}
+
+	if len(conditions) == 0 {
+		return result, nil
+	}
+
+	params := dbx.Params{}
+	for k, v := range result.Params {
+		params[k] = v
+	}
+	condition := dbx.And(conditions...).Build(nil, params)
+
+	return &search.ResolverResult{
+		Identifier: "(CASE WHEN " + condition + " THEN " + result.Identifier + " ELSE NULL END)",
+		Params:     params,
+	}, nil
+}
+
+// ResolveSearch implements `search.FullTextSearchResolver` interface.
+//
+// Joins the base collection records with its FTS5 search table and
+// returns the MATCH expression for the provided search text.
+//
+// Each whitespace separated search term is treated as a quoted prefix
+// query (aka. all terms must be present in at least one of the search fields).
+// The matching records relevance (bm25) could be used as "@rank" sort field.
+func (r *RecordFieldResolver) ResolveSearch(text string) (dbx.Expression, error) {
+	searchFields := r.baseCollection.SearchFields()
+	if len(searchFields) == 0 {
+		return nil, fmt.Errorf("full-text search is not enabled for collection %q", r.baseCollection.Name)
+	}
+
+	terms := strings.Fields(text)
+	if len(terms) == 0 {
+		return nil, nil // nothing to search
+	}
+
+	for i, term := range terms {
+		terms[i] = `"` + strings.ReplaceAll(term, `"`, `""`) + `"*`
+	}
+
+	r.searchJoin = &join{
+		tableName:  inflector.Columnify(r.baseCollection.SearchTableName()),
+		tableAlias: searchTableAlias,
+		on: dbx.NewExp(fmt.Sprintf(
+			"[[%s.id]] = [[%s.id]]",
+			searchTableAlias,
+			inflector.Columnify(r.baseCollection.Name),
+		)),
+	}
+
+	placeholder := "search" + security.PseudorandomString(5)
+
+	return dbx.NewExp(
+		fmt.Sprintf("[[%s.%s]] MATCH {:%s}", searchTableAlias, r.baseCollection.SearchTableName(), placeholder),
+		dbx.Params{placeholder: "{" + strings.Join(searchFields, " ") + "} : (" + strings.Join(terms, " ") + ")"},
+	), nil
+}
+
 func (r *RecordFieldResolver) resolveStaticRequestField(path ...string) (*search.ResolverResult, error) {
 	if len(path) == 0 {
 		return nil, fmt.Errorf("at least one path key should be provided")
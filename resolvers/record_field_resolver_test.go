@@ -226,6 +226,34 @@ func TestRecordFieldResolverUpdateQuery(t *testing.T) {
 			false,
 			"SELECT DISTINCT `demo1`.* FROM `demo1` LEFT JOIN json_each({:TEST}) `__dataSelect_select_one_je` LEFT JOIN json_each({:TEST}) `__dataSelect_select_many_je` WHERE (COALESCE(NULL, '') = COALESCE({:TEST}, '') AND COALESCE([[__dataSelect_select_one_je.value]], '') != COALESCE({:TEST}, '') AND COALESCE([[__dataSelect_select_one_je.value]], '') = COALESCE({:TEST}, '') AND {:TEST} LIKE {:TEST} ESCAPE '\\' AND ((COALESCE([[__dataSelect_select_many_je.value]], '') = COALESCE({:TEST}, '')) AND (NOT EXISTS (SELECT 1 FROM (SELECT [[__mm__dataSelect_select_many_je.value]] as [[multiMatchValue]] FROM `demo1` `__mm_demo1` LEFT JOIN json_each({:TEST}) `__mm__dataSelect_select_many_je` WHERE `__mm_demo1`.`id` = `demo1`.`id`) {{__smTEST}} WHERE NOT (COALESCE([[__smTEST.multiMatchValue]], '') = COALESCE({:TEST}, ''))))) AND [[__dataSelect_select_many_je.value]] < 1)",
 		},
+		{
+			"single back-relation (any operator)",
+			"demo3",
+			"demo4_via_rel_one_no_cascade.title ?= 'test'",
+			false,
+			"SELECT DISTINCT `demo3`.* FROM `demo3` LEFT JOIN `demo4` `demo3_demo4_via_rel_one_no_cascade` ON [[demo3_demo4_via_rel_one_no_cascade.rel_one_no_cascade]] = [[demo3.id]] WHERE COALESCE([[demo3_demo4_via_rel_one_no_cascade.title]], '') = COALESCE({:TEST}, '')",
+		},
+		{
+			"multiple back-relation (any operator)",
+			"users",
+			"demo1_via_rel_many.text ?= 'test'",
+			false,
+			"SELECT DISTINCT `users`.* FROM `users` LEFT JOIN `demo1` `users_demo1_via_rel_many` ON [[users.id]] IN (SELECT [[__je.value]] FROM json_each(CASE WHEN json_valid([[users_demo1_via_rel_many.rel_many]]) THEN [[users_demo1_via_rel_many.rel_many]] ELSE json_array([[users_demo1_via_rel_many.rel_many]]) END) {{__je}}) WHERE COALESCE([[users_demo1_via_rel_many.text]], '') = COALESCE({:TEST}, '')",
+		},
+		{
+			"multiple back-relation (multi-match operator)",
+			"users",
+			"demo1_via_rel_many.text = 'test'",
+			false,
+			"SELECT DISTINCT `users`.* FROM `users` LEFT JOIN `demo1` `users_demo1_via_rel_many` ON [[users.id]] IN (SELECT [[__je.value]] FROM json_each(CASE WHEN json_valid([[users_demo1_via_rel_many.rel_many]]) THEN [[users_demo1_via_rel_many.rel_many]] ELSE json_array([[users_demo1_via_rel_many.rel_many]]) END) {{__je}}) WHERE (((COALESCE([[users_demo1_via_rel_many.text]], '') = COALESCE({:TEST}, '')) AND (NOT EXISTS (SELECT 1 FROM (SELECT [[__mm_users_demo1_via_rel_many.text]] as [[multiMatchValue]] FROM `users` `__mm_users` LEFT JOIN `demo1` `__mm_users_demo1_via_rel_many` ON [[__mm_users.id]] IN (SELECT [[__je.value]] FROM json_each(CASE WHEN json_valid([[__mm_users_demo1_via_rel_many.rel_many]]) THEN [[__mm_users_demo1_via_rel_many.rel_many]] ELSE json_array([[__mm_users_demo1_via_rel_many.rel_many]]) END) {{__je}}) WHERE `__mm_users`.`id` = `users`.`id`) {{__smTEST}} WHERE NOT (COALESCE([[__smTEST.multiMatchValue]], '') = COALESCE({:TEST}, ''))))))",
+		},
+		{
+			"extended filter operands",
+			"demo4",
+			"lower(self_rel_one.title) = 'test' && json_object.a + 1 > 2",
+			false,
+			"SELECT DISTINCT `demo4`.* FROM `demo4` LEFT JOIN json_each(CASE WHEN json_valid([[demo4.self_rel_one]]) THEN [[demo4.self_rel_one]] ELSE json_array([[demo4.self_rel_one]]) END) `demo4_self_rel_one_je` LEFT JOIN `demo4` `demo4_self_rel_one` ON [[demo4_self_rel_one.id]] = [[demo4_self_rel_one_je.value]] WHERE (COALESCE(LOWER([[demo4_self_rel_one.title]]), '') = COALESCE({:TEST}, '') AND (JSON_EXTRACT([[demo4.json_object]], '$.a') + {:TEST}) > {:TEST})",
+		},
 		{
 			"regular select:each fields",
 			"demo1",
@@ -367,6 +395,14 @@ func TestRecordFieldResolverResolveSchemaFields(t *testing.T) {
 		{"self_rel_many.unknown", true, ""},
 		{"self_rel_many.title", false, "[[demo4_self_rel_many.title]]"},
 		{"self_rel_many.self_rel_one.self_rel_many.title", false, "[[demo4_self_rel_many_self_rel_one_self_rel_many.title]]"},
+		// back-relations
+		{"missing_via_rel_one.title", true, ""},
+		{"demo5_via_missing.title", true, ""},
+		{"demo5_via_title.title", true, ""},
+		{"demo3_via_rel_one_no_cascade.title", true, ""}, // not pointing to demo4
+		{"demo5_via_rel_one.unknown", true, ""},
+		{"demo5_via_rel_one.id", false, "[[demo4_demo5_via_rel_one.id]]"},
+		{"demo4_via_self_rel_many.self_rel_one.title", false, "[[demo4_demo4_via_self_rel_many_self_rel_one.title]]"},
 		// json_extract
 		{"json_array.0", false, "JSON_EXTRACT([[demo4.json_array]], '$[0]')"},
 		{"json_object.a.b.c", false, "JSON_EXTRACT([[demo4.json_object]], '$.a.b.c')"},
@@ -517,3 +553,170 @@ func TestRecordFieldResolverResolveStaticRequestDataFields(t *testing.T) {
 		}
 	}
 }
+
+func TestRecordFieldResolverResolveSearch(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// without search fields
+	r := resolvers.NewRecordFieldResolver(app.Dao(), collection, nil, true)
+	if _, err := r.ResolveSearch("test"); err == nil {
+		t.Fatal("Expected error for collection without search fields")
+	}
+	if _, err := r.Resolve("@rank"); err == nil {
+		t.Fatal("Expected @rank resolve error without full-text search")
+	}
+
+	collection.SetOptions(models.CollectionBaseOptions{SearchFields: []string{"title", "active"}})
+
+	// blank search
+	r = resolvers.NewRecordFieldResolver(app.Dao(), collection, nil, true)
+	expr, err := r.ResolveSearch("   ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expr != nil {
+		t.Fatalf("Expected nil expression for blank search, got %v", expr)
+	}
+
+	// non-blank search
+	r = resolvers.NewRecordFieldResolver(app.Dao(), collection, nil, true)
+	expr, err = r.ResolveSearch(`lorem  "ipsum OR`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rank, err := r.Resolve("@rank")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query := app.Dao().RecordQuery(collection).AndWhere(expr).OrderBy(rank.Identifier + " ASC")
+	if err := r.UpdateQuery(query); err != nil {
+		t.Fatal(err)
+	}
+
+	built := query.Build()
+
+	expectQuery := "^" + regexp.QuoteMeta(
+		"SELECT `demo2`.* FROM `demo2` INNER JOIN `_"+collection.Id+"_fts` `__fts` ON [[__fts.id]] = [[demo2.id]] WHERE [[__fts._"+collection.Id+"_fts]] MATCH {:search",
+	) + `\w+` + regexp.QuoteMeta("} ORDER BY [[__fts.rank]] ASC") + "$"
+	if !list.ExistInSliceWithRegex(built.SQL(), []string{expectQuery}) {
+		t.Fatalf("Expected query\n %v \ngot:\n %v", expectQuery, built.SQL())
+	}
+
+	expectMatch := `{title active} : ("lorem"* """ipsum"* "OR"*)`
+	for _, v := range built.Params() {
+		if v != expectMatch {
+			t.Fatalf("Expected match param %q, got %q", expectMatch, v)
+		}
+	}
+}
+
+func TestRecordFieldResolverResolveColumn(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	demo1, err := app.Dao().FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	demo4, err := app.Dao().FindCollectionByNameOrId("demo4")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scopedAdmin := &models.Admin{Role: models.AdminRoleReadonly, Collections: []any{"demo4"}}
+
+	scenarios := []struct {
+		name             string
+		collection       *models.Collection
+		requestData      *models.RequestData
+		field            string
+		expectError      bool
+		expectIdentifier string
+	}{
+		{
+			"base collection field",
+			demo4,
+			&models.RequestData{},
+			"title",
+			false,
+			"^" + regexp.QuoteMeta("[[demo4.title]]") + "$",
+		},
+		{
+			"relation to a collection with nil view rule",
+			demo1,
+			&models.RequestData{},
+			"rel_one.text",
+			true,
+			"",
+		},
+		{
+			"relation to a collection with non-empty view rule",
+			demo4,
+			&models.RequestData{},
+			"rel_one_no_cascade.title",
+			false,
+			"^" + regexp.QuoteMeta("(CASE WHEN [[demo4_rel_one_no_cascade.id]] IN (SELECT `demo3`.`id` FROM `demo3` WHERE ") + `.+ THEN \[\[demo4_rel_one_no_cascade\.title\]\] ELSE NULL END\)$`,
+		},
+		{
+			"public self relation",
+			demo4,
+			&models.RequestData{},
+			"self_rel_one.title",
+			false,
+			"^" + regexp.QuoteMeta("[[demo4_self_rel_one.title]]") + "$",
+		},
+		{
+			"scoped admin with relation to an inaccessible collection",
+			demo4,
+			&models.RequestData{Admin: scopedAdmin},
+			"rel_one_no_cascade.title",
+			true,
+			"",
+		},
+		{
+			"scoped admin with @collection field of an inaccessible collection",
+			demo4,
+			&models.RequestData{Admin: scopedAdmin},
+			"@collection.demo3.title",
+			true,
+			"",
+		},
+		{
+			"scoped admin with relation to an accessible collection",
+			demo4,
+			&models.RequestData{Admin: scopedAdmin},
+			"self_rel_one.title",
+			false,
+			"^" + regexp.QuoteMeta("[[demo4_self_rel_one.title]]") + "$",
+		},
+	}
+
+	for _, s := range scenarios {
+		r := resolvers.NewRecordFieldResolver(app.Dao(), s.collection, s.requestData, s.requestData.Admin != nil)
+
+		result, err := r.ResolveColumn(s.field)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		if !list.ExistInSliceWithRegex(result.Identifier, []string{s.expectIdentifier}) {
+			t.Errorf("[%s] Expected identifier matching\n%s\ngot\n%s", s.name, s.expectIdentifier, result.Identifier)
+		}
+	}
+}
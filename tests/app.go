@@ -150,6 +150,10 @@ func NewTestApp(optTestDataDir ...string) (*TestApp, error) {
 		return t.registerEventCall("OnRecordsListRequest")
 	})
 
+	t.OnRecordsAggregateRequest().Add(func(e *core.RecordsAggregateEvent) error {
+		return t.registerEventCall("OnRecordsAggregateRequest")
+	})
+
 	t.OnRecordViewRequest().Add(func(e *core.RecordViewEvent) error {
 		return t.registerEventCall("OnRecordViewRequest")
 	})
@@ -198,6 +202,30 @@ func NewTestApp(optTestDataDir ...string) (*TestApp, error) {
 		return t.registerEventCall("OnRecordAfterAuthWithOAuth2Request")
 	})
 
+	t.OnRecordBeforeAuthWithMfaRequest().Add(func(e *core.RecordAuthWithMfaEvent) error {
+		return t.registerEventCall("OnRecordBeforeAuthWithMfaRequest")
+	})
+
+	t.OnRecordAfterAuthWithMfaRequest().Add(func(e *core.RecordAuthWithMfaEvent) error {
+		return t.registerEventCall("OnRecordAfterAuthWithMfaRequest")
+	})
+
+	t.OnRecordBeforeRequestOTPRequest().Add(func(e *core.RecordRequestOTPEvent) error {
+		return t.registerEventCall("OnRecordBeforeRequestOTPRequest")
+	})
+
+	t.OnRecordAfterRequestOTPRequest().Add(func(e *core.RecordRequestOTPEvent) error {
+		return t.registerEventCall("OnRecordAfterRequestOTPRequest")
+	})
+
+	t.OnRecordBeforeAuthWithOTPRequest().Add(func(e *core.RecordAuthWithOTPEvent) error {
+		return t.registerEventCall("OnRecordBeforeAuthWithOTPRequest")
+	})
+
+	t.OnRecordAfterAuthWithOTPRequest().Add(func(e *core.RecordAuthWithOTPEvent) error {
+		return t.registerEventCall("OnRecordAfterAuthWithOTPRequest")
+	})
+
 	t.OnRecordBeforeAuthRefreshRequest().Add(func(e *core.RecordAuthRefreshEvent) error {
 		return t.registerEventCall("OnRecordBeforeAuthRefreshRequest")
 	})
@@ -298,6 +326,14 @@ func NewTestApp(optTestDataDir ...string) (*TestApp, error) {
 		return t.registerEventCall("OnMailerAfterRecordChangeEmailSend")
 	})
 
+	t.OnMailerBeforeRecordOTPSend().Add(func(e *core.MailerRecordEvent) error {
+		return t.registerEventCall("OnMailerBeforeRecordOTPSend")
+	})
+
+	t.OnMailerAfterRecordOTPSend().Add(func(e *core.MailerRecordEvent) error {
+		return t.registerEventCall("OnMailerAfterRecordOTPSend")
+	})
+
 	t.OnRealtimeConnectRequest().Add(func(e *core.RealtimeConnectEvent) error {
 		return t.registerEventCall("OnRealtimeConnectRequest")
 	})
@@ -418,6 +454,14 @@ func NewTestApp(optTestDataDir ...string) (*TestApp, error) {
 		return t.registerEventCall("OnAdminAfterAuthWithPasswordRequest")
 	})
 
+	t.OnAdminBeforeAuthWithMfaRequest().Add(func(e *core.AdminAuthWithMfaEvent) error {
+		return t.registerEventCall("OnAdminBeforeAuthWithMfaRequest")
+	})
+
+	t.OnAdminAfterAuthWithMfaRequest().Add(func(e *core.AdminAuthWithMfaEvent) error {
+		return t.registerEventCall("OnAdminAfterAuthWithMfaRequest")
+	})
+
 	t.OnAdminBeforeAuthRefreshRequest().Add(func(e *core.AdminAuthRefreshEvent) error {
 		return t.registerEventCall("OnAdminBeforeAuthRefreshRequest")
 	})
@@ -442,10 +486,22 @@ func NewTestApp(optTestDataDir ...string) (*TestApp, error) {
 		return t.registerEventCall("OnAdminAfterConfirmPasswordResetRequest")
 	})
 
+	t.OnFileBeforeUpload().Add(func(e *core.FileUploadEvent) error {
+		return t.registerEventCall("OnFileBeforeUpload")
+	})
+
 	t.OnFileDownloadRequest().Add(func(e *core.FileDownloadEvent) error {
 		return t.registerEventCall("OnFileDownloadRequest")
 	})
 
+	t.OnFileBeforeTokenRequest().Add(func(e *core.FileTokenEvent) error {
+		return t.registerEventCall("OnFileBeforeTokenRequest")
+	})
+
+	t.OnFileAfterTokenRequest().Add(func(e *core.FileTokenEvent) error {
+		return t.registerEventCall("OnFileAfterTokenRequest")
+	})
+
 	return t, nil
 }
 
@@ -0,0 +1,18 @@
+package tests
+
+import (
+	"testing"
+)
+
+// SkipIfNoFTS5 skips the current test if the test app db driver
+// was compiled without the SQLite FTS5 extension
+// (eg. CGO builds with mattn/go-sqlite3 without the "sqlite_fts5" build tag).
+func SkipIfNoFTS5(t *testing.T, app *TestApp) {
+	_, err := app.Dao().DB().NewQuery(
+		"CREATE VIRTUAL TABLE temp.fts5_check USING fts5(value); DROP TABLE temp.fts5_check;",
+	).Execute()
+
+	if err != nil {
+		t.Skipf("SQLite FTS5 extension is not available: %v", err)
+	}
+}
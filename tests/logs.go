@@ -51,3 +51,110 @@ func MockRequestLogsData(app *TestApp) error {
 
 	return err
 }
+
+func MockWebhookDeliveriesData(app *TestApp) error {
+	_, err := app.LogsDB().NewQuery(`
+		delete from {{_webhookDeliveries}};
+
+		insert into {{_webhookDeliveries}} (
+			[[id]],
+			[[webhookId]],
+			[[url]],
+			[[action]],
+			[[collectionId]],
+			[[recordId]],
+			[[payload]],
+			[[status]],
+			[[attempts]],
+			[[nextAttemptAt]],
+			[[responseStatus]],
+			[[error]],
+			[[created]],
+			[[updated]]
+		)
+		values
+		(
+			"e8nq3ks90dw2zkq",
+			"ftxuvq1ojf4b2pa",
+			"https://example.com/hooks/demo2",
+			"update",
+			"sz5l5z67tg7gku0",
+			"llvuca81nly1qls",
+			'{"action":"update","record":{"id":"llvuca81nly1qls"}}',
+			"success",
+			1,
+			"2022-05-01 10:00:00.123Z",
+			200,
+			"",
+			"2022-05-01 10:00:00.123Z",
+			"2022-05-01 10:00:00.123Z"
+		),
+		(
+			"9uc3ov0jalnr4yc",
+			"ftxuvq1ojf4b2pa",
+			"https://example.com/hooks/demo2",
+			"delete",
+			"sz5l5z67tg7gku0",
+			"achvryl401bhse3",
+			'{"action":"delete","record":{"id":"achvryl401bhse3"}}',
+			"pending",
+			2,
+			"2022-05-02 12:00:00.123Z",
+			500,
+			"Unexpected response status code 500.",
+			"2022-05-02 10:00:00.123Z",
+			"2022-05-02 11:00:00.123Z"
+		);
+	`).Execute()
+
+	return err
+}
+
+func MockAuditLogsData(app *TestApp) error {
+	_, err := app.LogsDB().NewQuery(`
+		delete from {{_auditLogs}};
+
+		insert into {{_auditLogs}} (
+			[[id]],
+			[[action]],
+			[[collectionId]],
+			[[collectionName]],
+			[[recordId]],
+			[[actorType]],
+			[[actorId]],
+			[[actorCollectionId]],
+			[[changes]],
+			[[created]],
+			[[updated]]
+		)
+		values
+		(
+			"p3sbvx0qm1fhz5c",
+			"update",
+			"sz5l5z67tg7gku0",
+			"demo2",
+			"llvuca81nly1qls",
+			"admin",
+			"sywbhecnh46rhm0",
+			"",
+			'{"title":{"old":"test1","new":"test1_update"}}',
+			"2022-05-01 10:00:00.123Z",
+			"2022-05-01 10:00:00.123Z"
+		),
+		(
+			"w9z0c6e5bkr2yjd",
+			"delete",
+			"sz5l5z67tg7gku0",
+			"demo2",
+			"achvryl401bhse3",
+			"auth_record",
+			"4q1xlclmfloku33",
+			"_pb_users_auth_",
+			'{"title":{"old":"test2","new":null}}',
+			"2022-05-02 10:00:00.123Z",
+			"2022-05-02 10:00:00.123Z"
+		);
+	`).Execute()
+
+	return err
+}
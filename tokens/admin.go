@@ -24,3 +24,24 @@ func NewAdminResetPasswordToken(app core.App, admin *models.Admin) (string, erro
 		app.Settings().AdminPasswordResetToken.Duration,
 	)
 }
+
+// NewAdminMfaToken generates and returns a new short-lived admin
+// "mfa pending" token (issued after a successful password authentication
+// and exchanged for a regular auth token after the second factor verification).
+func NewAdminMfaToken(app core.App, admin *models.Admin) (string, error) {
+	return security.NewToken(
+		jwt.MapClaims{"id": admin.Id, "type": TypeAdmin, "mfa": true},
+		(admin.TokenKey + app.Settings().AdminMfaToken.Secret),
+		app.Settings().AdminMfaToken.Duration,
+	)
+}
+
+// NewAdminFileToken generates and returns a new short-lived admin
+// protected file access token.
+func NewAdminFileToken(app core.App, admin *models.Admin) (string, error) {
+	return security.NewToken(
+		jwt.MapClaims{"id": admin.Id, "type": TypeAdmin},
+		(admin.TokenKey + app.Settings().AdminFileToken.Secret),
+		app.Settings().AdminFileToken.Duration,
+	)
+}
@@ -76,3 +76,61 @@ func NewRecordChangeEmailToken(app core.App, record *models.Record, newEmail str
 		app.Settings().RecordEmailChangeToken.Duration,
 	)
 }
+
+// NewRecordMfaToken generates and returns a new short-lived auth record
+// "mfa pending" token (issued after a successful password or OAuth2 authentication
+// and exchanged for a regular auth token after the second factor verification).
+func NewRecordMfaToken(app core.App, record *models.Record) (string, error) {
+	if !record.Collection().IsAuth() {
+		return "", errors.New("The record is not from an auth collection.")
+	}
+
+	return security.NewToken(
+		jwt.MapClaims{
+			"id":           record.Id,
+			"type":         TypeAuthRecord,
+			"collectionId": record.Collection().Id,
+			"mfa":          true,
+		},
+		(record.TokenKey() + app.Settings().RecordMfaToken.Secret),
+		app.Settings().RecordMfaToken.Duration,
+	)
+}
+
+// NewRecordOTPToken generates and returns a new auth record
+// one-time password (aka. magic link) token for the specified OTP id.
+func NewRecordOTPToken(app core.App, record *models.Record, otpId string) (string, error) {
+	if !record.Collection().IsAuth() {
+		return "", errors.New("The record is not from an auth collection.")
+	}
+
+	return security.NewToken(
+		jwt.MapClaims{
+			"id":           record.Id,
+			"type":         TypeAuthRecord,
+			"collectionId": record.Collection().Id,
+			"email":        record.Email(),
+			"otpId":        otpId,
+		},
+		(record.TokenKey() + app.Settings().RecordOTPToken.Secret),
+		app.Settings().RecordOTPToken.Duration,
+	)
+}
+
+// NewRecordFileToken generates and returns a new short-lived auth record
+// protected file access token.
+func NewRecordFileToken(app core.App, record *models.Record) (string, error) {
+	if !record.Collection().IsAuth() {
+		return "", errors.New("The record is not from an auth collection.")
+	}
+
+	return security.NewToken(
+		jwt.MapClaims{
+			"id":           record.Id,
+			"type":         TypeAuthRecord,
+			"collectionId": record.Collection().Id,
+		},
+		(record.TokenKey() + app.Settings().RecordFileToken.Secret),
+		app.Settings().RecordFileToken.Duration,
+	)
+}
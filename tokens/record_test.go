@@ -5,6 +5,7 @@ import (
 
 	"github.com/pocketbase/pocketbase/tests"
 	"github.com/pocketbase/pocketbase/tokens"
+	"github.com/pocketbase/pocketbase/tools/security"
 )
 
 func TestNewRecordAuthToken(t *testing.T) {
@@ -98,3 +99,92 @@ func TestNewRecordChangeEmailToken(t *testing.T) {
 		t.Fatalf("Expected auth record %v, got %v", user, tokenRecord)
 	}
 }
+
+func TestNewRecordMfaToken(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := tokens.NewRecordMfaToken(app, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokenRecord, _ := app.Dao().FindAuthRecordByToken(
+		token,
+		app.Settings().RecordMfaToken.Secret,
+	)
+	if tokenRecord == nil || tokenRecord.Id != user.Id {
+		t.Fatalf("Expected auth record %v, got %v", user, tokenRecord)
+	}
+
+	// shouldn't be usable as regular auth token
+	if authRecord, _ := app.Dao().FindAuthRecordByToken(token, app.Settings().RecordAuthToken.Secret); authRecord != nil {
+		t.Fatalf("Expected the mfa token to not be a valid auth token, got %v", authRecord)
+	}
+}
+
+func TestNewRecordOTPToken(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := tokens.NewRecordOTPToken(app, user, "test_otp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokenRecord, _ := app.Dao().FindAuthRecordByToken(
+		token,
+		app.Settings().RecordOTPToken.Secret,
+	)
+	if tokenRecord == nil || tokenRecord.Id != user.Id {
+		t.Fatalf("Expected auth record %v, got %v", user, tokenRecord)
+	}
+
+	claims, _ := security.ParseUnverifiedJWT(token)
+	if claims["otpId"] != "test_otp" {
+		t.Fatalf("Expected otpId claim %q, got %v", "test_otp", claims["otpId"])
+	}
+
+	// shouldn't be usable as regular auth token
+	if authRecord, _ := app.Dao().FindAuthRecordByToken(token, app.Settings().RecordAuthToken.Secret); authRecord != nil {
+		t.Fatalf("Expected the otp token to not be a valid auth token, got %v", authRecord)
+	}
+}
+
+func TestNewRecordFileToken(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := tokens.NewRecordFileToken(app, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokenRecord, _ := app.Dao().FindAuthRecordByToken(
+		token,
+		app.Settings().RecordFileToken.Secret,
+	)
+	if tokenRecord == nil || tokenRecord.Id != user.Id {
+		t.Fatalf("Expected auth record %v, got %v", user, tokenRecord)
+	}
+
+	// shouldn't be usable as regular auth token
+	if authRecord, _ := app.Dao().FindAuthRecordByToken(token, app.Settings().RecordAuthToken.Secret); authRecord != nil {
+		t.Fatalf("Expected the file token to not be a valid auth token, got %v", authRecord)
+	}
+}
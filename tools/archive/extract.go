@@ -0,0 +1,70 @@
+package archive
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Extract extracts the zip archive at src path into dest directory.
+//
+// Entries that would be written outside of dest (aka. "zip slip")
+// are rejected with an error.
+func Extract(src, dest string) error {
+	zr, err := zip.OpenReader(src)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	if err := os.MkdirAll(dest, os.ModePerm); err != nil {
+		return err
+	}
+
+	for _, f := range zr.File {
+		if err := extractFile(f, dest); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// extractFile extracts the provided zipFile into "basePath/zipFileName" path,
+// creating all the necessary path directories.
+func extractFile(zipFile *zip.File, basePath string) error {
+	path := filepath.Join(basePath, zipFile.Name)
+
+	// prevent "zip slip" (https://snyk.io/research/zip-slip-vulnerability)
+	cleanBase := filepath.Clean(basePath) + string(os.PathSeparator)
+	if !strings.HasPrefix(path, cleanBase) {
+		return fmt.Errorf("invalid archive file path %q", zipFile.Name)
+	}
+
+	if zipFile.FileInfo().IsDir() {
+		return os.MkdirAll(path, os.ModePerm)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+
+	r, err := zipFile.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, zipFile.Mode())
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, r)
+
+	return err
+}
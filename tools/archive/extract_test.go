@@ -0,0 +1,103 @@
+package archive_test
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/archive"
+)
+
+func TestExtractFailure(t *testing.T) {
+	testDir := createTestDir(t)
+	defer os.RemoveAll(testDir)
+
+	missingZipPath := filepath.Join(os.TempDir(), "pb_missing_test.zip")
+	extractPath := filepath.Join(os.TempDir(), "pb_zip_extract")
+	defer os.RemoveAll(extractPath)
+
+	if err := archive.Extract(missingZipPath, extractPath); err == nil {
+		t.Fatal("Expected Extract to fail due to missing zipPath")
+	}
+
+	if _, err := os.Stat(extractPath); err == nil {
+		t.Fatalf("Expected %q to not be created", extractPath)
+	}
+}
+
+func TestExtractZipSlip(t *testing.T) {
+	zipPath := filepath.Join(os.TempDir(), "pb_slip_test.zip")
+	defer os.RemoveAll(zipPath)
+
+	zf, err := os.Create(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(zf)
+	w, err := zw.Create("../escaped.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("test"))
+	zw.Close()
+	zf.Close()
+
+	extractPath := filepath.Join(os.TempDir(), "pb_zip_slip_extract")
+	defer os.RemoveAll(extractPath)
+
+	if err := archive.Extract(zipPath, extractPath); err == nil {
+		t.Fatal("Expected Extract to fail due to invalid file path")
+	}
+
+	if _, err := os.Stat(filepath.Join(os.TempDir(), "escaped.txt")); err == nil {
+		t.Fatal("Expected the escaped file to not be created")
+	}
+}
+
+func TestExtractSuccess(t *testing.T) {
+	testDir := createTestDir(t)
+	defer os.RemoveAll(testDir)
+
+	zipPath := filepath.Join(os.TempDir(), "pb_test.zip")
+	defer os.RemoveAll(zipPath)
+
+	extractPath := filepath.Join(os.TempDir(), "pb_zip_extract")
+	defer os.RemoveAll(extractPath)
+
+	// zip testDir content (with exclude)
+	if err := archive.Create(testDir, zipPath, "a/b"); err != nil {
+		t.Fatalf("Failed to create archive: %v", err)
+	}
+
+	if err := archive.Extract(zipPath, extractPath); err != nil {
+		t.Fatalf("Failed to extract %q in %q", zipPath, extractPath)
+	}
+
+	expected := map[string]bool{
+		"test":  true,
+		"test2": true,
+		"c":     true,
+		"a/a1":  true,
+		"a/a2":  true,
+		"a/b":   false,
+	}
+
+	for name, exists := range expected {
+		_, err := os.Stat(filepath.Join(extractPath, name))
+		if exists && err != nil {
+			t.Fatalf("Expected %q to be extracted, got %v", name, err)
+		}
+		if !exists && err == nil {
+			t.Fatalf("Expected %q to not be extracted", name)
+		}
+	}
+
+	content, err := os.ReadFile(filepath.Join(extractPath, "a/a1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "a/a1" {
+		t.Fatalf("Expected content %q, got %q", "a/a1", content)
+	}
+}
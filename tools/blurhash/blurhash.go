@@ -0,0 +1,167 @@
+// Package blurhash implements an encoder for the BlurHash compact
+// image placeholder representation (https://blurha.sh).
+package blurhash
+
+import (
+	"errors"
+	"image"
+	"math"
+	"strings"
+)
+
+const base83Chars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+
+// Encode calculates the BlurHash string of the provided image.
+//
+// xComponents and yComponents specify the number of the horizontal
+// and vertical hash components and must be in the 1-9 range.
+//
+// Note that the calculation is proportional to the number of pixels,
+// so consider downscaling larger images before encoding them.
+func Encode(img image.Image, xComponents int, yComponents int) (string, error) {
+	if xComponents < 1 || xComponents > 9 || yComponents < 1 || yComponents > 9 {
+		return "", errors.New("The BlurHash components must be in the 1-9 range.")
+	}
+
+	bounds := img.Bounds()
+	width := bounds.Dx()
+	height := bounds.Dy()
+	if width == 0 || height == 0 {
+		return "", errors.New("Cannot encode an empty image.")
+	}
+
+	// convert once the pixels to linear rgb
+	linear := make([][3]float64, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			r, g, b, _ := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			linear[y*width+x] = [3]float64{
+				sRGBToLinear(r >> 8),
+				sRGBToLinear(g >> 8),
+				sRGBToLinear(b >> 8),
+			}
+		}
+	}
+
+	factors := make([][3]float64, 0, xComponents*yComponents)
+	for j := 0; j < yComponents; j++ {
+		for i := 0; i < xComponents; i++ {
+			factors = append(factors, multiplyBasis(linear, width, height, i, j))
+		}
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(encode83((xComponents-1)+(yComponents-1)*9, 1))
+
+	dc := factors[0]
+	ac := factors[1:]
+
+	maxValue := 1.0
+	if len(ac) > 0 {
+		var actualMax float64
+		for _, f := range ac {
+			actualMax = math.Max(actualMax, math.Max(math.Abs(f[0]), math.Max(math.Abs(f[1]), math.Abs(f[2]))))
+		}
+
+		quantisedMax := clamp(int(math.Floor(actualMax*166-0.5)), 0, 82)
+		maxValue = float64(quantisedMax+1) / 166
+		sb.WriteString(encode83(quantisedMax, 1))
+	} else {
+		sb.WriteString(encode83(0, 1))
+	}
+
+	sb.WriteString(encode83(encodeDC(dc), 4))
+
+	for _, f := range ac {
+		sb.WriteString(encode83(encodeAC(f, maxValue), 2))
+	}
+
+	return sb.String(), nil
+}
+
+func multiplyBasis(linear [][3]float64, width int, height int, i int, j int) [3]float64 {
+	var result [3]float64
+
+	normalisation := 2.0
+	if i == 0 && j == 0 {
+		normalisation = 1
+	}
+
+	for y := 0; y < height; y++ {
+		cosY := math.Cos(math.Pi * float64(j) * float64(y) / float64(height))
+		for x := 0; x < width; x++ {
+			basis := normalisation * math.Cos(math.Pi*float64(i)*float64(x)/float64(width)) * cosY
+			pixel := linear[y*width+x]
+			result[0] += basis * pixel[0]
+			result[1] += basis * pixel[1]
+			result[2] += basis * pixel[2]
+		}
+	}
+
+	scale := 1 / float64(width*height)
+	result[0] *= scale
+	result[1] *= scale
+	result[2] *= scale
+
+	return result
+}
+
+func encodeDC(value [3]float64) int {
+	r := linearToSRGB(value[0])
+	g := linearToSRGB(value[1])
+	b := linearToSRGB(value[2])
+
+	return (r << 16) + (g << 8) + b
+}
+
+func encodeAC(value [3]float64, maxValue float64) int {
+	quantR := clamp(int(math.Floor(signPow(value[0]/maxValue, 0.5)*9+9.5)), 0, 18)
+	quantG := clamp(int(math.Floor(signPow(value[1]/maxValue, 0.5)*9+9.5)), 0, 18)
+	quantB := clamp(int(math.Floor(signPow(value[2]/maxValue, 0.5)*9+9.5)), 0, 18)
+
+	return quantR*19*19 + quantG*19 + quantB
+}
+
+func encode83(value int, length int) string {
+	result := make([]byte, length)
+
+	for i := 1; i <= length; i++ {
+		digit := (value / int(math.Pow(83, float64(length-i)))) % 83
+		result[i-1] = base83Chars[digit]
+	}
+
+	return string(result)
+}
+
+func sRGBToLinear(value uint32) float64 {
+	v := float64(value) / 255
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func linearToSRGB(value float64) int {
+	v := math.Max(0, math.Min(1, value))
+	if v <= 0.0031308 {
+		return int(v*12.92*255 + 0.5)
+	}
+
+	return int((1.055*math.Pow(v, 1/2.4)-0.055)*255 + 0.5)
+}
+
+func signPow(value float64, exp float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(value), exp), value)
+}
+
+func clamp(value int, min int, max int) int {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
@@ -0,0 +1,107 @@
+package blurhash_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/blurhash"
+)
+
+func newTestImage(width int, height int, fill func(x, y int) color.Color) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, fill(x, y))
+		}
+	}
+
+	return img
+}
+
+func TestEncodeInvalid(t *testing.T) {
+	img := newTestImage(2, 2, func(x, y int) color.Color { return color.White })
+
+	scenarios := []struct {
+		img image.Image
+		x   int
+		y   int
+	}{
+		{img, 0, 3},
+		{img, 4, 0},
+		{img, 10, 3},
+		{img, 4, 10},
+		{image.NewNRGBA(image.Rect(0, 0, 0, 0)), 4, 3},
+	}
+
+	for i, s := range scenarios {
+		if _, err := blurhash.Encode(s.img, s.x, s.y); err == nil {
+			t.Errorf("(%d) Expected error, got nil", i)
+		}
+	}
+}
+
+func TestEncodeSolidColor(t *testing.T) {
+	scenarios := []struct {
+		color    color.Color
+		expected string
+	}{
+		{color.White, "00TSUA"},
+		{color.Black, "000000"},
+		{color.NRGBA{R: 255, A: 255}, "00TI:j"},
+	}
+
+	for i, s := range scenarios {
+		img := newTestImage(8, 6, func(x, y int) color.Color { return s.color })
+
+		hash, err := blurhash.Encode(img, 1, 1)
+		if err != nil {
+			t.Errorf("(%d) Unexpected error %v", i, err)
+			continue
+		}
+
+		if hash != s.expected {
+			t.Errorf("(%d) Expected %q, got %q", i, s.expected, hash)
+		}
+	}
+}
+
+func TestEncodeComponents(t *testing.T) {
+	img := newTestImage(32, 16, func(x, y int) color.Color {
+		v := uint8(x * 255 / 31)
+		return color.NRGBA{R: v, G: v, B: 255 - v, A: 255}
+	})
+
+	scenarios := []struct {
+		x              int
+		y              int
+		expectedLength int
+		expectedFlag   byte
+	}{
+		{1, 1, 6, '0'},
+		{4, 3, 28, 'L'},
+		{9, 9, 166, '|'},
+	}
+
+	for i, s := range scenarios {
+		hash, err := blurhash.Encode(img, s.x, s.y)
+		if err != nil {
+			t.Errorf("(%d) Unexpected error %v", i, err)
+			continue
+		}
+
+		if len(hash) != s.expectedLength {
+			t.Errorf("(%d) Expected hash with %d characters, got %q", i, s.expectedLength, hash)
+		}
+
+		if hash[0] != s.expectedFlag {
+			t.Errorf("(%d) Expected size flag %q, got %q", i, s.expectedFlag, hash[0])
+		}
+
+		// should be deterministic
+		if hash2, _ := blurhash.Encode(img, s.x, s.y); hash2 != hash {
+			t.Errorf("(%d) Expected the same hash on repeated encode, got %q and %q", i, hash, hash2)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+// Package cron implements a crontab-like service to execute and schedule
+// repetitive tasks/jobs.
+//
+// Example:
+//
+//	c := cron.New()
+//	c.MustAdd("dailyReport", "0 0 * * *", func() { ... })
+//	c.Start()
+package cron
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+type job struct {
+	schedule *Schedule
+	run      func()
+}
+
+// Cron is a crontab-like struct for tasks/jobs scheduling.
+type Cron struct {
+	mux sync.RWMutex
+
+	interval time.Duration
+	timezone *time.Location
+	ticker   *time.Ticker
+	jobs     map[string]*job
+	stopCh   chan struct{}
+}
+
+// New create a new Cron struct with default tick interval of 1 minute
+// and timezone in UTC.
+//
+// You can change the default tick interval with Cron.SetInterval().
+// You can change the default timezone with Cron.SetTimezone().
+func New() *Cron {
+	return &Cron{
+		interval: 1 * time.Minute,
+		timezone: time.UTC,
+		jobs:     map[string]*job{},
+	}
+}
+
+// SetInterval changes the current cron tick interval
+// (it usually should be >= 1 minute).
+func (c *Cron) SetInterval(d time.Duration) {
+	// update interval
+	c.mux.Lock()
+	wasStarted := c.stopCh != nil
+	c.interval = d
+	c.mux.Unlock()
+
+	// restart the ticker
+	if wasStarted {
+		c.Start()
+	}
+}
+
+// SetTimezone changes the current cron tick timezone.
+func (c *Cron) SetTimezone(l *time.Location) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.timezone = l
+}
+
+// MustAdd is similar to Add() but panic on failure.
+func (c *Cron) MustAdd(jobId string, cronExpr string, run func()) {
+	if err := c.Add(jobId, cronExpr, run); err != nil {
+		panic(err)
+	}
+}
+
+// Add registers a single cron job.
+//
+// If there is already a job with the provided id, then the old job
+// will be replaced with the new one.
+//
+// cronExpr is a regular cron expression, eg. "0 */3 * * *" (aka. at minute 0 past every 3rd hour).
+// Check cron.NewSchedule() for the supported tokens.
+func (c *Cron) Add(jobId string, cronExpr string, run func()) error {
+	if run == nil {
+		return errors.New("failed to add new cron job: run must be non-nil function")
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	schedule, err := NewSchedule(cronExpr)
+	if err != nil {
+		return errors.New("failed to add new cron job: " + err.Error())
+	}
+
+	c.jobs[jobId] = &job{
+		schedule: schedule,
+		run:      run,
+	}
+
+	return nil
+}
+
+// Remove removes a single cron job by its id.
+func (c *Cron) Remove(jobId string) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	delete(c.jobs, jobId)
+}
+
+// RemoveAll removes all registered cron jobs.
+func (c *Cron) RemoveAll() {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.jobs = map[string]*job{}
+}
+
+// Total returns the current total number of registered cron jobs.
+func (c *Cron) Total() int {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	return len(c.jobs)
+}
+
+// Stop stops the current cron ticker (if not already).
+//
+// You can resume the ticker by calling Start().
+func (c *Cron) Stop() {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if c.stopCh == nil {
+		return // already stopped
+	}
+
+	close(c.stopCh)
+	c.stopCh = nil
+
+	if c.ticker != nil {
+		c.ticker.Stop()
+		c.ticker = nil
+	}
+}
+
+// Start starts the cron ticker.
+//
+// Calling Start() on already started cron will restart the ticker.
+func (c *Cron) Start() {
+	c.Stop()
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	stopCh := make(chan struct{})
+	c.stopCh = stopCh
+
+	// align the first tick with the beginning of the next interval
+	// (eg. at the start of the next minute)
+	now := time.Now()
+	next := now.Truncate(c.interval).Add(c.interval)
+	delay := next.Sub(now)
+
+	go func() {
+		select {
+		case <-stopCh:
+			return
+		case t := <-time.After(delay):
+			c.runDue(t)
+		}
+
+		c.mux.Lock()
+		select {
+		case <-stopCh:
+			c.mux.Unlock()
+			return // stopped while the lock was released
+		default:
+		}
+		ticker := time.NewTicker(c.interval)
+		c.ticker = ticker
+		c.mux.Unlock()
+
+		for {
+			select {
+			case <-stopCh:
+				return
+			case t := <-ticker.C:
+				c.runDue(t)
+			}
+		}
+	}()
+}
+
+// HasStarted checks whether the current Cron ticker has been started.
+func (c *Cron) HasStarted() bool {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	return c.stopCh != nil
+}
+
+// runDue runs all registered jobs that are scheduled for the provided time.
+func (c *Cron) runDue(t time.Time) {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	moment := NewMoment(t.In(c.timezone))
+
+	for _, j := range c.jobs {
+		if j.schedule.IsDue(moment) {
+			go j.run()
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package cron
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCronNew(t *testing.T) {
+	c := New()
+
+	if c.interval != 1*time.Minute {
+		t.Fatalf("Expected default interval %v, got %v", 1*time.Minute, c.interval)
+	}
+
+	if c.timezone != time.UTC {
+		t.Fatalf("Expected default timezone %v, got %v", time.UTC, c.timezone)
+	}
+
+	if c.Total() != 0 {
+		t.Fatalf("Expected no jobs, got %d", c.Total())
+	}
+
+	if c.HasStarted() {
+		t.Fatal("Expected the cron to not be started")
+	}
+}
+
+func TestCronSetInterval(t *testing.T) {
+	c := New()
+
+	c.SetInterval(2 * time.Minute)
+
+	if c.interval != 2*time.Minute {
+		t.Fatalf("Expected interval %v, got %v", 2*time.Minute, c.interval)
+	}
+
+	if c.HasStarted() {
+		t.Fatal("Expected SetInterval to not start a stopped cron")
+	}
+}
+
+func TestCronSetTimezone(t *testing.T) {
+	c := New()
+
+	timezone, _ := time.LoadLocation("Asia/Tokyo")
+
+	c.SetTimezone(timezone)
+
+	if c.timezone != timezone {
+		t.Fatalf("Expected timezone %v, got %v", timezone, c.timezone)
+	}
+}
+
+func TestCronAddAndRemove(t *testing.T) {
+	c := New()
+
+	if err := c.Add("test0", "* * * * *", nil); err == nil {
+		t.Fatal("Expected nil function error")
+	}
+
+	if err := c.Add("test1", "invalid", func() {}); err == nil {
+		t.Fatal("Expected invalid cron expression error")
+	}
+
+	c.Add("test2", "* * * * *", func() {})
+	c.Add("test3", "* * * * *", func() {})
+	c.Add("test4", "@daily", func() {})
+	c.Add("test4", "@hourly", func() {}) // replace
+
+	if total := c.Total(); total != 3 {
+		t.Fatalf("Expected %d jobs, got %d", 3, total)
+	}
+
+	if expr := c.jobs["test4"].schedule; len(expr.Hours) != 24 {
+		t.Fatalf("Expected test4 to be replaced with the @hourly schedule, got %v", expr)
+	}
+
+	c.Remove("missing")
+	c.Remove("test2")
+
+	if total := c.Total(); total != 2 {
+		t.Fatalf("Expected %d jobs, got %d", 2, total)
+	}
+
+	if _, ok := c.jobs["test2"]; ok {
+		t.Fatal("Expected test2 to be removed")
+	}
+
+	c.RemoveAll()
+
+	if total := c.Total(); total != 0 {
+		t.Fatalf("Expected no jobs, got %d", total)
+	}
+}
+
+func TestCronMustAdd(t *testing.T) {
+	c := New()
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected MustAdd to panic")
+		}
+	}()
+
+	c.MustAdd("test1", "* * * * * *", func() {})
+}
+
+func TestCronStartStop(t *testing.T) {
+	c := New()
+
+	c.SetInterval(10 * time.Millisecond)
+
+	var mux sync.Mutex
+	calls := 0
+
+	c.Add("test", "* * * * *", func() {
+		mux.Lock()
+		calls++
+		mux.Unlock()
+	})
+
+	c.Start()
+	if !c.HasStarted() {
+		t.Fatal("Expected the cron to be started")
+	}
+
+	// start again to ensure that it doesn't leave stray tickers
+	c.Start()
+
+	time.Sleep(55 * time.Millisecond)
+
+	c.Stop()
+	if c.HasStarted() {
+		t.Fatal("Expected the cron to be stopped")
+	}
+
+	mux.Lock()
+	totalCalls := calls
+	mux.Unlock()
+
+	if totalCalls < 3 || totalCalls > 6 {
+		t.Fatalf("Expected between 3 and 6 calls, got %d", totalCalls)
+	}
+
+	// no more calls after stop
+	time.Sleep(30 * time.Millisecond)
+
+	mux.Lock()
+	defer mux.Unlock()
+	if calls != totalCalls {
+		t.Fatalf("Expected no calls after stop, got %d new calls", calls-totalCalls)
+	}
+}
@@ -0,0 +1,171 @@
+package cron
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestNewMoment(t *testing.T) {
+	date, err := time.Parse("2006-01-02 15:04", "2023-05-09 15:20")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewMoment(date)
+
+	if m.Minute != 20 {
+		t.Fatalf("Expected m.Minute %d, got %d", 20, m.Minute)
+	}
+
+	if m.Hour != 15 {
+		t.Fatalf("Expected m.Hour %d, got %d", 15, m.Hour)
+	}
+
+	if m.Day != 9 {
+		t.Fatalf("Expected m.Day %d, got %d", 9, m.Day)
+	}
+
+	if m.Month != 5 {
+		t.Fatalf("Expected m.Month %d, got %d", 5, m.Month)
+	}
+
+	if m.DayOfWeek != 2 {
+		t.Fatalf("Expected m.DayOfWeek %d, got %d", 2, m.DayOfWeek)
+	}
+}
+
+func TestNewSchedule(t *testing.T) {
+	scenarios := []struct {
+		cronExpr       string
+		expectError    bool
+		expectSchedule string
+	}{
+		{"invalid", true, ""},
+		{"* * * *", true, ""},
+		{"* * * * * *", true, ""},
+		{"2/3 * * * *", true, ""},
+		{"60 * * * *", true, ""},
+		{"* 24 * * *", true, ""},
+		{"* * 0 * *", true, ""},
+		{"* * * 13 *", true, ""},
+		{"* * * * 7", true, ""},
+		{"5-2 * * * *", true, ""},
+		{"*/0 * * * *", true, ""},
+		{"a * * * *", true, ""},
+		{"1-2-3 * * * *", true, ""},
+		{"*/2/3 * * * *", true, ""},
+		{
+			"* * * * *",
+			false,
+			`{"minutes":{"0":{},"1":{},"10":{},"11":{},"12":{},"13":{},"14":{},"15":{},"16":{},"17":{},"18":{},"19":{},"2":{},"20":{},"21":{},"22":{},"23":{},"24":{},"25":{},"26":{},"27":{},"28":{},"29":{},"3":{},"30":{},"31":{},"32":{},"33":{},"34":{},"35":{},"36":{},"37":{},"38":{},"39":{},"4":{},"40":{},"41":{},"42":{},"43":{},"44":{},"45":{},"46":{},"47":{},"48":{},"49":{},"5":{},"50":{},"51":{},"52":{},"53":{},"54":{},"55":{},"56":{},"57":{},"58":{},"59":{},"6":{},"7":{},"8":{},"9":{}},"hours":{"0":{},"1":{},"10":{},"11":{},"12":{},"13":{},"14":{},"15":{},"16":{},"17":{},"18":{},"19":{},"2":{},"20":{},"21":{},"22":{},"23":{},"3":{},"4":{},"5":{},"6":{},"7":{},"8":{},"9":{}},"days":{"1":{},"10":{},"11":{},"12":{},"13":{},"14":{},"15":{},"16":{},"17":{},"18":{},"19":{},"2":{},"20":{},"21":{},"22":{},"23":{},"24":{},"25":{},"26":{},"27":{},"28":{},"29":{},"3":{},"30":{},"31":{},"4":{},"5":{},"6":{},"7":{},"8":{},"9":{}},"months":{"1":{},"10":{},"11":{},"12":{},"2":{},"3":{},"4":{},"5":{},"6":{},"7":{},"8":{},"9":{}},"daysOfWeek":{"0":{},"1":{},"2":{},"3":{},"4":{},"5":{},"6":{}}}`,
+		},
+		{
+			"*/10 1,5 10-12 1-6/2 0-6/3",
+			false,
+			`{"minutes":{"0":{},"10":{},"20":{},"30":{},"40":{},"50":{}},"hours":{"1":{},"5":{}},"days":{"10":{},"11":{},"12":{}},"months":{"1":{},"3":{},"5":{}},"daysOfWeek":{"0":{},"3":{},"6":{}}}`,
+		},
+		{
+			"@yearly",
+			false,
+			`{"minutes":{"0":{}},"hours":{"0":{}},"days":{"1":{}},"months":{"1":{}},"daysOfWeek":{"0":{},"1":{},"2":{},"3":{},"4":{},"5":{},"6":{}}}`,
+		},
+		{
+			"@weekly",
+			false,
+			`{"minutes":{"0":{}},"hours":{"0":{}},"days":{"1":{},"10":{},"11":{},"12":{},"13":{},"14":{},"15":{},"16":{},"17":{},"18":{},"19":{},"2":{},"20":{},"21":{},"22":{},"23":{},"24":{},"25":{},"26":{},"27":{},"28":{},"29":{},"3":{},"30":{},"31":{},"4":{},"5":{},"6":{},"7":{},"8":{},"9":{}},"months":{"1":{},"10":{},"11":{},"12":{},"2":{},"3":{},"4":{},"5":{},"6":{},"7":{},"8":{},"9":{}},"daysOfWeek":{"0":{}}}`,
+		},
+		{
+			"@hourly",
+			false,
+			`{"minutes":{"0":{}},"hours":{"0":{},"1":{},"10":{},"11":{},"12":{},"13":{},"14":{},"15":{},"16":{},"17":{},"18":{},"19":{},"2":{},"20":{},"21":{},"22":{},"23":{},"3":{},"4":{},"5":{},"6":{},"7":{},"8":{},"9":{}},"days":{"1":{},"10":{},"11":{},"12":{},"13":{},"14":{},"15":{},"16":{},"17":{},"18":{},"19":{},"2":{},"20":{},"21":{},"22":{},"23":{},"24":{},"25":{},"26":{},"27":{},"28":{},"29":{},"3":{},"30":{},"31":{},"4":{},"5":{},"6":{},"7":{},"8":{},"9":{}},"months":{"1":{},"10":{},"11":{},"12":{},"2":{},"3":{},"4":{},"5":{},"6":{},"7":{},"8":{},"9":{}},"daysOfWeek":{"0":{},"1":{},"2":{},"3":{},"4":{},"5":{},"6":{}}}`,
+		},
+	}
+
+	for _, s := range scenarios {
+		schedule, err := NewSchedule(s.cronExpr)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr to be %v, got %v (%v)", s.cronExpr, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		encoded, err := json.Marshal(schedule)
+		if err != nil {
+			t.Errorf("[%s] Failed to marshalize the result schedule: %v", s.cronExpr, err)
+			continue
+		}
+
+		if v := string(encoded); v != s.expectSchedule {
+			t.Errorf("[%s] Expected \n%s, \ngot \n%s", s.cronExpr, s.expectSchedule, v)
+		}
+	}
+}
+
+func TestScheduleIsDue(t *testing.T) {
+	scenarios := []struct {
+		cronExpr string
+		moment   *Moment
+		expected bool
+	}{
+		{
+			"* * * * *",
+			&Moment{},
+			false,
+		},
+		{
+			"* * * * *",
+			&Moment{Minute: 1, Hour: 1, Day: 1, Month: 1, DayOfWeek: 1},
+			true,
+		},
+		{
+			"5 * * * *",
+			&Moment{Minute: 1, Hour: 1, Day: 1, Month: 1, DayOfWeek: 1},
+			false,
+		},
+		{
+			"5 * * * *",
+			&Moment{Minute: 5, Hour: 1, Day: 1, Month: 1, DayOfWeek: 1},
+			true,
+		},
+		{
+			"*/3 2 * * *",
+			&Moment{Minute: 6, Hour: 2, Day: 1, Month: 1, DayOfWeek: 1},
+			true,
+		},
+		{
+			"*/3 2 * * *",
+			&Moment{Minute: 6, Hour: 3, Day: 1, Month: 1, DayOfWeek: 1},
+			false,
+		},
+		{
+			"@daily",
+			&Moment{Minute: 0, Hour: 0, Day: 5, Month: 6, DayOfWeek: 3},
+			true,
+		},
+		{
+			"* * * * 0",
+			&Moment{Minute: 1, Hour: 1, Day: 1, Month: 1, DayOfWeek: 1},
+			false,
+		},
+	}
+
+	for i, s := range scenarios {
+		schedule, err := NewSchedule(s.cronExpr)
+		if err != nil {
+			t.Errorf("[%d-%s] Unexpected cron error: %v", i, s.cronExpr, err)
+			continue
+		}
+
+		result := schedule.IsDue(s.moment)
+
+		if result != s.expected {
+			t.Errorf("[%d-%s] Expected %v, got %v", i, s.cronExpr, s.expected, result)
+		}
+	}
+}
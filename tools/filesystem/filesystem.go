@@ -3,7 +3,6 @@ package filesystem
 import (
 	"context"
 	"errors"
-	"image"
 	"io"
 	"mime/multipart"
 	"net/http"
@@ -11,13 +10,11 @@ import (
 	"path/filepath"
 	"regexp"
 	"sort"
-	"strconv"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/disintegration/imaging"
 	"github.com/gabriel-vasile/mimetype"
 	"github.com/pocketbase/pocketbase/tools/list"
 	"gocloud.dev/blob"
@@ -97,6 +94,28 @@ func (s *System) Attributes(fileKey string) (*blob.Attributes, error) {
 	return s.bucket.Attributes(s.ctx, fileKey)
 }
 
+// List returns a flat list with info for all files under the specified prefix.
+func (s *System) List(prefix string) ([]*blob.ListObject, error) {
+	files := []*blob.ListObject{}
+
+	iter := s.bucket.List(&blob.ListOptions{
+		Prefix: prefix,
+	})
+
+	for {
+		obj, err := iter.Next(s.ctx)
+		if err != nil {
+			if err != io.EOF {
+				return nil, err
+			}
+			break
+		}
+		files = append(files, obj)
+	}
+
+	return files, nil
+}
+
 // GetFile returns a file content reader for the given fileKey.
 //
 // NB! Make sure to call `Close()` after you are done working with it.
@@ -348,75 +367,9 @@ var ThumbSizeRegex = regexp.MustCompile(`^(\d+)x(\d+)(t|b|f)?$`)
 // - WxHt (eg. 300x100t) - resize and crop to WxH viewbox (from top)
 // - WxHb (eg. 300x100b) - resize and crop to WxH viewbox (from bottom)
 // - WxHf (eg. 300x100f) - fit inside a WxH viewbox (without cropping)
+//
+// The thumb format is detected from the thumbKey extension (fallbacks to png).
+// Use [System.CreateThumbWithOptions] for explicit format and quality.
 func (s *System) CreateThumb(originalKey string, thumbKey, thumbSize string) error {
-	sizeParts := ThumbSizeRegex.FindStringSubmatch(thumbSize)
-	if len(sizeParts) != 4 {
-		return errors.New("Thumb size must be in WxH, WxHt, WxHb or WxHf format.")
-	}
-
-	width, _ := strconv.Atoi(sizeParts[1])
-	height, _ := strconv.Atoi(sizeParts[2])
-	resizeType := sizeParts[3]
-
-	if width == 0 && height == 0 {
-		return errors.New("Thumb width and height cannot be zero at the same time.")
-	}
-
-	// fetch the original
-	r, readErr := s.bucket.NewReader(s.ctx, originalKey, nil)
-	if readErr != nil {
-		return readErr
-	}
-	defer r.Close()
-
-	// create imaging object from the original reader
-	// (note: only the first frame for animated image formats)
-	img, decodeErr := imaging.Decode(r, imaging.AutoOrientation(true))
-	if decodeErr != nil {
-		return decodeErr
-	}
-
-	var thumbImg *image.NRGBA
-
-	if width == 0 || height == 0 {
-		// force resize preserving aspect ratio
-		thumbImg = imaging.Resize(img, width, height, imaging.CatmullRom)
-	} else {
-		switch resizeType {
-		case "f":
-			// fit
-			thumbImg = imaging.Fit(img, width, height, imaging.CatmullRom)
-		case "t":
-			// fill and crop from top
-			thumbImg = imaging.Fill(img, width, height, imaging.Top, imaging.CatmullRom)
-		case "b":
-			// fill and crop from bottom
-			thumbImg = imaging.Fill(img, width, height, imaging.Bottom, imaging.CatmullRom)
-		default:
-			// fill and crop from center
-			thumbImg = imaging.Fill(img, width, height, imaging.Center, imaging.CatmullRom)
-		}
-	}
-
-	// open a thumb storage writer (aka. prepare for upload)
-	w, writerErr := s.bucket.NewWriter(s.ctx, thumbKey, nil)
-	if writerErr != nil {
-		return writerErr
-	}
-
-	// try to detect the thumb format based on the original file name
-	// (fallbacks to png on error)
-	format, err := imaging.FormatFromFilename(thumbKey)
-	if err != nil {
-		format = imaging.PNG
-	}
-
-	// thumb encode (aka. upload)
-	if err := imaging.Encode(w, thumbImg, format); err != nil {
-		w.Close()
-		return err
-	}
-
-	// check for close errors to ensure that the thumb was really saved
-	return w.Close()
+	return s.CreateThumbWithOptions(originalKey, thumbKey, ThumbOptions{Size: thumbSize})
 }
@@ -82,6 +82,45 @@ func TestFileSystemAttributes(t *testing.T) {
 	}
 }
 
+func TestFileSystemList(t *testing.T) {
+	dir := createTestDir(t)
+	defer os.RemoveAll(dir)
+
+	fs, err := filesystem.NewLocal(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	scenarios := []struct {
+		prefix   string
+		expected []string
+	}{
+		{"missing/", []string{}},
+		{"test/", []string{"test/sub1.txt", "test/sub2.txt"}},
+		{"image.", []string{"image.png", "image.svg"}},
+	}
+
+	for i, scenario := range scenarios {
+		objs, err := fs.List(scenario.prefix)
+		if err != nil {
+			t.Errorf("(%d) Unexpected error %v", i, err)
+			continue
+		}
+
+		if len(objs) != len(scenario.expected) {
+			t.Errorf("(%d) Expected %d objects, got %d", i, len(scenario.expected), len(objs))
+			continue
+		}
+
+		for j, key := range scenario.expected {
+			if objs[j].Key != key {
+				t.Errorf("(%d) Expected key %q at position %d, got %q", i, key, j, objs[j].Key)
+			}
+		}
+	}
+}
+
 func TestFileSystemDelete(t *testing.T) {
 	dir := createTestDir(t)
 	defer os.RemoveAll(dir)
@@ -0,0 +1,354 @@
+package filesystem
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/disintegration/imaging"
+	"github.com/pocketbase/pocketbase/tools/blurhash"
+)
+
+// DefaultImageQuality is the default quality of the lossy image formats.
+const DefaultImageQuality = 85
+
+// ImageEncodeFunc defines an image encoder function.
+//
+// quality is in the 1-100 range and could be ignored by the lossless formats.
+type ImageEncodeFunc func(w io.Writer, img image.Image, quality int) error
+
+// ImageFormat defines a single thumb output image format.
+type ImageFormat struct {
+	// Name is the unique format identifier (eg. "webp").
+	Name string
+
+	// ContentType is the format mime type (eg. "image/webp").
+	ContentType string
+
+	// Extension is the format file extension (eg. ".webp").
+	Extension string
+
+	// Encode encodes the image in the current format.
+	Encode ImageEncodeFunc
+}
+
+var imageFormatsMux sync.RWMutex
+
+// imageFormats lists the registered thumb output image formats.
+var imageFormats = map[string]ImageFormat{
+	"jpeg": {
+		Name:        "jpeg",
+		ContentType: "image/jpeg",
+		Extension:   ".jpg",
+		Encode: func(w io.Writer, img image.Image, quality int) error {
+			return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+		},
+	},
+	"png": {
+		Name:        "png",
+		ContentType: "image/png",
+		Extension:   ".png",
+		Encode: func(w io.Writer, img image.Image, quality int) error {
+			return png.Encode(w, img)
+		},
+	},
+	"gif": {
+		Name:        "gif",
+		ContentType: "image/gif",
+		Extension:   ".gif",
+		Encode: func(w io.Writer, img image.Image, quality int) error {
+			return gif.Encode(w, img, nil)
+		},
+	},
+}
+
+// RegisterImageFormat registers a new (or replaces an existing) thumb output format.
+//
+// Only jpeg, png and gif are available by default. Other formats (eg. webp, avif)
+// could be enabled by registering an encoder from a third party package:
+//
+//	filesystem.RegisterImageFormat(filesystem.ImageFormat{
+//		Name:        "webp",
+//		ContentType: "image/webp",
+//		Extension:   ".webp",
+//		Encode: func(w io.Writer, img image.Image, quality int) error {
+//			return webp.Encode(w, img, &webp.Options{Quality: float32(quality)})
+//		},
+//	})
+func RegisterImageFormat(format ImageFormat) error {
+	if format.Name == "" || format.ContentType == "" || format.Extension == "" || format.Encode == nil {
+		return errors.New("The image format name, content type, extension and encoder are required.")
+	}
+
+	imageFormatsMux.Lock()
+	defer imageFormatsMux.Unlock()
+
+	imageFormats[format.Name] = format
+
+	return nil
+}
+
+// FindImageFormat returns the registered thumb output format with the specified name.
+func FindImageFormat(name string) (ImageFormat, bool) {
+	imageFormatsMux.RLock()
+	defer imageFormatsMux.RUnlock()
+
+	format, ok := imageFormats[name]
+
+	return format, ok
+}
+
+// ImageFormatNames returns the sorted names of all registered thumb output formats.
+func ImageFormatNames() []string {
+	imageFormatsMux.RLock()
+	defer imageFormatsMux.RUnlock()
+
+	names := make([]string, 0, len(imageFormats))
+	for name := range imageFormats {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// ThumbOptions defines the options for creating a new thumb image.
+type ThumbOptions struct {
+	// Size is the thumb size in one of the [System.CreateThumb] formats.
+	//
+	// Leave it empty to preserve the original image dimensions
+	// (eg. for format conversion only).
+	Size string
+
+	// Format is the name of a registered [ImageFormat].
+	//
+	// If empty, the format is detected from the thumb key extension (fallbacks to png).
+	Format string
+
+	// Quality is the lossy formats quality in the 1-100 range (default to [DefaultImageQuality]).
+	Quality int
+}
+
+// CreateThumbWithOptions creates a new thumb image for the file at
+// originalKey location based on the provided options.
+// The new thumb file is stored at thumbKey location.
+func (s *System) CreateThumbWithOptions(originalKey string, thumbKey string, options ThumbOptions) error {
+	format, err := resolveThumbFormat(thumbKey, options.Format)
+	if err != nil {
+		return err
+	}
+
+	quality := options.Quality
+	if quality <= 0 || quality > 100 {
+		quality = DefaultImageQuality
+	}
+
+	// fetch the original
+	r, readErr := s.bucket.NewReader(s.ctx, originalKey, nil)
+	if readErr != nil {
+		return readErr
+	}
+	defer r.Close()
+
+	// create imaging object from the original reader
+	// (note: only the first frame for animated image formats)
+	img, decodeErr := imaging.Decode(r, imaging.AutoOrientation(true))
+	if decodeErr != nil {
+		return decodeErr
+	}
+
+	thumbImg, err := resizeImage(img, options.Size)
+	if err != nil {
+		return err
+	}
+
+	// open a thumb storage writer (aka. prepare for upload)
+	w, writerErr := s.bucket.NewWriter(s.ctx, thumbKey, nil)
+	if writerErr != nil {
+		return writerErr
+	}
+
+	// thumb encode (aka. upload)
+	if err := format.Encode(w, thumbImg, quality); err != nil {
+		w.Close()
+		return err
+	}
+
+	// check for close errors to ensure that the thumb was really saved
+	return w.Close()
+}
+
+func resolveThumbFormat(thumbKey string, name string) (ImageFormat, error) {
+	if name != "" {
+		format, ok := FindImageFormat(name)
+		if !ok {
+			return format, fmt.Errorf("Unsupported image format %q.", name)
+		}
+		return format, nil
+	}
+
+	// try to detect the thumb format based on the thumb file name
+	// (fallbacks to png on error)
+	detected, err := imaging.FormatFromFilename(thumbKey)
+	if err != nil {
+		detected = imaging.PNG
+	}
+
+	format, ok := FindImageFormat(strings.ToLower(detected.String()))
+	if !ok {
+		format, _ = FindImageFormat("png")
+	}
+
+	return format, nil
+}
+
+func resizeImage(img image.Image, size string) (image.Image, error) {
+	if size == "" {
+		return img, nil
+	}
+
+	sizeParts := ThumbSizeRegex.FindStringSubmatch(size)
+	if len(sizeParts) != 4 {
+		return nil, errors.New("Thumb size must be in WxH, WxHt, WxHb or WxHf format.")
+	}
+
+	width, _ := strconv.Atoi(sizeParts[1])
+	height, _ := strconv.Atoi(sizeParts[2])
+	resizeType := sizeParts[3]
+
+	if width == 0 && height == 0 {
+		return nil, errors.New("Thumb width and height cannot be zero at the same time.")
+	}
+
+	if width == 0 || height == 0 {
+		// force resize preserving aspect ratio
+		return imaging.Resize(img, width, height, imaging.CatmullRom), nil
+	}
+
+	switch resizeType {
+	case "f":
+		// fit
+		return imaging.Fit(img, width, height, imaging.CatmullRom), nil
+	case "t":
+		// fill and crop from top
+		return imaging.Fill(img, width, height, imaging.Top, imaging.CatmullRom), nil
+	case "b":
+		// fill and crop from bottom
+		return imaging.Fill(img, width, height, imaging.Bottom, imaging.CatmullRom), nil
+	default:
+		// fill and crop from center
+		return imaging.Fill(img, width, height, imaging.Center, imaging.CatmullRom), nil
+	}
+}
+
+// -------------------------------------------------------------------
+
+// ImagePlaceholder defines the low resolution placeholders of an image.
+type ImagePlaceholder struct {
+	// BlurHash is the 4x3 components BlurHash string of the image.
+	BlurHash string `json:"blurHash"`
+
+	// Color is the dominant image color as hex string (eg. "#ff0000").
+	Color string `json:"color"`
+
+	// Width is the original image width (after auto orientation).
+	Width int `json:"width"`
+
+	// Height is the original image height (after auto orientation).
+	Height int `json:"height"`
+}
+
+// CreateImagePlaceholder generates the placeholders of the image file at fileKey location.
+func (s *System) CreateImagePlaceholder(fileKey string) (*ImagePlaceholder, error) {
+	r, readErr := s.bucket.NewReader(s.ctx, fileKey, nil)
+	if readErr != nil {
+		return nil, readErr
+	}
+	defer r.Close()
+
+	img, decodeErr := imaging.Decode(r, imaging.AutoOrientation(true))
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
+
+	return NewImagePlaceholder(img)
+}
+
+// NewImagePlaceholder generates the placeholders of the provided image.
+func NewImagePlaceholder(img image.Image) (*ImagePlaceholder, error) {
+	bounds := img.Bounds()
+
+	// the placeholders are calculated from a small version of the image
+	// since the hash and the colors distribution don't need the details
+	small := imaging.Fit(img, 32, 32, imaging.Box)
+
+	hash, err := blurhash.Encode(small, 4, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ImagePlaceholder{
+		BlurHash: hash,
+		Color:    dominantColor(small),
+		Width:    bounds.Dx(),
+		Height:   bounds.Dy(),
+	}, nil
+}
+
+// dominantColor returns the average color of the most common
+// color bucket of the provided image as hex string.
+//
+// Fully transparent pixels are ignored.
+func dominantColor(img image.Image) string {
+	type bucket struct {
+		count   int
+		r, g, b int
+	}
+
+	buckets := map[int]*bucket{}
+	var top *bucket
+
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if c.A == 0 {
+				continue
+			}
+
+			// 4 bits per channel
+			key := int(c.R>>4)<<8 | int(c.G>>4)<<4 | int(c.B>>4)
+
+			b, ok := buckets[key]
+			if !ok {
+				b = &bucket{}
+				buckets[key] = b
+			}
+
+			b.count++
+			b.r += int(c.R)
+			b.g += int(c.G)
+			b.b += int(c.B)
+
+			if top == nil || b.count > top.count {
+				top = b
+			}
+		}
+	}
+
+	if top == nil {
+		return "#000000"
+	}
+
+	return fmt.Sprintf("#%02x%02x%02x", top.r/top.count, top.g/top.count, top.b/top.count)
+}
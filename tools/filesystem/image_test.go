@@ -0,0 +1,222 @@
+package filesystem_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+	"github.com/pocketbase/pocketbase/tools/list"
+)
+
+func TestRegisterImageFormat(t *testing.T) {
+	encode := func(w io.Writer, img image.Image, quality int) error {
+		_, err := w.Write([]byte("test"))
+		return err
+	}
+
+	scenarios := []struct {
+		name        string
+		format      filesystem.ImageFormat
+		expectError bool
+	}{
+		{"empty", filesystem.ImageFormat{}, true},
+		{"missing name", filesystem.ImageFormat{ContentType: "image/test", Extension: ".test", Encode: encode}, true},
+		{"missing content type", filesystem.ImageFormat{Name: "test", Extension: ".test", Encode: encode}, true},
+		{"missing extension", filesystem.ImageFormat{Name: "test", ContentType: "image/test", Encode: encode}, true},
+		{"missing encoder", filesystem.ImageFormat{Name: "test", ContentType: "image/test", Extension: ".test"}, true},
+		{"valid", filesystem.ImageFormat{Name: "test", ContentType: "image/test", Extension: ".test", Encode: encode}, false},
+	}
+
+	for _, s := range scenarios {
+		err := filesystem.RegisterImageFormat(s.format)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+		}
+	}
+
+	format, ok := filesystem.FindImageFormat("test")
+	if !ok || format.ContentType != "image/test" {
+		t.Fatalf("Expected the test format to be registered, got %v", format)
+	}
+
+	if !list.ExistInSlice("test", filesystem.ImageFormatNames()) {
+		t.Fatalf("Expected the test format in %v", filesystem.ImageFormatNames())
+	}
+}
+
+func TestFindImageFormat(t *testing.T) {
+	scenarios := []struct {
+		name                string
+		expectedFound       bool
+		expectedContentType string
+	}{
+		{"", false, ""},
+		{"missing", false, ""},
+		{"jpeg", true, "image/jpeg"},
+		{"png", true, "image/png"},
+		{"gif", true, "image/gif"},
+	}
+
+	for _, s := range scenarios {
+		format, ok := filesystem.FindImageFormat(s.name)
+
+		if ok != s.expectedFound {
+			t.Errorf("[%s] Expected found %v, got %v", s.name, s.expectedFound, ok)
+			continue
+		}
+
+		if format.ContentType != s.expectedContentType {
+			t.Errorf("[%s] Expected content type %q, got %q", s.name, s.expectedContentType, format.ContentType)
+		}
+	}
+}
+
+func TestFileSystemCreateThumbWithOptions(t *testing.T) {
+	dir := createTestDir(t)
+	defer os.RemoveAll(dir)
+
+	// bigger test image
+	img := image.NewNRGBA(image.Rect(0, 0, 40, 20))
+	f, err := os.Create(filepath.Join(dir, "big.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	png.Encode(f, img)
+	f.Close()
+
+	fs, err := filesystem.NewLocal(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	scenarios := []struct {
+		name           string
+		file           string
+		thumb          string
+		options        filesystem.ThumbOptions
+		expectError    bool
+		expectedFormat string
+		expectedWidth  int
+		expectedHeight int
+	}{
+		{"missing file", "missing.png", "thumb_missing", filesystem.ThumbOptions{Size: "10x10"}, true, "", 0, 0},
+		{"invalid size", "big.png", "thumb_invalid", filesystem.ThumbOptions{Size: "invalid"}, true, "", 0, 0},
+		{"unknown format", "big.png", "thumb_unknown", filesystem.ThumbOptions{Format: "unknown"}, true, "", 0, 0},
+		{"detected format", "big.png", "thumb_detected.gif", filesystem.ThumbOptions{Size: "10x10"}, false, "gif", 10, 10},
+		{"fallback format", "big.png", "thumb_fallback", filesystem.ThumbOptions{Size: "10x0"}, false, "png", 10, 5},
+		{"explicit format", "big.png", "thumb_explicit.png", filesystem.ThumbOptions{Size: "10x10f", Format: "jpeg", Quality: 50}, false, "jpeg", 10, 5},
+		{"conversion only", "big.png", "thumb_conversion", filesystem.ThumbOptions{Format: "jpeg"}, false, "jpeg", 40, 20},
+	}
+
+	for _, s := range scenarios {
+		err := fs.CreateThumbWithOptions(s.file, s.thumb, s.options)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		r, err := fs.GetFile(s.thumb)
+		if err != nil {
+			t.Errorf("[%s] Failed to open the thumb: %v", s.name, err)
+			continue
+		}
+		content, _ := io.ReadAll(r)
+		r.Close()
+
+		cfg, format, err := image.DecodeConfig(bytes.NewReader(content))
+		if err != nil {
+			t.Errorf("[%s] Failed to decode the thumb: %v", s.name, err)
+			continue
+		}
+
+		if format != s.expectedFormat {
+			t.Errorf("[%s] Expected format %q, got %q", s.name, s.expectedFormat, format)
+		}
+
+		if cfg.Width != s.expectedWidth || cfg.Height != s.expectedHeight {
+			t.Errorf("[%s] Expected %dx%d thumb, got %dx%d", s.name, s.expectedWidth, s.expectedHeight, cfg.Width, cfg.Height)
+		}
+	}
+}
+
+func TestFileSystemCreateImagePlaceholder(t *testing.T) {
+	dir := createTestDir(t)
+	defer os.RemoveAll(dir)
+
+	// red image with a small blue corner
+	img := image.NewNRGBA(image.Rect(0, 0, 40, 20))
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 40; x++ {
+			if x < 5 && y < 5 {
+				img.Set(x, y, color.NRGBA{B: 255, A: 255})
+			} else {
+				img.Set(x, y, color.NRGBA{R: 255, A: 255})
+			}
+		}
+	}
+	f, err := os.Create(filepath.Join(dir, "red.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	png.Encode(f, img)
+	f.Close()
+
+	fs, err := filesystem.NewLocal(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	if _, err := fs.CreateImagePlaceholder("missing.png"); err == nil {
+		t.Fatal("Expected error for missing file")
+	}
+
+	if _, err := fs.CreateImagePlaceholder("test/sub1.txt"); err == nil {
+		t.Fatal("Expected error for non-image file")
+	}
+
+	placeholder, err := fs.CreateImagePlaceholder("red.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if placeholder.Width != 40 || placeholder.Height != 20 {
+		t.Fatalf("Expected 40x20 dimensions, got %dx%d", placeholder.Width, placeholder.Height)
+	}
+
+	if placeholder.Color != "#ff0000" {
+		t.Fatalf("Expected #ff0000 dominant color, got %q", placeholder.Color)
+	}
+
+	if len(placeholder.BlurHash) != 28 {
+		t.Fatalf("Expected 4x3 components blurhash, got %q", placeholder.BlurHash)
+	}
+}
+
+func TestNewImagePlaceholderTransparent(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+
+	placeholder, err := filesystem.NewImagePlaceholder(img)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if placeholder.Color != "#000000" {
+		t.Fatalf("Expected #000000 fallback color, got %q", placeholder.Color)
+	}
+}
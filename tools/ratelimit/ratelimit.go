@@ -0,0 +1,110 @@
+// Package ratelimit implements a simple concurrent safe in-memory
+// sliding window rate limiter.
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// window stores the requests counters of a single limiter key.
+type window struct {
+	duration  time.Duration
+	start     time.Time // start of the current fixed window
+	current   int       // number of requests in the current window
+	previous  int       // number of requests in the previous window
+	lastCheck time.Time
+}
+
+// Limiter is an in-memory sliding window rate limiter.
+//
+// The sliding window is approximated by weighting the previous fixed window
+// counter with the remaining part of the current window (similar to the Cloudflare and Nginx approaches).
+type Limiter struct {
+	mux         sync.Mutex
+	windows     map[string]*window
+	lastCleanup time.Time
+
+	// now is used to allow time mocking in the tests
+	now func() time.Time
+}
+
+// New creates and returns a new Limiter instance.
+func New() *Limiter {
+	return &Limiter{
+		windows: map[string]*window{},
+		now:     time.Now,
+	}
+}
+
+// Allow registers a new request hit for the provided key and reports
+// whether it is within the maxRequests per duration limit.
+//
+// If the request is not allowed, the second returned value is
+// the approximate duration after which the client could try again.
+func (l *Limiter) Allow(key string, maxRequests int, duration time.Duration) (bool, time.Duration) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	now := l.now()
+
+	l.cleanup(now)
+
+	w, ok := l.windows[key]
+	if !ok || w.duration != duration {
+		w = &window{duration: duration, start: now}
+		l.windows[key] = w
+	}
+	w.lastCheck = now
+
+	// advance the window(s)
+	elapsed := now.Sub(w.start)
+	if elapsed >= duration {
+		if elapsed >= 2*duration {
+			// no requests in the previous window
+			w.previous = 0
+		} else {
+			w.previous = w.current
+		}
+		w.current = 0
+		w.start = w.start.Add(elapsed / duration * duration)
+		elapsed = now.Sub(w.start)
+	}
+
+	weight := 1 - float64(elapsed)/float64(duration)
+	estimated := int(math.Floor(float64(w.previous)*weight)) + w.current
+
+	if estimated >= maxRequests {
+		return false, w.start.Add(duration).Sub(now)
+	}
+
+	w.current++
+
+	return true, 0
+}
+
+// Reset removes all registered limiter windows.
+func (l *Limiter) Reset() {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	l.windows = map[string]*window{}
+}
+
+// cleanup removes the stale windows (aka. without hits for at least 2 windows).
+//
+// To minimize the overhead the cleanup is performed at most once per minute.
+func (l *Limiter) cleanup(now time.Time) {
+	if now.Sub(l.lastCleanup) < time.Minute {
+		return
+	}
+
+	l.lastCleanup = now
+
+	for key, w := range l.windows {
+		if now.Sub(w.lastCheck) > 2*w.duration {
+			delete(l.windows, key)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiterAllow(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	l := New()
+	l.now = func() time.Time { return now }
+
+	// fill the first window
+	for i := 0; i < 3; i++ {
+		if ok, _ := l.Allow("test", 3, 10*time.Second); !ok {
+			t.Fatalf("Expected request %d to be allowed", i)
+		}
+	}
+
+	ok, retryAfter := l.Allow("test", 3, 10*time.Second)
+	if ok {
+		t.Fatal("Expected the 4th request to be rejected")
+	}
+	if retryAfter != 10*time.Second {
+		t.Fatalf("Expected retryAfter %v, got %v", 10*time.Second, retryAfter)
+	}
+
+	// other keys shouldn't be affected
+	if ok, _ := l.Allow("test2", 3, 10*time.Second); !ok {
+		t.Fatal("Expected test2 request to be allowed")
+	}
+
+	// in the middle of the next window the previous window
+	// requests still have ~50% weight (floor(3*0.5) = 1)
+	now = now.Add(15 * time.Second)
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.Allow("test", 3, 10*time.Second); !ok {
+			t.Fatalf("Expected sliding window request %d to be allowed", i)
+		}
+	}
+	if ok, retryAfter := l.Allow("test", 3, 10*time.Second); ok || retryAfter != 5*time.Second {
+		t.Fatalf("Expected sliding window request to be rejected with retryAfter 5s, got %v, %v", ok, retryAfter)
+	}
+
+	// after 2 full windows without requests the limit is fully restored
+	now = now.Add(25 * time.Second)
+	for i := 0; i < 3; i++ {
+		if ok, _ := l.Allow("test", 3, 10*time.Second); !ok {
+			t.Fatalf("Expected request %d after 2 windows to be allowed", i)
+		}
+	}
+
+	// changing the duration resets the key window
+	if ok, _ := l.Allow("test", 3, 20*time.Second); !ok {
+		t.Fatal("Expected the request with different duration to be allowed")
+	}
+}
+
+func TestLimiterCleanup(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	l := New()
+	l.now = func() time.Time { return now }
+
+	l.Allow("test1", 1, time.Second)
+	l.Allow("test2", 1, time.Hour)
+
+	now = now.Add(2 * time.Minute)
+
+	l.Allow("test3", 1, time.Second)
+
+	if _, ok := l.windows["test1"]; ok {
+		t.Fatal("Expected the stale test1 window to be removed")
+	}
+
+	if _, ok := l.windows["test2"]; !ok {
+		t.Fatal("Expected the test2 window to be preserved")
+	}
+
+	if _, ok := l.windows["test3"]; !ok {
+		t.Fatal("Expected the test3 window to be preserved")
+	}
+}
+
+func TestLimiterReset(t *testing.T) {
+	l := New()
+
+	l.Allow("test", 1, time.Minute)
+
+	if ok, _ := l.Allow("test", 1, time.Minute); ok {
+		t.Fatal("Expected the request to be rejected")
+	}
+
+	l.Reset()
+
+	if ok, _ := l.Allow("test", 1, time.Minute); !ok {
+		t.Fatal("Expected the request to be allowed after reset")
+	}
+}
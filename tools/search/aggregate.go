@@ -0,0 +1,534 @@
+package search
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/list"
+)
+
+// url aggregate query params
+const (
+	GroupByQueryParam string = "groupBy"
+)
+
+// supported aggregate functions
+// (each function is also an url query param, eg. "sum=total,price")
+const (
+	AggregateCount string = "count"
+	AggregateSum   string = "sum"
+	AggregateAvg   string = "avg"
+	AggregateMin   string = "min"
+	AggregateMax   string = "max"
+)
+
+// supported date group buckets
+const (
+	BucketHour  string = "hour"
+	BucketDay   string = "day"
+	BucketWeek  string = "week"
+	BucketMonth string = "month"
+	BucketYear  string = "year"
+)
+
+// AggregateFuncs returns all supported aggregate functions.
+func AggregateFuncs() []string {
+	return []string{
+		AggregateCount,
+		AggregateSum,
+		AggregateAvg,
+		AggregateMin,
+		AggregateMax,
+	}
+}
+
+// bucketExprs defines the date bucket sql expression templates
+// (the week buckets start from Monday).
+var bucketExprs = map[string]string{
+	BucketHour:  "strftime('%%Y-%%m-%%d %%H:00', %s)",
+	BucketDay:   "strftime('%%Y-%%m-%%d', %s)",
+	BucketWeek:  "date(%s, 'weekday 0', '-6 days')",
+	BucketMonth: "strftime('%%Y-%%m', %s)",
+	BucketYear:  "strftime('%%Y', %s)",
+}
+
+// GroupField defines a single aggregate group by field.
+type GroupField struct {
+	Name string `json:"name"`
+
+	// Bucket is an optional date bucket
+	// (one of BucketHour, BucketDay, BucketWeek, BucketMonth, BucketYear).
+	Bucket string `json:"bucket"`
+}
+
+// Key returns the group field result key (eg. "status", "created:month").
+func (f GroupField) Key() string {
+	if f.Bucket == "" {
+		return f.Name
+	}
+
+	return f.Name + ":" + f.Bucket
+}
+
+// ParseGroupByFromString parses the provided string expression
+// into a slice of GroupFields.
+//
+// Example:
+//
+//	fields := search.ParseGroupByFromString("status,created:month")
+func ParseGroupByFromString(str string) (fields []GroupField) {
+	for _, field := range strings.Split(str, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		name, bucket, _ := strings.Cut(field, ":")
+
+		fields = append(fields, GroupField{strings.TrimSpace(name), strings.TrimSpace(bucket)})
+	}
+
+	return
+}
+
+// AggregateField defines a single aggregate function field.
+type AggregateField struct {
+	Func string `json:"func"`
+	Name string `json:"name"`
+}
+
+// Key returns the aggregate field sort key (eg. "count.id").
+func (f AggregateField) Key() string {
+	return f.Func + "." + f.Name
+}
+
+// AggregateProvider represents a single configured
+// aggregate (aka. group by) search provider instance.
+//
+// Each result item is a map with the following structure:
+//
+//	{
+//	  "group": {"status": "open", "created:month": "2023-01"},
+//	  "count": {"id": 10},
+//	  "sum":   {"total": 123.5}
+//	}
+type AggregateProvider struct {
+	fieldResolver FieldResolver
+	query         *dbx.SelectQuery
+	page          int
+	perPage       int
+	groupBy       []GroupField
+	aggregates    []AggregateField
+	sort          []SortField
+	filter        []FilterData
+}
+
+// NewAggregateProvider creates and returns a new aggregate search provider.
+//
+// Example:
+//
+//	baseQuery := db.Select().From("orders")
+//	fieldResolver := search.NewSimpleFieldResolver("id", "status", "total")
+//
+//	result, err := search.NewAggregateProvider(fieldResolver).
+//		Query(baseQuery).
+//		ParseAndExec("groupBy=status&count=id&sum=total&sort=-sum.total")
+func NewAggregateProvider(fieldResolver FieldResolver) *AggregateProvider {
+	return &AggregateProvider{
+		fieldResolver: fieldResolver,
+		page:          1,
+		perPage:       DefaultPerPage,
+		groupBy:       []GroupField{},
+		aggregates:    []AggregateField{},
+		sort:          []SortField{},
+		filter:        []FilterData{},
+	}
+}
+
+// Query sets the base query that will be used to aggregate the items.
+//
+// The select columns and the ORDER BY clause of the base query are ignored.
+func (s *AggregateProvider) Query(query *dbx.SelectQuery) *AggregateProvider {
+	s.query = query
+	return s
+}
+
+// Page sets the `page` field of the current aggregate provider.
+//
+// Normalization on the `page` value is done during `Exec()`.
+func (s *AggregateProvider) Page(page int) *AggregateProvider {
+	s.page = page
+	return s
+}
+
+// PerPage sets the `perPage` field of the current aggregate provider.
+//
+// Normalization on the `perPage` value is done during `Exec()`.
+func (s *AggregateProvider) PerPage(perPage int) *AggregateProvider {
+	s.perPage = perPage
+	return s
+}
+
+// GroupBy sets the `groupBy` field of the current aggregate provider.
+func (s *AggregateProvider) GroupBy(groupBy []GroupField) *AggregateProvider {
+	s.groupBy = groupBy
+	return s
+}
+
+// AddGroupBy appends the provided GroupField to the existing provider's groupBy field.
+func (s *AggregateProvider) AddGroupBy(field GroupField) *AggregateProvider {
+	s.groupBy = append(s.groupBy, field)
+	return s
+}
+
+// Aggregates sets the `aggregates` field of the current aggregate provider.
+func (s *AggregateProvider) Aggregates(aggregates []AggregateField) *AggregateProvider {
+	s.aggregates = aggregates
+	return s
+}
+
+// AddAggregate appends the provided AggregateField to the existing provider's aggregates field.
+func (s *AggregateProvider) AddAggregate(field AggregateField) *AggregateProvider {
+	s.aggregates = append(s.aggregates, field)
+	return s
+}
+
+// Sort sets the `sort` field of the current aggregate provider.
+//
+// The sort field names must be either a group field key (eg. "created:month")
+// or an aggregate field key (eg. "sum.total").
+func (s *AggregateProvider) Sort(sort []SortField) *AggregateProvider {
+	s.sort = sort
+	return s
+}
+
+// AddSort appends the provided SortField to the existing provider's sort field.
+func (s *AggregateProvider) AddSort(field SortField) *AggregateProvider {
+	s.sort = append(s.sort, field)
+	return s
+}
+
+// Filter sets the `filter` field of the current aggregate provider.
+func (s *AggregateProvider) Filter(filter []FilterData) *AggregateProvider {
+	s.filter = filter
+	return s
+}
+
+// AddFilter appends the provided FilterData to the existing provider's filter field.
+func (s *AggregateProvider) AddFilter(filter FilterData) *AggregateProvider {
+	if filter != "" {
+		s.filter = append(s.filter, filter)
+	}
+	return s
+}
+
+// Parse parses the aggregate query parameters from the provided query string
+// and assigns the found fields to the current aggregate provider.
+//
+// The data from the "groupBy", aggregate functions, "sort" and "filter" query
+// parameters are appended to the existing provider's fields.
+func (s *AggregateProvider) Parse(urlQuery string) error {
+	params, err := url.ParseQuery(urlQuery)
+	if err != nil {
+		return err
+	}
+
+	if rawPage := params.Get(PageQueryParam); rawPage != "" {
+		page, err := strconv.Atoi(rawPage)
+		if err != nil {
+			return err
+		}
+		s.Page(page)
+	}
+
+	if rawPerPage := params.Get(PerPageQueryParam); rawPerPage != "" {
+		perPage, err := strconv.Atoi(rawPerPage)
+		if err != nil {
+			return err
+		}
+		s.PerPage(perPage)
+	}
+
+	if rawGroupBy := params.Get(GroupByQueryParam); rawGroupBy != "" {
+		for _, field := range ParseGroupByFromString(rawGroupBy) {
+			s.AddGroupBy(field)
+		}
+	}
+
+	for _, fn := range AggregateFuncs() {
+		for _, name := range strings.Split(params.Get(fn), ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				s.AddAggregate(AggregateField{fn, name})
+			}
+		}
+	}
+
+	if rawSort := params.Get(SortQueryParam); rawSort != "" {
+		for _, sortField := range ParseSortFromString(rawSort) {
+			s.AddSort(sortField)
+		}
+	}
+
+	if rawFilter := params.Get(FilterQueryParam); rawFilter != "" {
+		s.AddFilter(FilterData(rawFilter))
+	}
+
+	return nil
+}
+
+// Exec executes the aggregate provider and returns
+// a paginated result with the aggregated groups.
+func (s *AggregateProvider) Exec() (*Result, error) {
+	if s.query == nil {
+		return nil, errors.New("Query is not set.")
+	}
+
+	if len(s.groupBy) == 0 && len(s.aggregates) == 0 {
+		return nil, errors.New("At least one group by or aggregate field is required.")
+	}
+
+	// clone provider's query
+	groupsQuery := *s.query
+	groupsQuery.Select().OrderBy()
+
+	// sort key => column alias
+	aliases := make(map[string]string, len(s.groupBy)+len(s.aggregates))
+
+	// apply group by
+	for i, field := range s.groupBy {
+		column, err := s.resolveColumn(&groupsQuery, field.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		if field.Bucket != "" {
+			tmpl, ok := bucketExprs[field.Bucket]
+			if !ok {
+				return nil, fmt.Errorf("invalid %q group by bucket %q", field.Name, field.Bucket)
+			}
+			column = fmt.Sprintf(tmpl, column)
+		}
+
+		alias := fmt.Sprintf("__group%d", i)
+		aliases[field.Key()] = alias
+
+		groupsQuery.AndSelect(column + " AS " + alias).AndGroupBy(column)
+	}
+
+	// apply aggregates
+	for i, field := range s.aggregates {
+		if !list.ExistInSlice(field.Func, AggregateFuncs()) {
+			return nil, fmt.Errorf("invalid aggregate function %q", field.Func)
+		}
+
+		column, err := s.resolveColumn(&groupsQuery, field.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		alias := fmt.Sprintf("__aggregate%d", i)
+		aliases[field.Key()] = alias
+
+		groupsQuery.AndSelect(fmt.Sprintf("%s(%s) AS %s", strings.ToUpper(field.Func), column, alias))
+	}
+
+	// apply sorting
+	for _, field := range s.sort {
+		alias, ok := aliases[field.Name]
+		if !ok {
+			return nil, fmt.Errorf("invalid sort field %q", field.Name)
+		}
+
+		groupsQuery.AndOrderBy(alias + " " + field.Direction)
+	}
+
+	// keep the groups order deterministic
+	for _, field := range s.groupBy {
+		groupsQuery.AndOrderBy(aliases[field.Key()] + " " + SortAsc)
+	}
+
+	// apply field resolver query modifications (if any)
+	//
+	// note: the modifications are applied before building the filters
+	// so that the groups query contains only the joins required
+	// by the group and aggregate fields
+	if err := s.fieldResolver.UpdateQuery(&groupsQuery); err != nil {
+		return nil, err
+	}
+
+	// apply the filters in a subquery to prevent the eventual
+	// filter joins from duplicating the aggregated rows
+	if len(s.filter) > 0 {
+		var baseTable string
+		if from := s.query.Info().From; len(from) > 0 {
+			baseTable = from[0]
+		}
+		idColumn := "[[" + baseTable + ".id]]"
+
+		filterQuery := *s.query
+		filterQuery.Select(idColumn).OrderBy()
+
+		for _, f := range s.filter {
+			expr, err := f.BuildExpr(s.fieldResolver)
+			if err != nil {
+				return nil, err
+			}
+			if expr != nil {
+				filterQuery.AndWhere(expr)
+			}
+		}
+
+		if err := s.fieldResolver.UpdateQuery(&filterQuery); err != nil {
+			return nil, err
+		}
+
+		built := filterQuery.Build()
+		groupsQuery.AndWhere(dbx.NewExp(idColumn+" IN ("+built.SQL()+")", built.Params()))
+	}
+
+	// normalize perPage
+	if s.perPage <= 0 {
+		s.perPage = DefaultPerPage
+	} else if s.perPage > MaxPerPage {
+		s.perPage = MaxPerPage
+	}
+
+	// count the groups
+	totalCount := 1 // without group by there is always a single aggregate row
+	if len(s.groupBy) > 0 {
+		clone := groupsQuery
+		countQuery := clone.Select("COUNT(*) OVER()").OrderBy().Limit(1)
+		err := countQuery.Row(&totalCount)
+		if errors.Is(err, sql.ErrNoRows) {
+			totalCount = 0
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	totalPages := int(math.Ceil(float64(totalCount) / float64(s.perPage)))
+
+	// normalize page according to the total count
+	if s.page <= 0 || totalCount == 0 {
+		s.page = 1
+	} else if s.page > totalPages {
+		s.page = totalPages
+	}
+
+	// apply pagination
+	groupsQuery.Limit(int64(s.perPage))
+	groupsQuery.Offset(int64(s.perPage * (s.page - 1)))
+
+	items, err := s.scanGroups(&groupsQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Page:       s.page,
+		PerPage:    s.perPage,
+		TotalItems: totalCount,
+		TotalPages: totalPages,
+		Items:      items,
+	}, nil
+}
+
+// ParseAndExec is a short convenient method to trigger both
+// `Parse()` and `Exec()` in a single call.
+func (s *AggregateProvider) ParseAndExec(urlQuery string) (*Result, error) {
+	if err := s.Parse(urlQuery); err != nil {
+		return nil, err
+	}
+
+	return s.Exec()
+}
+
+// resolveColumn resolves the provided field into a valid db column identifier
+// and binds its eventual params to the provided query.
+//
+// If the provider field resolver implements [ColumnResolver], the returned
+// identifier is the one from its ResolveColumn method.
+func (s *AggregateProvider) resolveColumn(query *dbx.SelectQuery, field string) (string, error) {
+	result, err := s.fieldResolver.Resolve(field)
+
+	// invalidate empty fields and non-column identifiers
+	if err != nil || len(result.Params) > 0 || result.Identifier == "" || strings.ToLower(result.Identifier) == "null" {
+		return "", fmt.Errorf("invalid aggregate field %q", field)
+	}
+
+	columnResolver, ok := s.fieldResolver.(ColumnResolver)
+	if !ok {
+		return result.Identifier, nil
+	}
+
+	result, err = columnResolver.ResolveColumn(field)
+	if err != nil {
+		return "", err
+	}
+
+	if len(result.Params) > 0 {
+		query.AndBind(result.Params)
+	}
+
+	return result.Identifier, nil
+}
+
+// scanGroups executes the groups query and converts
+// the selected rows into the aggregate result items.
+func (s *AggregateProvider) scanGroups(query *dbx.SelectQuery) ([]map[string]any, error) {
+	rows, err := query.Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totalColumns := len(s.groupBy) + len(s.aggregates)
+
+	items := []map[string]any{}
+
+	for rows.Next() {
+		values := make([]any, totalColumns)
+		pointers := make([]any, totalColumns)
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+
+		group := make(map[string]any, len(s.groupBy))
+		for i, field := range s.groupBy {
+			group[field.Key()] = normalizeAggregateValue(values[i])
+		}
+
+		item := map[string]any{"group": group}
+
+		for i, field := range s.aggregates {
+			fnData, _ := item[field.Func].(map[string]any)
+			if fnData == nil {
+				fnData = map[string]any{}
+				item[field.Func] = fnData
+			}
+			fnData[field.Name] = normalizeAggregateValue(values[len(s.groupBy)+i])
+		}
+
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+func normalizeAggregateValue(v any) any {
+	if raw, ok := v.([]byte); ok {
+		return string(raw)
+	}
+
+	return v
+}
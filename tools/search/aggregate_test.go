@@ -0,0 +1,277 @@
+package search
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/dbx"
+)
+
+func TestParseGroupByFromString(t *testing.T) {
+	scenarios := []struct {
+		value    string
+		expected string
+	}{
+		{"", "null"},
+		{"test", `[{"name":"test","bucket":""}]`},
+		{" a , b:month,, c : day ", `[{"name":"a","bucket":""},{"name":"b","bucket":"month"},{"name":"c","bucket":"day"}]`},
+	}
+
+	for i, s := range scenarios {
+		encoded, _ := json.Marshal(ParseGroupByFromString(s.value))
+		if string(encoded) != s.expected {
+			t.Errorf("(%d) Expected %s, got %s", i, s.expected, encoded)
+		}
+	}
+}
+
+func TestGroupFieldKey(t *testing.T) {
+	if key := (GroupField{"a", ""}).Key(); key != "a" {
+		t.Fatalf("Expected key %q, got %q", "a", key)
+	}
+
+	if key := (GroupField{"a", BucketMonth}).Key(); key != "a:month" {
+		t.Fatalf("Expected key %q, got %q", "a:month", key)
+	}
+}
+
+func TestAggregateProviderParse(t *testing.T) {
+	scenarios := []struct {
+		query            string
+		expectError      bool
+		expectPage       int
+		expectPerPage    int
+		expectGroupBy    string
+		expectAggregates string
+		expectSort       string
+		expectFilter     string
+	}{
+		{
+			"",
+			false,
+			1,
+			DefaultPerPage,
+			`[]`,
+			`[]`,
+			`[]`,
+			`[]`,
+		},
+		{
+			"page=a",
+			true,
+			1,
+			DefaultPerPage,
+			`[]`,
+			`[]`,
+			`[]`,
+			`[]`,
+		},
+		{
+			"perPage=a",
+			true,
+			1,
+			DefaultPerPage,
+			`[]`,
+			`[]`,
+			`[]`,
+			`[]`,
+		},
+		{
+			"page=2&perPage=10&groupBy=a,b:day&count=id&sum=c,d&avg=c&min=e&max=f&sort=-count.id,a&filter=a=1",
+			false,
+			2,
+			10,
+			`[{"name":"a","bucket":""},{"name":"b","bucket":"day"}]`,
+			`[{"func":"count","name":"id"},{"func":"sum","name":"c"},{"func":"sum","name":"d"},{"func":"avg","name":"c"},{"func":"min","name":"e"},{"func":"max","name":"f"}]`,
+			`[{"name":"count.id","direction":"DESC"},{"name":"a","direction":"ASC"}]`,
+			`["a=1"]`,
+		},
+	}
+
+	for i, s := range scenarios {
+		p := NewAggregateProvider(&testFieldResolver{})
+
+		err := p.Parse(s.query)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		if p.page != s.expectPage {
+			t.Errorf("(%d) Expected page %v, got %v", i, s.expectPage, p.page)
+		}
+
+		if p.perPage != s.expectPerPage {
+			t.Errorf("(%d) Expected perPage %v, got %v", i, s.expectPerPage, p.perPage)
+		}
+
+		encodedGroupBy, _ := json.Marshal(p.groupBy)
+		if string(encodedGroupBy) != s.expectGroupBy {
+			t.Errorf("(%d) Expected groupBy %v, got \n%v", i, s.expectGroupBy, string(encodedGroupBy))
+		}
+
+		encodedAggregates, _ := json.Marshal(p.aggregates)
+		if string(encodedAggregates) != s.expectAggregates {
+			t.Errorf("(%d) Expected aggregates %v, got \n%v", i, s.expectAggregates, string(encodedAggregates))
+		}
+
+		encodedSort, _ := json.Marshal(p.sort)
+		if string(encodedSort) != s.expectSort {
+			t.Errorf("(%d) Expected sort %v, got \n%v", i, s.expectSort, string(encodedSort))
+		}
+
+		encodedFilter, _ := json.Marshal(p.filter)
+		if string(encodedFilter) != s.expectFilter {
+			t.Errorf("(%d) Expected filter %v, got \n%v", i, s.expectFilter, string(encodedFilter))
+		}
+	}
+}
+
+func TestAggregateProviderExec(t *testing.T) {
+	testDB, err := createTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer testDB.Close()
+
+	testDB.Insert("test", dbx.Params{"id": 3, "test1": 3, "test2": "test2.1", "test3": "2023-01-01 10:20:30.000Z"}).Execute()
+	testDB.Insert("test", dbx.Params{"id": 4, "test1": 4, "test2": "test2.1", "test3": "2023-01-05 11:00:00.000Z"}).Execute()
+	testDB.Insert("test", dbx.Params{"id": 5, "test1": 5, "test2": "test2.2", "test3": "2023-02-05 11:00:00.000Z"}).Execute()
+
+	query := testDB.Select("*").From("test").OrderBy("id DESC")
+
+	scenarios := []struct {
+		name         string
+		query        *dbx.SelectQuery
+		queryString  string
+		expectError  bool
+		expectResult string
+	}{
+		{
+			"nil query",
+			nil,
+			"count=id",
+			true,
+			"",
+		},
+		{
+			"no group by and aggregate fields",
+			query,
+			"",
+			true,
+			"",
+		},
+		{
+			"invalid group by field",
+			query,
+			"groupBy=unknown",
+			true,
+			"",
+		},
+		{
+			"invalid group by bucket",
+			query,
+			"groupBy=test3:invalid",
+			true,
+			"",
+		},
+		{
+			"invalid aggregate field",
+			query,
+			"sum=unknown",
+			true,
+			"",
+		},
+		{
+			"invalid sort field",
+			query,
+			"groupBy=test2&sort=test1",
+			true,
+			"",
+		},
+		{
+			"invalid filter",
+			query,
+			"groupBy=test2&filter=invalid",
+			true,
+			"",
+		},
+		{
+			"aggregates without group by",
+			query,
+			"count=id&sum=test1&avg=test1&min=test1&max=test1&filter=test1>1",
+			false,
+			`{"page":1,"perPage":30,"totalItems":1,"totalPages":1,"items":[{"avg":{"test1":3.5},"count":{"id":4},"group":{},"max":{"test1":5},"min":{"test1":2},"sum":{"test1":14}}]}`,
+		},
+		{
+			"group by with sort",
+			query,
+			"groupBy=test2&count=id&sum=test1&sort=-sum.test1",
+			false,
+			`{"page":1,"perPage":30,"totalItems":2,"totalPages":1,"items":[{"count":{"id":3},"group":{"test2":"test2.1"},"sum":{"test1":8}},{"count":{"id":2},"group":{"test2":"test2.2"},"sum":{"test1":7}}]}`,
+		},
+		{
+			"group by without aggregates",
+			query,
+			"groupBy=test2",
+			false,
+			`{"page":1,"perPage":30,"totalItems":2,"totalPages":1,"items":[{"group":{"test2":"test2.1"}},{"group":{"test2":"test2.2"}}]}`,
+		},
+		{
+			"date buckets",
+			query,
+			"groupBy=test3:month,test3:week&count=id&filter=test3!=''",
+			false,
+			`{"page":1,"perPage":30,"totalItems":3,"totalPages":1,"items":[{"count":{"id":1},"group":{"test3:month":"2023-01","test3:week":"2022-12-26"}},{"count":{"id":1},"group":{"test3:month":"2023-01","test3:week":"2023-01-02"}},{"count":{"id":1},"group":{"test3:month":"2023-02","test3:week":"2023-01-30"}}]}`,
+		},
+		{
+			"pagination",
+			query,
+			"groupBy=test3:year&count=id&sort=-count.id&page=3&perPage=1",
+			false,
+			`{"page":2,"perPage":1,"totalItems":2,"totalPages":2,"items":[{"count":{"id":2},"group":{"test3:year":null}}]}`,
+		},
+		{
+			"zero results",
+			query,
+			"groupBy=test2&filter=test1>100",
+			false,
+			`{"page":1,"perPage":30,"totalItems":0,"totalPages":0,"items":[]}`,
+		},
+	}
+
+	for _, s := range scenarios {
+		testResolver := &testFieldResolver{}
+
+		result, err := NewAggregateProvider(testResolver).
+			Query(s.query).
+			ParseAndExec(s.queryString)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		// (the filters are applied with a separate subquery)
+		expectedUpdateCalls := 1
+		if strings.Contains(s.queryString, "filter=") {
+			expectedUpdateCalls = 2
+		}
+		if testResolver.UpdateQueryCalls != expectedUpdateCalls {
+			t.Errorf("[%s] Expected resolver.Update to be called %d, got %d", s.name, expectedUpdateCalls, testResolver.UpdateQueryCalls)
+		}
+
+		encoded, _ := json.Marshal(result)
+		if string(encoded) != s.expectResult {
+			t.Errorf("[%s] Expected result \n%v, \ngot \n%v", s.name, s.expectResult, string(encoded))
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package search
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/dbx"
+)
+
+// cursorDateLayout is the format of the date cursor values
+// (it is the same as the default app date layout).
+const cursorDateLayout = "2006-01-02 15:04:05.000Z"
+
+var errInvalidCursor = errors.New("Invalid or expired cursor.")
+
+// cursorData defines the decoded keyset pagination cursor.
+//
+// The cursor stores the id and the sort values of the last page row,
+// so that the next page could be resolved even if the row was
+// deleted or updated in the meantime.
+//
+// Note that the cursor is signed (see [encodeCursor]) to prevent
+// seeking with arbitrary values, which otherwise could be used to
+// guess the values of the sort columns.
+type cursorData struct {
+	// Sort is the cursor sort signature used to ensure that
+	// the cursor is used with the same sort fields.
+	Sort string `json:"s"`
+
+	// Id is the id of the last page row.
+	Id string `json:"id"`
+
+	// Values are the sort values of the last page row.
+	Values []any `json:"v"`
+}
+
+// cursorColumn defines a single resolved cursor sort column.
+type cursorColumn struct {
+	identifier string
+	direction  string
+}
+
+// cursorSortSignature returns the signature of the provided sort fields.
+func cursorSortSignature(sort []SortField) string {
+	parts := make([]string, len(sort))
+
+	for i, field := range sort {
+		parts[i] = field.Name + " " + field.Direction
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// encodeCursor encodes the provided cursor data into an opaque
+// url safe string signed with the specified secret.
+func encodeCursor(data *cursorData, secret string) (string, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(raw)
+
+	return payload + "." + signCursor(payload, secret), nil
+}
+
+// decodeCursor decodes and verifies an opaque cursor string created with encodeCursor.
+func decodeCursor(cursor string, secret string) (*cursorData, error) {
+	payload, signature, ok := strings.Cut(cursor, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(signCursor(payload, secret))) {
+		return nil, errInvalidCursor
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+
+	// decode the numbers as json.Number to preserve the int64 precision
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	data := &cursorData{}
+	if err := decoder.Decode(data); err != nil || data.Id == "" {
+		return nil, errInvalidCursor
+	}
+
+	for i, v := range data.Values {
+		data.Values[i] = normalizeCursorValue(v)
+	}
+
+	return data, nil
+}
+
+// signCursor returns the url safe HMAC-SHA256 signature of the cursor payload.
+func signCursor(payload string, secret string) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(payload))
+
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
+
+// normalizeCursorValue converts the provided scanned db value
+// into a type that could be used later as query param.
+func normalizeCursorValue(v any) any {
+	switch val := v.(type) {
+	case []byte:
+		return string(val)
+	case time.Time:
+		return val.UTC().Format(cursorDateLayout)
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i
+		}
+		if f, err := val.Float64(); err == nil {
+			return f
+		}
+		return val.String()
+	case bool:
+		if val {
+			return 1
+		}
+		return 0
+	default:
+		return val
+	}
+}
+
+// buildKeysetExpr builds the keyset (aka. seek) expression that
+// matches the rows after the cursor row based on the provided sort columns
+// and the cursor row id and sort values.
+//
+// The idColumn is used as last ASC sort column tiebreaker.
+// Note that NULL values are ordered before all other values (SQLite default).
+func buildKeysetExpr(columns []cursorColumn, idColumn string, id string, values []any) (dbx.Expression, error) {
+	if len(columns) != len(values) {
+		return nil, errInvalidCursor
+	}
+
+	params := dbx.Params{}
+	disjuncts := make([]string, 0, len(columns)+1)
+	equals := make([]string, 0, len(columns))
+
+	for i, col := range columns {
+		param := fmt.Sprintf("__cursor%d", i)
+		params[param] = values[i]
+
+		var after string
+
+		switch {
+		case col.direction == SortDesc && values[i] == nil:
+			// nothing could be after NULL in DESC order
+		case col.direction == SortDesc:
+			after = fmt.Sprintf("(%s < {:%s} OR %s IS NULL)", col.identifier, param, col.identifier)
+		case values[i] == nil:
+			after = fmt.Sprintf("%s IS NOT NULL", col.identifier)
+		default:
+			after = fmt.Sprintf("%s > {:%s}", col.identifier, param)
+		}
+
+		if after != "" {
+			// (the full slice expression forces a new backing array)
+			conditions := append(equals[:len(equals):len(equals)], after)
+			disjuncts = append(disjuncts, "("+strings.Join(conditions, " AND ")+")")
+		}
+
+		equals = append(equals, fmt.Sprintf("%s IS {:%s}", col.identifier, param))
+	}
+
+	params["__cursorId"] = id
+	disjuncts = append(disjuncts, "("+strings.Join(append(equals, idColumn+" > {:__cursorId}"), " AND ")+")")
+
+	return dbx.NewExp(strings.Join(disjuncts, " OR "), params), nil
+}
@@ -0,0 +1,166 @@
+package search
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+)
+
+func TestCursorSortSignature(t *testing.T) {
+	scenarios := []struct {
+		sort     []SortField
+		expected string
+	}{
+		{nil, ""},
+		{[]SortField{{"a", SortAsc}}, "a ASC"},
+		{[]SortField{{"a", SortAsc}, {"b.c", SortDesc}}, "a ASC,b.c DESC"},
+	}
+
+	for i, s := range scenarios {
+		result := cursorSortSignature(s.sort)
+		if result != s.expected {
+			t.Errorf("(%d) Expected %q, got %q", i, s.expected, result)
+		}
+	}
+}
+
+func TestEncodeDecodeCursor(t *testing.T) {
+	data := &cursorData{
+		Sort:   "a ASC,b DESC",
+		Id:     "abc",
+		Values: []any{"test", 9007199254740993, 1.5, nil},
+	}
+
+	encoded, err := encodeCursor(data, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := decodeCursor(encoded, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw, _ := json.Marshal(decoded)
+	expected := `{"s":"a ASC,b DESC","id":"abc","v":["test",9007199254740993,1.5,null]}`
+	if string(raw) != expected {
+		t.Fatalf("Expected %s, got %s", expected, raw)
+	}
+
+	if _, ok := decoded.Values[1].(int64); !ok {
+		t.Fatalf("Expected the integer value to be decoded as int64, got %T", decoded.Values[1])
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	valid, err := encodeCursor(&cursorData{Sort: "a ASC", Id: "abc", Values: []any{1}}, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, signature, _ := strings.Cut(valid, ".")
+
+	forgedPayload := "eyJzIjoiYSBBU0MiLCJpZCI6ImFiYyIsInYiOlsyXX0" // {"s":"a ASC","id":"abc","v":[2]}
+
+	scenarios := []struct {
+		cursor string
+		secret string
+	}{
+		{"", "secret"},
+		{"!invalid", "secret"},
+		{payload, "secret"},                         // missing signature
+		{valid, "other"},                            // different secret
+		{forgedPayload + "." + signature, "secret"}, // modified payload
+		{"dGVzdA." + signCursor("dGVzdA", "secret"), "secret"},                                           // "test"
+		{"eyJzIjoiYSBBU0MiLCJ2IjpbXX0." + signCursor("eyJzIjoiYSBBU0MiLCJ2IjpbXX0", "secret"), "secret"}, // missing id
+		{"eyJzIjoiIiwiaWQiOjF9." + signCursor("eyJzIjoiIiwiaWQiOjF9", "secret"), "secret"},               // non-string id
+	}
+
+	for i, s := range scenarios {
+		if _, err := decodeCursor(s.cursor, s.secret); err == nil {
+			t.Errorf("(%d) Expected error, got nil", i)
+		}
+	}
+}
+
+func TestNormalizeCursorValue(t *testing.T) {
+	date, _ := time.Parse(cursorDateLayout, "2023-01-01 01:02:03.456Z")
+
+	scenarios := []struct {
+		value    any
+		expected any
+	}{
+		{nil, nil},
+		{"test", "test"},
+		{[]byte("test"), "test"},
+		{int64(1), int64(1)},
+		{true, 1},
+		{false, 0},
+		{date, "2023-01-01 01:02:03.456Z"},
+		{json.Number("9007199254740993"), int64(9007199254740993)},
+		{json.Number("1.5"), 1.5},
+	}
+
+	for i, s := range scenarios {
+		result := normalizeCursorValue(s.value)
+		if result != s.expected {
+			t.Errorf("(%d) Expected %v (%T), got %v (%T)", i, s.expected, s.expected, result, result)
+		}
+	}
+}
+
+func TestBuildKeysetExpr(t *testing.T) {
+	columns := []cursorColumn{{"a", SortAsc}, {"b", SortDesc}}
+
+	scenarios := []struct {
+		name        string
+		values      []any
+		expectError bool
+		expectSql   string
+	}{
+		{
+			"values count mismatch",
+			[]any{1},
+			true,
+			"",
+		},
+		{
+			"non-null values",
+			[]any{1, "test"},
+			false,
+			"(a > {:__cursor0}) OR (a IS {:__cursor0} AND (b < {:__cursor1} OR b IS NULL)) OR (a IS {:__cursor0} AND b IS {:__cursor1} AND id > {:__cursorId})",
+		},
+		{
+			"null values",
+			[]any{nil, nil},
+			false,
+			"(a IS NOT NULL) OR (a IS {:__cursor0} AND b IS {:__cursor1} AND id > {:__cursorId})",
+		},
+	}
+
+	for _, s := range scenarios {
+		expr, err := buildKeysetExpr(columns, "id", "test_id", s.values)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		params := dbx.Params{}
+		sql := expr.Build(nil, params)
+		if sql != s.expectSql {
+			t.Errorf("[%s] Expected \n%v, \ngot \n%v", s.name, s.expectSql, sql)
+		}
+
+		if params["__cursorId"] != "test_id" {
+			t.Errorf("[%s] Expected __cursorId param %q, got %v", s.name, "test_id", params["__cursorId"])
+		}
+	}
+}
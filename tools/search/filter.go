@@ -9,15 +9,19 @@ import (
 	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/tools/security"
 	"github.com/pocketbase/pocketbase/tools/store"
-	"github.com/pocketbase/pocketbase/tools/types"
 	"github.com/spf13/cast"
 )
 
 // FilterData is a filter expression string following the `fexpr` package grammar.
 //
+// In addition the operands could also contain arithmetic operations (+, -, *, /, %),
+// function calls (lower, upper, trim, length, abs, round, strftime, geoDistance)
+// and datetime macros (@now, @todayStart, @todayEnd, @monthStart, @monthEnd, @yearStart, @yearEnd).
+//
 // Example:
 //
 //	var filter FilterData = "id = null || (name = 'test' && status = true)"
+//	var filter FilterData = "lower(name) = 'test' && created >= @monthStart && total * 2 > 10"
 //	resolver := search.NewSimpleFieldResolver("id", "name", "status")
 //	expr, err := filter.BuildExpr(resolver)
 type FilterData string
@@ -34,7 +38,11 @@ func (f FilterData) BuildExpr(fieldResolver FieldResolver) (dbx.Expression, erro
 	}
 	data, err := fexpr.Parse(raw)
 	if err != nil {
-		return nil, err
+		// fallback to the extended grammar (arithmetic operations, functions, etc.)
+		data, err = parseExtendedFilter(raw)
+		if err != nil {
+			return nil, err
+		}
 	}
 	// store in cache
 	// (the limit size is arbitrary and it is there to prevent the cache growing too big)
@@ -182,14 +190,14 @@ func buildExpr(
 func resolveToken(token fexpr.Token, fieldResolver FieldResolver) (*ResolverResult, error) {
 	switch token.Type {
 	case fexpr.TokenIdentifier:
-		// current datetime constant
+		// datetime macros (eg. @now, @todayStart, @monthStart)
 		// ---
-		if token.Literal == "@now" {
+		if value, ok := resolveDateMacro(token.Literal); ok {
 			placeholder := "t" + security.PseudorandomString(5)
 
 			return &ResolverResult{
 				Identifier: "{:" + placeholder + "}",
-				Params:     dbx.Params{placeholder: types.NowDateTime().String()},
+				Params:     dbx.Params{placeholder: value},
 			}, nil
 		}
 
@@ -227,6 +235,8 @@ func resolveToken(token fexpr.Token, fieldResolver FieldResolver) (*ResolverResu
 			Identifier: "{:" + placeholder + "}",
 			Params:     dbx.Params{placeholder: cast.ToFloat64(token.Literal)},
 		}, nil
+	case TokenOperand:
+		return resolveOperand(token.Literal, fieldResolver)
 	}
 
 	return nil, errors.New("unresolvable token type")
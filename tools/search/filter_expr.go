@@ -0,0 +1,655 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/ganigeorgiev/fexpr"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// TokenOperand is a custom fexpr token type that holds the raw source
+// of an extended filter operand (eg. "lower(title)" or "total * 2 + 1").
+const TokenOperand fexpr.TokenType = "operand"
+
+// GeoDistanceFuncName is the name of the custom sqlite function that is
+// used by the geoDistance() filter function (see also [GeoDistance]).
+//
+// Note that the function must be registered with the db driver.
+const GeoDistanceFuncName = "geo_distance"
+
+// earthRadius is the mean Earth radius in km.
+const earthRadius = 6371.0
+
+// GeoDistance returns the great-circle distance in km between the
+// two provided latitude/longitude points (using the haversine formula).
+func GeoDistance(lat1, lon1, lat2, lon2 float64) float64 {
+	dLat := (lat2 - lat1) * math.Pi / 180
+	dLon := (lon2 - lon1) * math.Pi / 180
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*math.Pi/180)*math.Cos(lat2*math.Pi/180)*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return earthRadius * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+// dateMacros lists the supported filter datetime identifiers.
+//
+// All macros are resolved in UTC at the time of the expression build.
+var dateMacros = map[string]func(now time.Time) time.Time{
+	"@now": func(now time.Time) time.Time {
+		return now
+	},
+	"@todayStart": func(now time.Time) time.Time {
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	},
+	"@todayEnd": func(now time.Time) time.Time {
+		return time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 999999999, time.UTC)
+	},
+	"@monthStart": func(now time.Time) time.Time {
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	},
+	"@monthEnd": func(now time.Time) time.Time {
+		return time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, -1, time.UTC)
+	},
+	"@yearStart": func(now time.Time) time.Time {
+		return time.Date(now.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	},
+	"@yearEnd": func(now time.Time) time.Time {
+		return time.Date(now.Year(), 12, 31, 23, 59, 59, 999999999, time.UTC)
+	},
+}
+
+// resolveDateMacro returns the formatted datetime string of the specified macro.
+func resolveDateMacro(name string) (string, bool) {
+	macro, ok := dateMacros[name]
+	if !ok {
+		return "", false
+	}
+
+	dt, err := types.ParseDateTime(macro(time.Now().UTC()))
+	if err != nil {
+		return "", false
+	}
+
+	return dt.String(), true
+}
+
+// filterFunc defines a single filter function specification.
+type filterFunc struct {
+	minArgs int
+	maxArgs int // -1 for unlimited
+	build   func(args []string) string
+}
+
+// filterFuncs lists the supported filter functions.
+var filterFuncs = map[string]filterFunc{
+	"lower": {1, 1, func(args []string) string {
+		return "LOWER(" + args[0] + ")"
+	}},
+	"upper": {1, 1, func(args []string) string {
+		return "UPPER(" + args[0] + ")"
+	}},
+	"trim": {1, 1, func(args []string) string {
+		return "TRIM(" + args[0] + ")"
+	}},
+	"length": {1, 1, func(args []string) string {
+		return "LENGTH(" + args[0] + ")"
+	}},
+	"abs": {1, 1, func(args []string) string {
+		return "ABS(" + args[0] + ")"
+	}},
+	"round": {1, 2, func(args []string) string {
+		return "ROUND(" + strings.Join(args, ", ") + ")"
+	}},
+	// strftime(format, datetime, modifiers...)
+	"strftime": {2, -1, func(args []string) string {
+		return "strftime(" + strings.Join(args, ", ") + ")"
+	}},
+	// geoDistance(lat1, lon1, lat2, lon2) - the distance in km
+	"geoDistance": {4, 4, func(args []string) string {
+		return GeoDistanceFuncName + "(" + strings.Join(args, ", ") + ")"
+	}},
+}
+
+// -------------------------------------------------------------------
+// Scanner
+// -------------------------------------------------------------------
+
+type exprTokenType int
+
+const (
+	exprTokenIdentifier exprTokenType = iota
+	exprTokenText
+	exprTokenNumber
+	exprTokenSign
+	exprTokenJoin
+	exprTokenArithmetic
+	exprTokenGroupStart
+	exprTokenGroupEnd
+	exprTokenComma
+)
+
+// exprToken defines a single extended filter expression token.
+type exprToken struct {
+	typ     exprTokenType
+	literal string
+	start   int // the start byte position in the scanned string
+	end     int // the end byte position in the scanned string
+}
+
+// scanExprTokens splits the provided extended filter expression into tokens.
+//
+// The identifiers, texts, signs and joins are scanned the same way as in fexpr.
+func scanExprTokens(raw string) ([]exprToken, error) {
+	result := []exprToken{}
+	runes := []rune(raw)
+
+	// byte offsets of each rune (+ the end of the string)
+	offsets := make([]int, 0, len(runes)+1)
+	for i := range raw {
+		offsets = append(offsets, i)
+	}
+	offsets = append(offsets, len(raw))
+
+	add := func(typ exprTokenType, literal string, start, end int) {
+		result = append(result, exprToken{typ: typ, literal: literal, start: offsets[start], end: offsets[end]})
+	}
+
+	for i := 0; i < len(runes); {
+		ch := runes[i]
+		start := i
+
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			i++
+		case isExprIdentifierStartRune(ch):
+			for i++; i < len(runes) && (isExprIdentifierStartRune(runes[i]) || isExprDigitRune(runes[i]) || runes[i] == '.' || runes[i] == ':'); i++ {
+			}
+			add(exprTokenIdentifier, string(runes[start:i]), start, i)
+		case isExprDigitRune(ch):
+			for i++; i < len(runes) && (isExprDigitRune(runes[i]) || runes[i] == '.'); i++ {
+			}
+			add(exprTokenNumber, string(runes[start:i]), start, i)
+		case ch == '\'' || ch == '"':
+			var prev rune
+			closed := false
+			for i++; i < len(runes); i++ {
+				if runes[i] == ch && prev != '\\' {
+					closed = true
+					break
+				}
+				prev = runes[i]
+			}
+			if !closed {
+				return nil, fmt.Errorf("invalid quoted text %q", string(runes[start:]))
+			}
+			i++
+			literal := string(runes[start+1 : i-1])
+			literal = strings.ReplaceAll(literal, `\`+string(ch), string(ch))
+			add(exprTokenText, literal, start, i)
+		case strings.ContainsRune("=?!<>~", ch):
+			for i++; i < len(runes) && strings.ContainsRune("=?!<>~", runes[i]); i++ {
+			}
+			literal := string(runes[start:i])
+			if !isExprSignOperator(literal) {
+				return nil, fmt.Errorf("invalid sign operator %q", literal)
+			}
+			add(exprTokenSign, literal, start, i)
+		case ch == '&' || ch == '|':
+			for i++; i < len(runes) && (runes[i] == '&' || runes[i] == '|'); i++ {
+			}
+			literal := string(runes[start:i])
+			if literal != string(fexpr.JoinAnd) && literal != string(fexpr.JoinOr) {
+				return nil, fmt.Errorf("invalid join operator %q", literal)
+			}
+			add(exprTokenJoin, literal, start, i)
+		case strings.ContainsRune("+-*/%", ch):
+			i++
+			add(exprTokenArithmetic, string(ch), start, i)
+		case ch == '(':
+			i++
+			add(exprTokenGroupStart, "(", start, i)
+		case ch == ')':
+			i++
+			add(exprTokenGroupEnd, ")", start, i)
+		case ch == ',':
+			i++
+			add(exprTokenComma, ",", start, i)
+		default:
+			return nil, fmt.Errorf("unexpected character %q", ch)
+		}
+	}
+
+	return result, nil
+}
+
+func isExprIdentifierStartRune(ch rune) bool {
+	return (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || ch == '_' || ch == '@' || ch == '#'
+}
+
+func isExprDigitRune(ch rune) bool {
+	return ch >= '0' && ch <= '9'
+}
+
+func isExprSignOperator(literal string) bool {
+	switch fexpr.SignOp(literal) {
+	case
+		fexpr.SignEq,
+		fexpr.SignNeq,
+		fexpr.SignLt,
+		fexpr.SignLte,
+		fexpr.SignGt,
+		fexpr.SignGte,
+		fexpr.SignLike,
+		fexpr.SignNlike,
+		fexpr.SignAnyEq,
+		fexpr.SignAnyNeq,
+		fexpr.SignAnyLike,
+		fexpr.SignAnyNlike,
+		fexpr.SignAnyLt,
+		fexpr.SignAnyLte,
+		fexpr.SignAnyGt,
+		fexpr.SignAnyGte:
+		return true
+	}
+
+	return false
+}
+
+// -------------------------------------------------------------------
+// Parser
+// -------------------------------------------------------------------
+
+// operandNode defines a single parsed extended operand expression node.
+type operandNode struct {
+	token fexpr.Token    // set for the plain identifier, text and number operands
+	op    string         // the arithmetic operator or the function name
+	fn    bool           // indicates whether the node is a function call
+	args  []*operandNode // the operator or function arguments
+}
+
+// exprParser is a recursive descent parser of the extended filter grammar:
+//
+//	groups     = group { join group }
+//	group      = "(" groups ")" | comparison
+//	comparison = operand sign operand
+//	operand    = term { ("+" | "-") term }
+//	term       = unary { ("*" | "/" | "%") unary }
+//	unary      = "-" unary | primary
+//	primary    = number | text | identifier | function "(" [ operand { "," operand } ] ")" | "(" operand ")"
+type exprParser struct {
+	raw    string
+	tokens []exprToken
+	pos    int
+}
+
+// parseExtendedFilter parses the provided filter expression that
+// could contain arithmetic operations and function calls.
+//
+// The returned groups have the same structure as the fexpr.Parse() result
+// with the complex operands stored as [TokenOperand] tokens.
+func parseExtendedFilter(raw string) ([]fexpr.ExprGroup, error) {
+	tokens, err := scanExprTokens(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &exprParser{raw: raw, tokens: tokens}
+
+	result, err := p.parseGroups()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t != nil {
+		return nil, fmt.Errorf("unexpected token %q", t.literal)
+	}
+
+	return result, nil
+}
+
+// parseOperand parses a single extended operand expression.
+func parseOperand(raw string) (*operandNode, error) {
+	tokens, err := scanExprTokens(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &exprParser{raw: raw, tokens: tokens}
+
+	node, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t != nil {
+		return nil, fmt.Errorf("unexpected token %q", t.literal)
+	}
+
+	return node, nil
+}
+
+func (p *exprParser) peek() *exprToken {
+	if p.pos >= len(p.tokens) {
+		return nil
+	}
+
+	return &p.tokens[p.pos]
+}
+
+func (p *exprParser) peekType(typ exprTokenType) bool {
+	t := p.peek()
+
+	return t != nil && t.typ == typ
+}
+
+func (p *exprParser) parseGroups() ([]fexpr.ExprGroup, error) {
+	result := []fexpr.ExprGroup{}
+	join := fexpr.JoinAnd
+
+	for {
+		item, err := p.parseGroup()
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, fexpr.ExprGroup{Join: join, Item: item})
+
+		if !p.peekType(exprTokenJoin) {
+			break
+		}
+
+		join = fexpr.JoinOp(p.peek().literal)
+		p.pos++
+	}
+
+	return result, nil
+}
+
+func (p *exprParser) parseGroup() (any, error) {
+	if p.peekType(exprTokenGroupStart) {
+		start := p.pos
+		p.pos++
+
+		groups, err := p.parseGroups()
+		if err == nil && p.peekType(exprTokenGroupEnd) {
+			p.pos++
+
+			// not followed by an operator, aka. not a parenthesized operand (eg. "(a + b) > 1")
+			if !p.peekType(exprTokenSign) && !p.peekType(exprTokenArithmetic) {
+				return groups, nil
+			}
+		}
+
+		// backtrack and try to parse as comparison
+		p.pos = start
+	}
+
+	return p.parseComparison()
+}
+
+func (p *exprParser) parseComparison() (fexpr.Expr, error) {
+	left, err := p.parseOperandToken()
+	if err != nil {
+		return fexpr.Expr{}, err
+	}
+
+	if !p.peekType(exprTokenSign) {
+		return fexpr.Expr{}, errors.New("expected a sign operator")
+	}
+	op := fexpr.SignOp(p.peek().literal)
+	p.pos++
+
+	right, err := p.parseOperandToken()
+	if err != nil {
+		return fexpr.Expr{}, err
+	}
+
+	return fexpr.Expr{Left: left, Op: op, Right: right}, nil
+}
+
+// parseOperandToken parses the next operand and returns it as a fexpr token.
+//
+// Plain identifiers, texts and numbers are returned as regular fexpr tokens,
+// while all other operands are returned as a single TokenOperand.
+func (p *exprParser) parseOperandToken() (fexpr.Token, error) {
+	if p.peek() == nil {
+		return fexpr.Token{}, errors.New("expected an operand")
+	}
+
+	start := p.peek().start
+
+	node, err := p.parseAdditive()
+	if err != nil {
+		return fexpr.Token{}, err
+	}
+
+	if node.token.Type != "" {
+		return node.token, nil
+	}
+
+	// negative number
+	if node.op == "-" && !node.fn && len(node.args) == 1 && node.args[0].token.Type == fexpr.TokenNumber {
+		return fexpr.Token{Type: fexpr.TokenNumber, Literal: "-" + node.args[0].token.Literal}, nil
+	}
+
+	end := p.tokens[p.pos-1].end
+
+	return fexpr.Token{Type: TokenOperand, Literal: strings.TrimSpace(p.raw[start:end])}, nil
+}
+
+func (p *exprParser) parseAdditive() (*operandNode, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peekType(exprTokenArithmetic) && (p.peek().literal == "+" || p.peek().literal == "-") {
+		op := p.peek().literal
+		p.pos++
+
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &operandNode{op: op, args: []*operandNode{left, right}}
+	}
+
+	return left, nil
+}
+
+func (p *exprParser) parseTerm() (*operandNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peekType(exprTokenArithmetic) && p.peek().literal != "+" && p.peek().literal != "-" {
+		op := p.peek().literal
+		p.pos++
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &operandNode{op: op, args: []*operandNode{left, right}}
+	}
+
+	return left, nil
+}
+
+func (p *exprParser) parseUnary() (*operandNode, error) {
+	if p.peekType(exprTokenArithmetic) && p.peek().literal == "-" {
+		p.pos++
+
+		arg, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return &operandNode{op: "-", args: []*operandNode{arg}}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (*operandNode, error) {
+	t := p.peek()
+	if t == nil {
+		return nil, errors.New("expected an operand, got end of expression")
+	}
+
+	switch t.typ {
+	case exprTokenNumber:
+		p.pos++
+		return &operandNode{token: fexpr.Token{Type: fexpr.TokenNumber, Literal: t.literal}}, nil
+	case exprTokenText:
+		p.pos++
+		return &operandNode{token: fexpr.Token{Type: fexpr.TokenText, Literal: t.literal}}, nil
+	case exprTokenIdentifier:
+		p.pos++
+
+		if !p.peekType(exprTokenGroupStart) {
+			return &operandNode{token: fexpr.Token{Type: fexpr.TokenIdentifier, Literal: t.literal}}, nil
+		}
+
+		// function call
+		p.pos++
+		node := &operandNode{op: t.literal, fn: true}
+
+		if p.peekType(exprTokenGroupEnd) {
+			p.pos++
+			return node, nil
+		}
+
+		for {
+			arg, err := p.parseAdditive()
+			if err != nil {
+				return nil, err
+			}
+			node.args = append(node.args, arg)
+
+			if p.peekType(exprTokenComma) {
+				p.pos++
+				continue
+			}
+
+			if !p.peekType(exprTokenGroupEnd) {
+				return nil, fmt.Errorf("missing closing parenthesis of function %q", t.literal)
+			}
+			p.pos++
+
+			return node, nil
+		}
+	case exprTokenGroupStart:
+		p.pos++
+
+		node, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+
+		if !p.peekType(exprTokenGroupEnd) {
+			return nil, errors.New("missing closing parenthesis")
+		}
+		p.pos++
+
+		return node, nil
+	}
+
+	return nil, fmt.Errorf("expected an operand, got %q", t.literal)
+}
+
+// -------------------------------------------------------------------
+// Compiler
+// -------------------------------------------------------------------
+
+// resolveOperand parses and resolves the provided raw extended operand expression.
+func resolveOperand(raw string, fieldResolver FieldResolver) (*ResolverResult, error) {
+	node, err := parseOperand(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return compileOperand(node, fieldResolver)
+}
+
+func compileOperand(node *operandNode, fieldResolver FieldResolver) (*ResolverResult, error) {
+	if node.token.Type != "" {
+		result, err := resolveToken(node.token, fieldResolver)
+		if err != nil {
+			return nil, err
+		}
+
+		if result.MultiMatchSubQuery != nil {
+			return nil, fmt.Errorf("multiple values field %q cannot be used in arithmetic and function expressions", node.token.Literal)
+		}
+
+		return result, nil
+	}
+
+	args := make([]*ResolverResult, len(node.args))
+	identifiers := make([]string, len(node.args))
+	for i, arg := range node.args {
+		result, err := compileOperand(arg, fieldResolver)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = result
+		identifiers[i] = result.Identifier
+	}
+
+	var identifier string
+
+	switch {
+	case node.fn:
+		fn, ok := filterFuncs[node.op]
+		if !ok {
+			return nil, fmt.Errorf("unknown function %q", node.op)
+		}
+
+		if len(args) < fn.minArgs || (fn.maxArgs >= 0 && len(args) > fn.maxArgs) {
+			return nil, fmt.Errorf("invalid number of %q function arguments", node.op)
+		}
+
+		identifier = fn.build(identifiers)
+	case len(args) == 1 && node.op == "-":
+		identifier = "(-" + identifiers[0] + ")"
+	case len(args) == 2 && node.op == "/":
+		// prevent the sqlite integer division
+		identifier = "(CAST(" + identifiers[0] + " AS REAL) / " + identifiers[1] + ")"
+	case len(args) == 2:
+		identifier = "(" + identifiers[0] + " " + node.op + " " + identifiers[1] + ")"
+	default:
+		return nil, fmt.Errorf("invalid operator %q", node.op)
+	}
+
+	result := &ResolverResult{Identifier: identifier, Params: dbx.Params{}}
+
+	afterBuildFuncs := []func(dbx.Expression) dbx.Expression{}
+	for _, arg := range args {
+		result.Params = mergeParams(result.Params, arg.Params)
+
+		if arg.AfterBuild != nil {
+			afterBuildFuncs = append(afterBuildFuncs, arg.AfterBuild)
+		}
+	}
+
+	if len(afterBuildFuncs) > 0 {
+		result.AfterBuild = func(expr dbx.Expression) dbx.Expression {
+			for _, fn := range afterBuildFuncs {
+				expr = fn(expr)
+			}
+			return expr
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,113 @@
+package search
+
+import (
+	"encoding/json"
+	"math"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestGeoDistance(t *testing.T) {
+	scenarios := []struct {
+		lat1, lon1, lat2, lon2 float64
+		expected               float64
+	}{
+		{0, 0, 0, 0, 0},
+		{42.6977, 23.3219, 42.6977, 23.3219, 0},
+		{42.6977, 23.3219, 48.8566, 2.3522, 1757.2}, // Sofia - Paris
+		{0, 0, 0, 180, 20015.1},
+	}
+
+	for i, s := range scenarios {
+		result := GeoDistance(s.lat1, s.lon1, s.lat2, s.lon2)
+		if math.Abs(result-s.expected) > 0.1 {
+			t.Errorf("(%d) Expected %v, got %v", i, s.expected, result)
+		}
+	}
+}
+
+func TestResolveDateMacro(t *testing.T) {
+	scenarios := []struct {
+		name    string
+		valid   bool
+		pattern string
+	}{
+		{"@missing", false, ""},
+		{"now", false, ""},
+		{"@now", true, `^\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}\.\d{3}Z$`},
+		{"@todayStart", true, `^\d{4}-\d{2}-\d{2} 00:00:00\.000Z$`},
+		{"@todayEnd", true, `^\d{4}-\d{2}-\d{2} 23:59:59\.999Z$`},
+		{"@monthStart", true, `^\d{4}-\d{2}-01 00:00:00\.000Z$`},
+		{"@monthEnd", true, `^\d{4}-\d{2}-(28|29|30|31) 23:59:59\.999Z$`},
+		{"@yearStart", true, `^\d{4}-01-01 00:00:00\.000Z$`},
+		{"@yearEnd", true, `^\d{4}-12-31 23:59:59\.999Z$`},
+	}
+
+	for _, s := range scenarios {
+		value, ok := resolveDateMacro(s.name)
+
+		if ok != s.valid {
+			t.Errorf("[%s] Expected valid %v, got %v", s.name, s.valid, ok)
+			continue
+		}
+
+		if ok && !regexp.MustCompile(s.pattern).MatchString(value) {
+			t.Errorf("[%s] Expected %q to match %s", s.name, value, s.pattern)
+		}
+	}
+}
+
+func TestParseExtendedFilter(t *testing.T) {
+	scenarios := []struct {
+		filter      string
+		expectError bool
+		expected    string
+	}{
+		{"", true, ""},
+		{"a", true, ""},
+		{"a >", true, ""},
+		{"a > 1 &&", true, ""},
+		{"a > 1 & b < 2", true, ""},
+		{"a => 1", true, ""},
+		{"a > 'test", true, ""},
+		{"a > 1)", true, ""},
+		{"(a > 1", true, ""},
+		{"a $ 1", true, ""},
+		{"a + > 1", true, ""},
+		{"fn(a,) > 1", true, ""},
+		{
+			"a > -1 || b.c ?= 'x \\'y\\''",
+			false,
+			`[{"Join":"&&","Item":{"Left":{"Type":"identifier","Literal":"a"},"Op":">","Right":{"Type":"number","Literal":"-1"}}},{"Join":"||","Item":{"Left":{"Type":"identifier","Literal":"b.c"},"Op":"?=","Right":{"Type":"text","Literal":"x 'y'"}}}]`,
+		},
+		{
+			"lower( a ) = 'x' && ((a + 1) * 2 > fn() || c = 1)",
+			false,
+			`[{"Join":"&&","Item":{"Left":{"Type":"operand","Literal":"lower( a )"},"Op":"=","Right":{"Type":"text","Literal":"x"}}},{"Join":"&&","Item":[{"Join":"&&","Item":{"Left":{"Type":"operand","Literal":"(a + 1) * 2"},"Op":">","Right":{"Type":"operand","Literal":"fn()"}}},{"Join":"||","Item":{"Left":{"Type":"identifier","Literal":"c"},"Op":"=","Right":{"Type":"number","Literal":"1"}}}]}]`,
+		},
+	}
+
+	for i, s := range scenarios {
+		result, err := parseExtendedFilter(s.filter)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		var encoded strings.Builder
+		encoder := json.NewEncoder(&encoded)
+		encoder.SetEscapeHTML(false)
+		encoder.Encode(result)
+
+		if strings.TrimSpace(encoded.String()) != s.expected {
+			t.Errorf("(%d) Expected \n%s, \ngot \n%s", i, s.expected, encoded.String())
+		}
+	}
+}
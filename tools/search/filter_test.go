@@ -106,6 +106,99 @@ func TestFilterDataBuildExpr(t *testing.T) {
 				regexp.QuoteMeta("}") +
 				"$",
 		},
+		{
+			"datetime macros",
+			"test1 >= @todayStart && test2 <= @monthEnd", false,
+			"^" +
+				regexp.QuoteMeta("([[test1]] >= {:") +
+				".+" +
+				regexp.QuoteMeta("} AND [[test2]] <= {:") +
+				".+" +
+				regexp.QuoteMeta("})") +
+				"$",
+		},
+		{
+			"arithmetic operators",
+			"test1 + 2 * -test2 > (test3 - 1) / 2 % 3", false,
+			"^" +
+				regexp.QuoteMeta("([[test1]] + ({:") +
+				".+" +
+				regexp.QuoteMeta("} * (-[[test2]]))) > ((CAST(([[test3]] - {:") +
+				".+" +
+				regexp.QuoteMeta("}) AS REAL) / {:") +
+				".+" +
+				regexp.QuoteMeta("}) % {:") +
+				".+" +
+				regexp.QuoteMeta("})") +
+				"$",
+		},
+		{
+			"functions",
+			"lower(test1) = upper('a') && length(trim(test2)) > 2 && abs(round(test3, 2)) < -1 && strftime('%Y', test4.sub) = '2023' && geoDistance(test1, test2, 1.5, 2) <= 10",
+			false,
+			"^" +
+				regexp.QuoteMeta("(COALESCE(LOWER([[test1]]), '') = COALESCE(UPPER({:") +
+				".+" +
+				regexp.QuoteMeta("}), '') AND LENGTH(TRIM([[test2]])) > {:") +
+				".+" +
+				regexp.QuoteMeta("} AND ABS(ROUND([[test3]], {:") +
+				".+" +
+				regexp.QuoteMeta("})) < {:") +
+				".+" +
+				regexp.QuoteMeta("} AND COALESCE(strftime({:") +
+				".+" +
+				regexp.QuoteMeta("}, [[test4.sub]]), '') = COALESCE({:") +
+				".+" +
+				regexp.QuoteMeta("}, '') AND geo_distance([[test1]], [[test2]], {:") +
+				".+" +
+				regexp.QuoteMeta("}, {:") +
+				".+" +
+				regexp.QuoteMeta("}) <= {:") +
+				".+" +
+				regexp.QuoteMeta("})") +
+				"$",
+		},
+		{
+			"parenthesized operands and groups",
+			"(test1 + 1) > 2 || (test1 > 1 && (test2 - 1) = 0)", false,
+			"^" +
+				regexp.QuoteMeta("(([[test1]] + {:") +
+				".+" +
+				regexp.QuoteMeta("}) > {:") +
+				".+" +
+				regexp.QuoteMeta("} OR ([[test1]] > {:") +
+				".+" +
+				regexp.QuoteMeta("} AND COALESCE(([[test2]] - {:") +
+				".+" +
+				regexp.QuoteMeta("}), '') = COALESCE({:") +
+				".+" +
+				regexp.QuoteMeta("}, '')))") +
+				"$",
+		},
+		{
+			"unknown function",
+			"missing(test1) > 1",
+			true,
+			"",
+		},
+		{
+			"invalid number of function arguments",
+			"lower(test1, test2) = 'a'",
+			true,
+			"",
+		},
+		{
+			"unknown function argument field",
+			"lower(unknown) = 'a'",
+			true,
+			"",
+		},
+		{
+			"unclosed function",
+			"lower(test1 = 'a'",
+			true,
+			"",
+		},
 		{
 			"complex expression",
 			"((test1 > 1) || (test2 != 2)) && test3 ~ '%%example' && test4.sub = null",
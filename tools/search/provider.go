@@ -2,11 +2,13 @@ package search
 
 import (
 	"errors"
+	"fmt"
 	"math"
 	"net/url"
 	"strconv"
 
 	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/list"
 )
 
 // DefaultPerPage specifies the default returned search result items.
@@ -17,19 +19,29 @@ const MaxPerPage int = 500
 
 // url search query params
 const (
-	PageQueryParam    string = "page"
-	PerPageQueryParam string = "perPage"
-	SortQueryParam    string = "sort"
-	FilterQueryParam  string = "filter"
+	PageQueryParam      string = "page"
+	PerPageQueryParam   string = "perPage"
+	SortQueryParam      string = "sort"
+	FilterQueryParam    string = "filter"
+	SearchQueryParam    string = "search"
+	CursorQueryParam    string = "cursor"
+	SkipTotalQueryParam string = "skipTotal"
 )
 
 // Result defines the returned search result structure.
+//
+// TotalItems and TotalPages are -1 when the total count is skipped.
 type Result struct {
 	Page       int `json:"page"`
 	PerPage    int `json:"perPage"`
 	TotalItems int `json:"totalItems"`
 	TotalPages int `json:"totalPages"`
 	Items      any `json:"items"`
+
+	// NextCursor is the cursor of the next page (cursor mode only).
+	//
+	// It is empty if there are no more items.
+	NextCursor string `json:"nextCursor,omitempty"`
 }
 
 // Provider represents a single configured search provider instance.
@@ -40,6 +52,11 @@ type Provider struct {
 	perPage       int
 	sort          []SortField
 	filter        []FilterData
+	search        string
+	cursor        string
+	cursorMode    bool
+	cursorSecret  string
+	skipTotal     bool
 }
 
 // NewProvider creates and returns a new search provider.
@@ -111,6 +128,46 @@ func (s *Provider) AddFilter(filter FilterData) *Provider {
 	return s
 }
 
+// Search sets the full-text `search` field of the current search provider.
+//
+// Note that the provider field resolver must implement
+// the [FullTextSearchResolver] interface.
+func (s *Provider) Search(search string) *Provider {
+	s.search = search
+	return s
+}
+
+// Cursor sets the keyset pagination cursor of the current search
+// provider and enables the cursor pagination mode.
+//
+// Set an empty cursor to fetch the first page. The cursor of the next page
+// is returned with the search result. In cursor mode the `page` field
+// and the ORDER BY clause of the base query are ignored.
+//
+// Note that the cursor pagination requires a cursor secret (see [Provider.CursorSecret]).
+func (s *Provider) Cursor(cursor string) *Provider {
+	s.cursor = cursor
+	s.cursorMode = true
+	return s
+}
+
+// CursorSecret sets the secret used to sign and verify the
+// keyset pagination cursors of the current search provider.
+//
+// A secret is required in order to use the cursor pagination mode.
+func (s *Provider) CursorSecret(secret string) *Provider {
+	s.cursorSecret = secret
+	return s
+}
+
+// SkipTotal sets whether to skip the total items count query.
+//
+// This could speed up significantly the search on large datasets.
+func (s *Provider) SkipTotal(skipTotal bool) *Provider {
+	s.skipTotal = skipTotal
+	return s
+}
+
 // Parse parses the search query parameter from the provided query string
 // and assigns the found fields to the current search provider.
 //
@@ -149,6 +206,22 @@ func (s *Provider) Parse(urlQuery string) error {
 		s.AddFilter(FilterData(rawFilter))
 	}
 
+	if rawSearch := params.Get(SearchQueryParam); rawSearch != "" {
+		s.Search(rawSearch)
+	}
+
+	if params.Has(CursorQueryParam) {
+		s.Cursor(params.Get(CursorQueryParam))
+	}
+
+	if rawSkipTotal := params.Get(SkipTotalQueryParam); rawSkipTotal != "" {
+		skipTotal, err := strconv.ParseBool(rawSkipTotal)
+		if err != nil {
+			return err
+		}
+		s.SkipTotal(skipTotal)
+	}
+
 	return nil
 }
 
@@ -173,33 +246,71 @@ func (s *Provider) Exec(items any) (*Result, error) {
 		}
 	}
 
+	// apply full-text search
+	if s.search != "" {
+		searchResolver, ok := s.fieldResolver.(FullTextSearchResolver)
+		if !ok {
+			return nil, errors.New("Full-text search is not supported.")
+		}
+
+		expr, err := searchResolver.ResolveSearch(s.search)
+		if err != nil {
+			return nil, err
+		}
+		if expr != nil {
+			modelsQuery.AndWhere(expr)
+		}
+	}
+
+	var baseTable string
+	if from := modelsQuery.Info().From; len(from) > 0 {
+		baseTable = from[0]
+	}
+	idColumn := "[[" + baseTable + ".id]]"
+
+	// the keyset seek requires the rows to be ordered only by the sort fields
+	if s.cursorMode {
+		if s.cursorSecret == "" {
+			return nil, errors.New("The cursor pagination is not supported.")
+		}
+
+		modelsQuery.OrderBy()
+	}
+
 	// apply sorting
+	cursorColumns := make([]cursorColumn, 0, len(s.sort))
 	for _, sortField := range s.sort {
 		expr, err := sortField.BuildExpr(s.fieldResolver)
 		if err != nil {
 			return nil, err
 		}
-		if expr != "" {
-			modelsQuery.AndOrderBy(expr)
+		if expr == "" {
+			continue
 		}
-	}
 
-	// apply field resolver query modifications (if any)
-	if err := s.fieldResolver.UpdateQuery(&modelsQuery); err != nil {
-		return nil, err
-	}
+		modelsQuery.AndOrderBy(expr)
 
-	queryInfo := modelsQuery.Info()
+		if s.cursorMode {
+			if sortField.Name == randomSortKey {
+				return nil, errors.New("The random sort is not supported in cursor mode.")
+			}
 
-	// count
-	var totalCount int64
-	var baseTable string
-	if len(queryInfo.From) > 0 {
-		baseTable = queryInfo.From[0]
+			column, err := sortField.buildCursorColumn(s.fieldResolver)
+			if err != nil {
+				return nil, err
+			}
+
+			cursorColumns = append(cursorColumns, cursorColumn{column, sortField.Direction})
+		}
+	}
+
+	// the id is used as tiebreaker to guarantee deterministic order
+	if s.cursorMode {
+		modelsQuery.AndOrderBy(idColumn + " " + SortAsc)
 	}
-	clone := modelsQuery
-	countQuery := clone.Select("COUNT(DISTINCT [[" + baseTable + ".id]])").OrderBy()
-	if err := countQuery.Row(&totalCount); err != nil {
+
+	// apply field resolver query modifications (if any)
+	if err := s.fieldResolver.UpdateQuery(&modelsQuery); err != nil {
 		return nil, err
 	}
 
@@ -210,12 +321,42 @@ func (s *Provider) Exec(items any) (*Result, error) {
 		s.perPage = MaxPerPage
 	}
 
-	totalPages := int(math.Ceil(float64(totalCount) / float64(s.perPage)))
+	// count
+	totalCount := -1
+	totalPages := -1
+	if !s.skipTotal {
+		clone := modelsQuery
+		countQuery := clone.Select("COUNT(DISTINCT " + idColumn + ")").OrderBy()
+		if err := countQuery.Row(&totalCount); err != nil {
+			return nil, err
+		}
+
+		totalPages = int(math.Ceil(float64(totalCount) / float64(s.perPage)))
+	}
+
+	result := &Result{
+		Page:       1,
+		PerPage:    s.perPage,
+		TotalItems: totalCount,
+		TotalPages: totalPages,
+		Items:      items,
+	}
+
+	if s.cursorMode {
+		nextCursor, err := s.execCursor(&modelsQuery, baseTable, idColumn, cursorColumns, items)
+		if err != nil {
+			return nil, err
+		}
+
+		result.NextCursor = nextCursor
+
+		return result, nil
+	}
 
 	// normalize page according to the total count
 	if s.page <= 0 || totalCount == 0 {
 		s.page = 1
-	} else if s.page > totalPages {
+	} else if !s.skipTotal && s.page > totalPages {
 		s.page = totalPages
 	}
 
@@ -228,13 +369,131 @@ func (s *Provider) Exec(items any) (*Result, error) {
 		return nil, err
 	}
 
-	return &Result{
-		Page:       s.page,
-		PerPage:    s.perPage,
-		TotalItems: int(totalCount),
-		TotalPages: totalPages,
-		Items:      items,
-	}, nil
+	result.Page = s.page
+
+	return result, nil
+}
+
+// execCursor fetches the page items after the provider cursor
+// and returns the cursor of the next page (if any).
+//
+// The page rows are located with a lightweight keyset query
+// and after that the items are fetched by their ids.
+func (s *Provider) execCursor(
+	modelsQuery *dbx.SelectQuery,
+	baseTable string,
+	idColumn string,
+	columns []cursorColumn,
+	items any,
+) (string, error) {
+	signature := baseTable + ":" + cursorSortSignature(s.sort)
+
+	selects := make([]string, 0, len(columns)+1)
+	selects = append(selects, idColumn)
+	for _, col := range columns {
+		selects = append(selects, col.identifier)
+	}
+
+	keysetQuery := *modelsQuery
+
+	if s.cursor != "" {
+		cursor, err := decodeCursor(s.cursor, s.cursorSecret)
+		if err != nil {
+			return "", err
+		}
+
+		if cursor.Sort != signature {
+			return "", errInvalidCursor
+		}
+
+		expr, err := buildKeysetExpr(columns, idColumn, cursor.Id, cursor.Values)
+		if err != nil {
+			return "", err
+		}
+
+		keysetQuery.AndWhere(expr)
+	}
+
+	// fetch one extra row to check whether there is a next page
+	keysetQuery.Select(selects...).Limit(int64(s.perPage + 1))
+
+	ids, lastValues, hasMore, err := s.scanKeysetRows(&keysetQuery, len(selects))
+	if err != nil {
+		return "", err
+	}
+
+	// fetch models
+	itemsQuery := *modelsQuery
+	if len(ids) == 0 {
+		itemsQuery.AndWhere(dbx.NewExp("1=0"))
+	} else {
+		itemsQuery.AndWhere(dbx.In(baseTable+".id", list.ToInterfaceSlice(ids)...))
+	}
+	if err := itemsQuery.All(items); err != nil {
+		return "", err
+	}
+
+	if !hasMore {
+		return "", nil
+	}
+
+	return encodeCursor(&cursorData{
+		Sort:   signature,
+		Id:     ids[len(ids)-1],
+		Values: lastValues,
+	}, s.cursorSecret)
+}
+
+// scanKeysetRows executes the keyset query and returns the unique ids
+// of the page rows, the sort values of the last page row and whether
+// there are more rows after the page.
+func (s *Provider) scanKeysetRows(query *dbx.SelectQuery, totalColumns int) (
+	ids []string,
+	lastValues []any,
+	hasMore bool,
+	err error,
+) {
+	rows, err := query.Rows()
+	if err != nil {
+		return nil, nil, false, err
+	}
+	defer rows.Close()
+
+	ids = make([]string, 0, s.perPage)
+
+	for rows.Next() {
+		values := make([]any, totalColumns)
+		pointers := make([]any, totalColumns)
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, nil, false, err
+		}
+
+		id := fmt.Sprint(normalizeCursorValue(values[0]))
+
+		// skip duplicated rows (eg. from multiple relations joins)
+		if list.ExistInSlice(id, ids) {
+			continue
+		}
+
+		if len(ids) == s.perPage {
+			hasMore = true
+			break
+		}
+
+		ids = append(ids, id)
+
+		// exclude the id column
+		lastValues = values[1:]
+		for i, v := range lastValues {
+			lastValues[i] = normalizeCursorValue(v)
+		}
+	}
+
+	return ids, lastValues, hasMore, rows.Err()
 }
 
 // ParseAndExec is a short convenient method to trigger both
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -90,6 +91,51 @@ func TestProviderFilter(t *testing.T) {
 	}
 }
 
+func TestProviderSearch(t *testing.T) {
+	r := &testFieldResolver{}
+	p := NewProvider(r).Search("test")
+
+	if p.search != "test" {
+		t.Fatalf("Expected search %q, got %q", "test", p.search)
+	}
+
+	if err := p.Parse("search=test2"); err != nil {
+		t.Fatal(err)
+	}
+
+	if p.search != "test2" {
+		t.Fatalf("Expected parsed search %q, got %q", "test2", p.search)
+	}
+}
+
+func TestProviderCursor(t *testing.T) {
+	r := &testFieldResolver{}
+	p := NewProvider(r)
+
+	if p.cursorMode {
+		t.Fatal("Expected the cursor mode to be disabled by default")
+	}
+
+	p.Cursor("test")
+
+	if !p.cursorMode {
+		t.Fatal("Expected the cursor mode to be enabled")
+	}
+
+	if p.cursor != "test" {
+		t.Fatalf("Expected cursor %q, got %q", "test", p.cursor)
+	}
+}
+
+func TestProviderSkipTotal(t *testing.T) {
+	r := &testFieldResolver{}
+	p := NewProvider(r).SkipTotal(true)
+
+	if !p.skipTotal {
+		t.Fatal("Expected skipTotal to be true")
+	}
+}
+
 func TestProviderParse(t *testing.T) {
 	initialPage := 2
 	initialPerPage := 123
@@ -187,6 +233,46 @@ func TestProviderParse(t *testing.T) {
 	}
 }
 
+func TestProviderParseCursorAndSkipTotal(t *testing.T) {
+	scenarios := []struct {
+		query           string
+		expectError     bool
+		expectCursor    string
+		expectCursorMod bool
+		expectSkipTotal bool
+	}{
+		{"", false, "", false, false},
+		{"cursor=", false, "", true, false},
+		{"cursor=abc&skipTotal=1", false, "abc", true, true},
+		{"skipTotal=false", false, "", false, false},
+		{"skipTotal=invalid", true, "", false, false},
+	}
+
+	for i, s := range scenarios {
+		p := NewProvider(&testFieldResolver{})
+
+		err := p.Parse(s.query)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		if p.cursor != s.expectCursor {
+			t.Errorf("(%d) Expected cursor %q, got %q", i, s.expectCursor, p.cursor)
+		}
+
+		if p.cursorMode != s.expectCursorMod {
+			t.Errorf("(%d) Expected cursorMode %v, got %v", i, s.expectCursorMod, p.cursorMode)
+		}
+
+		if p.skipTotal != s.expectSkipTotal {
+			t.Errorf("(%d) Expected skipTotal %v, got %v", i, s.expectSkipTotal, p.skipTotal)
+		}
+	}
+}
+
 func TestProviderExecEmptyQuery(t *testing.T) {
 	p := NewProvider(&testFieldResolver{}).
 		Query(nil)
@@ -351,6 +437,298 @@ func TestProviderExecNonEmptyQuery(t *testing.T) {
 	}
 }
 
+func TestProviderExecSkipTotal(t *testing.T) {
+	testDB, err := createTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer testDB.Close()
+
+	query := testDB.Select("*").From("test").OrderBy("test1 ASC")
+
+	scenarios := []struct {
+		page         int
+		expectResult string
+		expectQuery  string
+	}{
+		{
+			-1,
+			`{"page":1,"perPage":1,"totalItems":-1,"totalPages":-1,"items":[{"test1":1,"test2":"test2.1","test3":""}]}`,
+			"SELECT * FROM `test` ORDER BY `test1` ASC LIMIT 1",
+		},
+		{
+			2,
+			`{"page":2,"perPage":1,"totalItems":-1,"totalPages":-1,"items":[{"test1":2,"test2":"test2.2","test3":""}]}`,
+			"SELECT * FROM `test` ORDER BY `test1` ASC LIMIT 1 OFFSET 1",
+		},
+		{
+			// not capped by the total count
+			5,
+			`{"page":5,"perPage":1,"totalItems":-1,"totalPages":-1,"items":[]}`,
+			"SELECT * FROM `test` ORDER BY `test1` ASC LIMIT 1 OFFSET 4",
+		},
+	}
+
+	for i, s := range scenarios {
+		testDB.CalledQueries = []string{} // reset
+
+		result, err := NewProvider(&testFieldResolver{}).
+			Query(query).
+			Page(s.page).
+			PerPage(1).
+			SkipTotal(true).
+			Exec(&[]testTableStruct{})
+		if err != nil {
+			t.Errorf("(%d) Unexpected error %v", i, err)
+			continue
+		}
+
+		encoded, _ := json.Marshal(result)
+		if string(encoded) != s.expectResult {
+			t.Errorf("(%d) Expected result %v, got \n%v", i, s.expectResult, string(encoded))
+		}
+
+		if len(testDB.CalledQueries) != 1 || testDB.CalledQueries[0] != s.expectQuery {
+			t.Errorf("(%d) Expected the single query \n%v, got \n%v", i, s.expectQuery, testDB.CalledQueries)
+		}
+	}
+}
+
+func TestProviderExecCursor(t *testing.T) {
+	testDB, err := createTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer testDB.Close()
+
+	// extra rows with duplicated and null sort values
+	testDB.Insert("test", dbx.Params{"id": 3, "test1": 2, "test2": "test2.3"}).Execute()
+	testDB.Insert("test", dbx.Params{"id": 4, "test1": nil, "test2": "test2.4"}).Execute()
+	testDB.Insert("test", dbx.Params{"id": 5, "test1": 1, "test2": "test2.5"}).Execute()
+
+	// the base query order should be ignored
+	query := testDB.Select("*").From("test").OrderBy("test2 DESC")
+
+	scenarios := []struct {
+		name        string
+		sort        []SortField
+		skipTotal   bool
+		expectPages []string
+	}{
+		{
+			"no sort fields",
+			nil,
+			false,
+			[]string{"1,2", "3,4", "5"},
+		},
+		{
+			"ASC sort",
+			[]SortField{{"test1", SortAsc}},
+			true,
+			[]string{"4,1", "5,2", "3"},
+		},
+		{
+			"DESC sort",
+			[]SortField{{"test1", SortDesc}},
+			false,
+			[]string{"2,3", "1,5", "4"},
+		},
+		{
+			"multiple sort fields",
+			[]SortField{{"test1", SortDesc}, {"test2", SortDesc}},
+			false,
+			[]string{"3,2", "5,1", "4"},
+		},
+	}
+
+	for _, s := range scenarios {
+		cursor := ""
+		pages := []string{}
+
+		for i := 0; i < 10; i++ {
+			items := []struct {
+				Id int `db:"id"`
+			}{}
+
+			result, err := NewProvider(&testFieldResolver{}).
+				Query(query).
+				Page(3). // should be ignored
+				PerPage(2).
+				Sort(s.sort).
+				SkipTotal(s.skipTotal).
+				CursorSecret("test").
+				Cursor(cursor).
+				Exec(&items)
+			if err != nil {
+				t.Fatalf("[%s] Unexpected error %v", s.name, err)
+			}
+
+			if result.Page != 1 {
+				t.Fatalf("[%s] Expected page 1, got %d", s.name, result.Page)
+			}
+
+			expectedTotal := 5
+			if s.skipTotal {
+				expectedTotal = -1
+			}
+			if result.TotalItems != expectedTotal {
+				t.Fatalf("[%s] Expected totalItems %d, got %d", s.name, expectedTotal, result.TotalItems)
+			}
+
+			ids := make([]string, len(items))
+			for j, item := range items {
+				ids[j] = fmt.Sprint(item.Id)
+			}
+			pages = append(pages, strings.Join(ids, ","))
+
+			cursor = result.NextCursor
+			if cursor == "" {
+				break
+			}
+		}
+
+		encoded, _ := json.Marshal(pages)
+		expected, _ := json.Marshal(s.expectPages)
+		if string(encoded) != string(expected) {
+			t.Errorf("[%s] Expected pages %s, got %s", s.name, expected, encoded)
+		}
+	}
+}
+
+func TestProviderExecCursorChangedRow(t *testing.T) {
+	testDB, err := createTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer testDB.Close()
+
+	testDB.Insert("test", dbx.Params{"id": 3, "test1": 3, "test2": "test2.3"}).Execute()
+	testDB.Insert("test", dbx.Params{"id": 4, "test1": 4, "test2": "test2.4"}).Execute()
+
+	exec := func(cursor string) (*Result, string) {
+		items := []struct {
+			Id int `db:"id"`
+		}{}
+
+		result, err := NewProvider(&testFieldResolver{}).
+			Query(testDB.Select("*").From("test")).
+			PerPage(2).
+			Sort([]SortField{{"test1", SortAsc}}).
+			CursorSecret("test").
+			Cursor(cursor).
+			Exec(&items)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ids := make([]string, len(items))
+		for i, item := range items {
+			ids[i] = fmt.Sprint(item.Id)
+		}
+
+		return result, strings.Join(ids, ",")
+	}
+
+	first, _ := exec("")
+
+	// the cursor should continue after the cursor values
+	// even if the cursor row was updated or deleted
+	scenarios := []struct {
+		name   string
+		change func()
+	}{
+		{"updated cursor row", func() {
+			testDB.Update("test", dbx.Params{"test1": 10}, dbx.HashExp{"id": 2}).Execute()
+		}},
+		{"deleted cursor row", func() {
+			testDB.Delete("test", dbx.HashExp{"id": 2}).Execute()
+		}},
+	}
+
+	for _, s := range scenarios {
+		s.change()
+
+		_, ids := exec(first.NextCursor)
+		if ids != "3,4" {
+			t.Errorf("[%s] Expected ids %q, got %q", s.name, "3,4", ids)
+		}
+	}
+}
+
+func TestProviderExecCursorErrors(t *testing.T) {
+	testDB, err := createTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer testDB.Close()
+
+	query := testDB.Select("*").From("test")
+
+	validCursor, _ := encodeCursor(&cursorData{Sort: "test:test1 ASC", Id: "1", Values: []any{1}}, "test")
+	forgedCursor, _ := encodeCursor(&cursorData{Sort: "test:test1 ASC", Id: "1", Values: []any{1}}, "other")
+	valuesMismatchCursor, _ := encodeCursor(&cursorData{Sort: "test:test1 ASC", Id: "1"}, "test")
+
+	scenarios := []struct {
+		name   string
+		secret string
+		sort   []SortField
+		cursor string
+	}{
+		{"missing cursor secret", "", []SortField{{"test1", SortAsc}}, validCursor},
+		{"malformed cursor", "test", nil, "invalid"},
+		{"forged cursor", "test", []SortField{{"test1", SortAsc}}, forgedCursor},
+		{"sort fields mismatch", "test", []SortField{{"test2", SortAsc}}, validCursor},
+		{"sort values mismatch", "test", []SortField{{"test1", SortAsc}}, valuesMismatchCursor},
+		{"conditionally visible sort field", "test", []SortField{{"hidden", SortAsc}}, ""},
+		{"random sort", "test", []SortField{{randomSortKey, SortAsc}}, ""},
+	}
+
+	for _, s := range scenarios {
+		_, err := NewProvider(&testFieldResolver{}).
+			Query(query).
+			Sort(s.sort).
+			CursorSecret(s.secret).
+			Cursor(s.cursor).
+			Exec(&[]testTableStruct{})
+
+		if err == nil {
+			t.Errorf("[%s] Expected error, got nil", s.name)
+		}
+	}
+
+	// sanity check
+	_, err = NewProvider(&testFieldResolver{}).
+		Query(query).
+		Sort([]SortField{{"test1", SortAsc}}).
+		CursorSecret("test").
+		Cursor(validCursor).
+		Exec(&[]testTableStruct{})
+	if err != nil {
+		t.Fatalf("Expected the valid cursor to be accepted, got %v", err)
+	}
+}
+
+func TestProviderExecConditionallyVisibleSort(t *testing.T) {
+	testDB, err := createTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer testDB.Close()
+
+	if _, err := testDB.NewQuery("ALTER TABLE test ADD COLUMN hidden TEXT").Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	// fields with conditional visibility are allowed in the offset pagination mode
+	_, err = NewProvider(&testFieldResolver{}).
+		Query(testDB.Select("*").From("test")).
+		Sort([]SortField{{"hidden", SortAsc}}).
+		Exec(&[]testTableStruct{})
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+}
+
 func TestProviderParseAndExec(t *testing.T) {
 	testDB, err := createTestDB()
 	if err != nil {
@@ -450,6 +828,68 @@ func TestProviderParseAndExec(t *testing.T) {
 	}
 }
 
+func TestProviderExecSearch(t *testing.T) {
+	testDB, err := createTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer testDB.Close()
+
+	query := testDB.Select("*").From("test")
+
+	scenarios := []struct {
+		name         string
+		resolver     FieldResolver
+		search       string
+		expectError  bool
+		expectResult string
+	}{
+		{
+			"resolver without full-text search support",
+			&testFieldResolver{},
+			"test2.2",
+			true,
+			"",
+		},
+		{
+			"resolver with full-text search support and failed search resolve",
+			&testSearchFieldResolver{},
+			"unknown",
+			true,
+			"",
+		},
+		{
+			"resolver with full-text search support",
+			&testSearchFieldResolver{},
+			"test2.2",
+			false,
+			`{"page":1,"perPage":30,"totalItems":1,"totalPages":1,"items":[{"test1":2,"test2":"test2.2","test3":""}]}`,
+		},
+	}
+
+	for _, s := range scenarios {
+		result, err := NewProvider(s.resolver).
+			Query(query).
+			Search(s.search).
+			Exec(&[]testTableStruct{})
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		encoded, _ := json.Marshal(result)
+		if string(encoded) != s.expectResult {
+			t.Errorf("[%s] Expected result %v, got \n%v", s.name, s.expectResult, string(encoded))
+		}
+	}
+}
+
 // -------------------------------------------------------------------
 // Helpers
 // -------------------------------------------------------------------
@@ -502,5 +942,29 @@ func (t *testFieldResolver) Resolve(field string) (*ResolverResult, error) {
 		return nil, errors.New("test error")
 	}
 
+	// field with conditional visibility
+	if field == "hidden" {
+		return &ResolverResult{
+			Identifier: field,
+			AfterBuild: func(expr dbx.Expression) dbx.Expression {
+				return dbx.And(expr, dbx.NewExp("1=0"))
+			},
+		}, nil
+	}
+
 	return &ResolverResult{Identifier: field}, nil
 }
+
+// ---
+
+type testSearchFieldResolver struct {
+	testFieldResolver
+}
+
+func (t *testSearchFieldResolver) ResolveSearch(search string) (dbx.Expression, error) {
+	if search == "unknown" {
+		return nil, errors.New("test error")
+	}
+
+	return dbx.NewExp("test2 = {:search}", dbx.Params{"search": search}), nil
+}
@@ -40,6 +40,25 @@ type FieldResolver interface {
 	Resolve(field string) (*ResolverResult, error)
 }
 
+// ColumnResolver is an optional [FieldResolver] interface that could be
+// implemented by the resolvers that need to restrict the raw column values
+// returned by the [AggregateProvider] (eg. the values of inaccessible related records).
+type ColumnResolver interface {
+	// ResolveColumn parses the provided field and returns a db column
+	// identifier expression which value could be returned to the client.
+	ResolveColumn(field string) (*ResolverResult, error)
+}
+
+// FullTextSearchResolver is an optional [FieldResolver] interface
+// that could be implemented by the resolvers supporting full-text search.
+type FullTextSearchResolver interface {
+	// ResolveSearch returns a db expression that limits the search
+	// query only to the items matching the provided search text.
+	//
+	// Returning nil expression means no search filtering.
+	ResolveSearch(search string) (dbx.Expression, error)
+}
+
 // NewSimpleFieldResolver creates a new `SimpleFieldResolver` with the
 // provided `allowedFields`.
 //
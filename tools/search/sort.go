@@ -26,14 +26,54 @@ func (s *SortField) BuildExpr(fieldResolver FieldResolver) (string, error) {
 		return "RANDOM()", nil
 	}
 
+	column, err := s.buildColumn(fieldResolver)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s %s", column, s.Direction), nil
+}
+
+// buildColumn resolves the sort field into a valid db column identifier.
+func (s *SortField) buildColumn(fieldResolver FieldResolver) (string, error) {
+	result, err := s.resolve(fieldResolver)
+	if err != nil {
+		return "", err
+	}
+
+	return result.Identifier, nil
+}
+
+// buildCursorColumn resolves the sort field into a valid db column
+// identifier that could be used as keyset pagination cursor column.
+//
+// Fields with conditional visibility (eg. non-public emails) are not
+// allowed because their values are stored in the cursor.
+func (s *SortField) buildCursorColumn(fieldResolver FieldResolver) (string, error) {
+	result, err := s.resolve(fieldResolver)
+	if err != nil {
+		return "", err
+	}
+
+	if result.AfterBuild != nil {
+		return "", fmt.Errorf("sort field %q is not supported in cursor mode", s.Name)
+	}
+
+	return result.Identifier, nil
+}
+
+func (s *SortField) resolve(fieldResolver FieldResolver) (*ResolverResult, error) {
 	result, err := fieldResolver.Resolve(s.Name)
 
 	// invalidate empty fields and non-column identifiers
-	if err != nil || len(result.Params) > 0 || result.Identifier == "" || strings.ToLower(result.Identifier) == "null" {
-		return "", fmt.Errorf("invalid sort field %q", s.Name)
+	if err != nil ||
+		len(result.Params) > 0 ||
+		result.Identifier == "" ||
+		strings.ToLower(result.Identifier) == "null" {
+		return nil, fmt.Errorf("invalid sort field %q", s.Name)
 	}
 
-	return fmt.Sprintf("%s %s", result.Identifier, s.Direction), nil
+	return result, nil
 }
 
 // ParseSortFromString parses the provided string expression
@@ -0,0 +1,129 @@
+package security
+
+import (
+	"crypto/hmac"
+	crand "crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// TOTPPeriod is the validity period of a single TOTP code (RFC 6238 default).
+	TOTPPeriod = 30 * time.Second
+
+	// TOTPDigits is the number of digits of a single TOTP code.
+	TOTPDigits = 6
+
+	// totpSkew is the number of adjacent periods (before and after the current one)
+	// that are also accepted to compensate minor clock drifts.
+	totpSkew = 1
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewTOTPSecret generates a new cryptographically random
+// base32 encoded (without padding) 160-bit TOTP secret.
+func NewTOTPSecret() (string, error) {
+	b := make([]byte, 20)
+
+	if _, err := crand.Read(b); err != nil {
+		return "", err
+	}
+
+	return totpEncoding.EncodeToString(b), nil
+}
+
+// TOTPCode generates the [RFC 6238] time-based one-time password
+// for the provided base32 encoded secret and time.
+//
+// [RFC 6238]: https://datatracker.ietf.org/doc/html/rfc6238
+func TOTPCode(secret string, t time.Time) (string, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil {
+		return "", err
+	}
+
+	return hotp(key, uint64(t.Unix()/int64(TOTPPeriod.Seconds()))), nil
+}
+
+// ValidateTOTPCode reports whether the provided code is a valid
+// TOTP code for the base32 encoded secret at time t.
+//
+// Codes from the adjacent periods are also accepted to
+// compensate minor clock drifts between the server and the client.
+func ValidateTOTPCode(secret string, code string, t time.Time) bool {
+	_, ok := MatchTOTPCodeStep(secret, code, t)
+
+	return ok
+}
+
+// MatchTOTPCodeStep is similar to [ValidateTOTPCode] but also returns
+// the time step (aka. the period counter) of the matched code.
+//
+// The returned step could be used to reject already accepted codes.
+func MatchTOTPCodeStep(secret string, code string, t time.Time) (int64, bool) {
+	if len(code) != TOTPDigits {
+		return 0, false
+	}
+
+	for i := -totpSkew; i <= totpSkew; i++ {
+		periodTime := t.Add(time.Duration(i) * TOTPPeriod)
+
+		expected, err := TOTPCode(secret, periodTime)
+		if err != nil {
+			return 0, false
+		}
+
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return periodTime.Unix() / int64(TOTPPeriod.Seconds()), true
+		}
+	}
+
+	return 0, false
+}
+
+// TOTPAuthUrl returns the "otpauth://" key uri of the provided
+// TOTP secret (usually rendered as QR code for the authenticator apps).
+//
+// See https://github.com/google/google-authenticator/wiki/Key-Uri-Format.
+func TOTPAuthUrl(issuer string, account string, secret string) string {
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(account)
+
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(TOTPDigits))
+	query.Set("period", fmt.Sprint(int(TOTPPeriod.Seconds())))
+
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// hotp generates a [RFC 4226] HMAC-based one-time password.
+//
+// [RFC 4226]: https://datatracker.ietf.org/doc/html/rfc4226
+func hotp(key []byte, counter uint64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, counter)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	// dynamic truncation
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < TOTPDigits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", TOTPDigits, value%mod)
+}
@@ -0,0 +1,148 @@
+package security_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+// base32 of the RFC 6238 SHA1 test secret "12345678901234567890"
+const testTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestNewTOTPSecret(t *testing.T) {
+	generated := map[string]struct{}{}
+
+	for i := 0; i < 100; i++ {
+		secret, err := security.NewTOTPSecret()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(secret) != 32 {
+			t.Fatalf("Expected 32 chars secret, got %q", secret)
+		}
+
+		if _, ok := generated[secret]; ok {
+			t.Fatalf("Duplicated secret %q", secret)
+		}
+		generated[secret] = struct{}{}
+
+		// should be usable for code generation
+		if _, err := security.TOTPCode(secret, time.Now()); err != nil {
+			t.Fatalf("Failed to generate code for secret %q: %v", secret, err)
+		}
+	}
+}
+
+func TestTOTPCode(t *testing.T) {
+	// the RFC 6238 test vectors (truncated to 6 digits)
+	scenarios := []struct {
+		unix     int64
+		expected string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, s := range scenarios {
+		code, err := security.TOTPCode(testTOTPSecret, time.Unix(s.unix, 0))
+		if err != nil {
+			t.Fatalf("(%d) Unexpected error: %v", s.unix, err)
+		}
+
+		if code != s.expected {
+			t.Fatalf("(%d) Expected code %q, got %q", s.unix, s.expected, code)
+		}
+	}
+
+	if _, err := security.TOTPCode("invalid!", time.Now()); err == nil {
+		t.Fatal("Expected error for invalid secret")
+	}
+}
+
+func TestValidateTOTPCode(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+
+	scenarios := []struct {
+		name     string
+		secret   string
+		code     string
+		expected bool
+	}{
+		{"empty code", testTOTPSecret, "", false},
+		{"invalid code length", testTOTPSecret, "0504711", false},
+		{"invalid secret", "invalid!", "050471", false},
+		{"wrong code", testTOTPSecret, "123456", false},
+		{"current period code", testTOTPSecret, "050471", true},
+		{"previous period code", testTOTPSecret, mustTOTPCode(t, now.Add(-security.TOTPPeriod)), true},
+		{"next period code", testTOTPSecret, mustTOTPCode(t, now.Add(security.TOTPPeriod)), true},
+		{"too old code", testTOTPSecret, mustTOTPCode(t, now.Add(-3*security.TOTPPeriod)), false},
+	}
+
+	for _, s := range scenarios {
+		result := security.ValidateTOTPCode(s.secret, s.code, now)
+		if result != s.expected {
+			t.Errorf("[%s] Expected %v, got %v", s.name, s.expected, result)
+		}
+	}
+}
+
+func TestMatchTOTPCodeStep(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	step := now.Unix() / int64(security.TOTPPeriod.Seconds())
+
+	scenarios := []struct {
+		name         string
+		code         string
+		expectedStep int64
+		expected     bool
+	}{
+		{"wrong code", "123456", 0, false},
+		{"current period code", "050471", step, true},
+		{"previous period code", mustTOTPCode(t, now.Add(-security.TOTPPeriod)), step - 1, true},
+		{"next period code", mustTOTPCode(t, now.Add(security.TOTPPeriod)), step + 1, true},
+	}
+
+	for _, s := range scenarios {
+		resultStep, result := security.MatchTOTPCodeStep(testTOTPSecret, s.code, now)
+		if result != s.expected {
+			t.Errorf("[%s] Expected %v, got %v", s.name, s.expected, result)
+		}
+		if resultStep != s.expectedStep {
+			t.Errorf("[%s] Expected step %d, got %d", s.name, s.expectedStep, resultStep)
+		}
+	}
+}
+
+func TestTOTPAuthUrl(t *testing.T) {
+	result := security.TOTPAuthUrl("Acme Inc", "test@example.com", "ABC")
+
+	expectedParts := []string{
+		"otpauth://totp/Acme%20Inc:test@example.com?",
+		"secret=ABC",
+		"issuer=Acme+Inc",
+		"algorithm=SHA1",
+		"digits=6",
+		"period=30",
+	}
+
+	for _, part := range expectedParts {
+		if !strings.Contains(result, part) {
+			t.Errorf("Expected %q to contain %q", result, part)
+		}
+	}
+}
+
+func mustTOTPCode(t *testing.T, time time.Time) string {
+	code, err := security.TOTPCode(testTOTPSecret, time)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
@@ -131,3 +131,24 @@ func (s *Store[T]) SetIfLessThanLimit(key string, value T, maxAllowedElements in
 
 	return true
 }
+
+// SetIfNotExists sets a new value for key only if it is not already set.
+//
+// The check and the assignment are performed atomically under the store lock.
+// `false` is returned if the key already exists.
+func (s *Store[T]) SetIfNotExists(key string, value T) bool {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.data == nil {
+		s.data = make(map[string]T)
+	}
+
+	if _, ok := s.data[key]; ok {
+		return false
+	}
+
+	s.data[key] = value
+
+	return true
+}
@@ -3,6 +3,7 @@ package store_test
 import (
 	"bytes"
 	"encoding/json"
+	"sync"
 	"testing"
 
 	"github.com/pocketbase/pocketbase/tools/store"
@@ -230,3 +231,56 @@ func TestSetIfLessThanLimit(t *testing.T) {
 		}
 	}
 }
+
+func TestSetIfNotExists(t *testing.T) {
+	s := store.Store[int]{}
+
+	scenarios := []struct {
+		key           string
+		value         int
+		expected      bool
+		expectedValue int
+	}{
+		{"test1", 1, true, 1},
+		{"test2", 2, true, 2},
+		{"test1", 3, false, 1},
+	}
+
+	for i, scenario := range scenarios {
+		result := s.SetIfNotExists(scenario.key, scenario.value)
+
+		if result != scenario.expected {
+			t.Errorf("(%d) Expected result %v, got %v", i, scenario.expected, result)
+		}
+
+		if val := s.Get(scenario.key); val != scenario.expectedValue {
+			t.Errorf("(%d) Expected value %v, got %v", i, scenario.expectedValue, val)
+		}
+	}
+}
+
+func TestSetIfNotExistsConcurrent(t *testing.T) {
+	s := store.Store[int]{}
+
+	var wg sync.WaitGroup
+	var mux sync.Mutex
+	var succeeded int
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if s.SetIfNotExists("test", i) {
+				mux.Lock()
+				succeeded++
+				mux.Unlock()
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	if succeeded != 1 {
+		t.Fatalf("Expected exactly 1 successful set, got %d", succeeded)
+	}
+}
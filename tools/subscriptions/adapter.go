@@ -0,0 +1,32 @@
+package subscriptions
+
+// Event defines a broker event shared between multiple broker instances.
+type Event struct {
+	// Name is the event identifier (eg. "@record").
+	Name string `json:"name"`
+
+	// Data is the serialized event payload.
+	Data string `json:"data"`
+}
+
+// EventHandler defines a broker event handler function.
+type EventHandler func(event Event)
+
+// Adapter is an interface for a pub/sub transport that shares the
+// broker events between multiple broker instances (eg. app nodes).
+//
+// The adapter is expected to deliver only the events published by
+// the other broker instances (aka. not the ones published by itself).
+type Adapter interface {
+	// Publish sends the event to the other broker instances.
+	Publish(event Event) error
+
+	// Listen starts forwarding the events published by the
+	// other broker instances to handler until Close is called.
+	Listen(handler EventHandler) error
+
+	// Close stops listening for new events and releases the adapter resources.
+	//
+	// It is safe to call Close() multiple times.
+	Close() error
+}
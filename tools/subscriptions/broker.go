@@ -5,22 +5,79 @@ import (
 	"sync"
 )
 
-// Broker defines a struct for managing subscriptions clients.
-type Broker struct {
-	mux     sync.RWMutex
-	clients map[string]Client
+// Broker is an interface for a realtime subscriptions broker.
+//
+// Besides managing the connected clients, the broker could also share
+// events with the other broker instances (eg. other app nodes behind
+// a load balancer) through a pluggable [Adapter].
+type Broker interface {
+	// Clients returns a shallow copy of all registered clients indexed
+	// with their connection id.
+	Clients() map[string]Client
+
+	// ClientById finds a registered client by its id.
+	//
+	// Returns non-nil error when client with clientId is not registered.
+	ClientById(clientId string) (Client, error)
+
+	// Register adds a new client to the broker instance.
+	Register(client Client)
+
+	// Unregister removes a single client by its id.
+	//
+	// If client with clientId doesn't exist, this method does nothing.
+	Unregister(clientId string)
+
+	// ChannelSubscribers returns the registered clients subscribed
+	// to the named custom channel (sorted by their id).
+	//
+	// Note that the result could also contain discarded clients.
+	ChannelSubscribers(name string) []Client
+
+	// Publish shares the event with the other broker instances
+	// through the broker adapter.
+	//
+	// The event is not dispatched to the current broker event handlers
+	// and it is a no-op if the broker doesn't have an adapter.
+	Publish(event Event) error
+
+	// OnEvent registers a handler for the events published
+	// by the other broker instances.
+	OnEvent(handler EventHandler)
+
+	// SetAdapter replaces the broker adapter and closes the previous one (if any).
+	//
+	// Set nil to stop sharing events with the other broker instances.
+	SetAdapter(adapter Adapter) error
+}
+
+// ensures that DefaultBroker satisfies the Broker interface
+var _ Broker = (*DefaultBroker)(nil)
+
+// DefaultBroker defines the default in-memory subscriptions broker.
+type DefaultBroker struct {
+	mux      sync.RWMutex
+	clients  map[string]Client
+	adapter  Adapter
+	handlers []EventHandler
 }
 
-// NewBroker initializes and returns a new Broker instance.
-func NewBroker() *Broker {
-	return &Broker{
+// NewDefaultBroker initializes and returns a new DefaultBroker instance.
+func NewDefaultBroker() *DefaultBroker {
+	return &DefaultBroker{
 		clients: make(map[string]Client),
 	}
 }
 
-// Clients returns a shallow copy of all registered clients indexed
-// with their connection id.
-func (b *Broker) Clients() map[string]Client {
+// NewBroker initializes and returns a new DefaultBroker instance.
+//
+// Deprecated: Use NewDefaultBroker() instead.
+func NewBroker() *DefaultBroker {
+	return NewDefaultBroker()
+}
+
+// Clients implements the [Broker.Clients] interface method.
+func (b *DefaultBroker) Clients() map[string]Client {
 	b.mux.RLock()
 	defer b.mux.RUnlock()
 
@@ -33,10 +90,8 @@ func (b *Broker) Clients() map[string]Client {
 	return copy
 }
 
-// ClientById finds a registered client by its id.
-//
-// Returns non-nil error when client with clientId is not registered.
-func (b *Broker) ClientById(clientId string) (Client, error) {
+// ClientById implements the [Broker.ClientById] interface method.
+func (b *DefaultBroker) ClientById(clientId string) (Client, error) {
 	b.mux.RLock()
 	defer b.mux.RUnlock()
 
@@ -48,18 +103,16 @@ func (b *Broker) ClientById(clientId string) (Client, error) {
 	return client, nil
 }
 
-// Register adds a new client to the broker instance.
-func (b *Broker) Register(client Client) {
+// Register implements the [Broker.Register] interface method.
+func (b *DefaultBroker) Register(client Client) {
 	b.mux.Lock()
 	defer b.mux.Unlock()
 
 	b.clients[client.Id()] = client
 }
 
-// Unregister removes a single client by its id.
-//
-// If client with clientId doesn't exist, this method does nothing.
-func (b *Broker) Unregister(clientId string) {
+// Unregister implements the [Broker.Unregister] interface method.
+func (b *DefaultBroker) Unregister(clientId string) {
 	b.mux.Lock()
 	defer b.mux.Unlock()
 
@@ -68,3 +121,66 @@ func (b *Broker) Unregister(clientId string) {
 		delete(b.clients, clientId)
 	}
 }
+
+// Publish implements the [Broker.Publish] interface method.
+func (b *DefaultBroker) Publish(event Event) error {
+	b.mux.RLock()
+	adapter := b.adapter
+	b.mux.RUnlock()
+
+	if adapter == nil {
+		return nil // nothing to share with
+	}
+
+	return adapter.Publish(event)
+}
+
+// OnEvent implements the [Broker.OnEvent] interface method.
+func (b *DefaultBroker) OnEvent(handler EventHandler) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	b.handlers = append(b.handlers, handler)
+}
+
+// SetAdapter implements the [Broker.SetAdapter] interface method.
+func (b *DefaultBroker) SetAdapter(adapter Adapter) error {
+	// the previous adapter is closed outside of the lock because
+	// it may be still dispatching events (see [DefaultBroker.dispatch])
+	b.mux.Lock()
+	prevAdapter := b.adapter
+	b.adapter = nil
+	b.mux.Unlock()
+
+	if prevAdapter != nil {
+		if err := prevAdapter.Close(); err != nil {
+			return err
+		}
+	}
+
+	if adapter == nil {
+		return nil
+	}
+
+	if err := adapter.Listen(b.dispatch); err != nil {
+		return err
+	}
+
+	b.mux.Lock()
+	b.adapter = adapter
+	b.mux.Unlock()
+
+	return nil
+}
+
+// dispatch calls the registered event handlers with the provided event.
+func (b *DefaultBroker) dispatch(event Event) {
+	b.mux.RLock()
+	handlers := make([]EventHandler, len(b.handlers))
+	copy(handlers, b.handlers)
+	b.mux.RUnlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
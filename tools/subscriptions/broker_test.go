@@ -6,8 +6,8 @@ import (
 	"github.com/pocketbase/pocketbase/tools/subscriptions"
 )
 
-func TestNewBroker(t *testing.T) {
-	b := subscriptions.NewBroker()
+func TestNewDefaultBroker(t *testing.T) {
+	b := subscriptions.NewDefaultBroker()
 
 	if b.Clients() == nil {
 		t.Fatal("Expected clients map to be initialized")
@@ -15,7 +15,7 @@ func TestNewBroker(t *testing.T) {
 }
 
 func TestClients(t *testing.T) {
-	b := subscriptions.NewBroker()
+	b := subscriptions.NewDefaultBroker()
 
 	if total := len(b.Clients()); total != 0 {
 		t.Fatalf("Expected no clients, got %v", total)
@@ -37,7 +37,7 @@ func TestClients(t *testing.T) {
 }
 
 func TestClientById(t *testing.T) {
-	b := subscriptions.NewBroker()
+	b := subscriptions.NewDefaultBroker()
 
 	clientA := subscriptions.NewDefaultClient()
 	clientB := subscriptions.NewDefaultClient()
@@ -58,7 +58,7 @@ func TestClientById(t *testing.T) {
 }
 
 func TestRegister(t *testing.T) {
-	b := subscriptions.NewBroker()
+	b := subscriptions.NewDefaultBroker()
 
 	client := subscriptions.NewDefaultClient()
 	b.Register(client)
@@ -69,7 +69,7 @@ func TestRegister(t *testing.T) {
 }
 
 func TestUnregister(t *testing.T) {
-	b := subscriptions.NewBroker()
+	b := subscriptions.NewDefaultBroker()
 
 	clientA := subscriptions.NewDefaultClient()
 	clientB := subscriptions.NewDefaultClient()
@@ -91,3 +91,90 @@ func TestUnregister(t *testing.T) {
 		t.Fatalf("Expected client with id %s, got error %v", clientB.Id(), err)
 	}
 }
+
+type testAdapter struct {
+	published []subscriptions.Event
+	handler   subscriptions.EventHandler
+	closed    bool
+}
+
+func (a *testAdapter) Publish(event subscriptions.Event) error {
+	a.published = append(a.published, event)
+	return nil
+}
+
+func (a *testAdapter) Listen(handler subscriptions.EventHandler) error {
+	a.handler = handler
+	return nil
+}
+
+func (a *testAdapter) Close() error {
+	a.closed = true
+	return nil
+}
+
+func TestBrokerPublishWithoutAdapter(t *testing.T) {
+	b := subscriptions.NewDefaultBroker()
+
+	if err := b.Publish(subscriptions.Event{Name: "test"}); err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+}
+
+func TestBrokerSetAdapter(t *testing.T) {
+	b := subscriptions.NewDefaultBroker()
+
+	received := []string{}
+	b.OnEvent(func(e subscriptions.Event) {
+		received = append(received, "a:"+e.Name)
+	})
+	b.OnEvent(func(e subscriptions.Event) {
+		received = append(received, "b:"+e.Name)
+	})
+
+	adapter1 := &testAdapter{}
+	if err := b.SetAdapter(adapter1); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := b.Publish(subscriptions.Event{Name: "test1", Data: "1"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(adapter1.published) != 1 || adapter1.published[0].Data != "1" {
+		t.Fatalf("Expected the event to be published, got %v", adapter1.published)
+	}
+	if len(received) != 0 {
+		t.Fatalf("Expected the published event to not be dispatched locally, got %v", received)
+	}
+
+	// simulate an event from another broker instance
+	adapter1.handler(subscriptions.Event{Name: "remote"})
+	if len(received) != 2 || received[0] != "a:remote" || received[1] != "b:remote" {
+		t.Fatalf("Expected the remote event to be dispatched to all handlers, got %v", received)
+	}
+
+	// replace the adapter
+	adapter2 := &testAdapter{}
+	if err := b.SetAdapter(adapter2); err != nil {
+		t.Fatal(err)
+	}
+	if !adapter1.closed {
+		t.Fatal("Expected the previous adapter to be closed")
+	}
+	b.Publish(subscriptions.Event{Name: "test2"})
+	if len(adapter1.published) != 1 || len(adapter2.published) != 1 {
+		t.Fatalf("Expected the event to be published only with the new adapter, got %v vs %v", adapter1.published, adapter2.published)
+	}
+
+	// unset
+	if err := b.SetAdapter(nil); err != nil {
+		t.Fatal(err)
+	}
+	if !adapter2.closed {
+		t.Fatal("Expected the adapter to be closed")
+	}
+	b.Publish(subscriptions.Event{Name: "test3"})
+	if len(adapter2.published) != 1 {
+		t.Fatalf("Expected no more published events, got %v", adapter2.published)
+	}
+}
@@ -0,0 +1,53 @@
+package subscriptions
+
+import (
+	"sort"
+	"strings"
+)
+
+// ChannelTopicPrefix is the subscription topic prefix of the custom
+// (aka. user-defined) channels, eg. "@channel/chat".
+const ChannelTopicPrefix = "@channel/"
+
+// ChannelTopic returns the subscription topic of the named custom channel.
+func ChannelTopic(name string) string {
+	return ChannelTopicPrefix + name
+}
+
+// ChannelName extracts the custom channel name from a subscription topic.
+//
+// Returns false if the topic is not a custom channel topic.
+func ChannelName(topic string) (string, bool) {
+	if !strings.HasPrefix(topic, ChannelTopicPrefix) {
+		return "", false
+	}
+
+	name := strings.TrimPrefix(topic, ChannelTopicPrefix)
+
+	return name, name != ""
+}
+
+// ChannelSubscribers implements the [Broker.ChannelSubscribers] interface method.
+//
+// Note that the result could also contain discarded clients
+// (use [Client.IsDiscarded] if you need to filter them).
+func (b *DefaultBroker) ChannelSubscribers(name string) []Client {
+	topic := ChannelTopic(name)
+
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+
+	result := []Client{}
+
+	for _, client := range b.clients {
+		if client.HasSubscription(topic) {
+			result = append(result, client)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Id() < result[j].Id()
+	})
+
+	return result
+}
@@ -0,0 +1,84 @@
+package subscriptions_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/subscriptions"
+)
+
+func TestChannelTopic(t *testing.T) {
+	if topic := subscriptions.ChannelTopic("chat"); topic != "@channel/chat" {
+		t.Fatalf("Expected @channel/chat, got %q", topic)
+	}
+}
+
+func TestChannelName(t *testing.T) {
+	scenarios := []struct {
+		topic        string
+		expectedName string
+		expectedOk   bool
+	}{
+		{"", "", false},
+		{"demo/*", "", false},
+		{"@channel/", "", false},
+		{"channel/chat", "", false},
+		{"@channel/chat", "chat", true},
+	}
+
+	for i, s := range scenarios {
+		name, ok := subscriptions.ChannelName(s.topic)
+
+		if name != s.expectedName || ok != s.expectedOk {
+			t.Errorf("(%d) Expected (%q, %v), got (%q, %v)", i, s.expectedName, s.expectedOk, name, ok)
+		}
+	}
+}
+
+func TestBrokerChannelSubscribers(t *testing.T) {
+	b := subscriptions.NewDefaultBroker()
+
+	c1 := subscriptions.NewDefaultClient()
+	c1.Subscribe("@channel/a", "@channel/b")
+
+	c2 := subscriptions.NewDefaultClient()
+	c2.Subscribe("@channel/a", "a")
+
+	c3 := subscriptions.NewDefaultClient()
+	c3.Subscribe("@channel/a")
+	c3.Discard()
+
+	b.Register(c1)
+	b.Register(c2)
+	b.Register(c3)
+
+	scenarios := []struct {
+		channel  string
+		expected []subscriptions.Client
+	}{
+		{"missing", nil},
+		{"b", []subscriptions.Client{c1}},
+		{"a", []subscriptions.Client{c1, c2, c3}},
+	}
+
+	for i, s := range scenarios {
+		result := b.ChannelSubscribers(s.channel)
+
+		if len(result) != len(s.expected) {
+			t.Errorf("(%d) Expected %d clients, got %d", i, len(s.expected), len(result))
+			continue
+		}
+
+		for _, expected := range s.expected {
+			var exists bool
+			for _, c := range result {
+				if c.Id() == expected.Id() {
+					exists = true
+					break
+				}
+			}
+			if !exists {
+				t.Errorf("(%d) Missing client %q", i, expected.Id())
+			}
+		}
+	}
+}
@@ -8,6 +8,10 @@ import (
 
 // Message defines a client's channel data.
 type Message struct {
+	// Id is an optional unique and monotonically
+	// increasing message identifier (see [NextMessageId]).
+	Id uint64
+
 	Name string
 	Data string
 }
@@ -20,7 +24,8 @@ type Client interface {
 	// Channel returns the client's communication channel.
 	Channel() chan Message
 
-	// Subscriptions returns all subscriptions to which the client has subscribed to.
+	// Subscriptions returns a shallow copy of all subscriptions
+	// to which the client has subscribed to.
 	Subscriptions() map[string]struct{}
 
 	// Subscribe subscribes the client to the provided subscriptions list.
@@ -93,7 +98,13 @@ func (c *DefaultClient) Subscriptions() map[string]struct{} {
 	c.mux.RLock()
 	defer c.mux.RUnlock()
 
-	return c.subscriptions
+	copy := make(map[string]struct{}, len(c.subscriptions))
+
+	for s := range c.subscriptions {
+		copy[s] = struct{}{}
+	}
+
+	return copy
 }
 
 // Subscribe implements the [Client.Subscribe] interface method.
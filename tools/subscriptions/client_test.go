@@ -66,6 +66,13 @@ func TestSubscriptions(t *testing.T) {
 	if len(c.Subscriptions()) != 3 {
 		t.Errorf("Expected 3 subscriptions, got %v", c.Subscriptions())
 	}
+
+	// modifying the returned map shouldn't change the client subscriptions
+	delete(c.Subscriptions(), "sub1")
+
+	if !c.HasSubscription("sub1") {
+		t.Errorf("Expected sub1 to be still subscribed")
+	}
 }
 
 func TestSubscribe(t *testing.T) {
@@ -0,0 +1,94 @@
+package subscriptions
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// lastMessageId is seeded with the current time so that the
+// message ids are increasing also between app restarts.
+var lastMessageId = uint64(time.Now().UnixMicro())
+
+// NextMessageId returns a new unique and monotonically increasing message id.
+func NextMessageId() uint64 {
+	return atomic.AddUint64(&lastMessageId, 1)
+}
+
+// History defines a bounded per-topic (aka. per message name)
+// buffer with the latest client messages.
+type History struct {
+	mux         sync.RWMutex
+	maxPerTopic int
+	topics      map[string][]Message
+	discarded   map[string]uint64 // the id of the last discarded message per topic
+}
+
+// NewHistory creates a new History instance that keeps
+// up to maxPerTopic messages for each topic.
+func NewHistory(maxPerTopic int) *History {
+	if maxPerTopic < 1 {
+		maxPerTopic = 1
+	}
+
+	return &History{
+		maxPerTopic: maxPerTopic,
+		topics:      map[string][]Message{},
+		discarded:   map[string]uint64{},
+	}
+}
+
+// Push appends the message to its topic buffer.
+//
+// The oldest topic message is discarded if the buffer is full.
+func (h *History) Push(msg Message) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	messages := append(h.topics[msg.Name], msg)
+
+	if len(messages) > h.maxPerTopic {
+		h.discarded[msg.Name] = messages[0].Id
+		messages = messages[1:]
+	}
+
+	h.topics[msg.Name] = messages
+}
+
+// Since returns the buffered messages of the specified topics
+// with id greater than lastId (sorted by their id).
+//
+// The returned bool is false if some of the topics messages
+// after lastId were already discarded (aka. there is a gap).
+func (h *History) Since(lastId uint64, topics ...string) ([]Message, bool) {
+	h.mux.RLock()
+	defer h.mux.RUnlock()
+
+	result := []Message{}
+	complete := true
+	checked := make(map[string]struct{}, len(topics))
+
+	for _, topic := range topics {
+		if _, ok := checked[topic]; ok {
+			continue // already checked
+		}
+		checked[topic] = struct{}{}
+
+		if h.discarded[topic] > lastId {
+			complete = false
+		}
+
+		for _, msg := range h.topics[topic] {
+			if msg.Id > lastId {
+				result = append(result, msg)
+			}
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Id < result[j].Id
+	})
+
+	return result, complete
+}
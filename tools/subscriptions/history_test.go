@@ -0,0 +1,65 @@
+package subscriptions_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/subscriptions"
+)
+
+func TestNextMessageId(t *testing.T) {
+	last := subscriptions.NextMessageId()
+
+	for i := 0; i < 5; i++ {
+		id := subscriptions.NextMessageId()
+		if id <= last {
+			t.Fatalf("Expected %d to be greater than %d", id, last)
+		}
+		last = id
+	}
+}
+
+func TestHistoryPushAndSince(t *testing.T) {
+	h := subscriptions.NewHistory(2)
+
+	h.Push(subscriptions.Message{Id: 1, Name: "a"})
+	h.Push(subscriptions.Message{Id: 2, Name: "b"})
+	h.Push(subscriptions.Message{Id: 3, Name: "a"})
+	h.Push(subscriptions.Message{Id: 4, Name: "a"}) // discards 1
+	h.Push(subscriptions.Message{Id: 5, Name: "b"})
+
+	scenarios := []struct {
+		lastId           uint64
+		topics           []string
+		expectedIds      []uint64
+		expectedComplete bool
+	}{
+		{0, nil, []uint64{}, true},
+		{0, []string{"missing"}, []uint64{}, true},
+		{0, []string{"b"}, []uint64{2, 5}, true},
+		{2, []string{"b"}, []uint64{5}, true},
+		{0, []string{"a"}, []uint64{3, 4}, false},
+		{1, []string{"a"}, []uint64{3, 4}, true},
+		{1, []string{"a", "b", "a"}, []uint64{2, 3, 4, 5}, true},
+		{0, []string{"b", "a"}, []uint64{2, 3, 4, 5}, false},
+		{5, []string{"a", "b"}, []uint64{}, true},
+	}
+
+	for i, s := range scenarios {
+		messages, complete := h.Since(s.lastId, s.topics...)
+
+		if complete != s.expectedComplete {
+			t.Errorf("(%d) Expected complete %v, got %v", i, s.expectedComplete, complete)
+		}
+
+		if len(messages) != len(s.expectedIds) {
+			t.Errorf("(%d) Expected %d messages, got %v", i, len(s.expectedIds), messages)
+			continue
+		}
+
+		for j, id := range s.expectedIds {
+			if messages[j].Id != id {
+				t.Errorf("(%d) Expected message %d to have id %d, got %d", i, j, id, messages[j].Id)
+			}
+		}
+	}
+}
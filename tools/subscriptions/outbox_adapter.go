@@ -0,0 +1,177 @@
+package subscriptions
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+// OutboxTable is the name of the [OutboxAdapter] events table.
+const OutboxTable = "_realtimeOutbox"
+
+// ensures that OutboxAdapter satisfies the Adapter interface
+var _ Adapter = (*OutboxAdapter)(nil)
+
+// OutboxAdapter is a reference [Adapter] implementation that shares the
+// broker events through a SQLite table (aka. outbox) accessible by all
+// broker instances (eg. a db file on a volume shared between the app nodes).
+//
+// Each adapter periodically polls the table for new events published by
+// the other adapters. Events older than Retention are deleted.
+//
+// Note that the adapter doesn't close the provided db connection.
+type OutboxAdapter struct {
+	// PollInterval specifies how often to check for new events (default to 250ms).
+	PollInterval time.Duration
+
+	// Retention specifies how long to keep the published events (default to 1 minute).
+	Retention time.Duration
+
+	mux    sync.Mutex
+	db     *dbx.DB
+	nodeId string
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// outboxRow defines a single OutboxTable row.
+type outboxRow struct {
+	Id     int64  `db:"id"`
+	Origin string `db:"origin"`
+	Name   string `db:"name"`
+	Data   string `db:"data"`
+}
+
+// NewOutboxAdapter creates a new OutboxAdapter instance and
+// ensures that the OutboxTable exists in the provided db.
+func NewOutboxAdapter(db *dbx.DB) (*OutboxAdapter, error) {
+	_, err := db.NewQuery(`
+		CREATE TABLE IF NOT EXISTS {{` + OutboxTable + `}} (
+			[[id]]      INTEGER PRIMARY KEY AUTOINCREMENT,
+			[[origin]]  TEXT NOT NULL,
+			[[name]]    TEXT NOT NULL,
+			[[data]]    TEXT NOT NULL,
+			[[created]] INTEGER NOT NULL
+		)
+	`).Execute()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.NewQuery(`
+		CREATE INDEX IF NOT EXISTS _realtimeOutbox_created_idx ON {{` + OutboxTable + `}} ([[created]])
+	`).Execute()
+	if err != nil {
+		return nil, err
+	}
+
+	return &OutboxAdapter{
+		PollInterval: 250 * time.Millisecond,
+		Retention:    time.Minute,
+		db:           db,
+		nodeId:       security.RandomString(20),
+	}, nil
+}
+
+// Publish implements the [Adapter.Publish] interface method.
+func (a *OutboxAdapter) Publish(event Event) error {
+	_, err := a.db.Insert(OutboxTable, dbx.Params{
+		"origin":  a.nodeId,
+		"name":    event.Name,
+		"data":    event.Data,
+		"created": time.Now().UnixMilli(),
+	}).Execute()
+
+	return err
+}
+
+// Listen implements the [Adapter.Listen] interface method.
+//
+// Only the events published after the Listen call are forwarded.
+func (a *OutboxAdapter) Listen(handler EventHandler) error {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	if a.stop != nil {
+		return errors.New("The outbox adapter is already listening.")
+	}
+
+	var lastId int64
+	err := a.db.Select("COALESCE(MAX([[id]]), 0)").From(OutboxTable).Row(&lastId)
+	if err != nil {
+		return err
+	}
+
+	a.stop = make(chan struct{})
+	a.done = make(chan struct{})
+
+	go a.poll(lastId, handler, a.stop, a.done)
+
+	return nil
+}
+
+// Close implements the [Adapter.Close] interface method.
+func (a *OutboxAdapter) Close() error {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	if a.stop == nil {
+		return nil // not listening
+	}
+
+	close(a.stop)
+	<-a.done
+
+	a.stop = nil
+	a.done = nil
+
+	return nil
+}
+
+func (a *OutboxAdapter) poll(lastId int64, handler EventHandler, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(a.PollInterval)
+	defer ticker.Stop()
+
+	lastCleanup := time.Now()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			rows := []outboxRow{}
+
+			err := a.db.Select("id", "origin", "name", "data").
+				From(OutboxTable).
+				AndWhere(dbx.NewExp("[[id]] > {:lastId}", dbx.Params{"lastId": lastId})).
+				OrderBy("id ASC").
+				All(&rows)
+			if err != nil {
+				continue // retry on the next tick
+			}
+
+			for _, row := range rows {
+				lastId = row.Id
+
+				if row.Origin == a.nodeId {
+					continue // published by the current adapter
+				}
+
+				handler(Event{Name: row.Name, Data: row.Data})
+			}
+
+			if time.Since(lastCleanup) > a.Retention {
+				lastCleanup = time.Now()
+				a.db.Delete(OutboxTable, dbx.NewExp(
+					"[[created]] < {:threshold}",
+					dbx.Params{"threshold": lastCleanup.Add(-a.Retention).UnixMilli()},
+				)).Execute()
+			}
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package subscriptions_test
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/subscriptions"
+	_ "modernc.org/sqlite"
+)
+
+func TestOutboxAdapter(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "outbox.db")
+
+	// each adapter simulates a different app node with its own connection
+	newAdapter := func() *subscriptions.OutboxAdapter {
+		db, err := dbx.Open("sqlite", dbPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { db.Close() })
+
+		adapter, err := subscriptions.NewOutboxAdapter(db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		adapter.PollInterval = 10 * time.Millisecond
+
+		return adapter
+	}
+
+	adapterA := newAdapter()
+	adapterB := newAdapter()
+
+	// published before listening
+	if err := adapterA.Publish(subscriptions.Event{Name: "old"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var mux sync.Mutex
+	receivedA := []subscriptions.Event{}
+	receivedB := []subscriptions.Event{}
+
+	if err := adapterA.Listen(func(e subscriptions.Event) {
+		mux.Lock()
+		receivedA = append(receivedA, e)
+		mux.Unlock()
+	}); err != nil {
+		t.Fatal(err)
+	}
+	defer adapterA.Close()
+
+	if err := adapterB.Listen(func(e subscriptions.Event) {
+		mux.Lock()
+		receivedB = append(receivedB, e)
+		mux.Unlock()
+	}); err != nil {
+		t.Fatal(err)
+	}
+	defer adapterB.Close()
+
+	if err := adapterB.Listen(func(e subscriptions.Event) {}); err == nil {
+		t.Fatal("Expected error when listening multiple times")
+	}
+
+	adapterA.Publish(subscriptions.Event{Name: "a1", Data: `{"a":1}`})
+	adapterA.Publish(subscriptions.Event{Name: "a2", Data: `{"a":2}`})
+	adapterB.Publish(subscriptions.Event{Name: "b1", Data: `{"b":1}`})
+
+	time.Sleep(100 * time.Millisecond)
+
+	mux.Lock()
+	if len(receivedA) != 1 || receivedA[0].Name != "b1" || receivedA[0].Data != `{"b":1}` {
+		t.Errorf("Expected adapter A to receive only b1, got %v", receivedA)
+	}
+	if len(receivedB) != 2 || receivedB[0].Name != "a1" || receivedB[1].Name != "a2" {
+		t.Errorf("Expected adapter B to receive a1 and a2, got %v", receivedB)
+	}
+	mux.Unlock()
+
+	// no more events after close
+	if err := adapterB.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := adapterB.Close(); err != nil {
+		t.Fatalf("Expected multiple Close calls to be safe, got %v", err)
+	}
+	adapterA.Publish(subscriptions.Event{Name: "a3"})
+
+	time.Sleep(50 * time.Millisecond)
+
+	mux.Lock()
+	if len(receivedB) != 2 {
+		t.Errorf("Expected no new events after close, got %v", receivedB)
+	}
+	mux.Unlock()
+}
@@ -0,0 +1,267 @@
+// Package webhooks implements the signed delivery of the record
+// events to the configured outgoing webhooks.
+package webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// Headers sent with each webhook delivery request.
+const (
+	// HeaderId holds the unique delivery id (the same for all retries
+	// of the delivery and could be used as idempotency key).
+	HeaderId = "X-Webhook-Id"
+
+	// HeaderTimestamp holds the unix timestamp of the send attempt.
+	HeaderTimestamp = "X-Webhook-Timestamp"
+
+	// HeaderSignature holds the delivery signature in the format
+	// "sha256=HEX(HMAC-SHA256(secret, timestamp + "." + body))".
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+const (
+	// MaxAttempts is the max number of send attempts of a single
+	// delivery before it is marked as failed.
+	MaxAttempts = 8
+
+	// RetryBaseDelay is the delay before the first retry of a failed
+	// delivery (each following retry doubles the previous delay).
+	RetryBaseDelay = 30 * time.Second
+
+	// RetryMaxDelay is the max delay between two delivery retries.
+	RetryMaxDelay = 6 * time.Hour
+
+	// RequestTimeout is the max duration of a single send attempt.
+	RequestTimeout = 15 * time.Second
+)
+
+// reservationDuration is the time for which a delivery is excluded
+// from the due deliveries list while it is being sent.
+const reservationDuration = 2 * RequestTimeout
+
+var httpClient = &http.Client{Timeout: RequestTimeout}
+
+// Payload defines the webhook delivery request body.
+//
+// It has the same shape as the realtime record messages.
+type Payload struct {
+	Action string         `json:"action"`
+	Record *models.Record `json:"record"`
+}
+
+// Sign returns the signature of the provided webhook payload
+// in the format expected by the [HeaderSignature] header.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// RetryDelay returns the exponential backoff delay before the next
+// send attempt of a delivery that has already failed the specified attempts.
+func RetryDelay(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	delay := float64(RetryBaseDelay) * math.Pow(2, float64(attempts-1))
+	if delay > float64(RetryMaxDelay) {
+		return RetryMaxDelay
+	}
+
+	return time.Duration(delay)
+}
+
+// Enqueue creates and persists a new pending delivery for each
+// enabled webhook subscribed for the specified record action.
+//
+// The created deliveries are reserved for an immediate [Send] call
+// and will be picked by [ProcessDue] only if that call doesn't complete.
+func Enqueue(app core.App, action string, record *models.Record) ([]*models.WebhookDelivery, error) {
+	collection := record.Collection()
+	if collection == nil {
+		return nil, errors.New("Record collection not set.")
+	}
+
+	webhooks, err := app.Dao().FindWebhooksByRecordAction(collection, action)
+	if err != nil || len(webhooks) == 0 {
+		return nil, err
+	}
+
+	// create a clean record copy without expand and unknown fields
+	cleanRecord := record.CleanCopy()
+	cleanRecord.IgnoreEmailVisibility(true)
+
+	payload, err := json.Marshal(&Payload{
+		Action: action,
+		Record: cleanRecord,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	nextAttemptAt, err := types.ParseDateTime(time.Now().Add(reservationDuration))
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]*models.WebhookDelivery, 0, len(webhooks))
+
+	for _, webhook := range webhooks {
+		delivery := &models.WebhookDelivery{
+			WebhookId:     webhook.Id,
+			Url:           webhook.Url,
+			Action:        action,
+			CollectionId:  collection.Id,
+			RecordId:      record.Id,
+			Payload:       types.JsonRaw(payload),
+			Status:        models.WebhookDeliveryStatusPending,
+			NextAttemptAt: nextAttemptAt,
+		}
+
+		if err := app.LogsDao().SaveWebhookDelivery(delivery); err != nil {
+			return deliveries, err
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
+
+// Send performs a single send attempt of the provided pending delivery
+// and persists its updated state.
+//
+// A failed attempt is rescheduled with exponential backoff (see [RetryDelay])
+// until [MaxAttempts] is reached, after which the delivery is marked as failed.
+func Send(app core.App, delivery *models.WebhookDelivery) error {
+	if !delivery.IsPending() {
+		return errors.New("The webhook delivery is not pending.")
+	}
+
+	webhook, err := app.Dao().FindWebhookById(delivery.WebhookId)
+	if err != nil {
+		return markAsFailed(app, delivery, errors.New("Missing or deleted webhook."))
+	}
+
+	if webhook.Disabled {
+		return markAsFailed(app, delivery, errors.New("The webhook is disabled."))
+	}
+
+	// always use the latest webhook url so that broken endpoints could be fixed
+	delivery.Url = webhook.Url
+	delivery.Attempts++
+
+	status, sendErr := post(webhook, delivery)
+
+	delivery.ResponseStatus = status
+
+	if sendErr == nil {
+		delivery.Status = models.WebhookDeliveryStatusSuccess
+		delivery.Error = ""
+	} else {
+		delivery.Error = sendErr.Error()
+
+		if delivery.Attempts >= MaxAttempts {
+			delivery.Status = models.WebhookDeliveryStatusFailed
+		} else {
+			delivery.NextAttemptAt, _ = types.ParseDateTime(time.Now().Add(RetryDelay(delivery.Attempts)))
+		}
+	}
+
+	if err := app.LogsDao().SaveWebhookDelivery(delivery); err != nil {
+		return err
+	}
+
+	return sendErr
+}
+
+// ProcessDue sends (sequentially) up to limit due pending deliveries.
+//
+// It returns the last send error (if any).
+func ProcessDue(app core.App, limit int) error {
+	deliveries, err := app.LogsDao().FindDueWebhookDeliveries(time.Now(), limit)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+
+	for _, delivery := range deliveries {
+		// reserve the delivery to prevent concurrent sends by overlapping calls
+		delivery.NextAttemptAt, _ = types.ParseDateTime(time.Now().Add(reservationDuration))
+		if err := app.LogsDao().SaveWebhookDelivery(delivery); err != nil {
+			lastErr = err
+			continue
+		}
+
+		if err := Send(app, delivery); err != nil {
+			lastErr = err
+		}
+	}
+
+	return lastErr
+}
+
+// markAsFailed persists the delivery as failed without further retries.
+func markAsFailed(app core.App, delivery *models.WebhookDelivery, reason error) error {
+	delivery.Status = models.WebhookDeliveryStatusFailed
+	delivery.Error = reason.Error()
+
+	if err := app.LogsDao().SaveWebhookDelivery(delivery); err != nil {
+		return err
+	}
+
+	return reason
+}
+
+// post sends the delivery payload to the webhook url and returns
+// the response status code (or 0 if no response was received).
+func post(webhook *models.Webhook, delivery *models.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequest(http.MethodPost, webhook.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "PocketBase-Webhooks")
+	req.Header.Set(HeaderId, delivery.Id)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(webhook.Secret, timestamp, body))
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	// drain a limited part of the body to allow the connection reuse
+	io.Copy(io.Discard, io.LimitReader(res.Body, 1<<16))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("Unexpected response status code %d.", res.StatusCode)
+	}
+
+	return res.StatusCode, nil
+}
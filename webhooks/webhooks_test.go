@@ -0,0 +1,370 @@
+package webhooks_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+	"github.com/pocketbase/pocketbase/webhooks"
+)
+
+type receivedRequest struct {
+	headers http.Header
+	body    []byte
+}
+
+type testReceiver struct {
+	*httptest.Server
+
+	mux      sync.Mutex
+	status   int
+	requests []*receivedRequest
+}
+
+func newTestReceiver(status int) *testReceiver {
+	r := &testReceiver{status: status}
+
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+
+		r.mux.Lock()
+		r.requests = append(r.requests, &receivedRequest{headers: req.Header.Clone(), body: body})
+		r.mux.Unlock()
+
+		w.WriteHeader(r.status)
+	}))
+
+	return r
+}
+
+// setTestWebhookUrl enables the specified test webhook and changes its url.
+func setTestWebhookUrl(t *testing.T, app *tests.TestApp, webhookId string, url string) *models.Webhook {
+	webhook, err := app.Dao().FindWebhookById(webhookId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	webhook.Url = url
+	webhook.Disabled = false
+	if err := app.Dao().SaveWebhook(webhook); err != nil {
+		t.Fatal(err)
+	}
+
+	return webhook
+}
+
+func TestSign(t *testing.T) {
+	scenarios := []struct {
+		secret    string
+		timestamp int64
+		body      string
+		expected  string
+	}{
+		{"", 0, "", "sha256=b849d5a581847b281957065739df36df2463d1977ea8d6e1e4e6cf33fadc68c3"},
+		{"test", 1672531200, `{"action":"create"}`, "sha256=bee122bf035be8c6fde495dce21f72d4e69a4f8b2f15bcb9e7e216887e4bfcaa"},
+	}
+
+	for i, s := range scenarios {
+		result := webhooks.Sign(s.secret, s.timestamp, []byte(s.body))
+		if result != s.expected {
+			t.Errorf("(%d) Expected %q, got %q", i, s.expected, result)
+		}
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	scenarios := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{-1, webhooks.RetryBaseDelay},
+		{0, webhooks.RetryBaseDelay},
+		{1, webhooks.RetryBaseDelay},
+		{2, 2 * webhooks.RetryBaseDelay},
+		{3, 4 * webhooks.RetryBaseDelay},
+		{7, 64 * webhooks.RetryBaseDelay},
+		{100, webhooks.RetryMaxDelay},
+	}
+
+	for _, s := range scenarios {
+		result := webhooks.RetryDelay(s.attempts)
+		if result != s.expected {
+			t.Errorf("(%d) Expected %v, got %v", s.attempts, s.expected, result)
+		}
+	}
+}
+
+func TestEnqueue(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	setTestWebhookUrl(t, app, "ftxuvq1ojf4b2pa", "https://example.com")
+
+	demo1Record, err := app.Dao().FindRecordById("demo1", "84nmscqy84lsi1t")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	demo2Record, err := app.Dao().FindRecordById("demo2", "llvuca81nly1qls")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name     string
+		action   string
+		record   *models.Record
+		expected int
+	}{
+		{"non matching collection", models.WebhookActionCreate, demo1Record, 0},
+		{"non matching action", models.WebhookActionAuth, demo2Record, 0},
+		{"matching collection and action", models.WebhookActionUpdate, demo2Record, 1},
+	}
+
+	for _, s := range scenarios {
+		deliveries, err := webhooks.Enqueue(app, s.action, s.record)
+		if err != nil {
+			t.Errorf("[%s] Unexpected error %v", s.name, err)
+			continue
+		}
+
+		if len(deliveries) != s.expected {
+			t.Errorf("[%s] Expected %d deliveries, got %d", s.name, s.expected, len(deliveries))
+			continue
+		}
+
+		for _, d := range deliveries {
+			stored, err := app.LogsDao().FindWebhookDeliveryById(d.Id)
+			if err != nil {
+				t.Errorf("[%s] Expected the delivery to be persisted, got %v", s.name, err)
+				continue
+			}
+
+			if !stored.IsPending() || stored.Attempts != 0 {
+				t.Errorf("[%s] Expected new pending delivery, got %v", s.name, stored)
+			}
+
+			if stored.WebhookId != "ftxuvq1ojf4b2pa" ||
+				stored.Action != s.action ||
+				stored.CollectionId != s.record.Collection().Id ||
+				stored.RecordId != s.record.Id {
+				t.Errorf("[%s] Unexpected delivery fields %v", s.name, stored)
+			}
+
+			if !stored.NextAttemptAt.Time().After(time.Now()) {
+				t.Errorf("[%s] Expected the delivery to be reserved for an immediate send, got %v", s.name, stored.NextAttemptAt)
+			}
+
+			payload := map[string]any{}
+			if err := json.Unmarshal(stored.Payload, &payload); err != nil {
+				t.Errorf("[%s] Failed to decode the payload: %v", s.name, err)
+				continue
+			}
+
+			record, _ := payload["record"].(map[string]any)
+			if payload["action"] != s.action || record == nil || record["id"] != s.record.Id {
+				t.Errorf("[%s] Unexpected payload %s", s.name, stored.Payload)
+			}
+		}
+	}
+}
+
+func TestSendSuccess(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	receiver := newTestReceiver(http.StatusNoContent)
+	defer receiver.Close()
+
+	webhook := setTestWebhookUrl(t, app, "ftxuvq1ojf4b2pa", receiver.URL+"/test")
+
+	record, err := app.Dao().FindRecordById("demo2", "llvuca81nly1qls")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deliveries, err := webhooks.Enqueue(app, models.WebhookActionUpdate, record)
+	if err != nil || len(deliveries) != 1 {
+		t.Fatalf("Expected 1 delivery, got %d (%v)", len(deliveries), err)
+	}
+
+	if err := webhooks.Send(app, deliveries[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(receiver.requests) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(receiver.requests))
+	}
+
+	req := receiver.requests[0]
+
+	if req.headers.Get(webhooks.HeaderId) != deliveries[0].Id {
+		t.Fatalf("Expected delivery id header %q, got %q", deliveries[0].Id, req.headers.Get(webhooks.HeaderId))
+	}
+
+	timestamp, _ := strconv.ParseInt(req.headers.Get(webhooks.HeaderTimestamp), 10, 64)
+	expectedSignature := webhooks.Sign(webhook.Secret, timestamp, req.body)
+	if req.headers.Get(webhooks.HeaderSignature) != expectedSignature {
+		t.Fatalf("Expected signature %q, got %q", expectedSignature, req.headers.Get(webhooks.HeaderSignature))
+	}
+
+	if string(req.body) != string(deliveries[0].Payload) {
+		t.Fatalf("Expected body %s, got %s", deliveries[0].Payload, req.body)
+	}
+
+	stored, err := app.LogsDao().FindWebhookDeliveryById(deliveries[0].Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stored.Status != models.WebhookDeliveryStatusSuccess ||
+		stored.Attempts != 1 ||
+		stored.ResponseStatus != http.StatusNoContent ||
+		stored.Error != "" ||
+		stored.Url != webhook.Url {
+		t.Fatalf("Unexpected delivery state %v", stored)
+	}
+
+	// already sent deliveries shouldn't be resent
+	if err := webhooks.Send(app, stored); err == nil {
+		t.Fatal("Expected error for non pending delivery")
+	}
+}
+
+func TestSendFailure(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	receiver := newTestReceiver(http.StatusInternalServerError)
+	defer receiver.Close()
+
+	setTestWebhookUrl(t, app, "ftxuvq1ojf4b2pa", receiver.URL)
+
+	delivery := &models.WebhookDelivery{
+		WebhookId: "ftxuvq1ojf4b2pa",
+		Payload:   types.JsonRaw(`{}`),
+		Status:    models.WebhookDeliveryStatusPending,
+	}
+	if err := app.LogsDao().SaveWebhookDelivery(delivery); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 1; i <= webhooks.MaxAttempts; i++ {
+		if err := webhooks.Send(app, delivery); err == nil {
+			t.Fatalf("(%d) Expected send error", i)
+		}
+
+		if delivery.Attempts != i || delivery.ResponseStatus != http.StatusInternalServerError || delivery.Error == "" {
+			t.Fatalf("(%d) Unexpected delivery state %v", i, delivery)
+		}
+
+		if i < webhooks.MaxAttempts {
+			if !delivery.IsPending() {
+				t.Fatalf("(%d) Expected the delivery to remain pending, got %q", i, delivery.Status)
+			}
+
+			minNextAttempt := time.Now().Add(webhooks.RetryDelay(i) - time.Minute)
+			if delivery.NextAttemptAt.Time().Before(minNextAttempt) {
+				t.Fatalf("(%d) Expected the next attempt to be rescheduled with backoff, got %v", i, delivery.NextAttemptAt)
+			}
+		} else if delivery.Status != models.WebhookDeliveryStatusFailed {
+			t.Fatalf("(%d) Expected the delivery to be failed, got %q", i, delivery.Status)
+		}
+	}
+
+	if len(receiver.requests) != webhooks.MaxAttempts {
+		t.Fatalf("Expected %d requests, got %d", webhooks.MaxAttempts, len(receiver.requests))
+	}
+}
+
+func TestSendWithMissingOrDisabledWebhook(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		name      string
+		webhookId string
+	}{
+		{"missing webhook", "missing"},
+		{"disabled webhook", "kbbx2t0dpzpuddx"},
+	}
+
+	for _, s := range scenarios {
+		delivery := &models.WebhookDelivery{
+			WebhookId: s.webhookId,
+			Payload:   types.JsonRaw(`{}`),
+			Status:    models.WebhookDeliveryStatusPending,
+		}
+		if err := app.LogsDao().SaveWebhookDelivery(delivery); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := webhooks.Send(app, delivery); err == nil {
+			t.Errorf("[%s] Expected send error", s.name)
+		}
+
+		stored, err := app.LogsDao().FindWebhookDeliveryById(delivery.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if stored.Status != models.WebhookDeliveryStatusFailed || stored.Attempts != 0 || stored.Error == "" {
+			t.Errorf("[%s] Expected failed delivery without attempts, got %v", s.name, stored)
+		}
+	}
+}
+
+func TestProcessDue(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	tests.MockWebhookDeliveriesData(app)
+
+	receiver := newTestReceiver(http.StatusOK)
+	defer receiver.Close()
+
+	setTestWebhookUrl(t, app, "ftxuvq1ojf4b2pa", receiver.URL)
+
+	// not due yet
+	future := &models.WebhookDelivery{
+		WebhookId: "ftxuvq1ojf4b2pa",
+		Payload:   types.JsonRaw(`{}`),
+		Status:    models.WebhookDeliveryStatusPending,
+	}
+	future.NextAttemptAt, _ = types.ParseDateTime(time.Now().Add(time.Hour))
+	if err := app.LogsDao().SaveWebhookDelivery(future); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := webhooks.ProcessDue(app, 10); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(receiver.requests) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(receiver.requests))
+	}
+
+	due, err := app.LogsDao().FindWebhookDeliveryById("9uc3ov0jalnr4yc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if due.Status != models.WebhookDeliveryStatusSuccess || due.Attempts != 3 {
+		t.Fatalf("Expected the due delivery to be sent, got %v", due)
+	}
+
+	future, err = app.LogsDao().FindWebhookDeliveryById(future.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !future.IsPending() || future.Attempts != 0 {
+		t.Fatalf("Expected the future delivery to remain untouched, got %v", future)
+	}
+}